@@ -131,7 +131,7 @@ func (e *evmInstance) Exec() error {
 		Value:    value,
 		Gas:      &gas,
 	}
-	out, err := e.vm.Execute(e.state, e.blockState, e, params, e.code)
+	out, err := e.execute(params, e.code)
 	if err != nil {
 		return err
 	}
@@ -153,6 +153,11 @@ func (e *evmInstance) Exec() error {
 	return nil
 }
 
+// execute 执行evm代码，trace模式下由Call记录内部调用
+func (e *evmInstance) execute(params engine.CallParams, code []byte) ([]byte, error) {
+	return e.vm.Execute(e.state, e.blockState, e, params, code)
+}
+
 func (e *evmInstance) ResourceUsed() contract.Limits {
 	return contract.Limits{
 		Cpu: int64(e.gasUsed),
@@ -165,7 +170,11 @@ func (e *evmInstance) Release() {
 func (e *evmInstance) Abort(msg string) {
 }
 
+// Call 每次调用结束时由burrow回调，trace模式下记录到当前合约调用中
 func (e *evmInstance) Call(call *exec.CallEvent, exception *errors.Exception) error {
+	if e.ctx.Tracer.Enabled() {
+		e.ctx.Tracer.AddEvmCall(formatCallEvent(call, exception))
+	}
 	return nil
 }
 
@@ -263,7 +272,7 @@ func (e *evmInstance) deployContract() error {
 		Value:    big.NewInt(0),
 		Gas:      &gas,
 	}
-	contractCode, err := e.vm.Execute(e.state, e.blockState, e, params, input)
+	contractCode, err := e.execute(params, input)
 	if err != nil {
 		return err
 	}
@@ -4,10 +4,13 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/hyperledger/burrow/execution/errors"
 	"github.com/hyperledger/burrow/execution/evm/abi"
 	"github.com/hyperledger/burrow/execution/exec"
+	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge"
 )
 
@@ -133,3 +136,34 @@ func TestUnpackEventFromAbi(t *testing.T) {
 	}
 	fmt.Printf("%+v\n", event)
 }
+
+func TestEvmInstanceTraceCall(t *testing.T) {
+	tracer := contract.NewTracer(0)
+	e := &evmInstance{
+		ctx: &bridge.Context{Tracer: tracer},
+	}
+	call := &exec.CallEvent{
+		CallType:   exec.CallTypeCall,
+		CallData:   &exec.CallData{Data: []byte{0x01}, Gas: 100},
+		StackDepth: 1,
+		Return:     []byte{0x02},
+	}
+
+	// 没有调用帧时不记录
+	e.Call(call, nil)
+	tracer.Enter("evm", "c1", "invoke", nil)
+	e.Call(call, errors.Errorf(errors.Codes.ExecutionReverted, "revert"))
+	tracer.Exit(nil, contract.Limits{}, nil)
+
+	lines := tracer.Traces()[0].EvmCalls
+	if len(lines) != 1 || !strings.HasPrefix(lines[0], "depth=1 type=Call") ||
+		!strings.Contains(lines[0], "input=01 return=02 exception=") {
+		t.Errorf("unexpected evm calls %v", lines)
+	}
+
+	// 未开启trace
+	e.ctx.Tracer = nil
+	if err := e.Call(call, nil); err != nil {
+		t.Error(err)
+	}
+}
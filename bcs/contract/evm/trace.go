@@ -0,0 +1,20 @@
+package evm
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/burrow/execution/errors"
+	"github.com/hyperledger/burrow/execution/exec"
+)
+
+// formatCallEvent 将一次evm内部调用格式化为一行轨迹
+func formatCallEvent(call *exec.CallEvent, exception *errors.Exception) string {
+	data := call.GetCallData()
+	line := fmt.Sprintf("depth=%d type=%s caller=%s callee=%s gas=%d input=%x return=%x",
+		call.GetStackDepth(), call.GetCallType(), data.Caller, data.Callee,
+		data.GetGas(), []byte(data.Data), []byte(call.Return))
+	if exception != nil {
+		line += " exception=" + exception.Error()
+	}
+	return line
+}
@@ -2,6 +2,7 @@ package native
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"path/filepath"
@@ -13,11 +14,38 @@ import (
 	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge/pbrpc"
+	"github.com/xuperchain/xupercore/lib/metrics"
 	"github.com/xuperchain/xupercore/protos"
 
 	"google.golang.org/grpc"
 )
 
+const (
+	defaultHealthCheckInterval = time.Second
+	defaultMaxRestartBackoff   = 30 * time.Second
+	defaultCallTimeout         = 30 * time.Second
+)
+
+var (
+	ErrProcessUnavailable = errors.New("native contract process unavailable, waiting restart")
+	ErrProcessClosed      = errors.New("native contract process closed")
+	ErrAcquireTimeout     = errors.New("acquire native contract process timeout")
+)
+
+// 合约进程的状态
+const (
+	// 正常运行
+	processRunning = iota
+	// 空闲超时被停止，下次调用时重新启动
+	processIdle
+	// 异常退出且重启失败，等待退避后重启
+	processFailed
+	// 合约进程已关闭
+	processClosed
+	// 正在启动或重启，启动在lifeMutex外进行
+	processStarting
+)
+
 type contractProcess struct {
 	cfg *contract.NativeConfig
 
@@ -30,12 +58,26 @@ type contractProcess struct {
 	process       Process
 	monitorStopch chan struct{}
 	monitorWaiter sync.WaitGroup
+	stopOnce      sync.Once // 并发调用Stop时只关闭一次
 	logger        log15.Logger
 
 	mutex     sync.Mutex
 	rpcPort   int
 	rpcConn   *grpc.ClientConn
 	rpcClient pbrpc.NativeCodeClient
+
+	// 保护进程的生命周期状态
+	lifeMutex   sync.Mutex
+	state       int
+	running     bool
+	active      int
+	lastActive  time.Time
+	backoff     time.Duration
+	nextRestart time.Time
+	// 启动完成时关闭
+	starting chan struct{}
+	// 限制单个合约的并发调用
+	sem chan struct{}
 }
 
 func newContractProcess(cfg *contract.NativeConfig, name, basedir, chainAddr string, desc *protos.WasmCodeDesc) (*contractProcess, error) {
@@ -50,6 +92,9 @@ func newContractProcess(cfg *contract.NativeConfig, name, basedir, chainAddr str
 		logger:        log15.New(),
 		//logger:        log.DefaultLogger.New("contract", name),
 	}
+	if cfg.MaxConcurrency > 0 {
+		process.sem = make(chan struct{}, cfg.MaxConcurrency)
+	}
 	return process, nil
 }
 
@@ -106,35 +151,128 @@ func (c *contractProcess) waitReply() error {
 func (c *contractProcess) heartBeat() error {
 	ctx, cancel := context.WithTimeout(context.TODO(), 3*time.Second)
 	defer cancel()
-	_, err := c.rpcClient.Ping(ctx, new(pb.PingRequest))
+	_, err := c.RpcClient().Ping(ctx, new(pb.PingRequest))
 	return err
 }
 
 func (c *contractProcess) monitor() {
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
-
-	c.monitorWaiter.Add(1)
 	defer c.monitorWaiter.Done()
-forloop:
+
+	ticker := time.NewTicker(c.healthCheckInterval())
+	defer ticker.Stop()
 	for {
 		select {
 		case <-c.monitorStopch:
 			return
 		case <-ticker.C:
-			err := c.heartBeat()
-			if err == nil {
-				continue forloop
-			}
-			c.logger.Error("process heartbeat error", "error", err)
-			err = c.restartProcess()
-			if err != nil {
-				c.logger.Error("restart process error", "error", err)
-			}
+			c.check()
 		}
 	}
 }
 
+// check 健康检查，停止空闲的进程，重启异常的进程
+// 进程的停止和启动比较耗时，在lifeMutex外进行，不阻塞Acquire和Release
+func (c *contractProcess) check() {
+	c.lifeMutex.Lock()
+	state := c.state
+	if state == processRunning && c.isIdle() {
+		process := c.detachProcess()
+		c.state = processIdle
+		c.lifeMutex.Unlock()
+		c.logger.Info("stop idle process", "contract", c.name)
+		c.stopProcess(process)
+		return
+	}
+	c.lifeMutex.Unlock()
+
+	switch state {
+	case processRunning:
+		err := c.heartBeat()
+		if err == nil {
+			c.updateUsage()
+			return
+		}
+		c.logger.Error("process heartbeat error", "contract", c.name, "error", err)
+	case processFailed:
+	default:
+		return
+	}
+
+	c.lifeMutex.Lock()
+	// 检查期间进程可能已被停止
+	if c.state != state || time.Now().Before(c.nextRestart) {
+		c.lifeMutex.Unlock()
+		return
+	}
+	metrics.ContractNativeRestartCounter.WithLabelValues(c.name).Inc()
+	old := c.detachProcess()
+	c.beginStart()
+	c.lifeMutex.Unlock()
+
+	c.stopProcess(old)
+	process, err := c.startProcess()
+
+	c.lifeMutex.Lock()
+	c.finishStart(process, err)
+	backoff := c.backoff
+	c.lifeMutex.Unlock()
+	if err != nil {
+		c.logger.Error("restart process error", "contract", c.name, "error", err, "backoff", backoff)
+		return
+	}
+	c.logger.Info("restart process success", "contract", c.name)
+}
+
+// isIdle 没有正在进行的调用且空闲超时，调用方需持有lifeMutex
+func (c *contractProcess) isIdle() bool {
+	if c.cfg.IdleTimeout <= 0 || c.active > 0 {
+		return false
+	}
+	return time.Since(c.lastActive) >= time.Duration(c.cfg.IdleTimeout)*time.Second
+}
+
+func (c *contractProcess) updateUsage() {
+	c.lifeMutex.Lock()
+	process := c.process
+	c.lifeMutex.Unlock()
+	usage, err := process.Usage()
+	if err != nil {
+		c.logger.Debug("get process usage error", "contract", c.name, "error", err)
+		return
+	}
+	metrics.ContractNativeCPUGauge.WithLabelValues(c.name).Set(usage.CPUSeconds)
+	metrics.ContractNativeMemoryGauge.WithLabelValues(c.name).Set(float64(usage.MemoryBytes))
+}
+
+func (c *contractProcess) healthCheckInterval() time.Duration {
+	if c.cfg.HealthCheckInterval > 0 {
+		return time.Duration(c.cfg.HealthCheckInterval) * time.Second
+	}
+	return defaultHealthCheckInterval
+}
+
+func (c *contractProcess) maxRestartBackoff() time.Duration {
+	if c.cfg.MaxRestartBackoff > 0 {
+		return time.Duration(c.cfg.MaxRestartBackoff) * time.Second
+	}
+	return defaultMaxRestartBackoff
+}
+
+// CallTimeout 一次合约调用的超时时间，包括等待并发限制和启动空闲进程的时间
+func (c *contractProcess) CallTimeout() time.Duration {
+	if c.cfg.CallTimeout > 0 {
+		return time.Duration(c.cfg.CallTimeout) * time.Second
+	}
+	return defaultCallTimeout
+}
+
+func (c *contractProcess) stopTimeout() time.Duration {
+	if c.cfg.StopTimeout > 0 {
+		return time.Duration(c.cfg.StopTimeout) * time.Second
+	}
+	return time.Second
+}
+
 func (c *contractProcess) resetRpcClient() error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -161,51 +299,193 @@ func (c *contractProcess) RpcClient() pbrpc.NativeCodeClient {
 	return c.rpcClient
 }
 
-func (c *contractProcess) restartProcess() error {
-	c.process.Stop(time.Second)
-	return c.start(false)
+// Acquire 获取调用合约使用的rpc客户端，超过最大并发或进程正在启动时等待，ctx到期时返回ErrAcquireTimeout，
+// 因空闲被停止的进程会重新启动，调用结束后需要调用Release
+func (c *contractProcess) Acquire(ctx context.Context) (pbrpc.NativeCodeClient, error) {
+	if c.sem != nil {
+		select {
+		case c.sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %v", ErrAcquireTimeout, ctx.Err())
+		}
+	}
+	client, err := c.acquire(ctx)
+	if err != nil {
+		if c.sem != nil {
+			<-c.sem
+		}
+		return nil, err
+	}
+	return client, nil
+}
+
+func (c *contractProcess) acquire(ctx context.Context) (pbrpc.NativeCodeClient, error) {
+	var startErr error
+	c.lifeMutex.Lock()
+	defer c.lifeMutex.Unlock()
+	for {
+		switch c.state {
+		case processRunning:
+			c.active++
+			c.lastActive = time.Now()
+			metrics.ContractNativeConcurrentCallGauge.WithLabelValues(c.name).Set(float64(c.active))
+			return c.RpcClient(), nil
+		case processStarting:
+			starting := c.starting
+			c.lifeMutex.Unlock()
+			select {
+			case <-starting:
+			case <-ctx.Done():
+				c.lifeMutex.Lock()
+				return nil, fmt.Errorf("%w: %v", ErrAcquireTimeout, ctx.Err())
+			}
+			c.lifeMutex.Lock()
+		case processIdle:
+			c.beginStart()
+			c.lifeMutex.Unlock()
+			process, err := c.startProcess()
+			c.lifeMutex.Lock()
+			c.finishStart(process, err)
+			startErr = err
+		case processFailed:
+			if startErr != nil {
+				return nil, startErr
+			}
+			return nil, ErrProcessUnavailable
+		default:
+			return nil, ErrProcessClosed
+		}
+	}
+}
+
+// Release 结束一次合约调用
+func (c *contractProcess) Release() {
+	c.lifeMutex.Lock()
+	c.active--
+	c.lastActive = time.Now()
+	metrics.ContractNativeConcurrentCallGauge.WithLabelValues(c.name).Set(float64(c.active))
+	c.lifeMutex.Unlock()
+	if c.sem != nil {
+		<-c.sem
+	}
 }
 
-func (c *contractProcess) start(startMonitor bool) error {
+// beginStart 进入启动状态，其他调用等待启动完成，调用方需持有lifeMutex
+func (c *contractProcess) beginStart() {
+	c.state = processStarting
+	c.starting = make(chan struct{})
+}
+
+// finishStart 记录启动结果，失败时按退避时间等待重启，调用方需持有lifeMutex
+func (c *contractProcess) finishStart(process Process, err error) {
+	defer close(c.starting)
+	if err != nil {
+		c.state = processFailed
+		c.backoff *= 2
+		if c.backoff == 0 {
+			c.backoff = time.Second
+		}
+		if c.backoff > c.maxRestartBackoff() {
+			c.backoff = c.maxRestartBackoff()
+		}
+		c.nextRestart = time.Now().Add(c.backoff)
+		return
+	}
+	c.process = process
+	c.running = true
+	c.state = processRunning
+	c.backoff = 0
+	c.lastActive = time.Now()
+	metrics.ContractNativeRunningGauge.WithLabelValues(c.name).Set(1)
+}
+
+// startProcess 启动进程并等待就绪，不修改lifeMutex保护的状态
+func (c *contractProcess) startProcess() (Process, error) {
 	err := c.resetRpcClient()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	c.process, err = c.makeHostProcess()
+	process, err := c.makeHostProcess()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = c.process.Start()
+	err = process.Start()
 	if err != nil {
-		return err
+		return nil, err
 	}
 	err = c.waitReply()
 	if err != nil {
 		// 避免启动失败后产生僵尸进程
-		c.process.Stop(time.Second)
-		return err
+		process.Stop(time.Second)
+		return nil, err
 	}
-	if startMonitor {
-		go c.monitor()
+	return process, nil
+}
+
+// detachProcess 标记进程已停止，返回需要停止的进程，调用方需持有lifeMutex
+func (c *contractProcess) detachProcess() Process {
+	if !c.running {
+		return nil
 	}
+	c.running = false
+	metrics.ContractNativeRunningGauge.WithLabelValues(c.name).Set(0)
+	return c.process
+}
 
-	return nil
+// stopProcess 停止detachProcess返回的进程
+func (c *contractProcess) stopProcess(process Process) {
+	if process == nil {
+		return
+	}
+	err := process.Stop(c.stopTimeout())
+	if err != nil {
+		c.logger.Error("process stoped error", "contract", c.name, "error", err)
+	}
 }
 
 func (c *contractProcess) Start() error {
-	return c.start(true)
+	process, err := c.startProcess()
+	if err != nil {
+		return err
+	}
+	c.lifeMutex.Lock()
+	c.beginStart()
+	c.finishStart(process, nil)
+	c.lifeMutex.Unlock()
+
+	c.monitorWaiter.Add(1)
+	go c.monitor()
+	return nil
 }
 
 func (c *contractProcess) Stop() {
+	c.stopOnce.Do(c.stop)
+}
+
+func (c *contractProcess) stop() {
+	c.lifeMutex.Lock()
+	closed := c.state == processClosed
+	c.lifeMutex.Unlock()
+	if closed {
+		return
+	}
 	// close monitor and waiting monitor stoped
 	close(c.monitorStopch)
 	c.monitorWaiter.Wait()
 
-	err := c.process.Stop(time.Second)
-	if err != nil {
-		c.logger.Error("process stoped error", "error", err)
+	c.lifeMutex.Lock()
+	// 等待正在进行的启动完成
+	for c.state == processStarting {
+		starting := c.starting
+		c.lifeMutex.Unlock()
+		<-starting
+		c.lifeMutex.Lock()
 	}
+	process := c.detachProcess()
+	c.state = processClosed
+	c.lifeMutex.Unlock()
+	c.stopProcess(process)
 }
 
 func (c *contractProcess) GetDesc() *protos.WasmCodeDesc {
@@ -0,0 +1,251 @@
+package native
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func newTestProcess(t *testing.T, cfg *contract.NativeConfig) (*contractProcess, func()) {
+	basedir, err := ioutil.TempDir("", "native-process")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin, err := build(filepath.Join(basedir, "counter.bin"))
+	if err != nil {
+		os.RemoveAll(basedir)
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256(bin)
+	desc := &protos.WasmCodeDesc{
+		Runtime: "go",
+		Digest:  digest[:],
+	}
+	err = ioutil.WriteFile(filepath.Join(basedir, nativeCodeFileName(desc)), bin, 0755)
+	if err != nil {
+		os.RemoveAll(basedir)
+		t.Fatal(err)
+	}
+	// 只做健康检查，不需要真实的syscall服务
+	process, err := newContractProcess(cfg, "counter", basedir, "tcp://127.0.0.1:1", desc)
+	if err != nil {
+		os.RemoveAll(basedir)
+		t.Fatal(err)
+	}
+	if err := process.Start(); err != nil {
+		os.RemoveAll(basedir)
+		t.Fatal(err)
+	}
+	return process, func() {
+		process.Stop()
+		os.RemoveAll(basedir)
+	}
+}
+
+func ping(c *contractProcess) error {
+	// 包括空闲进程重新启动的时间
+	ctx, cancel := context.WithTimeout(context.TODO(), 5*time.Second)
+	defer cancel()
+	client, err := c.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Release()
+	_, err = client.Ping(ctx, new(pb.PingRequest))
+	return err
+}
+
+func processState(c *contractProcess) int {
+	c.lifeMutex.Lock()
+	defer c.lifeMutex.Unlock()
+	return c.state
+}
+
+func waitFor(timeout time.Duration, cond func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return false
+}
+
+func TestProcessRestart(t *testing.T) {
+	process, closer := newTestProcess(t, &contract.NativeConfig{})
+	defer closer()
+
+	if err := ping(process); err != nil {
+		t.Fatal(err)
+	}
+	process.lifeMutex.Lock()
+	oldPid := process.process.(*HostProcess).cmd.Process.Pid
+	process.lifeMutex.Unlock()
+	// 杀死整个进程组模拟进程崩溃
+	if err := syscall.Kill(-oldPid, syscall.SIGKILL); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted := waitFor(10*time.Second, func() bool {
+		process.lifeMutex.Lock()
+		defer process.lifeMutex.Unlock()
+		return process.state == processRunning && process.process.(*HostProcess).cmd.Process.Pid != oldPid
+	})
+	if !restarted {
+		t.Fatal("expect process restarted")
+	}
+	if err := ping(process); err != nil {
+		t.Fatal(err)
+	}
+	usage, err := process.process.Usage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.MemoryBytes <= 0 {
+		t.Fatalf("unexpected usage %v", usage)
+	}
+}
+
+func TestProcessIdleTimeout(t *testing.T) {
+	process, closer := newTestProcess(t, &contract.NativeConfig{
+		IdleTimeout: 1,
+	})
+	defer closer()
+
+	// 调用期间不会被停止
+	if _, err := process.Acquire(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2500 * time.Millisecond)
+	if processState(process) != processRunning {
+		t.Fatal("active process should not be stopped")
+	}
+	process.Release()
+
+	if !waitFor(5*time.Second, func() bool { return processState(process) == processIdle }) {
+		t.Fatal("expect idle process stopped")
+	}
+	// 下次调用时重新启动
+	if err := ping(process); err != nil {
+		t.Fatal(err)
+	}
+	if processState(process) != processRunning {
+		t.Fatal("expect process started lazily")
+	}
+
+	// 并发关闭只执行一次
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			process.Stop()
+		}()
+	}
+	wg.Wait()
+	if _, err := process.Acquire(context.TODO()); err != ErrProcessClosed {
+		t.Fatalf("expect closed error, got %v", err)
+	}
+}
+
+func TestProcessMaxConcurrency(t *testing.T) {
+	process, closer := newTestProcess(t, &contract.NativeConfig{
+		MaxConcurrency: 1,
+	})
+	defer closer()
+
+	if _, err := process.Acquire(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	acquired := make(chan error, 1)
+	go func() {
+		_, err := process.Acquire(context.TODO())
+		acquired <- err
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("expect blocked by max concurrency")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	process.Release()
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expect acquired after release")
+	}
+	process.Release()
+}
+
+func TestProcessAcquireTimeout(t *testing.T) {
+	process, closer := newTestProcess(t, &contract.NativeConfig{
+		MaxConcurrency: 1,
+	})
+	defer closer()
+
+	if _, err := process.Acquire(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := process.Acquire(ctx); !errors.Is(err, ErrAcquireTimeout) {
+		t.Fatalf("expect acquire timeout, got %v", err)
+	}
+	process.Release()
+
+	// 进程启动期间Acquire等待启动完成，Release不受影响
+	if _, err := process.Acquire(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	process.lifeMutex.Lock()
+	process.beginStart()
+	process.lifeMutex.Unlock()
+	released := make(chan struct{})
+	go func() {
+		process.Release()
+		close(released)
+	}()
+	select {
+	case <-released:
+	case <-time.After(time.Second):
+		t.Fatal("release blocked by process start")
+	}
+	ctx, cancel = context.WithTimeout(context.TODO(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := process.Acquire(ctx); !errors.Is(err, ErrAcquireTimeout) {
+		t.Fatalf("expect acquire timeout while starting, got %v", err)
+	}
+
+	acquired := make(chan error, 1)
+	go func() {
+		_, err := process.Acquire(context.TODO())
+		acquired <- err
+	}()
+	process.lifeMutex.Lock()
+	process.finishStart(process.process, nil)
+	process.lifeMutex.Unlock()
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expect acquired after process started")
+	}
+	process.Release()
+}
@@ -83,7 +83,14 @@ func (i *nativeVmInstance) Exec() error {
 	request := &pb.NativeCallRequest{
 		Ctxid: i.ctx.ID,
 	}
-	_, err := i.process.RpcClient().Call(context.TODO(), request)
+	ctx, cancel := context.WithTimeout(context.TODO(), i.process.CallTimeout())
+	defer cancel()
+	client, err := i.process.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer i.process.Release()
+	_, err = client.Call(ctx, request)
 	return err
 }
 
@@ -1,17 +1,23 @@
 package native
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os/exec"
 	"path/filepath"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	log15 "github.com/xuperchain/log15"
 	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/abi"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge"
 	_ "github.com/xuperchain/xupercore/kernel/contract/kernel"
 	_ "github.com/xuperchain/xupercore/kernel/contract/manager"
 	"github.com/xuperchain/xupercore/kernel/contract/mock"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+	"github.com/xuperchain/xupercore/protos"
 )
 
 type MockLogger struct {
@@ -45,7 +51,10 @@ var contractConfig = &contract.ContractConfig{
 }
 
 func compile(th *mock.TestHelper) ([]byte, error) {
-	target := filepath.Join(th.Basedir(), "counter.bin")
+	return build(filepath.Join(th.Basedir(), "counter.bin"))
+}
+
+func build(target string) ([]byte, error) {
 	cmd := exec.Command("go", "build", "-o", target)
 	cmd.Dir = "testdata"
 	out, err := cmd.CombinedOutput()
@@ -104,9 +113,59 @@ func TestNativeInvoke(t *testing.T) {
 	t.Logf("body:%s", resp.Body)
 }
 
+func TestNativeAbi(t *testing.T) {
+	th := mock.NewTestHelper(contractConfig)
+	defer th.Close()
+
+	bin, err := compile(th)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counterAbi := []byte(`{"methods": [{"name": "increase", "inputs": [{"name": "key", "type": "string"}], "returns": "uint64"}]}`)
+	// 开启合约版本历史前忽略ABI
+	_, err = th.Deploy("native", "go", "counter0", bin, map[string][]byte{
+		"creator":      []byte("icexin"),
+		"contract_abi": counterAbi,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := th.State().Get("contract", bridge.ContractAbiKey("counter0"))
+	if err == nil && len(data.GetPureData().GetValue()) != 0 {
+		t.Fatalf("expect abi ignored, got %s", data.GetPureData().GetValue())
+	}
+
+	th.SetContractVersion(true)
+	_, err = th.Deploy("native", "go", "counter", bin, map[string][]byte{
+		"creator":      []byte("icexin"),
+		"contract_abi": counterAbi,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err = th.State().Get("contract", bridge.ContractAbiKey("counter"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data.GetPureData().GetValue()) != string(counterAbi) {
+		t.Fatalf("unexpected abi %s", data.GetPureData().GetValue())
+	}
+
+	// 部署时校验ABI
+	_, err = th.Deploy("native", "go", "counter2", bin, map[string][]byte{
+		"creator":      []byte("icexin"),
+		"contract_abi": []byte(`{"methods": [{"name": "increase", "inputs": [{"name": "key", "type": "float"}]}]}`),
+	})
+	if !errors.Is(err, abi.ErrInvalidABI) {
+		t.Fatalf("expect invalid abi, got %v", err)
+	}
+}
+
 func TestNativeUpgrade(t *testing.T) {
 	th := mock.NewTestHelper(contractConfig)
 	defer th.Close()
+	th.SetContractVersion(true)
 
 	bin, err := compile(th)
 	if err != nil {
@@ -124,6 +183,123 @@ func TestNativeUpgrade(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+
+	err = th.UpgradeWithArgs("counter", bin, map[string][]byte{
+		"version": []byte("3"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := th.Invoke("native", "counter", "get", map[string][]byte{
+		"key": []byte("version"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resp.Body) != "3" {
+		t.Fatalf("expect migrate executed, got %s", resp.Body)
+	}
+
+	// 迁移失败时升级失败
+	err = th.UpgradeWithArgs("counter", bin, map[string][]byte{})
+	if err == nil {
+		t.Fatal("expect upgrade fail when migrate fail")
+	}
+
+	for version := int64(1); version <= 3; version++ {
+		data, err := th.State().Get("contract", bridge.ContractHistoryKey("counter", version))
+		if err != nil {
+			t.Fatal(err)
+		}
+		record := &protos.ContractVersion{}
+		if err := proto.Unmarshal(data.GetPureData().GetValue(), record); err != nil {
+			t.Fatal(err)
+		}
+		if record.Version != version || record.Migrated != (version == 3) {
+			t.Fatalf("unexpected version record: %v", record)
+		}
+	}
+	if _, err := th.State().Get("contract", bridge.ContractHistoryKey("counter", 4)); err == nil {
+		t.Fatal("failed upgrade should not record version")
+	}
+
+	// 只保留最近5个旧版本的代码
+	for i := 0; i < 4; i++ {
+		if err := th.Upgrade("counter", bin); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := th.State().Get("contract", []byte("counter.code.00000000000000000002")); err != nil {
+		t.Fatal("recent code should be kept", err)
+	}
+	data, err := th.State().Get("contract", []byte("counter.code.00000000000000000001"))
+	if err != nil || !sandbox.IsDelFlag(data.GetPureData().GetValue()) {
+		t.Fatal("expired code should be deleted", err)
+	}
+}
+
+func TestNativeUpgradeBeforeFork(t *testing.T) {
+	th := mock.NewTestHelper(contractConfig)
+	defer th.Close()
+
+	bin, err := compile(th)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = th.Deploy("native", "go", "counter", bin, map[string][]byte{
+		"creator": []byte("icexin"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 未开启版本历史时忽略migrate_args，不写入版本记录
+	err = th.UpgradeWithArgs("counter", bin, map[string][]byte{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range [][]byte{[]byte("counter.version"), bridge.ContractHistoryKey("counter", 1),
+		[]byte("counter.code.00000000000000000001")} {
+		if _, err := th.State().Get("contract", key); err == nil {
+			t.Fatalf("unexpected key %s before fork", key)
+		}
+	}
+}
+
+func TestNativeContractCallRevert(t *testing.T) {
+	for _, revert := range []bool{false, true} {
+		th := mock.NewTestHelper(contractConfig)
+		th.SetContractCallRevert(revert)
+
+		bin, err := compile(th)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range []string{"caller", "callee"} {
+			_, err = th.Deploy("native", "go", name, bin, map[string][]byte{
+				"creator": []byte("icexin"),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		_, err = th.Invoke("native", "caller", "call", map[string][]byte{
+			"contract": []byte("callee"),
+			"method":   []byte("fail"),
+			"key":      []byte("k1"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 分叉前的交易重放时保留被调合约失败前的修改，分叉后回滚
+		_, err = th.State().Get("callee", []byte("k1"))
+		if revert && err == nil {
+			t.Fatal("failed callee write should be reverted")
+		}
+		if !revert && err != nil {
+			t.Fatal("failed callee write should be kept before fork", err)
+		}
+		th.Close()
+	}
 }
 
 func TestNativeDocker(t *testing.T) {
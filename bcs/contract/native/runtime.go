@@ -1,9 +1,13 @@
 package native
 
 import (
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -22,8 +26,22 @@ var (
 
 const (
 	pingTimeoutSecond = 2
+	// /proc/<pid>/stat中cpu时间的单位
+	clockTicks = 100
 )
 
+var (
+	ErrProcessNotStarted = errors.New("process not started")
+)
+
+// ProcessUsage 进程的资源使用情况
+type ProcessUsage struct {
+	// 累计使用的cpu时间，单位秒
+	CPUSeconds float64
+	// 占用的内存，单位字节
+	MemoryBytes int64
+}
+
 // Process is the container of running contract
 type Process interface {
 	// Start 启动Native code进程
@@ -31,6 +49,9 @@ type Process interface {
 
 	// Stop 停止进程，如果在超时时间内进程没有退出则强制杀死进程
 	Stop(timeout time.Duration) error
+
+	// Usage 获取进程的资源使用情况
+	Usage() (*ProcessUsage, error)
 }
 
 // DockerProcess is the process running as a docker container
@@ -189,6 +210,78 @@ func (h *HostProcess) Start() error {
 	return nil
 }
 
+// Usage implements process interface
+func (d *DockerProcess) Usage() (*ProcessUsage, error) {
+	if d.id == "" {
+		return nil, ErrProcessNotStarted
+	}
+	client, err := getDockerClient()
+	if err != nil {
+		return nil, err
+	}
+	statsch := make(chan *docker.Stats, 1)
+	err = client.Stats(docker.StatsOptions{
+		ID:      d.id,
+		Stats:   statsch,
+		Stream:  false,
+		Timeout: 3 * time.Second,
+	})
+	if err != nil {
+		return nil, err
+	}
+	stats, ok := <-statsch
+	if !ok || stats == nil {
+		return nil, fmt.Errorf("no stats of container %s", d.id)
+	}
+	return &ProcessUsage{
+		CPUSeconds:  float64(stats.CPUStats.CPUUsage.TotalUsage) / float64(time.Second),
+		MemoryBytes: int64(stats.MemoryStats.Usage),
+	}, nil
+}
+
+// Usage implements process interface
+func (h *HostProcess) Usage() (*ProcessUsage, error) {
+	if h.cmd == nil || h.cmd.Process == nil {
+		return nil, ErrProcessNotStarted
+	}
+	return readProcStat(h.cmd.Process.Pid)
+}
+
+// readProcStat 从/proc/<pid>/stat读取进程的cpu时间和常驻内存
+func readProcStat(pid int) (*ProcessUsage, error) {
+	buf, err := ioutil.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return nil, err
+	}
+	// 进程名可能包含空格，从最后一个')'之后开始解析，第一个字段为state(第3列)
+	stat := string(buf)
+	idx := strings.LastIndexByte(stat, ')')
+	if idx < 0 {
+		return nil, fmt.Errorf("bad stat format of pid %d", pid)
+	}
+	fields := strings.Fields(stat[idx+1:])
+	// utime(14) stime(15) rss(24)
+	if len(fields) < 22 {
+		return nil, fmt.Errorf("bad stat format of pid %d", pid)
+	}
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	rss, err := strconv.ParseInt(fields[21], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &ProcessUsage{
+		CPUSeconds:  float64(utime+stime) / clockTicks,
+		MemoryBytes: rss * int64(os.Getpagesize()),
+	}, nil
+}
+
 func processExists(pid int) bool {
 	return syscall.Kill(pid, syscall.Signal(0)) == nil
 }
@@ -58,6 +58,42 @@ func (c *counter) Get(ctx code.Context) code.Response {
 	return code.OK(value)
 }
 
+func (c *counter) Migrate(ctx code.Context) code.Response {
+	version, ok := ctx.Args()["version"]
+	if !ok {
+		return code.Errors("missing version")
+	}
+	err := ctx.PutObject([]byte("version"), version)
+	if err != nil {
+		return code.Error(err)
+	}
+	return code.OK([]byte("ok"))
+}
+
+func (c *counter) Fail(ctx code.Context) code.Response {
+	key, ok := ctx.Args()["key"]
+	if !ok {
+		return code.Errors("missing key")
+	}
+	err := ctx.PutObject(key, []byte("failed"))
+	if err != nil {
+		return code.Error(err)
+	}
+	return code.Errors("fail after put")
+}
+
+// Call 调用另一个合约的方法，忽略被调合约的失败
+func (c *counter) Call(ctx code.Context) code.Response {
+	args := ctx.Args()
+	resp, err := ctx.Call("native", string(args["contract"]), string(args["method"]), map[string][]byte{
+		"key": args["key"],
+	})
+	if err != nil {
+		return code.Error(err)
+	}
+	return code.OK([]byte(resp.Message))
+}
+
 func main() {
 	driver.Serve(new(counter))
 }
@@ -1,18 +1,63 @@
 package xvm
 
 import (
+	"crypto/ecdsa"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"github.com/xuperchain/crypto/core/account"
-	"github.com/xuperchain/crypto/core/sign"
 	"hash"
 	"unsafe"
 
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/xuperchain/crypto/core/account"
+	"github.com/xuperchain/crypto/core/common"
+	"github.com/xuperchain/crypto/core/sign"
+	"github.com/xuperchain/crypto/gm/gmsm/sm3"
 	"github.com/xuperchain/xvm/exec"
 	"github.com/xuperchain/xvm/runtime/emscripten"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/xuperchain/xupercore/lib/crypto/client"
+)
+
+const (
+	builtinGasKey = "builtinGas"
+
+	// 内置函数的资源消耗，按照32字节为一个字计算
+	hashBaseGas  = 30
+	hashWordGas  = 6
+	codecBaseGas = 10
+	codecWordGas = 3
+	// 每个公钥一次验签的消耗
+	verifyGas = 3000
 )
 
+// builtinGas 记录内置函数消耗的gas，计入合约的cpu消耗
+type builtinGas struct {
+	used  int64
+	limit int64
+}
+
+// chargeGas 内置函数执行前扣除gas，超过限制时终止合约执行
+// sha256、hex、ecverify等早期的内置函数不计费，保持已部署合约的资源消耗不变
+func chargeGas(ctx exec.Context, gas int64) {
+	meter, ok := ctx.GetUserData(builtinGasKey).(*builtinGas)
+	if !ok {
+		return
+	}
+	meter.used += gas
+	if ctx.GasUsed()+meter.used > meter.limit {
+		exec.ThrowMessage("run out of gas")
+	}
+}
+
+func wordGas(base, perWord int64, size uint32) int64 {
+	return base + perWord*int64((size+31)/32)
+}
+
 func touint32(n int32) uint32 {
 	return *(*uint32)(unsafe.Pointer(&n))
 }
@@ -21,6 +66,10 @@ func hashFunc(name string) hash.Hash {
 	switch name {
 	case "sha256":
 		return sha256.New()
+	case "sm3":
+		return sm3.New()
+	case "keccak256":
+		return sha3.NewLegacyKeccak256()
 	default:
 		return nil
 	}
@@ -40,6 +89,9 @@ func xvmHash(ctx exec.Context,
 	if hasher == nil {
 		exec.ThrowMessage(fmt.Sprintf("hash %s not found", name))
 	}
+	if name != "sha256" {
+		chargeGas(ctx, wordGas(hashBaseGas, hashWordGas, inputlen))
+	}
 	hasher.Write(input)
 	out := hasher.Sum(nil)
 	copy(output, out[:])
@@ -55,11 +107,22 @@ func getCodec(name string) codec {
 	switch name {
 	case "hex":
 		return hexCodec{}
+	case "base58":
+		return base58Codec{}
+	case "base64":
+		return base64Codec{}
 	default:
 		return nil
 	}
 }
 
+// chargeCodecGas hex之外的编解码按照输入长度计费
+func chargeCodecGas(ctx exec.Context, name string, size uint32) {
+	if name != "hex" {
+		chargeGas(ctx, wordGas(codecBaseGas, codecWordGas, size))
+	}
+}
+
 type hexCodec struct{}
 
 func (h hexCodec) Encode(in []byte) []byte {
@@ -73,6 +136,35 @@ func (h hexCodec) Decode(in []byte) ([]byte, error) {
 	return out, err
 }
 
+type base58Codec struct{}
+
+func (b base58Codec) Encode(in []byte) []byte {
+	return []byte(base58.Encode(in))
+}
+
+func (b base58Codec) Decode(in []byte) ([]byte, error) {
+	out := base58.Decode(string(in))
+	// 非法字符时解码结果为空
+	if len(out) == 0 && len(in) != 0 {
+		return nil, errors.New("invalid base58 string")
+	}
+	return out, nil
+}
+
+type base64Codec struct{}
+
+func (b base64Codec) Encode(in []byte) []byte {
+	out := make([]byte, base64.StdEncoding.EncodedLen(len(in)))
+	base64.StdEncoding.Encode(out, in)
+	return out
+}
+
+func (b base64Codec) Decode(in []byte) ([]byte, error) {
+	out := make([]byte, base64.StdEncoding.DecodedLen(len(in)))
+	n, err := base64.StdEncoding.Decode(out, in)
+	return out[:n], err
+}
+
 func xvmEncode(ctx exec.Context,
 	nameptr uint32,
 	inputptr uint32, inputlen uint32,
@@ -86,6 +178,7 @@ func xvmEncode(ctx exec.Context,
 	if c == nil {
 		exec.ThrowMessage(fmt.Sprintf("codec %s not found", name))
 	}
+	chargeCodecGas(ctx, name, inputlen)
 	out := c.Encode(input)
 
 	codec.SetUint32(outputpptr, bytesdup(ctx, out))
@@ -106,6 +199,7 @@ func xvmDecode(ctx exec.Context,
 	if c == nil {
 		exec.ThrowMessage(fmt.Sprintf("codec %s not found", name))
 	}
+	chargeCodecGas(ctx, name, inputlen)
 	out, err := c.Decode(input)
 	if err != nil {
 		return 1
@@ -136,6 +230,141 @@ func xvmECVerify(ctx exec.Context,
 	return touint32(-1)
 }
 
+// xvmSM2Verify 使用国密SM2公钥验证签名，与lib/crypto/client/gm一致
+func xvmSM2Verify(ctx exec.Context,
+	pubptr, publen,
+	sigptr, siglen, msgptr, msglen uint32) uint32 {
+	codec := exec.NewCodec(ctx)
+	chargeGas(ctx, verifyGas)
+
+	pubkeyJSON := codec.Bytes(pubptr, publen)
+	sig := codec.Bytes(sigptr, siglen)
+	msg := codec.Bytes(msgptr, msglen)
+	if !verifySM2(pubkeyJSON, sig, msg) {
+		return touint32(-1)
+	}
+	return 0
+}
+
+// xvmSchnorrVerify 验证XuperSignature格式的schnorr签名，按照公钥的曲线选择密码学插件
+func xvmSchnorrVerify(ctx exec.Context,
+	pubptr, publen,
+	sigptr, siglen, msgptr, msglen uint32) uint32 {
+	codec := exec.NewCodec(ctx)
+	chargeGas(ctx, verifyGas)
+
+	pubkeyJSON := codec.Bytes(pubptr, publen)
+	sig := codec.Bytes(sigptr, siglen)
+	msg := codec.Bytes(msgptr, msglen)
+	if !verifySchnorr(pubkeyJSON, sig, msg) {
+		return touint32(-1)
+	}
+	return 0
+}
+
+// xvmMultiSigVerify 验证XuperSignature格式的多重签名，公钥为json数组，与base.MultiSig.VerifyMultiSig一致
+func xvmMultiSigVerify(ctx exec.Context,
+	pubsptr, pubslen,
+	sigptr, siglen, msgptr, msglen uint32) uint32 {
+	codec := exec.NewCodec(ctx)
+
+	pubkeysJSON := codec.Bytes(pubsptr, pubslen)
+	var pubkeys []json.RawMessage
+	if err := json.Unmarshal(pubkeysJSON, &pubkeys); err != nil || len(pubkeys) == 0 {
+		return touint32(-1)
+	}
+	chargeGas(ctx, verifyGas*int64(len(pubkeys)))
+
+	sig := codec.Bytes(sigptr, siglen)
+	msg := codec.Bytes(msgptr, msglen)
+	if !verifyMultiSig(pubkeys, sig, msg) {
+		return touint32(-1)
+	}
+	return 0
+}
+
+// safeVerify 密码学库对非法输入可能panic，合约输入不可信，panic时视为验签失败
+func safeVerify(verify func() (bool, error)) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			ok = false
+		}
+	}()
+	ok, _ = verify()
+	return ok
+}
+
+// unwrapSignature 从统一签名格式XuperSignature中取出指定类型的签名内容
+func unwrapSignature(sig []byte, sigType string) ([]byte, bool) {
+	xuperSig := new(common.XuperSignature)
+	if err := json.Unmarshal(sig, xuperSig); err != nil || xuperSig.SigType != sigType {
+		return nil, false
+	}
+	return xuperSig.SigContent, true
+}
+
+func verifySM2(pubkeyJSON, sig, msg []byte) bool {
+	cryptoClient, err := client.CreateCryptoClient(client.CryptoTypeGM)
+	if err != nil {
+		return false
+	}
+	pubkey, err := cryptoClient.GetEcdsaPublicKeyFromJsonStr(string(pubkeyJSON))
+	if err != nil {
+		return false
+	}
+	return safeVerify(func() (bool, error) {
+		return cryptoClient.VerifyECDSA(pubkey, sig, msg)
+	})
+}
+
+type schnorrVerifier interface {
+	VerifySchnorr(publicKey *ecdsa.PublicKey, sig, message []byte) (bool, error)
+}
+
+func verifySchnorr(pubkeyJSON, sig, msg []byte) bool {
+	content, ok := unwrapSignature(sig, common.Schnorr)
+	if !ok {
+		return false
+	}
+	cryptoClient, err := client.CreateCryptoClientFromJSONPublicKey(pubkeyJSON)
+	if err != nil {
+		return false
+	}
+	verifier, ok := cryptoClient.(schnorrVerifier)
+	if !ok {
+		return false
+	}
+	pubkey, err := cryptoClient.GetEcdsaPublicKeyFromJsonStr(string(pubkeyJSON))
+	if err != nil {
+		return false
+	}
+	return safeVerify(func() (bool, error) {
+		return verifier.VerifySchnorr(pubkey, content, msg)
+	})
+}
+
+func verifyMultiSig(pubkeysJSON []json.RawMessage, sig, msg []byte) bool {
+	content, ok := unwrapSignature(sig, common.MultiSig)
+	if !ok {
+		return false
+	}
+	cryptoClient, err := client.CreateCryptoClientFromJSONPublicKey(pubkeysJSON[0])
+	if err != nil {
+		return false
+	}
+	pubkeys := make([]*ecdsa.PublicKey, 0, len(pubkeysJSON))
+	for _, pubkeyJSON := range pubkeysJSON {
+		pubkey, err := cryptoClient.GetEcdsaPublicKeyFromJsonStr(string(pubkeyJSON))
+		if err != nil {
+			return false
+		}
+		pubkeys = append(pubkeys, pubkey)
+	}
+	return safeVerify(func() (bool, error) {
+		return cryptoClient.VerifyMultiSig(pubkeys, content, msg)
+	})
+}
+
 // func xvmMakeTx(ctx exec.Context, txptr, txlen, outpptr, outlenPtr uint32) uint32 {
 // 	codec := exec.NewCodec(ctx)
 // 	txbuf := codec.Bytes(txptr, txlen)
@@ -199,10 +428,13 @@ func strdup(ctx exec.Context, s string) uint32 {
 }
 
 var builtinResolver = exec.MapResolver(map[string]interface{}{
-	"env._xvm_hash":     xvmHash,
-	"env._xvm_encode":   xvmEncode,
-	"env._xvm_decode":   xvmDecode,
-	"env._xvm_ecverify": xvmECVerify,
+	"env._xvm_hash":            xvmHash,
+	"env._xvm_encode":          xvmEncode,
+	"env._xvm_decode":          xvmDecode,
+	"env._xvm_ecverify":        xvmECVerify,
+	"env._xvm_sm2verify":       xvmSM2Verify,
+	"env._xvm_schnorr_verify":  xvmSchnorrVerify,
+	"env._xvm_multisig_verify": xvmMultiSigVerify,
 	// "env._xvm_make_tx":          xvmMakeTx,
 	"env._xvm_addr_from_pubkey": xvmAddressFromPubkey,
 })
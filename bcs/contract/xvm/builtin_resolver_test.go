@@ -0,0 +1,148 @@
+package xvm
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/xuperchain/xvm/exec"
+
+	"github.com/xuperchain/xupercore/lib/crypto/client"
+	"github.com/xuperchain/xupercore/lib/crypto/client/base"
+)
+
+func TestHashFunc(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		expect string
+	}{
+		{"sm3", "abc", "66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0"},
+		{"keccak256", "", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+	}
+	for _, tc := range testCases {
+		hasher := hashFunc(tc.name)
+		hasher.Write([]byte(tc.input))
+		if out := hex.EncodeToString(hasher.Sum(nil)); out != tc.expect {
+			t.Errorf("%s expect %s got %s", tc.name, tc.expect, out)
+		}
+	}
+	if hashFunc("md5") != nil {
+		t.Error("expect unknown hash")
+	}
+}
+
+func TestCodec(t *testing.T) {
+	input := []byte("\x00\x01hello xuper")
+	for _, name := range []string{"hex", "base58", "base64"} {
+		c := getCodec(name)
+		out, err := c.Decode(c.Encode(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != string(input) {
+			t.Errorf("%s expect %q got %q", name, input, out)
+		}
+	}
+	if _, err := getCodec("base58").Decode([]byte("0OIl")); err == nil {
+		t.Error("expect invalid base58 error")
+	}
+	if _, err := getCodec("base64").Decode([]byte("!!")); err == nil {
+		t.Error("expect invalid base64 error")
+	}
+}
+
+func newTestKey(t *testing.T, cryptoClient base.CryptoClient, seed string) (*ecdsa.PrivateKey, []byte) {
+	priv, err := cryptoClient.GenerateKeyBySeed([]byte(seed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := cryptoClient.GetEcdsaPublicKeyJsonFormatStr(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv, []byte(pub)
+}
+
+func TestVerifySM2(t *testing.T) {
+	gmClient, _ := client.CreateCryptoClient(client.CryptoTypeGM)
+	priv, pub := newTestKey(t, gmClient, "xvm builtin sm2 test seed 00000000")
+	msg := []byte("hello")
+	sig, err := gmClient.SignECDSA(priv, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !verifySM2(pub, sig, msg) {
+		t.Error("expect sm2 verify ok")
+	}
+	if verifySM2(pub, sig, []byte("world")) {
+		t.Error("expect sm2 verify fail")
+	}
+}
+
+func TestVerifySchnorrAndMultiSig(t *testing.T) {
+	cryptoClient, _ := client.CreateCryptoClient(client.CryptoTypeDefault)
+	priv1, pub1 := newTestKey(t, cryptoClient, "xvm builtin multisig test seed 001")
+	priv2, pub2 := newTestKey(t, cryptoClient, "xvm builtin multisig test seed 002")
+	msg := []byte("hello")
+
+	signer, ok := cryptoClient.(interface {
+		SignSchnorr(*ecdsa.PrivateKey, []byte) ([]byte, error)
+	})
+	if !ok {
+		t.Fatal("crypto client not support schnorr")
+	}
+	sig, err := signer.SignSchnorr(priv1, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !verifySchnorr(pub1, sig, msg) || verifySchnorr(pub2, sig, msg) {
+		t.Error("unexpected schnorr verify result")
+	}
+
+	sig, err = cryptoClient.MultiSign([]*ecdsa.PrivateKey{priv1, priv2}, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubs := []json.RawMessage{pub1, pub2}
+	if !verifyMultiSig(pubs, sig, msg) {
+		t.Error("expect multisig verify ok")
+	}
+	if verifyMultiSig(pubs[:1], sig, msg) {
+		t.Error("expect multisig verify fail with partial keys")
+	}
+}
+
+type fakeExecContext struct {
+	exec.Context
+	gasUsed  int64
+	userData map[string]interface{}
+}
+
+func (f *fakeExecContext) GasUsed() int64 {
+	return f.gasUsed
+}
+
+func (f *fakeExecContext) GetUserData(key string) interface{} {
+	return f.userData[key]
+}
+
+func TestChargeGas(t *testing.T) {
+	meter := &builtinGas{limit: 100}
+	ctx := &fakeExecContext{
+		gasUsed:  50,
+		userData: map[string]interface{}{builtinGasKey: meter},
+	}
+	chargeGas(ctx, wordGas(codecBaseGas, codecWordGas, 33))
+	if meter.used != 16 {
+		t.Fatalf("expect 16 gas used, got %d", meter.used)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expect out of gas")
+		}
+	}()
+	chargeGas(ctx, verifyGas)
+}
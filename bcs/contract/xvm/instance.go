@@ -33,6 +33,7 @@ func createInstance(ctx *bridge.Context, code *contractCode, syscall *bridge.Sys
 		}
 	}
 	execCtx.SetUserData(contextIDKey, ctx.ID)
+	execCtx.SetUserData(builtinGasKey, &builtinGas{limit: ctx.ResourceLimits.Cpu})
 	instance := &xvmInstance{
 		bridgeCtx: ctx,
 		execCtx:   execCtx,
@@ -74,6 +75,9 @@ func (x *xvmInstance) ResourceUsed() contract.Limits {
 	limits := contract.Limits{
 		Cpu: x.execCtx.GasUsed(),
 	}
+	if meter, ok := x.execCtx.GetUserData(builtinGasKey).(*builtinGas); ok {
+		limits.Cpu += meter.used
+	}
 	mem := x.execCtx.Memory()
 	if mem != nil {
 		limits.Memory = int64(len(mem))
@@ -0,0 +1,226 @@
+// Package backup 提供链的账本和状态机数据的在线备份与恢复
+// 备份文件为gzip压缩的tar包，依次包含MANIFEST、ledger、state三个文件，
+// ledger和state文件由kv记录顺序组成，每条记录为 uvarint(len(key)) key uvarint(len(value)) value
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/def"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+)
+
+const (
+	// ArchiveVersion 备份文件格式版本
+	ArchiveVersion = 1
+
+	ManifestFileName = "MANIFEST"
+	LedgerFileName   = "ledger"
+	StateFileName    = "state"
+
+	// 等待账本和状态机到达同一高度的重试次数和间隔
+	quiesceRetry    = 50
+	quiesceInterval = 100 * time.Millisecond
+)
+
+var (
+	ErrParameter        = errors.New("backup parameter error")
+	ErrNotConsistent    = errors.New("ledger and state are not at the same tip")
+	ErrArchiveVersion   = errors.New("unsupported archive version")
+	ErrArchiveCorrupted = errors.New("archive corrupted")
+	ErrManifestMismatch = errors.New("manifest mismatch")
+)
+
+// DBInfo 备份中单个数据库的校验信息
+type DBInfo struct {
+	Count  int64  `json:"count"`
+	Size   int64  `json:"size"`
+	Sha256 string `json:"sha256"`
+}
+
+// Manifest 备份文件描述信息
+type Manifest struct {
+	Version     int    `json:"version"`
+	BCName      string `json:"bcname"`
+	Height      int64  `json:"height"`
+	TipBlockid  string `json:"tip_blockid"`
+	GenesisHash string `json:"genesis_hash"`
+	CreateTime  int64  `json:"create_time"`
+	Ledger      DBInfo `json:"ledger"`
+	State       DBInfo `json:"state"`
+}
+
+// Backup 在线备份链的账本和状态机数据
+// 短暂暂停区块确认，在同一高度获取两个数据库的快照后恢复，之后再写入备份文件
+func Backup(bcName string, leg *ledger.Ledger, sta *state.State, w io.Writer) (*Manifest, error) {
+	if bcName == "" || leg == nil || sta == nil || w == nil {
+		return nil, ErrParameter
+	}
+
+	manifest := &Manifest{
+		Version:    ArchiveVersion,
+		BCName:     bcName,
+		CreateTime: time.Now().Unix(),
+	}
+	ledgerIter, stateIter, err := snapshot(leg, sta, manifest)
+	if err != nil {
+		return nil, err
+	}
+	defer ledgerIter.Release()
+	defer stateIter.Release()
+
+	// 第一次遍历计算校验信息，MANIFEST需要写在最前面
+	if manifest.Ledger, err = dumpRecords(ledgerIter, nil); err != nil {
+		return nil, err
+	}
+	if manifest.State, err = dumpRecords(stateIter, nil); err != nil {
+		return nil, err
+	}
+	manifestBuf, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	if err := writeFile(tw, ManifestFileName, int64(len(manifestBuf)), func(w io.Writer) error {
+		_, err := w.Write(manifestBuf)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if err := writeDB(tw, LedgerFileName, ledgerIter, manifest.Ledger); err != nil {
+		return nil, err
+	}
+	if err := writeDB(tw, StateFileName, stateIter, manifest.State); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// snapshot 暂停更新并在账本和状态机位于同一高度时创建迭代器
+// kvdb的迭代器读取的是创建时的快照，恢复更新后不影响备份内容
+func snapshot(leg *ledger.Ledger, sta *state.State, manifest *Manifest) (kvdb.Iterator, kvdb.Iterator, error) {
+	var ledgerIter, stateIter kvdb.Iterator
+	for i := 0; i < quiesceRetry; i++ {
+		err := sta.Quiesce(func() error {
+			meta := leg.GetMeta()
+			if !bytes.Equal(meta.GetTipBlockid(), sta.GetLatestBlockid()) {
+				return ErrNotConsistent
+			}
+
+			manifest.Height = meta.GetTrunkHeight()
+			manifest.TipBlockid = hex.EncodeToString(meta.GetTipBlockid())
+			manifest.GenesisHash = hex.EncodeToString(meta.GetRootBlockid())
+			ledgerIter = leg.GetBaseDB().NewIteratorWithPrefix(nil)
+			stateIter = sta.GetLDB().NewIteratorWithPrefix(nil)
+			return nil
+		})
+		if err == nil {
+			return ledgerIter, stateIter, nil
+		}
+		if err != ErrNotConsistent {
+			return nil, nil, err
+		}
+		time.Sleep(quiesceInterval)
+	}
+
+	return nil, nil, ErrNotConsistent
+}
+
+func writeDB(tw *tar.Writer, name string, iter kvdb.Iterator, info DBInfo) error {
+	return writeFile(tw, name, info.Size, func(w io.Writer) error {
+		written, err := dumpRecords(iter, w)
+		if err != nil {
+			return err
+		}
+		if written != info {
+			return fmt.Errorf("%s changed during backup", name)
+		}
+		return nil
+	})
+}
+
+func writeFile(tw *tar.Writer, name string, size int64, write func(io.Writer) error) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    size,
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	return write(tw)
+}
+
+// dumpRecords 从头遍历迭代器，w不为空时写入记录，返回记录的校验信息
+func dumpRecords(iter kvdb.Iterator, w io.Writer) (DBInfo, error) {
+	var info DBInfo
+	h := sha256.New()
+	out := io.Writer(h)
+	if w != nil {
+		out = io.MultiWriter(h, w)
+	}
+
+	for ok := iter.First(); ok; ok = iter.Next() {
+		n, err := writeRecord(out, iter.Key(), iter.Value())
+		if err != nil {
+			return info, err
+		}
+		info.Count++
+		info.Size += n
+	}
+	if err := iter.Error(); err != nil {
+		return info, err
+	}
+
+	info.Sha256 = hex.EncodeToString(h.Sum(nil))
+	return info, nil
+}
+
+func writeRecord(w io.Writer, key, value []byte) (int64, error) {
+	var total int64
+	for _, data := range [][]byte{key, value} {
+		n, err := def.WriteBytes(w, data)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func readRecord(r *bufio.Reader, limit int64) ([]byte, []byte, error) {
+	key, err := def.ReadBytes(r, limit)
+	if err == io.EOF {
+		return nil, nil, err
+	}
+	if err != nil {
+		return nil, nil, ErrArchiveCorrupted
+	}
+	value, err := def.ReadBytes(r, limit)
+	if err != nil {
+		return nil, nil, ErrArchiveCorrupted
+	}
+	return key, value, nil
+}
@@ -0,0 +1,181 @@
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/def"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/context"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/tx"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	xconf "github.com/xuperchain/xupercore/kernel/common/xconfig"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/lib/crypto/client"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb/memory"
+)
+
+var genesisConf = []byte(`{
+    "version": "1",
+    "predistribution": [
+        {
+            "address": "TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY",
+            "quota": "100000000000000000000"
+        }
+    ],
+    "maxblocksize": "16",
+    "award": "1000000",
+    "decimals": "8",
+    "award_decay": {
+        "height_gap": 31536000,
+        "ratio": 1
+    },
+    "gas_price": {
+        "cpu_rate": 1000,
+        "mem_rate": 1000000,
+        "disk_rate": 1,
+        "xfee_rate": 1
+    },
+    "new_account_resource_amount": 1000,
+    "genesis_consensus": {
+        "name": "single",
+        "config": {
+            "miner": "TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY",
+            "period": 3000
+        }
+    }
+}`)
+
+func newEnvConf(t *testing.T, chainDir string) *xconf.EnvConf {
+	econf, err := mock.NewMemoryEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	econf.ChainDir = chainDir
+	// 恢复和安装后重新打开账本，需要保留关闭后的内存数据
+	t.Cleanup(memory.Retain(econf.GenDataAbsPath(chainDir)))
+	return econf
+}
+
+func createChain(t *testing.T, econf *xconf.EnvConf) (*ledger.Ledger, *state.State) {
+	lctx, err := ledger.NewLedgerCtx(econf, "xuper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	leg, err := ledger.CreateLedger(lctx, genesisConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootTx, err := tx.GenerateRootTx(genesisConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := leg.FormatRootBlock([]*pb.Transaction{rootTx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := leg.ConfirmBlock(block, true); !status.Succ {
+		t.Fatal("confirm root block fail")
+	}
+
+	crypt, err := client.CreateCryptoClient(client.CryptoTypeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sctx, err := context.NewStateCtx(econf, "xuper", leg, crypt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sta, err := state.NewState(sctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sta.Play(block.Blockid); err != nil {
+		t.Fatal(err)
+	}
+	return leg, sta
+}
+
+func openDB(t *testing.T, econf *xconf.EnvConf, dir string) kvdb.Database {
+	db, err := kvdb.CreateKVInstance(&kvdb.KVParameter{
+		DBPath:       filepath.Join(econf.GenDataAbsPath(econf.ChainDir), "xuper", dir),
+		KVEngineType: kvdb.KVEngineTypeMemory,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func equalDB(a, b kvdb.Database) bool {
+	ia := a.NewIteratorWithPrefix(nil)
+	defer ia.Release()
+	ib := b.NewIteratorWithPrefix(nil)
+	defer ib.Release()
+
+	for ia.Next() {
+		if !ib.Next() || !bytes.Equal(ia.Key(), ib.Key()) || !bytes.Equal(ia.Value(), ib.Value()) {
+			return false
+		}
+	}
+	return !ib.Next()
+}
+
+func TestBackupRestore(t *testing.T) {
+	srcConf := newEnvConf(t, "/memory/backup_test/src")
+	leg, sta := createChain(t, srcConf)
+	defer leg.Close()
+	defer sta.Close()
+
+	var buf bytes.Buffer
+	manifest, err := Backup("xuper", leg, sta, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Height != 0 || manifest.Ledger.Count == 0 || manifest.State.Count == 0 {
+		t.Fatalf("unexpected manifest: %+v", manifest)
+	}
+
+	// 链名与备份不一致时拒绝恢复
+	dstConf := newEnvConf(t, "/memory/backup_test/dst")
+	_, err = Restore(dstConf, "other", bytes.NewReader(buf.Bytes()))
+	if !errors.Is(err, ErrManifestMismatch) {
+		t.Fatalf("expect manifest mismatch, got %v", err)
+	}
+
+	restored, err := Restore(dstConf, "", bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *restored != *manifest {
+		t.Fatalf("restored manifest mismatch: %+v", restored)
+	}
+
+	for _, dir := range []string{def.LedgerStrgDirName, def.StateStrgDirName} {
+		if !equalDB(openDB(t, srcConf, dir), openDB(t, dstConf, dir)) {
+			t.Fatalf("restored %s db mismatch", dir)
+		}
+	}
+}
+
+func TestRestoreCorrupted(t *testing.T) {
+	srcConf := newEnvConf(t, "/memory/backup_test/corrupted")
+	leg, sta := createChain(t, srcConf)
+	defer leg.Close()
+	defer sta.Close()
+
+	var buf bytes.Buffer
+	if _, err := Backup("xuper", leg, sta, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	dstConf := newEnvConf(t, "/memory/backup_test/corrupted_dst")
+	data := buf.Bytes()
+	if _, err := Restore(dstConf, "", bytes.NewReader(data[:len(data)/2])); err == nil {
+		t.Fatal("expect error when restore truncated archive")
+	}
+}
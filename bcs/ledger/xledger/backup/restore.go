@@ -0,0 +1,200 @@
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	lconf "github.com/xuperchain/xupercore/bcs/ledger/xledger/config"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/def"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	xconf "github.com/xuperchain/xupercore/kernel/common/xconfig"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+const (
+	// 恢复时单个batch的数据量上限
+	restoreBatchSize = 4 << 20
+)
+
+// Restore 从备份文件恢复链的账本和状态机数据
+// bcName为空时使用备份中的链名，目标链已存在时返回错误；
+// 先校验MANIFEST，写入数据后校验数据摘要，最后打开账本确认与MANIFEST一致
+func Restore(envCfg *xconf.EnvConf, bcName string, r io.Reader) (*Manifest, error) {
+	if envCfg == nil || r == nil {
+		return nil, ErrParameter
+	}
+
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, ErrArchiveCorrupted
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+
+	manifest, err := readManifest(tr)
+	if err != nil {
+		return nil, err
+	}
+	if bcName == "" {
+		bcName = manifest.BCName
+	}
+	if err := validateManifest(manifest, bcName); err != nil {
+		return nil, err
+	}
+
+	chainDir := filepath.Join(envCfg.GenDataAbsPath(envCfg.ChainDir), bcName)
+	if utils.PathExists(chainDir) {
+		return nil, fmt.Errorf("restore chain %s failed because chain dir exist", bcName)
+	}
+
+	lcfg, err := lconf.LoadLedgerConf(envCfg.GenConfFilePath(envCfg.LedgerConf))
+	if err != nil {
+		return nil, err
+	}
+
+	err = restoreData(tr, lcfg, chainDir, manifest)
+	if err == nil {
+		err = verifyLedger(envCfg, bcName, manifest)
+	}
+	if err != nil {
+		os.RemoveAll(chainDir)
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+func readManifest(tr *tar.Reader) (*Manifest, error) {
+	header, err := tr.Next()
+	if err != nil || header.Name != ManifestFileName {
+		return nil, ErrArchiveCorrupted
+	}
+
+	data, err := ioutil.ReadAll(tr)
+	if err != nil {
+		return nil, ErrArchiveCorrupted
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, ErrArchiveCorrupted
+	}
+	return manifest, nil
+}
+
+func validateManifest(manifest *Manifest, bcName string) error {
+	if manifest.Version != ArchiveVersion {
+		return ErrArchiveVersion
+	}
+	if manifest.BCName != bcName {
+		return fmt.Errorf("%w: bcname %s, expect %s", ErrManifestMismatch, manifest.BCName, bcName)
+	}
+	if manifest.Height < 0 {
+		return fmt.Errorf("%w: invalid height %d", ErrManifestMismatch, manifest.Height)
+	}
+	for _, id := range []string{manifest.TipBlockid, manifest.GenesisHash} {
+		if _, err := hex.DecodeString(id); err != nil || id == "" {
+			return fmt.Errorf("%w: invalid blockid %s", ErrManifestMismatch, id)
+		}
+	}
+	return nil
+}
+
+func restoreData(tr *tar.Reader, lcfg *lconf.XLedgerConf, chainDir string, manifest *Manifest) error {
+	dbs := []struct {
+		name string
+		path string
+		info DBInfo
+	}{
+		{LedgerFileName, filepath.Join(chainDir, def.LedgerStrgDirName), manifest.Ledger},
+		{StateFileName, filepath.Join(chainDir, def.StateStrgDirName), manifest.State},
+	}
+
+	for _, db := range dbs {
+		header, err := tr.Next()
+		if err != nil || header.Name != db.name || header.Size != db.info.Size {
+			return ErrArchiveCorrupted
+		}
+		if err := restoreDB(tr, lcfg, db.path, db.info); err != nil {
+			return fmt.Errorf("restore %s failed: %w", db.name, err)
+		}
+	}
+	return nil
+}
+
+func restoreDB(r io.Reader, lcfg *lconf.XLedgerConf, path string, info DBInfo) error {
+	kvParam := &kvdb.KVParameter{
+		DBPath:                path,
+		KVEngineType:          lcfg.KVEngineType,
+		MemCacheSize:          ledger.MemCacheSize,
+		FileHandlersCacheSize: ledger.FileHandlersCacheSize,
+		OtherPaths:            lcfg.OtherPaths,
+		StorageType:           lcfg.StorageType,
+	}
+	db, err := kvdb.CreateKVInstance(kvParam)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	h := sha256.New()
+	br := bufio.NewReader(io.TeeReader(r, h))
+	batch := db.NewBatch()
+	var count int64
+	for {
+		key, value, err := readRecord(br, info.Size)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		batch.Put(key, value)
+		count++
+
+		if batch.ValueSize() >= restoreBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+
+	// tar条目读完时已覆盖条目内的全部数据
+	if count != info.Count || hex.EncodeToString(h.Sum(nil)) != info.Sha256 {
+		return ErrArchiveCorrupted
+	}
+	return batch.Write()
+}
+
+// verifyLedger 打开恢复后的账本，确认与MANIFEST描述的高度一致
+func verifyLedger(envCfg *xconf.EnvConf, bcName string, manifest *Manifest) error {
+	lctx, err := ledger.NewLedgerCtx(envCfg, bcName)
+	if err != nil {
+		return err
+	}
+	leg, err := ledger.OpenLedger(lctx)
+	if err != nil {
+		return err
+	}
+	defer leg.Close()
+
+	meta := leg.GetMeta()
+	if hex.EncodeToString(meta.GetTipBlockid()) != manifest.TipBlockid ||
+		hex.EncodeToString(meta.GetRootBlockid()) != manifest.GenesisHash ||
+		meta.GetTrunkHeight() != manifest.Height {
+		return fmt.Errorf("%w: restored ledger tip %x height %d", ErrManifestMismatch,
+			meta.GetTipBlockid(), meta.GetTrunkHeight())
+	}
+	return nil
+}
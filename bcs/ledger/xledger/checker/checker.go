@@ -0,0 +1,162 @@
+// Package checker 离线检查链的账本和状态机的一致性，并按需修复
+// 修复方式:
+// truncate 账本和状态机都回退到两者一致的最高区块，之后由节点重新同步；
+// replay   账本只裁剪损坏的部分，状态机回滚到账本主干上，缺失的区块在节点启动时重新执行。
+package checker
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+const (
+	RepairTruncate = "truncate"
+	RepairReplay   = "replay"
+)
+
+var (
+	ErrParameter         = errors.New("checker parameter error")
+	ErrRepairMode        = errors.New("unsupported repair mode")
+	ErrGenesisCorrupted  = errors.New("genesis block corrupted, can not repair")
+	ErrStateBlockMissing = errors.New("state latest block not found in ledger, can not repair")
+)
+
+// Report 账本和状态机的检查结果
+type Report struct {
+	Ledger *ledger.CheckReport
+	// 账本元数据中的主干末端
+	TipBlockid []byte
+	TipHeight  int64
+	// 状态机最新执行的区块，区块不在账本中时高度为-1
+	StateBlockid []byte
+	StateHeight  int64
+	StateInTrunk bool
+}
+
+// Consistent 账本没有问题且状态机与账本末端一致
+func (r *Report) Consistent() bool {
+	return len(r.Ledger.Issues) == 0 && bytes.Equal(r.StateBlockid, r.TipBlockid)
+}
+
+// Strings 以可读的形式输出检查结果
+func (r *Report) Strings() []string {
+	lines := make([]string, 0, len(r.Ledger.Issues)+3)
+	lines = append(lines, fmt.Sprintf("ledger tip:%s height:%d checked:%d", utils.F(r.TipBlockid),
+		r.TipHeight, r.Ledger.CheckedBlocks))
+	lines = append(lines, fmt.Sprintf("state latest:%s height:%d in_trunk:%v", utils.F(r.StateBlockid),
+		r.StateHeight, r.StateInTrunk))
+	for _, issue := range r.Ledger.Issues {
+		lines = append(lines, issue.String())
+	}
+	lines = append(lines, fmt.Sprintf("last good block:%s height:%d", utils.F(r.Ledger.LastGoodBlockid),
+		r.Ledger.LastGoodHeight))
+	return lines
+}
+
+// hasFatalIssue 存在需要裁剪账本才能修复的问题
+func (r *Report) hasFatalIssue() bool {
+	return !bytes.Equal(r.Ledger.LastGoodBlockid, r.TipBlockid)
+}
+
+// hasIndexIssue 存在可以重建索引修复的问题
+func (r *Report) hasIndexIssue() bool {
+	for _, kind := range []string{ledger.IssueHeightIndex, ledger.IssueTxIndex, ledger.IssueBranchInfo} {
+		if r.Ledger.HasIssue(kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check 检查账本，并比较状态机最新区块与账本主干
+func Check(leg *ledger.Ledger, sta *state.State) (*Report, error) {
+	if leg == nil || sta == nil {
+		return nil, ErrParameter
+	}
+	ledgerReport, err := leg.Check()
+	if err != nil {
+		return nil, err
+	}
+	meta := leg.GetMeta()
+	report := &Report{
+		Ledger:       ledgerReport,
+		TipBlockid:   meta.TipBlockid,
+		TipHeight:    meta.TrunkHeight,
+		StateBlockid: sta.GetLatestBlockid(),
+		StateHeight:  -1,
+	}
+	if block, err := leg.QueryBlockHeader(report.StateBlockid); err == nil {
+		report.StateHeight = block.Height
+		report.StateInTrunk = block.InTrunk
+	}
+	return report, nil
+}
+
+// Repair 按检查结果修复账本和状态机，修复后需要重新检查
+func Repair(leg *ledger.Ledger, sta *state.State, report *Report, mode string) error {
+	if leg == nil || sta == nil || report == nil {
+		return ErrParameter
+	}
+	if mode != RepairTruncate && mode != RepairReplay {
+		return ErrRepairMode
+	}
+	if report.Ledger.LastGoodHeight < 0 {
+		return ErrGenesisCorrupted
+	}
+	if report.StateHeight < 0 {
+		return ErrStateBlockMissing
+	}
+
+	// 先修复索引，裁剪账本依赖分支信息
+	if report.hasIndexIssue() {
+		if err := leg.RebuildIndexes(); err != nil {
+			return err
+		}
+	}
+
+	// 账本裁剪到最后一个完好的区块，truncate模式下不高于状态机所在的分叉点
+	target := report.TipBlockid
+	if report.hasFatalIssue() {
+		target = report.Ledger.LastGoodBlockid
+	}
+	ancestor, err := commonAncestor(leg, report.StateBlockid, target)
+	if err != nil {
+		return err
+	}
+	if mode == RepairTruncate {
+		target = ancestor
+	}
+
+	// 状态机先回滚到主干上，回滚需要账本中的区块，账本中更高的区块在节点启动时执行
+	if !bytes.Equal(ancestor, report.StateBlockid) {
+		if err := sta.Walk(ancestor, false); err != nil {
+			return err
+		}
+	}
+	if !bytes.Equal(target, report.TipBlockid) {
+		if err := leg.Truncate(target); err != nil {
+			return err
+		}
+		if err := leg.RebuildIndexes(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// commonAncestor 返回两个区块的最近公共祖先
+func commonAncestor(leg *ledger.Ledger, blockid []byte, target []byte) ([]byte, error) {
+	undoBlocks, _, err := leg.FindUndoAndTodoBlocks(blockid, target)
+	if err != nil {
+		return nil, err
+	}
+	if len(undoBlocks) == 0 {
+		return blockid, nil
+	}
+	return undoBlocks[len(undoBlocks)-1].PreHash, nil
+}
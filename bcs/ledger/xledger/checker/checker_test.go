@@ -0,0 +1,207 @@
+package checker
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/def"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/context"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/tx"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	xconf "github.com/xuperchain/xupercore/kernel/common/xconfig"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/lib/crypto/client"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+)
+
+var genesisConf = []byte(`{
+    "version": "1",
+    "predistribution": [
+        {
+            "address": "TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY",
+            "quota": "100000000000000000000"
+        }
+    ],
+    "maxblocksize": "16",
+    "award": "1000000",
+    "decimals": "8",
+    "award_decay": {
+        "height_gap": 31536000,
+        "ratio": 1
+    },
+    "gas_price": {
+        "cpu_rate": 1000,
+        "mem_rate": 1000000,
+        "disk_rate": 1,
+        "xfee_rate": 1
+    },
+    "new_account_resource_amount": 1000,
+    "genesis_consensus": {
+        "name": "single",
+        "config": {
+            "miner": "TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY",
+            "period": 3000
+        }
+    }
+}`)
+
+func createChain(t *testing.T, chainDir string) (*xconf.EnvConf, *ledger.Ledger, *state.State) {
+	econf, err := mock.NewMemoryEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	econf.ChainDir = chainDir
+	lctx, err := ledger.NewLedgerCtx(econf, "xuper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	leg, err := ledger.CreateLedger(lctx, genesisConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootTx, err := tx.GenerateRootTx(genesisConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := leg.FormatRootBlock([]*pb.Transaction{rootTx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := leg.ConfirmBlock(block, true); !status.Succ {
+		t.Fatal("confirm root block fail")
+	}
+
+	crypt, err := client.CreateCryptoClient(client.CryptoTypeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sctx, err := context.NewStateCtx(econf, "xuper", leg, crypt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sta, err := state.NewState(sctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sta.Play(block.Blockid); err != nil {
+		t.Fatal(err)
+	}
+	return econf, leg, sta
+}
+
+// appendBlock 在账本主干上追加只包含奖励交易的区块，play为true时状态机同时执行
+func appendBlock(t *testing.T, leg *ledger.Ledger, sta *state.State, play bool) *pb.InternalBlock {
+	awardTx, err := tx.GenerateAwardTx("miner-1", "1000", []byte("award"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaPk, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	block, err := leg.FormatBlock([]*pb.Transaction{awardTx}, []byte("miner-1"), ecdsaPk, 123456789, 0, 0,
+		leg.GetMeta().TipBlockid, sta.GetTotal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := leg.ConfirmBlock(block, false); !status.Succ {
+		t.Fatal("confirm block fail", status.Error)
+	}
+	if play {
+		if err := sta.Play(block.Blockid); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return block
+}
+
+func TestCheckAndRepairIndex(t *testing.T) {
+	econf, leg, sta := createChain(t, "/memory/checker_test/index")
+	defer leg.Close()
+	defer sta.Close()
+	appendBlock(t, leg, sta, true)
+	appendBlock(t, leg, sta, true)
+
+	report, err := Check(leg, sta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Consistent() || report.Ledger.CheckedBlocks != 3 {
+		t.Fatalf("expect consistent, got %v", report.Strings())
+	}
+
+	// 删除高度索引
+	db, err := kvdb.CreateKVInstance(&kvdb.KVParameter{
+		DBPath:       filepath.Join(econf.GenDataAbsPath(econf.ChainDir), "xuper", def.LedgerStrgDirName),
+		KVEngineType: kvdb.KVEngineTypeMemory,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Delete([]byte(pb.BlockHeightPrefix + fmt.Sprintf("%020d", 1))); err != nil {
+		t.Fatal(err)
+	}
+	report, err = Check(leg, sta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Consistent() || !report.Ledger.HasIssue(ledger.IssueHeightIndex) {
+		t.Fatalf("expect height index issue, got %v", report.Strings())
+	}
+	if !bytes.Equal(report.Ledger.LastGoodBlockid, report.TipBlockid) {
+		t.Fatal("index issue should not break last good block")
+	}
+
+	if err := Repair(leg, sta, report, RepairReplay); err != nil {
+		t.Fatal(err)
+	}
+	report, err = Check(leg, sta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Consistent() || report.TipHeight != 2 {
+		t.Fatalf("expect consistent after repair, got %v", report.Strings())
+	}
+}
+
+func TestRepairStateBehind(t *testing.T) {
+	_, leg, sta := createChain(t, "/memory/checker_test/behind")
+	defer leg.Close()
+	defer sta.Close()
+	block := appendBlock(t, leg, sta, true)
+	appendBlock(t, leg, sta, false)
+
+	report, err := Check(leg, sta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Consistent() || len(report.Ledger.Issues) != 0 || report.StateHeight != 1 || !report.StateInTrunk {
+		t.Fatalf("expect state behind ledger, got %v", report.Strings())
+	}
+	if err := Repair(leg, sta, report, "unknown"); err != ErrRepairMode {
+		t.Fatalf("expect repair mode error, got %v", err)
+	}
+
+	// replay模式保留账本，由节点启动时执行缺失的区块
+	if err := Repair(leg, sta, report, RepairReplay); err != nil {
+		t.Fatal(err)
+	}
+	if leg.GetMeta().TrunkHeight != 2 {
+		t.Fatal("replay mode should keep ledger")
+	}
+
+	if err := Repair(leg, sta, report, RepairTruncate); err != nil {
+		t.Fatal(err)
+	}
+	report, err = Check(leg, sta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Consistent() || !bytes.Equal(report.TipBlockid, block.Blockid) {
+		t.Fatalf("expect ledger truncated to state, got %v", report.Strings())
+	}
+}
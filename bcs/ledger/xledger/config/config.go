@@ -10,10 +10,14 @@ import (
 
 type XLedgerConf struct {
 	// kv storage type
-	KVEngineType string     `yaml:"kvEngineType,omitempty"`
-	OtherPaths   []string   `yaml:"otherPaths,omitempty"`
-	StorageType  string     `yaml:"storageType,omitempty"`
-	Utxo         UtxoConfig `yaml:"utxo,omitempty"`
+	KVEngineType string         `yaml:"kvEngineType,omitempty"`
+	OtherPaths   []string       `yaml:"otherPaths,omitempty"`
+	StorageType  string         `yaml:"storageType,omitempty"`
+	Utxo         UtxoConfig     `yaml:"utxo,omitempty"`
+	Prune        PruneConfig    `yaml:"prune,omitempty"`
+	Snapshot     SnapshotConfig `yaml:"snapshot,omitempty"`
+	// 执行区块时并发校验交易的协程数，0表示使用CPU核数，1表示串行校验，交易写入始终串行
+	ReplayWorkers int `yaml:"replayWorkers,omitempty"`
 }
 
 type UtxoConfig struct {
@@ -21,6 +25,44 @@ type UtxoConfig struct {
 	TmpLockSeconds int `yaml:"tmplockSeconds,omitempty"`
 }
 
+// PruneConfig 历史区块裁剪配置
+// 只有不可逆区块会被裁剪，需要链开启不可逆区块滑动窗口
+type PruneConfig struct {
+	// 是否开启裁剪
+	Enable bool `yaml:"enable,omitempty"`
+	// 保留完整交易的不可逆区块个数
+	KeepBlocks int64 `yaml:"keepBlocks,omitempty"`
+	// 每批裁剪的区块个数，后台逐批裁剪，避免长时间占用账本
+	BatchBlocks int64 `yaml:"batchBlocks,omitempty"`
+	// 被裁剪交易的归档存储，不配置时直接删除
+	Archive ArchiveConfig `yaml:"archive,omitempty"`
+}
+
+// ArchiveConfig 归档存储配置
+type ArchiveConfig struct {
+	// 归档类型: local, s3
+	Type string `yaml:"type,omitempty"`
+	// local为本地目录，相对路径基于链数据目录; s3为bucket中的路径前缀
+	Path string `yaml:"path,omitempty"`
+	// s3配置
+	Bucket   string `yaml:"bucket,omitempty"`
+	Ak       string `yaml:"ak,omitempty"`
+	Sk       string `yaml:"sk,omitempty"`
+	Region   string `yaml:"region,omitempty"`
+	Endpoint string `yaml:"endpoint,omitempty"`
+}
+
+// SnapshotConfig 状态快照配置
+// 快照包含某一高度的状态机数据，新节点下载快照后只需同步之后的区块
+type SnapshotConfig struct {
+	// 生成快照的区块间隔，0表示不生成
+	Interval int64 `yaml:"interval,omitempty"`
+	// 快照分片大小
+	ChunkSize int64 `yaml:"chunkSize,omitempty"`
+	// 保留的快照个数
+	KeepCount int `yaml:"keepCount,omitempty"`
+}
+
 func LoadLedgerConf(cfgFile string) (*XLedgerConf, error) {
 	cfg := GetDefLedgerConf()
 	err := cfg.loadConf(cfgFile)
@@ -40,6 +82,16 @@ func GetDefLedgerConf() *XLedgerConf {
 			CacheSize:      1000,
 			TmpLockSeconds: 60,
 		},
+		Prune: PruneConfig{
+			Enable:      false,
+			KeepBlocks:  10000,
+			BatchBlocks: 100,
+		},
+		Snapshot: SnapshotConfig{
+			Interval:  0,
+			ChunkSize: 4 << 20,
+			KeepCount: 2,
+		},
 	}
 }
 
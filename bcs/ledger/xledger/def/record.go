@@ -0,0 +1,43 @@
+package def
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// 备份和快照中kv记录的编码，每个字段为 uvarint(len(data)) data
+
+var ErrRecordCorrupted = errors.New("record corrupted")
+
+// WriteBytes 写入带长度前缀的数据，返回写入的字节数
+func WriteBytes(w io.Writer, data []byte) (int64, error) {
+	var lenBuf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(lenBuf[:], uint64(len(data)))
+	if _, err := w.Write(lenBuf[:n]); err != nil {
+		return 0, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return int64(n), err
+	}
+	return int64(n + len(data)), nil
+}
+
+// ReadBytes 读取带长度前缀的数据，长度超过limit时视为损坏
+// 在字段边界读到结尾时返回io.EOF，其他情况的不完整数据返回ErrRecordCorrupted
+func ReadBytes(r *bufio.Reader, limit int64) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil || int64(size) > limit {
+		return nil, ErrRecordCorrupted
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, ErrRecordCorrupted
+	}
+	return data, nil
+}
@@ -0,0 +1,104 @@
+package ledger
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	lconf "github.com/xuperchain/xupercore/bcs/ledger/xledger/config"
+	levels3 "github.com/xuperchain/xupercore/lib/storage/s3"
+)
+
+var (
+	// ErrArchiveNotFound is returned when data not exist in archive
+	ErrArchiveNotFound = errors.New("data not found in archive")
+)
+
+// Archive 归档存储，保存被裁剪的交易，key为txid
+type Archive interface {
+	Put(key []byte, value []byte) error
+	Get(key []byte) ([]byte, error)
+}
+
+// NewArchive 根据配置创建归档存储，未配置时返回nil
+func NewArchive(cfg *lconf.ArchiveConfig, dataDir string) (Archive, error) {
+	switch cfg.Type {
+	case "":
+		return nil, nil
+	case "local":
+		dir := cfg.Path
+		if dir == "" {
+			dir = "archive"
+		}
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(dataDir, dir)
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+		return &localArchive{dir: dir}, nil
+	case "s3":
+		client, err := levels3.GetS3Client(levels3.OpenOption{
+			Bucket:   cfg.Bucket,
+			Path:     cfg.Path,
+			Ak:       cfg.Ak,
+			Sk:       cfg.Sk,
+			Region:   cfg.Region,
+			Endpoint: cfg.Endpoint,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &s3Archive{client: client}, nil
+	}
+	return nil, fmt.Errorf("archive type %s not supported", cfg.Type)
+}
+
+// localArchive 按key的前两个字节分目录保存在本地
+type localArchive struct {
+	dir string
+}
+
+func (a *localArchive) path(key []byte) string {
+	name := hex.EncodeToString(key)
+	if len(name) < 4 {
+		return filepath.Join(a.dir, name)
+	}
+	return filepath.Join(a.dir, name[:4], name)
+}
+
+func (a *localArchive) Put(key []byte, value []byte) error {
+	p := a.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	// 先写临时文件再改名，避免读到不完整的数据
+	tmp := p + ".tmp"
+	if err := ioutil.WriteFile(tmp, value, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
+func (a *localArchive) Get(key []byte) ([]byte, error) {
+	data, err := ioutil.ReadFile(a.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrArchiveNotFound
+	}
+	return data, err
+}
+
+type s3Archive struct {
+	client *levels3.S3Client
+}
+
+func (a *s3Archive) Put(key []byte, value []byte) error {
+	return a.client.PutBytes(hex.EncodeToString(key), value)
+}
+
+func (a *s3Archive) Get(key []byte) ([]byte, error) {
+	return a.client.GetBytes(hex.EncodeToString(key))
+}
@@ -0,0 +1,114 @@
+package ledger
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/xuperchain/xupercore/protos"
+)
+
+// 出块奖励分配:
+// 奖励按AwardSplit中的比例分给多个地址，AwardProposer代表出块的矿工，按比例取整后的余数归第一个地址；
+// 未配置AwardSplit时奖励全部归矿工。配置MaxSupply后奖励不超过发行上限与当前总量的差值。
+
+// AwardProposer 奖励分配中代表矿工的地址
+const AwardProposer = "$proposer"
+
+var (
+	ErrInvalidAwardSplit = errors.New("invalid award split")
+	ErrInvalidMaxSupply  = errors.New("invalid max supply")
+)
+
+// AwardRecipient 奖励的接收地址和分配比例
+type AwardRecipient struct {
+	Address string `json:"address"`
+	Ratio   int64  `json:"ratio"`
+}
+
+// VerifyAwardSplit 校验奖励分配，比例必须为正数且地址不重复
+func VerifyAwardSplit(split []AwardRecipient) error {
+	if len(split) == 0 {
+		return ErrInvalidAwardSplit
+	}
+	addrs := make(map[string]bool, len(split))
+	for _, r := range split {
+		if r.Address == "" || r.Ratio <= 0 || addrs[r.Address] {
+			return ErrInvalidAwardSplit
+		}
+		addrs[r.Address] = true
+	}
+	return nil
+}
+
+// SplitAward 按比例拆分奖励，生成奖励交易的输出
+func SplitAward(award *big.Int, proposer string, split []AwardRecipient) []*protos.TxOutput {
+	if len(split) == 0 {
+		split = []AwardRecipient{{Address: AwardProposer, Ratio: 1}}
+	}
+	sum := int64(0)
+	for _, r := range split {
+		sum += r.Ratio
+	}
+	outputs := make([]*protos.TxOutput, 0, len(split))
+	remain := big.NewInt(0).Set(award)
+	for _, r := range split {
+		amount := big.NewInt(0).Mul(award, big.NewInt(r.Ratio))
+		amount.Div(amount, big.NewInt(sum))
+		remain.Sub(remain, amount)
+		addr := r.Address
+		if addr == AwardProposer {
+			addr = proposer
+		}
+		outputs = append(outputs, &protos.TxOutput{ToAddr: []byte(addr), Amount: amount.Bytes()})
+	}
+	// 取整的余数归第一个地址
+	first := big.NewInt(0).SetBytes(outputs[0].Amount)
+	outputs[0].Amount = first.Add(first, remain).Bytes()
+	return outputs
+}
+
+// GetAwardSplit 返回创世配置中的奖励分配，未配置时返回nil
+func (rc *RootConfig) GetAwardSplit() []AwardRecipient {
+	return rc.AwardSplit
+}
+
+// GetMaxSupply 返回发行上限，未配置时返回nil
+func (rc *RootConfig) GetMaxSupply() *big.Int {
+	if rc.AwardSchedule.MaxSupply == "" {
+		return nil
+	}
+	maxSupply, ok := big.NewInt(0).SetString(rc.AwardSchedule.MaxSupply, 10)
+	if !ok || maxSupply.Sign() < 0 {
+		return nil
+	}
+	return maxSupply
+}
+
+// CapAward 根据发行上限和当前总量限制奖励，未配置发行上限时返回原奖励
+func (gb *GenesisBlock) CapAward(award *big.Int, total *big.Int) *big.Int {
+	maxSupply := gb.config.GetMaxSupply()
+	if maxSupply == nil {
+		return award
+	}
+	left := big.NewInt(0).Sub(maxSupply, total)
+	if left.Sign() <= 0 {
+		return big.NewInt(0)
+	}
+	if award.Cmp(left) > 0 {
+		return left
+	}
+	return award
+}
+
+// verifyAwardConfig 校验创世配置中的奖励分配和发行上限
+func (rc *RootConfig) verifyAwardConfig() error {
+	if len(rc.AwardSplit) > 0 {
+		if err := VerifyAwardSplit(rc.AwardSplit); err != nil {
+			return err
+		}
+	}
+	if rc.AwardSchedule.MaxSupply != "" && rc.GetMaxSupply() == nil {
+		return ErrInvalidMaxSupply
+	}
+	return nil
+}
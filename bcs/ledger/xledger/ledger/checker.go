@@ -0,0 +1,299 @@
+package ledger
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/lib/cache"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+// 账本一致性检查:
+// 从创世块开始沿主干检查区块的链接关系、高度、blockid、merkle根、高度索引和交易索引，
+// 再检查分支信息和账本元数据。节点异常退出后离线使用，检查期间账本不能有写入。
+
+const (
+	IssueBlockMissing = "block_missing"
+	IssueBlockid      = "blockid_mismatch"
+	IssueLinkage      = "linkage"
+	IssueHeight       = "height"
+	IssueHeightIndex  = "height_index"
+	IssueMerkleRoot   = "merkle_root"
+	IssueTxMissing    = "tx_missing"
+	IssueTxIndex      = "tx_index"
+	IssueBranchInfo   = "branch_info"
+	IssueMeta         = "meta"
+)
+
+// CheckIssue 检查发现的问题
+type CheckIssue struct {
+	Kind    string
+	Height  int64
+	Blockid []byte
+	Detail  string
+}
+
+func (ci *CheckIssue) String() string {
+	return fmt.Sprintf("[%s] height:%d blockid:%s %s", ci.Kind, ci.Height, utils.F(ci.Blockid), ci.Detail)
+}
+
+// CheckReport 账本检查结果
+type CheckReport struct {
+	// 检查过的主干区块数
+	CheckedBlocks int64
+	Issues        []*CheckIssue
+	// 从创世块开始连续通过检查的最后一个主干区块，修复时账本裁剪到这个区块
+	LastGoodBlockid []byte
+	LastGoodHeight  int64
+}
+
+// HasIssue 是否存在指定类型的问题
+func (cr *CheckReport) HasIssue(kind string) bool {
+	for _, issue := range cr.Issues {
+		if issue.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (cr *CheckReport) addIssue(kind string, height int64, blockid []byte, format string, args ...interface{}) {
+	cr.Issues = append(cr.Issues, &CheckIssue{
+		Kind:    kind,
+		Height:  height,
+		Blockid: blockid,
+		Detail:  fmt.Sprintf(format, args...),
+	})
+}
+
+// isIndexIssue 索引类的问题可以通过RebuildIndexes修复，不需要裁剪账本
+func isIndexIssue(kind string) bool {
+	return kind == IssueHeightIndex || kind == IssueTxIndex || kind == IssueBranchInfo
+}
+
+func heightKey(height int64) []byte {
+	return []byte(fmt.Sprintf("%020d", height))
+}
+
+// Check 检查账本的一致性
+func (l *Ledger) Check() (*CheckReport, error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	meta := l.meta
+	report := &CheckReport{LastGoodHeight: -1}
+	var prev *pb.InternalBlock
+	good := true
+	for height := int64(0); height <= meta.TrunkHeight; height++ {
+		start := len(report.Issues)
+		// 高度索引和前一个区块的NextHash需要一致
+		expect := meta.RootBlockid
+		if prev != nil {
+			expect = prev.NextHash
+		}
+		indexed, err := l.heightTable.Get(heightKey(height))
+		if err != nil {
+			report.addIssue(IssueHeightIndex, height, expect, "height index missing")
+			indexed = nil
+		}
+		blockid := expect
+		switch {
+		case len(expect) == 0 && len(indexed) == 0:
+			report.addIssue(IssueLinkage, height, nil, "trunk ends before tip")
+		case len(expect) == 0:
+			report.addIssue(IssueLinkage, height, indexed, "next hash of previous block missing")
+			blockid = indexed
+		case len(indexed) > 0 && !bytes.Equal(indexed, expect):
+			report.addIssue(IssueHeightIndex, height, indexed, "height index mismatch, expect:%s", utils.F(expect))
+		}
+		if len(blockid) == 0 {
+			break
+		}
+
+		block, err := l.fetchBlock(blockid)
+		if err != nil {
+			report.addIssue(IssueBlockMissing, height, blockid, "query block error:%v", err)
+			break
+		}
+		report.CheckedBlocks++
+		l.checkBlock(block, height, prev, report)
+
+		for _, issue := range report.Issues[start:] {
+			if !isIndexIssue(issue.Kind) {
+				good = false
+			}
+		}
+		if good {
+			report.LastGoodBlockid = block.Blockid
+			report.LastGoodHeight = block.Height
+		}
+		prev = block
+	}
+
+	// 主干末端需要与元数据一致
+	if prev != nil && !bytes.Equal(prev.Blockid, meta.TipBlockid) {
+		report.addIssue(IssueMeta, prev.Height, prev.Blockid, "trunk tip mismatch, meta tip:%s", utils.F(meta.TipBlockid))
+	}
+	if exist, _ := l.heightTable.Has(heightKey(meta.TrunkHeight + 1)); exist {
+		report.addIssue(IssueHeightIndex, meta.TrunkHeight+1, nil, "stale height index above trunk height")
+	}
+	if err := l.checkBranchInfo(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// checkBlock 检查单个主干区块
+func (l *Ledger) checkBlock(block *pb.InternalBlock, height int64, prev *pb.InternalBlock, report *CheckReport) {
+	if block.Height != height {
+		report.addIssue(IssueHeight, height, block.Blockid, "block height:%d", block.Height)
+	}
+	if !block.InTrunk {
+		report.addIssue(IssueLinkage, height, block.Blockid, "trunk block not marked in trunk")
+	}
+	if prev != nil && !bytes.Equal(block.PreHash, prev.Blockid) {
+		report.addIssue(IssueLinkage, height, block.Blockid, "pre hash:%s, expect:%s",
+			utils.F(block.PreHash), utils.F(prev.Blockid))
+	}
+	if blockid, err := MakeBlockID(block); err != nil || !bytes.Equal(blockid, block.Blockid) {
+		report.addIssue(IssueBlockid, height, block.Blockid, "make blockid:%s, err:%v", utils.F(blockid), err)
+	}
+	if len(block.MerkleTree) == 0 || int(block.TxCount) > len(block.MerkleTree) ||
+		!bytes.Equal(block.MerkleTree[len(block.MerkleTree)-1], block.MerkleRoot) {
+		report.addIssue(IssueMerkleRoot, height, block.Blockid, "merkle tree does not match merkle root")
+		return
+	}
+
+	// 已裁剪的区块只保存了区块头
+	if block.Height <= l.GetPrunedHeight() {
+		return
+	}
+	txs := make([]*pb.Transaction, 0, block.TxCount)
+	for _, txid := range block.MerkleTree[:block.TxCount] {
+		pbTxBuf, err := l.confirmedTable.Get(txid)
+		if err != nil {
+			report.addIssue(IssueTxMissing, height, block.Blockid, "tx %s missing, err:%v", utils.F(txid), err)
+			return
+		}
+		tx := &pb.Transaction{}
+		if err := proto.Unmarshal(pbTxBuf, tx); err != nil || !bytes.Equal(tx.Txid, txid) {
+			report.addIssue(IssueTxMissing, height, block.Blockid, "tx %s corrupted", utils.F(txid))
+			return
+		}
+		if !bytes.Equal(tx.Blockid, block.Blockid) {
+			report.addIssue(IssueTxIndex, height, block.Blockid, "tx %s points to block %s",
+				utils.F(txid), utils.F(tx.Blockid))
+		}
+		txs = append(txs, tx)
+	}
+	merkleTree := MakeMerkleTree(txs)
+	if len(merkleTree) == 0 || !bytes.Equal(merkleTree[len(merkleTree)-1], block.MerkleRoot) {
+		report.addIssue(IssueMerkleRoot, height, block.Blockid, "merkle root mismatch with txs")
+	}
+}
+
+// checkBranchInfo 检查分支信息，每个分支末端需要存在且高度一致，主干末端需要在分支信息中
+func (l *Ledger) checkBranchInfo(report *CheckReport) error {
+	it := l.baseDB.NewIteratorWithPrefix([]byte(pb.BranchInfoPrefix))
+	defer it.Release()
+	hasTip := false
+	for it.Next() {
+		blockid := append([]byte{}, it.Key()[len(pb.BranchInfoPrefix):]...)
+		height, err := strconv.ParseInt(string(it.Value()), 10, 64)
+		if err != nil {
+			report.addIssue(IssueBranchInfo, -1, blockid, "invalid branch height:%s", it.Value())
+			continue
+		}
+		block, err := l.fetchBlock(blockid)
+		if err != nil {
+			report.addIssue(IssueBranchInfo, height, blockid, "branch tip not found")
+			continue
+		}
+		if block.Height != height {
+			report.addIssue(IssueBranchInfo, height, blockid, "branch tip height:%d", block.Height)
+		}
+		if bytes.Equal(blockid, l.meta.TipBlockid) {
+			hasTip = true
+		}
+	}
+	if it.Error() != nil {
+		return it.Error()
+	}
+	if !hasTip {
+		report.addIssue(IssueBranchInfo, l.meta.TrunkHeight, l.meta.TipBlockid, "trunk tip not in branch info")
+	}
+	return nil
+}
+
+// RebuildIndexes 沿主干重建高度索引和交易索引，并清理无效的分支信息
+// 主干中缺失的区块之后无法重建，需要先裁剪账本
+func (l *Ledger) RebuildIndexes() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	batch := l.baseDB.NewBatch()
+	blockid := l.meta.RootBlockid
+	height := int64(0)
+	// 后台裁剪协程会并发更新裁剪高度
+	prunedHeight := l.GetPrunedHeight()
+	for ; height <= l.meta.TrunkHeight && len(blockid) > 0; height++ {
+		block, err := l.fetchBlock(blockid)
+		if err != nil {
+			l.xlog.Warn("rebuild indexes stopped, block missing", "height", height, "blockid", utils.F(blockid))
+			break
+		}
+		batch.Put(append([]byte(pb.BlockHeightPrefix), heightKey(height)...), block.Blockid)
+		if height > prunedHeight {
+			if err := l.correctTxsBlockid(block.Blockid, batch); err != nil {
+				l.xlog.Warn("rebuild indexes stopped, correct tx index failed", "height", height, "err", err)
+				break
+			}
+		}
+		blockid = block.NextHash
+	}
+	// 删除主干高度以上的索引
+	for ; ; height++ {
+		if exist, _ := l.heightTable.Has(heightKey(height)); !exist {
+			break
+		}
+		if height > l.meta.TrunkHeight {
+			batch.Delete(append([]byte(pb.BlockHeightPrefix), heightKey(height)...))
+		}
+	}
+	if err := l.rebuildBranchInfo(batch); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	l.blockCache = cache.NewLRUCache(BlockCacheSize)
+	l.xlog.Info("rebuild ledger indexes succeed", "trunkHeight", l.meta.TrunkHeight)
+	return nil
+}
+
+// rebuildBranchInfo 删除不存在或高度错误的分支末端，补充主干末端
+func (l *Ledger) rebuildBranchInfo(batch kvdb.Batch) error {
+	it := l.baseDB.NewIteratorWithPrefix([]byte(pb.BranchInfoPrefix))
+	defer it.Release()
+	for it.Next() {
+		key := append([]byte{}, it.Key()...)
+		height, err := strconv.ParseInt(string(it.Value()), 10, 64)
+		block, findErr := l.fetchBlock(key[len(pb.BranchInfoPrefix):])
+		if err != nil || findErr != nil || block.Height != height {
+			batch.Delete(key)
+		}
+	}
+	if it.Error() != nil {
+		return it.Error()
+	}
+	if tip, err := l.fetchBlock(l.meta.TipBlockid); err == nil {
+		return batch.Put(append([]byte(pb.BranchInfoPrefix), tip.Blockid...),
+			[]byte(strconv.FormatInt(tip.Height, 10)))
+	}
+	return nil
+}
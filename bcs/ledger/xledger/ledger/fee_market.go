@@ -0,0 +1,165 @@
+package ledger
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+// 费用市场:
+// 每个区块带有基础费用(每单位gas)，由父区块的基础费用和父区块交易总大小相对目标大小的偏离程度决定，
+// 父区块超过目标大小时上调，低于目标大小时下调，每个区块最多调整1/ChangeDenominator。
+// 交易的手续费中gas*基础费用的部分被销毁或转入国库账户，剩余部分作为小费归矿工。
+
+const (
+	defaultTargetPercent     = 50
+	defaultChangeDenominator = 8
+)
+
+var (
+	ErrBaseFeeMismatch = errors.New("base fee of block mismatch")
+)
+
+// FeeMarketConfig 费用市场配置
+type FeeMarketConfig struct {
+	Enabled bool `json:"enabled"`
+	// 创世块之后第一个区块的基础费用
+	InitialBaseFee string `json:"initial_base_fee"`
+	// 基础费用的下限
+	MinBaseFee string `json:"min_base_fee"`
+	// 目标区块大小占MaxBlockSize的百分比，默认50
+	TargetPercent int64 `json:"target_percent"`
+	// 单个区块基础费用的最大调整比例为1/ChangeDenominator，默认8
+	ChangeDenominator int64 `json:"change_denominator"`
+	// 基础费用的接收地址，为空时销毁
+	Treasury string `json:"treasury"`
+}
+
+// IsFeeMarketEnabled 是否开启费用市场，nofee的链不开启
+func (rc *RootConfig) IsFeeMarketEnabled() bool {
+	return rc.FeeMarket.Enabled && !rc.NoFee
+}
+
+// GetFeeMarketTreasury 返回基础费用的接收地址，为空表示销毁
+func (rc *RootConfig) GetFeeMarketTreasury() string {
+	return rc.FeeMarket.Treasury
+}
+
+func (fc *FeeMarketConfig) initialBaseFee() *big.Int {
+	fee, ok := big.NewInt(0).SetString(fc.InitialBaseFee, 10)
+	if !ok || fee.Sign() < 0 {
+		return big.NewInt(0)
+	}
+	return fee
+}
+
+func (fc *FeeMarketConfig) minBaseFee() *big.Int {
+	fee, ok := big.NewInt(0).SetString(fc.MinBaseFee, 10)
+	if !ok || fee.Sign() < 0 {
+		return big.NewInt(0)
+	}
+	return fee
+}
+
+func (fc *FeeMarketConfig) targetPercent() int64 {
+	if fc.TargetPercent <= 0 || fc.TargetPercent > 100 {
+		return defaultTargetPercent
+	}
+	return fc.TargetPercent
+}
+
+func (fc *FeeMarketConfig) changeDenominator() int64 {
+	if fc.ChangeDenominator <= 0 {
+		return defaultChangeDenominator
+	}
+	return fc.ChangeDenominator
+}
+
+// NextBaseFee 根据父区块的基础费用和交易总大小计算子区块的基础费用
+func (fc *FeeMarketConfig) NextBaseFee(parentBaseFee *big.Int, parentSize, maxBlockSize int64) *big.Int {
+	target := maxBlockSize * fc.targetPercent() / 100
+	if target <= 0 || parentSize == target {
+		return big.NewInt(0).Set(parentBaseFee)
+	}
+	diff := parentSize - target
+	if diff < 0 {
+		diff = -diff
+	}
+	// delta = parentBaseFee * |size - target| / target / denominator
+	delta := big.NewInt(0).Mul(parentBaseFee, big.NewInt(diff))
+	delta.Div(delta, big.NewInt(target))
+	delta.Div(delta, big.NewInt(fc.changeDenominator()))
+	next := big.NewInt(0)
+	if parentSize > target {
+		// 区块超过目标大小时至少上调1，避免基础费用为0后无法上调
+		if delta.Sign() == 0 {
+			delta.SetInt64(1)
+		}
+		next.Add(parentBaseFee, delta)
+	} else {
+		next.Sub(parentBaseFee, delta)
+	}
+	if min := fc.minBaseFee(); next.Cmp(min) < 0 {
+		next.Set(min)
+	}
+	return next
+}
+
+// BlockTxSize 返回区块中交易的总大小，用于衡量区块的占用程度
+func BlockTxSize(block *pb.InternalBlock) int64 {
+	size := int64(0)
+	for _, tx := range block.Transactions {
+		size += int64(proto.Size(tx))
+	}
+	return size
+}
+
+// CalcBaseFee 计算父区块为preHash的区块的基础费用，未开启费用市场时返回nil
+func (l *Ledger) CalcBaseFee(preHash []byte) (*big.Int, error) {
+	config := l.GenesisBlock.GetConfig()
+	if !config.IsFeeMarketEnabled() || len(preHash) == 0 {
+		return nil, nil
+	}
+	parent, err := l.QueryBlock(preHash)
+	if err != nil {
+		return nil, err
+	}
+	// 创世块不带基础费用
+	if len(parent.BaseFee) == 0 {
+		return config.FeeMarket.initialBaseFee(), nil
+	}
+	parentBaseFee := big.NewInt(0).SetBytes(parent.BaseFee)
+	// 使用创世配置中的区块大小，保证所有节点计算结果一致
+	return config.FeeMarket.NextBaseFee(parentBaseFee, BlockTxSize(parent), l.GetMaxBlockSize()), nil
+}
+
+// EncodeBaseFee 编码区块头中的基础费用，基础费用为0时编码为[]byte{0}，与未开启费用市场的区块区分
+func EncodeBaseFee(fee *big.Int) []byte {
+	if fee == nil {
+		return nil
+	}
+	if fee.Sign() == 0 {
+		return []byte{0}
+	}
+	return fee.Bytes()
+}
+
+// verifyBaseFee 校验区块头中的基础费用
+func (l *Ledger) verifyBaseFee(block *pb.InternalBlock) error {
+	baseFee, err := l.CalcBaseFee(block.PreHash)
+	if err != nil {
+		return err
+	}
+	expect := EncodeBaseFee(baseFee)
+	if !bytes.Equal(expect, block.BaseFee) {
+		l.xlog.Warn("base fee mismatch", "blockid", utils.F(block.Blockid),
+			"expect", utils.F(expect), "actual", utils.F(block.BaseFee))
+		return ErrBaseFeeMismatch
+	}
+	return nil
+}
@@ -36,6 +36,15 @@ type RootConfig struct {
 		HeightGap int64   `json:"height_gap"`
 		Ratio     float64 `json:"ratio"`
 	} `json:"award_decay"`
+	// AwardSchedule 奖励减半和发行上限
+	AwardSchedule struct {
+		// 每隔HalvingHeight个区块奖励减半，0表示不减半
+		HalvingHeight int64 `json:"halving_height"`
+		// 发行总量上限，为空表示不限制
+		MaxSupply string `json:"max_supply"`
+	} `json:"award_schedule"`
+	// AwardSplit 出块奖励的分配比例，为空时全部归矿工，可通过提案修改
+	AwardSplit []AwardRecipient `json:"award_split"`
 	GasPrice struct {
 		CpuRate  int64 `json:"cpu_rate"`
 		MemRate  int64 `json:"mem_rate"`
@@ -55,6 +64,60 @@ type RootConfig struct {
 	IrreversibleSlideWindow string `json:"irreversibleslidewindow"`
 	// GroupChainContract
 	GroupChainContract InvokeRequest `json:"group_chain_contract"`
+	// FeeMarket 按区块大小动态调整基础费用
+	FeeMarket FeeMarketConfig `json:"fee_market"`
+	// ModifyBlockAddr 可以脱敏已确认交易的监管地址，为空表示不开启
+	ModifyBlockAddr string `json:"modify_block_addr"`
+	// StorageQuota 合约存储计量，开启后预执行和交易进入交易池时检查合约的存储用量不超过配额
+	StorageQuota StorageQuotaConfig `json:"storage_quota"`
+	// StateRoot 区块状态根的分叉配置
+	StateRoot StateRootConfig `json:"state_root"`
+	// ContractVersion 合约版本历史的分叉配置
+	ContractVersion ContractVersionConfig `json:"contract_version"`
+	// ContractCallRevert 跨合约调用失败时回滚被调合约修改的分叉配置
+	ContractCallRevert ContractCallRevertConfig `json:"contract_call_revert"`
+	// ParallelTimerTask 定时任务并发预执行的分叉配置
+	ParallelTimerTask ParallelTimerTaskConfig `json:"parallel_timer_task"`
+}
+
+// StorageQuotaConfig 合约存储计量配置
+type StorageQuotaConfig struct {
+	Enabled bool `json:"enabled"`
+	// 未通过提案单独设置配额的合约可使用的字节数，0表示不限制
+	DefaultQuota int64 `json:"default_quota"`
+}
+
+// ContractVersionConfig 合约版本历史配置
+type ContractVersionConfig struct {
+	// 从该高度起部署和升级合约记录版本历史，0表示不开启
+	ForkHeight int64 `json:"fork_height"`
+}
+
+// IsContractVersionEnabled 高度为height的区块中部署和升级合约是否记录版本历史
+func (rc *RootConfig) IsContractVersionEnabled(height int64) bool {
+	return rc.ContractVersion.ForkHeight > 0 && height >= rc.ContractVersion.ForkHeight
+}
+
+// ContractCallRevertConfig 跨合约调用回滚配置
+type ContractCallRevertConfig struct {
+	// 从该高度起被调合约失败时回滚其对状态的修改，0表示不开启
+	ForkHeight int64 `json:"fork_height"`
+}
+
+// IsContractCallRevertEnabled 高度为height的区块中被调合约失败时是否回滚其修改
+func (rc *RootConfig) IsContractCallRevertEnabled(height int64) bool {
+	return rc.ContractCallRevert.ForkHeight > 0 && height >= rc.ContractCallRevert.ForkHeight
+}
+
+// ParallelTimerTaskConfig 定时任务并发预执行配置
+type ParallelTimerTaskConfig struct {
+	// 从该高度起定时任务并发预执行后合并为一个定时交易，0表示不开启
+	ForkHeight int64 `json:"fork_height"`
+}
+
+// IsParallelTimerTaskEnabled 高度为height的区块中定时任务是否并发预执行
+func (rc *RootConfig) IsParallelTimerTaskEnabled(height int64) bool {
+	return rc.ParallelTimerTask.ForkHeight > 0 && height >= rc.ParallelTimerTask.ForkHeight
 }
 
 // GasPrice define gas rate for utxo
@@ -206,6 +269,10 @@ func NewGenesisBlock(genesisCfg []byte) (*GenesisBlock, error) {
 		config.GasPrice.XfeeRate = 0
 	}
 
+	if err := config.verifyAwardConfig(); err != nil {
+		return nil, err
+	}
+
 	gb := &GenesisBlock{
 		awardCache: cache.NewLRUCache(awardCacheSize),
 		config:     config,
@@ -221,6 +288,16 @@ func (gb *GenesisBlock) GetConfig() *RootConfig {
 
 // CalcAward calc system award by block height
 func (gb *GenesisBlock) CalcAward(blockHeight int64) *big.Int {
+	award := gb.calcDecayedAward(blockHeight)
+	halvingHeight := gb.config.AwardSchedule.HalvingHeight
+	if halvingHeight <= 0 {
+		return award
+	}
+	// 缓存中的奖励不能被修改
+	return big.NewInt(0).Rsh(award, uint(blockHeight/halvingHeight))
+}
+
+func (gb *GenesisBlock) calcDecayedAward(blockHeight int64) *big.Int {
 	award := big.NewInt(0)
 	award.SetString(gb.config.Award, 10)
 	if gb.config.AwardDecay.HeightGap == 0 { //无衰减策略
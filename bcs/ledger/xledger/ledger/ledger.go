@@ -86,8 +86,20 @@ type Ledger struct {
 	blkHeaderCache *cache.LRUCache // block header cache, 加速fetchBlock
 	cryptoClient   cryptoBase.CryptoClient
 	confirmBatch   kvdb.Batch //新增区块
+	stateRootFunc  StateRootFunc // 计算区块状态根，由状态机设置
+	prunedTable    kvdb.Database // 已裁剪的交易
+	archive        Archive       // 被裁剪交易的归档存储
+	prunedHeight   int64         // 已裁剪的最高区块高度
+	pruneTarget    int64         // 后台裁剪的目标不可逆高度
+	pruneMutex     sync.Mutex    // 串行化裁剪
+	pruneSignal    chan struct{}
+	pruneExit      chan struct{}
+	pruneWaiter    sync.WaitGroup
 }
 
+// StateRootFunc 根据父区块、交易列表和矿工计算区块执行后的状态根，只对分叉高度之后的区块调用
+type StateRootFunc func(preHash []byte, txList []*pb.Transaction, proposer []byte) ([]byte, error)
+
 // ConfirmStatus block status
 type ConfirmStatus struct {
 	Succ        bool  // 区块是否提交成功
@@ -136,6 +148,7 @@ func newLedger(lctx *LedgerCtx, createIfMissing bool, genesisCfg []byte) (*Ledge
 	ledger.blocksTable = kvdb.NewTable(baseDB, pb.BlocksTablePrefix)
 	ledger.pendingTable = kvdb.NewTable(baseDB, pb.PendingBlocksTablePrefix)
 	ledger.heightTable = kvdb.NewTable(baseDB, pb.BlockHeightPrefix)
+	ledger.prunedTable = kvdb.NewTable(baseDB, pb.PrunedTxTablePrefix)
 	ledger.xlog = lctx.XLog
 	ledger.meta = &pb.LedgerMeta{}
 	ledger.blockCache = cache.NewLRUCache(BlockCacheSize)
@@ -185,11 +198,37 @@ func newLedger(lctx *LedgerCtx, createIfMissing bool, genesisCfg []byte) (*Ledge
 	}
 	ledger.cryptoClient = crypto
 
+	if err := ledger.loadPrunedHeight(); err != nil {
+		lctx.XLog.Warn("failed to load pruned height", "err", err)
+		return nil, err
+	}
+	ledger.archive, err = NewArchive(&lctx.LedgerCfg.Prune.Archive, storePath)
+	if err != nil {
+		lctx.XLog.Warn("failed to open archive", "err", err)
+		return nil, err
+	}
+	if err := ledger.checkStateRootPrune(); err != nil {
+		lctx.XLog.Warn("state root fork conflicts with prune", "err", err)
+		return nil, err
+	}
+	ledger.pruneSignal = make(chan struct{}, 1)
+	ledger.pruneExit = make(chan struct{})
+	if lctx.LedgerCfg.Prune.Enable {
+		ledger.pruneWaiter.Add(1)
+		go ledger.pruneWorker()
+	}
+
 	return ledger, nil
 }
 
 // Close close an instance of ledger
 func (l *Ledger) Close() {
+	select {
+	case <-l.pruneExit:
+	default:
+		close(l.pruneExit)
+	}
+	l.pruneWaiter.Wait()
 	l.baseDB.Close()
 }
 
@@ -323,7 +362,25 @@ func (l *Ledger) formatBlock(txList []*pb.Transaction,
 	if len(block.MerkleTree) > 0 {
 		block.MerkleRoot = block.MerkleTree[len(block.MerkleTree)-1]
 	}
-	var err error
+	baseFee, err := l.CalcBaseFee(preHash)
+	if err != nil {
+		l.xlog.Warn("format block calc base fee failed", "preHash", utils.F(preHash), "err", err)
+		return nil, err
+	}
+	block.BaseFee = EncodeBaseFee(baseFee)
+	withStateRoot, err := l.IsStateRootEnabledAfter(preHash)
+	if err != nil {
+		l.xlog.Warn("format block query parent failed", "preHash", utils.F(preHash), "err", err)
+		return nil, err
+	}
+	if needSign && withStateRoot && l.stateRootFunc != nil {
+		stateRoot, err := l.stateRootFunc(preHash, txList, proposer)
+		if err != nil {
+			l.xlog.Warn("format block calc state root failed", "preHash", utils.F(preHash), "err", err)
+			return nil, err
+		}
+		block.StateRoot = stateRoot
+	}
 	block.Blockid, err = MakeBlockID(block)
 	if err != nil {
 		return nil, err
@@ -460,10 +517,13 @@ func (l *Ledger) IsValidTx(idx int, tx *pb.Transaction, block *pb.InternalBlock)
 		}
 		//交易奖励的金额是否符合策略?
 		awardTarget := l.GenesisBlock.CalcAward(block.Height)
-		amountBytes := tx.TxOutputs[0].Amount
 		awardN := big.NewInt(0)
-		awardN.SetBytes(amountBytes)
-		if awardN.Cmp(awardTarget) != 0 {
+		for _, txOutput := range tx.TxOutputs {
+			awardN.Add(awardN, big.NewInt(0).SetBytes(txOutput.Amount))
+		}
+		// 配置发行上限时奖励可能少于目标值，具体分配由状态机校验
+		cmp := awardN.Cmp(awardTarget)
+		if cmp > 0 || (cmp < 0 && l.GenesisBlock.GetConfig().GetMaxSupply() == nil) {
 			l.xlog.Warn("invalid block award found", "award", awardN.String(), "target", awardTarget.String())
 			return false
 		}
@@ -794,7 +854,14 @@ func (l *Ledger) queryBlock(blockid []byte, needBody bool) (*pb.InternalBlock, e
 	if needBody {
 		realTransactions := make([]*pb.Transaction, 0)
 		for _, txid := range block.MerkleTree[:block.TxCount] {
-			pbTxBuf, kvErr := l.confirmedTable.Get(txid)
+			pbTxBuf, kvErr := l.getTxBuf(txid)
+			if kvErr == ErrTxPruned {
+				return block, ErrBlockPruned
+			}
+			// 通过快照同步的账本中，快照之前区块的交易只保存了被状态机引用的部分
+			if def.NormalizedKVError(kvErr) == def.ErrKVNotFound && block.Height <= l.GetPrunedHeight() {
+				return block, ErrBlockPruned
+			}
 			if kvErr != nil {
 				l.xlog.Warn("tx not found", "kvErr", kvErr, "txid", utils.F(txid))
 				return block, kvErr
@@ -834,14 +901,17 @@ func (l *Ledger) QueryBlockHeader(blockid []byte) (*pb.InternalBlock, error) {
 
 // HasTransaction check if a transaction exists in the ledger
 func (l *Ledger) HasTransaction(txid []byte) (bool, error) {
-	table := l.confirmedTable
-	return table.Has(txid)
+	exist, err := l.confirmedTable.Has(txid)
+	if err != nil || exist {
+		return exist, err
+	}
+	return l.prunedTable.Has(txid)
 }
 
 // QueryTransaction query a transaction in the ledger and return it if exist
+// pruned transaction is read from archive, ErrTxPruned is returned if no archive
 func (l *Ledger) QueryTransaction(txid []byte) (*pb.Transaction, error) {
-	table := l.confirmedTable
-	pbTxBuf, kvErr := table.Get(txid)
+	pbTxBuf, kvErr := l.getTxBuf(txid)
 	if kvErr != nil {
 		if def.NormalizedKVError(kvErr) == def.ErrKVNotFound {
 			return nil, ErrTxNotFound
@@ -859,25 +929,20 @@ func (l *Ledger) QueryTransaction(txid []byte) (*pb.Transaction, error) {
 // IsTxInTrunk check if a transaction is in trunk by transaction ID
 func (l *Ledger) IsTxInTrunk(txid []byte) bool {
 	var blk *pb.InternalBlock
-	var err error
-	table := l.confirmedTable
-	pbTxBuf, kvErr := table.Get(txid)
-	if kvErr != nil {
-		return false
-	}
-	realTx := &pb.Transaction{}
-	pbErr := proto.Unmarshal(pbTxBuf, realTx)
-	if pbErr != nil {
-		l.xlog.Warn("IsTxInTrunk error", "txid", utils.F(txid), "pbErr", pbErr)
+	blockid, err := l.queryTxBlockid(txid)
+	if err != nil {
+		if err != ErrTxNotFound {
+			l.xlog.Warn("IsTxInTrunk error", "txid", utils.F(txid), "err", err)
+		}
 		return false
 	}
-	blkInCache, exist := l.blockCache.Get(string(realTx.Blockid))
+	blkInCache, exist := l.blockCache.Get(string(blockid))
 	if exist {
 		blk = blkInCache.(*pb.InternalBlock)
 	} else {
-		blk, err = l.queryBlock(realTx.Blockid, false)
+		blk, err = l.queryBlock(blockid, false)
 		if err != nil {
-			l.xlog.Warn("IsTxInTrunk error", "blkid", utils.F(realTx.Blockid), "kvErr", err)
+			l.xlog.Warn("IsTxInTrunk error", "blkid", utils.F(blockid), "kvErr", err)
 			return false
 		}
 	}
@@ -1071,6 +1136,20 @@ func (l *Ledger) QueryBlockByHeight(height int64) (*pb.InternalBlock, error) {
 	return l.QueryBlock(blockID)
 }
 
+// Quiesce 持有账本锁执行f，期间不会确认或裁剪区块
+// f中不能调用需要账本锁的方法，例如QueryBlock
+func (l *Ledger) Quiesce(f func() error) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return f()
+}
+
+// SetStateRootFunc 设置区块状态根的计算方法
+func (l *Ledger) SetStateRootFunc(f StateRootFunc) {
+	l.stateRootFunc = f
+}
+
 // GetBaseDB get internal db instance
 func (l *Ledger) GetBaseDB() kvdb.Database {
 	return l.baseDB
@@ -1186,6 +1265,23 @@ func (l *Ledger) VerifyBlock(block *pb.InternalBlock, logid string) (bool, error
 		return false, nil
 	}
 
+	if err := l.verifyBaseFee(block); err != nil {
+		l.xlog.Warn("VerifyBlock verify base fee error", "logid", logid, "error", err)
+		return false, nil
+	}
+
+	// 状态根的值在状态机执行区块时校验，这里只校验是否按分叉高度携带
+	withStateRoot, err := l.IsStateRootEnabledAfter(block.PreHash)
+	if err != nil {
+		l.xlog.Warn("VerifyBlock query parent block error", "logid", logid, "error", err)
+		return false, nil
+	}
+	if withStateRoot != (len(block.StateRoot) > 0) {
+		l.xlog.Warn("VerifyBlock state root is unexpected", "logid", logid, "withStateRoot", withStateRoot,
+			"block state root", utils.F(block.StateRoot))
+		return false, nil
+	}
+
 	k, err := l.cryptoClient.GetEcdsaPublicKeyFromJsonStr(string(block.Pubkey))
 	if err != nil {
 		l.xlog.Warn("VerifyBlock get ecdsa from block error", "logid", logid, "error", err)
@@ -1205,14 +1301,50 @@ func (l *Ledger) VerifyBlock(block *pb.InternalBlock, logid string) (bool, error
 	return true, nil
 }
 
+// QueryTxMerkleProof query a confirmed transaction with its merkle branch and block header
+func (l *Ledger) QueryTxMerkleProof(txid []byte) (*pb.TxMerkleProof, error) {
+	tx, err := l.QueryTransaction(txid)
+	if err != nil {
+		return nil, err
+	}
+	header, err := l.QueryBlockHeader(tx.GetBlockid())
+	if err != nil {
+		return nil, err
+	}
+	index := -1
+	for i, id := range header.MerkleTree[:header.TxCount] {
+		if bytes.Equal(id, txid) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, ErrTxNotFound
+	}
+	branch, err := MakeMerkleProof(header.MerkleTree, index)
+	if err != nil {
+		return nil, err
+	}
+	// 区块头可能来自缓存，复制后再裁剪
+	proofHeader := proto.Clone(header).(*pb.InternalBlock)
+	proofHeader.MerkleTree = nil
+	proofHeader.Transactions = nil
+	return &pb.TxMerkleProof{
+		Tx:     tx,
+		Header: proofHeader,
+		Index:  int32(index),
+		Branch: branch,
+	}, nil
+}
+
 // QueryBlockByTxid query block by txid after it has confirmed
 func (l *Ledger) QueryBlockByTxid(txid []byte) (*pb.InternalBlock, error) {
-	if exit, _ := l.HasTransaction(txid); !exit {
+	blockid, err := l.queryTxBlockid(txid)
+	if err == ErrTxNotFound {
 		return nil, ErrTxNotConfirmed
 	}
-	tx, err := l.QueryTransaction(txid)
 	if err != nil {
 		return nil, err
 	}
-	return l.queryBlock(tx.GetBlockid(), false)
+	return l.queryBlock(blockid, false)
 }
@@ -50,6 +50,43 @@ func MakeMerkleTree(txList []*pb.Transaction) [][]byte {
 	return tree
 }
 
+// MakeMerkleProof 根据MakeMerkleTree生成的merkle树，返回第index个叶子自下而上的兄弟节点，没有右兄弟时为nil
+func MakeMerkleProof(merkleTree [][]byte, index int) ([][]byte, error) {
+	leafSize := (len(merkleTree) + 1) / 2
+	if index < 0 || index >= leafSize || merkleTree[index] == nil {
+		return nil, errors.New("merkle leaf index out of range")
+	}
+	branch := make([][]byte, 0)
+	for offset, size := 0, leafSize; size > 1; offset, size = offset+size, size/2 {
+		branch = append(branch, merkleTree[offset+(index^1)])
+		index /= 2
+	}
+	return branch, nil
+}
+
+// VerifyMerkleProof 校验txid及其merkle路径能否得到merkleRoot，txCount用于约束路径长度
+func VerifyMerkleProof(merkleRoot []byte, txCount int, txid []byte, index int, branch [][]byte) bool {
+	if txCount <= 0 || index < 0 || index >= txCount {
+		return false
+	}
+	if 1<<uint(len(branch)) != getLeafSize(txCount) {
+		return false
+	}
+	node := txid
+	for _, sibling := range branch {
+		switch {
+		case index&1 == 1:
+			node = hash.DoubleSha256(bytes.Join([][]byte{sibling, node}, []byte{}))
+		case len(sibling) == 0: //没有右孩子
+			node = hash.DoubleSha256(bytes.Join([][]byte{node, node}, []byte{}))
+		default:
+			node = hash.DoubleSha256(bytes.Join([][]byte{node, sibling}, []byte{}))
+		}
+		index /= 2
+	}
+	return bytes.Equal(node, merkleRoot)
+}
+
 //序列化系统合约失败的Txs
 func encodeFailedTxs(buf *bytes.Buffer, block *pb.InternalBlock) error {
 	txids := []string{}
@@ -183,5 +220,19 @@ func MakeBlockID(block *pb.InternalBlock) ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("encodeJustify failed, err=%v", err)
 	}
+	// 分叉高度之前的区块不带状态根，保持原有的blockid
+	if len(block.StateRoot) > 0 {
+		err = binary.Write(buf, binary.LittleEndian, block.StateRoot)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// 未开启费用市场的区块保持原有的blockid
+	if len(block.BaseFee) > 0 {
+		err = binary.Write(buf, binary.LittleEndian, block.BaseFee)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return hash.DoubleSha256(buf.Bytes()), nil
 }
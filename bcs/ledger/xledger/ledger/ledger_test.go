@@ -1,6 +1,7 @@
 package ledger
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
@@ -12,6 +13,7 @@ import (
 	"math/big"
 	//"os"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 
@@ -19,33 +21,16 @@ import (
 	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	"github.com/xuperchain/xupercore/kernel/mock"
 	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/utils"
 	_ "github.com/xuperchain/xupercore/lib/storage/kvdb/leveldb"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb/memory"
 	"github.com/xuperchain/xupercore/protos"
 )
 
 const AliceAddress = "TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY"
 const BobAddress = "WNWk3ekXeM5M2232dY2uCJmEqWhfQiDYT"
 
-func openLedger() (*Ledger, error) {
-	workspace, dirErr := ioutil.TempDir("/tmp", "")
-	if dirErr != nil {
-		return nil, dirErr
-	}
-	os.RemoveAll(workspace)
-	defer os.RemoveAll(workspace)
-	econf, err := mock.NewEnvConfForTest()
-	if err != nil {
-		return nil, err
-	}
-	logs.InitLog(econf.GenConfFilePath(econf.LogConf), econf.GenDirAbsPath(econf.LogDir))
-
-	lctx, err := NewLedgerCtx(econf, "xuper")
-	if err != nil {
-		return nil, err
-	}
-	lctx.EnvCfg.ChainDir = workspace
-
-	genesisConf := []byte(`
+var genesisConf = []byte(`
 		{
     "version": "1",
     "predistribution": [
@@ -77,6 +62,26 @@ func openLedger() (*Ledger, error) {
     }
 }
     `)
+
+func openLedger() (*Ledger, error) {
+	workspace, dirErr := ioutil.TempDir("/tmp", "")
+	if dirErr != nil {
+		return nil, dirErr
+	}
+	os.RemoveAll(workspace)
+	defer os.RemoveAll(workspace)
+	econf, err := mock.NewEnvConfForTest()
+	if err != nil {
+		return nil, err
+	}
+	logs.InitLog(econf.GenConfFilePath(econf.LogConf), econf.GenDirAbsPath(econf.LogDir))
+
+	lctx, err := NewLedgerCtx(econf, "xuper")
+	if err != nil {
+		return nil, err
+	}
+	lctx.EnvCfg.ChainDir = workspace
+
 	ledgerIns, err := CreateLedger(lctx, genesisConf)
 	if err != nil {
 		return nil, err
@@ -92,6 +97,49 @@ func TestOpenClose(t *testing.T) {
 	ledger.Close()
 }
 
+func TestOpenCloseWithMemoryEngine(t *testing.T) {
+	econf, err := mock.NewMemoryEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lctx, err := NewLedgerCtx(econf, "xuper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lctx.EnvCfg.ChainDir = "/memory/ledger_test"
+	// 关闭后重新打开需要保留内存数据
+	defer memory.Retain(lctx.EnvCfg.GenDataAbsPath(lctx.EnvCfg.ChainDir))()
+
+	ledger, err := CreateLedger(lctx, genesisConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := &pb.Transaction{Coinbase: true, Desc: []byte(`{"maxblocksize" : "128"}`)}
+	tx.TxOutputs = append(tx.TxOutputs, &protos.TxOutput{Amount: []byte("888"), ToAddr: []byte(BobAddress)})
+	tx.Txid, _ = txhash.MakeTransactionID(tx)
+	block, err := ledger.FormatRootBlock([]*pb.Transaction{tx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := ledger.ConfirmBlock(block, true); !status.Succ {
+		t.Fatal("confirm root block fail")
+	}
+	ledger.Close()
+
+	// 同一进程内重新打开可以读到之前写入的数据
+	ledger, err = OpenLedger(lctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+	if hasTx, _ := ledger.HasTransaction(tx.Txid); !hasTx {
+		t.Fatal("genesis tx not exist after reopen")
+	}
+	if utils.FileIsExist(lctx.EnvCfg.ChainDir) {
+		t.Fatal("memory engine should not create data dir")
+	}
+}
+
 func TestBasicFunc(t *testing.T) {
 	ledger, err := openLedger()
 	if err != nil {
@@ -494,3 +542,408 @@ func TestTruncate(t *testing.T) {
 
 	ledger.Close()
 }
+
+func TestMerkleProof(t *testing.T) {
+	for n := 1; n <= 9; n++ {
+		txList := make([]*pb.Transaction, n)
+		for i := range txList {
+			txList[i] = &pb.Transaction{Txid: []byte(fmt.Sprintf("txid-%d-%d", n, i))}
+		}
+		tree := MakeMerkleTree(txList)
+		root := tree[len(tree)-1]
+		for i, tx := range txList {
+			branch, err := MakeMerkleProof(tree, i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !VerifyMerkleProof(root, n, tx.Txid, i, branch) {
+				t.Fatalf("verify merkle proof failed, txCount:%d index:%d", n, i)
+			}
+			if n > 1 && VerifyMerkleProof(root, n, tx.Txid, (i+1)%n, branch) {
+				t.Fatalf("proof with wrong index should fail, txCount:%d index:%d", n, i)
+			}
+			if VerifyMerkleProof(root, n+8, tx.Txid, i, branch) {
+				t.Fatalf("proof with wrong tx count should fail, txCount:%d index:%d", n, i)
+			}
+		}
+		if _, err := MakeMerkleProof(tree, n); n > 1 && n&(n-1) != 0 && err == nil {
+			t.Fatalf("padding leaf should not have proof, txCount:%d", n)
+		}
+	}
+}
+
+func TestPruneBlocks(t *testing.T) {
+	econf, err := mock.NewMemoryEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lctx, err := NewLedgerCtx(econf, "xuper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lctx.EnvCfg.ChainDir = "/memory/prune_test"
+	// 关闭后重新打开需要保留内存数据
+	defer memory.Retain(lctx.EnvCfg.GenDataAbsPath(lctx.EnvCfg.ChainDir))()
+	archiveDir, err := ioutil.TempDir("", "archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(archiveDir)
+	lctx.LedgerCfg.Prune.Enable = true
+	lctx.LedgerCfg.Prune.KeepBlocks = 2
+	lctx.LedgerCfg.Prune.BatchBlocks = 3
+	lctx.LedgerCfg.Prune.Archive.Type = "local"
+	lctx.LedgerCfg.Prune.Archive.Path = archiveDir
+
+	ledger, err := CreateLedger(lctx, genesisConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootTx := &pb.Transaction{Coinbase: true, Desc: []byte(`{"maxblocksize" : "128"}`)}
+	rootTx.TxOutputs = append(rootTx.TxOutputs, &protos.TxOutput{Amount: []byte("888"), ToAddr: []byte(BobAddress)})
+	rootTx.Txid, _ = txhash.MakeTransactionID(rootTx)
+	block, err := ledger.FormatRootBlock([]*pb.Transaction{rootTx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := ledger.ConfirmBlock(block, true); !status.Succ {
+		t.Fatal("confirm root block fail")
+	}
+	ecdsaPk, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	var blocks []*pb.InternalBlock
+	var txs []*pb.Transaction
+	for i := 1; i <= 6; i++ {
+		tx := &pb.Transaction{Desc: []byte(fmt.Sprintf("tx-%d", i)), Nonce: fmt.Sprint(i)}
+		tx.TxOutputsExt = append(tx.TxOutputsExt, &protos.TxOutputExt{Bucket: "b", Key: []byte("k"), Value: []byte(fmt.Sprint(i))})
+		tx.Txid, _ = txhash.MakeTransactionID(tx)
+		block, err = ledger.FormatBlock([]*pb.Transaction{tx}, []byte(AliceAddress), ecdsaPk,
+			int64(i), 0, 0, block.Blockid, big.NewInt(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status := ledger.ConfirmBlock(block, false); !status.Succ {
+			t.Fatal("confirm block fail")
+		}
+		blocks = append(blocks, block)
+		txs = append(txs, tx)
+	}
+
+	// 不可逆高度为6，保留2个区块，后台分两批裁剪1~4
+	ledger.SchedulePrune(6)
+	for i := 0; i < 100 && ledger.GetPrunedHeight() < 4; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if ledger.GetPrunedHeight() != 4 {
+		t.Fatal("unexpected pruned height", ledger.GetPrunedHeight())
+	}
+	if exist, _ := ledger.confirmedTable.Has(txs[1].Txid); exist {
+		t.Fatal("pruned tx should be removed from confirmed table")
+	}
+	if exist, _ := ledger.confirmedTable.Has(txs[4].Txid); !exist {
+		t.Fatal("recent tx should not be pruned")
+	}
+	if hasTx, _ := ledger.HasTransaction(rootTx.Txid); !hasTx {
+		t.Fatal("genesis tx should not be pruned")
+	}
+	for i, tx := range txs {
+		if hasTx, _ := ledger.HasTransaction(tx.Txid); !hasTx || !ledger.IsTxInTrunk(tx.Txid) {
+			t.Fatal("tx should be kept in index", i)
+		}
+		blk, err := ledger.QueryBlockByTxid(tx.Txid)
+		if err != nil || string(blk.Blockid) != string(blocks[i].Blockid) {
+			t.Fatal("query block by txid failed", i, err)
+		}
+	}
+	// 从归档读取完整交易和区块
+	ltx, err := ledger.QueryTransaction(txs[1].Txid)
+	if err != nil || string(ltx.Desc) != "tx-2" {
+		t.Fatal("query pruned tx from archive failed", err)
+	}
+	blk, err := ledger.QueryBlock(blocks[2].Blockid)
+	if err != nil || len(blk.Transactions) != 1 || string(blk.Transactions[0].Desc) != "tx-3" {
+		t.Fatal("query pruned block from archive failed", err)
+	}
+	ledger.Close()
+
+	// 重新打开后没有归档时只能读到区块头和状态相关字段
+	lctx.LedgerCfg.Prune.Archive.Type = ""
+	ledger, err = OpenLedger(lctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+	if ledger.GetPrunedHeight() != 4 {
+		t.Fatal("pruned height should be persisted", ledger.GetPrunedHeight())
+	}
+	if _, err := ledger.QueryTransaction(txs[0].Txid); err != ErrTxPruned {
+		t.Fatal("unexpected result for pruned tx", err)
+	}
+	if _, err := ledger.QueryBlock(blocks[0].Blockid); err != ErrBlockPruned {
+		t.Fatal("unexpected result for pruned block", err)
+	}
+	if _, err := ledger.QueryBlockHeader(blocks[0].Blockid); err != nil {
+		t.Fatal("header of pruned block should be kept", err)
+	}
+	stateTx, err := ledger.QueryTransactionState(txs[0].Txid)
+	if err != nil || len(stateTx.TxOutputsExt) != 1 || string(stateTx.TxOutputsExt[0].Value) != "1" {
+		t.Fatal("query pruned tx for state failed", err)
+	}
+	if err := ledger.PruneBlocks(6); err != nil || ledger.GetPrunedHeight() != 4 {
+		t.Fatal("prune again should do nothing", err)
+	}
+}
+
+func TestStateRootForkWithPrune(t *testing.T) {
+	forkConf := bytes.Replace(genesisConf, []byte(`"version": "1",`),
+		[]byte(`"version": "1", "state_root": {"fork_height": 3},`), 1)
+	archiveDir, err := ioutil.TempDir("", "archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(archiveDir)
+
+	cases := []struct {
+		name    string
+		prune   bool
+		archive string
+		err     error
+	}{
+		{"no_prune", false, "", nil},
+		{"prune_without_archive", true, "", ErrStateRootPruned},
+		{"prune_with_archive", true, "local", nil},
+	}
+	for _, c := range cases {
+		econf, err := mock.NewMemoryEnvConfForTest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		lctx, err := NewLedgerCtx(econf, "xuper")
+		if err != nil {
+			t.Fatal(err)
+		}
+		lctx.EnvCfg.ChainDir = "/memory/state_root_prune_test_" + c.name
+		lctx.LedgerCfg.Prune.Enable = c.prune
+		lctx.LedgerCfg.Prune.Archive.Type = c.archive
+		lctx.LedgerCfg.Prune.Archive.Path = archiveDir
+		ledger, err := CreateLedger(lctx, forkConf)
+		if err != c.err {
+			t.Fatal("unexpected result", c.name, err)
+		}
+		if ledger != nil {
+			ledger.Close()
+		}
+	}
+}
+
+func TestNextBaseFee(t *testing.T) {
+	fc := &FeeMarketConfig{TargetPercent: 50, ChangeDenominator: 8}
+	cases := []struct {
+		parent int64
+		size   int64
+		expect int64
+	}{
+		{1000, 500, 1000},  // 等于目标大小
+		{1000, 1000, 1125}, // 满块上调1/8
+		{1000, 0, 875},     // 空块下调1/8
+		{1000, 750, 1062},
+		{0, 501, 1}, // 基础费用为0时至少上调1
+	}
+	for i, c := range cases {
+		next := fc.NextBaseFee(big.NewInt(c.parent), c.size, 1000)
+		if next.Int64() != c.expect {
+			t.Fatal("unexpected base fee", i, next, c.expect)
+		}
+	}
+	// 不低于下限
+	fc.MinBaseFee = "10"
+	if next := fc.NextBaseFee(big.NewInt(11), 0, 1000); next.Int64() != 10 {
+		t.Fatal("base fee should not be lower than min base fee", next)
+	}
+}
+
+func TestFeeMarketBlock(t *testing.T) {
+	econf, err := mock.NewMemoryEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lctx, err := NewLedgerCtx(econf, "xuper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lctx.EnvCfg.ChainDir = "/memory/fee_market_test"
+	ledger, err := CreateLedger(lctx, genesisConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+	// 账本使用创世交易中的配置
+	rootTx := &pb.Transaction{Coinbase: true,
+		Desc: []byte(`{"maxblocksize" : "1", "fee_market": {"enabled": true, "initial_base_fee": "1000"}}`)}
+	rootTx.TxOutputs = append(rootTx.TxOutputs, &protos.TxOutput{Amount: []byte("888"), ToAddr: []byte(BobAddress)})
+	rootTx.Txid, _ = txhash.MakeTransactionID(rootTx)
+	rootBlock, err := ledger.FormatRootBlock([]*pb.Transaction{rootTx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rootBlock.BaseFee) != 0 {
+		t.Fatal("genesis block should not have base fee")
+	}
+	if status := ledger.ConfirmBlock(rootBlock, true); !status.Succ {
+		t.Fatal("confirm root block fail")
+	}
+
+	ecdsaPk, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	newTx := func(desc string) *pb.Transaction {
+		tx := &pb.Transaction{Desc: []byte(desc)}
+		tx.Txid, _ = txhash.MakeTransactionID(tx)
+		return tx
+	}
+	block1, err := ledger.FormatBlock([]*pb.Transaction{newTx("tx-1")}, []byte(AliceAddress), ecdsaPk,
+		1, 0, 0, rootBlock.Blockid, big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if big.NewInt(0).SetBytes(block1.BaseFee).Int64() != 1000 {
+		t.Fatal("first block should use initial base fee", block1.BaseFee)
+	}
+	if status := ledger.ConfirmBlock(block1, false); !status.Succ {
+		t.Fatal("confirm block fail")
+	}
+	// 区块远小于目标大小，基础费用下调
+	block2, err := ledger.FormatBlock([]*pb.Transaction{newTx("tx-2")}, []byte(AliceAddress), ecdsaPk,
+		2, 0, 0, block1.Blockid, big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fc := ledger.GenesisBlock.GetConfig().FeeMarket
+	expect := fc.NextBaseFee(big.NewInt(1000), BlockTxSize(block1), ledger.GetMaxBlockSize())
+	if expect.Int64() >= 1000 || big.NewInt(0).SetBytes(block2.BaseFee).Cmp(expect) != 0 {
+		t.Fatal("unexpected base fee", block2.BaseFee, expect)
+	}
+	if err := ledger.verifyBaseFee(block2); err != nil {
+		t.Fatal("verify base fee fail", err)
+	}
+	block2.BaseFee = EncodeBaseFee(big.NewInt(1))
+	if err := ledger.verifyBaseFee(block2); err != ErrBaseFeeMismatch {
+		t.Fatal("expect base fee mismatch", err)
+	}
+}
+
+func TestAwardSchedule(t *testing.T) {
+	gb, err := NewGenesisBlock([]byte(`{"award": "1000", "award_schedule": {"halving_height": 10, "max_supply": "5000"},
+		"award_split": [{"address": "$proposer", "ratio": 7}, {"address": "treasury", "ratio": 2}, {"address": "pool", "ratio": 1}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gb.CalcAward(9).Int64() != 1000 || gb.CalcAward(10).Int64() != 500 || gb.CalcAward(25).Int64() != 250 {
+		t.Fatal("unexpected halving award", gb.CalcAward(9), gb.CalcAward(10), gb.CalcAward(25))
+	}
+	// 不超过发行上限
+	if award := gb.CapAward(big.NewInt(1000), big.NewInt(4500)); award.Int64() != 500 {
+		t.Fatal("award should be capped", award)
+	}
+	if award := gb.CapAward(big.NewInt(1000), big.NewInt(6000)); award.Sign() != 0 {
+		t.Fatal("award should be zero after max supply", award)
+	}
+
+	// 取整余数归第一个地址
+	outputs := SplitAward(big.NewInt(999), "miner", gb.GetConfig().GetAwardSplit())
+	expect := []struct {
+		addr   string
+		amount int64
+	}{{"miner", 700}, {"treasury", 199}, {"pool", 99}}
+	sum := int64(0)
+	for i, e := range expect {
+		amount := big.NewInt(0).SetBytes(outputs[i].Amount).Int64()
+		sum += amount
+		if string(outputs[i].ToAddr) != e.addr || (i > 0 && amount != e.amount) {
+			t.Fatal("unexpected award output", i, string(outputs[i].ToAddr), amount)
+		}
+	}
+	if sum != 999 {
+		t.Fatal("award outputs should sum to award", sum)
+	}
+
+	if _, err := NewGenesisBlock([]byte(`{"award": "1000", "award_split": [{"address": "a", "ratio": 0}]}`)); err != ErrInvalidAwardSplit {
+		t.Fatal("expect invalid award split", err)
+	}
+	if _, err := NewGenesisBlock([]byte(`{"award": "1000", "award_schedule": {"max_supply": "-1"}}`)); err != ErrInvalidMaxSupply {
+		t.Fatal("expect invalid max supply", err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	ledger, err := openLedger()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ledger.Close()
+
+	t1 := &pb.Transaction{Coinbase: true, Desc: []byte(`{"maxblocksize" : "128"}`)}
+	t1.TxOutputs = append(t1.TxOutputs, &protos.TxOutput{Amount: []byte("888"), ToAddr: []byte(BobAddress)})
+	t1.Txid, _ = txhash.MakeTransactionID(t1)
+	block1, err := ledger.FormatRootBlock([]*pb.Transaction{t1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := ledger.ConfirmBlock(block1, true); !status.Succ {
+		t.Fatal("confirm block fail")
+	}
+	ecdsaPk, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	preHash := block1.Blockid
+	var blocks []*pb.InternalBlock
+	for i := 0; i < 2; i++ {
+		tx := &pb.Transaction{Desc: []byte(fmt.Sprintf("tx-%d", i))}
+		tx.Txid, _ = txhash.MakeTransactionID(tx)
+		block, err := ledger.FormatBlock([]*pb.Transaction{tx}, []byte("xchain-Miner-222222"), ecdsaPk,
+			223456789, 0, 0, preHash, big.NewInt(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status := ledger.ConfirmBlock(block, false); !status.Succ {
+			t.Fatal("confirm block fail")
+		}
+		blocks = append(blocks, block)
+		preHash = block.Blockid
+	}
+
+	report, err := ledger.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Issues) != 0 || report.CheckedBlocks != 3 || report.LastGoodHeight != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	// 高度索引和交易索引错误可以重建
+	ledger.heightTable.Delete(heightKey(1))
+	wrongTx := proto.Clone(blocks[0].Transactions[0]).(*pb.Transaction)
+	wrongTx.Blockid = blocks[1].Blockid
+	wrongBuf, _ := proto.Marshal(wrongTx)
+	ledger.confirmedTable.Put(wrongTx.Txid, wrongBuf)
+	report, _ = ledger.Check()
+	if !report.HasIssue(IssueHeightIndex) || !report.HasIssue(IssueTxIndex) || report.LastGoodHeight != 2 {
+		t.Fatalf("expect index issues: %+v", report.Issues)
+	}
+	if err := ledger.RebuildIndexes(); err != nil {
+		t.Fatal(err)
+	}
+	report, _ = ledger.Check()
+	if len(report.Issues) != 0 {
+		t.Fatalf("expect no issue after rebuild: %+v", report.Issues)
+	}
+
+	// 交易缺失时最后完好的区块为前一个区块
+	ledger.confirmedTable.Delete(blocks[1].Transactions[0].Txid)
+	report, _ = ledger.Check()
+	if !report.HasIssue(IssueTxMissing) || !bytes.Equal(report.LastGoodBlockid, blocks[0].Blockid) {
+		t.Fatalf("expect tx missing: %+v", report.Issues)
+	}
+	if err := ledger.Truncate(report.LastGoodBlockid); err != nil {
+		t.Fatal(err)
+	}
+	report, _ = ledger.Check()
+	if len(report.Issues) != 0 || report.LastGoodHeight != 1 {
+		t.Fatalf("expect no issue after truncate: %+v", report.Issues)
+	}
+}
@@ -0,0 +1,236 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/def"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+)
+
+// 裁剪后区块表中的区块头和高度索引保持不变，交易从已确认交易表移到裁剪交易表，
+// 裁剪交易表只保存交易所在的区块以及状态机读取数据需要的字段，完整交易可选地保存到归档存储
+
+var (
+	// ErrBlockPruned is returned when the body of block has been pruned and no archive available
+	ErrBlockPruned = errors.New("block body has been pruned")
+	// ErrTxPruned is returned when the transaction has been pruned and no archive available
+	ErrTxPruned = errors.New("transaction has been pruned")
+)
+
+const (
+	// PrunedHeightKey 已裁剪的最高区块高度
+	PrunedHeightKey = "PrunedHeight"
+)
+
+func (l *Ledger) loadPrunedHeight() error {
+	buf, err := l.metaTable.Get([]byte(PrunedHeightKey))
+	if def.NormalizedKVError(err) == def.ErrKVNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	height, err := strconv.ParseInt(string(buf), 10, 64)
+	if err != nil {
+		return err
+	}
+	atomic.StoreInt64(&l.prunedHeight, height)
+	return nil
+}
+
+// GetPrunedHeight 返回已裁剪的最高区块高度，0表示未裁剪
+func (l *Ledger) GetPrunedHeight() int64 {
+	return atomic.LoadInt64(&l.prunedHeight)
+}
+
+// SchedulePrune 通知后台协程裁剪到不可逆高度，不阻塞区块执行
+func (l *Ledger) SchedulePrune(irreversibleHeight int64) {
+	if !l.ctx.LedgerCfg.Prune.Enable {
+		return
+	}
+	for {
+		target := atomic.LoadInt64(&l.pruneTarget)
+		if irreversibleHeight <= target ||
+			atomic.CompareAndSwapInt64(&l.pruneTarget, target, irreversibleHeight) {
+			break
+		}
+	}
+	select {
+	case l.pruneSignal <- struct{}{}:
+	default:
+	}
+}
+
+func (l *Ledger) pruneWorker() {
+	defer l.pruneWaiter.Done()
+	for {
+		select {
+		case <-l.pruneExit:
+			return
+		case <-l.pruneSignal:
+		}
+		err := l.pruneTo(atomic.LoadInt64(&l.pruneTarget), l.pruneExit)
+		if err != nil {
+			l.xlog.Warn("prune ledger failed", "err", err)
+		}
+	}
+}
+
+// PruneBlocks 裁剪不可逆区块的交易，保留最近KeepBlocks个不可逆区块的完整交易
+// 创世块不会被裁剪，同步执行，区块执行时使用SchedulePrune
+func (l *Ledger) PruneBlocks(irreversibleHeight int64) error {
+	if !l.ctx.LedgerCfg.Prune.Enable {
+		return nil
+	}
+	return l.pruneTo(irreversibleHeight, nil)
+}
+
+// pruneTo 按批裁剪，每批单独提交，exit关闭时在批次之间退出
+func (l *Ledger) pruneTo(irreversibleHeight int64, exit chan struct{}) error {
+	cfg := l.ctx.LedgerCfg.Prune
+	l.pruneMutex.Lock()
+	defer l.pruneMutex.Unlock()
+
+	l.mutex.RLock()
+	trunkHeight := l.meta.TrunkHeight
+	l.mutex.RUnlock()
+	target := irreversibleHeight - cfg.KeepBlocks
+	if target > trunkHeight {
+		target = trunkHeight
+	}
+	batchBlocks := cfg.BatchBlocks
+	if batchBlocks <= 0 {
+		batchBlocks = 1
+	}
+	for from := l.GetPrunedHeight() + 1; from <= target; from += batchBlocks {
+		select {
+		case <-exit:
+			return nil
+		default:
+		}
+		to := from + batchBlocks - 1
+		if to > target {
+			to = target
+		}
+		if err := l.pruneBatch(from, to); err != nil {
+			l.xlog.Warn("prune block failed", "from", from, "to", to, "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneBatch 裁剪[from, to]的区块，读取区块和上传归档时不持有账本锁，
+// 裁剪的区块都已不可逆，不会被并发修改
+func (l *Ledger) pruneBatch(from, to int64) error {
+	batch := l.baseDB.NewBatch()
+	blockids := make([][]byte, 0, to-from+1)
+	txCount := 0
+	for height := from; height <= to; height++ {
+		blockid, err := l.heightTable.Get([]byte(fmt.Sprintf("%020d", height)))
+		if err != nil {
+			return err
+		}
+		block, err := l.queryBlock(blockid, true)
+		if err != nil {
+			return err
+		}
+		for _, tx := range block.Transactions {
+			if l.archive != nil {
+				txBuf, err := proto.Marshal(tx)
+				if err != nil {
+					return err
+				}
+				if err := l.archive.Put(tx.Txid, txBuf); err != nil {
+					return err
+				}
+			}
+			prunedBuf, err := proto.Marshal(PrunedTx(tx))
+			if err != nil {
+				return err
+			}
+			batch.Put(append([]byte(pb.PrunedTxTablePrefix), tx.Txid...), prunedBuf)
+			batch.Delete(append([]byte(pb.ConfirmedTablePrefix), tx.Txid...))
+		}
+		blockids = append(blockids, blockid)
+		txCount += len(block.Transactions)
+	}
+	batch.Put(append([]byte(pb.MetaTablePrefix), []byte(PrunedHeightKey)...),
+		[]byte(strconv.FormatInt(to, 10)))
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	atomic.StoreInt64(&l.prunedHeight, to)
+	for _, blockid := range blockids {
+		l.blockCache.Del(string(blockid))
+	}
+	l.xlog.Debug("prune blocks", "from", from, "to", to, "txCount", txCount)
+	return nil
+}
+
+// PrunedTx 返回交易裁剪后保存的内容，保留交易所在区块以及xmodel读取数据、修订检查需要的字段
+func PrunedTx(tx *pb.Transaction) *pb.Transaction {
+	return &pb.Transaction{
+		Txid:         tx.Txid,
+		Blockid:      tx.Blockid,
+		TxInputsExt:  tx.TxInputsExt,
+		TxOutputsExt: tx.TxOutputsExt,
+		ModifyBlock:  tx.ModifyBlock,
+	}
+}
+
+// getTxBuf 读取完整交易，已裁剪的交易从归档存储读取
+func (l *Ledger) getTxBuf(txid []byte) ([]byte, error) {
+	pbTxBuf, kvErr := l.confirmedTable.Get(txid)
+	if kvErr == nil || def.NormalizedKVError(kvErr) != def.ErrKVNotFound {
+		return pbTxBuf, kvErr
+	}
+	if exist, _ := l.prunedTable.Has(txid); !exist {
+		return nil, kvErr
+	}
+	if l.archive == nil {
+		return nil, ErrTxPruned
+	}
+	return l.archive.Get(txid)
+}
+
+// QueryTransactionState 供状态机读取交易，已裁剪的交易在没有归档时只包含状态相关的字段
+func (l *Ledger) QueryTransactionState(txid []byte) (*pb.Transaction, error) {
+	tx, err := l.QueryTransaction(txid)
+	if err == nil || err == ErrTxNotFound {
+		return tx, err
+	}
+	// 没有归档或归档读取失败时使用裁剪后的交易
+	pbTxBuf, kvErr := l.prunedTable.Get(txid)
+	if kvErr != nil {
+		return nil, err
+	}
+	realTx := &pb.Transaction{}
+	if err := proto.Unmarshal(pbTxBuf, realTx); err != nil {
+		return nil, err
+	}
+	return realTx, nil
+}
+
+// queryTxBlockid 返回交易所在的区块
+func (l *Ledger) queryTxBlockid(txid []byte) ([]byte, error) {
+	pbTxBuf, kvErr := l.confirmedTable.Get(txid)
+	if def.NormalizedKVError(kvErr) == def.ErrKVNotFound {
+		pbTxBuf, kvErr = l.prunedTable.Get(txid)
+	}
+	if kvErr != nil {
+		if def.NormalizedKVError(kvErr) == def.ErrKVNotFound {
+			return nil, ErrTxNotFound
+		}
+		return nil, kvErr
+	}
+	realTx := &pb.Transaction{}
+	if err := proto.Unmarshal(pbTxBuf, realTx); err != nil {
+		return nil, err
+	}
+	return realTx.Blockid, nil
+}
@@ -0,0 +1,160 @@
+package ledger
+
+import (
+	"encoding/hex"
+	"errors"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	cryptoClient "github.com/xuperchain/xupercore/lib/crypto/client"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+// 交易脱敏:
+// 监管地址可以清除已确认交易的desc和合约调用参数，其余字段保持不变。
+// txid和区块的merkle树不变，脱敏后的交易通过监管地址对交易摘要的签名校验。
+
+const (
+	RedactFieldDesc         = "desc"
+	RedactFieldContractArgs = "contract_args"
+)
+
+var (
+	ErrInvalidRedactFields = errors.New("invalid redact fields")
+	ErrTxAlreadyRedacted   = errors.New("tx already redacted")
+	ErrRedactedTxMismatch  = errors.New("redacted tx contains unexpected fields")
+	ErrInvalidRedactSign   = errors.New("invalid redact sign")
+)
+
+// VerifyRedactFields 校验脱敏字段，只允许desc和contract_args且不能重复
+func VerifyRedactFields(fields []string) error {
+	if len(fields) == 0 {
+		return ErrInvalidRedactFields
+	}
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if field != RedactFieldDesc && field != RedactFieldContractArgs {
+			return ErrInvalidRedactFields
+		}
+		if seen[field] {
+			return ErrInvalidRedactFields
+		}
+		seen[field] = true
+	}
+	return nil
+}
+
+// RedactTx 返回清除了指定字段的交易副本，不修改原交易
+func RedactTx(tx *pb.Transaction, fields []string) *pb.Transaction {
+	redacted := proto.Clone(tx).(*pb.Transaction)
+	for _, field := range fields {
+		switch field {
+		case RedactFieldDesc:
+			redacted.Desc = nil
+		case RedactFieldContractArgs:
+			for _, req := range redacted.ContractRequests {
+				req.Args = nil
+			}
+		}
+	}
+	return redacted
+}
+
+// IsRedactedTx 交易是否被监管脱敏
+func IsRedactedTx(tx *pb.Transaction) bool {
+	return tx.GetModifyBlock().GetMarked() && len(tx.GetModifyBlock().GetRedactedFields()) > 0
+}
+
+// VerifyRedactedTx 校验脱敏交易只清除了允许的字段
+func VerifyRedactedTx(tx *pb.Transaction) error {
+	fields := tx.GetModifyBlock().GetRedactedFields()
+	if err := VerifyRedactFields(fields); err != nil {
+		return err
+	}
+	redacted := RedactTx(tx, fields)
+	if !proto.Equal(redacted, tx) {
+		return ErrRedactedTxMismatch
+	}
+	return nil
+}
+
+// VerifyModifySign 校验监管地址的公钥和对交易摘要的签名，交易摘要不包含ModifyBlock
+func VerifyModifySign(modifyBlockAddr, publicKey, sign string, tx *pb.Transaction) error {
+	xcc, err := cryptoClient.CreateCryptoClientFromJSONPublicKey([]byte(publicKey))
+	if err != nil {
+		return err
+	}
+	ecdsaKey, err := xcc.GetEcdsaPublicKeyFromJsonStr(publicKey)
+	if err != nil {
+		return err
+	}
+	isMatch, _ := xcc.VerifyAddressUsingPublicKey(modifyBlockAddr, ecdsaKey)
+	if !isMatch {
+		return errors.New("address and public key not match")
+	}
+	bytesign, err := hex.DecodeString(sign)
+	if err != nil {
+		return errors.New("invalide arg type: sign byte")
+	}
+	digestHash, err := txhash.MakeTxDigestHash(tx)
+	if err != nil {
+		return err
+	}
+	ok, err := xcc.VerifyECDSA(ecdsaKey, bytesign, digestHash)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidRedactSign
+	}
+	return nil
+}
+
+// VerifyRedactedTxSign 校验脱敏交易的字段和ModifyBlock中监管地址的签名，
+// 脱敏交易的内容与txid不再一致，不能通过重新计算txid校验
+func VerifyRedactedTxSign(tx *pb.Transaction, modifyBlockAddr string) error {
+	if err := VerifyRedactedTx(tx); err != nil {
+		return err
+	}
+	modify := tx.GetModifyBlock()
+	return VerifyModifySign(modifyBlockAddr, modify.GetPublicKey(), modify.GetSign(), tx)
+}
+
+// RedactTransaction 按modify中的字段脱敏已确认的交易，txid保持不变
+func (l *Ledger) RedactTransaction(txid []byte, modify *pb.ModifyBlock) error {
+	if err := VerifyRedactFields(modify.GetRedactedFields()); err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	// 已裁剪的交易不在confirmed表中，不支持脱敏
+	if exist, _ := l.prunedTable.Has(txid); exist {
+		return ErrTxPruned
+	}
+	tx, err := l.QueryTransaction(txid)
+	if err != nil {
+		return err
+	}
+	if tx.GetModifyBlock().GetMarked() {
+		return ErrTxAlreadyRedacted
+	}
+	redacted := RedactTx(tx, modify.RedactedFields)
+	redacted.ModifyBlock = modify
+	pbTxBuf, err := proto.Marshal(redacted)
+	if err != nil {
+		l.xlog.Warn("marshal trasaction failed when RedactTransaction", "err", err)
+		return err
+	}
+	if err := l.confirmedTable.Put(txid, pbTxBuf); err != nil {
+		return err
+	}
+	// 缓存中的区块带有脱敏前的交易
+	l.blockCache.Del(string(tx.Blockid))
+	l.xlog.Info("redact transaction success", "txid", utils.F(txid), "fields", modify.RedactedFields,
+		"effectiveTxid", modify.EffectiveTxid)
+	return nil
+}
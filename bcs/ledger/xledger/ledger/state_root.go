@@ -0,0 +1,56 @@
+package ledger
+
+import "errors"
+
+// 状态根:
+// 从分叉高度起区块头携带状态根并参与blockid的计算，分叉之前的区块保持原有格式。
+// 状态根的值由状态机执行区块时校验，账本只校验区块是否按分叉高度携带状态根。
+// 分叉前最后一个区块的状态树需要从创世块重放完整交易得到，
+// 因此到达分叉高度之前不能在没有归档的情况下裁剪区块。
+
+// ErrStateRootPruned 到达状态根分叉高度之前开启了裁剪且没有归档
+var ErrStateRootPruned = errors.New("prune without archive is not allowed before state root fork")
+
+// StateRootConfig 状态根配置
+type StateRootConfig struct {
+	// 从该高度起区块携带状态根，0表示不开启
+	ForkHeight int64 `json:"fork_height"`
+}
+
+// IsStateRootEnabled 高度为height的区块是否携带状态根，创世块不携带
+func (rc *RootConfig) IsStateRootEnabled(height int64) bool {
+	return rc.StateRoot.ForkHeight > 0 && height > 0 && height >= rc.StateRoot.ForkHeight
+}
+
+// IsStateRootForkParent 高度为height的区块是否为分叉前的最后一个区块，其状态根需要从创世块重放得到
+func (rc *RootConfig) IsStateRootForkParent(height int64) bool {
+	return !rc.IsStateRootEnabled(height) && rc.IsStateRootEnabled(height+1)
+}
+
+// IsStateRootEnabledAfter 父区块为preHash的区块是否携带状态根
+func (l *Ledger) IsStateRootEnabledAfter(preHash []byte) (bool, error) {
+	config := l.GenesisBlock.GetConfig()
+	if config.StateRoot.ForkHeight <= 0 || len(preHash) == 0 {
+		return false, nil
+	}
+	parent, err := l.QueryBlockHeader(preHash)
+	if err != nil {
+		return false, err
+	}
+	return config.IsStateRootEnabled(parent.Height + 1), nil
+}
+
+// checkStateRootPrune 到达分叉高度之前，没有归档时不允许开启裁剪或使用已裁剪的账本
+func (l *Ledger) checkStateRootPrune() error {
+	config := l.GenesisBlock.GetConfig()
+	if config.StateRoot.ForkHeight <= 0 || l.meta.GetTrunkHeight() >= config.StateRoot.ForkHeight {
+		return nil
+	}
+	if l.archive != nil {
+		return nil
+	}
+	if l.ctx.LedgerCfg.Prune.Enable || l.GetPrunedHeight() > 0 {
+		return ErrStateRootPruned
+	}
+	return nil
+}
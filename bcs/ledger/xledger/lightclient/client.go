@@ -0,0 +1,300 @@
+// Package lightclient 实现只下载区块头的轻客户端
+//
+// 轻客户端从一个可信的区块(创世块或检查点)开始，逐个校验后续区块头的blockid、矿工签名、
+// 出块人是否符合共识调度以及chained-bft的QC签名，并据此判断区块是否已经不可回滚。
+package lightclient
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/spv"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	cryptoBase "github.com/xuperchain/xupercore/lib/crypto/client/base"
+)
+
+var (
+	ErrParameter        = errors.New("lightclient: invalid parameter")
+	ErrHeaderNotLinked  = errors.New("lightclient: header not linked to tip")
+	ErrInvalidProposer  = errors.New("lightclient: invalid proposer")
+	ErrHeaderNotFound   = errors.New("lightclient: header not found")
+	ErrFinalityConflict = errors.New("lightclient: source conflicts with final block")
+)
+
+// bftCommitDepth chained-bft三阶段提交，区块之后连续三个区块带有合法QC时不可回滚
+const bftCommitDepth = 3
+
+// HeaderSource 区块头来源，通常为对全节点rpc的封装，不要求可信
+type HeaderSource interface {
+	GetTipHeight() (int64, error)
+	GetHeaderByHeight(height int64) (*pb.InternalBlock, error)
+}
+
+// ValidatorSet 验证人集合从Height高度开始生效
+type ValidatorSet struct {
+	Term       int64
+	Height     int64
+	Validators []string
+}
+
+type headerItem struct {
+	header     *pb.InternalBlock
+	validators []string
+}
+
+// LightClient 跟随一条链的区块头
+type LightClient struct {
+	verifier *spv.Verifier
+	election Election
+	source   HeaderSource
+	// 未启用bft时，区块之后需要的确认数
+	confirmations int64
+
+	mutex   sync.RWMutex
+	trusted int64
+	// 已确认的最高高度，只增不减
+	final   int64
+	headers []*headerItem
+	index   map[string]int64
+	history []*ValidatorSet
+}
+
+// NewLightClient 以trusted为起点创建轻客户端，trusted需通过可信渠道获取
+func NewLightClient(crypto cryptoBase.CryptoClient, election Election, source HeaderSource,
+	trusted *pb.InternalBlock, confirmations int64) (*LightClient, error) {
+	if crypto == nil || election == nil || source == nil || trusted == nil {
+		return nil, ErrParameter
+	}
+	// 未启用bft时只能依靠确认数判断区块不可回滚
+	if !election.EnableBFT() && confirmations <= 0 {
+		return nil, ErrParameter
+	}
+	c := &LightClient{
+		verifier:      spv.NewVerifier(crypto),
+		election:      election,
+		source:        source,
+		confirmations: confirmations,
+		trusted:       trusted.Height,
+		final:         trusted.Height,
+		index:         make(map[string]int64),
+	}
+	if err := c.verifier.VerifyHeader(trusted); err != nil {
+		return nil, err
+	}
+	term, validators, err := election.Validators(trusted)
+	if err != nil {
+		return nil, err
+	}
+	c.push(stripHeader(trusted), term, validators)
+	return c, nil
+}
+
+// stripHeader 只保留区块头
+func stripHeader(block *pb.InternalBlock) *pb.InternalBlock {
+	header := *block
+	header.Transactions = nil
+	header.MerkleTree = nil
+	header.FailedTxs = nil
+	return &header
+}
+
+func (c *LightClient) push(header *pb.InternalBlock, term int64, validators []string) {
+	c.headers = append(c.headers, &headerItem{header: header, validators: validators})
+	c.index[string(header.Blockid)] = header.Height
+	if n := len(c.history); n == 0 || c.history[n-1].Term != term ||
+		!stringsEqual(c.history[n-1].Validators, validators) {
+		c.history = append(c.history, &ValidatorSet{
+			Term:       term,
+			Height:     header.Height,
+			Validators: validators,
+		})
+	}
+	depth := c.confirmations
+	if c.election.EnableBFT() {
+		depth = bftCommitDepth
+	}
+	if header.Height-depth > c.final {
+		c.final = header.Height - depth
+	}
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *LightClient) tip() *headerItem {
+	return c.headers[len(c.headers)-1]
+}
+
+func (c *LightClient) item(height int64) *headerItem {
+	i := height - c.trusted
+	if i < 0 || i >= int64(len(c.headers)) {
+		return nil
+	}
+	return c.headers[i]
+}
+
+// Sync 从source同步区块头直到source的最高高度，返回同步后的最高高度
+// source发生分叉时回滚尚未确认的区块头，分叉涉及已确认区块时返回ErrFinalityConflict
+func (c *LightClient) Sync() (int64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	tipHeight, err := c.source.GetTipHeight()
+	if err != nil {
+		return c.tip().header.Height, err
+	}
+	for c.tip().header.Height < tipHeight {
+		header, err := c.source.GetHeaderByHeight(c.tip().header.Height + 1)
+		if err != nil {
+			return c.tip().header.Height, err
+		}
+		if header == nil {
+			return c.tip().header.Height, ErrHeaderNotFound
+		}
+		if !bytes.Equal(header.PreHash, c.tip().header.Blockid) {
+			if err := c.rollback(); err != nil {
+				return c.tip().header.Height, err
+			}
+			continue
+		}
+		if err := c.appendHeader(header); err != nil {
+			return c.tip().header.Height, err
+		}
+	}
+	return c.tip().header.Height, nil
+}
+
+// AppendHeader 校验并追加一个区块头，用于由调用方推送区块头的场景
+func (c *LightClient) AppendHeader(header *pb.InternalBlock) error {
+	if header == nil {
+		return ErrParameter
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.appendHeader(header)
+}
+
+func (c *LightClient) appendHeader(header *pb.InternalBlock) error {
+	parent := c.tip()
+	if header.Height != parent.header.Height+1 || !bytes.Equal(header.PreHash, parent.header.Blockid) {
+		return ErrHeaderNotLinked
+	}
+	if err := c.verifier.VerifyHeader(header); err != nil {
+		return err
+	}
+	term, validators, err := c.election.Validators(header)
+	if err != nil {
+		return err
+	}
+	_, pos, err := c.election.Schedule(header, validators)
+	if err != nil {
+		return err
+	}
+	if validators[pos] != string(header.Proposer) {
+		return ErrInvalidProposer
+	}
+	// 与CheckMinerMatch一致，共识生效后的第一个区块没有justify，由父区块的验证人集合签名
+	if c.election.EnableBFT() && header.Height > c.election.StartHeight() {
+		if err := c.verifier.VerifyQuorumCert(header.Justify, header.PreHash, parent.validators); err != nil {
+			return err
+		}
+	}
+	c.push(stripHeader(header), term, validators)
+	return nil
+}
+
+// rollback 回滚到与source一致的最近区块，不能越过已确认的区块
+func (c *LightClient) rollback() error {
+	for len(c.headers) > 1 {
+		cur := c.tip().header
+		if c.isFinal(cur.Height) {
+			return ErrFinalityConflict
+		}
+		remote, err := c.source.GetHeaderByHeight(cur.Height)
+		if err != nil {
+			return err
+		}
+		if remote != nil && bytes.Equal(remote.Blockid, cur.Blockid) {
+			return nil
+		}
+		c.headers = c.headers[:len(c.headers)-1]
+		delete(c.index, string(cur.Blockid))
+		for n := len(c.history); n > 1 && c.history[n-1].Height >= cur.Height; n-- {
+			c.history = c.history[:n-1]
+		}
+	}
+	return ErrFinalityConflict
+}
+
+// TipHeader 返回已校验的最高区块头
+func (c *LightClient) TipHeader() *pb.InternalBlock {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.tip().header
+}
+
+// GetHeader 返回已校验的区块头
+func (c *LightClient) GetHeader(blockid []byte) (*pb.InternalBlock, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	height, ok := c.index[string(blockid)]
+	if !ok {
+		return nil, ErrHeaderNotFound
+	}
+	return c.item(height).header, nil
+}
+
+// GetHeaderByHeight 返回已校验的区块头
+func (c *LightClient) GetHeaderByHeight(height int64) (*pb.InternalBlock, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	item := c.item(height)
+	if item == nil {
+		return nil, ErrHeaderNotFound
+	}
+	return item.header, nil
+}
+
+// ValidatorHistory 返回同步过程中验证人集合的变更记录
+func (c *LightClient) ValidatorHistory() []*ValidatorSet {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return append([]*ValidatorSet{}, c.history...)
+}
+
+// IsFinal 区块是否已不可回滚
+// 启用chained-bft时需要后续三个区块都带有合法QC，否则需要足够的确认数，
+// 区块一旦确认，即使之后源节点发生回滚也不会再变为未确认
+func (c *LightClient) IsFinal(blockid []byte) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	height, ok := c.index[string(blockid)]
+	if !ok {
+		return false
+	}
+	return c.isFinal(height)
+}
+
+// IsFinalHeight 同IsFinal，按高度查询
+func (c *LightClient) IsFinalHeight(height int64) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if c.item(height) == nil {
+		return false
+	}
+	return c.isFinal(height)
+}
+
+func (c *LightClient) isFinal(height int64) bool {
+	return height <= c.final
+}
@@ -0,0 +1,337 @@
+package lightclient
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/spv"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	cryptoClient "github.com/xuperchain/xupercore/lib/crypto/client"
+	cryptoBase "github.com/xuperchain/xupercore/lib/crypto/client/base"
+)
+
+type testAccount struct {
+	address string
+	pubkey  string
+	key     *ecdsa.PrivateKey
+}
+
+func newAccounts(t *testing.T, crypto cryptoBase.CryptoClient, from, n int) []*testAccount {
+	var accounts []*testAccount
+	for i := from; i < from+n; i++ {
+		key, err := crypto.GenerateKeyBySeed([]byte(fmt.Sprintf("lightclient-test-seed-%024d", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr, _ := crypto.GetAddressFromPublicKey(&key.PublicKey)
+		pubkey, _ := crypto.GetEcdsaPublicKeyJsonFormatStr(key)
+		accounts = append(accounts, &testAccount{address: addr, pubkey: pubkey, key: key})
+	}
+	return accounts
+}
+
+func addresses(accounts []*testAccount) []string {
+	var res []string
+	for _, acc := range accounts {
+		res = append(res, acc.address)
+	}
+	return res
+}
+
+// mockChain 在内存中构造带签名和QC的区块头
+type mockChain struct {
+	t       *testing.T
+	crypto  cryptoBase.CryptoClient
+	headers []*pb.InternalBlock
+	// 共识存储中记录的term，tdpos使用
+	term func(timestamp int64) int64
+}
+
+func newMockChain(t *testing.T, crypto cryptoBase.CryptoClient) *mockChain {
+	genesis := &pb.InternalBlock{Version: 1, Timestamp: 1}
+	genesis.Blockid, _ = ledger.MakeBlockID(genesis)
+	return &mockChain{t: t, crypto: crypto, headers: []*pb.InternalBlock{genesis}}
+}
+
+func (m *mockChain) GetTipHeight() (int64, error) {
+	return int64(len(m.headers) - 1), nil
+}
+
+func (m *mockChain) GetHeaderByHeight(height int64) (*pb.InternalBlock, error) {
+	if height < 0 || height >= int64(len(m.headers)) {
+		return nil, ErrHeaderNotFound
+	}
+	return m.headers[height], nil
+}
+
+// fork 丢弃height及之后的区块
+func (m *mockChain) fork(height int64) {
+	m.headers = m.headers[:height]
+}
+
+func (m *mockChain) makeBlock(proposer *testAccount, timestamp int64, signers []*testAccount) *pb.InternalBlock {
+	pre := m.headers[len(m.headers)-1]
+	block := &pb.InternalBlock{
+		Version:   1,
+		PreHash:   pre.Blockid,
+		Height:    pre.Height + 1,
+		Timestamp: timestamp,
+		Proposer:  []byte(proposer.address),
+		Pubkey:    []byte(proposer.pubkey),
+	}
+	if m.term != nil {
+		block.CurTerm = m.term(timestamp)
+	}
+	if signers != nil {
+		qc := &pb.QuorumCert{ProposalId: pre.Blockid, SignInfos: &pb.QCSignInfos{}}
+		for _, acc := range signers {
+			sign, _ := m.crypto.SignECDSA(acc.key, pre.Blockid)
+			qc.SignInfos.QCSignInfos = append(qc.SignInfos.QCSignInfos, &pb.SignInfo{
+				Address:   acc.address,
+				PublicKey: acc.pubkey,
+				Sign:      sign,
+			})
+		}
+		block.Justify = qc
+	}
+	var err error
+	block.Blockid, err = ledger.MakeBlockID(block)
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	block.Sign, err = m.crypto.SignECDSA(proposer.key, block.Blockid)
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	return block
+}
+
+type mapProvider map[int64][]string
+
+// GetValidators 按高度返回不大于height的最近一次变更
+func (p mapProvider) GetValidators(term int64, height int64) ([]string, error) {
+	var res []string
+	var from int64 = -1
+	for h, v := range p {
+		if h <= height && h > from {
+			from, res = h, v
+		}
+	}
+	return res, nil
+}
+
+func TestXpoaBFT(t *testing.T) {
+	crypto, err := cryptoClient.CreateCryptoClient(cryptoClient.CryptoTypeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	initAccounts := newAccounts(t, crypto, 0, 4)
+	newAccs := newAccounts(t, crypto, 4, 3)
+	config := fmt.Sprintf(`{"period": 3000, "block_num": 2, "init_proposer": {"address": ["%s", "%s", "%s", "%s"]}, "bft_config": {}}`,
+		initAccounts[0].address, initAccounts[1].address, initAccounts[2].address, initAccounts[3].address)
+	// 第7个区块的快照中变更了验证人，从第8个区块开始生效
+	provider := mapProvider{0: addresses(initAccounts), 7: addresses(newAccs)}
+	election, err := NewElection("xpoa", []byte(config), 1, provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain := newMockChain(t, crypto)
+	period := int64(3000 * time.Millisecond)
+	accountsAt := func(height int64) []*testAccount {
+		if height >= 8 {
+			return newAccs
+		}
+		return initAccounts
+	}
+	appendBlock := func(height int64, signers []*testAccount) *pb.InternalBlock {
+		accs := accountsAt(height)
+		// 每个验证人出2个块
+		ts := height * period
+		pos := (height / 2) % int64(len(accs))
+		if height == 1 {
+			signers = nil
+		}
+		block := chain.makeBlock(accs[pos], ts, signers)
+		chain.headers = append(chain.headers, block)
+		return block
+	}
+	for h := int64(1); h <= 12; h++ {
+		appendBlock(h, accountsAt(h - 1)[:3])
+	}
+
+	client, err := NewLightClient(crypto, election, chain, chain.headers[0], 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tip, err := client.Sync()
+	if err != nil || tip != 12 {
+		t.Fatal("sync failed", tip, err)
+	}
+	if !client.IsFinalHeight(9) || client.IsFinalHeight(10) || !client.IsFinal(chain.headers[9].Blockid) {
+		t.Fatal("unexpected finality")
+	}
+	history := client.ValidatorHistory()
+	changed := false
+	for _, set := range history {
+		if set.Height == 8 && set.Validators[0] == newAccs[0].address {
+			changed = true
+		}
+	}
+	if !changed {
+		t.Fatal("validator change not tracked")
+	}
+
+	// 源节点切换到另一条分叉，未确认的区块被替换
+	chain.fork(11)
+	appendBlock(11, newAccs[1:])
+	forkTip := appendBlock(12, newAccs[1:])
+	appendBlock(13, newAccs[1:])
+	if tip, err := client.Sync(); err != nil || tip != 13 {
+		t.Fatal("sync fork failed", tip, err)
+	}
+	if h, _ := client.GetHeaderByHeight(12); string(h.Blockid) != string(forkTip.Blockid) {
+		t.Fatal("client should follow fork")
+	}
+
+	// 验证人签名不足
+	bad := chain.makeBlock(newAccs[(14/2)%3], 14*period, newAccs[:1])
+	if err := client.AppendHeader(bad); err != spv.ErrNoEnoughVotes {
+		t.Fatal("unexpected result for weak qc", err)
+	}
+	// 非调度的出块人
+	bad = chain.makeBlock(newAccs[((14/2)+1)%3], 14*period, newAccs[:3])
+	if err := client.AppendHeader(bad); err != ErrInvalidProposer {
+		t.Fatal("unexpected result for wrong proposer", err)
+	}
+	// 篡改区块头
+	bad = chain.makeBlock(newAccs[(14/2)%3], 14*period, newAccs[:3])
+	bad.Timestamp++
+	if err := client.AppendHeader(bad); err != spv.ErrBlockidMismatch {
+		t.Fatal("unexpected result for tampered header", err)
+	}
+
+	// 分叉越过已确认区块
+	chain.fork(5)
+	for h := int64(5); h <= 14; h++ {
+		appendBlock(h, accountsAt(h - 1)[1:])
+	}
+	if _, err := client.Sync(); err != ErrFinalityConflict {
+		t.Fatal("unexpected result for conflict with final block", err)
+	}
+}
+
+func TestTdposConfirmations(t *testing.T) {
+	crypto, err := cryptoClient.CreateCryptoClient(cryptoClient.CryptoTypeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts := newAccounts(t, crypto, 0, 4)
+	initTime := int64(time.Second)
+	config := fmt.Sprintf(`{"timestamp": "%d", "proposer_num": "2", "period": "1000", "alternate_interval": "1000",
+		"term_interval": "1000", "block_num": "1", "vote_unit_price": "1", "init_proposer": {"1": ["%s", "%s"]}}`,
+		initTime, accounts[0].address, accounts[1].address)
+	// 第二轮开始由投票选出新的验证人，以新一轮第一个区块的父区块快照计算
+	provider := mapProvider{0: addresses(accounts[:2]), 3: addresses(accounts[2:])}
+	election, err := NewElection("tdpos", []byte(config), 1, provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 每轮2000ms，每个验证人出1个块
+	chain := newMockChain(t, crypto)
+	chain.term = func(timestamp int64) int64 {
+		term, _, _ := election.(*tdposElection).minerScheduling(timestamp)
+		return term
+	}
+	for h := int64(1); h <= 8; h++ {
+		k := h + 1
+		accs := accounts[:2]
+		if h >= 4 {
+			accs = accounts[2:]
+		}
+		ts := initTime + k*int64(time.Second) + int64(500*time.Millisecond)
+		chain.headers = append(chain.headers, chain.makeBlock(accs[k%2], ts, nil))
+	}
+
+	client, err := NewLightClient(crypto, election, chain, chain.headers[0], 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tip, err := client.Sync(); err != nil || tip != 8 {
+		t.Fatal("sync failed", tip, err)
+	}
+	if !client.IsFinalHeight(5) || client.IsFinalHeight(6) {
+		t.Fatal("unexpected finality")
+	}
+	history := client.ValidatorHistory()
+	if len(history) < 2 || history[len(history)-1].Validators[0] != accounts[2].address {
+		t.Fatal("validator change not tracked", history)
+	}
+
+	// 时间戳落在轮换间隔中
+	bad := chain.makeBlock(accounts[2], initTime+10*int64(time.Second), nil)
+	if err := client.AppendHeader(bad); err != ErrSchedule {
+		t.Fatal("unexpected result for out of schedule block", err)
+	}
+	// 共识存储中的term与调度不一致
+	bad = chain.makeBlock(accounts[2], initTime+9*int64(time.Second)+int64(500*time.Millisecond), nil)
+	bad.CurTerm++
+	bad.Blockid, _ = ledger.MakeBlockID(bad)
+	bad.Sign, _ = crypto.SignECDSA(accounts[2].key, bad.Blockid)
+	if err := client.AppendHeader(bad); err != ErrConsensusStorage {
+		t.Fatal("unexpected result for wrong term", err)
+	}
+
+	// 未启用bft时必须指定确认数
+	if _, err := NewLightClient(crypto, election, chain, chain.headers[0], 0); err != ErrParameter {
+		t.Fatal("confirmations should be positive", err)
+	}
+	// 无法获取初始验证人之后的验证人集合
+	election, _ = NewElection("tdpos", []byte(config), 1, nil)
+	client, err = NewLightClient(crypto, election, chain, chain.headers[0], 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tip, err := client.Sync(); err != ErrValidatorsUnknown || tip != 3 {
+		t.Fatal("unexpected result without provider", tip, err)
+	}
+}
+
+type recordProvider struct {
+	mapProvider
+	heights []int64
+}
+
+func (p *recordProvider) GetValidators(term int64, height int64) ([]string, error) {
+	p.heights = append(p.heights, height)
+	return p.mapProvider.GetValidators(term, height)
+}
+
+func TestXpoaRollbackTarget(t *testing.T) {
+	crypto, err := cryptoClient.CreateCryptoClient(cryptoClient.CryptoTypeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts := newAccounts(t, crypto, 0, 2)
+	config := fmt.Sprintf(`{"period": 3000, "block_num": 1, "init_proposer": {"address": ["%s", "%s"]}, "bft_config": {}}`,
+		accounts[0].address, accounts[1].address)
+	provider := &recordProvider{mapProvider: mapProvider{0: addresses(accounts)}}
+	election, err := NewElection("xpoa", []byte(config), 1, provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header := &pb.InternalBlock{Height: 10, Timestamp: 10 * int64(3000*time.Millisecond)}
+	if _, _, err := election.Validators(header); err != nil || provider.heights[0] != 9 {
+		t.Fatal("validators should use parent snapshot", provider.heights, err)
+	}
+	// 回滚重做的区块在共识存储中记录了快照高度
+	header.TargetBits = 6
+	if _, _, err := election.Validators(header); err != nil || provider.heights[1] != 6 {
+		t.Fatal("validators should use rollback target", provider.heights, err)
+	}
+}
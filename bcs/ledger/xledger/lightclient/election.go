@@ -0,0 +1,306 @@
+package lightclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/xuperchain/xupercore/bcs/consensus/tdpos"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	common "github.com/xuperchain/xupercore/kernel/consensus/base/common"
+)
+
+var (
+	ErrUnsupportedConsensus = errors.New("lightclient: consensus not supported")
+	ErrConsensusConfig      = errors.New("lightclient: consensus config invalid")
+	ErrSchedule             = errors.New("lightclient: block timestamp out of schedule")
+	ErrEmptyValidators      = errors.New("lightclient: validators is empty")
+	ErrValidatorsUnknown    = errors.New("lightclient: validators can not be established")
+	ErrConsensusStorage     = errors.New("lightclient: consensus storage mismatch schedule")
+)
+
+// ValidatorProvider 提供初始验证人之后的验证人集合
+// 验证人集合由合约状态决定，无法仅从区块头推导，需由可信渠道(如自建全节点)提供，
+// 轻客户端会用区块的出块人和QC对其进行交叉校验
+type ValidatorProvider interface {
+	// GetValidators 返回以height高度的状态快照计算出的验证人集合
+	// height由区块头的共识存储推导，与全节点CheckMinerMatch使用的快照高度一致；
+	// tdpos按term变更验证人，同一term只会查询一次；xpoa按高度查询，term为0
+	GetValidators(term int64, height int64) ([]string, error)
+}
+
+// parseConsensusStorage 区块头中的共识字段经BlockAgent转换为共识存储，xpoa与tdpos的解析方式一致
+func parseConsensusStorage(header *pb.InternalBlock) (*common.ConsensusStorage, error) {
+	in, err := tdpos.ParseConsensusStorage(state.NewBlockAgent(header))
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrConsensusStorage, err)
+	}
+	storage, ok := in.(*common.ConsensusStorage)
+	if !ok {
+		return nil, ErrConsensusStorage
+	}
+	return storage, nil
+}
+
+// Election 共识的出块调度规则，与对应共识的CheckMinerMatch保持一致
+type Election interface {
+	// Schedule 返回区块所在的term以及出块人在验证人集合中的位置
+	Schedule(header *pb.InternalBlock, validators []string) (term int64, pos int64, err error)
+	// Validators 返回区块对应的验证人集合
+	Validators(header *pb.InternalBlock) (term int64, validators []string, err error)
+	// EnableBFT 是否启用了chained-bft
+	EnableBFT() bool
+	// StartHeight 共识生效的高度
+	StartHeight() int64
+}
+
+// NewElection 根据创世块中genesis_consensus的name和config创建调度规则，
+// startHeight为该共识生效的高度，创世共识为1
+func NewElection(name string, config []byte, startHeight int64, provider ValidatorProvider) (Election, error) {
+	switch name {
+	case "tdpos", "xpos":
+		return newTdposElection(config, startHeight, provider)
+	case "xpoa", "poa":
+		return newXpoaElection(config, startHeight, provider)
+	}
+	return nil, ErrUnsupportedConsensus
+}
+
+// tdposElection 与bcs/consensus/tdpos的调度算法一致
+type tdposElection struct {
+	proposerNum       int64
+	period            int64
+	alternateInterval int64
+	termInterval      int64
+	blockNum          int64
+	initTimestamp     int64
+	initValidators    []string
+	enableBFT         bool
+	startHeight       int64
+	provider          ValidatorProvider
+
+	mutex sync.Mutex
+	// term -> validators
+	cache map[int64][]string
+}
+
+func newTdposElection(config []byte, startHeight int64, provider ValidatorProvider) (*tdposElection, error) {
+	// tdpos配置中的数值均为字符串
+	var cfg struct {
+		ProposerNum       string              `json:"proposer_num"`
+		Period            string              `json:"period"`
+		AlternateInterval string              `json:"alternate_interval"`
+		TermInterval      string              `json:"term_interval"`
+		BlockNum          string              `json:"block_num"`
+		Timestamp         string              `json:"timestamp"`
+		InitProposer      map[string][]string `json:"init_proposer"`
+		EnableBFT         map[string]bool     `json:"bft_config,omitempty"`
+	}
+	if err := json.Unmarshal(config, &cfg); err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrConsensusConfig, err)
+	}
+	e := &tdposElection{
+		initValidators: cfg.InitProposer["1"],
+		enableBFT:      cfg.EnableBFT != nil,
+		startHeight:    startHeight,
+		provider:       provider,
+		cache:          make(map[int64][]string),
+	}
+	fields := []struct {
+		value string
+		field *int64
+	}{
+		{cfg.ProposerNum, &e.proposerNum},
+		{cfg.Period, &e.period},
+		{cfg.AlternateInterval, &e.alternateInterval},
+		{cfg.TermInterval, &e.termInterval},
+		{cfg.BlockNum, &e.blockNum},
+		{cfg.Timestamp, &e.initTimestamp},
+	}
+	for _, f := range fields {
+		v, err := strconv.ParseInt(f.value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", ErrConsensusConfig, err)
+		}
+		*f.field = v
+	}
+	if e.proposerNum <= 0 || e.period <= 0 || e.blockNum <= 0 || len(e.initValidators) == 0 {
+		return nil, ErrConsensusConfig
+	}
+	return e, nil
+}
+
+func (e *tdposElection) EnableBFT() bool {
+	return e.enableBFT
+}
+
+func (e *tdposElection) StartHeight() int64 {
+	return e.startHeight
+}
+
+// minerScheduling 同tdposSchedule.minerScheduling
+func (e *tdposElection) minerScheduling(timestamp int64) (term int64, pos int64, blockPos int64) {
+	if timestamp < e.initTimestamp {
+		return
+	}
+	T := timestamp / int64(time.Millisecond)
+	initT := e.initTimestamp / int64(time.Millisecond)
+	termTime := e.termInterval + (e.blockNum-1)*e.proposerNum*e.period + (e.proposerNum-1)*e.alternateInterval
+	term = (T-initT)/termTime + 1
+	termBegin := initT + (term-1)*termTime + e.termInterval - e.alternateInterval
+	if termBegin >= T {
+		return term, 0, -1
+	}
+	posTime := e.alternateInterval + e.period*(e.blockNum-1)
+	pos = (T - termBegin) / posTime
+	proposerBegin := termBegin + pos*posTime + e.alternateInterval - e.period
+	if proposerBegin >= T {
+		return term, pos, -1
+	}
+	blockPos = (T - proposerBegin) / e.period
+	return
+}
+
+func (e *tdposElection) Schedule(header *pb.InternalBlock, validators []string) (int64, int64, error) {
+	term, pos, blockPos := e.minerScheduling(header.Timestamp)
+	if blockPos < 0 || blockPos >= e.blockNum || pos >= e.proposerNum || pos >= int64(len(validators)) {
+		return 0, 0, ErrSchedule
+	}
+	return term, pos, nil
+}
+
+func (e *tdposElection) Validators(header *pb.InternalBlock) (int64, []string, error) {
+	term, _, _ := e.minerScheduling(header.Timestamp)
+	// 同CalOldProposers，前几个区块使用初始验证人
+	if header.Height < e.startHeight+3 {
+		return term, e.initValidators, nil
+	}
+	storage, err := parseConsensusStorage(header)
+	if err != nil {
+		return 0, nil, err
+	}
+	if storage.CurTerm != term {
+		return 0, nil, ErrConsensusStorage
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if v, ok := e.cache[term]; ok {
+		return term, v, nil
+	}
+	if e.provider == nil {
+		return term, nil, ErrValidatorsUnknown
+	}
+	// 同CalOldProposers，新term的验证人由父区块的快照计算，chained-bft回滚重做的区块使用共识存储中记录的高度
+	target := header.Height - 1
+	if e.enableBFT && storage.TargetBits != 0 {
+		target = int64(storage.TargetBits)
+	}
+	v, err := e.provider.GetValidators(term, target)
+	if err != nil {
+		return term, nil, err
+	}
+	if len(v) == 0 {
+		return term, nil, ErrEmptyValidators
+	}
+	e.cache[term] = v
+	return term, v, nil
+}
+
+// xpoaElection 与bcs/consensus/xpoa的调度算法一致
+type xpoaElection struct {
+	period         int64
+	blockNum       int64
+	initValidators []string
+	enableBFT      bool
+	startHeight    int64
+	provider       ValidatorProvider
+}
+
+func newXpoaElection(config []byte, startHeight int64, provider ValidatorProvider) (*xpoaElection, error) {
+	var cfg struct {
+		BlockNum     int64 `json:"block_num"`
+		Period       int64 `json:"period"`
+		InitProposer struct {
+			Address []string `json:"address"`
+		} `json:"init_proposer"`
+		EnableBFT map[string]bool `json:"bft_config,omitempty"`
+	}
+	if err := json.Unmarshal(config, &cfg); err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrConsensusConfig, err)
+	}
+	if cfg.Period <= 0 || cfg.BlockNum <= 0 || len(cfg.InitProposer.Address) == 0 {
+		return nil, ErrConsensusConfig
+	}
+	return &xpoaElection{
+		period:         cfg.Period,
+		blockNum:       cfg.BlockNum,
+		initValidators: cfg.InitProposer.Address,
+		enableBFT:      cfg.EnableBFT != nil,
+		startHeight:    startHeight,
+		provider:       provider,
+	}, nil
+}
+
+func (e *xpoaElection) EnableBFT() bool {
+	return e.enableBFT
+}
+
+func (e *xpoaElection) StartHeight() int64 {
+	return e.startHeight
+}
+
+// minerScheduling 同xpoaSchedule.minerScheduling
+func (e *xpoaElection) minerScheduling(timestamp int64, length int) (term int64, pos int64, blockPos int64) {
+	termTime := e.period * int64(length) * e.blockNum
+	posTime := e.period * e.blockNum
+	term = (timestamp/int64(time.Millisecond))/termTime + 1
+	resTime := timestamp/int64(time.Millisecond) - (term-1)*termTime
+	pos = resTime / posTime
+	resTime = resTime - (resTime/posTime)*posTime
+	blockPos = resTime/e.period + 1
+	return
+}
+
+func (e *xpoaElection) Schedule(header *pb.InternalBlock, validators []string) (int64, int64, error) {
+	if len(validators) == 0 {
+		return 0, 0, ErrEmptyValidators
+	}
+	term, pos, _ := e.minerScheduling(header.Timestamp, len(validators))
+	return term, pos, nil
+}
+
+func (e *xpoaElection) Validators(header *pb.InternalBlock) (int64, []string, error) {
+	// 同GetLocalValidates，前几个区块使用初始验证人
+	if header.Height-1 <= 3 || header.Height < e.startHeight+3 {
+		term, _, _ := e.minerScheduling(header.Timestamp, len(e.initValidators))
+		return term, e.initValidators, nil
+	}
+	if e.provider == nil {
+		return 0, nil, ErrValidatorsUnknown
+	}
+	// 同GetLocalValidates，使用父区块的快照，chained-bft回滚重做的区块使用共识存储中记录的高度
+	target := header.Height - 1
+	if e.enableBFT {
+		storage, err := parseConsensusStorage(header)
+		if err != nil {
+			return 0, nil, err
+		}
+		if storage.TargetBits != 0 {
+			target = int64(storage.TargetBits)
+		}
+	}
+	// xpoa的验证人可随时通过合约变更，term依赖验证人个数，因此不做缓存
+	v, err := e.provider.GetValidators(0, target)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(v) == 0 {
+		return 0, nil, ErrEmptyValidators
+	}
+	term, _, _ := e.minerScheduling(header.Timestamp, len(v))
+	return term, v, nil
+}
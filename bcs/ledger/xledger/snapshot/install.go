@@ -0,0 +1,574 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	lconf "github.com/xuperchain/xupercore/bcs/ledger/xledger/config"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/def"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/spv"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/smt"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/xmodel"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	xconf "github.com/xuperchain/xupercore/kernel/common/xconfig"
+	"github.com/xuperchain/xupercore/lib/cache"
+	cryptoBase "github.com/xuperchain/xupercore/lib/crypto/client/base"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+const (
+	// 安装时单个batch的数据量上限
+	installBatchSize = 4 << 20
+	// 重建状态树时每更新多少个叶子持久化一次树节点
+	treeCommitInterval = 10000
+)
+
+// 安装快照时允许写入的状态机数据表，未确认交易表在快照中为空
+var installTables = []string{
+	pb.MetaTablePrefix,
+	pb.UTXOTablePrefix,
+	pb.ExtUtxoDelTablePrefix,
+	pb.ExtUtxoTablePrefix,
+	pb.NonceTablePrefix,
+	pb.NonceExpireTablePrefix,
+	pb.StorageUsageTablePrefix,
+}
+
+// Install 将本地已下载的快照安装到只有创世块的链上，链需处于关闭状态
+// 先完整校验快照(分片摘要、区块头链及矿工签名、快照区块交易、按快照数据重建的状态根)，通过后再写入账本和状态机。
+// 区块头只校验矿工签名，不校验矿工是否为验证人，调用方需只统计验证人提供的manifest。
+// utxo和xmodel数据由状态根校验，meta、nonce和存储用量记录不在状态根中，调用方需通过SelectManifest确认足够多的验证人提供了相同的快照。
+// 安装后快照区块之前的区块只保留区块头，交易只保留被状态机引用的部分；
+// 写入过程中断时需要删除链数据后重新安装
+func Install(envCfg *xconf.EnvConf, bcName string, store *Store, height int64,
+	crypto cryptoBase.CryptoClient) (*Manifest, error) {
+	if envCfg == nil || bcName == "" || store == nil || crypto == nil {
+		return nil, ErrParameter
+	}
+	m, _, err := store.Manifest(height)
+	if err != nil {
+		return nil, err
+	}
+	if m.BCName != bcName {
+		return nil, fmt.Errorf("%w: bcname %s, expect %s", ErrManifestInvalid, m.BCName, bcName)
+	}
+
+	lcfg, err := lconf.LoadLedgerConf(envCfg.GenConfFilePath(envCfg.LedgerConf))
+	if err != nil {
+		return nil, err
+	}
+	chainDir := filepath.Join(envCfg.GenDataAbsPath(envCfg.ChainDir), bcName)
+	ldb, err := openDB(lcfg, filepath.Join(chainDir, def.LedgerStrgDirName))
+	if err != nil {
+		return nil, err
+	}
+	defer ldb.Close()
+	sdb, err := openDB(lcfg, filepath.Join(chainDir, def.StateStrgDirName))
+	if err != nil {
+		return nil, err
+	}
+	defer sdb.Close()
+
+	in, err := newInstaller(m, store, ldb, sdb, spv.NewVerifier(crypto))
+	if err != nil {
+		return nil, err
+	}
+	if err := in.verify(); err != nil {
+		in.cleanPrunedTxs()
+		return nil, err
+	}
+	if err := in.write(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func openDB(lcfg *lconf.XLedgerConf, path string) (kvdb.Database, error) {
+	return kvdb.CreateKVInstance(&kvdb.KVParameter{
+		DBPath:                path,
+		KVEngineType:          lcfg.KVEngineType,
+		MemCacheSize:          ledger.MemCacheSize,
+		FileHandlersCacheSize: ledger.FileHandlersCacheSize,
+		OtherPaths:            lcfg.OtherPaths,
+		StorageType:           lcfg.StorageType,
+	})
+}
+
+type installer struct {
+	m        *Manifest
+	store    *Store
+	ldb      kvdb.Database
+	sdb      kvdb.Database
+	meta     *pb.LedgerMeta
+	blockid  []byte
+	verifier *spv.Verifier
+
+	// 校验区块头链时下一个应出现的区块，到达创世块后为nil
+	nextBlockid []byte
+	nextHeight  int64
+	tip         *pb.InternalBlock
+	blockTxs    int
+
+	txCache *cache.LRUCache
+	txBatch kvdb.Batch
+	tree    *smt.Tree
+	updates int
+	pointer bool
+	records int64
+}
+
+func newInstaller(m *Manifest, store *Store, ldb, sdb kvdb.Database, verifier *spv.Verifier) (*installer, error) {
+	metaBuf, err := ldb.Get([]byte(pb.MetaTablePrefix))
+	if err != nil {
+		return nil, err
+	}
+	meta := &pb.LedgerMeta{}
+	if err := proto.Unmarshal(metaBuf, meta); err != nil {
+		return nil, err
+	}
+	if meta.TrunkHeight != 0 {
+		return nil, ErrLedgerNotEmpty
+	}
+	if hex.EncodeToString(meta.RootBlockid) != m.GenesisHash {
+		return nil, ErrGenesisMismatch
+	}
+	modifyBlockAddr, err := loadModifyBlockAddr(ldb, meta)
+	if err != nil {
+		return nil, err
+	}
+	verifier.SetModifyBlockAddr(modifyBlockAddr)
+	blockid, _ := hex.DecodeString(m.Blockid)
+	return &installer{
+		m:           m,
+		store:       store,
+		ldb:         ldb,
+		sdb:         sdb,
+		meta:        meta,
+		blockid:     blockid,
+		verifier:    verifier,
+		nextBlockid: blockid,
+		nextHeight:  m.Height,
+		txCache:     cache.NewLRUCache(txCacheSize),
+		txBatch:     ldb.NewBatch(),
+		tree:        smt.NewTree(kvdb.NewTable(sdb, pb.StateTreeTablePrefix), nil),
+	}, nil
+}
+
+// rangeChunks 依次校验并遍历所有分片中的记录
+// loadModifyBlockAddr 从本地创世块读取监管地址，用于校验快照中的脱敏交易
+func loadModifyBlockAddr(ldb kvdb.Database, meta *pb.LedgerMeta) (string, error) {
+	blockBuf, err := ldb.Get(append([]byte(pb.BlocksTablePrefix), meta.RootBlockid...))
+	if err != nil {
+		return "", err
+	}
+	block := &pb.InternalBlock{}
+	if err := proto.Unmarshal(blockBuf, block); err != nil {
+		return "", err
+	}
+	for _, txid := range block.MerkleTree[:block.TxCount] {
+		txBuf, err := ldb.Get(append([]byte(pb.ConfirmedTablePrefix), txid...))
+		if err != nil {
+			return "", err
+		}
+		tx := &pb.Transaction{}
+		if err := proto.Unmarshal(txBuf, tx); err != nil {
+			return "", err
+		}
+		if !tx.Coinbase {
+			continue
+		}
+		gb, err := ledger.NewGenesisBlock(tx.Desc)
+		if err != nil {
+			return "", err
+		}
+		return gb.GetConfig().ModifyBlockAddr, nil
+	}
+	return "", ErrGenesisMismatch
+}
+
+func (in *installer) rangeChunks(f func(kind byte, key, value []byte) error) error {
+	for i := range in.m.Chunks {
+		data, err := in.store.Chunk(in.m.Height, i)
+		if err != nil {
+			return err
+		}
+		if err := in.m.VerifyChunk(i, data); err != nil {
+			return err
+		}
+		if err := rangeRecords(data, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verify 校验快照内容，同时写入裁剪交易表并重建状态树，二者都不影响现有数据
+func (in *installer) verify() error {
+	if err := in.rangeChunks(in.verifyRecord); err != nil {
+		return err
+	}
+	if err := in.txBatch.Write(); err != nil {
+		return err
+	}
+	if err := in.commitTree(); err != nil {
+		return err
+	}
+	if in.nextBlockid != nil || in.tip == nil || in.blockTxs != int(in.tip.TxCount) || !in.pointer {
+		return ErrRecordInvalid
+	}
+	if in.records != in.m.Records {
+		return ErrRecordInvalid
+	}
+	if hex.EncodeToString(in.tree.Root()) != in.m.StateRoot {
+		return ErrStateRootMismatch
+	}
+	return nil
+}
+
+func (in *installer) verifyRecord(kind byte, key, value []byte) error {
+	in.records++
+	if kind == RecordHeader {
+		return in.verifyHeader(key, value)
+	}
+	// 区块头之后才是其他记录
+	if in.nextBlockid != nil {
+		return ErrRecordInvalid
+	}
+	switch kind {
+	case RecordBlockTx:
+		return in.verifyBlockTx(key, value)
+	case RecordTx:
+		return in.putPrunedTx(key, value)
+	case RecordState:
+		return in.verifyState(key, value)
+	}
+	return ErrRecordInvalid
+}
+
+func (in *installer) verifyHeader(key, value []byte) error {
+	if in.nextBlockid == nil {
+		return ErrHeaderChain
+	}
+	header := &pb.InternalBlock{}
+	if err := proto.Unmarshal(value, header); err != nil {
+		return ErrRecordInvalid
+	}
+	if !bytes.Equal(key, in.nextBlockid) || !bytes.Equal(header.Blockid, key) || header.Height != in.nextHeight {
+		return ErrHeaderChain
+	}
+	if err := in.verifier.VerifyHeader(header); err != nil {
+		if err == spv.ErrProposerSign {
+			return ErrHeaderSign
+		}
+		return ErrHeaderChain
+	}
+	if !verifyMerkleTree(header) {
+		return ErrHeaderChain
+	}
+	if in.tip == nil {
+		if len(header.StateRoot) == 0 {
+			return ErrStateRootMissing
+		}
+		if hex.EncodeToString(header.StateRoot) != in.m.StateRoot {
+			return ErrStateRootMismatch
+		}
+		in.tip = header
+	}
+	if header.Height == 0 {
+		if !bytes.Equal(header.Blockid, in.meta.RootBlockid) {
+			return ErrGenesisMismatch
+		}
+		in.nextBlockid = nil
+		return nil
+	}
+	if len(header.PreHash) == 0 {
+		return ErrHeaderChain
+	}
+	in.nextBlockid = header.PreHash
+	in.nextHeight--
+	return nil
+}
+
+// verifyMerkleTree 检查区块头中的merkle树由前TxCount个txid生成
+func verifyMerkleTree(header *pb.InternalBlock) bool {
+	if int(header.TxCount) > len(header.MerkleTree) {
+		return false
+	}
+	txs := make([]*pb.Transaction, header.TxCount)
+	for i := range txs {
+		txs[i] = &pb.Transaction{Txid: header.MerkleTree[i]}
+	}
+	tree := ledger.MakeMerkleTree(txs)
+	if len(tree) != len(header.MerkleTree) {
+		return false
+	}
+	for i := range tree {
+		if !bytes.Equal(tree[i], header.MerkleTree[i]) {
+			return false
+		}
+	}
+	return len(tree) == 0 || bytes.Equal(tree[len(tree)-1], header.MerkleRoot)
+}
+
+func (in *installer) verifyBlockTx(key, value []byte) error {
+	if in.blockTxs >= int(in.tip.TxCount) || !bytes.Equal(key, in.tip.MerkleTree[in.blockTxs]) {
+		return ErrRecordInvalid
+	}
+	tx := &pb.Transaction{}
+	if err := proto.Unmarshal(value, tx); err != nil {
+		return ErrRecordInvalid
+	}
+	// 脱敏交易的内容与txid不一致，由verifier校验监管签名
+	if err := in.verifier.VerifyTx(tx); err != nil || !bytes.Equal(tx.Txid, key) || !bytes.Equal(tx.Blockid, in.blockid) {
+		return ErrRecordInvalid
+	}
+	in.blockTxs++
+	return nil
+}
+
+// putPrunedTx 写入状态机引用的交易，交易内容由引用它的数据和状态根间接校验
+func (in *installer) putPrunedTx(key, value []byte) error {
+	tx := &pb.Transaction{}
+	if err := proto.Unmarshal(value, tx); err != nil || !bytes.Equal(tx.Txid, key) {
+		return ErrRecordInvalid
+	}
+	in.txBatch.Put(append([]byte(pb.PrunedTxTablePrefix), key...), value)
+	in.txCache.Add(string(key), tx)
+	if in.txBatch.ValueSize() >= installBatchSize {
+		if err := in.txBatch.Write(); err != nil {
+			return err
+		}
+		in.txBatch.Reset()
+	}
+	return nil
+}
+
+func (in *installer) queryTx(txid []byte) (*pb.Transaction, error) {
+	if tx, ok := in.txCache.Get(string(txid)); ok {
+		return tx.(*pb.Transaction), nil
+	}
+	// 缓存中没有时交易可能还在batch中
+	if err := in.txBatch.Write(); err != nil {
+		return nil, err
+	}
+	in.txBatch.Reset()
+	buf, err := in.ldb.Get(append([]byte(pb.PrunedTxTablePrefix), txid...))
+	if def.NormalizedKVError(err) == def.ErrKVNotFound {
+		return nil, ErrRecordInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	tx := &pb.Transaction{}
+	if err := proto.Unmarshal(buf, tx); err != nil {
+		return nil, err
+	}
+	in.txCache.Add(string(txid), tx)
+	return tx, nil
+}
+
+func (in *installer) verifyState(key, value []byte) error {
+	switch {
+	case bytes.HasPrefix(key, []byte(pb.ExtUtxoTablePrefix)):
+		out, err := in.versionOutput(key[len(pb.ExtUtxoTablePrefix):], value)
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(out.Value, []byte(xmodel.DelFlag)) {
+			return ErrRecordInvalid
+		}
+		return in.updateTree(key, out.Value)
+	case bytes.HasPrefix(key, []byte(pb.ExtUtxoDelTablePrefix)):
+		out, err := in.versionOutput(key[len(pb.ExtUtxoDelTablePrefix):], value)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(out.Value, []byte(xmodel.DelFlag)) {
+			return ErrRecordInvalid
+		}
+		return nil
+	case bytes.HasPrefix(key, []byte(pb.UTXOTablePrefix)):
+		uItem := &utxo.UtxoItem{}
+		if err := uItem.Loads(value); err != nil {
+			return ErrRecordInvalid
+		}
+		return in.updateTree(key, value)
+	case bytes.HasPrefix(key, []byte(pb.NonceTablePrefix)):
+		// nonce、存储用量和meta记录不在状态根中，只校验格式，内容依赖节点间的manifest共识
+		if _, err := state.ParseNonceKey(key[len(pb.NonceTablePrefix):]); err != nil {
+			return ErrRecordInvalid
+		}
+		if _, err := strconv.ParseInt(string(value), 10, 64); err != nil {
+			return ErrRecordInvalid
+		}
+		return nil
+	case bytes.HasPrefix(key, []byte(pb.NonceExpireTablePrefix)):
+		if _, _, err := state.ParseNonceExpireKey(key[len(pb.NonceExpireTablePrefix):]); err != nil {
+			return ErrRecordInvalid
+		}
+		return nil
+	case bytes.HasPrefix(key, []byte(pb.StorageUsageTablePrefix)):
+		if _, err := strconv.ParseInt(string(value), 10, 64); err != nil {
+			return ErrRecordInvalid
+		}
+		return nil
+	case bytes.HasPrefix(key, []byte(pb.MetaTablePrefix)):
+		if string(key[len(pb.MetaTablePrefix):]) == utxo.LatestBlockKey {
+			if !bytes.Equal(value, in.blockid) {
+				return ErrRecordInvalid
+			}
+			in.pointer = true
+		}
+		return nil
+	}
+	return ErrRecordInvalid
+}
+
+// versionOutput 返回xmodel版本对应的交易输出，并检查输出的key与数据一致
+func (in *installer) versionOutput(rawKey []byte, version []byte) (*protos.TxOutputExt, error) {
+	txid, offset, err := xmodel.ParseVersion(string(version))
+	if err != nil {
+		return nil, ErrRecordInvalid
+	}
+	tx, err := in.queryTx(txid)
+	if err != nil {
+		return nil, err
+	}
+	if offset < 0 || offset >= len(tx.TxOutputsExt) {
+		return nil, ErrRecordInvalid
+	}
+	out := tx.TxOutputsExt[offset]
+	if !bytes.Equal(xmodel.MakeRawKey(out.Bucket, out.Key), rawKey) {
+		return nil, ErrRecordInvalid
+	}
+	return out, nil
+}
+
+func (in *installer) updateTree(key, value []byte) error {
+	if err := in.tree.Update(key, value); err != nil {
+		return err
+	}
+	in.updates++
+	if in.updates%treeCommitInterval == 0 {
+		return in.commitTree()
+	}
+	return nil
+}
+
+// commitTree 持久化状态树节点，节点按内容寻址，校验失败时留下的节点不影响状态机
+func (in *installer) commitTree() error {
+	batch := in.sdb.NewBatch()
+	for h, data := range in.tree.Commit() {
+		batch.Put(append([]byte(pb.StateTreeTablePrefix), h...), data)
+	}
+	return batch.Write()
+}
+
+func (in *installer) cleanPrunedTxs() {
+	clearPrefix(in.ldb, pb.PrunedTxTablePrefix)
+}
+
+// write 替换状态机数据，写入区块头和快照区块的交易，最后更新账本元数据
+func (in *installer) write() error {
+	for _, prefix := range append(installTables, pb.UnconfirmedTablePrefix) {
+		if err := clearPrefix(in.sdb, prefix); err != nil {
+			return err
+		}
+	}
+
+	stateBatch := in.sdb.NewBatch()
+	ledgerBatch := in.ldb.NewBatch()
+	var next []byte
+	err := in.rangeChunks(func(kind byte, key, value []byte) error {
+		switch kind {
+		case RecordHeader:
+			header := &pb.InternalBlock{}
+			if err := proto.Unmarshal(value, header); err != nil {
+				return err
+			}
+			if header.Height == 0 {
+				// 使用本地的创世块
+				buf, err := in.ldb.Get(append([]byte(pb.BlocksTablePrefix), header.Blockid...))
+				if err != nil {
+					return err
+				}
+				header = &pb.InternalBlock{}
+				if err := proto.Unmarshal(buf, header); err != nil {
+					return err
+				}
+			}
+			header.InTrunk = true
+			header.NextHash = next
+			buf, err := proto.Marshal(header)
+			if err != nil {
+				return err
+			}
+			ledgerBatch.Put(append([]byte(pb.BlocksTablePrefix), header.Blockid...), buf)
+			sHeight := []byte(fmt.Sprintf("%020d", header.Height))
+			ledgerBatch.Put(append([]byte(pb.BlockHeightPrefix), sHeight...), header.Blockid)
+			next = header.Blockid
+		case RecordBlockTx:
+			ledgerBatch.Put(append([]byte(pb.ConfirmedTablePrefix), key...), value)
+		case RecordState:
+			stateBatch.Put(key, value)
+		}
+		for _, batch := range []kvdb.Batch{ledgerBatch, stateBatch} {
+			if batch.ValueSize() < installBatchSize {
+				continue
+			}
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := stateBatch.Write(); err != nil {
+		return err
+	}
+
+	// 快照区块成为主干唯一的分支
+	meta := proto.Clone(in.meta).(*pb.LedgerMeta)
+	meta.TipBlockid = in.blockid
+	meta.TrunkHeight = in.m.Height
+	metaBuf, err := proto.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	ledgerBatch.Delete(append([]byte(pb.BranchInfoPrefix), in.meta.RootBlockid...))
+	ledgerBatch.Put(append([]byte(pb.BranchInfoPrefix), in.blockid...), []byte(strconv.FormatInt(in.m.Height, 10)))
+	ledgerBatch.Put(append([]byte(pb.MetaTablePrefix), []byte(ledger.PrunedHeightKey)...),
+		[]byte(strconv.FormatInt(in.m.Height-1, 10)))
+	ledgerBatch.Put([]byte(pb.MetaTablePrefix), metaBuf)
+	return ledgerBatch.Write()
+}
+
+func clearPrefix(db kvdb.Database, prefix string) error {
+	iter := db.NewIteratorWithPrefix([]byte(prefix))
+	defer iter.Release()
+	batch := db.NewBatch()
+	for iter.Next() {
+		batch.Delete(iter.Key())
+		if batch.ValueSize() >= installBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
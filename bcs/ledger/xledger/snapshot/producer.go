@@ -0,0 +1,551 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/golang/protobuf/proto"
+	lconf "github.com/xuperchain/xupercore/bcs/ledger/xledger/config"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/xmodel"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/lib/cache"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+)
+
+const (
+	// 生成快照时交易缓存的大小
+	txCacheSize = 10000
+)
+
+// 快照包含的状态机数据表
+var stateTables = []string{
+	pb.MetaTablePrefix,
+	pb.UnconfirmedTablePrefix,
+	pb.UTXOTablePrefix,
+	pb.ExtUtxoDelTablePrefix,
+	pb.ExtUtxoTablePrefix,
+	pb.NonceTablePrefix,
+	pb.NonceExpireTablePrefix,
+	pb.StorageUsageTablePrefix,
+}
+
+// Producer 在配置的区块间隔生成快照，并对外提供已不可逆的快照
+type Producer struct {
+	bcName string
+	ledger *ledger.Ledger
+	state  *state.State
+	cfg    *lconf.SnapshotConfig
+	store  *Store
+	log    logs.Logger
+
+	wg      sync.WaitGroup
+	running int32
+}
+
+// NewProducer 创建快照生成器，cfg.Interval为0时只提供本地已有的快照
+func NewProducer(bcName string, leg *ledger.Ledger, sta *state.State, cfg *lconf.SnapshotConfig,
+	dir string, log logs.Logger) *Producer {
+	p := &Producer{
+		bcName: bcName,
+		ledger: leg,
+		state:  sta,
+		cfg:    cfg,
+		store:  NewStore(dir),
+		log:    log,
+	}
+	if cfg.Interval > 0 {
+		sta.SetPlayHook(p.onPlay)
+	}
+	return p
+}
+
+// Store 返回本地快照存储
+func (p *Producer) Store() *Store {
+	return p.store
+}
+
+// onPlay 在状态机锁内获取数据库的一致性视图，之后异步写入快照
+func (p *Producer) onPlay(height int64, blockid []byte) {
+	if height <= 0 || height%p.cfg.Interval != 0 {
+		return
+	}
+	// 不带状态根的区块无法校验，不生成快照
+	if !p.ledger.GetGenesisBlock().GetConfig().IsStateRootEnabled(height) {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&p.running, 0, 1) {
+		p.log.Warn("skip snapshot because previous one is running", "height", height)
+		return
+	}
+	c := newCapture(p.state.GetLDB(), height, blockid)
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer atomic.StoreInt32(&p.running, 0)
+		defer c.release()
+
+		manifest, err := dump(p.bcName, p.ledger, c, p.store, p.cfg.ChunkSize)
+		if err != nil {
+			p.log.Warn("create snapshot failed", "height", height, "err", err)
+			return
+		}
+		p.log.Info("create snapshot succ", "height", height, "blockid", manifest.Blockid,
+			"chunks", len(manifest.Chunks), "records", manifest.Records)
+		if err := p.store.Prune(p.cfg.KeepCount); err != nil {
+			p.log.Warn("prune snapshot failed", "err", err)
+		}
+	}()
+}
+
+// Latest 返回本地最新的可对外提供的快照，快照区块需已不可逆且仍在主干上
+func (p *Producer) Latest() (*Manifest, []byte, error) {
+	heights, err := p.store.Heights()
+	if err != nil {
+		return nil, nil, err
+	}
+	irreversible := p.state.GetMeta().GetIrreversibleBlockHeight()
+	for _, height := range heights {
+		if height > irreversible {
+			continue
+		}
+		if m, data, err := p.Get(height); err == nil {
+			return m, data, nil
+		}
+	}
+	return nil, nil, ErrNotFound
+}
+
+// Get 返回指定高度的快照，快照区块不在主干上时返回ErrNotFound
+func (p *Producer) Get(height int64) (*Manifest, []byte, error) {
+	m, data, err := p.store.Manifest(height)
+	if err != nil {
+		return nil, nil, err
+	}
+	blockid, _ := hex.DecodeString(m.Blockid)
+	header, err := p.ledger.QueryBlockHeader(blockid)
+	if err != nil || !header.InTrunk || m.BCName != p.bcName {
+		return nil, nil, ErrNotFound
+	}
+	return m, data, nil
+}
+
+// Close 等待正在生成的快照完成
+func (p *Producer) Close() {
+	p.wg.Wait()
+}
+
+// Create 在状态机当前高度生成快照
+func Create(bcName string, leg *ledger.Ledger, sta *state.State, store *Store, chunkSize int64) (*Manifest, error) {
+	if bcName == "" || leg == nil || sta == nil || store == nil || chunkSize <= 0 {
+		return nil, ErrParameter
+	}
+	var c *capture
+	err := sta.Quiesce(func() error {
+		blockid := sta.GetLatestBlockid()
+		header, err := leg.QueryBlockHeader(blockid)
+		if err != nil {
+			return err
+		}
+		c = newCapture(sta.GetLDB(), header.Height, blockid)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer c.release()
+	return dump(bcName, leg, c, store, chunkSize)
+}
+
+// capture 状态机数据库在某个区块的一致性视图，迭代器创建后不受后续写入影响
+type capture struct {
+	height  int64
+	blockid []byte
+	iters   map[string]kvdb.Iterator
+}
+
+func newCapture(db kvdb.Database, height int64, blockid []byte) *capture {
+	c := &capture{
+		height:  height,
+		blockid: blockid,
+		iters:   make(map[string]kvdb.Iterator),
+	}
+	for _, prefix := range stateTables {
+		c.iters[prefix] = db.NewIteratorWithPrefix([]byte(prefix))
+	}
+	return c
+}
+
+func (c *capture) release() {
+	for _, iter := range c.iters {
+		iter.Release()
+	}
+}
+
+// dumper 将状态机数据转换为只包含已确认交易的结果
+// 状态机数据库中包含未确认交易的修改，需要按未确认交易的输入回退到已确认的版本，
+// 这样不同节点在同一高度生成的快照内容一致
+type dumper struct {
+	ledger      *ledger.Ledger
+	w           *chunkWriter
+	unconfirmed map[string]*pb.Transaction
+	txCache     *cache.LRUCache
+	written     map[string]bool
+}
+
+func dump(bcName string, leg *ledger.Ledger, c *capture, store *Store, chunkSize int64) (*Manifest, error) {
+	header, err := leg.QueryBlockHeader(c.blockid)
+	if err != nil {
+		return nil, err
+	}
+	if len(header.StateRoot) == 0 {
+		return nil, ErrStateRootMissing
+	}
+	w, err := store.NewWriter(c.height)
+	if err != nil {
+		return nil, err
+	}
+	d := &dumper{
+		ledger:      leg,
+		w:           newChunkWriter(w, chunkSize),
+		unconfirmed: make(map[string]*pb.Transaction),
+		txCache:     cache.NewLRUCache(txCacheSize),
+		written:     make(map[string]bool),
+	}
+	steps := []func(*capture) error{
+		d.loadUnconfirmed,
+		d.dumpHeaders,
+		d.dumpBlock,
+		d.dumpMeta,
+		d.dumpUtxo,
+		d.dumpXModel,
+		d.dumpNonces,
+		d.dumpStorageUsage,
+	}
+	for _, step := range steps {
+		if err := step(c); err != nil {
+			w.Abort()
+			return nil, err
+		}
+	}
+	if err := d.w.flush(); err != nil {
+		w.Abort()
+		return nil, err
+	}
+
+	manifest := &Manifest{
+		Version:     FormatVersion,
+		BCName:      bcName,
+		Height:      c.height,
+		Blockid:     hex.EncodeToString(c.blockid),
+		StateRoot:   hex.EncodeToString(header.StateRoot),
+		GenesisHash: hex.EncodeToString(leg.GetMeta().GetRootBlockid()),
+		Records:     d.w.records,
+		Chunks:      w.Chunks(),
+	}
+	if err := w.Commit(manifest); err != nil {
+		w.Abort()
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func (d *dumper) loadUnconfirmed(c *capture) error {
+	iter := c.iters[pb.UnconfirmedTablePrefix]
+	for iter.Next() {
+		tx := &pb.Transaction{}
+		if err := proto.Unmarshal(iter.Value(), tx); err != nil {
+			return err
+		}
+		d.unconfirmed[string(tx.Txid)] = tx
+	}
+	return iter.Error()
+}
+
+// dumpHeaders 从快照区块向前写入到创世块的区块头
+func (d *dumper) dumpHeaders(c *capture) error {
+	blockid := c.blockid
+	for {
+		header, err := d.ledger.QueryBlockHeader(blockid)
+		if err != nil {
+			return err
+		}
+		buf, err := marshalHeader(header)
+		if err != nil {
+			return err
+		}
+		if err := d.w.write(RecordHeader, header.Blockid, buf); err != nil {
+			return err
+		}
+		if len(header.PreHash) == 0 {
+			return nil
+		}
+		blockid = header.PreHash
+	}
+}
+
+// marshalHeader 去掉与本地主干相关的字段后序列化区块头
+func marshalHeader(header *pb.InternalBlock) ([]byte, error) {
+	h := *header
+	h.InTrunk = false
+	h.NextHash = nil
+	h.Transactions = nil
+	return marshalDeterministic(&h)
+}
+
+func marshalDeterministic(msg proto.Message) ([]byte, error) {
+	buf := proto.NewBuffer(nil)
+	buf.SetDeterministic(true)
+	if err := buf.Marshal(msg); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// dumpBlock 写入快照区块的完整交易，安装后该区块可以正常查询
+func (d *dumper) dumpBlock(c *capture) error {
+	block, err := d.ledger.QueryBlock(c.blockid)
+	if err != nil {
+		return err
+	}
+	for _, tx := range block.Transactions {
+		buf, err := marshalDeterministic(tx)
+		if err != nil {
+			return err
+		}
+		if err := d.w.write(RecordBlockTx, tx.Txid, buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *dumper) dumpMeta(c *capture) error {
+	iter := c.iters[pb.MetaTablePrefix]
+	for iter.Next() {
+		if err := d.w.write(RecordState, iter.Key(), iter.Value()); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+// dumpStorageUsage 存储用量只在区块执行时更新，不包含未确认交易的修改
+func (d *dumper) dumpStorageUsage(c *capture) error {
+	iter := c.iters[pb.StorageUsageTablePrefix]
+	for iter.Next() {
+		if err := d.w.write(RecordState, iter.Key(), iter.Value()); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+// dumpUtxo 跳过未确认交易产生的utxo，恢复被未确认交易花费的utxo
+func (d *dumper) dumpUtxo(c *capture) error {
+	iter := c.iters[pb.UTXOTablePrefix]
+	for iter.Next() {
+		if d.isUnconfirmed(utxoTxid(iter.Key())) {
+			continue
+		}
+		if err := d.w.write(RecordState, iter.Key(), iter.Value()); err != nil {
+			return err
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+
+	spent := make(map[string][]byte)
+	for _, tx := range d.unconfirmed {
+		for _, txInput := range tx.TxInputs {
+			if d.isUnconfirmed(txInput.RefTxid) {
+				continue
+			}
+			uItem := &utxo.UtxoItem{
+				Amount:       big.NewInt(0).SetBytes(txInput.Amount),
+				FrozenHeight: txInput.FrozenHeight,
+			}
+			value, err := uItem.Dumps()
+			if err != nil {
+				return err
+			}
+			spent[utxo.GenUtxoKeyWithPrefix(txInput.FromAddr, txInput.RefTxid, txInput.RefOffset)] = value
+		}
+	}
+	keys := make([]string, 0, len(spent))
+	for key := range spent {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := d.w.write(RecordState, []byte(key), spent[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *dumper) isUnconfirmed(txid []byte) bool {
+	_, ok := d.unconfirmed[string(txid)]
+	return ok
+}
+
+// utxoTxid 从U+addr_txid_offset中解析txid
+func utxoTxid(key []byte) []byte {
+	parts := strings.Split(string(key), "_")
+	if len(parts) < 3 {
+		return nil
+	}
+	txid, _ := hex.DecodeString(parts[len(parts)-2])
+	return txid
+}
+
+// dumpNonces 只写入已确认交易的nonce记录
+func (d *dumper) dumpNonces(c *capture) error {
+	iter := c.iters[pb.NonceTablePrefix]
+	for iter.Next() {
+		txid, err := state.ParseNonceKey(iter.Key()[len(pb.NonceTablePrefix):])
+		if err != nil {
+			return err
+		}
+		if d.isUnconfirmed(txid) {
+			continue
+		}
+		if err := d.w.write(RecordState, iter.Key(), iter.Value()); err != nil {
+			return err
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+
+	iter = c.iters[pb.NonceExpireTablePrefix]
+	for iter.Next() {
+		_, nonceKey, err := state.ParseNonceExpireKey(iter.Key()[len(pb.NonceExpireTablePrefix):])
+		if err != nil {
+			return err
+		}
+		txid, _ := state.ParseNonceKey(nonceKey)
+		if d.isUnconfirmed(txid) {
+			continue
+		}
+		if err := d.w.write(RecordState, iter.Key(), iter.Value()); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+// dumpXModel 按key合并数据表和删除表，每个key只保留一条记录
+// 数据表中存在的key在删除表中的记录不会被读取，不同节点上可能不同，因此不写入快照
+func (d *dumper) dumpXModel(c *capture) error {
+	delIter := c.iters[pb.ExtUtxoDelTablePrefix]
+	iter := c.iters[pb.ExtUtxoTablePrefix]
+	delOk, ok := delIter.Next(), iter.Next()
+	for delOk || ok {
+		// 迭代器前进后key和value的内容可能失效，需要先复制
+		var rawKey, version []byte
+		var delKey []byte
+		if delOk {
+			delKey = delIter.Key()[len(pb.ExtUtxoDelTablePrefix):]
+		}
+		if ok && (!delOk || bytes.Compare(iter.Key()[len(pb.ExtUtxoTablePrefix):], delKey) <= 0) {
+			rawKey = append(rawKey, iter.Key()[len(pb.ExtUtxoTablePrefix):]...)
+			version = append(version, iter.Value()...)
+			if delOk && bytes.Equal(rawKey, delKey) {
+				delOk = delIter.Next()
+			}
+			ok = iter.Next()
+		} else {
+			rawKey = append(rawKey, delKey...)
+			version = append(version, delIter.Value()...)
+			delOk = delIter.Next()
+		}
+		if err := d.dumpXModelKey(rawKey, string(version)); err != nil {
+			return err
+		}
+	}
+	if err := delIter.Error(); err != nil {
+		return err
+	}
+	return iter.Error()
+}
+
+func (d *dumper) dumpXModelKey(rawKey []byte, version string) error {
+	version, err := d.confirmedVersion(rawKey, version)
+	if err != nil || version == "" {
+		return err
+	}
+	txid, offset, err := xmodel.ParseVersion(version)
+	if err != nil {
+		return err
+	}
+	tx, err := d.queryTx(txid)
+	if err != nil {
+		return err
+	}
+	if offset >= len(tx.TxOutputsExt) {
+		return ErrChunkCorrupted
+	}
+	// 交易先于引用它的数据写入，安装时可以据此校验数据
+	if !d.written[string(txid)] {
+		buf, err := marshalDeterministic(tx)
+		if err != nil {
+			return err
+		}
+		if err := d.w.write(RecordTx, txid, buf); err != nil {
+			return err
+		}
+		d.written[string(txid)] = true
+	}
+	prefix := pb.ExtUtxoTablePrefix
+	if bytes.Equal(tx.TxOutputsExt[offset].Value, []byte(xmodel.DelFlag)) {
+		prefix = pb.ExtUtxoDelTablePrefix
+	}
+	return d.w.write(RecordState, append([]byte(prefix), rawKey...), []byte(version))
+}
+
+// confirmedVersion 沿未确认交易的输入回退到已确认的版本，返回空表示key只被未确认交易写过
+func (d *dumper) confirmedVersion(rawKey []byte, version string) (string, error) {
+	for version != "" {
+		txid, _, err := xmodel.ParseVersion(version)
+		if err != nil {
+			return "", err
+		}
+		tx, ok := d.unconfirmed[string(txid)]
+		if !ok {
+			return version, nil
+		}
+		version = ""
+		for _, txIn := range tx.TxInputsExt {
+			if bytes.Equal(xmodel.MakeRawKey(txIn.Bucket, txIn.Key), rawKey) {
+				version = xmodel.GetVersionOfTxInput(txIn)
+				break
+			}
+		}
+	}
+	return "", nil
+}
+
+// queryTx 查询已确认交易裁剪后的内容
+func (d *dumper) queryTx(txid []byte) (*pb.Transaction, error) {
+	if tx, ok := d.txCache.Get(string(txid)); ok {
+		return tx.(*pb.Transaction), nil
+	}
+	tx, err := d.ledger.QueryTransactionState(txid)
+	if err != nil {
+		return nil, err
+	}
+	tx = ledger.PrunedTx(tx)
+	d.txCache.Add(string(txid), tx)
+	return tx, nil
+}
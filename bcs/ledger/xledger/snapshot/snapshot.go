@@ -0,0 +1,383 @@
+// Package snapshot 提供状态快照的生成、存储和安装
+//
+// 快照记录某个区块执行后的状态机数据(utxo、xmodel、meta)，从创世块到该区块的区块头、
+// 该区块的完整交易以及状态机引用的交易(裁剪后的内容)。快照按大小切分为多个分片，MANIFEST记录每个分片的摘要，
+// 新节点从其他节点下载快照，校验分片、区块头链以及区块头中的状态根后安装，之后只需同步快照之后的区块。
+//
+// 分片由记录顺序组成，每条记录为 kind uvarint(len(key)) key uvarint(len(value)) value，编码见def.WriteBytes
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/def"
+)
+
+const (
+	// FormatVersion 快照格式版本
+	FormatVersion = 1
+
+	// DirName 快照在链数据目录下的存储目录
+	DirName          = "snapshot"
+	ManifestFileName = "MANIFEST"
+	chunkFilePrefix  = "chunk-"
+	tmpDirSuffix     = ".tmp"
+)
+
+// 记录类型
+const (
+	// RecordHeader 区块头，key为blockid
+	RecordHeader byte = 'H'
+	// RecordBlockTx 快照区块中的完整交易，key为txid
+	RecordBlockTx byte = 'C'
+	// RecordTx 状态机引用的交易，key为txid，value为裁剪后的交易
+	RecordTx byte = 'T'
+	// RecordState 状态机数据，key为状态机数据库中的原始key
+	RecordState byte = 'S'
+)
+
+var (
+	ErrParameter         = errors.New("snapshot parameter error")
+	ErrNotFound          = errors.New("snapshot not found")
+	ErrVersion           = errors.New("unsupported snapshot version")
+	ErrManifestInvalid   = errors.New("snapshot manifest invalid")
+	ErrChunkCorrupted    = errors.New("snapshot chunk corrupted")
+	ErrHeaderChain       = errors.New("snapshot header chain invalid")
+	ErrHeaderSign        = errors.New("snapshot header sign invalid")
+	ErrGenesisMismatch   = errors.New("snapshot genesis block mismatch")
+	ErrStateRootMismatch = errors.New("snapshot state root mismatch")
+	ErrStateRootMissing  = errors.New("snapshot block has no state root")
+	ErrRecordInvalid     = errors.New("snapshot record invalid")
+	ErrLedgerNotEmpty    = errors.New("ledger is not empty")
+	ErrNoQuorum          = errors.New("no snapshot served by enough peers")
+)
+
+// ChunkInfo 分片的校验信息
+type ChunkInfo struct {
+	Size   int64  `json:"size"`
+	Sha256 string `json:"sha256"`
+}
+
+// Manifest 快照描述信息，所有字段由快照内容决定，相同高度的快照在不同节点上一致
+type Manifest struct {
+	Version int    `json:"version"`
+	BCName  string `json:"bcname"`
+	Height  int64  `json:"height"`
+	Blockid string `json:"blockid"`
+	// 快照区块头中的状态根，只对带状态根的区块生成快照
+	StateRoot   string      `json:"state_root"`
+	GenesisHash string      `json:"genesis_hash"`
+	Records     int64       `json:"records"`
+	Chunks      []ChunkInfo `json:"chunks"`
+}
+
+// Hash 返回manifest的摘要，用于在多个节点间比对快照
+func (m *Manifest) Hash() ([]byte, error) {
+	buf, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(buf)
+	return h[:], nil
+}
+
+// ParseManifest 解析并检查manifest
+func ParseManifest(data []byte) (*Manifest, error) {
+	m := &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, ErrManifestInvalid
+	}
+	if m.Version != FormatVersion {
+		return nil, ErrVersion
+	}
+	if m.Height <= 0 || len(m.Chunks) == 0 {
+		return nil, ErrManifestInvalid
+	}
+	for _, id := range []string{m.Blockid, m.GenesisHash, m.StateRoot} {
+		if _, err := hex.DecodeString(id); err != nil {
+			return nil, ErrManifestInvalid
+		}
+	}
+	if m.Blockid == "" || m.GenesisHash == "" || m.StateRoot == "" {
+		return nil, ErrManifestInvalid
+	}
+	return m, nil
+}
+
+// VerifyChunk 校验分片内容与manifest一致
+func (m *Manifest) VerifyChunk(index int, data []byte) error {
+	if index < 0 || index >= len(m.Chunks) {
+		return ErrParameter
+	}
+	h := sha256.Sum256(data)
+	info := m.Chunks[index]
+	if int64(len(data)) != info.Size || hex.EncodeToString(h[:]) != info.Sha256 {
+		return ErrChunkCorrupted
+	}
+	return nil
+}
+
+// Quorum 返回n个节点中至多f=(n-1)/3个节点作恶时需要的一致节点数2f+1
+func Quorum(n int) int {
+	return 2*((n-1)/3) + 1
+}
+
+// SelectManifest 从各节点返回的manifest中选出一致节点数不少于quorum和Quorum(len(manifests))的最高快照
+// meta、nonce和存储用量记录不在状态根中，其内容只由manifest中的分片摘要保证，依赖足够多的验证人提供相同的manifest
+func SelectManifest(manifests [][]byte, quorum int) (*Manifest, error) {
+	if q := Quorum(len(manifests)); quorum < q {
+		quorum = q
+	}
+	type candidate struct {
+		manifest *Manifest
+		votes    int
+	}
+	candidates := make(map[string]*candidate)
+	for _, data := range manifests {
+		m, err := ParseManifest(data)
+		if err != nil {
+			continue
+		}
+		h, err := m.Hash()
+		if err != nil {
+			continue
+		}
+		c, ok := candidates[string(h)]
+		if !ok {
+			c = &candidate{manifest: m}
+			candidates[string(h)] = c
+		}
+		c.votes++
+	}
+
+	var best *candidate
+	for _, c := range candidates {
+		if c.votes < quorum {
+			continue
+		}
+		if best == nil || c.manifest.Height > best.manifest.Height ||
+			(c.manifest.Height == best.manifest.Height && c.votes > best.votes) {
+			best = c
+		}
+	}
+	if best == nil {
+		return nil, ErrNoQuorum
+	}
+	return best.manifest, nil
+}
+
+// Store 管理本地保存的快照，每个快照一个目录，目录名为快照高度
+type Store struct {
+	dir string
+}
+
+// NewStore 打开快照目录
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+func (s *Store) path(height int64) string {
+	return filepath.Join(s.dir, strconv.FormatInt(height, 10))
+}
+
+func chunkFileName(index int) string {
+	return fmt.Sprintf("%s%06d", chunkFilePrefix, index)
+}
+
+// Heights 返回本地完整的快照高度，从高到低排列
+func (s *Store) Heights() ([]int64, error) {
+	infos, err := ioutil.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var heights []int64
+	for _, info := range infos {
+		if !info.IsDir() {
+			continue
+		}
+		height, err := strconv.ParseInt(info.Name(), 10, 64)
+		if err != nil {
+			continue
+		}
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] > heights[j] })
+	return heights, nil
+}
+
+// Manifest 读取快照的manifest，同时返回原始内容
+func (s *Store) Manifest(height int64) (*Manifest, []byte, error) {
+	data, err := ioutil.ReadFile(filepath.Join(s.path(height), ManifestFileName))
+	if os.IsNotExist(err) {
+		return nil, nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	m, err := ParseManifest(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return m, data, nil
+}
+
+// Chunk 读取快照分片
+func (s *Store) Chunk(height int64, index int) ([]byte, error) {
+	if index < 0 {
+		return nil, ErrParameter
+	}
+	data, err := ioutil.ReadFile(filepath.Join(s.path(height), chunkFileName(index)))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+// Remove 删除快照
+func (s *Store) Remove(height int64) error {
+	return os.RemoveAll(s.path(height))
+}
+
+// Prune 只保留最新的keep个快照
+func (s *Store) Prune(keep int) error {
+	heights, err := s.Heights()
+	if err != nil {
+		return err
+	}
+	for i := keep; i < len(heights); i++ {
+		if err := s.Remove(heights[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Writer 在临时目录中写入快照，Commit后才对外可见
+type Writer struct {
+	store  *Store
+	dir    string
+	chunks []ChunkInfo
+}
+
+// NewWriter 创建高度为height的快照写入器，会清理之前未完成的写入
+func (s *Store) NewWriter(height int64) (*Writer, error) {
+	dir := s.path(height) + tmpDirSuffix
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Writer{store: s, dir: dir}, nil
+}
+
+// WriteChunk 写入下一个分片
+func (w *Writer) WriteChunk(data []byte) error {
+	name := filepath.Join(w.dir, chunkFileName(len(w.chunks)))
+	if err := ioutil.WriteFile(name, data, 0644); err != nil {
+		return err
+	}
+	h := sha256.Sum256(data)
+	w.chunks = append(w.chunks, ChunkInfo{Size: int64(len(data)), Sha256: hex.EncodeToString(h[:])})
+	return nil
+}
+
+// Chunks 返回已写入分片的校验信息
+func (w *Writer) Chunks() []ChunkInfo {
+	return w.chunks
+}
+
+// Commit 写入manifest并将快照移动到正式目录
+func (w *Writer) Commit(m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(w.dir, ManifestFileName), data, 0644); err != nil {
+		return err
+	}
+	target := w.store.path(m.Height)
+	if err := os.RemoveAll(target); err != nil {
+		return err
+	}
+	return os.Rename(w.dir, target)
+}
+
+// Abort 放弃写入
+func (w *Writer) Abort() {
+	os.RemoveAll(w.dir)
+}
+
+// chunkWriter 将记录按大小切分成分片
+type chunkWriter struct {
+	w         *Writer
+	chunkSize int64
+	buf       bytes.Buffer
+	records   int64
+}
+
+func newChunkWriter(w *Writer, chunkSize int64) *chunkWriter {
+	return &chunkWriter{w: w, chunkSize: chunkSize}
+}
+
+func (c *chunkWriter) write(kind byte, key, value []byte) error {
+	c.buf.WriteByte(kind)
+	def.WriteBytes(&c.buf, key)
+	def.WriteBytes(&c.buf, value)
+	c.records++
+	if int64(c.buf.Len()) >= c.chunkSize {
+		return c.flush()
+	}
+	return nil
+}
+
+func (c *chunkWriter) flush() error {
+	if c.buf.Len() == 0 {
+		return nil
+	}
+	if err := c.w.WriteChunk(c.buf.Bytes()); err != nil {
+		return err
+	}
+	c.buf.Reset()
+	return nil
+}
+
+// rangeRecords 依次遍历分片中的记录
+func rangeRecords(data []byte, f func(kind byte, key, value []byte) error) error {
+	r := bufio.NewReader(bytes.NewReader(data))
+	for {
+		kind, err := r.ReadByte()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		key, err := def.ReadBytes(r, int64(len(data)))
+		if err != nil {
+			return ErrChunkCorrupted
+		}
+		value, err := def.ReadBytes(r, int64(len(data)))
+		if err != nil {
+			return ErrChunkCorrupted
+		}
+		if err := f(kind, key, value); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,454 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/context"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/xmodel"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/tx"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	xconf "github.com/xuperchain/xupercore/kernel/common/xconfig"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	"github.com/xuperchain/xupercore/lib/crypto/client"
+	cryptoBase "github.com/xuperchain/xupercore/lib/crypto/client/base"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb/memory"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+var genesisConf = []byte(`{
+    "version": "1",
+    "predistribution": [
+        {
+            "address": "TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY",
+            "quota": "100000000000000000000"
+        }
+    ],
+    "maxblocksize": "16",
+    "award": "1000000",
+    "decimals": "8",
+    "award_decay": {
+        "height_gap": 31536000,
+        "ratio": 1
+    },
+    "gas_price": {
+        "cpu_rate": 1000,
+        "mem_rate": 1000000,
+        "disk_rate": 1,
+        "xfee_rate": 1
+    },
+    "new_account_resource_amount": 1000,
+    "modify_block_addr": "fAx3z8P2H4ABEdAfAeahVyUQadecR1Mnn",
+    "state_root": {
+        "fork_height": 1
+    },
+    "genesis_consensus": {
+        "name": "single",
+        "config": {
+            "miner": "TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY",
+            "period": 3000
+        }
+    }
+}`)
+
+func newEnvConf(t *testing.T, chainDir string) *xconf.EnvConf {
+	econf, err := mock.NewMemoryEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	econf.ChainDir = chainDir
+	// 恢复和安装后重新打开账本，需要保留关闭后的内存数据
+	t.Cleanup(memory.Retain(econf.GenDataAbsPath(chainDir)))
+	return econf
+}
+
+func createChain(t *testing.T, econf *xconf.EnvConf) (*ledger.Ledger, *state.State) {
+	lctx, err := ledger.NewLedgerCtx(econf, "xuper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	leg, err := ledger.CreateLedger(lctx, genesisConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootTx, err := tx.GenerateRootTx(genesisConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := leg.FormatRootBlock([]*pb.Transaction{rootTx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := leg.ConfirmBlock(block, true); !status.Succ {
+		t.Fatal("confirm root block fail")
+	}
+	sta := openState(t, econf, leg)
+	if err := sta.Play(block.Blockid); err != nil {
+		t.Fatal(err)
+	}
+	return leg, sta
+}
+
+func openChain(t *testing.T, econf *xconf.EnvConf) (*ledger.Ledger, *state.State) {
+	lctx, err := ledger.NewLedgerCtx(econf, "xuper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	leg, err := ledger.OpenLedger(lctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leg, openState(t, econf, leg)
+}
+
+func newCrypto(t *testing.T) cryptoBase.CryptoClient {
+	crypt, err := client.CreateCryptoClient(client.CryptoTypeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return crypt
+}
+
+func openState(t *testing.T, econf *xconf.EnvConf, leg *ledger.Ledger) *state.State {
+	sctx, err := context.NewStateCtx(econf, "xuper", leg, newCrypto(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sta, err := state.NewState(sctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sta
+}
+
+// kvTx 构造读写xmodel的交易，values中的nil表示删除
+func kvTx(t *testing.T, sta *state.State, nonce string, values map[string][]byte) *pb.Transaction {
+	reader := sta.CreateXMReader()
+	tx := &pb.Transaction{Version: 1, Nonce: nonce, Desc: []byte("kv " + nonce)}
+	for key, value := range values {
+		verData, err := reader.Get("test", []byte(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx.TxInputsExt = append(tx.TxInputsExt, &protos.TxInputExt{
+			Bucket:    "test",
+			Key:       []byte(key),
+			RefTxid:   verData.RefTxid,
+			RefOffset: verData.RefOffset,
+		})
+		if value == nil {
+			value = []byte(xmodel.DelFlag)
+		}
+		tx.TxOutputsExt = append(tx.TxOutputsExt, &protos.TxOutputExt{Bucket: "test", Key: []byte(key), Value: value})
+	}
+	tx.Txid, _ = txhash.MakeTransactionID(tx)
+	if err := sta.DoTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func appendBlock(t *testing.T, leg *ledger.Ledger, sta *state.State, txs ...*pb.Transaction) *pb.InternalBlock {
+	awardTx, err := tx.GenerateAwardTx("miner", "1000", []byte("award"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	crypt := newCrypto(t)
+	key, err := crypt.GenerateKeyBySeed([]byte("snapshot-test-miner-seed-0000000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	miner, err := crypt.GetAddressFromPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := leg.FormatBlock(append(txs, awardTx), []byte(miner), key, 123456789, 0, 0,
+		sta.GetLatestBlockid(), sta.GetTotal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := leg.ConfirmBlock(block, false); !status.Succ {
+		t.Fatal("confirm block fail")
+	}
+	if err := sta.Play(block.Blockid); err != nil {
+		t.Fatal(err)
+	}
+	return block
+}
+
+// redactTx 用创世配置中的监管地址脱敏交易的desc
+func redactTx(t *testing.T, leg *ledger.Ledger, target *pb.Transaction) {
+	crypt := newCrypto(t)
+	key, err := crypt.GenerateKeyBySeed([]byte("snapshot-test-supervisor-seed-00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubkey, err := crypt.GetEcdsaPublicKeyJsonFormatStr(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := []string{ledger.RedactFieldDesc}
+	digest, err := txhash.MakeTxDigestHash(ledger.RedactTx(target, fields))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign, err := crypt.SignECDSA(key, digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modify := &pb.ModifyBlock{
+		Marked:         true,
+		PublicKey:      pubkey,
+		Sign:           hex.EncodeToString(sign),
+		RedactedFields: fields,
+	}
+	if err := leg.RedactTransaction(target.Txid, modify); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func getValue(t *testing.T, sta *state.State, key string) []byte {
+	verData, err := sta.CreateXMReader().Get("test", []byte(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return verData.GetPureData().GetValue()
+}
+
+func TestSelectManifest(t *testing.T) {
+	marshal := func(m *Manifest) []byte {
+		data, _ := json.Marshal(m)
+		return data
+	}
+	newManifest := func(height int64, blockid string) *Manifest {
+		return &Manifest{
+			Version:     FormatVersion,
+			BCName:      "xuper",
+			Height:      height,
+			Blockid:     blockid,
+			GenesisHash: "00",
+			StateRoot:   "00",
+			Chunks:      []ChunkInfo{{Size: 1, Sha256: "00"}},
+		}
+	}
+	low := marshal(newManifest(10, "0a"))
+	high := marshal(newManifest(20, "14"))
+	fork := marshal(newManifest(20, "ff"))
+
+	m, err := SelectManifest([][]byte{low, high, low, fork, low, []byte("bad")}, 2)
+	if err != nil || m.Height != 10 {
+		t.Fatal("expect the highest snapshot with quorum", m, err)
+	}
+	m, err = SelectManifest([][]byte{low, high, high, high}, 2)
+	if err != nil || m.Height != 20 {
+		t.Fatal("expect the highest snapshot", m, err)
+	}
+	if _, err := SelectManifest([][]byte{high, fork}, 2); err != ErrNoQuorum {
+		t.Fatal("expect no quorum", err)
+	}
+	// 配置的quorum低于2f+1时按2f+1计算
+	if _, err := SelectManifest([][]byte{low, high, high, fork}, 2); err != ErrNoQuorum {
+		t.Fatal("expect 2f+1 quorum", err)
+	}
+
+	// 不带状态根的manifest无法校验
+	noRoot := newManifest(20, "14")
+	noRoot.StateRoot = ""
+	if _, err := ParseManifest(marshal(noRoot)); err != ErrManifestInvalid {
+		t.Fatal("expect manifest without state root rejected", err)
+	}
+}
+
+func TestCreateAndInstall(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store := NewStore(dir)
+
+	srcConf := newEnvConf(t, "/memory/snapshot_test/src")
+	leg, sta := createChain(t, srcConf)
+	defer leg.Close()
+	defer sta.Close()
+
+	tx1 := kvTx(t, sta, "1", map[string][]byte{"a": []byte("1"), "b": []byte("2")})
+	appendBlock(t, leg, sta, tx1)
+	tx2 := kvTx(t, sta, "2", map[string][]byte{"a": []byte("3"), "b": nil})
+	tip := appendBlock(t, leg, sta, tx2)
+	redactTx(t, leg, tx2)
+	// 未确认交易不会进入快照
+	kvTx(t, sta, "3", map[string][]byte{"a": []byte("5"), "c": []byte("9")})
+
+	m, err := Create("xuper", leg, sta, store, 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Height != 2 || m.Blockid != hex.EncodeToString(tip.Blockid) || m.StateRoot == "" || len(m.Chunks) < 2 {
+		t.Fatalf("unexpected manifest: %+v", m)
+	}
+	if heights, _ := store.Heights(); len(heights) != 1 || heights[0] != 2 {
+		t.Fatal("unexpected snapshot heights", heights)
+	}
+
+	// 不同链名或非空账本拒绝安装
+	dstConf := newEnvConf(t, "/memory/snapshot_test/dst")
+	dstLeg, dstSta := createChain(t, dstConf)
+	dstLeg.Close()
+	dstSta.Close()
+	if _, err := Install(dstConf, "other", store, m.Height, newCrypto(t)); err == nil {
+		t.Fatal("expect error when bcname mismatch")
+	}
+	if _, err := Install(srcConf, "xuper", store, m.Height, newCrypto(t)); err != ErrLedgerNotEmpty {
+		t.Fatal("expect ledger not empty", err)
+	}
+
+	if _, err := Install(dstConf, "xuper", store, m.Height, newCrypto(t)); err != nil {
+		t.Fatal(err)
+	}
+	dstLeg, dstSta = openChain(t, dstConf)
+	defer dstLeg.Close()
+	defer dstSta.Close()
+
+	if !bytes.Equal(dstLeg.GetMeta().TipBlockid, tip.Blockid) || !bytes.Equal(dstSta.GetLatestBlockid(), tip.Blockid) {
+		t.Fatal("unexpected tip after install")
+	}
+	root, err := dstSta.GetStateRoot()
+	if err != nil || !bytes.Equal(root, tip.StateRoot) {
+		t.Fatal("unexpected state root after install", err)
+	}
+	if string(getValue(t, dstSta, "a")) != "3" || string(getValue(t, dstSta, "b")) != xmodel.DelFlag ||
+		getValue(t, dstSta, "c") != nil {
+		t.Fatal("unexpected xmodel data after install")
+	}
+	if _, err := dstLeg.QueryBlock(tip.Blockid); err != nil {
+		t.Fatal("snapshot block should be queryable", err)
+	}
+	if redacted, err := dstLeg.QueryTransaction(tx2.Txid); err != nil || !ledger.IsRedactedTx(redacted) {
+		t.Fatal("redacted tx should be installed", err)
+	}
+	if _, err := dstLeg.QueryBlock(tip.PreHash); err != ledger.ErrBlockPruned {
+		t.Fatal("expect block pruned", err)
+	}
+	if _, err := dstLeg.QueryBlockByHeight(0); err != nil {
+		t.Fatal("genesis block should be queryable", err)
+	}
+
+	// 安装后可以继续执行区块
+	tx4 := kvTx(t, dstSta, "4", map[string][]byte{"a": []byte("7")})
+	block := appendBlock(t, dstLeg, dstSta, tx4)
+	if len(block.StateRoot) == 0 || string(getValue(t, dstSta, "a")) != "7" {
+		t.Fatal("unexpected state after play new block")
+	}
+}
+
+func TestInstallCorrupted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store := NewStore(dir)
+
+	srcConf := newEnvConf(t, "/memory/snapshot_test/corrupted")
+	leg, sta := createChain(t, srcConf)
+	defer leg.Close()
+	defer sta.Close()
+	appendBlock(t, leg, sta, kvTx(t, sta, "1", map[string][]byte{"a": []byte("1")}))
+	m, err := Create("xuper", leg, sta, store, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := store.Chunk(m.Height, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 篡改分片内容
+	dstConf := newEnvConf(t, "/memory/snapshot_test/corrupted_dst")
+	dstLeg, dstSta := createChain(t, dstConf)
+	dstLeg.Close()
+	dstSta.Close()
+	bad := append([]byte{}, data...)
+	bad[len(bad)-1] ^= 0xff
+	chunkPath := filepath.Join(dir, strconv.FormatInt(m.Height, 10), chunkFileName(0))
+	if err := ioutil.WriteFile(chunkPath, bad, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Install(dstConf, "xuper", store, m.Height, newCrypto(t)); err != ErrChunkCorrupted {
+		t.Fatal("expect chunk corrupted", err)
+	}
+
+	// 篡改状态数据并重新生成manifest，状态根校验失败
+	w, err := store.NewWriter(m.Height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cw := newChunkWriter(w, 1<<20)
+	err = rangeRecords(data, func(kind byte, key, value []byte) error {
+		if kind == RecordState && bytes.HasPrefix(key, []byte(pb.UTXOTablePrefix)) {
+			value = []byte(`{"Amount":1,"FrozenHeight":0}`)
+		}
+		return cw.write(kind, key, value)
+	})
+	if err != nil || cw.flush() != nil {
+		t.Fatal("rewrite snapshot failed", err)
+	}
+	fake := *m
+	fake.Chunks = w.Chunks()
+	if err := w.Commit(&fake); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Install(dstConf, "xuper", store, m.Height, newCrypto(t)); err != ErrStateRootMismatch {
+		t.Fatal("expect state root mismatch", err)
+	}
+
+	// 篡改区块头的矿工签名，blockid不变但签名校验失败
+	w, err = store.NewWriter(m.Height)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cw = newChunkWriter(w, 1<<20)
+	err = rangeRecords(data, func(kind byte, key, value []byte) error {
+		if kind == RecordHeader {
+			header := &pb.InternalBlock{}
+			if err := proto.Unmarshal(value, header); err != nil {
+				return err
+			}
+			if len(header.Sign) > 0 {
+				header.Sign[len(header.Sign)-1] ^= 0xff
+				value, _ = proto.Marshal(header)
+			}
+		}
+		return cw.write(kind, key, value)
+	})
+	if err != nil || cw.flush() != nil {
+		t.Fatal("rewrite snapshot failed", err)
+	}
+	fake = *m
+	fake.Chunks = w.Chunks()
+	if err := w.Commit(&fake); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Install(dstConf, "xuper", store, m.Height, newCrypto(t)); err != ErrHeaderSign {
+		t.Fatal("expect header sign invalid", err)
+	}
+
+	// 校验失败后账本保持不变
+	dstLeg, dstSta = openChain(t, dstConf)
+	defer dstLeg.Close()
+	defer dstSta.Close()
+	if dstLeg.GetMeta().TrunkHeight != 0 {
+		t.Fatal("ledger should not be changed")
+	}
+}
@@ -0,0 +1,162 @@
+// Package spv 为不运行全节点的客户端提供区块头和交易的校验
+//
+// 客户端需要通过可信渠道获取创世块id以及各区块对应的验证人集合，
+// 在此基础上可以只依赖区块头校验交易是否已被打包上链。
+package spv
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	cryptoBase "github.com/xuperchain/xupercore/lib/crypto/client/base"
+)
+
+var (
+	ErrParameter       = errors.New("spv: invalid parameter")
+	ErrTxidMismatch    = errors.New("spv: txid not match tx content")
+	ErrRedactedTx      = errors.New("spv: redacted tx invalid")
+	ErrMerkleProof     = errors.New("spv: merkle proof invalid")
+	ErrBlockidMismatch = errors.New("spv: blockid not match block header")
+	ErrProposerSign    = errors.New("spv: proposer sign invalid")
+	ErrEmptyQC         = errors.New("spv: quorum cert is empty")
+	ErrQCProposal      = errors.New("spv: quorum cert not for expected block")
+	ErrQCSign          = errors.New("spv: quorum cert sign invalid")
+	ErrNoEnoughVotes   = errors.New("spv: quorum cert votes not enough")
+)
+
+// Verifier 校验区块头、交易merkle证明和QC
+type Verifier struct {
+	crypto cryptoBase.CryptoClient
+	// 创世配置中的监管地址，用于校验脱敏交易
+	modifyBlockAddr string
+}
+
+// NewVerifier 使用与链一致的密码学插件创建校验器
+func NewVerifier(crypto cryptoBase.CryptoClient) *Verifier {
+	return &Verifier{
+		crypto: crypto,
+	}
+}
+
+// SetModifyBlockAddr 设置创世配置中的监管地址，未设置时脱敏交易均校验失败
+func (v *Verifier) SetModifyBlockAddr(addr string) {
+	v.modifyBlockAddr = addr
+}
+
+// VerifyHeader 校验区块头的blockid和矿工签名，创世块没有签名，只校验blockid
+func (v *Verifier) VerifyHeader(header *pb.InternalBlock) error {
+	if header == nil {
+		return ErrParameter
+	}
+	blkid, err := ledger.MakeBlockID(header)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(blkid, header.Blockid) {
+		return ErrBlockidMismatch
+	}
+	if len(header.PreHash) == 0 {
+		return nil
+	}
+
+	pk, err := v.crypto.GetEcdsaPublicKeyFromJsonStr(string(header.Pubkey))
+	if err != nil {
+		return ErrProposerSign
+	}
+	if ok, _ := v.crypto.VerifyAddressUsingPublicKey(string(header.Proposer), pk); !ok {
+		return ErrProposerSign
+	}
+	valid, err := v.crypto.VerifyECDSA(pk, header.Sign, header.Blockid)
+	if err != nil || !valid {
+		return ErrProposerSign
+	}
+	return nil
+}
+
+// VerifyTxProof 校验交易内容、交易的merkle路径以及所在的区块头
+func (v *Verifier) VerifyTxProof(proof *pb.TxMerkleProof) error {
+	if proof == nil || proof.Tx == nil || proof.Header == nil {
+		return ErrParameter
+	}
+	if err := v.VerifyTx(proof.Tx); err != nil {
+		return err
+	}
+	header := proof.Header
+	if !ledger.VerifyMerkleProof(header.MerkleRoot, int(header.TxCount), proof.Tx.Txid,
+		int(proof.Index), proof.Branch) {
+		return ErrMerkleProof
+	}
+	return v.VerifyHeader(header)
+}
+
+// VerifyTx 校验交易内容与txid一致，脱敏交易的内容与txid不一致，改为校验脱敏字段和监管签名
+func (v *Verifier) VerifyTx(tx *pb.Transaction) error {
+	if tx == nil {
+		return ErrParameter
+	}
+	if ledger.IsRedactedTx(tx) {
+		if err := ledger.VerifyRedactedTxSign(tx, v.modifyBlockAddr); err != nil {
+			return ErrRedactedTx
+		}
+		return nil
+	}
+	txid, err := txhash.MakeTransactionID(tx)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(txid, tx.Txid) {
+		return ErrTxidMismatch
+	}
+	return nil
+}
+
+// VerifyQuorumCert 校验QC是否由validators中足够多的节点对proposalId签名
+// validators为被认证区块对应的验证人集合，票数阈值与chained-bft一致
+func (v *Verifier) VerifyQuorumCert(qc *pb.QuorumCert, proposalId []byte, validators []string) error {
+	if qc == nil || qc.SignInfos == nil || len(validators) == 0 {
+		return ErrEmptyQC
+	}
+	if !bytes.Equal(qc.ProposalId, proposalId) {
+		return ErrQCProposal
+	}
+
+	isValidator := make(map[string]bool, len(validators))
+	for _, addr := range validators {
+		isValidator[addr] = true
+	}
+	voted := make(map[string]bool)
+	for _, sign := range qc.SignInfos.QCSignInfos {
+		if !isValidator[sign.Address] || voted[sign.Address] {
+			continue
+		}
+		pk, err := v.crypto.GetEcdsaPublicKeyFromJsonStr(sign.PublicKey)
+		if err != nil {
+			return ErrQCSign
+		}
+		addr, err := v.crypto.GetAddressFromPublicKey(pk)
+		if err != nil || addr != sign.Address {
+			return ErrQCSign
+		}
+		valid, err := v.crypto.VerifyECDSA(pk, sign.Sign, qc.ProposalId)
+		if err != nil || !valid {
+			return ErrQCSign
+		}
+		voted[sign.Address] = true
+	}
+	if !votesEnough(len(voted), len(validators)) {
+		return ErrNoEnoughVotes
+	}
+	return nil
+}
+
+// votesEnough 与chained-bft的CalVotesThreshold保持一致，input+1表示计入提案者自身
+func votesEnough(input, sum int) bool {
+	f := (sum - 1) / 3
+	if f == 0 {
+		return input+1 >= sum
+	}
+	return input+1 >= sum-f
+}
@@ -0,0 +1,236 @@
+package spv
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	cryptoClient "github.com/xuperchain/xupercore/lib/crypto/client"
+	cryptoBase "github.com/xuperchain/xupercore/lib/crypto/client/base"
+	"github.com/xuperchain/xupercore/lib/logs"
+	_ "github.com/xuperchain/xupercore/lib/storage/kvdb/memory"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+var genesisConf = []byte(`{
+    "version": "1",
+    "predistribution": [],
+    "maxblocksize": "16",
+    "award": "1000000",
+    "decimals": "8",
+    "award_decay": {"height_gap": 31536000, "ratio": 1},
+    "gas_price": {"cpu_rate": 1000, "mem_rate": 1000000, "disk_rate": 1, "xfee_rate": 1},
+    "new_account_resource_amount": 1000,
+    "genesis_consensus": {"name": "single", "config": {"miner": "TeyyPLpp9L7QAcxHangtcHTu7HUZ6iydY", "period": 3000}}
+}`)
+
+type testAccount struct {
+	address string
+	pubkey  string
+	key     *ecdsa.PrivateKey
+}
+
+func newAccount(t *testing.T, crypto cryptoBase.CryptoClient, seed int) *testAccount {
+	key, err := crypto.GenerateKeyBySeed([]byte(fmt.Sprintf("spv-test-seed-%032d", seed)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := crypto.GetAddressFromPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubkey, err := crypto.GetEcdsaPublicKeyJsonFormatStr(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testAccount{address: addr, pubkey: pubkey, key: key}
+}
+
+func newTestLedger(t *testing.T) *ledger.Ledger {
+	econf, err := mock.NewMemoryEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs.InitLog(econf.GenConfFilePath(econf.LogConf), econf.GenDirAbsPath(econf.LogDir))
+	lctx, err := ledger.NewLedgerCtx(econf, "xuper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	leg, err := ledger.CreateLedger(lctx, genesisConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leg
+}
+
+func TestVerifyTxProof(t *testing.T) {
+	crypto, err := cryptoClient.CreateCryptoClient(cryptoClient.CryptoTypeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	miner := newAccount(t, crypto, 0)
+	leg := newTestLedger(t)
+	defer leg.Close()
+
+	rootTx := &pb.Transaction{Coinbase: true, Desc: []byte(`{"maxblocksize" : "128"}`)}
+	rootTx.TxOutputs = append(rootTx.TxOutputs, &protos.TxOutput{Amount: []byte("888"), ToAddr: []byte(miner.address)})
+	rootTx.Txid, _ = txhash.MakeTransactionID(rootTx)
+	rootBlock, err := leg.FormatRootBlock([]*pb.Transaction{rootTx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := leg.ConfirmBlock(rootBlock, true); !status.Succ {
+		t.Fatal("confirm root block failed", status.Error)
+	}
+
+	var txList []*pb.Transaction
+	for i := 0; i < 5; i++ {
+		tx := &pb.Transaction{Desc: []byte(fmt.Sprintf("tx-%d", i)), Nonce: fmt.Sprint(i)}
+		tx.Txid, _ = txhash.MakeTransactionID(tx)
+		txList = append(txList, tx)
+	}
+	block, err := leg.FormatBlock(txList, []byte(miner.address), miner.key, 223456789, 0, 0,
+		rootBlock.Blockid, big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := leg.ConfirmBlock(block, false); !status.Succ {
+		t.Fatal("confirm block failed", status.Error)
+	}
+
+	verifier := NewVerifier(crypto)
+	for _, tx := range append(txList, rootTx) {
+		proof, err := leg.QueryTxMerkleProof(tx.Txid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(proof.Header.MerkleTree) != 0 {
+			t.Fatal("proof header should not carry merkle tree")
+		}
+		if err := verifier.VerifyTxProof(proof); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	proof, _ := leg.QueryTxMerkleProof(txList[2].Txid)
+	fake := proto.Clone(proof).(*pb.TxMerkleProof)
+	fake.Tx.Desc = []byte("fake")
+	if err := verifier.VerifyTxProof(fake); err != ErrTxidMismatch {
+		t.Fatal("unexpected result for tampered tx", err)
+	}
+	fake = proto.Clone(proof).(*pb.TxMerkleProof)
+	fake.Index = 3
+	if err := verifier.VerifyTxProof(fake); err != ErrMerkleProof {
+		t.Fatal("unexpected result for wrong index", err)
+	}
+	fake = proto.Clone(proof).(*pb.TxMerkleProof)
+	fake.Header.Timestamp++
+	if err := verifier.VerifyTxProof(fake); err != ErrBlockidMismatch {
+		t.Fatal("unexpected result for tampered header", err)
+	}
+	fake = proto.Clone(proof).(*pb.TxMerkleProof)
+	other := newAccount(t, crypto, 1)
+	fake.Header.Sign, _ = crypto.SignECDSA(other.key, fake.Header.Blockid)
+	if err := verifier.VerifyTxProof(fake); err != ErrProposerSign {
+		t.Fatal("unexpected result for wrong sign", err)
+	}
+
+	// 脱敏交易校验监管签名
+	supervisor := newAccount(t, crypto, 2)
+	fields := []string{ledger.RedactFieldDesc}
+	digest, _ := txhash.MakeTxDigestHash(ledger.RedactTx(txList[1], fields))
+	sign, _ := crypto.SignECDSA(supervisor.key, digest)
+	modify := &pb.ModifyBlock{
+		Marked:         true,
+		PublicKey:      supervisor.pubkey,
+		Sign:           hex.EncodeToString(sign),
+		RedactedFields: fields,
+	}
+	if err := leg.RedactTransaction(txList[1].Txid, modify); err != nil {
+		t.Fatal(err)
+	}
+	proof, err = leg.QueryTxMerkleProof(txList[1].Txid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verifier.VerifyTxProof(proof); err != ErrRedactedTx {
+		t.Fatal("redacted tx should fail without modify block addr", err)
+	}
+	verifier.SetModifyBlockAddr(supervisor.address)
+	if err := verifier.VerifyTxProof(proof); err != nil {
+		t.Fatal(err)
+	}
+	fake = proto.Clone(proof).(*pb.TxMerkleProof)
+	fake.Tx.Nonce = "fake"
+	if err := verifier.VerifyTxProof(fake); err != ErrRedactedTx {
+		t.Fatal("unexpected result for tampered redacted tx", err)
+	}
+	verifier.SetModifyBlockAddr(other.address)
+	if err := verifier.VerifyTxProof(proof); err != ErrRedactedTx {
+		t.Fatal("unexpected result for wrong supervisor", err)
+	}
+}
+
+func TestVerifyQuorumCert(t *testing.T) {
+	crypto, err := cryptoClient.CreateCryptoClient(cryptoClient.CryptoTypeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var accounts []*testAccount
+	var validators []string
+	for i := 0; i < 4; i++ {
+		acc := newAccount(t, crypto, i)
+		accounts = append(accounts, acc)
+		validators = append(validators, acc.address)
+	}
+	proposalId := []byte("proposal")
+	makeQC := func(signers []*testAccount) *pb.QuorumCert {
+		qc := &pb.QuorumCert{ProposalId: proposalId, SignInfos: &pb.QCSignInfos{}}
+		for _, acc := range signers {
+			sign, err := crypto.SignECDSA(acc.key, proposalId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			qc.SignInfos.QCSignInfos = append(qc.SignInfos.QCSignInfos, &pb.SignInfo{
+				Address:   acc.address,
+				PublicKey: acc.pubkey,
+				Sign:      sign,
+			})
+		}
+		return qc
+	}
+
+	verifier := NewVerifier(crypto)
+	if err := verifier.VerifyQuorumCert(makeQC(accounts[:3]), proposalId, validators); err != nil {
+		t.Fatal(err)
+	}
+	if err := verifier.VerifyQuorumCert(makeQC(accounts[:3]), []byte("other"), validators); err != ErrQCProposal {
+		t.Fatal("unexpected result for other proposal", err)
+	}
+	// 重复签名只计一次
+	if err := verifier.VerifyQuorumCert(makeQC([]*testAccount{accounts[0], accounts[0], accounts[0]}),
+		proposalId, validators); err != ErrNoEnoughVotes {
+		t.Fatal("unexpected result for duplicated votes", err)
+	}
+	outsider := newAccount(t, crypto, 9)
+	if err := verifier.VerifyQuorumCert(makeQC([]*testAccount{accounts[0], outsider}),
+		proposalId, validators); err != ErrNoEnoughVotes {
+		t.Fatal("unexpected result for outsider votes", err)
+	}
+	qc := makeQC(accounts[:3])
+	qc.SignInfos.QCSignInfos[1].Sign = qc.SignInfos.QCSignInfos[0].Sign
+	if err := verifier.VerifyQuorumCert(qc, proposalId, validators); err != ErrQCSign {
+		t.Fatal("unexpected result for bad sign", err)
+	}
+	if err := verifier.VerifyQuorumCert(nil, proposalId, validators); err != ErrEmptyQC {
+		t.Fatal("unexpected result for empty qc", err)
+	}
+}
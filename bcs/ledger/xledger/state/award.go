@@ -0,0 +1,138 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/xmodel"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/proposal/utils"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+// 出块奖励的分配比例保存在$award合约存储中，通过提案调用UpdateSplit修改，未修改过时使用创世配置。
+// 配置了分配比例或发行上限时，其他节点执行区块前校验奖励交易的每个输出。
+
+const (
+	AwardKernelContract = "$award"
+	awardSplitKey       = "split"
+)
+
+var (
+	ErrInvalidAwardTx = errors.New("invalid award tx")
+)
+
+// registerAwardMethods 注册修改奖励分配的系统合约方法
+func (t *State) registerAwardMethods(contractMgr contract.Manager) {
+	if contractMgr == nil || contractMgr.GetKernRegistry() == nil {
+		return
+	}
+	contractMgr.GetKernRegistry().RegisterKernMethod(AwardKernelContract, "UpdateSplit", t.updateAwardSplit)
+}
+
+// updateAwardSplit 由提案在trigger高度调用，参数为{"award_split": [...]}
+func (t *State) updateAwardSplit(ctx contract.KContext) (*contract.Response, error) {
+	if ctx.Caller() != utils.ProposalKernelContract {
+		return nil, fmt.Errorf("caller %s no authority to UpdateSplit", ctx.Caller())
+	}
+	args := struct {
+		AwardSplit []ledger.AwardRecipient `json:"award_split"`
+	}{}
+	if err := json.Unmarshal(ctx.Args()["args"], &args); err != nil {
+		return nil, fmt.Errorf("update award split failed, parse args error: %v", err)
+	}
+	if err := ledger.VerifyAwardSplit(args.AwardSplit); err != nil {
+		return nil, err
+	}
+	value, err := json.Marshal(args.AwardSplit)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Put(AwardKernelContract, []byte(awardSplitKey), value); err != nil {
+		return nil, err
+	}
+	return &contract.Response{
+		Status:  utils.StatusOK,
+		Message: "success",
+	}, nil
+}
+
+// GetAwardSplit 返回当前生效的奖励分配，未配置时返回nil
+func (t *State) GetAwardSplit() ([]ledger.AwardRecipient, error) {
+	data, err := t.xmodel.Get(AwardKernelContract, []byte(awardSplitKey))
+	if err != nil {
+		return nil, err
+	}
+	value := data.GetPureData().GetValue()
+	if len(value) == 0 || bytes.Equal(value, []byte(xmodel.DelFlag)) {
+		return t.sctx.Ledger.GetGenesisBlock().GetConfig().GetAwardSplit(), nil
+	}
+	var split []ledger.AwardRecipient
+	if err := json.Unmarshal(value, &split); err != nil {
+		return nil, err
+	}
+	return split, nil
+}
+
+// awardVerifiable 配置了分配比例或发行上限时需要校验奖励交易的每个输出
+func (t *State) awardVerifiable(split []ledger.AwardRecipient) bool {
+	return len(split) > 0 || t.sctx.Ledger.GetGenesisBlock().GetConfig().GetMaxSupply() != nil
+}
+
+// AwardOutputs 基于当前状态计算高度为height的区块的奖励输出
+func (t *State) AwardOutputs(height int64, proposer []byte) ([]*protos.TxOutput, error) {
+	split, err := t.GetAwardSplit()
+	if err != nil {
+		return nil, err
+	}
+	genesis := t.sctx.Ledger.GetGenesisBlock()
+	award := genesis.CapAward(genesis.CalcAward(height), t.GetTotal())
+	if award.Sign() < 0 {
+		return nil, ErrInvalidAwardTx
+	}
+	return ledger.SplitAward(award, string(proposer), split), nil
+}
+
+// verifyAwardTx 执行区块前校验奖励交易的输出与当前的分配规则一致
+func (t *State) verifyAwardTx(block *pb.InternalBlock) error {
+	// 创世块的奖励交易为预分配
+	if len(block.PreHash) == 0 {
+		return nil
+	}
+	split, err := t.GetAwardSplit()
+	if err != nil {
+		return err
+	}
+	if !t.awardVerifiable(split) {
+		return nil
+	}
+	for _, tx := range block.Transactions {
+		if !tx.Coinbase {
+			continue
+		}
+		expect, err := t.AwardOutputs(block.Height, block.Proposer)
+		if err != nil {
+			return err
+		}
+		if len(tx.TxOutputs) != len(expect) {
+			t.log.Warn("award tx outputs mismatch", "height", block.Height, "expect", len(expect),
+				"actual", len(tx.TxOutputs))
+			return ErrInvalidAwardTx
+		}
+		for i, txOutput := range tx.TxOutputs {
+			if !bytes.Equal(txOutput.ToAddr, expect[i].ToAddr) ||
+				big.NewInt(0).SetBytes(txOutput.Amount).Cmp(big.NewInt(0).SetBytes(expect[i].Amount)) != 0 ||
+				txOutput.FrozenHeight != 0 {
+				t.log.Warn("award tx output mismatch", "height", block.Height, "index", i,
+					"expectAddr", string(expect[i].ToAddr), "actualAddr", string(txOutput.ToAddr))
+				return ErrInvalidAwardTx
+			}
+		}
+	}
+	return nil
+}
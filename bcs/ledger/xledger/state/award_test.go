@@ -0,0 +1,67 @@
+package state
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	txn "github.com/xuperchain/xupercore/bcs/ledger/xledger/tx"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+)
+
+func TestAwardSplit(t *testing.T) {
+	// 发行上限比预分配多500，第一个区块的奖励被限制为500
+	ts := newTestState(t, `"award_schedule": {"max_supply": "100000000000000000500"},
+		"award_split": [{"address": "$proposer", "ratio": 3}, {"address": "treasury", "ratio": 1}],`)
+	ledger, sta, rootBlock := ts.ledger, ts.state, ts.rootBlock
+
+	outputs, err := sta.AwardOutputs(1, []byte("miner-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(outputs) != 2 || string(outputs[0].ToAddr) != "miner-1" || string(outputs[1].ToAddr) != "treasury" ||
+		big.NewInt(0).SetBytes(outputs[0].Amount).Int64() != 375 ||
+		big.NewInt(0).SetBytes(outputs[1].Amount).Int64() != 125 {
+		t.Fatal("unexpected award outputs", outputs)
+	}
+
+	// 按分配规则生成的奖励交易可以执行
+	awardTx, err := txn.GenerateAwardTxWithOutputs(outputs, []byte("award"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaPk, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	block, err := ledger.FormatBlock([]*pb.Transaction{awardTx}, []byte("miner-1"), ecdsaPk, 123456789, 0, 0,
+		rootBlock.Blockid, sta.GetTotal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := ledger.ConfirmBlock(block, false); !status.Succ {
+		t.Fatal("confirm block fail", status.Error)
+	}
+	if err := sta.Play(block.Blockid); err != nil {
+		t.Fatal(err)
+	}
+
+	// 奖励全部给矿工的区块不符合分配规则
+	badTx, err := txn.GenerateAwardTx("miner-1", "500", []byte("award"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	badBlock := &pb.InternalBlock{
+		Height:       2,
+		PreHash:      block.Blockid,
+		Proposer:     []byte("miner-1"),
+		Transactions: []*pb.Transaction{badTx},
+	}
+	if err := sta.verifyAwardTx(badBlock); err != ErrInvalidAwardTx {
+		t.Fatal("expect invalid award tx", err)
+	}
+	// 达到发行上限后奖励为0
+	outputs, err = sta.AwardOutputs(2, []byte("miner-1"))
+	if err != nil || big.NewInt(0).SetBytes(outputs[0].Amount).Sign() != 0 {
+		t.Fatal("award should be zero after max supply", outputs, err)
+	}
+}
@@ -0,0 +1,27 @@
+package state
+
+import (
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+)
+
+// 跨合约调用回滚:
+// 从创世配置的分叉高度起，被调合约失败时回滚其对状态的修改，分叉前的区块按原规则重放，
+// 分叉前预执行的合约交易读写集可能与分叉后的执行结果不一致，在分叉区块之前被回滚。
+
+// ContractCallRevertEnabled 下一个区块中被调合约失败时是否回滚其修改
+func (t *State) ContractCallRevertEnabled() bool {
+	height, err := t.latestHeight()
+	if err != nil {
+		return false
+	}
+	return t.sctx.Ledger.GetGenesisBlock().GetConfig().IsContractCallRevertEnabled(height + 1)
+}
+
+// crossesContractCallRevertFork 交易调用了合约，且按分叉前的规则执行，不能打包进高度为height的分叉区块
+func (t *State) crossesContractCallRevertFork(tx *pb.Transaction, height int64) bool {
+	config := t.sctx.Ledger.GetGenesisBlock().GetConfig()
+	if config.IsContractCallRevertEnabled(height-1) || !config.IsContractCallRevertEnabled(height) {
+		return false
+	}
+	return len(tx.ContractRequests) > 0
+}
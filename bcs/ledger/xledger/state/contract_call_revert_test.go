@@ -0,0 +1,35 @@
+package state
+
+import (
+	"testing"
+
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func TestContractCallRevertFork(t *testing.T) {
+	ts := newTestState(t, `"contract_call_revert": {"fork_height": 2},`)
+	sta := ts.state
+
+	if sta.ContractCallRevertEnabled() {
+		t.Fatal("contract call revert should not be enabled before fork")
+	}
+	block1 := confirmNonceBlock(t, ts.ledger, sta, ts.rootBlock.Blockid)
+	if err := sta.Play(block1.Blockid); err != nil {
+		t.Fatal(err)
+	}
+	if !sta.ContractCallRevertEnabled() {
+		t.Fatal("contract call revert should be enabled at fork height")
+	}
+
+	// 分叉前预执行的合约交易不能打包进分叉区块
+	invokeTx := &pb.Transaction{
+		ContractRequests: []*protos.InvokeRequest{{ModuleName: "native", ContractName: "counter", MethodName: "call"}},
+	}
+	if !sta.unpackableTx(invokeTx, 2) || sta.unpackableTx(invokeTx, 3) {
+		t.Fatal("contract tx should only be undone at fork height")
+	}
+	if sta.unpackableTx(&pb.Transaction{}, 2) {
+		t.Fatal("tx without contract requests should not be undone")
+	}
+}
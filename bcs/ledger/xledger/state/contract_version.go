@@ -0,0 +1,35 @@
+package state
+
+import (
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+)
+
+// 合约版本历史:
+// 从创世配置的分叉高度起，部署和升级合约额外写入版本号、版本历史和被替换的旧代码，
+// 合约按下一个区块的高度执行，分叉前预执行的部署和升级交易在分叉区块之前被回滚。
+
+// contractBucket 合约部署信息所在的bucket，部署和升级交易都会写入
+const contractBucket = "contract"
+
+// ContractVersionEnabled 下一个区块中部署和升级合约是否记录版本历史
+func (t *State) ContractVersionEnabled() bool {
+	height, err := t.latestHeight()
+	if err != nil {
+		return false
+	}
+	return t.sctx.Ledger.GetGenesisBlock().GetConfig().IsContractVersionEnabled(height + 1)
+}
+
+// crossesContractVersionFork 交易修改了合约部署信息，且按分叉前的规则执行，不能打包进高度为height的分叉区块
+func (t *State) crossesContractVersionFork(tx *pb.Transaction, height int64) bool {
+	config := t.sctx.Ledger.GetGenesisBlock().GetConfig()
+	if config.IsContractVersionEnabled(height-1) || !config.IsContractVersionEnabled(height) {
+		return false
+	}
+	for _, txOutputExt := range tx.TxOutputsExt {
+		if txOutputExt.Bucket == contractBucket {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package state
+
+import (
+	"testing"
+
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func TestContractVersionFork(t *testing.T) {
+	ts := newTestState(t, `"contract_version": {"fork_height": 2},`)
+	sta := ts.state
+
+	// 下一个区块高度为1，还未到分叉高度
+	if sta.ContractVersionEnabled() {
+		t.Fatal("contract version should not be enabled before fork")
+	}
+	block1 := confirmNonceBlock(t, ts.ledger, sta, ts.rootBlock.Blockid)
+	if err := sta.Play(block1.Blockid); err != nil {
+		t.Fatal(err)
+	}
+	if !sta.ContractVersionEnabled() {
+		t.Fatal("contract version should be enabled at fork height")
+	}
+
+	// 分叉前预执行的部署交易不能打包进分叉区块
+	deployTx := &pb.Transaction{
+		TxOutputsExt: []*protos.TxOutputExt{{Bucket: contractBucket, Key: []byte("counter.desc"), Value: []byte("desc")}},
+	}
+	if !sta.unpackableTx(deployTx, 2) || sta.unpackableTx(deployTx, 3) {
+		t.Fatal("deploy tx should only be undone at fork height")
+	}
+	if sta.unpackableTx(&pb.Transaction{}, 2) {
+		t.Fatal("tx without contract deployment should not be undone")
+	}
+}
@@ -0,0 +1,178 @@
+package state
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+// 开启费用市场后，交易手续费("$"输出)先支付gas*区块基础费用，这部分被销毁或转入国库账户，
+// 剩余部分作为小费归矿工。gas价格和保留合约可通过提案修改，区块执行时将当时的值记录在费用参数表中，
+// 回滚区块和重放状态树时使用记录的值，拆分结果与执行时一致。
+
+var (
+	ErrFeeNotEnough = errors.New("tx fee is not enough to pay the base fee")
+)
+
+// feeUtxo 手续费产生的utxo
+type feeUtxo struct {
+	addr   []byte
+	offset int32
+	amount *big.Int
+}
+
+// NextBaseFee 返回下一个区块的基础费用，未开启费用市场时返回nil
+func (t *State) NextBaseFee() (*big.Int, error) {
+	return t.sctx.Ledger.CalcBaseFee(t.latestBlockid)
+}
+
+// feeParams 计算交易gas消耗的参数
+type feeParams struct {
+	gasPrice *protos.GasPrice
+	reserved int
+}
+
+// currentFeeParams 返回当前状态下的参数，区块执行过程中提案的修改在区块完成后才生效
+func (t *State) currentFeeParams() *feeParams {
+	return &feeParams{
+		gasPrice: t.meta.GetGasPrice(),
+		reserved: len(t.meta.GetReservedContracts()),
+	}
+}
+
+func feeParamsKey(blockid []byte) []byte {
+	return append([]byte(pb.FeeParamsTablePrefix), blockid...)
+}
+
+// blockFeeParams 返回区块执行时记录的参数
+// 未开启费用市场的区块不使用gas消耗，执行时尚未记录参数的区块使用当前参数
+func (t *State) blockFeeParams(block *pb.InternalBlock) (*feeParams, error) {
+	if len(block.BaseFee) == 0 {
+		return t.currentFeeParams(), nil
+	}
+	value, err := t.ldb.Get(feeParamsKey(block.Blockid))
+	if err != nil {
+		if kvdb.ErrNotFound(err) {
+			return t.currentFeeParams(), nil
+		}
+		return nil, err
+	}
+	meta := &pb.UtxoMeta{}
+	if err := proto.Unmarshal(value, meta); err != nil {
+		return nil, err
+	}
+	return &feeParams{
+		gasPrice: meta.GetGasPrice(),
+		reserved: len(meta.GetReservedContracts()),
+	}, nil
+}
+
+// saveFeeParams 区块执行时记录当前参数，随区块的batch写入
+func (t *State) saveFeeParams(block *pb.InternalBlock, batch kvdb.Batch) error {
+	if len(block.BaseFee) == 0 {
+		return nil
+	}
+	value, err := proto.Marshal(&pb.UtxoMeta{
+		GasPrice:          t.meta.GetGasPrice(),
+		ReservedContracts: t.meta.GetReservedContracts(),
+	})
+	if err != nil {
+		return err
+	}
+	return batch.Put(feeParamsKey(block.Blockid), value)
+}
+
+// TxGasUsed 返回交易在当前参数下声明的gas消耗，与verifyTxRWSets一致，不计保留合约
+func (t *State) TxGasUsed(tx *pb.Transaction) int64 {
+	return txGasUsed(tx, t.currentFeeParams())
+}
+
+func txGasUsed(tx *pb.Transaction, params *feeParams) int64 {
+	if tx.Coinbase || tx.Autogen {
+		return 0
+	}
+	gasUsed := int64(0)
+	for i, req := range tx.GetContractRequests() {
+		if i < params.reserved {
+			continue
+		}
+		limits := contract.FromPbLimits(req.GetResourceLimits())
+		gasUsed += limits.TotalGas(params.gasPrice)
+	}
+	return gasUsed
+}
+
+// TxBaseFee 返回交易在指定基础费用和当前参数下需要支付的基础费用
+func (t *State) TxBaseFee(tx *pb.Transaction, baseFee *big.Int) *big.Int {
+	return txBaseFee(tx, baseFee, t.currentFeeParams())
+}
+
+func txBaseFee(tx *pb.Transaction, baseFee *big.Int, params *feeParams) *big.Int {
+	if baseFee == nil {
+		return big.NewInt(0)
+	}
+	return big.NewInt(0).Mul(big.NewInt(txGasUsed(tx, params)), baseFee)
+}
+
+// VerifyTxBaseFee 检查交易的手续费能否在当前参数下支付指定的基础费用
+func (t *State) VerifyTxBaseFee(tx *pb.Transaction, baseFee *big.Int) error {
+	_, _, err := t.splitFee(tx, nil, baseFee, t.currentFeeParams())
+	return err
+}
+
+// blockBaseFee 返回区块头中的基础费用，未开启费用市场的区块返回nil
+func blockBaseFee(block *pb.InternalBlock) *big.Int {
+	if len(block.BaseFee) == 0 {
+		return nil
+	}
+	return big.NewInt(0).SetBytes(block.BaseFee)
+}
+
+// splitFee 拆分交易手续费，返回生成的utxo和被销毁的金额
+// baseFee为nil时手续费全部归矿工，与未开启费用市场时一致
+func (t *State) splitFee(tx *pb.Transaction, proposer []byte, baseFee *big.Int,
+	params *feeParams) ([]*feeUtxo, *big.Int, error) {
+	var utxos []*feeUtxo
+	burnt := big.NewInt(0)
+	remain := txBaseFee(tx, baseFee, params)
+	treasury := []byte(t.sctx.Ledger.GetGenesisBlock().GetConfig().GetFeeMarketTreasury())
+	for offset, txOutput := range tx.TxOutputs {
+		if !bytes.Equal(txOutput.ToAddr, []byte(FeePlaceholder)) {
+			continue
+		}
+		amount := big.NewInt(0).SetBytes(txOutput.Amount)
+		if baseFee == nil {
+			utxos = append(utxos, &feeUtxo{addr: proposer, offset: int32(offset), amount: amount})
+			continue
+		}
+		// 多个手续费输出时依次支付基础费用
+		charge := big.NewInt(0).Set(remain)
+		if charge.Cmp(amount) > 0 {
+			charge.Set(amount)
+		}
+		remain.Sub(remain, charge)
+		tip := big.NewInt(0).Sub(amount, charge)
+		switch {
+		case len(treasury) == 0:
+			burnt.Add(burnt, charge)
+		case bytes.Equal(treasury, proposer):
+			// 国库账户出块时合并为一个utxo
+			tip.Add(tip, charge)
+		case charge.Sign() > 0:
+			utxos = append(utxos, &feeUtxo{addr: treasury, offset: int32(offset), amount: charge})
+		}
+		if tip.Sign() > 0 {
+			utxos = append(utxos, &feeUtxo{addr: proposer, offset: int32(offset), amount: tip})
+		}
+	}
+	if remain.Sign() > 0 {
+		return nil, nil, ErrFeeNotEnough
+	}
+	return utxos, burnt, nil
+}
@@ -0,0 +1,113 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	ledger_pkg "github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func TestFeeMarket(t *testing.T) {
+	ts := newTestState(t, `"fee_market": {"enabled": true, "initial_base_fee": "2"},`)
+	ledger, sta, rootBlock := ts.ledger, ts.state, ts.rootBlock
+
+	baseFee, err := sta.NextBaseFee()
+	if err != nil || baseFee.Int64() != 2 {
+		t.Fatal("unexpected next base fee", baseFee, err)
+	}
+	// 出块后区块头带有基础费用，状态根按拆分后的手续费计算
+	block1 := confirmNonceBlock(t, ledger, sta, rootBlock.Blockid)
+	if big.NewInt(0).SetBytes(block1.BaseFee).Int64() != 2 {
+		t.Fatal("unexpected base fee of block", block1.BaseFee)
+	}
+	if err := sta.Play(block1.Blockid); err != nil {
+		t.Fatal(err)
+	}
+
+	// gas为100，基础费用为2时需要支付200，剩余100为小费
+	tx := &pb.Transaction{
+		Txid: []byte("fee-market-tx"),
+		ContractRequests: []*protos.InvokeRequest{
+			{ResourceLimits: []*protos.ResourceLimit{{Type: protos.ResourceType_DISK, Limit: 100}}},
+		},
+		TxOutputs: []*protos.TxOutput{{ToAddr: []byte(FeePlaceholder), Amount: big.NewInt(300).Bytes()}},
+	}
+	if gas := sta.TxGasUsed(tx); gas != 100 {
+		t.Fatal("unexpected gas used", gas)
+	}
+	if err := sta.VerifyTxBaseFee(tx, big.NewInt(4)); err != ErrFeeNotEnough {
+		t.Fatal("expect fee not enough", err)
+	}
+	fees, burnt, err := sta.splitFee(tx, []byte("miner"), big.NewInt(2), sta.currentFeeParams())
+	if err != nil || burnt.Int64() != 200 || len(fees) != 1 || fees[0].amount.Int64() != 100 {
+		t.Fatal("unexpected fee split", fees, burnt, err)
+	}
+	// 未开启费用市场时全部归矿工
+	fees, burnt, err = sta.splitFee(tx, []byte("miner"), nil, sta.currentFeeParams())
+	if err != nil || burnt.Sign() != 0 || len(fees) != 1 || fees[0].amount.Int64() != 300 {
+		t.Fatal("unexpected fee split without fee market", fees, burnt, err)
+	}
+
+	// 销毁的基础费用在回滚时恢复
+	block := &pb.InternalBlock{Blockid: []byte("fee-block"), Proposer: []byte("miner"),
+		BaseFee: ledger_pkg.EncodeBaseFee(big.NewInt(2))}
+	total := big.NewInt(0).Set(sta.GetTotal())
+	batch := sta.NewBatch()
+	if err := sta.saveFeeParams(block, batch); err != nil {
+		t.Fatal(err)
+	}
+	if err := sta.payFee(tx, batch, block, sta.currentFeeParams()); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatal(err)
+	}
+	if big.NewInt(0).Sub(total, sta.GetTotal()).Int64() != 200 {
+		t.Fatal("base fee should be burnt", total, sta.GetTotal())
+	}
+	tipKey := []byte(utxo.GenUtxoKeyWithPrefix([]byte("miner"), tx.Txid, 0))
+	if exist, _ := sta.ldb.Has(tipKey); !exist {
+		t.Fatal("tip utxo should be created")
+	}
+	// 提案修改gas价格后，回滚使用区块执行时记录的参数
+	gasPrice := sta.meta.Meta.GasPrice
+	sta.meta.Meta.GasPrice = &protos.GasPrice{DiskRate: 2}
+	_, burnt, err = sta.splitFee(tx, []byte("miner"), big.NewInt(2), sta.currentFeeParams())
+	if err != nil || burnt.Int64() != 100 {
+		t.Fatal("unexpected fee split with new gas price", burnt, err)
+	}
+	params, err := sta.blockFeeParams(block)
+	if err != nil || txGasUsed(tx, params) != 100 {
+		t.Fatal("unexpected recorded fee params", err)
+	}
+	batch = sta.NewBatch()
+	if err := sta.undoPayFee(tx, batch, block, params); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatal(err)
+	}
+	if sta.GetTotal().Cmp(total) != 0 {
+		t.Fatal("burnt fee should be restored", total, sta.GetTotal())
+	}
+	if exist, _ := sta.ldb.Has(tipKey); exist {
+		t.Fatal("tip utxo should be removed")
+	}
+	sta.meta.Meta.GasPrice = gasPrice
+
+	// 配置国库账户时基础费用转入国库，国库账户出块时合并为一个utxo
+	ledger.GetGenesisBlock().GetConfig().FeeMarket.Treasury = "treasury"
+	fees, burnt, err = sta.splitFee(tx, []byte("miner"), big.NewInt(2), sta.currentFeeParams())
+	if err != nil || burnt.Sign() != 0 || len(fees) != 2 ||
+		string(fees[0].addr) != "treasury" || fees[0].amount.Int64() != 200 ||
+		string(fees[1].addr) != "miner" || fees[1].amount.Int64() != 100 {
+		t.Fatal("unexpected fee split with treasury", fees, burnt, err)
+	}
+	fees, _, err = sta.splitFee(tx, []byte("treasury"), big.NewInt(2), sta.currentFeeParams())
+	if err != nil || len(fees) != 1 || fees[0].amount.Int64() != 300 {
+		t.Fatal("unexpected fee split when treasury is proposer", fees, err)
+	}
+}
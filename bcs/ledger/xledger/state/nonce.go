@@ -0,0 +1,229 @@
+package state
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+// 交易有效期和nonce防重放:
+// 设置了ValidUntilHeight的交易只能被打包进不高于该高度的区块，有效期内同一发起者的nonce只能被一笔交易使用。
+// nonce表随交易的执行和回滚写入和删除，因此分叉切换后记录与当前分支一致；
+// 有效期低于不可逆高度的记录不会再被引用，执行区块时按过期索引清理。
+
+var (
+	ErrTxExpired     = errors.New("tx is beyond its valid height")
+	ErrNonceReplay   = errors.New("nonce has been used by another tx of the initiator")
+	ErrNonceKeyParse = errors.New("parse nonce key error")
+)
+
+// NonceKey 返回交易在nonce表中的key(不含表前缀)，nonce和txid使用hex编码，避免与分隔符冲突
+func NonceKey(tx *pb.Transaction) []byte {
+	return append(noncePrefix(tx), hex.EncodeToString(tx.Txid)...)
+}
+
+func noncePrefix(tx *pb.Transaction) []byte {
+	return []byte(tx.Initiator + "/" + hex.EncodeToString([]byte(tx.Nonce)) + "/")
+}
+
+// ParseNonceKey 从nonce表的key(不含表前缀)中解析txid
+func ParseNonceKey(key []byte) ([]byte, error) {
+	parts := strings.Split(string(key), "/")
+	if len(parts) != 3 {
+		return nil, ErrNonceKeyParse
+	}
+	if _, err := hex.DecodeString(parts[1]); err != nil {
+		return nil, ErrNonceKeyParse
+	}
+	txid, err := hex.DecodeString(parts[2])
+	if err != nil || len(txid) == 0 {
+		return nil, ErrNonceKeyParse
+	}
+	return txid, nil
+}
+
+// NonceExpireKey 返回过期索引的key(不含表前缀)
+func NonceExpireKey(height int64, nonceKey []byte) []byte {
+	return append([]byte(fmt.Sprintf("%020d/", height)), nonceKey...)
+}
+
+// ParseNonceExpireKey 从过期索引的key(不含表前缀)中解析有效期和nonce表的key
+func ParseNonceExpireKey(key []byte) (int64, []byte, error) {
+	idx := bytes.IndexByte(key, '/')
+	if idx < 0 {
+		return 0, nil, ErrNonceKeyParse
+	}
+	height, err := strconv.ParseInt(string(key[:idx]), 10, 64)
+	if err != nil {
+		return 0, nil, ErrNonceKeyParse
+	}
+	nonceKey := key[idx+1:]
+	if _, err := ParseNonceKey(nonceKey); err != nil {
+		return 0, nil, err
+	}
+	return height, nonceKey, nil
+}
+
+// verifyTxExpiry 检查交易能否被打包进指定高度的区块
+func verifyTxExpiry(tx *pb.Transaction, height int64) error {
+	if tx.ValidUntilHeight > 0 && height > tx.ValidUntilHeight {
+		return ErrTxExpired
+	}
+	return nil
+}
+
+// unpackableTx 交易不能被打包进高度为height的区块
+func (t *State) unpackableTx(tx *pb.Transaction, height int64) bool {
+	return verifyTxExpiry(tx, height) != nil || t.crossesContractVersionFork(tx, height) ||
+		t.crossesContractCallRevertFork(tx, height)
+}
+
+// latestHeight 返回状态机最新区块的高度
+func (t *State) latestHeight() (int64, error) {
+	header, err := t.sctx.Ledger.QueryBlockHeader(t.latestBlockid)
+	if err != nil {
+		return 0, err
+	}
+	return header.Height, nil
+}
+
+// checkNonce 检查交易的nonce在height高度是否已被其他仍在有效期内的交易使用
+// ignore中的交易会在同一批次中被回滚，不计入
+func (t *State) checkNonce(tx *pb.Transaction, height int64, ignore map[string]bool) error {
+	if tx.ValidUntilHeight == 0 {
+		return nil
+	}
+	prefix := append([]byte(pb.NonceTablePrefix), noncePrefix(tx)...)
+	iter := t.ldb.NewIteratorWithPrefix(prefix)
+	defer iter.Release()
+	for iter.Next() {
+		txid, err := ParseNonceKey(iter.Key()[len(pb.NonceTablePrefix):])
+		if err != nil {
+			continue
+		}
+		if bytes.Equal(txid, tx.Txid) || ignore[string(txid)] {
+			continue
+		}
+		validUntil, err := strconv.ParseInt(string(iter.Value()), 10, 64)
+		if err != nil {
+			return err
+		}
+		if validUntil >= height {
+			t.log.Warn("nonce replay", "txid", utils.F(tx.Txid), "initiator", tx.Initiator,
+				"nonce", tx.Nonce, "usedBy", utils.F(txid))
+			return ErrNonceReplay
+		}
+	}
+	return iter.Error()
+}
+
+// verifyBlockNonces 校验区块中交易的有效期和nonce，undoDone为执行区块时回滚的未确认交易
+func (t *State) verifyBlockNonces(block *pb.InternalBlock, undoDone map[string]bool) error {
+	nonces := map[string]bool{}
+	for _, tx := range block.Transactions {
+		if tx.ValidUntilHeight == 0 {
+			continue
+		}
+		if err := verifyTxExpiry(tx, block.Height); err != nil {
+			t.log.Warn("tx expired", "txid", utils.F(tx.Txid), "validUntilHeight", tx.ValidUntilHeight,
+				"height", block.Height)
+			return err
+		}
+		prefix := string(noncePrefix(tx))
+		if nonces[prefix] {
+			t.log.Warn("found duplicated nonce in same block", "txid", utils.F(tx.Txid))
+			return ErrNonceReplay
+		}
+		nonces[prefix] = true
+		if err := t.checkNonce(tx, block.Height, undoDone); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// putNonce 交易执行时记录nonce
+func putNonce(tx *pb.Transaction, batch kvdb.Batch) {
+	if tx.ValidUntilHeight == 0 {
+		return
+	}
+	key := NonceKey(tx)
+	batch.Put(append([]byte(pb.NonceTablePrefix), key...), []byte(strconv.FormatInt(tx.ValidUntilHeight, 10)))
+	batch.Put(append([]byte(pb.NonceExpireTablePrefix), NonceExpireKey(tx.ValidUntilHeight, key)...), []byte{})
+}
+
+// deleteNonce 交易回滚时删除nonce记录
+func deleteNonce(tx *pb.Transaction, batch kvdb.Batch) {
+	if tx.ValidUntilHeight == 0 {
+		return
+	}
+	key := NonceKey(tx)
+	batch.Delete(append([]byte(pb.NonceTablePrefix), key...))
+	batch.Delete(append([]byte(pb.NonceExpireTablePrefix), NonceExpireKey(tx.ValidUntilHeight, key)...))
+}
+
+// pruneNonces 清理有效期低于不可逆高度的nonce记录，这些交易不会再被任何分支打包
+func (t *State) pruneNonces(irreversibleHeight int64, batch kvdb.Batch) error {
+	if irreversibleHeight <= 0 {
+		return nil
+	}
+	start := []byte(pb.NonceExpireTablePrefix)
+	limit := append([]byte(pb.NonceExpireTablePrefix), fmt.Sprintf("%020d", irreversibleHeight)...)
+	iter := t.ldb.NewIteratorWithRange(start, limit)
+	defer iter.Release()
+	for iter.Next() {
+		key := append([]byte{}, iter.Key()...)
+		batch.Delete(key)
+		_, nonceKey, err := ParseNonceExpireKey(key[len(pb.NonceExpireTablePrefix):])
+		if err != nil {
+			continue
+		}
+		batch.Delete(append([]byte(pb.NonceTablePrefix), nonceKey...))
+	}
+	return iter.Error()
+}
+
+// undoExpiredTxs 执行区块后回滚下一个区块不能再打包的未确认交易，返回被回滚的交易
+func (t *State) undoExpiredTxs(block *pb.InternalBlock, batch kvdb.Batch) (map[string]bool, error) {
+	undoDone := map[string]bool{}
+	txidsInBlock := map[string]bool{}
+	for _, tx := range block.Transactions {
+		txidsInBlock[string(tx.Txid)] = true
+	}
+	hasExpired := false
+	t.tx.UnconfirmTxInMem.Range(func(k, v interface{}) bool {
+		tx := v.(*pb.Transaction)
+		if !txidsInBlock[k.(string)] && t.unpackableTx(tx, block.Height+1) {
+			hasExpired = true
+			return false
+		}
+		return true
+	})
+	if !hasExpired {
+		return undoDone, nil
+	}
+
+	unconfirmTxMap, unconfirmTxGraph, _, err := t.tx.SortUnconfirmedTx()
+	if err != nil {
+		return nil, err
+	}
+	for txid, unconfirmTx := range unconfirmTxMap {
+		if txidsInBlock[txid] || !t.unpackableTx(unconfirmTx, block.Height+1) {
+			continue
+		}
+		t.log.Warn("will undo tx because it can not be packed into next block", "txid", utils.F(unconfirmTx.Txid),
+			"validUntilHeight", unconfirmTx.ValidUntilHeight)
+		err := t.undoUnconfirmedTx(unconfirmTx, unconfirmTxMap, unconfirmTxGraph, batch, undoDone, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return undoDone, nil
+}
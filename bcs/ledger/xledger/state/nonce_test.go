@@ -0,0 +1,200 @@
+package state
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	ledger_pkg "github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/context"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
+	txn "github.com/xuperchain/xupercore/bcs/ledger/xledger/tx"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	crypto_client "github.com/xuperchain/xupercore/lib/crypto/client"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+// nonceTx 构造bob花费指定utxo转账给alice的交易，找零为输出1
+func nonceTx(t testing.TB, sta *State, refTx *pb.Transaction, offset int32, amount int64, nonce string, validUntil int64) *pb.Transaction {
+	total := big.NewInt(0).SetBytes(refTx.TxOutputs[offset].Amount)
+	tx := &pb.Transaction{
+		Version:          1,
+		Nonce:            nonce,
+		Initiator:        BobAddress,
+		AuthRequire:      []string{BobAddress},
+		ValidUntilHeight: validUntil,
+		TxInputs: []*protos.TxInput{
+			{
+				RefTxid:   refTx.Txid,
+				RefOffset: offset,
+				FromAddr:  []byte(BobAddress),
+				Amount:    total.Bytes(),
+			},
+		},
+		TxOutputs: []*protos.TxOutput{
+			{ToAddr: []byte(AliceAddress), Amount: big.NewInt(amount).Bytes()},
+			{ToAddr: []byte(BobAddress), Amount: big.NewInt(0).Sub(total, big.NewInt(amount)).Bytes()},
+		},
+	}
+	sign, err := txhash.ProcessSignTx(sta.sctx.Crypt, tx, []byte(BobPrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.InitiatorSigns = []*protos.SignatureInfo{{PublicKey: BobPubkey, Sign: sign}}
+	tx.AuthRequireSigns = tx.InitiatorSigns
+	tx.Txid, _ = txhash.MakeTransactionID(tx)
+	return tx
+}
+
+func confirmNonceBlock(t testing.TB, ledger *ledger_pkg.Ledger, sta *State, preHash []byte, txs ...*pb.Transaction) *pb.InternalBlock {
+	awardTx, err := txn.GenerateAwardTx("miner-1", "1000", []byte("award"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecdsaPk, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	block, err := ledger.FormatBlock(append(txs, awardTx), []byte("miner-1"), ecdsaPk, 123456789, 0, 0, preHash, sta.GetTotal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := ledger.ConfirmBlock(block, false); !status.Succ {
+		t.Fatal("confirm block fail", status.Error)
+	}
+	return block
+}
+
+// testState 基于内存存储的账本和状态机，创世配置在GenesisConf基础上修改
+type testState struct {
+	ledger    *ledger_pkg.Ledger
+	state     *State
+	genesisTx *pb.Transaction
+	rootBlock *pb.InternalBlock
+}
+
+// newTestState genesisPatch为插入到GenesisConf中的创世配置项，以逗号结尾
+func newTestState(t testing.TB, genesisPatch string) *testState {
+	econf, err := mock.NewMemoryEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs.InitLog(econf.GenConfFilePath(econf.LogConf), econf.GenDirAbsPath(econf.LogDir))
+	econf.ChainDir = "/memory/state_test/" + t.Name()
+
+	conf := bytes.Replace(GenesisConf, []byte(`"maxblocksize": "16",`),
+		[]byte(`"maxblocksize": "16", `+genesisPatch), 1)
+	lctx, err := ledger_pkg.NewLedgerCtx(econf, "xuper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ledger, err := ledger_pkg.CreateLedger(lctx, conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(ledger.Close)
+	genesisTx, err := txn.GenerateRootTx(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootBlock, _ := ledger.FormatRootBlock([]*pb.Transaction{genesisTx})
+	if status := ledger.ConfirmBlock(rootBlock, true); !status.Succ {
+		t.Fatal("confirm block fail")
+	}
+	crypt, err := crypto_client.CreateCryptoClient(crypto_client.CryptoTypeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sctx, err := context.NewStateCtx(econf, "xuper", ledger, crypt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sta, err := NewState(sctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sta.Close() })
+	if err := sta.Play(rootBlock.Blockid); err != nil {
+		t.Fatal(err)
+	}
+	return &testState{ledger: ledger, state: sta, genesisTx: genesisTx, rootBlock: rootBlock}
+}
+
+func TestTxExpiryAndNonce(t *testing.T) {
+	ts := newTestState(t, "")
+	ledger, sta, genesisTx, rootBlock := ts.ledger, ts.state, ts.genesisTx, ts.rootBlock
+
+	// 交易池中同一nonce只能被一笔交易使用
+	tx1 := nonceTx(t, sta, genesisTx, 0, 10, "n1", 5)
+	tx2 := nonceTx(t, sta, genesisTx, 0, 20, "n1", 5)
+	if err := sta.DoTx(tx1); err != nil {
+		t.Fatal(err)
+	}
+	if err := sta.DoTx(tx2); err != ErrNonceReplay {
+		t.Fatal("expect nonce replay", err)
+	}
+
+	// 分叉A: A1打包tx1
+	blockA1 := confirmNonceBlock(t, ledger, sta, rootBlock.Blockid, tx1)
+	if err := sta.Play(blockA1.Blockid); err != nil {
+		t.Fatal(err)
+	}
+	if err := sta.DoTx(tx2); err != ErrNonceReplay {
+		t.Fatal("expect nonce replay after confirmed", err)
+	}
+	expiredTx := nonceTx(t, sta, tx1, 1, 1, "n2", 1)
+	if ok, err := sta.ImmediateVerifyTx(expiredTx, false); ok || err != ErrTxExpired {
+		t.Fatal("expect tx expired", err)
+	}
+	if err := sta.verifyBlockNonces(&pb.InternalBlock{Height: 2, Transactions: []*pb.Transaction{expiredTx}}, nil); err != ErrTxExpired {
+		t.Fatal("expect block with expired tx invalid", err)
+	}
+	dupTx := nonceTx(t, sta, tx1, 1, 2, "n3", 5)
+	dupTx2 := nonceTx(t, sta, tx1, 1, 3, "n3", 5)
+	if err := sta.verifyBlockNonces(&pb.InternalBlock{Height: 2, Transactions: []*pb.Transaction{dupTx, dupTx2}}, nil); err != ErrNonceReplay {
+		t.Fatal("expect duplicated nonce in block invalid", err)
+	}
+
+	// 分叉B更长，B2打包使用相同nonce的tx2，切换分支后tx1的nonce记录被回滚
+	blockB1 := confirmNonceBlock(t, ledger, sta, rootBlock.Blockid)
+	blockB2 := confirmNonceBlock(t, ledger, sta, blockB1.Blockid, tx2)
+	if err := sta.Walk(blockB2.Blockid, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := sta.checkNonce(tx1, 3, nil); err != ErrNonceReplay {
+		t.Fatal("tx1 should be replay on fork B", err)
+	}
+
+	// 切回更长的分叉A，tx2在A上属于重放
+	blockA2 := confirmNonceBlock(t, ledger, sta, blockA1.Blockid)
+	blockA3 := confirmNonceBlock(t, ledger, sta, blockA2.Blockid)
+	if err := sta.Walk(blockA3.Blockid, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := sta.verifyBlockNonces(&pb.InternalBlock{Height: 4, Transactions: []*pb.Transaction{tx2}}, nil); err != ErrNonceReplay {
+		t.Fatal("expect tx2 replay on fork A", err)
+	}
+	// 有效期过后nonce可以再次使用
+	if err := sta.checkNonce(tx2, 6, nil); err != nil {
+		t.Fatal("nonce should be reusable after expired", err)
+	}
+
+	// 下一个区块不能打包的未确认交易被回滚
+	poolTx := nonceTx(t, sta, tx1, 1, 5, "n4", 4)
+	if err := sta.DoTx(poolTx); err != nil {
+		t.Fatal(err)
+	}
+	blockA4 := confirmNonceBlock(t, ledger, sta, blockA3.Blockid)
+	if err := sta.Play(blockA4.Blockid); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sta.tx.UnconfirmTxInMem.Load(string(poolTx.Txid)); ok {
+		t.Fatal("expired tx should be removed from unconfirmed pool")
+	}
+	reuseTx := nonceTx(t, sta, tx1, 1, 6, "n4", 10)
+	if err := sta.checkNonce(reuseTx, 5, nil); err != nil {
+		t.Fatal("nonce of undone tx should be released", err)
+	}
+}
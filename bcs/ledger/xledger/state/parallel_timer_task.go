@@ -0,0 +1,100 @@
+package state
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/xmodel"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/tx"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/parallel"
+	"github.com/xuperchain/xupercore/kernel/contract/proposal/utils"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+// 定时任务并发预执行:
+// 分叉前$timer_task合约的Do方法在一个沙盒中依次触发该高度的定时任务；从创世配置的分叉高度起，
+// 每个定时任务作为一个交易在最新状态上并发预执行，冲突的任务按顺序重新执行，
+// 执行成功的任务按注册顺序合并为一个定时交易，失败的任务不修改状态。
+// 打包和校验区块都通过GetTimerTx生成定时交易，分叉前的区块按原规则重放。
+
+// getParallelTimerTx 并发执行高度为blockHeight的定时任务，生成定时交易
+func (t *State) getParallelTimerTx(blockHeight int64) (*pb.Transaction, error) {
+	reader := t.CreateXMReader()
+	sandBox, err := t.sctx.ContractMgr.NewStateSandbox(&contract.SandboxConfig{
+		XMReader: reader,
+	})
+	if err != nil {
+		t.log.Error("GetTimerTx new state sandbox error", "error", err)
+		return nil, err
+	}
+	tasks, err := timerTasks(sandBox, blockHeight)
+	if err != nil {
+		t.log.Error("GetTimerTx select timer tasks error", "error", err)
+		return nil, err
+	}
+	if err := sandBox.Flush(); err != nil {
+		return nil, err
+	}
+
+	executor := parallel.NewExecutor(t.sctx.ContractMgr, t.replayWorkers)
+	executor.SetContractCallRevert(t.sctx.Ledger.GetGenesisBlock().GetConfig().IsContractCallRevertEnabled(blockHeight))
+	results, err := executor.Execute(reader, tasks)
+	if err != nil {
+		t.log.Error("GetTimerTx execute timer tasks error", "error", err)
+		return nil, err
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			req := tasks[result.Index].Requests[0]
+			t.log.Warn("GetTimerTx timer task failed", "blockHeight", blockHeight,
+				"contractName", req.GetContractName(), "method", req.GetMethodName(), "error", result.Err)
+		}
+	}
+
+	rwSet := parallel.Merge(sandBox.RWSet(), results)
+	inputs := xmodel.GetTxInputs(rwSet.RSet)
+	outputs := xmodel.GetTxOutputs(rwSet.WSet)
+	autoTx, err := tx.GenerateAutoTxWithRWSets(inputs, outputs)
+	if err != nil {
+		return nil, err
+	}
+	t.log.Trace("GetTimerTx", "tasks", len(tasks), "readSet", rwSet.RSet, "writeSet", rwSet.WSet)
+	return autoTx, nil
+}
+
+// timerTasks 按注册顺序读取高度为blockHeight的定时任务，与$timer_task合约的Do方法相同，
+// 无法解析的任务被跳过
+func timerTasks(state contract.StateSandbox, blockHeight int64) ([]*parallel.Task, error) {
+	height := strconv.FormatInt(blockHeight, 10)
+	startKey := utils.MakeTimerBlockHeightPrefix(height)
+	endKey := utils.PrefixRange([]byte(utils.MakeTimerBlockHeightPrefixSeparator(height)))
+	iter, err := state.Select(utils.GetTimerBucket(), []byte(startKey), endKey)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var tasks []*parallel.Task
+	for iter.Next() {
+		var trigger utils.TriggerDesc
+		if err := json.Unmarshal(iter.Value(), &trigger); err != nil {
+			continue
+		}
+		args, err := json.Marshal(trigger.Args)
+		if err != nil {
+			continue
+		}
+		tasks = append(tasks, &parallel.Task{
+			Caller: utils.TimerTaskKernelContract,
+			Requests: []*protos.InvokeRequest{{
+				ModuleName:   trigger.Module,
+				ContractName: trigger.Contract,
+				MethodName:   trigger.Method,
+				Args:         map[string][]byte{"args": args},
+			}},
+		})
+	}
+	return tasks, iter.Error()
+}
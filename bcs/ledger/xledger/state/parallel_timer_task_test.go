@@ -0,0 +1,38 @@
+package state
+
+import (
+	"testing"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/proposal/utils"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+	"github.com/xuperchain/xupercore/kernel/ledger"
+)
+
+func TestTimerTasks(t *testing.T) {
+	state := sandbox.NewMemXModel()
+	put := func(height, taskID, trigger string) {
+		key := []byte(utils.MakeTimerBlockHeightTaskKey(height, taskID))
+		state.Put(utils.GetTimerBucket(), key, &ledger.VersionedData{
+			RefTxid:  []byte("txid"),
+			PureData: &ledger.PureData{Bucket: utils.GetTimerBucket(), Key: key, Value: []byte(trigger)},
+		})
+	}
+	put("5", "1", `{"height": 5, "module": "xkernel", "contract": "$proposal", "method": "Thaw", "args": {"proposal_id": "1"}}`)
+	put("5", "2", `invalid`)
+	put("6", "3", `{"height": 6, "module": "xkernel", "contract": "$proposal", "method": "Thaw"}`)
+
+	tasks, err := timerTasks(sandbox.NewXModelCache(&contract.SandboxConfig{XMReader: state}), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 无法解析的任务和其他高度的任务被跳过
+	if len(tasks) != 1 {
+		t.Fatalf("expect 1 task, got %d", len(tasks))
+	}
+	req := tasks[0].Requests[0]
+	if tasks[0].Caller != utils.TimerTaskKernelContract || req.GetContractName() != "$proposal" ||
+		req.GetMethodName() != "Thaw" || string(req.GetArgs()["args"]) != `{"proposal_id":"1"}` {
+		t.Fatalf("unexpected task %v %v", tasks[0], req)
+	}
+}
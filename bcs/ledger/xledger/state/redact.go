@@ -0,0 +1,167 @@
+package state
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/def"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/xmodel"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/proposal/utils"
+)
+
+// 交易脱敏流程:
+// 1. 监管地址对脱敏后交易的摘要签名，发起调用$redact.Redact的交易，参数为txid、fields、public_key和sign；
+// 2. 系统合约校验发起人和签名，将脱敏记录写入$redact合约存储；
+// 3. 包含脱敏记录的区块不可逆后，各节点按记录清除本地账本中交易的对应字段，并标记ModifyBlock。
+// 账本和状态机不在同一个数据库中，脱敏无法与区块的写入原子提交，也无法在回滚时恢复，
+// 因此只处理不可逆区块中的记录，链需要开启不可逆区块窗口。
+
+const (
+	RedactKernelContract = "$redact"
+	// 已处理脱敏记录的不可逆区块高度，保存在meta表中
+	RedactHeightKey = "RedactHeight"
+)
+
+var (
+	ErrRedactNotPermitted    = errors.New("initiator is not permitted to redact tx")
+	ErrInvalidRedactSign     = ledger.ErrInvalidRedactSign
+	ErrRedactNotIrreversible = errors.New("redact requires irreversible slide window")
+)
+
+// redactRecord 保存在$redact合约存储中的脱敏记录，key为txid
+type redactRecord struct {
+	Fields    []string `json:"fields"`
+	PublicKey string   `json:"public_key"`
+	Sign      string   `json:"sign"`
+}
+
+// registerRedactMethods 注册交易脱敏的系统合约方法
+func (t *State) registerRedactMethods(contractMgr contract.Manager) {
+	if contractMgr == nil || contractMgr.GetKernRegistry() == nil {
+		return
+	}
+	contractMgr.GetKernRegistry().RegisterKernMethod(RedactKernelContract, "Redact", t.redactTx)
+}
+
+// redactTx 只能由创世配置中的监管地址发起
+func (t *State) redactTx(ctx contract.KContext) (*contract.Response, error) {
+	if t.utxo.ModifyBlockAddr == "" || ctx.Initiator() != t.utxo.ModifyBlockAddr {
+		return nil, ErrRedactNotPermitted
+	}
+	if t.meta.GetIrreversibleSlideWindow() <= 0 {
+		return nil, ErrRedactNotIrreversible
+	}
+	args := ctx.Args()
+	txid, err := hex.DecodeString(string(args["txid"]))
+	if err != nil || len(txid) == 0 {
+		return nil, fmt.Errorf("redact tx failed, invalid txid: %s", args["txid"])
+	}
+	record := &redactRecord{
+		Fields:    strings.Split(string(args["fields"]), ","),
+		PublicKey: string(args["public_key"]),
+		Sign:      string(args["sign"]),
+	}
+	if err := ledger.VerifyRedactFields(record.Fields); err != nil {
+		return nil, err
+	}
+	if value, err := ctx.Get(RedactKernelContract, txid); err == nil && len(value) > 0 {
+		return nil, ledger.ErrTxAlreadyRedacted
+	}
+	tx, err := t.sctx.Ledger.QueryTransaction(txid)
+	if err != nil {
+		return nil, fmt.Errorf("redact tx failed, query tx error: %v", err)
+	}
+	if tx.Coinbase || tx.GetModifyBlock().GetMarked() {
+		return nil, ledger.ErrTxAlreadyRedacted
+	}
+	if err := t.verifyModifySign(record.PublicKey, record.Sign, ledger.RedactTx(tx, record.Fields)); err != nil {
+		return nil, err
+	}
+	value, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Put(RedactKernelContract, txid, value); err != nil {
+		return nil, err
+	}
+	return &contract.Response{
+		Status:  utils.StatusOK,
+		Message: "success",
+	}, nil
+}
+
+// verifyModifySign 校验监管地址的公钥和对交易摘要的签名
+func (t *State) verifyModifySign(publicKey string, sign string, tx *pb.Transaction) error {
+	err := ledger.VerifyModifySign(t.utxo.ModifyBlockAddr, publicKey, sign, tx)
+	if err != nil {
+		t.log.Warn("verifyModifySign failed", "error", err)
+	}
+	return err
+}
+
+// loadRedactHeight 读取脱敏进度，没有记录时从当前不可逆高度开始，之前的记录已在区块执行时处理
+func (t *State) loadRedactHeight() (int64, error) {
+	value, err := t.meta.MetaTable.Get([]byte(RedactHeightKey))
+	if def.NormalizedKVError(err) == def.ErrKVNotFound {
+		return t.meta.GetIrreversibleBlockHeight(), nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(value), 10, 64)
+}
+
+// applyRedactions 按新增不可逆区块中的脱敏记录修改本地账本
+// 先修改账本再记录进度，中断后重复处理不影响已脱敏的交易
+func (t *State) applyRedactions() {
+	irreversibleHeight := t.meta.GetIrreversibleBlockHeight()
+	for height := t.redactHeight + 1; height <= irreversibleHeight; height++ {
+		block, err := t.sctx.Ledger.QueryBlockByHeight(height)
+		if err != nil {
+			t.log.Warn("query block failed when apply redactions", "height", height, "err", err)
+			return
+		}
+		t.redactBlock(block)
+		err = t.meta.MetaTable.Put([]byte(RedactHeightKey), []byte(strconv.FormatInt(height, 10)))
+		if err != nil {
+			t.log.Warn("save redact height failed", "height", height, "err", err)
+			return
+		}
+		t.redactHeight = height
+	}
+}
+
+// redactBlock 按区块中的脱敏记录修改本地账本
+func (t *State) redactBlock(block *pb.InternalBlock) {
+	for _, tx := range block.Transactions {
+		for _, txOutputExt := range tx.TxOutputsExt {
+			if txOutputExt.Bucket != RedactKernelContract || string(txOutputExt.Value) == xmodel.DelFlag {
+				continue
+			}
+			record := &redactRecord{}
+			if err := json.Unmarshal(txOutputExt.Value, record); err != nil {
+				t.log.Warn("parse redact record failed", "txid", hex.EncodeToString(txOutputExt.Key), "err", err)
+				continue
+			}
+			modify := &pb.ModifyBlock{
+				Marked:          true,
+				EffectiveTxid:   hex.EncodeToString(tx.Txid),
+				EffectiveHeight: block.Height,
+				PublicKey:       record.PublicKey,
+				Sign:            record.Sign,
+				RedactedFields:  record.Fields,
+			}
+			err := t.sctx.Ledger.RedactTransaction(txOutputExt.Key, modify)
+			if err != nil && err != ledger.ErrTxAlreadyRedacted {
+				t.log.Warn("redact transaction failed", "txid", hex.EncodeToString(txOutputExt.Key), "err", err)
+			}
+		}
+	}
+}
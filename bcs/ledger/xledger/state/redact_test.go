@@ -0,0 +1,120 @@
+package state
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	ledger_pkg "github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	crypto_client "github.com/xuperchain/xupercore/lib/crypto/client"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func TestRedactTx(t *testing.T) {
+	crypt, err := crypto_client.CreateCryptoClient(crypto_client.CryptoTypeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := crypt.GenerateKeyBySeed([]byte("redact-test-seed-000000000000000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, _ := crypt.GetAddressFromPublicKey(&key.PublicKey)
+	pubkey, _ := crypt.GetEcdsaPublicKeyJsonFormatStr(key)
+
+	ts := newTestState(t, `"modify_block_addr": "`+addr+`",`)
+	ledger, sta, rootBlock := ts.ledger, ts.state, ts.rootBlock
+
+	if sta.utxo.ModifyBlockAddr != addr {
+		t.Fatal("modify block addr should be loaded from genesis", sta.utxo.ModifyBlockAddr)
+	}
+
+	target := &pb.Transaction{
+		Version:   1,
+		Desc:      []byte("sensitive desc"),
+		Initiator: "alice",
+		ContractRequests: []*protos.InvokeRequest{
+			{ModuleName: "wasm", ContractName: "counter", MethodName: "increase",
+				Args: map[string][]byte{"key": []byte("sensitive args")}},
+		},
+	}
+	target.Txid, _ = txhash.MakeTransactionID(target)
+	block1 := confirmNonceBlock(t, ledger, sta, rootBlock.Blockid, target)
+
+	// 监管地址对脱敏后交易的摘要签名
+	fields := []string{ledger_pkg.RedactFieldDesc, ledger_pkg.RedactFieldContractArgs}
+	digest, _ := txhash.MakeTxDigestHash(ledger_pkg.RedactTx(target, fields))
+	sign, err := crypt.SignECDSA(key, digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sta.verifyModifySign(pubkey, hex.EncodeToString(sign), ledger_pkg.RedactTx(target, fields)); err != nil {
+		t.Fatal(err)
+	}
+	// 签名只覆盖指定字段被清除后的交易
+	if err := sta.verifyModifySign(pubkey, hex.EncodeToString(sign),
+		ledger_pkg.RedactTx(target, fields[:1])); err != ErrInvalidRedactSign {
+		t.Fatal("expect invalid redact sign", err)
+	}
+
+	record, _ := json.Marshal(&redactRecord{Fields: fields, PublicKey: pubkey, Sign: hex.EncodeToString(sign)})
+	redactTx := &pb.Transaction{
+		Version:      1,
+		TxOutputsExt: []*protos.TxOutputExt{{Bucket: RedactKernelContract, Key: target.Txid, Value: record}},
+	}
+	redactTx.Txid, _ = txhash.MakeTransactionID(redactTx)
+	block2 := confirmNonceBlock(t, ledger, sta, block1.Blockid, redactTx)
+	setIrreversible := func(height int64) {
+		sta.meta.MutexMeta.Lock()
+		sta.meta.Meta.IrreversibleBlockHeight = height
+		sta.meta.MutexMeta.Unlock()
+	}
+	// 脱敏记录所在区块不可逆之前不修改账本
+	setIrreversible(1)
+	sta.applyRedactions()
+	if tx, err := ledger.QueryTransaction(target.Txid); err != nil || ledger_pkg.IsRedactedTx(tx) {
+		t.Fatal("tx should not be redacted before irreversible", err)
+	}
+	setIrreversible(2)
+	sta.applyRedactions()
+	if height, err := sta.loadRedactHeight(); err != nil || height != 2 {
+		t.Fatal("unexpected redact height", height, err)
+	}
+	redacted, err := ledger.QueryTransaction(target.Txid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ledger_pkg.IsRedactedTx(redacted) || len(redacted.Desc) > 0 || len(redacted.ContractRequests[0].Args) > 0 ||
+		redacted.ContractRequests[0].MethodName != "increase" || !bytes.Equal(redacted.Txid, target.Txid) {
+		t.Fatal("unexpected redacted tx", redacted)
+	}
+	if redacted.ModifyBlock.EffectiveHeight != 2 || redacted.ModifyBlock.EffectiveTxid != hex.EncodeToString(redactTx.Txid) {
+		t.Fatal("unexpected modify block", redacted.ModifyBlock)
+	}
+	// 区块中的交易同样被脱敏
+	block, err := ledger.QueryBlock(block1.Blockid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ledger_pkg.IsRedactedTx(block.Transactions[0]) {
+		t.Fatal("tx in block should be redacted")
+	}
+	// 重复执行不影响已脱敏的交易
+	sta.redactBlock(block2)
+
+	if err := sta.verifyMarkedTx(redacted); err != nil {
+		t.Fatal(err)
+	}
+	redacted.Initiator = "bob"
+	if err := sta.verifyMarkedTx(redacted); err == nil {
+		t.Fatal("modified tx should not pass verification")
+	}
+	redacted.Initiator = "alice"
+	redacted.Desc = []byte("sensitive desc")
+	if err := sta.verifyMarkedTx(redacted); err != ledger_pkg.ErrRedactedTxMismatch {
+		t.Fatal("expect redacted tx mismatch", err)
+	}
+}
@@ -0,0 +1,231 @@
+package state
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo"
+	txn "github.com/xuperchain/xupercore/bcs/ledger/xledger/tx"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+)
+
+// syncBatch 多个冲突组并发写入同一个batch时加锁
+type syncBatch struct {
+	kvdb.Batch
+	mutex sync.Mutex
+}
+
+func (b *syncBatch) ValueSize() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.Batch.ValueSize()
+}
+
+func (b *syncBatch) Put(key []byte, value []byte) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.Batch.Put(key, value)
+}
+
+func (b *syncBatch) Delete(key []byte) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.Batch.Delete(key)
+}
+
+func (b *syncBatch) PutIfAbsent(key []byte, value []byte) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.Batch.PutIfAbsent(key, value)
+}
+
+func (b *syncBatch) Exist(key []byte) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.Batch.Exist(key)
+}
+
+// 执行区块时交易的校验(签名、权限、合约重新执行)是最耗时的部分，按读写集冲突把交易分组后并发校验，
+// 组内按区块中的顺序校验；并发校验只能看到已提交的状态，依赖同一区块中前面交易的校验可能失败，
+// 这些交易在写入阶段按顺序重新校验。
+//
+// 校验后各组交易也并发写入同一个batch：不同组的交易读写的key和引用的utxo互不相交，
+// 写入的key不重叠，组内仍按区块中的顺序写入，因此写入结果与串行执行一致。
+// 包含coinbase交易的组会修改utxo总量，在并发写入前按顺序串行执行；
+// 各组销毁的基础费用在全部组写入后一次性从utxo总量中扣除。
+
+// runConflictGroups 用最多workers个协程并发处理各组交易，返回位置最靠前的组的错误
+func runConflictGroups(workers int, groups [][]*pb.Transaction, f func(txs []*pb.Transaction) error) error {
+	if workers > len(groups) {
+		workers = len(groups)
+	}
+	if workers <= 1 {
+		for _, txs := range groups {
+			if err := f(txs); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	errs := make([]error, len(groups))
+	ch := make(chan int)
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range ch {
+				errs[idx] = f(groups[idx])
+			}
+		}()
+	}
+	for idx := range groups {
+		ch <- idx
+	}
+	close(ch)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// preVerifyBlockTxs 并发校验区块中的交易，返回校验通过的交易，串行模式下不预先校验
+func (t *State) preVerifyBlockTxs(block *pb.InternalBlock) map[string]bool {
+	verified := make(map[string]bool, len(block.Transactions))
+	if t.replayWorkers <= 1 {
+		return verified
+	}
+	var mutex sync.Mutex
+	groups := txn.SplitToConflictGroups(block.Transactions)
+	runConflictGroups(t.replayWorkers, groups, func(txs []*pb.Transaction) error {
+		for _, tx := range txs {
+			if err := t.verifyTxForWalk(block.Height, tx); err != nil {
+				t.log.Debug("pre verify tx failed, verify again in order", "txid", hex.EncodeToString(tx.Txid), "err", err)
+				continue
+			}
+			mutex.Lock()
+			verified[string(tx.Txid)] = true
+			mutex.Unlock()
+		}
+		return nil
+	})
+	return verified
+}
+
+// verifyTxForWalk 校验区块中的定时交易和普通交易
+func (t *State) verifyTxForWalk(height int64, tx *pb.Transaction) error {
+	showTxId := hex.EncodeToString(tx.Txid)
+	// 校验定时交易合法性
+	if t.verifyAutogenTxValid(tx) && !tx.Coinbase {
+		if ok, err := t.ImmediateVerifyAutoTx(height, tx, false); !ok {
+			return fmt.Errorf("immediate verify auto tx error.txid:%s,err:%v", showTxId, err)
+		}
+	}
+
+	// 校验普通交易合法性
+	if !tx.Autogen && !tx.Coinbase {
+		if ok, err := t.ImmediateVerifyTx(tx, false); !ok {
+			// 被监管修改过的交易通过监管签名校验
+			if ok, isRelyOnMarkedTx, _ := t.verifyMarked(tx); !isRelyOnMarkedTx || !ok {
+				return fmt.Errorf("immediate verify tx error.txid:%s,err:%v", showTxId, err)
+			}
+		}
+	}
+	return nil
+}
+
+// applyBlockTxsForWalk 执行区块中的交易并支付手续费，并发模式下按冲突组并发写入batch
+func (t *State) applyBlockTxsForWalk(block *pb.InternalBlock, batch kvdb.Batch,
+	verified map[string]bool, params *feeParams) error {
+	if t.replayWorkers <= 1 {
+		return t.applyTxsForWalk(block, block.Transactions, batch, verified, params, nil)
+	}
+
+	var serial, parallel [][]*pb.Transaction
+	for _, txs := range txn.SplitToConflictGroups(block.Transactions) {
+		if hasCoinbaseTx(txs) {
+			serial = append(serial, txs)
+			continue
+		}
+		parallel = append(parallel, txs)
+	}
+
+	sb := &syncBatch{Batch: batch}
+	t.xmodel.BindBatch(sb)
+	burnt := big.NewInt(0)
+	var mutex sync.Mutex
+	apply := func(txs []*pb.Transaction) error {
+		groupBurnt := big.NewInt(0)
+		if err := t.applyTxsForWalk(block, txs, sb, verified, params, groupBurnt); err != nil {
+			return err
+		}
+		mutex.Lock()
+		burnt.Add(burnt, groupBurnt)
+		mutex.Unlock()
+		return nil
+	}
+	if err := runConflictGroups(1, serial, apply); err != nil {
+		return err
+	}
+	if err := runConflictGroups(t.replayWorkers, parallel, apply); err != nil {
+		return err
+	}
+	// 基础费用被销毁
+	if burnt.Sign() > 0 {
+		t.utxo.UpdateUtxoTotal(burnt, sb, false)
+	}
+	return nil
+}
+
+// applyTxsForWalk 按顺序执行交易，burnt不为nil时累加销毁的基础费用而不更新utxo总量
+func (t *State) applyTxsForWalk(block *pb.InternalBlock, txs []*pb.Transaction, batch kvdb.Batch,
+	verified map[string]bool, params *feeParams, burnt *big.Int) error {
+	for _, tx := range txs {
+		showTxId := hex.EncodeToString(tx.Txid)
+		t.log.Debug("procTodoBlkForWalk", "txid", showTxId, "autogen", t.verifyAutogenTxValid(tx), "coinbase", tx.Coinbase)
+		// 预校验未通过的交易按顺序重新校验
+		if !verified[string(tx.Txid)] {
+			if err := t.verifyTxForWalk(block.Height, tx); err != nil {
+				return err
+			}
+		}
+
+		// 执行交易
+		cacheFiller := &utxo.CacheFiller{}
+		if err := t.doTxInternal(tx, batch, cacheFiller); err != nil {
+			return fmt.Errorf("todo tx fail.txid:%s,err:%v", showTxId, err)
+		}
+		cacheFiller.Commit()
+
+		// 处理小费
+		if burnt == nil {
+			if err := t.payFee(tx, batch, block, params); err != nil {
+				return fmt.Errorf("pay fee fail.txid:%s,err:%v", showTxId, err)
+			}
+			continue
+		}
+		txBurnt, err := t.payFeeUtxos(tx, batch, block, params)
+		if err != nil {
+			return fmt.Errorf("pay fee fail.txid:%s,err:%v", showTxId, err)
+		}
+		burnt.Add(burnt, txBurnt)
+	}
+	return nil
+}
+
+func hasCoinbaseTx(txs []*pb.Transaction) bool {
+	for _, tx := range txs {
+		if tx.Coinbase {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
+	txn "github.com/xuperchain/xupercore/bcs/ledger/xledger/tx"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+// makeReplayTxs 生成n个交易，每group个交易读写同一个key
+func makeReplayTxs(n int, group int) []*pb.Transaction {
+	txs := make([]*pb.Transaction, 0, n)
+	for i := 0; i < n; i++ {
+		key := []byte(fmt.Sprintf("key_%d", i/group))
+		txs = append(txs, &pb.Transaction{
+			Txid:         []byte(fmt.Sprintf("tx_%d", i)),
+			TxInputsExt:  []*protos.TxInputExt{{Bucket: "counter", Key: key}},
+			TxOutputsExt: []*protos.TxOutputExt{{Bucket: "counter", Key: key, Value: []byte("v")}},
+		})
+	}
+	return txs
+}
+
+func TestRunConflictGroups(t *testing.T) {
+	groups := txn.SplitToConflictGroups(makeReplayTxs(100, 4))
+	if len(groups) != 25 {
+		t.Fatalf("expect 25 groups, got %d", len(groups))
+	}
+	for _, workers := range []int{1, 4, 100} {
+		var count int64
+		err := runConflictGroups(workers, groups, func(txs []*pb.Transaction) error {
+			atomic.AddInt64(&count, int64(len(txs)))
+			return nil
+		})
+		if err != nil || count != 100 {
+			t.Fatalf("workers %d expect 100 txs, got %d, err %v", workers, count, err)
+		}
+	}
+
+	// 多个组失败时返回位置最靠前的组的错误
+	err := runConflictGroups(8, groups, func(txs []*pb.Transaction) error {
+		switch string(txs[0].Txid) {
+		case "tx_8", "tx_40":
+			return errors.New(string(txs[0].Txid))
+		}
+		return nil
+	})
+	if err == nil || err.Error() != "tx_8" {
+		t.Fatalf("expect error of tx_8, got %v", err)
+	}
+}
+
+// replayBlocks 生成两个区块：第一个区块把bob的utxo拆成n份，第二个区块的n个交易各花费其中一份转给alice，
+// 状态机执行到第一个区块
+func replayBlocks(tb testing.TB, n int) (*testState, *pb.InternalBlock, *pb.InternalBlock) {
+	ts := newTestState(tb, "")
+	ledger, sta := ts.ledger, ts.state
+
+	total := big.NewInt(0).SetBytes(ts.genesisTx.TxOutputs[0].Amount)
+	splitTx := &pb.Transaction{
+		Version:     1,
+		Nonce:       "split",
+		Initiator:   BobAddress,
+		AuthRequire: []string{BobAddress},
+		TxInputs: []*protos.TxInput{
+			{RefTxid: ts.genesisTx.Txid, FromAddr: []byte(BobAddress), Amount: total.Bytes()},
+		},
+	}
+	for i := 0; i < n; i++ {
+		splitTx.TxOutputs = append(splitTx.TxOutputs, &protos.TxOutput{ToAddr: []byte(BobAddress), Amount: big.NewInt(10).Bytes()})
+		total.Sub(total, big.NewInt(10))
+	}
+	splitTx.TxOutputs = append(splitTx.TxOutputs, &protos.TxOutput{ToAddr: []byte(BobAddress), Amount: total.Bytes()})
+	sign, err := txhash.ProcessSignTx(sta.sctx.Crypt, splitTx, []byte(BobPrivateKey))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	splitTx.InitiatorSigns = []*protos.SignatureInfo{{PublicKey: BobPubkey, Sign: sign}}
+	splitTx.AuthRequireSigns = splitTx.InitiatorSigns
+	splitTx.Txid, _ = txhash.MakeTransactionID(splitTx)
+	block1 := confirmNonceBlock(tb, ledger, sta, ts.rootBlock.Blockid, splitTx)
+	if err := sta.Play(block1.Blockid); err != nil {
+		tb.Fatal(err)
+	}
+
+	txs := make([]*pb.Transaction, 0, n)
+	for i := 0; i < n; i++ {
+		txs = append(txs, nonceTx(tb, sta, splitTx, int32(i), 1, fmt.Sprintf("replay_%d", i), 0))
+	}
+	block2 := confirmNonceBlock(tb, ledger, sta, block1.Blockid, txs...)
+	return ts, block1, block2
+}
+
+func TestReplayParallelApply(t *testing.T) {
+	ts, block1, block2 := replayBlocks(t, 50)
+	sta := ts.state
+
+	var totals, balances []string
+	for _, workers := range []int{1, 4} {
+		sta.replayWorkers = workers
+		if err := sta.Walk(block2.Blockid, false); err != nil {
+			t.Fatal(err)
+		}
+		balance, err := sta.GetBalance(AliceAddress)
+		if err != nil {
+			t.Fatal(err)
+		}
+		totals = append(totals, sta.GetTotal().String())
+		balances = append(balances, balance.String())
+		if err := sta.Walk(block1.Blockid, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if balances[0] != "50" || balances[1] != balances[0] || totals[1] != totals[0] {
+		t.Fatal("parallel apply differs from sequential", balances, totals)
+	}
+}
+
+// benchmarkReplayWalk 状态机在两个区块间来回切换，只统计Walk经procTodoBlkForWalk执行第二个区块的耗时
+func benchmarkReplayWalk(b *testing.B, workers int) {
+	ts, block1, block2 := replayBlocks(b, 500)
+	sta := ts.state
+	sta.replayWorkers = workers
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := sta.Walk(block2.Blockid, false); err != nil {
+			b.Fatal(err)
+		}
+		b.StopTimer()
+		if err := sta.Walk(block1.Blockid, false); err != nil {
+			b.Fatal(err)
+		}
+		b.StartTimer()
+	}
+}
+
+func BenchmarkReplayWalkSequential(b *testing.B) {
+	benchmarkReplayWalk(b, 1)
+}
+
+func BenchmarkReplayWalkParallel(b *testing.B) {
+	benchmarkReplayWalk(b, runtime.GOMAXPROCS(0))
+}
@@ -0,0 +1,95 @@
+package smt
+
+import (
+	"bytes"
+)
+
+// Proof key在某个状态根下的存在性或不存在性证明
+type Proof struct {
+	Key   []byte
+	Value []byte
+	// key是否存在，存在时Value为对应的值
+	Exist bool
+	// 自根节点向下路径上的兄弟节点哈希
+	Siblings [][]byte
+	// 不存在性证明中路径终点的另一个叶子，终点为空子树时均为空
+	LeafPath      []byte
+	LeafValueHash []byte
+}
+
+// Prove 生成key在root下的证明
+func Prove(store Store, root, key []byte) (*Proof, error) {
+	path := digest(key)
+	proof := &Proof{Key: key}
+	h := root
+	for depth := 0; depth < MaxDepth; depth++ {
+		if IsEmptyRoot(h) {
+			return proof, nil
+		}
+		n, err := loadNode(store, h)
+		if err != nil {
+			return nil, err
+		}
+		if n.leaf {
+			if !bytes.Equal(n.a, path) {
+				proof.LeafPath = n.a
+				proof.LeafValueHash = n.b
+				return proof, nil
+			}
+			value, err := store.Get(n.b)
+			if err != nil {
+				return nil, err
+			}
+			proof.Exist = true
+			proof.Value = value
+			return proof, nil
+		}
+		if bit(path, depth) == 0 {
+			proof.Siblings = append(proof.Siblings, n.b)
+			h = n.a
+		} else {
+			proof.Siblings = append(proof.Siblings, n.a)
+			h = n.b
+		}
+	}
+	return nil, ErrInvalidNode
+}
+
+// VerifyProof 校验证明是否与root一致
+func VerifyProof(root []byte, proof *Proof) bool {
+	if proof == nil || len(proof.Siblings) > MaxDepth {
+		return false
+	}
+	path := digest(proof.Key)
+	var h []byte
+	switch {
+	case proof.Exist:
+		h = leafHash(path, digest(proof.Value))
+	case len(proof.LeafPath) == 0:
+		h = emptyHash
+	default:
+		if len(proof.LeafPath) != HashLen || len(proof.LeafValueHash) != HashLen ||
+			bytes.Equal(proof.LeafPath, path) {
+			return false
+		}
+		// 终点叶子必须位于key的路径上
+		for i := range proof.Siblings {
+			if bit(proof.LeafPath, i) != bit(path, i) {
+				return false
+			}
+		}
+		h = leafHash(proof.LeafPath, proof.LeafValueHash)
+	}
+	for i := len(proof.Siblings) - 1; i >= 0; i-- {
+		sibling := proof.Siblings[i]
+		if len(sibling) != HashLen {
+			return false
+		}
+		if bit(path, i) == 0 {
+			h = internalHash(h, sibling)
+		} else {
+			h = internalHash(sibling, h)
+		}
+	}
+	return bytes.Equal(h, root)
+}
@@ -0,0 +1,261 @@
+// Package smt 实现用于状态承诺的稀疏merkle树(sparse merkle tree)
+//
+// 叶子在树中的路径由sha256(key)决定，只包含一个叶子的子树直接用该叶子表示，
+// 因此每次更新只会产生O(log n)个新节点。节点和值均按内容寻址存储(key为其哈希)，
+// 写入后不再修改，任意历史状态根都可以直接读取和生成证明，回滚只需切换根。
+package smt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+)
+
+const (
+	// HashLen 节点哈希长度
+	HashLen = sha256.Size
+	// MaxDepth 树的最大深度
+	MaxDepth = HashLen * 8
+
+	leafPrefix     byte = 0x00
+	internalPrefix byte = 0x01
+	nodeLen             = 1 + 2*HashLen
+)
+
+var (
+	ErrNodeNotFound = errors.New("smt: node not found")
+	ErrInvalidNode  = errors.New("smt: invalid node")
+)
+
+// emptyHash 空子树的哈希
+var emptyHash = make([]byte, HashLen)
+
+// Store 节点存储，按哈希读取已持久化的节点或值
+type Store interface {
+	Get(hash []byte) ([]byte, error)
+}
+
+// EmptyRoot 返回空树的根
+func EmptyRoot() []byte {
+	return make([]byte, HashLen)
+}
+
+// IsEmptyRoot 判断是否为空树的根
+func IsEmptyRoot(root []byte) bool {
+	return bytes.Equal(root, emptyHash)
+}
+
+func digest(data []byte) []byte {
+	h := sha256.Sum256(data)
+	return h[:]
+}
+
+// bit 返回path第i位，高位在前
+func bit(path []byte, i int) byte {
+	return (path[i/8] >> (7 - uint(i%8))) & 1
+}
+
+type node struct {
+	leaf bool
+	// 叶子节点: a为key路径, b为value哈希; 内部节点: a为左孩子, b为右孩子
+	a, b []byte
+}
+
+func encodeNode(prefix byte, a, b []byte) []byte {
+	buf := make([]byte, 0, nodeLen)
+	buf = append(buf, prefix)
+	buf = append(buf, a...)
+	return append(buf, b...)
+}
+
+func decodeNode(data []byte) (*node, error) {
+	if len(data) != nodeLen || (data[0] != leafPrefix && data[0] != internalPrefix) {
+		return nil, ErrInvalidNode
+	}
+	return &node{
+		leaf: data[0] == leafPrefix,
+		a:    data[1 : 1+HashLen],
+		b:    data[1+HashLen:],
+	}, nil
+}
+
+func leafHash(path, valueHash []byte) []byte {
+	return digest(encodeNode(leafPrefix, path, valueHash))
+}
+
+func internalHash(left, right []byte) []byte {
+	return digest(encodeNode(internalPrefix, left, right))
+}
+
+func loadNode(store Store, h []byte) (*node, error) {
+	data, err := store.Get(h)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, ErrNodeNotFound
+	}
+	return decodeNode(data)
+}
+
+// Tree 基于某个状态根的一次更新，新产生的节点暂存在内存中，由Commit交给调用方持久化
+type Tree struct {
+	store Store
+	root  []byte
+	dirty map[string][]byte
+}
+
+// NewTree 基于root打开一棵树，root为空时表示空树
+func NewTree(store Store, root []byte) *Tree {
+	if len(root) == 0 {
+		root = EmptyRoot()
+	}
+	return &Tree{
+		store: store,
+		root:  root,
+		dirty: make(map[string][]byte),
+	}
+}
+
+// Root 返回当前的根
+func (t *Tree) Root() []byte {
+	return t.root
+}
+
+// Update 写入key对应的value
+func (t *Tree) Update(key, value []byte) error {
+	path := digest(key)
+	valueHash := digest(value)
+	t.dirty[string(valueHash)] = append([]byte{}, value...)
+	leaf := t.putNode(leafPrefix, path, valueHash)
+	root, err := t.update(t.root, path, leaf, 0)
+	if err != nil {
+		return err
+	}
+	t.root = root
+	return nil
+}
+
+// Delete 删除key，key不存在时不改变根
+func (t *Tree) Delete(key []byte) error {
+	root, err := t.update(t.root, digest(key), nil, 0)
+	if err != nil {
+		return err
+	}
+	t.root = root
+	return nil
+}
+
+// Commit 返回当前根可达的新节点和值(哈希 -> 内容)，调用方需将其持久化
+func (t *Tree) Commit() map[string][]byte {
+	nodes := make(map[string][]byte)
+	t.collect(t.root, nodes)
+	t.dirty = make(map[string][]byte)
+	return nodes
+}
+
+func (t *Tree) collect(h []byte, nodes map[string][]byte) {
+	data, ok := t.dirty[string(h)]
+	if !ok {
+		return
+	}
+	nodes[string(h)] = data
+	n, _ := decodeNode(data)
+	if n.leaf {
+		if v, ok := t.dirty[string(n.b)]; ok {
+			nodes[string(n.b)] = v
+		}
+		return
+	}
+	t.collect(n.a, nodes)
+	t.collect(n.b, nodes)
+}
+
+func (t *Tree) putNode(prefix byte, a, b []byte) []byte {
+	data := encodeNode(prefix, a, b)
+	h := digest(data)
+	t.dirty[string(h)] = data
+	return h
+}
+
+func (t *Tree) load(h []byte) (*node, error) {
+	if data, ok := t.dirty[string(h)]; ok {
+		return decodeNode(data)
+	}
+	return loadNode(t.store, h)
+}
+
+// update 在以h为根、深度为depth的子树中将path处替换为leaf(nil表示删除)，返回新的子树根
+func (t *Tree) update(h, path, leaf []byte, depth int) ([]byte, error) {
+	if IsEmptyRoot(h) {
+		if leaf == nil {
+			return emptyHash, nil
+		}
+		return leaf, nil
+	}
+	n, err := t.load(h)
+	if err != nil {
+		return nil, err
+	}
+	if n.leaf {
+		if bytes.Equal(n.a, path) {
+			if leaf == nil {
+				return emptyHash, nil
+			}
+			return leaf, nil
+		}
+		if leaf == nil {
+			return h, nil
+		}
+		return t.split(h, n.a, leaf, path, depth), nil
+	}
+
+	left, right := n.a, n.b
+	if bit(path, depth) == 0 {
+		left, err = t.update(left, path, leaf, depth+1)
+	} else {
+		right, err = t.update(right, path, leaf, depth+1)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t.join(left, right)
+}
+
+// join 生成内部节点，只包含一个叶子的子树收缩为该叶子
+func (t *Tree) join(left, right []byte) ([]byte, error) {
+	leftEmpty, rightEmpty := IsEmptyRoot(left), IsEmptyRoot(right)
+	if leftEmpty && rightEmpty {
+		return emptyHash, nil
+	}
+	if leftEmpty || rightEmpty {
+		child := left
+		if leftEmpty {
+			child = right
+		}
+		n, err := t.load(child)
+		if err != nil {
+			return nil, err
+		}
+		if n.leaf {
+			return child, nil
+		}
+	}
+	return t.putNode(internalPrefix, left, right), nil
+}
+
+// split 两个路径不同的叶子a、b在depth处开始分叉
+func (t *Tree) split(a, aPath, b, bPath []byte, depth int) []byte {
+	aBit, bBit := bit(aPath, depth), bit(bPath, depth)
+	if aBit != bBit {
+		if aBit == 0 {
+			return t.putNode(internalPrefix, a, b)
+		}
+		return t.putNode(internalPrefix, b, a)
+	}
+	child := t.split(a, aPath, b, bPath, depth+1)
+	if aBit == 0 {
+		return t.putNode(internalPrefix, child, emptyHash)
+	}
+	return t.putNode(internalPrefix, emptyHash, child)
+}
@@ -0,0 +1,134 @@
+package smt
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+type mapStore map[string][]byte
+
+func (s mapStore) Get(hash []byte) ([]byte, error) {
+	data, ok := s[string(hash)]
+	if !ok {
+		return nil, ErrNodeNotFound
+	}
+	return data, nil
+}
+
+func (s mapStore) commit(t *Tree) {
+	for k, v := range t.Commit() {
+		s[k] = v
+	}
+}
+
+func TestUpdateOrderIndependent(t *testing.T) {
+	store1, store2 := mapStore{}, mapStore{}
+	t1, t2 := NewTree(store1, nil), NewTree(store2, nil)
+	if !IsEmptyRoot(t1.Root()) {
+		t.Fatal("new tree should be empty")
+	}
+	n := 100
+	for i := 0; i < n; i++ {
+		if err := t1.Update([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store1.commit(t1)
+	for i := n - 1; i >= 0; i-- {
+		if err := t2.Update([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i))); err != nil {
+			t.Fatal(err)
+		}
+		// 中途提交不影响最终结果
+		if i%10 == 0 {
+			store2.commit(t2)
+		}
+	}
+	if !bytes.Equal(t1.Root(), t2.Root()) {
+		t.Fatal("root should not depend on update order")
+	}
+
+	// 删除后回到原来的根
+	root := t1.Root()
+	t1 = NewTree(store1, root)
+	if err := t1.Update([]byte("extra"), []byte("v")); err != nil {
+		t.Fatal(err)
+	}
+	if err := t1.Delete([]byte("extra")); err != nil {
+		t.Fatal(err)
+	}
+	if err := t1.Delete([]byte("not exist")); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(t1.Root(), root) {
+		t.Fatal("root should be restored after delete")
+	}
+	for i := 0; i < n; i++ {
+		if err := t1.Delete([]byte(fmt.Sprintf("key%d", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !IsEmptyRoot(t1.Root()) {
+		t.Fatal("tree should be empty after deleting all keys")
+	}
+}
+
+func TestProof(t *testing.T) {
+	store := mapStore{}
+	tree := NewTree(store, nil)
+	for i := 0; i < 50; i++ {
+		tree.Update([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i)))
+	}
+	tree.Update([]byte("empty"), []byte{})
+	store.commit(tree)
+	root := tree.Root()
+
+	proof, err := Prove(store, root, []byte("key7"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proof.Exist || string(proof.Value) != "value7" {
+		t.Fatalf("unexpected proof value: %v %s", proof.Exist, proof.Value)
+	}
+	if !VerifyProof(root, proof) {
+		t.Fatal("inclusion proof should be valid")
+	}
+	proof.Value = []byte("value8")
+	if VerifyProof(root, proof) {
+		t.Fatal("tampered value should be rejected")
+	}
+
+	proof, err = Prove(store, root, []byte("empty"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proof.Exist || len(proof.Value) != 0 || !VerifyProof(root, proof) {
+		t.Fatal("empty value proof should be valid")
+	}
+
+	for i := 0; i < 20; i++ {
+		key := []byte(fmt.Sprintf("absent%d", i))
+		proof, err = Prove(store, root, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proof.Exist || !VerifyProof(root, proof) {
+			t.Fatal("non-inclusion proof should be valid")
+		}
+		proof.Exist = true
+		if VerifyProof(root, proof) {
+			t.Fatal("forged inclusion should be rejected")
+		}
+	}
+
+	// 历史根仍然可以生成证明
+	tree.Update([]byte("key7"), []byte("new"))
+	store.commit(tree)
+	proof, err = Prove(store, root, []byte("key7"))
+	if err != nil || string(proof.Value) != "value7" || !VerifyProof(root, proof) {
+		t.Fatal("proof against old root should still work")
+	}
+	if VerifyProof(tree.Root(), proof) {
+		t.Fatal("old proof should not match new root")
+	}
+}
@@ -7,9 +7,12 @@ import (
 	"errors"
 	"fmt"
 	pb2 "github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
+	"math"
 	"math/big"
 	"path/filepath"
+	"runtime"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -48,6 +51,7 @@ var (
 	ErrRWSetInvalid         = errors.New("RWSet of transaction invalid")
 	ErrACLNotEnough         = errors.New("ACL not enough")
 	ErrInvalidSignature     = errors.New("the signature is invalid or not match the address")
+	ErrContractAbiNotFound  = errors.New("contract abi not found")
 
 	ErrGasNotEnough   = errors.New("Gas not enough")
 	ErrVersionInvalid = errors.New("Invalid tx version")
@@ -84,10 +88,25 @@ type State struct {
 	ldb           kvdb.Database
 	latestBlockid []byte
 
+	// 状态树节点表，以及分叉前最后一个区块和最近计算的区块状态根的缓存
+	stateTree   kvdb.Database
+	forkRoot    *stateRootResult
+	pendingRoot *stateRootResult
+	rootMutex   sync.Mutex
+
 	// 最新区块高度通知装置
 	heightNotifier *BlockHeightNotifier
+	// 状态机更新到新区块后的回调
+	playHook PlayHook
+	// 执行区块时并发校验交易的协程数
+	replayWorkers int
+	// 已处理脱敏记录的不可逆区块高度
+	redactHeight int64
 }
 
+// PlayHook 状态机更新到区块后调用，调用时持有状态机锁，不能阻塞或调用需要状态机锁的方法
+type PlayHook func(height int64, blockid []byte)
+
 func NewState(sctx *context.StateCtx) (*State, error) {
 	if sctx == nil {
 		return nil, fmt.Errorf("create state failed because context set error")
@@ -130,6 +149,7 @@ func NewState(sctx *context.StateCtx) (*State, error) {
 	if err != nil {
 		return nil, fmt.Errorf("create state failed because create utxo error:%s", err)
 	}
+	obj.utxo.SetModifyBlockAddr(sctx.Ledger.GetGenesisBlock().GetConfig().ModifyBlockAddr)
 
 	obj.tx, err = tx.NewTx(sctx, obj.ldb)
 	if err != nil {
@@ -151,6 +171,18 @@ func NewState(sctx *context.StateCtx) (*State, error) {
 	}
 
 	obj.heightNotifier = NewBlockHeightNotifier()
+	obj.replayWorkers = sctx.LedgerCfg.ReplayWorkers
+	if obj.replayWorkers <= 0 {
+		obj.replayWorkers = runtime.NumCPU()
+	}
+
+	obj.stateTree = kvdb.NewTable(obj.ldb, pb.StateTreeTablePrefix)
+	sctx.Ledger.SetStateRootFunc(obj.CalcStateRoot)
+
+	obj.redactHeight, err = obj.loadRedactHeight()
+	if err != nil {
+		return nil, err
+	}
 
 	return obj, nil
 }
@@ -161,6 +193,9 @@ func (t *State) SetAclMG(aclMgr aclBase.AclManager) {
 
 func (t *State) SetContractMG(contractMgr contract.Manager) {
 	t.sctx.SetContractMG(contractMgr)
+	t.registerAwardMethods(contractMgr)
+	t.registerRedactMethods(contractMgr)
+	t.registerStorageMethods(contractMgr)
 }
 
 func (t *State) SetGovernTokenMG(governTokenMgr governToken.GovManager) {
@@ -228,6 +263,77 @@ func (t *State) GetContractStatus(contractName string) (*protos.ContractStatus,
 	return res, nil
 }
 
+// QueryContractHistory 查询合约的版本历史，txid和高度取自写入版本记录的交易
+func (t *State) QueryContractHistory(contractName string) ([]*protos.ContractVersion, error) {
+	it, err := t.xmodel.Select("contract", bridge.ContractHistoryKey(contractName, 0),
+		bridge.ContractHistoryKey(contractName, math.MaxInt64))
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	var history []*protos.ContractVersion
+	for it.Next() {
+		verdata := it.Value()
+		value := verdata.GetPureData().GetValue()
+		if len(value) == 0 || bytes.Equal(value, []byte(xmodel.DelFlag)) {
+			continue
+		}
+		version := &protos.ContractVersion{}
+		if err := proto.Unmarshal(value, version); err != nil {
+			return nil, err
+		}
+		version.Txid = hex.EncodeToString(verdata.GetRefTxid())
+		tx, _, err := t.xmodel.QueryTx(verdata.GetRefTxid())
+		if err != nil {
+			t.log.Warn("QueryContractHistory query tx error", "txid", version.Txid, "error", err)
+			return nil, err
+		}
+		if block, err := t.sctx.Ledger.QueryBlockHeader(tx.GetBlockid()); err == nil {
+			version.Height = block.GetHeight()
+		}
+		history = append(history, version)
+	}
+	if it.Error() != nil {
+		return nil, it.Error()
+	}
+	return history, nil
+}
+
+// QueryContractAbi 查询合约部署或升级时保存的ABI
+func (t *State) QueryContractAbi(contractName string) (*protos.ContractAbi, error) {
+	descData, err := t.xmodel.Get("contract", bridge.ContractCodeDescKey(contractName))
+	if err != nil {
+		return nil, err
+	}
+	descBuf := descData.GetPureData().GetValue()
+	if len(descBuf) == 0 || bytes.Equal(descBuf, []byte(xmodel.DelFlag)) {
+		return nil, ErrContractAbiNotFound
+	}
+	desc := &protos.WasmCodeDesc{}
+	if err := proto.Unmarshal(descBuf, desc); err != nil {
+		return nil, err
+	}
+
+	abiData, err := t.xmodel.Get("contract", bridge.ContractAbiKey(contractName))
+	if err != nil {
+		return nil, err
+	}
+	abiBuf := abiData.GetPureData().GetValue()
+	if len(abiBuf) == 0 || bytes.Equal(abiBuf, []byte(xmodel.DelFlag)) {
+		return nil, ErrContractAbiNotFound
+	}
+	contractType := desc.GetContractType()
+	if contractType == "" {
+		contractType = string(bridge.TypeWasm)
+	}
+	return &protos.ContractAbi{
+		ContractName: contractName,
+		ContractType: contractType,
+		Abi:          abiBuf,
+	}, nil
+}
+
 func (t *State) QueryAccountACL(accountName string) (*protos.Acl, error) {
 	return t.sctx.AclMgr.GetAccountACL(accountName)
 }
@@ -334,6 +440,13 @@ func (t *State) VerifyTx(tx *pb.Transaction) (bool, error) {
 			return ok, err
 		}
 	}
+	// 交易进入交易池时按已确认的用量检查配额，区块中的交易按累计用量另行检查
+	if err == nil && isValid {
+		if err := t.checkStorageQuota(tx); err != nil {
+			t.log.Warn("check storage quota failed", "txid", utils.F(tx.Txid), "err", err)
+			return false, err
+		}
+	}
 	return isValid, err
 }
 
@@ -414,6 +527,10 @@ func (t *State) PlayForMiner(blockid []byte) error {
 			t.clearBalanceCache()
 		}
 	}()
+	params := t.currentFeeParams()
+	if err = t.saveFeeParams(block, batch); err != nil {
+		return err
+	}
 	for _, tx := range block.Transactions {
 		txid := string(tx.Txid)
 		if tx.Coinbase || tx.Autogen {
@@ -425,16 +542,32 @@ func (t *State) PlayForMiner(blockid []byte) error {
 		} else {
 			batch.Delete(append([]byte(pb.UnconfirmedTablePrefix), []byte(txid)...))
 		}
-		err = t.payFee(tx, batch, block)
+		err = t.payFee(tx, batch, block, params)
 		if err != nil {
 			t.log.Warn("payFee failed", "feeErr", err)
 			return err
 		}
 	}
 	timer.Mark("do_tx")
+	if err = t.updateStorageUsage(block, batch, false); err != nil {
+		return err
+	}
+	err = t.verifyStateRoot(block, batch)
+	if err != nil {
+		return err
+	}
+	timer.Mark("verify_state_root")
+	// 矿工不经过processUnconfirmTxs，需要单独回滚过期的未确认交易
+	undoDone, err := t.undoExpiredTxs(block, batch)
+	if err != nil {
+		return err
+	}
 	// 更新不可逆区块高度
 	curIrreversibleBlockHeight := t.meta.GetIrreversibleBlockHeight()
 	curIrreversibleSlideWindow := t.meta.GetIrreversibleSlideWindow()
+	if err = t.pruneNonces(curIrreversibleBlockHeight, batch); err != nil {
+		return err
+	}
 	updateErr := t.meta.UpdateNextIrreversibleBlockHeight(block.Height, curIrreversibleBlockHeight, curIrreversibleSlideWindow, batch)
 	if updateErr != nil {
 		return updateErr
@@ -449,11 +582,16 @@ func (t *State) PlayForMiner(blockid []byte) error {
 	for _, tx := range block.Transactions {
 		t.tx.UnconfirmTxInMem.Delete(string(tx.Txid))
 	}
+	for txid := range undoDone {
+		t.tx.UnconfirmTxInMem.Delete(txid)
+	}
 	// 内存级别更新UtxoMeta信息
 	t.meta.MutexMeta.Lock()
 	newMeta := proto.Clone(t.meta.MetaTmp).(*pb.UtxoMeta)
 	t.meta.Meta = newMeta
 	t.meta.MutexMeta.Unlock()
+	t.pruneLedger()
+	t.applyRedactions()
 	t.log.Info("play for miner", "height", block.Height, "blockId", utils.F(block.Blockid), "costs", timer.Print())
 	return nil
 }
@@ -484,6 +622,13 @@ func (t *State) PlayAndRepost(blockid []byte, needRepost bool, isRootTx bool) er
 		return err
 	}
 
+	if err := t.verifyBlockNonces(block, undoDone); err != nil {
+		return err
+	}
+	if err := t.verifyAwardTx(block); err != nil {
+		return err
+	}
+
 	// parallel verify
 	verifyErr := t.verifyBlockTxs(block, isRootTx, unconfirmToConfirm)
 	timer.Mark("verify_block_txs")
@@ -492,7 +637,14 @@ func (t *State) PlayAndRepost(blockid []byte, needRepost bool, isRootTx bool) er
 		return verifyErr
 	}
 	t.log.Debug("play and repost verify block tx succ")
+	if err := t.verifyBlockStorageQuota(block); err != nil {
+		return err
+	}
 
+	params := t.currentFeeParams()
+	if err := t.saveFeeParams(block, batch); err != nil {
+		return err
+	}
 	for idx := 0; idx < len(block.Transactions); idx++ {
 		tx := block.Transactions[idx]
 		txid := string(tx.Txid)
@@ -505,16 +657,26 @@ func (t *State) PlayAndRepost(blockid []byte, needRepost bool, isRootTx bool) er
 			}
 			cacheFiller.Commit()
 		}
-		feeErr := t.payFee(tx, batch, block)
+		feeErr := t.payFee(tx, batch, block, params)
 		if feeErr != nil {
 			t.log.Warn("payFee failed", "feeErr", feeErr)
 			return feeErr
 		}
 	}
 	timer.Mark("do_tx")
+	if err := t.updateStorageUsage(block, batch, false); err != nil {
+		return err
+	}
+	if rootErr := t.verifyStateRoot(block, batch); rootErr != nil {
+		return rootErr
+	}
+	timer.Mark("verify_state_root")
 	// 更新不可逆区块高度
 	curIrreversibleBlockHeight := t.meta.GetIrreversibleBlockHeight()
 	curIrreversibleSlideWindow := t.meta.GetIrreversibleSlideWindow()
+	if err := t.pruneNonces(curIrreversibleBlockHeight, batch); err != nil {
+		return err
+	}
 	updateErr := t.meta.UpdateNextIrreversibleBlockHeight(block.Height, curIrreversibleBlockHeight, curIrreversibleSlideWindow, batch)
 	if updateErr != nil {
 		return updateErr
@@ -537,6 +699,8 @@ func (t *State) PlayAndRepost(blockid []byte, needRepost bool, isRootTx bool) er
 	newMeta := proto.Clone(t.meta.MetaTmp).(*pb.UtxoMeta)
 	t.meta.Meta = newMeta
 	t.meta.MutexMeta.Unlock()
+	t.pruneLedger()
+	t.applyRedactions()
 
 	t.log.Info("play and repost", "height", block.Height, "blockId", utils.F(block.Blockid), "unconfirmed", len(unconfirmToConfirm), "undo", len(undoDone), "costs", timer.Print())
 	return nil
@@ -544,13 +708,17 @@ func (t *State) PlayAndRepost(blockid []byte, needRepost bool, isRootTx bool) er
 
 func (t *State) GetTimerTx(blockHeight int64) (*pb.Transaction, error) {
 	stateConfig := &contract.SandboxConfig{
-		XMReader:   t.CreateXMReader(),
-		UTXOReader: t.CreateUtxoReader(),
+		XMReader:           t.CreateXMReader(),
+		UTXOReader:         t.CreateUtxoReader(),
+		ContractCallRevert: t.ContractCallRevertEnabled(),
 	}
 	if !t.sctx.IsInit() {
 		return nil, nil
 	}
 	t.log.Info("GetTimerTx", "blockHeight", blockHeight)
+	if t.sctx.Ledger.GetGenesisBlock().GetConfig().IsParallelTimerTaskEnabled(blockHeight) {
+		return t.getParallelTimerTx(blockHeight)
+	}
 	sandBox, err := t.sctx.ContractMgr.NewStateSandbox(stateConfig)
 	if err != nil {
 		t.log.Error("PreExec new state sandbox error", "error", err)
@@ -705,6 +873,8 @@ func (t *State) Walk(blockid []byte, ledgerPrune bool) error {
 		return fmt.Errorf("walk todo block fail")
 	}
 	xTimer.Mark("walk_todo_block")
+	t.pruneLedger()
+	t.applyRedactions()
 
 	// 异步回放被回滚未确认交易
 	go t.recoverUnconfirmedTx(undoList)
@@ -714,6 +884,11 @@ func (t *State) Walk(blockid []byte, ledgerPrune bool) error {
 	return nil
 }
 
+// pruneLedger 不可逆区块高度更新后通知账本在后台裁剪历史区块，不阻塞区块执行
+func (t *State) pruneLedger() {
+	t.sctx.Ledger.SchedulePrune(t.meta.GetIrreversibleBlockHeight())
+}
+
 // 查询交易
 func (t *State) QueryTx(txid []byte) (*pb.Transaction, bool, error) {
 	return t.xmodel.QueryTx(txid)
@@ -773,6 +948,22 @@ func (t *State) doTxSync(tx *pb.Transaction) error {
 		t.log.Debug("this tx already in unconfirm table, when DoTx", "txid", utils.F(tx.Txid))
 		return ErrAlreadyInUnconfirmed
 	}
+	if tx.ValidUntilHeight > 0 {
+		height, err := t.latestHeight()
+		if err != nil {
+			return err
+		}
+		if err := t.checkNonce(tx, height+1, nil); err != nil {
+			return err
+		}
+	}
+	baseFee, err := t.NextBaseFee()
+	if err != nil {
+		return err
+	}
+	if err := t.VerifyTxBaseFee(tx, baseFee); err != nil {
+		return err
+	}
 	batch := t.ldb.NewBatch()
 	cacheFiller := &utxo.CacheFiller{}
 	beginTime := time.Now()
@@ -813,6 +1004,7 @@ func (t *State) doTxInternal(tx *pb.Transaction, batch kvdb.Batch, cacheFiller *
 		t.log.Warn("xmodel DoTx failed", "err", err)
 		return ErrRWSetInvalid
 	}
+	putNonce(tx, batch)
 	for _, txInput := range tx.TxInputs {
 		addr := txInput.FromAddr
 		txid := txInput.RefTxid
@@ -861,6 +1053,20 @@ func (t *State) NewBatch() kvdb.Batch {
 	return t.ldb.NewBatch()
 }
 
+// Quiesce 暂停状态机和账本的更新执行f，用于获取账本和状态机一致的快照
+// f中不能调用需要状态机锁或账本锁的方法
+func (t *State) Quiesce(f func() error) error {
+	t.utxo.Mutex.Lock()
+	defer t.utxo.Mutex.Unlock()
+
+	return t.sctx.Ledger.Quiesce(f)
+}
+
+// SetPlayHook 设置状态机更新到新区块后的回调
+func (t *State) SetPlayHook(f PlayHook) {
+	t.playHook = f
+}
+
 func (t *State) GetLDB() kvdb.Database {
 	return t.ldb
 }
@@ -968,6 +1174,7 @@ func (t *State) undoTxInternal(tx *pb.Transaction, batch kvdb.Batch) error {
 		t.log.Warn("xmodel.UndoTx failed", "err", err)
 		return ErrRWSetInvalid
 	}
+	deleteNonce(tx, batch)
 
 	for _, txInput := range tx.TxInputs {
 		addr := txInput.FromAddr
@@ -1040,6 +1247,13 @@ func (t *State) procUndoBlkForWalk(undoBlocks []*pb.InternalBlock,
 
 		// 将batch赋值到合约机的上下文
 		batch := t.ldb.NewBatch()
+		// 按区块执行时记录的参数回滚手续费
+		var params *feeParams
+		params, err = t.blockFeeParams(undoBlk)
+		if err != nil {
+			return fmt.Errorf("load fee params fail.blockid:%s,err:%v", showBlkId, err)
+		}
+		batch.Delete(feeParamsKey(undoBlk.Blockid))
 		// 倒序回滚交易
 		for i := len(undoBlk.Transactions) - 1; i >= 0; i-- {
 			tx = undoBlk.Transactions[i]
@@ -1054,12 +1268,17 @@ func (t *State) procUndoBlkForWalk(undoBlocks []*pb.InternalBlock,
 			}
 
 			// 回滚小费，undoTxInternal不会滚小费
-			err = t.undoPayFee(tx, batch, undoBlk)
+			err = t.undoPayFee(tx, batch, undoBlk, params)
 			if err != nil {
 				return fmt.Errorf("undo fee fail.txid:%s,err:%v", showTxId, err)
 			}
 		}
 
+		// 回滚合约存储用量
+		if err = t.updateStorageUsage(undoBlk, batch, true); err != nil {
+			return fmt.Errorf("undo storage usage fail.blockid:%s,err:%v", showBlkId, err)
+		}
+
 		// 账本裁剪时，无视区块不可逆原则
 		if ledgerPrune {
 			curIrreversibleBlockHeight := t.meta.GetIrreversibleBlockHeight()
@@ -1072,6 +1291,7 @@ func (t *State) procUndoBlkForWalk(undoBlocks []*pb.InternalBlock,
 		}
 
 		// 更新utxoVM LatestBlockid，这里是回滚，所以是更新为上一个区块
+		// 状态树节点不会被修改，状态根随之回到上一个区块的状态根
 		err = t.updateLatestBlockid(undoBlk.PreHash, batch, "error occurs when undo blocks")
 		if err != nil {
 			return fmt.Errorf("update latest blockid fail.latest_blockid:%s,err:%v",
@@ -1105,23 +1325,29 @@ func (t *State) updateLatestBlockid(newBlockid []byte, batch kvdb.Batch, reason
 	}
 	t.latestBlockid = newBlockid
 	t.heightNotifier.UpdateHeight(blk.GetHeight())
+	if t.playHook != nil {
+		t.playHook(blk.GetHeight(), newBlockid)
+	}
 	return nil
 }
 
-func (t *State) undoPayFee(tx *pb.Transaction, batch kvdb.Batch, block *pb.InternalBlock) error {
-	for offset, txOutput := range tx.TxOutputs {
-		addr := txOutput.ToAddr
-		if !bytes.Equal(addr, []byte(FeePlaceholder)) {
-			continue
-		}
-		addr = block.Proposer
-		utxoKey := utxo.GenUtxoKeyWithPrefix(addr, tx.Txid, int32(offset))
+func (t *State) undoPayFee(tx *pb.Transaction, batch kvdb.Batch, block *pb.InternalBlock, params *feeParams) error {
+	utxos, burnt, err := t.splitFee(tx, block.Proposer, blockBaseFee(block), params)
+	if err != nil {
+		return err
+	}
+	for _, fee := range utxos {
+		utxoKey := utxo.GenUtxoKeyWithPrefix(fee.addr, tx.Txid, fee.offset)
 		// 删除产生的UTXO
 		batch.Delete([]byte(utxoKey))
-		t.utxo.UtxoCache.Remove(string(addr), utxoKey)
-		t.utxo.SubBalance(addr, big.NewInt(0).SetBytes(txOutput.Amount))
+		t.utxo.UtxoCache.Remove(string(fee.addr), utxoKey)
+		t.utxo.SubBalance(fee.addr, fee.amount)
 		t.log.Info("undo delete fee utxo key", "utxoKey", utxoKey)
 	}
+	// 恢复被销毁的基础费用
+	if burnt.Sign() > 0 {
+		t.utxo.UpdateUtxoTotal(burnt, batch, true)
+	}
 	return nil
 }
 
@@ -1129,8 +1355,6 @@ func (t *State) undoPayFee(tx *pb.Transaction, batch kvdb.Batch, block *pb.Inter
 func (t *State) procTodoBlkForWalk(todoBlocks []*pb.InternalBlock) (err error) {
 	var todoBlk *pb.InternalBlock
 	var showBlkId string
-	var tx *pb.Transaction
-	var showTxId string
 
 	// 依次执行每个块的交易
 	for i := len(todoBlocks) - 1; i >= 0; i-- {
@@ -1141,48 +1365,51 @@ func (t *State) procTodoBlkForWalk(todoBlocks []*pb.InternalBlock) (err error) {
 		// 将batch赋值到合约机的上下文
 		batch := t.ldb.NewBatch()
 
-		// 执行区块里面的交易
-		idx, length := 0, len(todoBlk.Transactions)
-		for idx < length {
-			tx = todoBlk.Transactions[idx]
-			showTxId = hex.EncodeToString(tx.Txid)
-			t.log.Debug("procTodoBlkForWalk", "txid", showTxId, "autogen", t.verifyAutogenTxValid(tx), "coinbase", tx.Coinbase)
-			// 校验定时交易合法性
-			if t.verifyAutogenTxValid(tx) && !tx.Coinbase {
-				// 校验auto tx
-				if ok, err := t.ImmediateVerifyAutoTx(todoBlk.Height, tx, false); !ok {
-					return fmt.Errorf("immediate verify auto tx error.txid:%s,err:%v", showTxId, err)
-				}
-			}
+		// 校验交易有效期和nonce
+		if err = t.verifyBlockNonces(todoBlk, nil); err != nil {
+			return fmt.Errorf("verify nonce fail.blockid:%s,err:%v", showBlkId, err)
+		}
+		// 校验奖励分配
+		if err = t.verifyAwardTx(todoBlk); err != nil {
+			return fmt.Errorf("verify award tx fail.blockid:%s,err:%v", showBlkId, err)
+		}
 
-			// 校验普通交易合法性
-			if !tx.Autogen && !tx.Coinbase {
-				if ok, err := t.ImmediateVerifyTx(tx, false); !ok {
-					return fmt.Errorf("immediate verify tx error.txid:%s,err:%v", showTxId, err)
-				}
-			}
+		// 校验合约存储配额
+		if err = t.verifyBlockStorageQuota(todoBlk); err != nil {
+			return fmt.Errorf("verify storage quota fail.blockid:%s,err:%v", showBlkId, err)
+		}
 
-			// 执行交易
-			cacheFiller := &utxo.CacheFiller{}
-			err = t.doTxInternal(tx, batch, cacheFiller)
-			if err != nil {
-				return fmt.Errorf("todo tx fail.txid:%s,err:%v", showTxId, err)
-			}
-			cacheFiller.Commit()
+		// 按读写集冲突分组并发校验交易
+		verified := t.preVerifyBlockTxs(todoBlk)
+		params := t.currentFeeParams()
+		if err = t.saveFeeParams(todoBlk, batch); err != nil {
+			return fmt.Errorf("save fee params fail.blockid:%s,err:%v", showBlkId, err)
+		}
 
-			// 处理小费
-			err = t.payFee(tx, batch, todoBlk)
-			if err != nil {
-				return fmt.Errorf("pay fee fail.txid:%s,err:%v", showTxId, err)
-			}
-			idx++
+		// 执行区块里面的交易
+		if err = t.applyBlockTxsForWalk(todoBlk, batch, verified, params); err != nil {
+			return err
 		}
 
 		t.log.Debug("Begin to Finalize", "blockid", showBlkId)
 
+		// 汇总合约存储用量
+		if err = t.updateStorageUsage(todoBlk, batch, false); err != nil {
+			return fmt.Errorf("update storage usage fail.blockid:%s,err:%v", showBlkId, err)
+		}
+
+		// 校验状态根
+		err = t.verifyStateRoot(todoBlk, batch)
+		if err != nil {
+			return fmt.Errorf("verify state root fail.blockid:%s,err:%v", showBlkId, err)
+		}
+
 		// 更新不可逆区块高度
 		curIrreversibleBlockHeight := t.meta.GetIrreversibleBlockHeight()
 		curIrreversibleSlideWindow := t.meta.GetIrreversibleSlideWindow()
+		if err = t.pruneNonces(curIrreversibleBlockHeight, batch); err != nil {
+			return fmt.Errorf("prune nonce fail.blockid:%s,err:%v", showBlkId, err)
+		}
 		err = t.meta.UpdateNextIrreversibleBlockHeight(todoBlk.Height, curIrreversibleBlockHeight,
 			curIrreversibleSlideWindow, batch)
 		if err != nil {
@@ -1206,32 +1433,49 @@ func (t *State) procTodoBlkForWalk(todoBlocks []*pb.InternalBlock) (err error) {
 	return nil
 }
 
-func (t *State) payFee(tx *pb.Transaction, batch kvdb.Batch, block *pb.InternalBlock) error {
-	for offset, txOutput := range tx.TxOutputs {
-		addr := txOutput.ToAddr
-		if !bytes.Equal(addr, []byte(FeePlaceholder)) {
-			continue
-		}
-		addr = block.Proposer // 占位符替换为矿工
-		utxoKey := utxo.GenUtxoKeyWithPrefix(addr, tx.Txid, int32(offset))
+func (t *State) payFee(tx *pb.Transaction, batch kvdb.Batch, block *pb.InternalBlock, params *feeParams) error {
+	burnt, err := t.payFeeUtxos(tx, batch, block, params)
+	if err != nil {
+		return err
+	}
+	// 基础费用被销毁
+	if burnt.Sign() > 0 {
+		t.utxo.UpdateUtxoTotal(burnt, batch, false)
+	}
+	return nil
+}
+
+// payFeeUtxos 写入交易手续费产生的utxo，返回需要销毁的基础费用
+func (t *State) payFeeUtxos(tx *pb.Transaction, batch kvdb.Batch, block *pb.InternalBlock, params *feeParams) (*big.Int, error) {
+	utxos, burnt, err := t.splitFee(tx, block.Proposer, blockBaseFee(block), params)
+	if err != nil {
+		t.log.Warn("tx fee is not enough", "txid", utils.F(tx.Txid), "baseFee", blockBaseFee(block))
+		return nil, err
+	}
+	for _, fee := range utxos {
+		utxoKey := utxo.GenUtxoKeyWithPrefix(fee.addr, tx.Txid, fee.offset)
 		uItem := &utxo.UtxoItem{}
-		uItem.Amount = big.NewInt(0)
-		uItem.Amount.SetBytes(txOutput.Amount)
+		uItem.Amount = fee.amount
 		uItemBinary, uErr := uItem.Dumps()
 		if uErr != nil {
-			return uErr
+			return nil, uErr
 		}
 		batch.Put([]byte(utxoKey), uItemBinary) // 插入本交易产生的utxo
-		t.utxo.AddBalance(addr, uItem.Amount)
-		t.utxo.UtxoCache.Insert(string(addr), utxoKey, uItem)
+		t.utxo.AddBalance(fee.addr, uItem.Amount)
+		t.utxo.UtxoCache.Insert(string(fee.addr), utxoKey, uItem)
 		t.log.Trace("    insert fee utxo key", "utxoKey", utxoKey, "amount", uItem.Amount.String())
 	}
-	return nil
+	return burnt, nil
 }
 
 func (t *State) recoverUnconfirmedTx(undoList []*pb.Transaction) {
 	xTimer := timer.NewXTimer()
 	t.log.Info("start recover unconfirm tx", "tx_count", len(undoList))
+	// 离线工具回滚状态机时没有合约管理器，无法重新执行交易
+	if len(undoList) > 0 && t.sctx.ContractMgr == nil {
+		t.log.Warn("contract manager not set, ignore recover unconfirm tx", "tx_count", len(undoList))
+		return
+	}
 
 	var tx *pb.Transaction
 	var succCnt, verifyErrCnt, confirmCnt, doTxErrCnt int
@@ -1290,8 +1534,12 @@ func (t *State) processUnconfirmTxs(block *pb.InternalBlock, batch kvdb.Batch, n
 	txidsInBlock := map[string]bool{}    // block里面所有的txid
 	UTXOKeysInBlock := map[string]bool{} // block里面所有的交易需要用掉的utxo
 	keysVersionInBlock := map[string]string{}
+	noncesInBlock := map[string]bool{} // block里面设置了有效期的交易使用的nonce
 	for _, tx := range block.Transactions {
 		txidsInBlock[string(tx.Txid)] = true
+		if tx.ValidUntilHeight > 0 {
+			noncesInBlock[string(noncePrefix(tx))] = true
+		}
 		for _, txInput := range tx.TxInputs {
 			utxoKey := utxo.GenUtxoKey(txInput.FromAddr, txInput.RefTxid, txInput.RefOffset)
 			if UTXOKeysInBlock[utxoKey] { //检查块内的utxo双花情况
@@ -1365,11 +1613,21 @@ func (t *State) processUnconfirmTxs(block *pb.InternalBlock, batch kvdb.Batch, n
 				break
 			}
 		}
+		if unconfirmTx.ValidUntilHeight > 0 && noncesInBlock[string(noncePrefix(unconfirmTx))] {
+			t.log.Warn("nonce conflict", "txid", utils.F(unconfirmTx.Txid), "initiator", unconfirmTx.Initiator)
+			hasConflict = true
+		}
 		tooDelayed := delayedTxMap[string(unconfirmTx.Txid)]
 		if tooDelayed {
 			t.log.Warn("will undo tx because it is beyond confirmed delay", "txid", utils.F(unconfirmTx.Txid))
 		}
-		if hasConflict || tooDelayed {
+		// 下一个区块不能再打包的交易
+		expired := t.unpackableTx(unconfirmTx, block.Height+1)
+		if expired {
+			t.log.Warn("will undo tx because it can not be packed into next block", "txid", utils.F(unconfirmTx.Txid),
+				"validUntilHeight", unconfirmTx.ValidUntilHeight)
+		}
+		if hasConflict || tooDelayed || expired {
 			undoErr := t.undoUnconfirmedTx(unconfirmTx, unconfirmTxMap,
 				unconfirmTxGraph, batch, undoDone, nil)
 			if undoErr != nil {
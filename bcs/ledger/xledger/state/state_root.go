@@ -0,0 +1,313 @@
+package state
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/smt"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/xmodel"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+	"github.com/xuperchain/xupercore/lib/utils"
+)
+
+// 状态根覆盖xmodel数据和utxo，叶子的key与状态表中的key一致:
+// xmodel为ExtUtxoTablePrefix+bucket/key，value为数据内容；
+// utxo为UTXOTablePrefix+addr_txid_offset，value为UtxoItem序列化结果。
+// 树节点按哈希寻址且不会被修改，回滚区块只需回到父区块的状态根。
+// 只有分叉高度之后的区块携带状态根，见ledger.StateRootConfig。
+//
+// 状态根只覆盖交易声明的写集合，以下数据不在状态树中，无法通过状态证明校验:
+// nonce表(NonceTablePrefix)是有效期内已上链交易的索引，由区块中的交易决定；
+// 存储用量表(StorageUsageTablePrefix)是合约xmodel数据大小的累计，由xmodel数据决定。
+// 分叉前最后一个区块的状态树需要从创世块重放完整区块，
+// 账本在到达分叉高度之前拒绝在没有归档的情况下裁剪，见ledger.ErrStateRootPruned。
+
+const (
+	// 重放区块构建初始状态树时收集节点的区块间隔
+	replayCommitInterval = 1000
+)
+
+var (
+	ErrStateRootMismatch    = errors.New("state root of block mismatch")
+	ErrStateRootUnsupported = errors.New("state root is not supported by this block")
+)
+
+// XModelStateKey 返回xmodel数据在状态树中的key
+func XModelStateKey(bucket string, key []byte) []byte {
+	return append([]byte(pb.ExtUtxoTablePrefix), xmodel.MakeRawKey(bucket, key)...)
+}
+
+// UtxoStateKey 返回utxo在状态树中的key
+func UtxoStateKey(addr []byte, txid []byte, offset int32) []byte {
+	return []byte(utxo.GenUtxoKeyWithPrefix(addr, txid, offset))
+}
+
+// stateRootResult 区块的状态根及新增的树节点，节点随区块的batch一起写入
+type stateRootResult struct {
+	key   string
+	root  []byte
+	nodes map[string][]byte
+}
+
+// overlayStore 在已持久化节点之上叠加尚未写入的节点
+type overlayStore struct {
+	nodes map[string][]byte
+	store smt.Store
+}
+
+func (s *overlayStore) Get(h []byte) ([]byte, error) {
+	if data, ok := s.nodes[string(h)]; ok {
+		return data, nil
+	}
+	return s.store.Get(h)
+}
+
+// CalcStateRoot 在父区块状态根的基础上执行交易的写集合，返回新的状态根
+// 父区块之后的区块不携带状态根时返回nil；树节点不在这里写入，由执行区块时随区块的batch持久化
+func (t *State) CalcStateRoot(preHash []byte, txList []*pb.Transaction, proposer []byte) ([]byte, error) {
+	result, err := t.calcStateRoot(preHash, txList, proposer)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	return result.root, nil
+}
+
+// calcStateRoot 每个区块的计算结果被缓存，出块、执行区块时不会重复计算
+func (t *State) calcStateRoot(preHash []byte, txList []*pb.Transaction, proposer []byte) (*stateRootResult, error) {
+	enabled, err := t.sctx.Ledger.IsStateRootEnabledAfter(preHash)
+	if err != nil || !enabled {
+		return nil, err
+	}
+
+	key := stateRootKey(preHash, txList, proposer)
+	t.rootMutex.Lock()
+	cached := t.pendingRoot
+	t.rootMutex.Unlock()
+	if cached != nil && cached.key == key {
+		return cached, nil
+	}
+
+	parent, err := t.stateRootOf(preHash)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		return nil, ErrStateRootUnsupported
+	}
+	baseFee, err := t.sctx.Ledger.CalcBaseFee(preHash)
+	if err != nil {
+		return nil, err
+	}
+	store := &overlayStore{nodes: parent.nodes, store: t.stateTree}
+	tree := smt.NewTree(store, parent.root)
+	if err := t.updateStateTree(tree, txList, proposer, baseFee, t.currentFeeParams()); err != nil {
+		return nil, err
+	}
+
+	// 分叉后的第一个区块需要同时写入重放得到的初始状态树
+	nodes := tree.Commit()
+	for h, data := range parent.nodes {
+		nodes[h] = data
+	}
+	result := &stateRootResult{
+		key:   key,
+		root:  tree.Root(),
+		nodes: nodes,
+	}
+	t.rootMutex.Lock()
+	t.pendingRoot = result
+	t.rootMutex.Unlock()
+	return result, nil
+}
+
+func stateRootKey(preHash []byte, txList []*pb.Transaction, proposer []byte) string {
+	var buf bytes.Buffer
+	buf.Write(preHash)
+	buf.Write(proposer)
+	for _, tx := range txList {
+		buf.Write(tx.Txid)
+	}
+	return buf.String()
+}
+
+// GetStateRoot 返回状态机当前的状态根，不支持时返回nil
+func (t *State) GetStateRoot() ([]byte, error) {
+	result, err := t.stateRootOf(t.latestBlockid)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	return result.root, nil
+}
+
+// GetStateProof 返回key在指定区块状态根下的值及证明
+func (t *State) GetStateProof(blockid []byte, key []byte) (*smt.Proof, []byte, error) {
+	result, err := t.stateRootOf(blockid)
+	if err != nil {
+		return nil, nil, err
+	}
+	if result == nil {
+		return nil, nil, ErrStateRootUnsupported
+	}
+	store := &overlayStore{nodes: result.nodes, store: t.stateTree}
+	proof, err := smt.Prove(store, result.root, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof, result.root, nil
+}
+
+// verifyStateRoot 执行区块时校验区块头中的状态根，并将新增的树节点写入区块的batch
+func (t *State) verifyStateRoot(block *pb.InternalBlock, batch kvdb.Batch) error {
+	result, err := t.calcStateRoot(block.PreHash, block.Transactions, block.Proposer)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		if len(block.StateRoot) > 0 {
+			return ErrStateRootMismatch
+		}
+		return nil
+	}
+	if !bytes.Equal(result.root, block.StateRoot) {
+		t.log.Warn("state root mismatch", "blockid", utils.F(block.Blockid),
+			"expect", utils.F(block.StateRoot), "actual", utils.F(result.root))
+		return ErrStateRootMismatch
+	}
+	for h, data := range result.nodes {
+		batch.Put(append([]byte(pb.StateTreeTablePrefix), h...), data)
+	}
+	return nil
+}
+
+// stateRootOf 返回区块执行后的状态根，分叉之前的区块返回nil
+// 分叉前最后一个区块的状态树从创世块重放得到，节点随分叉后第一个区块写入
+func (t *State) stateRootOf(blockid []byte) (*stateRootResult, error) {
+	header, err := t.sctx.Ledger.QueryBlockHeader(blockid)
+	if err != nil {
+		return nil, err
+	}
+	if len(header.StateRoot) > 0 {
+		return &stateRootResult{key: string(blockid), root: header.StateRoot}, nil
+	}
+	if !t.sctx.Ledger.GetGenesisBlock().GetConfig().IsStateRootForkParent(header.Height) {
+		return nil, nil
+	}
+
+	t.rootMutex.Lock()
+	defer t.rootMutex.Unlock()
+	if t.forkRoot != nil && t.forkRoot.key == string(blockid) {
+		return t.forkRoot, nil
+	}
+	result, err := t.replayStateTree(blockid)
+	if err != nil {
+		return nil, err
+	}
+	t.forkRoot = result
+	return result, nil
+}
+
+// replayStateTree 从创世块开始执行到blockid的所有区块，构建分叉时的初始状态树
+func (t *State) replayStateTree(blockid []byte) (*stateRootResult, error) {
+	var chain [][]byte
+	for id := blockid; len(id) > 0; {
+		header, err := t.sctx.Ledger.QueryBlockHeader(id)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, id)
+		id = header.PreHash
+	}
+
+	store := &overlayStore{nodes: make(map[string][]byte), store: t.stateTree}
+	tree := smt.NewTree(store, nil)
+	for i := len(chain) - 1; i >= 0; i-- {
+		block, err := t.sctx.Ledger.QueryBlock(chain[i])
+		if err != nil {
+			return nil, err
+		}
+		baseFee, err := t.sctx.Ledger.CalcBaseFee(block.PreHash)
+		if err != nil {
+			return nil, err
+		}
+		params, err := t.blockFeeParams(block)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.updateStateTree(tree, block.Transactions, block.Proposer, baseFee, params); err != nil {
+			return nil, err
+		}
+		// 定期收集节点，避免中间节点占用过多内存
+		if i%replayCommitInterval == 0 {
+			for h, data := range tree.Commit() {
+				store.nodes[h] = data
+			}
+		}
+	}
+
+	return &stateRootResult{
+		key:   string(blockid),
+		root:  tree.Root(),
+		nodes: store.nodes,
+	}, nil
+}
+
+func (t *State) updateStateTree(tree *smt.Tree, txList []*pb.Transaction, proposer []byte,
+	baseFee *big.Int, params *feeParams) error {
+	for _, tx := range txList {
+		for _, txOut := range tx.TxOutputsExt {
+			if txOut.Bucket == xmodel.TransientBucket {
+				continue
+			}
+			key := XModelStateKey(txOut.Bucket, txOut.Key)
+			var err error
+			if bytes.Equal(txOut.Value, []byte(xmodel.DelFlag)) {
+				err = tree.Delete(key)
+			} else {
+				err = tree.Update(key, txOut.Value)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		for _, txInput := range tx.TxInputs {
+			key := UtxoStateKey(txInput.FromAddr, txInput.RefTxid, txInput.RefOffset)
+			if err := tree.Delete(key); err != nil {
+				return err
+			}
+		}
+		for offset, txOutput := range tx.TxOutputs {
+			uItem := &utxo.UtxoItem{}
+			uItem.Amount = big.NewInt(0).SetBytes(txOutput.Amount)
+			// 与doTxInternal一致，忽略为0的输出，手续费单独处理
+			if bytes.Equal(txOutput.ToAddr, []byte(FeePlaceholder)) || uItem.Amount.Sign() == 0 {
+				continue
+			}
+			uItem.FrozenHeight = txOutput.FrozenHeight
+			value, err := uItem.Dumps()
+			if err != nil {
+				return err
+			}
+			if err := tree.Update(UtxoStateKey(txOutput.ToAddr, tx.Txid, int32(offset)), value); err != nil {
+				return err
+			}
+		}
+		// 与payFee一致，手续费按基础费用拆分且不冻结
+		fees, _, err := t.splitFee(tx, proposer, baseFee, params)
+		if err != nil {
+			return err
+		}
+		for _, fee := range fees {
+			value, err := (&utxo.UtxoItem{Amount: fee.amount}).Dumps()
+			if err != nil {
+				return err
+			}
+			if err := tree.Update(UtxoStateKey(fee.addr, tx.Txid, fee.offset), value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -11,14 +11,18 @@ import (
 	"math/big"
 	"os"
 	"path/filepath"
+	"strconv"
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
+
 	_ "github.com/xuperchain/xupercore/bcs/contract/evm"
 	_ "github.com/xuperchain/xupercore/bcs/contract/native"
 	_ "github.com/xuperchain/xupercore/bcs/contract/xvm"
 	ledger_pkg "github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/context"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/smt"
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo"
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
 	txn "github.com/xuperchain/xupercore/bcs/ledger/xledger/tx"
@@ -574,6 +578,200 @@ func TestFrozenHeight(t *testing.T) {
 	}
 }
 
+// newStateRootTest 创建从forkHeight开始携带状态根的链，返回已执行创世块的状态机
+func newStateRootTest(t *testing.T, forkHeight int64) (*ledger_pkg.Ledger, *State, *pb.InternalBlock) {
+	workspace, dirErr := ioutil.TempDir("/tmp", "")
+	if dirErr != nil {
+		t.Fatal(dirErr)
+	}
+	os.RemoveAll(workspace)
+	t.Cleanup(func() { os.RemoveAll(workspace) })
+	econf, err := mock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logs.InitLog(econf.GenConfFilePath(econf.LogConf), econf.GenDirAbsPath(econf.LogDir))
+
+	lctx, err := ledger_pkg.NewLedgerCtx(econf, "xuper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lctx.EnvCfg.ChainDir = workspace
+	ledger, err := ledger_pkg.CreateLedger(lctx, GenesisConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := txn.GenerateRootTx([]byte(`
+       {
+        "version" : "1"
+        , "consensus" : {
+                "miner" : "0x00000000000"
+        }
+        , "predistribution":[
+                {
+                        "address" : "` + BobAddress + `",
+                        "quota" : "100"
+                }
+        ]
+        , "maxblocksize" : "128"
+        , "period" : "5000"
+        , "award" : "1000"
+        , "state_root" : {
+                "fork_height" : ` + strconv.FormatInt(forkHeight, 10) + `
+        }
+		}
+    `))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootBlock, _ := ledger.FormatRootBlock([]*pb.Transaction{tx})
+	confirmStatus := ledger.ConfirmBlock(rootBlock, true)
+	if !confirmStatus.Succ {
+		t.Fatal("confirm block fail")
+	}
+	crypt, err := crypto_client.CreateCryptoClient(crypto_client.CryptoTypeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sctx, err := context.NewStateCtx(econf, "xuper", ledger, crypt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sctx.EnvCfg.ChainDir = workspace
+	stateHandle, err := NewState(sctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stateHandle.Play(rootBlock.Blockid); err != nil {
+		t.Fatal(err)
+	}
+	return ledger, stateHandle, rootBlock
+}
+
+func TestStateRoot(t *testing.T) {
+	ledger, stateHandle, rootBlock := newStateRootTest(t, 1)
+	genesisRoot, err := stateHandle.GetStateRoot()
+	if err != nil || genesisRoot == nil {
+		t.Fatal("genesis state root error", err)
+	}
+
+	nextBlockid, err := transfer("bob", "alice", t, stateHandle, ledger, "60", rootBlock.Blockid, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ledger.QueryBlock(nextBlockid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(block.StateRoot) == 0 || bytes.Equal(block.StateRoot, genesisRoot) {
+		t.Fatal("unexpected state root of block")
+	}
+	if err := stateHandle.Play(nextBlockid); err != nil {
+		t.Fatal(err)
+	}
+	root, _ := stateHandle.GetStateRoot()
+	if !bytes.Equal(root, block.StateRoot) {
+		t.Fatal("state root mismatch after play")
+	}
+
+	// 新产生的utxo存在于新区块的状态根下
+	var transferTx *pb.Transaction
+	for _, tx := range block.Transactions {
+		if !tx.Coinbase {
+			transferTx = tx
+		}
+	}
+	proof, proofRoot, err := stateHandle.GetStateProof(nextBlockid, UtxoStateKey([]byte(AliceAddress), transferTx.Txid, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proof.Exist || !bytes.Equal(proofRoot, block.StateRoot) || !smt.VerifyProof(proofRoot, proof) {
+		t.Fatal("invalid inclusion proof")
+	}
+	uItem := &utxo.UtxoItem{}
+	if err := uItem.Loads(proof.Value); err != nil || uItem.Amount.String() != "60" {
+		t.Fatal("unexpected utxo value", err)
+	}
+
+	// 被花掉的创世utxo只存在于创世块的状态根下
+	bobKey := UtxoStateKey([]byte(BobAddress), rootBlock.Transactions[0].Txid, 0)
+	proof, proofRoot, err = stateHandle.GetStateProof(nextBlockid, bobKey)
+	if err != nil || proof.Exist || !smt.VerifyProof(proofRoot, proof) {
+		t.Fatal("invalid non-inclusion proof", err)
+	}
+	proof, proofRoot, err = stateHandle.GetStateProof(rootBlock.Blockid, bobKey)
+	if err != nil || !proof.Exist || !bytes.Equal(proofRoot, genesisRoot) || !smt.VerifyProof(proofRoot, proof) {
+		t.Fatal("invalid genesis inclusion proof", err)
+	}
+
+	fakeBlock := proto.Clone(block).(*pb.InternalBlock)
+	fakeBlock.StateRoot = genesisRoot
+	if err := stateHandle.verifyStateRoot(fakeBlock, stateHandle.NewBatch()); err != ErrStateRootMismatch {
+		t.Fatal("unexpected verify result", err)
+	}
+}
+
+func TestStateRootFork(t *testing.T) {
+	ledger, stateHandle, rootBlock := newStateRootTest(t, 2)
+	if root, err := stateHandle.GetStateRoot(); err != nil || root != nil {
+		t.Fatal("state root should be unsupported before fork", err)
+	}
+
+	// 分叉之前的区块不带状态根
+	firstBlockid, err := transfer("bob", "alice", t, stateHandle, ledger, "10", rootBlock.Blockid, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := ledger.QueryBlock(firstBlockid)
+	if err != nil || len(first.StateRoot) > 0 {
+		t.Fatal("block before fork should not carry state root", err)
+	}
+	if err := stateHandle.Play(firstBlockid); err != nil {
+		t.Fatal(err)
+	}
+	fakeFirst := proto.Clone(first).(*pb.InternalBlock)
+	fakeFirst.StateRoot = []byte("root")
+	if err := stateHandle.verifyStateRoot(fakeFirst, stateHandle.NewBatch()); err != ErrStateRootMismatch {
+		t.Fatal("state root before fork should be rejected", err)
+	}
+
+	// 分叉前最后一个区块的状态根从创世块重放得到
+	forkRoot, err := stateHandle.GetStateRoot()
+	if err != nil || forkRoot == nil {
+		t.Fatal("fork parent state root error", err)
+	}
+	nextBlockid, err := transfer("bob", "alice", t, stateHandle, ledger, "20", firstBlockid, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ledger.QueryBlock(nextBlockid)
+	if err != nil || len(block.StateRoot) == 0 {
+		t.Fatal("block after fork should carry state root", err)
+	}
+	if err := stateHandle.Play(nextBlockid); err != nil {
+		t.Fatal(err)
+	}
+
+	// 初始状态树的节点随分叉后第一个区块写入
+	stateHandle.forkRoot = nil
+	var transferTx *pb.Transaction
+	for _, tx := range first.Transactions {
+		if !tx.Coinbase {
+			transferTx = tx
+		}
+	}
+	key := UtxoStateKey([]byte(AliceAddress), transferTx.Txid, 0)
+	for _, id := range [][]byte{firstBlockid, nextBlockid} {
+		proof, proofRoot, err := stateHandle.GetStateProof(id, key)
+		if err != nil || !proof.Exist || !smt.VerifyProof(proofRoot, proof) {
+			t.Fatal("invalid inclusion proof", err)
+		}
+	}
+	if _, _, err := stateHandle.GetStateProof(rootBlock.Blockid, key); err != ErrStateRootUnsupported {
+		t.Fatal("genesis should not support state root", err)
+	}
+}
+
 func TestGetSnapShotWithBlock(t *testing.T) {
 	workspace, dirErr := ioutil.TempDir("/tmp", "")
 	if dirErr != nil {
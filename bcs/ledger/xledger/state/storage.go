@@ -0,0 +1,359 @@
+package state
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge"
+	"github.com/xuperchain/xupercore/kernel/contract/proposal/utils"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+// 创世配置开启合约存储计量后，区块执行时按交易的读写集汇总每个合约的存储用量，保存在状态机的用量表中，
+// 回滚区块时减去相同的用量。用量表由每个节点自行维护，不在读写集和状态根中。
+// 预执行和交易进入交易池时按已确认的用量检查单个交易，打包和校验区块时按区块内累计的用量变化检查，
+// 两者使用相同的StorageQuotaChecker，自动生成的交易只累计用量不检查配额。
+// 沙盒写入前会强制读取，合约bucket中没有对应读集的写入无法确定旧值，视为无效交易。
+// 单个合约的配额通过提案调用$storage.SetQuota修改。
+
+const (
+	StorageKernelContract = sandbox.StorageBucket
+)
+
+var (
+	ErrStorageQuotaDisabled    = errors.New("contract storage quota is not enabled")
+	ErrStorageWriteWithoutRead = errors.New("contract storage write without read")
+)
+
+// registerStorageMethods 注册修改合约存储配额的系统合约方法
+func (t *State) registerStorageMethods(contractMgr contract.Manager) {
+	if contractMgr == nil || contractMgr.GetKernRegistry() == nil {
+		return
+	}
+	contractMgr.GetKernRegistry().RegisterKernMethod(StorageKernelContract, "SetQuota", t.setStorageQuota)
+}
+
+// setStorageQuota 由提案在trigger高度调用，参数为{"contract_name": "...", "quota": 1024}，quota为0表示不限制
+func (t *State) setStorageQuota(ctx contract.KContext) (*contract.Response, error) {
+	if ctx.Caller() != utils.ProposalKernelContract {
+		return nil, fmt.Errorf("caller %s no authority to SetQuota", ctx.Caller())
+	}
+	if t.StorageQuota() == nil {
+		return nil, ErrStorageQuotaDisabled
+	}
+	args := struct {
+		ContractName string `json:"contract_name"`
+		Quota        int64  `json:"quota"`
+	}{}
+	if err := json.Unmarshal(ctx.Args()["args"], &args); err != nil {
+		return nil, fmt.Errorf("set storage quota failed, parse args error: %v", err)
+	}
+	if err := contract.ValidContractName(args.ContractName); err != nil {
+		return nil, err
+	}
+	if args.Quota < 0 {
+		return nil, fmt.Errorf("set storage quota failed, invalid quota: %d", args.Quota)
+	}
+	err := ctx.Put(StorageKernelContract, sandbox.StorageQuotaKey(args.ContractName),
+		sandbox.EncodeStorageBytes(args.Quota))
+	if err != nil {
+		return nil, err
+	}
+	return &contract.Response{
+		Status:  utils.StatusOK,
+		Message: "success",
+	}, nil
+}
+
+// StorageQuota 返回执行合约的沙盒使用的存储计量配置，未开启时返回nil
+func (t *State) StorageQuota() *contract.StorageQuota {
+	conf := t.sctx.Ledger.GetGenesisBlock().GetConfig().StorageQuota
+	if !conf.Enabled {
+		return nil
+	}
+	return &contract.StorageQuota{
+		Default: conf.DefaultQuota,
+		Usage:   t.storageUsage,
+	}
+}
+
+// QueryContractStorage 查询合约已确认的存储用量和生效的配额
+func (t *State) QueryContractStorage(contractName string) (*protos.ContractStorage, error) {
+	quota := t.StorageQuota()
+	if quota == nil {
+		return nil, ErrStorageQuotaDisabled
+	}
+	used, err := t.storageUsage(contractName)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := t.storageQuotaOf(contractName, quota.Default)
+	if err != nil {
+		return nil, err
+	}
+	return &protos.ContractStorage{
+		ContractName: contractName,
+		Used:         used,
+		Quota:        limit,
+	}, nil
+}
+
+// QueryStorageUsage 查询所有合约的存储用量，按合约名排序
+func (t *State) QueryStorageUsage() ([]*protos.ContractStorage, error) {
+	if t.StorageQuota() == nil {
+		return nil, ErrStorageQuotaDisabled
+	}
+	var names []string
+	iter := t.ldb.NewIteratorWithPrefix([]byte(pb.StorageUsageTablePrefix))
+	for iter.Next() {
+		names = append(names, string(iter.Key()[len(pb.StorageUsageTablePrefix):]))
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+
+	usage := make([]*protos.ContractStorage, 0, len(names))
+	for _, name := range names {
+		storage, err := t.QueryContractStorage(name)
+		if err != nil {
+			return nil, err
+		}
+		usage = append(usage, storage)
+	}
+	return usage, nil
+}
+
+func storageUsageKey(contractName string) []byte {
+	return append([]byte(pb.StorageUsageTablePrefix), contractName...)
+}
+
+// loadStorageUsage 读取用量表中保存的值，可能因开启计量前写入的数据被删除而小于0
+func (t *State) loadStorageUsage(contractName string) (int64, error) {
+	value, err := t.ldb.Get(storageUsageKey(contractName))
+	if err != nil {
+		if kvdb.ErrNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return sandbox.DecodeStorageBytes(value)
+}
+
+// storageUsage 返回合约已确认的存储用量
+func (t *State) storageUsage(contractName string) (int64, error) {
+	used, err := t.loadStorageUsage(contractName)
+	if err != nil || used < 0 {
+		return 0, err
+	}
+	return used, nil
+}
+
+// storageQuotaOf 返回合约生效的存储配额，未单独设置时使用默认配额
+func (t *State) storageQuotaOf(contractName string, defaultQuota int64) (int64, error) {
+	data, err := t.xmodel.Get(StorageKernelContract, sandbox.StorageQuotaKey(contractName))
+	if err != nil {
+		return 0, err
+	}
+	value := data.GetPureData().GetValue()
+	if len(value) == 0 || sandbox.IsDelFlag(value) {
+		return defaultQuota, nil
+	}
+	return sandbox.DecodeStorageBytes(value)
+}
+
+// checkStorageQuota 交易进入交易池前按已确认的用量检查合约存储配额
+func (t *State) checkStorageQuota(tx *pb.Transaction) error {
+	checker := t.NewStorageQuotaChecker()
+	if checker == nil {
+		return nil
+	}
+	return checker.Add(tx)
+}
+
+// verifyBlockStorageQuota 按区块内累计的用量变化检查合约存储配额
+func (t *State) verifyBlockStorageQuota(block *pb.InternalBlock) error {
+	checker := t.NewStorageQuotaChecker()
+	if checker == nil {
+		return nil
+	}
+	for _, tx := range block.Transactions {
+		if err := checker.Add(tx); err != nil {
+			t.log.Warn("verify block storage quota failed", "txid", hex.EncodeToString(tx.Txid), "err", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// StorageQuotaChecker 按已确认的用量和已加入交易的累计用量变化检查合约存储配额
+type StorageQuotaChecker struct {
+	state     *State
+	quota     *contract.StorageQuota
+	contracts *contractBucketFilter
+	deltas    map[string]int64
+}
+
+// NewStorageQuotaChecker 创建打包和校验区块使用的配额检查，未开启存储计量时返回nil
+func (t *State) NewStorageQuotaChecker() *StorageQuotaChecker {
+	quota := t.StorageQuota()
+	if quota == nil {
+		return nil
+	}
+	return &StorageQuotaChecker{
+		state:     t,
+		quota:     quota,
+		contracts: t.newContractBucketFilter(),
+		deltas:    make(map[string]int64),
+	}
+}
+
+// Add 检查加入tx后合约的累计用量是否超过配额，未超过时累计tx的用量变化
+func (c *StorageQuotaChecker) Add(tx *pb.Transaction) error {
+	c.contracts.addDeploys(tx)
+	deltas, err := c.state.txStorageDeltas(tx, c.contracts)
+	if err != nil {
+		return err
+	}
+	if !tx.Autogen && !tx.Coinbase {
+		for name, delta := range deltas {
+			total := c.deltas[name] + delta
+			if delta <= 0 || total <= 0 {
+				continue
+			}
+			limit, err := c.state.storageQuotaOf(name, c.quota.Default)
+			if err != nil {
+				return err
+			}
+			used, err := c.state.storageUsage(name)
+			if err != nil {
+				return err
+			}
+			if limit > 0 && used+total > limit {
+				return fmt.Errorf("%w: contract %s used %d, quota %d", sandbox.ErrStorageQuotaExceeded, name, used+total, limit)
+			}
+		}
+	}
+	for name, delta := range deltas {
+		c.deltas[name] += delta
+	}
+	return nil
+}
+
+// updateStorageUsage 执行区块时累加区块中交易的存储用量，回滚区块时减去
+func (t *State) updateStorageUsage(block *pb.InternalBlock, batch kvdb.Batch, undo bool) error {
+	if t.StorageQuota() == nil {
+		return nil
+	}
+	deltas, err := t.storageDeltas(block.Transactions)
+	if err != nil {
+		return err
+	}
+	for name, delta := range deltas {
+		if delta == 0 {
+			continue
+		}
+		used, err := t.loadStorageUsage(name)
+		if err != nil {
+			return err
+		}
+		if undo {
+			used -= delta
+		} else {
+			used += delta
+		}
+		if used == 0 {
+			batch.Delete(storageUsageKey(name))
+		} else {
+			batch.Put(storageUsageKey(name), sandbox.EncodeStorageBytes(used))
+		}
+	}
+	return nil
+}
+
+// storageDeltas 按读写集计算交易对每个合约占用字节的变化
+func (t *State) storageDeltas(txs []*pb.Transaction) (map[string]int64, error) {
+	deltas := make(map[string]int64)
+	contracts := t.newContractBucketFilter()
+	for _, tx := range txs {
+		contracts.addDeploys(tx)
+	}
+	for _, tx := range txs {
+		txDeltas, err := t.txStorageDeltas(tx, contracts)
+		if err != nil {
+			return nil, err
+		}
+		for name, delta := range txDeltas {
+			deltas[name] += delta
+		}
+	}
+	return deltas, nil
+}
+
+// txStorageDeltas 计算单个交易对每个合约占用字节的变化，旧值为读集引用的版本
+func (t *State) txStorageDeltas(tx *pb.Transaction, contracts *contractBucketFilter) (map[string]int64, error) {
+	deltas := make(map[string]int64)
+	inputs := make(map[string]*protos.TxInputExt, len(tx.TxInputsExt))
+	for _, txIn := range tx.TxInputsExt {
+		inputs[txIn.Bucket+"/"+string(txIn.Key)] = txIn
+	}
+	for _, txOut := range tx.TxOutputsExt {
+		if !contracts.isContract(txOut.Bucket) {
+			continue
+		}
+		txIn, ok := inputs[txOut.Bucket+"/"+string(txOut.Key)]
+		if !ok {
+			return nil, fmt.Errorf("%w: contract %s key %s", ErrStorageWriteWithoutRead, txOut.Bucket, txOut.Key)
+		}
+		old, err := t.xmodel.GetFromLedger(txIn)
+		if err != nil {
+			return nil, err
+		}
+		deltas[txOut.Bucket] += sandbox.StorageSize(txOut.Key, txOut.Value) -
+			sandbox.StorageSize(txOut.Key, old.GetPureData().GetValue())
+	}
+	return deltas, nil
+}
+
+// contractBucketFilter 只统计已部署合约的bucket，包括交易中部署的合约，部署信息见bridge.ContractCodeDescKey
+type contractBucketFilter struct {
+	state *State
+	known map[string]bool
+}
+
+func (t *State) newContractBucketFilter() *contractBucketFilter {
+	return &contractBucketFilter{
+		state: t,
+		known: make(map[string]bool),
+	}
+}
+
+// addDeploys 记录交易中部署的合约
+func (f *contractBucketFilter) addDeploys(tx *pb.Transaction) {
+	for _, txOut := range tx.TxOutputsExt {
+		if txOut.Bucket == contractBucket && bytes.HasSuffix(txOut.Key, []byte(".desc")) {
+			f.known[strings.TrimSuffix(string(txOut.Key), ".desc")] = true
+		}
+	}
+}
+
+func (f *contractBucketFilter) isContract(bucket string) bool {
+	if deployed, ok := f.known[bucket]; ok {
+		return deployed
+	}
+	deployed := false
+	if contract.ValidContractName(bucket) == nil {
+		data, err := f.state.xmodel.Get(contractBucket, bridge.ContractCodeDescKey(bucket))
+		deployed = err == nil && len(data.GetPureData().GetValue()) > 0
+	}
+	f.known[bucket] = deployed
+	return deployed
+}
@@ -0,0 +1,101 @@
+package state
+
+import (
+	"errors"
+	"testing"
+
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func storageTestTx(txid string, outputs ...*protos.TxOutputExt) *pb.Transaction {
+	tx := &pb.Transaction{Txid: []byte(txid), TxOutputsExt: outputs}
+	for _, out := range outputs {
+		tx.TxInputsExt = append(tx.TxInputsExt, &protos.TxInputExt{Bucket: out.Bucket, Key: out.Key})
+	}
+	return tx
+}
+
+func TestStorageUsage(t *testing.T) {
+	ts := newTestState(t, `"storage_quota": {"enabled": true, "default_quota": 20},`)
+	sta := ts.state
+
+	// 部署合约的区块同时写入合约数据，合约部署信息不计入用量
+	deploy := storageTestTx("deploy",
+		&protos.TxOutputExt{Bucket: contractBucket, Key: []byte("counter.desc"), Value: []byte("desc")},
+		&protos.TxOutputExt{Bucket: "counter", Key: []byte("k0"), Value: []byte("v0")},
+	)
+	block := &pb.InternalBlock{Transactions: []*pb.Transaction{deploy}}
+	batch := sta.NewBatch()
+	if err := sta.doTxInternal(deploy, batch, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := sta.updateStorageUsage(block, batch, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatal(err)
+	}
+	usage, err := sta.QueryStorageUsage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usage) != 1 || usage[0].ContractName != "counter" || usage[0].Used != 4 || usage[0].Quota != 20 {
+		t.Fatalf("unexpected storage usage %v", usage)
+	}
+
+	// 进入交易池时按已确认的用量检查配额
+	large := storageTestTx("large", &protos.TxOutputExt{Bucket: "counter", Key: []byte("k1"), Value: make([]byte, 16)})
+	if err := sta.checkStorageQuota(large); !errors.Is(err, sandbox.ErrStorageQuotaExceeded) {
+		t.Fatalf("expect quota exceeded, got %v", err)
+	}
+	small := storageTestTx("small", &protos.TxOutputExt{Bucket: "counter", Key: []byte("k1"), Value: []byte("v1")})
+	if err := sta.checkStorageQuota(small); err != nil {
+		t.Fatal(err)
+	}
+
+	// 区块中的交易按累计用量检查配额，单独检查都未超过配额的交易一起打包时超过
+	tx1 := storageTestTx("tx1", &protos.TxOutputExt{Bucket: "counter", Key: []byte("k1"), Value: make([]byte, 8)})
+	tx2 := storageTestTx("tx2", &protos.TxOutputExt{Bucket: "counter", Key: []byte("k2"), Value: make([]byte, 8)})
+	if sta.checkStorageQuota(tx1) != nil || sta.checkStorageQuota(tx2) != nil {
+		t.Fatal("single tx should not exceed quota")
+	}
+	err = sta.verifyBlockStorageQuota(&pb.InternalBlock{Transactions: []*pb.Transaction{tx1, tx2}})
+	if !errors.Is(err, sandbox.ErrStorageQuotaExceeded) {
+		t.Fatalf("expect block quota exceeded, got %v", err)
+	}
+	checker := sta.NewStorageQuotaChecker()
+	if err := checker.Add(tx1); err != nil {
+		t.Fatal(err)
+	}
+	if err := checker.Add(tx2); !errors.Is(err, sandbox.ErrStorageQuotaExceeded) {
+		t.Fatalf("expect quota exceeded when packing, got %v", err)
+	}
+	// 自动生成的交易只累计用量
+	autoTx := storageTestTx("auto", &protos.TxOutputExt{Bucket: "counter", Key: []byte("k3"), Value: make([]byte, 16)})
+	autoTx.Autogen = true
+	if err := checker.Add(autoTx); err != nil {
+		t.Fatal(err)
+	}
+
+	// 没有对应读集的写入无法确定旧值
+	noRead := &pb.Transaction{Txid: []byte("noread"), TxOutputsExt: []*protos.TxOutputExt{
+		{Bucket: "counter", Key: []byte("k0"), Value: []byte("v0")},
+	}}
+	if err := sta.checkStorageQuota(noRead); !errors.Is(err, ErrStorageWriteWithoutRead) {
+		t.Fatalf("expect write without read, got %v", err)
+	}
+
+	// 回滚区块时减去相同的用量
+	batch = sta.NewBatch()
+	if err := sta.updateStorageUsage(block, batch, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatal(err)
+	}
+	if usage, err := sta.QueryStorageUsage(); err != nil || len(usage) != 0 {
+		t.Fatalf("storage usage should be removed after undo: %v, %v", usage, err)
+	}
+}
@@ -9,9 +9,9 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
-	"sync"
 	"time"
 
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo"
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/xmodel"
@@ -21,7 +21,6 @@ import (
 	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
 	kledger "github.com/xuperchain/xupercore/kernel/ledger"
 	aclu "github.com/xuperchain/xupercore/kernel/permission/acl/utils"
-	"github.com/xuperchain/xupercore/lib/crypto/client"
 	"github.com/xuperchain/xupercore/lib/metrics"
 	"github.com/xuperchain/xupercore/protos"
 
@@ -67,6 +66,17 @@ func (t *State) ImmediateVerifyTx(tx *pb.Transaction, isRootTx bool) (bool, erro
 		t.log.Warn("tx too large, should not be greater than half of max blocksize", "size", proto.Size(tx))
 		return false, ErrTxTooLarge
 	}
+	// 交易需要能被打包进下一个区块
+	if tx.ValidUntilHeight > 0 {
+		height, err := t.latestHeight()
+		if err != nil {
+			return false, err
+		}
+		if err := verifyTxExpiry(tx, height+1); err != nil {
+			t.log.Warn("tx expired", "validUntilHeight", tx.ValidUntilHeight, "height", height+1)
+			return false, err
+		}
+	}
 
 	// Start transaction verification workflow
 	if tx.Version > RootTxVersion {
@@ -179,6 +189,17 @@ func (t *State) ImmediateVerifyAutoTx(blockHeight int64, tx *pb.Transaction, isR
 		t.log.Warn("tx too large, should not be greater than half of max blocksize", "size", proto.Size(tx))
 		return false, ErrTxTooLarge
 	}
+	// 交易需要能被打包进下一个区块
+	if tx.ValidUntilHeight > 0 {
+		height, err := t.latestHeight()
+		if err != nil {
+			return false, err
+		}
+		if err := verifyTxExpiry(tx, height+1); err != nil {
+			t.log.Warn("tx expired", "validUntilHeight", tx.ValidUntilHeight, "height", height+1)
+			return false, err
+		}
+	}
 
 	// Start transaction verification workflow
 	if tx.Version > RootTxVersion {
@@ -582,8 +603,10 @@ func (t *State) verifyTxRWSets(tx *pb.Transaction) (bool, error) {
 	}
 	utxoReader := sandbox.NewUTXOReaderFromInput(utxoInput)
 	sandBoxConfig := &contract.SandboxConfig{
-		XMReader:   reader,
-		UTXOReader: utxoReader,
+		XMReader:           reader,
+		UTXOReader:         utxoReader,
+		ContractVersion:    t.ContractVersionEnabled(),
+		ContractCallRevert: t.ContractCallRevertEnabled(),
 	}
 	sandBox, err := t.sctx.ContractMgr.NewStateSandbox(sandBoxConfig)
 	if err != nil {
@@ -722,33 +745,15 @@ func (t *State) verifyMarked(tx *pb.Transaction) (bool, bool, error) {
 }
 
 func (t *State) verifyMarkedTx(tx *pb.Transaction) error {
-	bytespk := []byte(tx.ModifyBlock.PublicKey)
-	xcc, err := client.CreateCryptoClientFromJSONPublicKey(bytespk)
-	if err != nil {
-		return err
-	}
-	ecdsaKey, err := xcc.GetEcdsaPublicKeyFromJsonStr(tx.ModifyBlock.PublicKey)
-	if err != nil {
-		return err
-	}
-	isMatch, _ := xcc.VerifyAddressUsingPublicKey(t.utxo.ModifyBlockAddr, ecdsaKey)
-	if !isMatch {
-		return errors.New("address and public key not match")
-	}
-
-	bytesign, err := hex.DecodeString(tx.ModifyBlock.Sign)
-	if err != nil {
-		return errors.New("invalide arg type: sign byte")
+	// 脱敏的交易只能清除允许的字段，监管签名覆盖其余字段
+	if ledger.IsRedactedTx(tx) {
+		if err := ledger.VerifyRedactedTx(tx); err != nil {
+			return err
+		}
 	}
-	tx.ModifyBlock = &pb.ModifyBlock{}
-	digestHash, err := txhash.MakeTxDigestHash(tx)
+	err := t.verifyModifySign(tx.ModifyBlock.PublicKey, tx.ModifyBlock.Sign, tx)
 	if err != nil {
-		t.log.Warn("verifyMarkedTx call MakeTxDigestHash failed", "error", err)
-		return err
-	}
-	ok, err := xcc.VerifyECDSA(ecdsaKey, bytesign, digestHash)
-	if err != nil || !ok {
-		t.log.Warn("verifyMarkedTx validateUpdateBlockChainData verifySignatures failed")
+		t.log.Warn("verifyMarkedTx validateUpdateBlockChainData verifySignatures failed", "err", err)
 		return err
 	}
 	return nil
@@ -789,11 +794,12 @@ func (t *State) verifyRelyOnMarkedTxs(tx *pb.Transaction) (bool, bool, error) {
 // err
 func (t *State) checkRelyOnMarkedTxid(reftxid []byte, blockid []byte) (bool, bool, error) {
 	isRely := false
-	reftx, err := t.sctx.Ledger.QueryTransaction(reftxid)
+	reftx, err := t.sctx.Ledger.QueryTransactionState(reftxid)
 	if err != nil {
 		return true, isRely, nil
 	}
-	if reftx.GetModifyBlock() != nil && reftx.ModifyBlock.Marked {
+	// 脱敏只清除交易内容，不影响引用它的交易
+	if reftx.GetModifyBlock() != nil && reftx.ModifyBlock.Marked && !ledger.IsRedactedTx(reftx) {
 		isRely = true
 		if string(blockid) != "" {
 			ib, err := t.sctx.Ledger.QueryBlock(blockid)
@@ -877,26 +883,10 @@ func (t *State) queryAccountACL(accountName string) (*protos.Acl, error) {
 }
 
 func (t *State) verifyBlockTxs(block *pb.InternalBlock, isRootTx bool, unconfirmToConfirm map[string]bool) error {
-	var err error
-	var once sync.Once
-	wg := sync.WaitGroup{}
-	dags := txn.SplitToDags(block)
-	for _, txs := range dags {
-		wg.Add(1)
-		go func(txs []*pb.Transaction) {
-			defer wg.Done()
-			verifyErr := t.verifyDAGTxs(block.Height, txs, isRootTx, unconfirmToConfirm)
-			onceBody := func() {
-				err = verifyErr
-			}
-			// err 只被赋值一次
-			if verifyErr != nil {
-				once.Do(onceBody)
-			}
-		}(txs)
-	}
-	wg.Wait()
-	return err
+	groups := txn.SplitToConflictGroups(block.Transactions)
+	return runConflictGroups(t.replayWorkers, groups, func(txs []*pb.Transaction) error {
+		return t.verifyDAGTxs(block.Height, txs, isRootTx, unconfirmToConfirm)
+	})
 }
 
 func (t *State) verifyDAGTxs(blockHeight int64, txs []*pb.Transaction, isRootTx bool, unconfirmToConfirm map[string]bool) error {
@@ -921,10 +911,13 @@ func (t *State) verifyDAGTxs(blockHeight int64, txs []*pb.Transaction, isRootTx
 					if isRelyOnMarkedTx {
 						if !ok || err != nil {
 							t.log.Warn("tx verification failed because it is blocked tx", "err", err)
-						} else {
-							t.log.Trace("blocked tx verification succeed")
+							if err == nil {
+								err = errors.New("dotx failed to verify blocked tx")
+							}
+							return err
 						}
-						return err
+						t.log.Trace("blocked tx verification succeed")
+						continue
 					}
 					return errors.New("dotx failed to ImmediateVerifyTx error")
 				}
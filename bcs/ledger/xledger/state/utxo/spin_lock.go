@@ -70,6 +70,11 @@ func (sp *SpinLock) ExtractLockKeys(tx *pb.Transaction) []*LockKey {
 		k := string(tx.Txid) + "_" + strconv.Itoa(offset)
 		keys = append(keys, &LockKey{key: k, lockType: exclusiveLock})
 	}
+	if tx.ValidUntilHeight > 0 {
+		// 同一发起者相同nonce的交易互斥执行
+		k := "nonce:" + tx.Initiator + "/" + tx.Nonce
+		keys = append(keys, &LockKey{key: k, lockType: exclusiveLock})
+	}
 	readKeys := map[string]bool{}
 	writeKeys := map[string]bool{}
 	for _, input := range tx.TxInputsExt {
@@ -177,6 +177,10 @@ func txDigestHashV2(tx *pb.Transaction, includeSigns bool) []byte {
 
 	enc.Encode(tx.GetHDInfo().GetHdPublicKey())
 	enc.Encode(tx.GetHDInfo().GetOriginalHash())
+	// 未设置有效期时不参与编码，保持已有交易的txid不变
+	if tx.ValidUntilHeight > 0 {
+		enc.Encode(tx.ValidUntilHeight)
+	}
 
 	sum := sha256.Sum256(h.Sum(nil))
 	return sum[:]
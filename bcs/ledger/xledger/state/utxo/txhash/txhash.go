@@ -158,6 +158,12 @@ func encodeTxData(tx *pb.Transaction, includeSigns bool) ([]byte, error) {
 			return nil, err
 		}
 	}
+	// 未设置有效期时不参与编码，保持已有交易的txid不变
+	if tx.ValidUntilHeight > 0 {
+		if err = encoder.Encode(tx.ValidUntilHeight); err != nil {
+			return nil, err
+		}
+	}
 	return buf.Bytes(), nil
 }
 
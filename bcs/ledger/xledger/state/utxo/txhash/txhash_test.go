@@ -67,6 +67,23 @@ func TestTxHashVersion(t *testing.T) {
 
 }
 
+func TestTxHashValidUntilHeight(t *testing.T) {
+	tx := readTxFile(t, "tx.pb")
+	for version := 1; version <= 3; version++ {
+		tx.Version = int32(version)
+		tx.ValidUntilHeight = 0
+		txid, _ := MakeTransactionID(tx)
+		digest, _ := MakeTxDigestHash(tx)
+		tx.ValidUntilHeight = 100
+		expireTxid, _ := MakeTransactionID(tx)
+		expireDigest, _ := MakeTxDigestHash(tx)
+		if hex.EncodeToString(txid) == hex.EncodeToString(expireTxid) ||
+			hex.EncodeToString(digest) == hex.EncodeToString(expireDigest) {
+			t.Fatalf("valid until height should be signed when version = %d", version)
+		}
+	}
+}
+
 func TestTestSignTx(t *testing.T) {
 	tx := &pb.Transaction{}
 	_, err := MakeTxDigestHash(tx)
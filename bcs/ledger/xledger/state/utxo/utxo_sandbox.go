@@ -7,10 +7,31 @@ import (
 	"math/big"
 )
 
+// UTXOCheckpoint 记录检查点时已生成的输入输出和已锁定的utxo数量
+type UTXOCheckpoint struct {
+	inputs  int
+	outputs int
+	locked  int
+	reader  int
+}
+
+// checkpointReader 按交易输入依次选择UTXO的读取器，回滚时需要同步回滚读取位置
+type checkpointReader interface {
+	Checkpoint() int
+	RevertToCheckpoint(cp int)
+}
+
+// keyUnlocker 选择UTXO时会临时锁定的读取器，回滚时需要解锁被撤销的转账锁定的utxo
+type keyUnlocker interface {
+	UnlockKey(utxoKey []byte)
+}
+
 type UTXOSandbox struct {
 	inputCache  []*protos.TxInput
 	outputCache []*protos.TxOutput
-	utxoReader  contract.UtxoReader
+	// lockedKeys 转账时锁定的utxo
+	lockedKeys [][]byte
+	utxoReader contract.UtxoReader
 }
 
 func NewUTXOSandbox(cfg *contract.SandboxConfig) *UTXOSandbox {
@@ -24,10 +45,11 @@ func (u *UTXOSandbox) Transfer(from, to string, amount *big.Int) error {
 	if amount.Cmp(new(big.Int)) == 0 {
 		return errors.New("should  be large than zero")
 	}
-	inputs, _, total, err := u.utxoReader.SelectUtxo(from, amount, true, false)
+	inputs, lockedKeys, total, err := u.utxoReader.SelectUtxo(from, amount, true, false)
 	if err != nil {
 		return err
 	}
+	u.lockedKeys = append(u.lockedKeys, lockedKeys...)
 	u.inputCache = append(u.inputCache, inputs...)
 	u.outputCache = append(u.outputCache, &protos.TxOutput{
 		Amount: amount.Bytes(),
@@ -49,3 +71,37 @@ func (uc *UTXOSandbox) GetUTXORWSets() *contract.UTXORWSet {
 		WSet: uc.outputCache,
 	}
 }
+
+// Checkpoint 返回当前的输入输出位置
+func (uc *UTXOSandbox) Checkpoint() UTXOCheckpoint {
+	cp := UTXOCheckpoint{
+		inputs:  len(uc.inputCache),
+		outputs: len(uc.outputCache),
+		locked:  len(uc.lockedKeys),
+	}
+	if reader, ok := uc.utxoReader.(checkpointReader); ok {
+		cp.reader = reader.Checkpoint()
+	}
+	return cp
+}
+
+// RevertToCheckpoint 撤销检查点之后的转账
+func (uc *UTXOSandbox) RevertToCheckpoint(cp UTXOCheckpoint) {
+	if cp.inputs <= len(uc.inputCache) {
+		uc.inputCache = uc.inputCache[:cp.inputs]
+	}
+	if cp.outputs <= len(uc.outputCache) {
+		uc.outputCache = uc.outputCache[:cp.outputs]
+	}
+	if cp.locked <= len(uc.lockedKeys) {
+		if unlocker, ok := uc.utxoReader.(keyUnlocker); ok {
+			for _, key := range uc.lockedKeys[cp.locked:] {
+				unlocker.UnlockKey(key)
+			}
+		}
+		uc.lockedKeys = uc.lockedKeys[:cp.locked]
+	}
+	if reader, ok := uc.utxoReader.(checkpointReader); ok {
+		reader.RevertToCheckpoint(cp.reader)
+	}
+}
@@ -0,0 +1,54 @@
+package utxo
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+// lockingReader 每次选择一个新的utxo并锁定
+type lockingReader struct {
+	selected int
+	locked   map[string]bool
+}
+
+func (r *lockingReader) SelectUtxo(from string, amount *big.Int, lock, excludeUnconfirmed bool) ([]*protos.TxInput, [][]byte, *big.Int, error) {
+	key := []byte(fmt.Sprintf("%s_%d", from, r.selected))
+	r.selected++
+	r.locked[string(key)] = true
+	input := &protos.TxInput{RefTxid: key, FromAddr: []byte(from), Amount: amount.Bytes()}
+	return []*protos.TxInput{input}, [][]byte{key}, amount, nil
+}
+
+func (r *lockingReader) UnlockKey(utxoKey []byte) {
+	delete(r.locked, string(utxoKey))
+}
+
+func TestUTXOSandboxRevertUnlock(t *testing.T) {
+	reader := &lockingReader{locked: make(map[string]bool)}
+	sandbox := NewUTXOSandbox(&contract.SandboxConfig{UTXOReader: reader})
+
+	if err := sandbox.Transfer("alice", "bob", big.NewInt(1)); err != nil {
+		t.Fatal(err)
+	}
+	cp := sandbox.Checkpoint()
+	if err := sandbox.Transfer("alice", "carol", big.NewInt(2)); err != nil {
+		t.Fatal(err)
+	}
+	if len(reader.locked) != 2 {
+		t.Fatal("unexpected locked utxos", reader.locked)
+	}
+
+	// 回滚后被撤销的转账锁定的utxo被解锁，之前的保持锁定
+	sandbox.RevertToCheckpoint(cp)
+	if len(reader.locked) != 1 || !reader.locked["alice_0"] {
+		t.Fatal("unexpected locked utxos after revert", reader.locked)
+	}
+	rwSet := sandbox.GetUTXORWSets()
+	if len(rwSet.Rset) != 1 || len(rwSet.WSet) != 1 {
+		t.Fatal("unexpected utxo rwset after revert", rwSet)
+	}
+}
@@ -34,6 +34,13 @@ func makeRawKey(bucket string, key []byte) []byte {
 	return append(k, key...)
 }
 
+// bucketEnd 返回第一个大于bucket中所有key的raw key
+func bucketEnd(bucket string) []byte {
+	k := makeRawKey(bucket, nil)
+	k[len(k)-1]++
+	return k
+}
+
 func queryUnconfirmTx(txid []byte, table kvdb.Database) (*pb.Transaction, error) {
 	pbBuf, findErr := table.Get(txid)
 	if findErr != nil {
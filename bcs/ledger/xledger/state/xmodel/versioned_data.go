@@ -17,6 +17,11 @@ func parseVersion(version string) ([]byte, int, error) {
 	return txid, offset, nil
 }
 
+// ParseVersion parse version into txid and offset
+func ParseVersion(version string) ([]byte, int, error) {
+	return parseVersion(version)
+}
+
 //GetTxidFromVersion parse version and fetch txid from version string
 func GetTxidFromVersion(version string) []byte {
 	txid, _, err := parseVersion(version)
@@ -272,15 +272,32 @@ func (s *XModel) GetWithTxStatus(bucket string, key []byte) (*kledger.VersionedD
 }
 
 // Select select all kv from a bucket, can set key range, left closed, right opend
+// endKey为空时区间为空，已上链的交易依赖这一行为，不能修改
 func (s *XModel) Select(bucket string, startKey []byte, endKey []byte) (kledger.XMIterator, error) {
 	rawStartKey := makeRawKey(bucket, startKey)
 	rawEndKey := makeRawKey(bucket, endKey)
-	iter := &XMIterator{
-		bucket: bucket,
-		iter:   s.extUtxoTable.NewIteratorWithRange(rawStartKey, rawEndKey),
-		model:  s,
+	return s.newIterator(bucket, rawStartKey, rawEndKey, false), nil
+}
+
+// SelectRange likes Select, but an empty endKey means the end of bucket,
+// and iterates from the biggest key when reverse is true
+func (s *XModel) SelectRange(bucket string, startKey []byte, endKey []byte, reverse bool) (kledger.XMIterator, error) {
+	rawStartKey := makeRawKey(bucket, startKey)
+	rawEndKey := makeRawKey(bucket, endKey)
+	// 与沙盒中MemXModel的行为保持一致
+	if len(endKey) == 0 {
+		rawEndKey = bucketEnd(bucket)
+	}
+	return s.newIterator(bucket, rawStartKey, rawEndKey, reverse), nil
+}
+
+func (s *XModel) newIterator(bucket string, rawStartKey []byte, rawEndKey []byte, reverse bool) kledger.XMIterator {
+	return &XMIterator{
+		bucket:  bucket,
+		iter:    s.extUtxoTable.NewIteratorWithRange(rawStartKey, rawEndKey),
+		model:   s,
+		reverse: reverse,
 	}
-	return iter, nil
 }
 
 func (s *XModel) queryTx(txid []byte) (*pb.Transaction, bool, error) {
@@ -292,7 +309,7 @@ func (s *XModel) queryTx(txid []byte) (*pb.Transaction, bool, error) {
 	} else {
 		return unconfirmTx, false, nil
 	}
-	confirmedTx, err := s.ledger.QueryTransaction(txid)
+	confirmedTx, err := s.ledger.QueryTransactionState(txid)
 	if err != nil {
 		return nil, false, err
 	}
@@ -322,6 +339,11 @@ func (s *XModel) CleanCache() {
 	s.cleanCache(nil)
 }
 
+// BindBatch 并发执行同一个batch中的交易前绑定batch，避免DoTx在并发时切换batch缓存
+func (s *XModel) BindBatch(batch kvdb.Batch) {
+	s.cleanCache(batch)
+}
+
 func (s *XModel) cleanCache(newBatch kvdb.Batch) {
 	if newBatch != s.lastBatch {
 		s.batchCache = &sync.Map{}
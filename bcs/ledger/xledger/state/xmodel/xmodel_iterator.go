@@ -12,6 +12,10 @@ type XMIterator struct {
 	model  *XModel
 	value  *kledger.VersionedData
 	err    error
+
+	// reverse为true时从最大的key开始向前迭代
+	reverse bool
+	started bool
 }
 
 // Data get data pointer to VersionedData for XMIterator
@@ -21,7 +25,16 @@ func (di *XMIterator) Value() *kledger.VersionedData {
 
 // Next check if next element exist
 func (di *XMIterator) Next() bool {
-	ok := di.iter.Next()
+	var ok bool
+	switch {
+	case !di.reverse:
+		ok = di.iter.Next()
+	case !di.started:
+		ok = di.iter.Last()
+	default:
+		ok = di.iter.Prev()
+	}
+	di.started = true
 	if !ok {
 		return false
 	}
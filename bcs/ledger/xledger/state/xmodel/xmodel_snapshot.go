@@ -81,6 +81,10 @@ func (t *xModSnapshot) Select(bucket string, startKey []byte, endKey []byte) (kl
 	return nil, fmt.Errorf("xmodel snapshot temporarily not supported select")
 }
 
+func (t *xModSnapshot) SelectRange(bucket string, startKey []byte, endKey []byte, reverse bool) (kledger.XMIterator, error) {
+	return nil, fmt.Errorf("xmodel snapshot temporarily not supported select")
+}
+
 func (t *xModSnapshot) isInit() bool {
 	if t.xmod == nil || t.logger == nil || len(t.blkId) < 1 || t.blkHeight < 0 {
 		return false
@@ -90,7 +94,8 @@ func (t *xModSnapshot) isInit() bool {
 }
 
 func (t *xModSnapshot) getBlockHeight(blockid []byte) (int64, error) {
-	blkInfo, err := t.xmod.QueryBlock(blockid)
+	// 只需要区块高度，区块的交易可能已被裁剪
+	blkInfo, err := t.xmod.ledger.QueryBlockHeader(blockid)
 	if err != nil {
 		return 0, fmt.Errorf("query block info fail. block_id:%s err:%v",
 			hex.EncodeToString(blockid), err)
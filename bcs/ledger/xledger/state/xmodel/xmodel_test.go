@@ -198,6 +198,29 @@ func TestBaiscFunc(t *testing.T) {
 	if validKvCount != 1 {
 		t.Fatal("unexpected", validKvCount)
 	}
+	// Select中空的endKey保持原有语义，不返回任何数据
+	legacyIter, err := xModel.Select("bucket1", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if legacyIter.Next() {
+		t.Fatal("unexpected legacy select result", string(legacyIter.Key()))
+	}
+	legacyIter.Close()
+	for _, reverse := range []bool{false, true} {
+		riter, err := xModel.SelectRange("bucket1", nil, nil, reverse)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys := []string{}
+		for riter.Next() {
+			keys = append(keys, string(riter.Key()))
+		}
+		riter.Close()
+		if len(keys) != 1 || keys[0] != "world" {
+			t.Fatal("unexpected", reverse, keys)
+		}
+	}
 	_, isConfiremd, err := xModel.QueryTx(tx2.Txid)
 	if err != nil {
 		t.Fatal(err)
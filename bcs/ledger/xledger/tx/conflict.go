@@ -0,0 +1,104 @@
+package tx
+
+import (
+	"strconv"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/xmodel"
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+)
+
+// SplitToConflictGroups 按交易间的依赖和读写冲突把交易分成互不冲突的组，
+// 满足以下任一条件的两个交易在同一组:
+// 1. 后面的交易引用了前面交易的utxo或读写集版本；
+// 2. 两个交易花费同一个utxo；
+// 3. 两个交易读写同一个key，且至少一个是写。
+// 组内交易保持原有顺序，组按第一个交易的位置排序，不同组的交易可以并发执行。
+func SplitToConflictGroups(txs []*pb.Transaction) [][]*pb.Transaction {
+	parent := make([]int, len(txs))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(i, j int) {
+		ri, rj := find(i), find(j)
+		// 以位置靠前的交易作为组的代表，保证分组结果确定
+		if ri < rj {
+			parent[rj] = ri
+		} else if rj < ri {
+			parent[ri] = rj
+		}
+	}
+
+	txIndex := make(map[string]int, len(txs))
+	writers := make(map[string]int)
+	readers := make(map[string][]int)
+	read := func(i int, key string) {
+		if w, ok := writers[key]; ok {
+			union(w, i)
+		}
+		readers[key] = append(readers[key], i)
+	}
+	write := func(i int, key string) {
+		if w, ok := writers[key]; ok {
+			union(w, i)
+		}
+		for _, r := range readers[key] {
+			union(r, i)
+		}
+		delete(readers, key)
+		writers[key] = i
+	}
+
+	for i, tx := range txs {
+		for _, input := range tx.TxInputs {
+			if j, ok := txIndex[string(input.RefTxid)]; ok {
+				union(j, i)
+			}
+			write(i, utxoConflictKey(input.FromAddr, input.RefTxid, input.RefOffset))
+		}
+		for _, input := range tx.TxInputsExt {
+			if j, ok := txIndex[string(input.RefTxid)]; ok {
+				union(j, i)
+			}
+			if input.Bucket == xmodel.TransientBucket {
+				continue
+			}
+			read(i, extConflictKey(input.Bucket, input.Key))
+		}
+		for _, output := range tx.TxOutputsExt {
+			if output.Bucket == xmodel.TransientBucket {
+				continue
+			}
+			write(i, extConflictKey(output.Bucket, output.Key))
+		}
+		txIndex[string(tx.Txid)] = i
+	}
+
+	groupIndex := make(map[int]int)
+	var groups [][]*pb.Transaction
+	for i, tx := range txs {
+		root := find(i)
+		idx, ok := groupIndex[root]
+		if !ok {
+			idx = len(groups)
+			groupIndex[root] = idx
+			groups = append(groups, nil)
+		}
+		groups[idx] = append(groups[idx], tx)
+	}
+	return groups
+}
+
+func utxoConflictKey(addr []byte, txid []byte, offset int32) string {
+	return "u" + string(addr) + "_" + string(txid) + "_" + strconv.Itoa(int(offset))
+}
+
+func extConflictKey(bucket string, key []byte) string {
+	return "x" + bucket + "/" + string(key)
+}
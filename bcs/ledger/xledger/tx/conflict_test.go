@@ -0,0 +1,56 @@
+package tx
+
+import (
+	"testing"
+
+	pb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func TestSplitToConflictGroups(t *testing.T) {
+	txs := []*pb.Transaction{
+		// tx0写k1
+		{Txid: []byte("tx0"), TxOutputsExt: []*protos.TxOutputExt{{Bucket: "b", Key: []byte("k1"), Value: []byte("v")}}},
+		// tx1只读k2，tx2只读k2，只读不冲突
+		{Txid: []byte("tx1"), TxInputsExt: []*protos.TxInputExt{{Bucket: "b", Key: []byte("k2")}}},
+		{Txid: []byte("tx2"), TxInputsExt: []*protos.TxInputExt{{Bucket: "b", Key: []byte("k2")}}},
+		// tx3读tx0写的k1
+		{Txid: []byte("tx3"), TxInputsExt: []*protos.TxInputExt{{Bucket: "b", Key: []byte("k1"), RefTxid: []byte("tx0")}}},
+		// tx4写k2，与读k2的tx1、tx2冲突
+		{Txid: []byte("tx4"), TxOutputsExt: []*protos.TxOutputExt{{Bucket: "b", Key: []byte("k2"), Value: []byte("v")}}},
+		// tx5引用tx0的utxo
+		{Txid: []byte("tx5"), TxInputs: []*protos.TxInput{{RefTxid: []byte("tx0"), FromAddr: []byte("alice")}}},
+		// tx6和tx7花费同一个utxo
+		{Txid: []byte("tx6"), TxInputs: []*protos.TxInput{{RefTxid: []byte("old"), RefOffset: 1, FromAddr: []byte("bob")}}},
+		{Txid: []byte("tx7"), TxInputs: []*protos.TxInput{{RefTxid: []byte("old"), RefOffset: 1, FromAddr: []byte("bob")}}},
+		// 临时bucket不冲突
+		{Txid: []byte("tx8"), TxOutputsExt: []*protos.TxOutputExt{{Bucket: "$transient", Key: []byte("k1")}}},
+		{Txid: []byte("tx9"), TxOutputsExt: []*protos.TxOutputExt{{Bucket: "$transient", Key: []byte("k1")}}},
+	}
+	expect := [][]string{
+		{"tx0", "tx3", "tx5"},
+		{"tx1", "tx2", "tx4"},
+		{"tx6", "tx7"},
+		{"tx8"},
+		{"tx9"},
+	}
+
+	groups := SplitToConflictGroups(txs)
+	if len(groups) != len(expect) {
+		t.Fatalf("expect %d groups, got %d", len(expect), len(groups))
+	}
+	for i, group := range groups {
+		if len(group) != len(expect[i]) {
+			t.Fatalf("group %d expect %v, got %d txs", i, expect[i], len(group))
+		}
+		for j, tx := range group {
+			if string(tx.Txid) != expect[i][j] {
+				t.Fatalf("group %d expect %v, got %s at %d", i, expect[i], tx.Txid, j)
+			}
+		}
+	}
+
+	if groups := SplitToConflictGroups(nil); len(groups) != 0 {
+		t.Fatal("expect no group for empty txs")
+	}
+}
@@ -87,6 +87,20 @@ func GenerateAwardTx(address, awardAmount string, desc []byte) (*pb.Transaction,
 	return utxoTx, nil
 }
 
+// 生成按比例分配的奖励TX
+func GenerateAwardTxWithOutputs(outputs []*protos.TxOutput, desc []byte) (*pb.Transaction, error) {
+	if len(outputs) == 0 {
+		return nil, ErrUnexpected
+	}
+	utxoTx := &pb.Transaction{Version: TxVersion}
+	utxoTx.TxOutputs = outputs
+	utxoTx.Desc = desc
+	utxoTx.Coinbase = true
+	utxoTx.Timestamp = time.Now().UnixNano()
+	utxoTx.Txid, _ = txhash.MakeTransactionID(utxoTx)
+	return utxoTx, nil
+}
+
 // 生成只有Desc的空交易
 func GenerateEmptyTx(desc []byte) (*pb.Transaction, error) {
 	utxoTx := &pb.Transaction{Version: TxVersion}
@@ -12,4 +12,10 @@ const (
 	ExtUtxoTablePrefix       = "ZU"
 	BlockHeightPrefix        = "ZH"
 	BranchInfoPrefix         = "ZI"
+	StateTreeTablePrefix     = "ZT" // 状态树节点，按节点哈希寻址
+	PrunedTxTablePrefix      = "ZP" // 已裁剪的交易，只保留所在区块和状态相关字段
+	NonceTablePrefix         = "ZN" // 有效期内已使用的nonce，key为initiator/nonce/txid
+	NonceExpireTablePrefix   = "ZE" // nonce按有效期的索引，用于清理过期记录
+	StorageUsageTablePrefix  = "ZS" // 合约已确认的存储用量，key为合约名
+	FeeParamsTablePrefix     = "ZG" // 开启费用市场的区块执行时的gas价格和保留合约，key为blockid
 )
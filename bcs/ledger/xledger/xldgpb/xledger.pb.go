@@ -448,7 +448,10 @@ type Transaction struct {
 	// 可修改区块链标记
 	ModifyBlock *ModifyBlock `protobuf:"bytes,32,opt,name=modify_block,json=modifyBlock,proto3" json:"modify_block,omitempty"`
 	// HD加解密相关信息
-	HDInfo               *HDInfo  `protobuf:"bytes,33,opt,name=HD_info,json=HDInfo,proto3" json:"HD_info,omitempty"`
+	HDInfo *HDInfo `protobuf:"bytes,33,opt,name=HD_info,json=HDInfo,proto3" json:"HD_info,omitempty"`
+	// 交易有效期，交易只能被打包进不高于该高度的区块，为0表示不限制；
+	// 设置有效期的交易，有效期内同一发起者的nonce不能重复使用
+	ValidUntilHeight     int64    `protobuf:"varint,34,opt,name=valid_until_height,json=validUntilHeight,proto3" json:"valid_until_height,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -626,6 +629,13 @@ func (m *Transaction) GetHDInfo() *HDInfo {
 	return nil
 }
 
+func (m *Transaction) GetValidUntilHeight() int64 {
+	if m != nil {
+		return m.ValidUntilHeight
+	}
+	return 0
+}
+
 // Ledger metadata
 type LedgerMeta struct {
 	// root block id
@@ -867,6 +877,10 @@ type InternalBlock struct {
 	TargetBits  int32             `protobuf:"varint,19,opt,name=targetBits,proto3" json:"targetBits,omitempty"`
 	// Justify used in chained-bft
 	Justify *QuorumCert `protobuf:"bytes,20,opt,name=Justify,proto3" json:"Justify,omitempty"`
+	// 区块执行后的状态根，由xmodel和utxo构成的稀疏merkle树计算
+	StateRoot []byte `protobuf:"bytes,21,opt,name=state_root,json=stateRoot,proto3" json:"state_root,omitempty"`
+	// 开启费用市场时区块的基础费用(每单位gas)，由父区块的基础费用和大小计算
+	BaseFee []byte `protobuf:"bytes,22,opt,name=base_fee,json=baseFee,proto3" json:"base_fee,omitempty"`
 	// 下面的属性会动态变化
 	// If the block is on the trunk
 	InTrunk bool `protobuf:"varint,14,opt,name=in_trunk,json=inTrunk,proto3" json:"in_trunk,omitempty"`
@@ -1028,6 +1042,20 @@ func (m *InternalBlock) GetJustify() *QuorumCert {
 	return nil
 }
 
+func (m *InternalBlock) GetStateRoot() []byte {
+	if m != nil {
+		return m.StateRoot
+	}
+	return nil
+}
+
+func (m *InternalBlock) GetBaseFee() []byte {
+	if m != nil {
+		return m.BaseFee
+	}
+	return nil
+}
+
 func (m *InternalBlock) GetInTrunk() bool {
 	if m != nil {
 		return m.InTrunk
@@ -1123,7 +1151,9 @@ type ModifyBlock struct {
 	// 监管的public key
 	PublicKey string `protobuf:"bytes,4,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
 	// 监管地址对修改的交易id的签名
-	Sign                 string   `protobuf:"bytes,5,opt,name=sign,proto3" json:"sign,omitempty"`
+	Sign string `protobuf:"bytes,5,opt,name=sign,proto3" json:"sign,omitempty"`
+	// 被脱敏的字段，为空表示整笔交易被标记
+	RedactedFields       []string `protobuf:"bytes,6,rep,name=redacted_fields,json=redactedFields,proto3" json:"redacted_fields,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1189,6 +1219,13 @@ func (m *ModifyBlock) GetSign() string {
 	return ""
 }
 
+func (m *ModifyBlock) GetRedactedFields() []string {
+	if m != nil {
+		return m.RedactedFields
+	}
+	return nil
+}
+
 type TxDataAccount struct {
 	// 地址
 	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
@@ -1509,6 +1546,73 @@ func (m *UtxoOutput) GetTotalSelected() string {
 	return ""
 }
 
+// TxMerkleProof 交易在区块中的merkle证明
+type TxMerkleProof struct {
+	Tx *Transaction `protobuf:"bytes,1,opt,name=tx,proto3" json:"tx,omitempty"`
+	// 区块头，不包含交易和merkle树
+	Header *InternalBlock `protobuf:"bytes,2,opt,name=header,proto3" json:"header,omitempty"`
+	// 交易在区块中的序号
+	Index int32 `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
+	// 自叶子向上的兄弟节点哈希，为空表示没有右兄弟
+	Branch               [][]byte `protobuf:"bytes,4,rep,name=branch,proto3" json:"branch,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TxMerkleProof) Reset()         { *m = TxMerkleProof{} }
+func (m *TxMerkleProof) String() string { return proto.CompactTextString(m) }
+func (*TxMerkleProof) ProtoMessage()    {}
+func (*TxMerkleProof) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b639a3762518476d, []int{17}
+}
+
+func (m *TxMerkleProof) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxMerkleProof.Unmarshal(m, b)
+}
+func (m *TxMerkleProof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxMerkleProof.Marshal(b, m, deterministic)
+}
+func (m *TxMerkleProof) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxMerkleProof.Merge(m, src)
+}
+func (m *TxMerkleProof) XXX_Size() int {
+	return xxx_messageInfo_TxMerkleProof.Size(m)
+}
+func (m *TxMerkleProof) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxMerkleProof.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxMerkleProof proto.InternalMessageInfo
+
+func (m *TxMerkleProof) GetTx() *Transaction {
+	if m != nil {
+		return m.Tx
+	}
+	return nil
+}
+
+func (m *TxMerkleProof) GetHeader() *InternalBlock {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *TxMerkleProof) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *TxMerkleProof) GetBranch() [][]byte {
+	if m != nil {
+		return m.Branch
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("xldgpb.TransactionStatus", TransactionStatus_name, TransactionStatus_value)
 	proto.RegisterEnum("xldgpb.BlockStatus", BlockStatus_name, BlockStatus_value)
@@ -1531,6 +1635,7 @@ func init() {
 	proto.RegisterType((*UtxoRecordDetail)(nil), "xldgpb.UtxoRecordDetail")
 	proto.RegisterType((*BalanceDetailInfo)(nil), "xldgpb.BalanceDetailInfo")
 	proto.RegisterType((*UtxoOutput)(nil), "xldgpb.UtxoOutput")
+	proto.RegisterType((*TxMerkleProof)(nil), "xldgpb.TxMerkleProof")
 }
 
 func init() {
@@ -1538,129 +1643,138 @@ func init() {
 }
 
 var fileDescriptor_b639a3762518476d = []byte{
-	// 1983 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x58, 0xeb, 0x52, 0x1b, 0xc9,
-	0x15, 0xb6, 0x10, 0x20, 0xe9, 0xe8, 0x82, 0x68, 0x3b, 0xde, 0x59, 0xbc, 0xb6, 0xb1, 0xec, 0xd4,
-	0x12, 0x97, 0x03, 0x15, 0xa7, 0xb2, 0xd9, 0xcd, 0xad, 0x0a, 0x24, 0xb1, 0x28, 0x80, 0xc0, 0xcd,
-	0x60, 0x53, 0xa9, 0x54, 0x4d, 0x8d, 0x46, 0x2d, 0xa9, 0x0b, 0x69, 0x5a, 0xe9, 0xee, 0x81, 0xc1,
-	0x8f, 0x90, 0x47, 0xc8, 0x23, 0xe4, 0x41, 0xf2, 0x2f, 0xaf, 0x93, 0xdf, 0xa9, 0xbe, 0x8d, 0x46,
-	0xb8, 0xf0, 0x2f, 0xf5, 0xf9, 0xfa, 0x9c, 0xd3, 0x7d, 0xee, 0x3d, 0x82, 0xef, 0x07, 0x91, 0xd8,
-	0x9b, 0x92, 0xe1, 0x98, 0xf0, 0xbd, 0x34, 0xfb, 0x1d, 0x8e, 0xe7, 0x03, 0x47, 0xee, 0xce, 0x39,
-	0x93, 0x0c, 0xad, 0x1b, 0x74, 0xeb, 0x65, 0x9a, 0xcc, 0x09, 0x8f, 0x18, 0x27, 0x7b, 0x7a, 0x43,
-	0xec, 0x45, 0x2c, 0x96, 0x3c, 0x8c, 0xa4, 0x61, 0xdc, 0x7a, 0xfe, 0x05, 0x43, 0x5e, 0xcf, 0xd6,
-	0xab, 0x2f, 0xb6, 0xe7, 0x84, 0xcf, 0xa8, 0x10, 0x94, 0xc5, 0x86, 0xa5, 0xb5, 0x0f, 0xd5, 0x0f,
-	0xed, 0x0b, 0x3a, 0x8e, 0x7b, 0xf1, 0x88, 0x09, 0xf4, 0x7e, 0x89, 0xf4, 0x0a, 0xdb, 0xc5, 0x9d,
-	0xea, 0xfb, 0xe6, 0xae, 0xb9, 0xcf, 0xae, 0xdb, 0xc0, 0x79, 0xa6, 0xd6, 0x47, 0x28, 0x3b, 0x02,
-	0x79, 0x50, 0xda, 0x1f, 0x0e, 0x39, 0x11, 0x4a, 0xb6, 0xb0, 0x53, 0xc1, 0x8e, 0x44, 0xdf, 0x41,
-	0xe5, 0x3c, 0x19, 0x4c, 0x69, 0x74, 0x4c, 0xee, 0xbc, 0x15, 0xbd, 0xb7, 0x00, 0x10, 0x82, 0x55,
-	0xa5, 0xc3, 0x2b, 0x6e, 0x17, 0x76, 0x6a, 0x58, 0xaf, 0x5b, 0xff, 0x29, 0x00, 0x7c, 0x48, 0x18,
-	0x4f, 0x66, 0x6d, 0xc2, 0x25, 0x7a, 0x01, 0x70, 0xce, 0xd9, 0x9c, 0x89, 0x70, 0xda, 0x1b, 0x6a,
-	0xed, 0x35, 0x9c, 0x43, 0xd0, 0x36, 0x54, 0x1d, 0x75, 0x2a, 0xc6, 0xfa, 0x88, 0x1a, 0xce, 0x43,
-	0xe8, 0x35, 0xac, 0xfa, 0x77, 0x73, 0xa2, 0x0f, 0x69, 0xbc, 0xdf, 0x70, 0x56, 0x7d, 0x68, 0x5f,
-	0xc8, 0x50, 0x12, 0xac, 0x37, 0xd5, 0x31, 0x1f, 0x29, 0xb9, 0xed, 0x27, 0xb3, 0x01, 0xe1, 0xde,
-	0xea, 0x76, 0x61, 0xa7, 0x88, 0x73, 0x08, 0xfa, 0x0d, 0x54, 0x16, 0xfe, 0x59, 0xdb, 0x2e, 0xec,
-	0x54, 0xdf, 0x3f, 0xce, 0x69, 0x72, 0x5b, 0x78, 0xc1, 0xd5, 0xfa, 0x00, 0xeb, 0x47, 0x1d, 0xed,
-	0x9e, 0x16, 0xd4, 0x27, 0xc3, 0x60, 0xae, 0xcd, 0x0e, 0xae, 0xc9, 0x9d, 0x35, 0xa3, 0x3a, 0x19,
-	0x2e, 0x5c, 0xf1, 0x1a, 0xea, 0x8c, 0xd3, 0x31, 0x8d, 0xc3, 0x69, 0x30, 0x09, 0xc5, 0xc4, 0x5a,
-	0x52, 0x73, 0xe0, 0x51, 0x28, 0x26, 0xad, 0x33, 0x68, 0x5c, 0xa9, 0xd8, 0xaa, 0x43, 0x42, 0x99,
-	0x70, 0x82, 0x5e, 0x42, 0x75, 0xa1, 0xd7, 0x44, 0xae, 0x86, 0x61, 0xee, 0xd4, 0xea, 0x00, 0x08,
-	0xc7, 0x6d, 0x75, 0x2e, 0x80, 0xd6, 0xff, 0xd6, 0xa1, 0xea, 0xf3, 0x30, 0x16, 0x61, 0x24, 0x29,
-	0x8b, 0x55, 0x40, 0x64, 0x4a, 0x9d, 0x9f, 0xf5, 0x5a, 0x05, 0x77, 0x30, 0x65, 0xd1, 0x35, 0x1d,
-	0x5a, 0x79, 0x47, 0xa2, 0x77, 0x50, 0x91, 0x69, 0x40, 0xe3, 0x79, 0x22, 0x85, 0x57, 0xd4, 0x49,
-	0xb3, 0x61, 0x12, 0x4c, 0xec, 0xfa, 0x69, 0x4f, 0xe1, 0xb8, 0x2c, 0xcd, 0x42, 0xa0, 0x3d, 0x00,
-	0x99, 0x06, 0x2c, 0x91, 0x9a, 0x7d, 0xd5, 0xe6, 0x58, 0xc6, 0x7e, 0xa6, 0x37, 0x70, 0x45, 0xda,
-	0x95, 0x50, 0x97, 0x19, 0x12, 0x11, 0x79, 0xeb, 0xe6, 0x32, 0x6a, 0x8d, 0xb6, 0xa0, 0x1c, 0x31,
-	0x1a, 0x0f, 0x42, 0x41, 0xbc, 0xd2, 0x76, 0x61, 0xa7, 0x8c, 0x33, 0x1a, 0x3d, 0x81, 0xb5, 0x98,
-	0xc5, 0x11, 0xf1, 0xca, 0x3a, 0xcf, 0x0c, 0xa1, 0x1c, 0x20, 0xe9, 0x8c, 0x08, 0x19, 0xce, 0xe6,
-	0x5e, 0x45, 0x07, 0x76, 0x01, 0x28, 0xe3, 0x6e, 0x08, 0x57, 0x95, 0xe1, 0xc1, 0x76, 0x61, 0x67,
-	0x0d, 0x3b, 0x52, 0xed, 0x84, 0x89, 0x64, 0x63, 0x12, 0x7b, 0x55, 0x7d, 0x90, 0x23, 0xd1, 0x0f,
-	0x50, 0xcf, 0xcc, 0x0e, 0x48, 0x2a, 0xbd, 0x6f, 0xb4, 0x2d, 0xe8, 0x9e, 0xe9, 0xdd, 0x54, 0xe2,
-	0xaa, 0xb3, 0xbe, 0x9b, 0x4a, 0xf4, 0x13, 0x34, 0x16, 0x0e, 0xd0, 0x82, 0x9e, 0x16, 0x7c, 0x7c,
-	0xdf, 0x09, 0x4a, 0xb2, 0x96, 0xf9, 0x41, 0x89, 0x1e, 0xc0, 0xa6, 0xeb, 0x01, 0x01, 0x27, 0xff,
-	0x48, 0x88, 0x90, 0xc2, 0xfb, 0x56, 0x4b, 0xff, 0xc2, 0x49, 0xf7, 0xe2, 0x1b, 0x76, 0x4d, 0xb0,
-	0xd9, 0xc5, 0x4d, 0xc7, 0x6f, 0x01, 0x9d, 0x09, 0x34, 0xa6, 0x92, 0x86, 0x92, 0x71, 0x6f, 0xcb,
-	0x94, 0x62, 0x06, 0xa0, 0x57, 0x50, 0x0b, 0x13, 0x39, 0xd1, 0xda, 0x29, 0x27, 0xde, 0xb3, 0xed,
-	0xe2, 0x4e, 0x05, 0x57, 0x15, 0x86, 0x0d, 0x84, 0xfe, 0x02, 0x1b, 0x19, 0x7f, 0xa0, 0x72, 0x48,
-	0x78, 0xdf, 0x2d, 0x5f, 0x21, 0xcb, 0x4b, 0xdd, 0x2e, 0x1a, 0x19, 0xb7, 0xc2, 0x05, 0x6a, 0x03,
-	0xca, 0x1f, 0x61, 0x55, 0x3c, 0xff, 0x9a, 0x8a, 0x66, 0xee, 0x7c, 0xa3, 0xe4, 0xd7, 0x80, 0x38,
-	0x89, 0x08, 0xbd, 0x21, 0xc3, 0x60, 0x11, 0xd7, 0x17, 0x3a, 0xae, 0x9b, 0x6e, 0xc7, 0xcf, 0xe2,
-	0xfb, 0x3b, 0x00, 0xdd, 0x0d, 0xf5, 0x61, 0xde, 0x4b, 0x5d, 0xb8, 0x4f, 0x5d, 0xe1, 0x2e, 0xd7,
-	0x12, 0xae, 0xa4, 0x8e, 0x46, 0x3f, 0x40, 0x6d, 0xc6, 0x86, 0x74, 0x74, 0x17, 0xe8, 0x5c, 0xf7,
-	0xb6, 0x97, 0x2b, 0xfe, 0x54, 0xef, 0x1d, 0xa8, 0x2d, 0x5c, 0x9d, 0x2d, 0x08, 0xf4, 0x3d, 0x94,
-	0x8e, 0x3a, 0x01, 0x8d, 0x47, 0xcc, 0x7b, 0xa5, 0x45, 0x1a, 0x4e, 0xc4, 0xb4, 0x02, 0x6c, 0x5b,
-	0x42, 0x4b, 0x00, 0x9c, 0xe8, 0x9e, 0x7d, 0x4a, 0x64, 0xa8, 0x9c, 0xcf, 0x19, 0x93, 0x81, 0xab,
-	0x33, 0xdb, 0x1f, 0x14, 0x76, 0x60, 0x6b, 0xed, 0x25, 0x54, 0x25, 0x9d, 0x07, 0xcb, 0x95, 0x08,
-	0x92, 0xce, 0x1d, 0xc3, 0x2b, 0xa8, 0x49, 0x9e, 0xc4, 0xd7, 0xc1, 0x84, 0xd0, 0xf1, 0x44, 0xea,
-	0x76, 0x57, 0xc4, 0x55, 0x8d, 0x1d, 0x69, 0xa8, 0xf5, 0xaf, 0x35, 0x28, 0x5f, 0xca, 0x94, 0xe9,
-	0x33, 0x7f, 0x09, 0x8d, 0x69, 0x28, 0x89, 0xb8, 0x7f, 0x6a, 0xdd, 0xa0, 0x4e, 0x6d, 0x0b, 0xea,
-	0x6a, 0xa5, 0xda, 0x4b, 0x30, 0xa5, 0x42, 0x7a, 0x2b, 0x26, 0x31, 0x14, 0x78, 0x4c, 0xee, 0x4e,
-	0xa8, 0x90, 0xe8, 0x39, 0x40, 0x22, 0x53, 0x16, 0x48, 0x26, 0xc3, 0xa9, 0x3e, 0xb8, 0x82, 0x2b,
-	0x0a, 0xf1, 0x15, 0xa0, 0x6a, 0x36, 0xbc, 0x19, 0x77, 0xc8, 0x34, 0xbc, 0xb3, 0x9d, 0x35, 0xa3,
-	0xd1, 0x3b, 0xd8, 0x4c, 0xe2, 0x88, 0xc5, 0x23, 0xca, 0x67, 0x7e, 0xba, 0x3f, 0x63, 0x49, 0x2c,
-	0x75, 0x7f, 0x2d, 0xe2, 0x2f, 0x37, 0xd0, 0x1b, 0x68, 0xcc, 0xc2, 0xd4, 0x5c, 0x38, 0x10, 0xf4,
-	0x33, 0xd1, 0xbd, 0xa1, 0x88, 0x6b, 0xb3, 0x30, 0xd5, 0x17, 0xbe, 0xa0, 0x9f, 0x09, 0xea, 0xa8,
-	0x14, 0x11, 0x84, 0xab, 0x14, 0x71, 0x55, 0x20, 0xbc, 0xd2, 0xd7, 0xaa, 0x65, 0xd3, 0x09, 0xb4,
-	0x1d, 0xbf, 0xd2, 0x32, 0x62, 0x7c, 0x40, 0x87, 0x43, 0x12, 0x67, 0x6a, 0x74, 0x6b, 0x79, 0x58,
-	0x4b, 0x26, 0xe0, 0xd4, 0xa0, 0x3f, 0xc3, 0xb3, 0x98, 0xdc, 0x06, 0x61, 0x14, 0x29, 0x03, 0x02,
-	0x4e, 0x04, 0x4b, 0x78, 0x44, 0x82, 0xd0, 0x58, 0x6a, 0xfa, 0x91, 0x17, 0x93, 0xdb, 0x7d, 0xc3,
-	0x81, 0x2d, 0x83, 0x35, 0xf8, 0x47, 0xf8, 0x86, 0x72, 0x4e, 0x74, 0x4f, 0x1a, 0x4c, 0x89, 0xb6,
-	0xd1, 0x04, 0x53, 0xb7, 0xab, 0x22, 0x7e, 0x68, 0xfb, 0xbe, 0xe4, 0xc5, 0x94, 0x0e, 0xc9, 0x27,
-	0x1a, 0x0f, 0xd9, 0xad, 0x6e, 0x67, 0xf7, 0x24, 0x73, 0xdb, 0xe8, 0x1d, 0x94, 0xc7, 0xa1, 0x38,
-	0xe7, 0x34, 0x22, 0x5e, 0x4d, 0x9b, 0x9b, 0x75, 0xe9, 0x9f, 0x2d, 0x8e, 0x33, 0x0e, 0xf4, 0x33,
-	0x3c, 0x19, 0x73, 0x96, 0xcc, 0x83, 0x68, 0x12, 0xd2, 0x9c, 0xa3, 0xea, 0x5f, 0x73, 0x14, 0xd2,
-	0x22, 0x6d, 0x25, 0xe1, 0x3c, 0xd5, 0xfa, 0xef, 0x1a, 0xd4, 0x7b, 0xb1, 0x24, 0x3c, 0x0e, 0xa7,
-	0xa6, 0x98, 0x72, 0xbd, 0xb9, 0xb0, 0xdc, 0x9b, 0xb3, 0x4e, 0xbf, 0xa2, 0x71, 0xdb, 0xe9, 0x73,
-	0x83, 0xaa, 0xb8, 0x3c, 0xa8, 0xbe, 0x85, 0xf2, 0x9c, 0x13, 0x33, 0x57, 0x57, 0xcd, 0xd6, 0x9c,
-	0x13, 0x35, 0x52, 0x55, 0x72, 0xce, 0xf5, 0x63, 0x81, 0x70, 0x9d, 0x77, 0x35, 0x9c, 0xd1, 0x6a,
-	0x00, 0xe9, 0xb6, 0x61, 0x07, 0x90, 0x5a, 0xa3, 0xa7, 0xb0, 0x3e, 0x4f, 0x06, 0x6a, 0x88, 0x97,
-	0x34, 0x6a, 0x29, 0x55, 0x9f, 0x33, 0xc2, 0xaf, 0xa7, 0x24, 0x50, 0x55, 0xab, 0xf3, 0xa4, 0x86,
-	0xc1, 0x40, 0x98, 0x31, 0xa9, 0x04, 0x6d, 0x65, 0x9a, 0xa0, 0x5b, 0x6a, 0x79, 0x3e, 0xc1, 0xfd,
-	0xf9, 0xf4, 0x7b, 0x55, 0xd5, 0xd9, 0x7c, 0x16, 0x5e, 0xd5, 0x4e, 0x0c, 0xdb, 0x55, 0x72, 0xb3,
-	0x1b, 0x2f, 0x31, 0x2a, 0x93, 0x65, 0x1a, 0xe8, 0x9c, 0xd2, 0x51, 0x5c, 0xc3, 0x25, 0x99, 0xb6,
-	0x75, 0x52, 0x2d, 0xae, 0x2a, 0x39, 0x21, 0x5e, 0xdd, 0xbc, 0x19, 0x0c, 0xe4, 0x73, 0xa2, 0x1d,
-	0x19, 0x25, 0xdc, 0x27, 0x7c, 0xe6, 0x35, 0xf5, 0x85, 0x1c, 0xa9, 0x5e, 0x5b, 0x51, 0xc2, 0x75,
-	0x78, 0xfa, 0xc9, 0xcc, 0xdb, 0x34, 0x3d, 0x26, 0x07, 0xa1, 0x36, 0xc0, 0x28, 0xa4, 0x53, 0xd5,
-	0x9d, 0x53, 0xe1, 0x21, 0x7d, 0xdd, 0x37, 0xee, 0xba, 0x4b, 0xf1, 0xdd, 0x3d, 0xd4, 0x7c, 0x7e,
-	0x2a, 0xba, 0xb1, 0xe4, 0x77, 0xb8, 0x32, 0x72, 0xb4, 0x7a, 0x8d, 0xc9, 0x90, 0x8f, 0x89, 0x3c,
-	0xa0, 0x52, 0x78, 0x8f, 0xf5, 0xf5, 0x73, 0x08, 0x7a, 0x07, 0xa5, 0xbf, 0x26, 0x42, 0xd2, 0xd1,
-	0x9d, 0xf7, 0x44, 0xe7, 0x19, 0xca, 0xde, 0x62, 0xd9, 0xcb, 0x11, 0x3b, 0x16, 0xe5, 0x0a, 0x1a,
-	0x07, 0xba, 0x11, 0x7a, 0x0d, 0x33, 0xca, 0x69, 0xec, 0x2b, 0x12, 0x3d, 0x83, 0x4a, 0x4c, 0x52,
-	0x69, 0x32, 0x63, 0xc3, 0x84, 0x5f, 0x01, 0x2a, 0x35, 0xb6, 0xfe, 0x04, 0x8d, 0xe5, 0x2b, 0xa2,
-	0x26, 0x14, 0xdd, 0xf3, 0xad, 0x82, 0xd5, 0x52, 0x65, 0xe2, 0x4d, 0x38, 0x4d, 0x88, 0x7d, 0xdb,
-	0x1a, 0xe2, 0x0f, 0x2b, 0x3f, 0x16, 0x5a, 0xff, 0x2c, 0xc0, 0xaa, 0x6a, 0xb6, 0x2a, 0xf0, 0xb6,
-	0xda, 0x4d, 0x83, 0xb5, 0x94, 0xc2, 0x25, 0x53, 0xef, 0x64, 0xdb, 0xcc, 0x2d, 0xa5, 0x32, 0x52,
-	0xb2, 0x73, 0x93, 0x63, 0x26, 0x8f, 0x33, 0x5a, 0x45, 0x86, 0x93, 0x91, 0xaf, 0x9e, 0x68, 0x36,
-	0x8f, 0x2d, 0xa9, 0xd2, 0x88, 0x93, 0xd1, 0xd9, 0x68, 0x24, 0x88, 0x69, 0xa0, 0x6b, 0x78, 0x01,
-	0xb4, 0xfe, 0x5d, 0x80, 0x6a, 0x6e, 0x68, 0xa9, 0xe6, 0x4f, 0x46, 0x23, 0x12, 0x49, 0x7a, 0x43,
-	0x82, 0xec, 0xc5, 0x57, 0xc1, 0xf5, 0x0c, 0xd5, 0x4a, 0x9f, 0xc2, 0xfa, 0x2c, 0xe4, 0xd7, 0xc4,
-	0xcc, 0x9b, 0x32, 0xb6, 0x14, 0xfa, 0x15, 0x34, 0x17, 0xe2, 0x4b, 0xf3, 0x66, 0x23, 0xc3, 0x6d,
-	0x1f, 0x7a, 0x0e, 0x90, 0x7b, 0xf8, 0xae, 0x9a, 0xd9, 0x30, 0xcf, 0x7f, 0x01, 0xe8, 0x12, 0x5b,
-	0xd3, 0x1b, 0x7a, 0xdd, 0x1a, 0x41, 0xdd, 0x4f, 0x3b, 0xa1, 0x0c, 0x6d, 0x4f, 0xd4, 0x4f, 0xb1,
-	0xe5, 0xcf, 0x0b, 0x4b, 0xe6, 0x7c, 0x6b, 0xfc, 0xef, 0x7c, 0xfb, 0x1a, 0xea, 0x23, 0xce, 0x3e,
-	0x93, 0x78, 0xf9, 0x76, 0x35, 0x03, 0xda, 0x71, 0xc8, 0x00, 0x54, 0x80, 0x30, 0x89, 0x18, 0xd7,
-	0x0e, 0x54, 0x23, 0xab, 0x9d, 0x45, 0xca, 0xce, 0x30, 0x53, 0x33, 0x2f, 0xcc, 0x88, 0xdb, 0xcf,
-	0x1f, 0x96, 0x43, 0xd4, 0x47, 0x06, 0x95, 0x64, 0x96, 0xbd, 0x82, 0x6d, 0x3a, 0x2a, 0xfd, 0xc7,
-	0xe4, 0x0e, 0xeb, 0xcd, 0xd6, 0x05, 0x94, 0x2c, 0x90, 0x0f, 0xa4, 0x35, 0xc9, 0x05, 0xf2, 0x29,
-	0xac, 0x33, 0x13, 0x45, 0x6b, 0x92, 0xa1, 0x72, 0xa6, 0x16, 0xf3, 0xa6, 0xaa, 0xd0, 0x36, 0x17,
-	0x66, 0x74, 0x88, 0x0c, 0xe9, 0x14, 0xed, 0x42, 0x99, 0xcd, 0x49, 0xac, 0x70, 0xad, 0x3f, 0x57,
-	0x21, 0x0b, 0x5e, 0x9c, 0xf1, 0xa0, 0xf7, 0x00, 0x2a, 0x2f, 0xc8, 0x50, 0x4b, 0xac, 0x3c, 0x28,
-	0x91, 0xe3, 0x52, 0x32, 0xc6, 0x9d, 0x5a, 0xa6, 0xf8, 0xb0, 0xcc, 0x82, 0xab, 0xd5, 0x83, 0xcd,
-	0x83, 0x70, 0x1a, 0xc6, 0x11, 0x31, 0x17, 0x75, 0x5f, 0x8f, 0x03, 0x03, 0x3a, 0x5f, 0x58, 0x52,
-	0x95, 0x02, 0x15, 0x87, 0x5a, 0xdc, 0x66, 0x60, 0x46, 0xb7, 0xfe, 0x6e, 0xa2, 0x67, 0x1e, 0xc9,
-	0x68, 0x07, 0xca, 0x2a, 0x1a, 0xea, 0x39, 0x62, 0x3f, 0x5f, 0x6b, 0x4b, 0x57, 0xc9, 0x76, 0xd1,
-	0x1b, 0xa8, 0xeb, 0x77, 0xca, 0x05, 0x99, 0x92, 0x48, 0xda, 0xd4, 0xae, 0xe0, 0x65, 0xf0, 0xed,
-	0x2d, 0x6c, 0xe6, 0x7a, 0xab, 0xfa, 0x52, 0x4c, 0x04, 0xda, 0x80, 0xaa, 0x7f, 0x15, 0x5c, 0xf6,
-	0x3b, 0xdd, 0xc3, 0x5e, 0xbf, 0xdb, 0x7c, 0x84, 0x1a, 0x00, 0xfe, 0x55, 0xd0, 0x3f, 0xeb, 0x5e,
-	0xf5, 0x2e, 0xfc, 0x66, 0xc1, 0xd2, 0xed, 0xb3, 0xfe, 0x61, 0x0f, 0x9f, 0x36, 0x57, 0x50, 0x13,
-	0x6a, 0xfe, 0x55, 0x70, 0x78, 0x89, 0xdb, 0xfb, 0x7e, 0xef, 0xac, 0xdf, 0x2c, 0x5a, 0xe4, 0xb2,
-	0xef, 0x78, 0x56, 0x51, 0x1d, 0x2a, 0x8a, 0x67, 0xbf, 0x77, 0xd2, 0xed, 0x34, 0xd7, 0xde, 0xfa,
-	0x50, 0x35, 0x2f, 0x99, 0xec, 0xc8, 0x83, 0x93, 0xb3, 0xf6, 0x71, 0xd0, 0xc5, 0xf8, 0x0c, 0x37,
-	0x1f, 0x2d, 0x00, 0x1f, 0x5f, 0xf6, 0x8f, 0x9b, 0x05, 0xa5, 0xd1, 0x00, 0x07, 0x78, 0xbf, 0xdf,
-	0x3e, 0x6a, 0xae, 0xa0, 0x4d, 0xa8, 0x1b, 0xc4, 0x5d, 0xac, 0xf8, 0xf6, 0x04, 0x4a, 0xf6, 0x7b,
-	0x17, 0xd5, 0xa0, 0xdc, 0xef, 0x7e, 0x0a, 0x3e, 0xf6, 0xba, 0x9f, 0x9a, 0x8f, 0x50, 0x15, 0x4a,
-	0xe7, 0xb8, 0x7b, 0xbe, 0x8f, 0xbb, 0xe6, 0xfa, 0xe7, 0xb8, 0x1b, 0xb4, 0xcf, 0x4e, 0x4f, 0x7b,
-	0x7e, 0x73, 0x05, 0x01, 0xac, 0xdb, 0x75, 0x51, 0xad, 0x3b, 0xdd, 0x76, 0xaf, 0xd3, 0x6d, 0xae,
-	0x1e, 0xfc, 0xf1, 0x6f, 0x3f, 0x8d, 0xa9, 0x9c, 0x24, 0x83, 0xdd, 0x88, 0xcd, 0xf6, 0xcc, 0xbf,
-	0x0d, 0x6a, 0x96, 0xef, 0x2d, 0xfe, 0x78, 0x78, 0xf0, 0x3f, 0x8f, 0xc1, 0xba, 0x7e, 0x11, 0xfc,
-	0xf6, 0xff, 0x01, 0x00, 0x00, 0xff, 0xff, 0x36, 0xac, 0x5e, 0x88, 0x17, 0x11, 0x00, 0x00,
+	// 2114 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x58, 0x5f, 0x6f, 0x1b, 0xb9,
+	0x11, 0x3f, 0x59, 0xb6, 0x25, 0x8d, 0xfe, 0x58, 0xe6, 0x5d, 0x72, 0x1b, 0xe7, 0x72, 0x71, 0x94,
+	0x14, 0x71, 0x83, 0x9c, 0x8d, 0xa6, 0xe8, 0xf5, 0xae, 0xff, 0x00, 0x5b, 0x96, 0x2f, 0x6a, 0x62,
+	0xd9, 0xa1, 0xe5, 0x24, 0x28, 0x0a, 0x2c, 0x56, 0xbb, 0x94, 0x44, 0x58, 0x5a, 0xaa, 0x24, 0xd7,
+	0x59, 0xe7, 0x0b, 0x14, 0xe8, 0x5b, 0x5f, 0xfb, 0xdc, 0xa7, 0x7e, 0x90, 0x7e, 0x81, 0x7e, 0xa1,
+	0x62, 0x48, 0xee, 0x6a, 0x95, 0x5c, 0xf2, 0x24, 0xce, 0x6f, 0x66, 0x48, 0x0e, 0xe7, 0xef, 0x0a,
+	0x1e, 0x8f, 0x42, 0x75, 0x30, 0x63, 0xd1, 0x84, 0xc9, 0x83, 0x34, 0xff, 0x8d, 0x26, 0x8b, 0x51,
+	0x46, 0xee, 0x2f, 0xa4, 0xd0, 0x82, 0x6c, 0x5a, 0x74, 0xe7, 0x7e, 0x9a, 0x2c, 0x98, 0x0c, 0x85,
+	0x64, 0x07, 0x86, 0xa1, 0x0e, 0x42, 0x11, 0x6b, 0x19, 0x84, 0xda, 0x0a, 0xee, 0xdc, 0xfb, 0x48,
+	0xa0, 0xb8, 0xcf, 0xce, 0x83, 0x8f, 0xd8, 0x0b, 0x26, 0xe7, 0x5c, 0x29, 0x2e, 0x62, 0x2b, 0xd2,
+	0x39, 0x84, 0xfa, 0xab, 0xee, 0x05, 0x9f, 0xc4, 0xfd, 0x78, 0x2c, 0x14, 0x79, 0xb6, 0x42, 0x7a,
+	0xa5, 0xdd, 0xf2, 0x5e, 0xfd, 0x59, 0x7b, 0xdf, 0xde, 0x67, 0x3f, 0x63, 0xd0, 0xa2, 0x50, 0xe7,
+	0x35, 0x54, 0x33, 0x82, 0x78, 0x50, 0x39, 0x8c, 0x22, 0xc9, 0x14, 0xea, 0x96, 0xf6, 0x6a, 0x34,
+	0x23, 0xc9, 0x37, 0x50, 0x3b, 0x4f, 0x46, 0x33, 0x1e, 0xbe, 0x60, 0x37, 0xde, 0x9a, 0xe1, 0x2d,
+	0x01, 0x42, 0x60, 0x1d, 0xf7, 0xf0, 0xca, 0xbb, 0xa5, 0xbd, 0x06, 0x35, 0xeb, 0xce, 0x7f, 0x4b,
+	0x00, 0xaf, 0x12, 0x21, 0x93, 0x79, 0x97, 0x49, 0x4d, 0xbe, 0x05, 0x38, 0x97, 0x62, 0x21, 0x54,
+	0x30, 0xeb, 0x47, 0x66, 0xf7, 0x06, 0x2d, 0x20, 0x64, 0x17, 0xea, 0x19, 0x75, 0xaa, 0x26, 0xe6,
+	0x88, 0x06, 0x2d, 0x42, 0xe4, 0x21, 0xac, 0x0f, 0x6f, 0x16, 0xcc, 0x1c, 0xd2, 0x7a, 0xb6, 0x95,
+	0x59, 0xf5, 0xaa, 0x7b, 0xa1, 0x03, 0xcd, 0xa8, 0x61, 0xe2, 0x31, 0xaf, 0x39, 0x7b, 0x37, 0x48,
+	0xe6, 0x23, 0x26, 0xbd, 0xf5, 0xdd, 0xd2, 0x5e, 0x99, 0x16, 0x10, 0xf2, 0x2b, 0xa8, 0x2d, 0xdf,
+	0x67, 0x63, 0xb7, 0xb4, 0x57, 0x7f, 0xf6, 0x65, 0x61, 0xa7, 0x8c, 0x45, 0x97, 0x52, 0x9d, 0x57,
+	0xb0, 0xf9, 0xfc, 0x18, 0x97, 0xa4, 0x03, 0xcd, 0x69, 0xe4, 0x2f, 0x8c, 0xd9, 0xfe, 0x15, 0xbb,
+	0x71, 0x66, 0xd4, 0xa7, 0xd1, 0xf2, 0x29, 0x1e, 0x42, 0x53, 0x48, 0x3e, 0xe1, 0x71, 0x30, 0xf3,
+	0xa7, 0x81, 0x9a, 0x3a, 0x4b, 0x1a, 0x19, 0xf8, 0x3c, 0x50, 0xd3, 0xce, 0x19, 0xb4, 0xde, 0xa2,
+	0x6f, 0xf1, 0x90, 0x40, 0x27, 0x92, 0x91, 0xfb, 0x50, 0x5f, 0xee, 0x6b, 0x3d, 0xd7, 0xa0, 0xb0,
+	0xc8, 0xb6, 0x35, 0x0e, 0x50, 0x99, 0xb4, 0xdb, 0x73, 0x09, 0x74, 0xfe, 0x5d, 0x81, 0xfa, 0x50,
+	0x06, 0xb1, 0x0a, 0x42, 0xcd, 0x45, 0x8c, 0x0e, 0xd1, 0x29, 0xcf, 0xde, 0xd9, 0xac, 0xd1, 0xb9,
+	0xa3, 0x99, 0x08, 0xaf, 0x78, 0xe4, 0xf4, 0x33, 0x92, 0x3c, 0x85, 0x9a, 0x4e, 0x7d, 0x1e, 0x2f,
+	0x12, 0xad, 0xbc, 0xb2, 0x09, 0x9a, 0x2d, 0x1b, 0x60, 0x6a, 0x7f, 0x98, 0xf6, 0x11, 0xa7, 0x55,
+	0x6d, 0x17, 0x8a, 0x1c, 0x00, 0xe8, 0xd4, 0x17, 0x89, 0x36, 0xe2, 0xeb, 0x2e, 0xc6, 0x72, 0xf1,
+	0x33, 0xc3, 0xa0, 0x35, 0xed, 0x56, 0x0a, 0x2f, 0x13, 0x31, 0x15, 0x7a, 0x9b, 0xf6, 0x32, 0xb8,
+	0x26, 0x3b, 0x50, 0x0d, 0x05, 0x8f, 0x47, 0x81, 0x62, 0x5e, 0x65, 0xb7, 0xb4, 0x57, 0xa5, 0x39,
+	0x4d, 0xbe, 0x82, 0x8d, 0x58, 0xc4, 0x21, 0xf3, 0xaa, 0x26, 0xce, 0x2c, 0x81, 0x0f, 0xa0, 0xf9,
+	0x9c, 0x29, 0x1d, 0xcc, 0x17, 0x5e, 0xcd, 0x38, 0x76, 0x09, 0xa0, 0x71, 0xd7, 0x4c, 0x62, 0x66,
+	0x78, 0xb0, 0x5b, 0xda, 0xdb, 0xa0, 0x19, 0x89, 0x9c, 0x20, 0xd1, 0x62, 0xc2, 0x62, 0xaf, 0x6e,
+	0x0e, 0xca, 0x48, 0xf2, 0x3d, 0x34, 0x73, 0xb3, 0x7d, 0x96, 0x6a, 0xef, 0x6b, 0x63, 0x0b, 0xf9,
+	0xc0, 0xf4, 0x5e, 0xaa, 0x69, 0x3d, 0xb3, 0xbe, 0x97, 0x6a, 0xf2, 0x23, 0xb4, 0x96, 0x0f, 0x60,
+	0x14, 0x3d, 0xa3, 0xf8, 0xe5, 0x87, 0x8f, 0x80, 0x9a, 0x8d, 0xfc, 0x1d, 0x50, 0xf5, 0x08, 0xb6,
+	0xb3, 0x1a, 0xe0, 0x4b, 0xf6, 0xb7, 0x84, 0x29, 0xad, 0xbc, 0x3b, 0x46, 0xfb, 0x56, 0xa6, 0xdd,
+	0x8f, 0xaf, 0xc5, 0x15, 0xa3, 0x96, 0x4b, 0xdb, 0x99, 0xbc, 0x03, 0x4c, 0x24, 0xf0, 0x98, 0x6b,
+	0x1e, 0x68, 0x21, 0xbd, 0x1d, 0x9b, 0x8a, 0x39, 0x40, 0x1e, 0x40, 0x23, 0x48, 0xf4, 0xd4, 0xec,
+	0xce, 0x25, 0xf3, 0xee, 0xee, 0x96, 0xf7, 0x6a, 0xb4, 0x8e, 0x18, 0xb5, 0x10, 0xf9, 0x13, 0x6c,
+	0xe5, 0xf2, 0x3e, 0xc6, 0x90, 0xf2, 0xbe, 0x59, 0xbd, 0x42, 0x1e, 0x97, 0xa6, 0x5c, 0xb4, 0x72,
+	0x69, 0xc4, 0x15, 0xe9, 0x02, 0x29, 0x1e, 0xe1, 0xb6, 0xb8, 0xf7, 0xb9, 0x2d, 0xda, 0x85, 0xf3,
+	0xed, 0x26, 0xdf, 0x01, 0x91, 0x2c, 0x64, 0xfc, 0x9a, 0x45, 0xfe, 0xd2, 0xaf, 0xdf, 0x1a, 0xbf,
+	0x6e, 0x67, 0x9c, 0x61, 0xee, 0xdf, 0xdf, 0x00, 0x98, 0x6a, 0x68, 0x0e, 0xf3, 0xee, 0x9b, 0xc4,
+	0xbd, 0x9d, 0x25, 0xee, 0x6a, 0x2e, 0xd1, 0x5a, 0x9a, 0xd1, 0xe4, 0x7b, 0x68, 0xcc, 0x45, 0xc4,
+	0xc7, 0x37, 0xbe, 0x89, 0x75, 0x6f, 0x77, 0x35, 0xe3, 0x4f, 0x0d, 0xef, 0x08, 0x59, 0xb4, 0x3e,
+	0x5f, 0x12, 0xe4, 0x31, 0x54, 0x9e, 0x1f, 0xfb, 0x3c, 0x1e, 0x0b, 0xef, 0x81, 0x51, 0x69, 0x65,
+	0x2a, 0xb6, 0x14, 0xd0, 0xac, 0x24, 0x3c, 0x05, 0x72, 0x1d, 0xcc, 0x78, 0xe4, 0x27, 0xb1, 0xe6,
+	0x33, 0x7f, 0xca, 0xf8, 0x64, 0xaa, 0xbd, 0x8e, 0x31, 0xa3, 0x6d, 0x38, 0x97, 0xc8, 0x78, 0x6e,
+	0xf0, 0x8e, 0x02, 0x78, 0x69, 0x2a, 0xfc, 0x29, 0xd3, 0x01, 0xba, 0x4a, 0x0a, 0xa1, 0xfd, 0x2c,
+	0x2b, 0x5d, 0x35, 0x41, 0xec, 0xc8, 0x42, 0x58, 0x16, 0x34, 0x5f, 0xf8, 0xab, 0x79, 0x0b, 0x9a,
+	0x2f, 0x32, 0x81, 0x07, 0xd0, 0xd0, 0x32, 0x89, 0xaf, 0xb2, 0x93, 0xcb, 0xe6, 0xe4, 0xba, 0xc1,
+	0xdc, 0xa1, 0xff, 0xda, 0x80, 0xea, 0xa5, 0x4e, 0x85, 0x39, 0xf3, 0x17, 0xd0, 0x9a, 0x05, 0x9a,
+	0xa9, 0x0f, 0x4f, 0x6d, 0x5a, 0x34, 0xdb, 0xb6, 0x03, 0x4d, 0x5c, 0x61, 0x31, 0xf2, 0x67, 0x5c,
+	0x69, 0x6f, 0xcd, 0x86, 0x11, 0x82, 0x2f, 0xd8, 0xcd, 0x4b, 0xae, 0x34, 0xb9, 0x07, 0x90, 0xe8,
+	0x54, 0xf8, 0x5a, 0xe8, 0x60, 0x66, 0x0e, 0xae, 0xd1, 0x1a, 0x22, 0x43, 0x04, 0x30, 0xc3, 0x83,
+	0xeb, 0xc9, 0x31, 0x9b, 0x05, 0x37, 0xae, 0x0e, 0xe7, 0x34, 0x79, 0x0a, 0xdb, 0x49, 0x1c, 0x8a,
+	0x78, 0xcc, 0xe5, 0x7c, 0x98, 0x1e, 0xce, 0x45, 0x12, 0x6b, 0x53, 0x8d, 0xcb, 0xf4, 0x63, 0x06,
+	0x79, 0x04, 0xad, 0x79, 0x90, 0xda, 0x0b, 0xfb, 0x8a, 0xbf, 0x67, 0xa6, 0x92, 0x94, 0x69, 0x63,
+	0x1e, 0xa4, 0xe6, 0xc2, 0x17, 0xfc, 0x3d, 0x23, 0xc7, 0x18, 0x50, 0x8a, 0x49, 0x0c, 0xa8, 0x2c,
+	0x67, 0x94, 0x57, 0xf9, 0x5c, 0x6e, 0x6d, 0x67, 0x0a, 0xdd, 0x4c, 0x1e, 0x77, 0x19, 0x0b, 0x39,
+	0xe2, 0x51, 0xc4, 0xe2, 0x7c, 0x1b, 0x53, 0x88, 0x3e, 0xbd, 0x4b, 0xae, 0x90, 0x6d, 0x43, 0xfe,
+	0x08, 0x77, 0x63, 0xf6, 0xce, 0x0f, 0xc2, 0x10, 0x0d, 0xf0, 0x25, 0x53, 0x22, 0x91, 0x21, 0xf3,
+	0x03, 0x6b, 0xa9, 0xad, 0x5e, 0x5e, 0xcc, 0xde, 0x1d, 0x5a, 0x09, 0xea, 0x04, 0x9c, 0xc1, 0x3f,
+	0xc0, 0xd7, 0x5c, 0x4a, 0x66, 0x2a, 0xd8, 0x68, 0xc6, 0x8c, 0x8d, 0xd6, 0x99, 0xa6, 0xb8, 0x95,
+	0xe9, 0xa7, 0xd8, 0x1f, 0x6a, 0x5e, 0xcc, 0x78, 0xc4, 0xde, 0xf0, 0x38, 0x12, 0xef, 0xbc, 0xfa,
+	0xc7, 0x9a, 0x05, 0x36, 0x79, 0x0a, 0xd5, 0x49, 0xa0, 0xce, 0x25, 0x0f, 0x99, 0xd7, 0xd8, 0x2d,
+	0x15, 0x6b, 0xfa, 0x4f, 0x0e, 0xa7, 0xb9, 0x04, 0xf9, 0x09, 0xbe, 0x9a, 0x48, 0x91, 0x2c, 0xfc,
+	0x70, 0x1a, 0xf0, 0xc2, 0x43, 0x35, 0x3f, 0xf7, 0x50, 0xc4, 0xa8, 0x74, 0x51, 0x23, 0x7b, 0xa9,
+	0xce, 0xdf, 0x37, 0xa1, 0xd9, 0x8f, 0x35, 0x93, 0x71, 0x30, 0xb3, 0xa9, 0x57, 0xa8, 0xe4, 0xa5,
+	0xd5, 0x4a, 0x9e, 0xf7, 0x85, 0x35, 0x83, 0x5b, 0xa2, 0xd8, 0xd6, 0xca, 0xab, 0x6d, 0xed, 0x0e,
+	0x54, 0x17, 0x92, 0xd9, 0x2e, 0xbc, 0x6e, 0x59, 0x0b, 0xc9, 0xb0, 0x01, 0x63, 0x70, 0x2e, 0xcc,
+	0x68, 0xc1, 0xa4, 0x89, 0xbb, 0x06, 0xcd, 0x69, 0x6c, 0x57, 0xa6, 0xc8, 0xb8, 0x76, 0x85, 0x6b,
+	0x72, 0x1b, 0x36, 0x17, 0xc9, 0x08, 0x5b, 0x7e, 0xc5, 0xa0, 0x8e, 0xc2, 0xfc, 0x9c, 0x33, 0x79,
+	0x35, 0x63, 0x3e, 0x66, 0xad, 0x89, 0x93, 0x06, 0x05, 0x0b, 0x51, 0x21, 0x34, 0x2a, 0xba, 0xcc,
+	0xb4, 0x4e, 0x77, 0xd4, 0x6a, 0x37, 0x83, 0x0f, 0xbb, 0xd9, 0x6f, 0x31, 0xab, 0xf3, 0x6e, 0xae,
+	0xbc, 0xba, 0xeb, 0x2f, 0xae, 0x06, 0x15, 0x3a, 0x3d, 0x5d, 0x11, 0x44, 0x93, 0x75, 0xea, 0x9b,
+	0x98, 0x32, 0x5e, 0xdc, 0xa0, 0x15, 0x9d, 0x76, 0x91, 0x2c, 0x5c, 0x55, 0x4b, 0xc6, 0xbc, 0xa6,
+	0x9d, 0x30, 0x2c, 0x34, 0x94, 0xcc, 0x3c, 0x64, 0x98, 0xc8, 0x21, 0x93, 0x73, 0xaf, 0x6d, 0x2e,
+	0x94, 0x91, 0x38, 0x9b, 0x85, 0x89, 0x34, 0xee, 0x19, 0x24, 0x73, 0x6f, 0xdb, 0xd6, 0x98, 0x02,
+	0x44, 0xba, 0x00, 0xe3, 0x80, 0xcf, 0xb0, 0x96, 0xa7, 0xca, 0x23, 0xe6, 0xba, 0x8f, 0xb2, 0xeb,
+	0xae, 0xf8, 0x77, 0xff, 0xc4, 0xc8, 0x0d, 0x53, 0xd5, 0x8b, 0xb5, 0xbc, 0xa1, 0xb5, 0x71, 0x46,
+	0xe3, 0xec, 0xa6, 0x03, 0x39, 0x61, 0xfa, 0x88, 0x6b, 0xe5, 0x7d, 0x69, 0xae, 0x5f, 0x40, 0xc8,
+	0x53, 0xa8, 0xfc, 0x39, 0x51, 0x9a, 0x8f, 0x6f, 0xbc, 0xaf, 0x4c, 0x9c, 0x91, 0x7c, 0x72, 0xcb,
+	0xe7, 0x4c, 0x9a, 0x89, 0x60, 0x79, 0x52, 0x3a, 0xd0, 0xce, 0x33, 0xb7, 0xdc, 0xc4, 0x84, 0x88,
+	0x71, 0xcc, 0x1d, 0xa8, 0xe2, 0xb0, 0xe1, 0x8f, 0x19, 0xf3, 0x6e, 0xbb, 0xb8, 0x09, 0x14, 0x3b,
+	0x61, 0x0c, 0x59, 0x3c, 0xf6, 0x4d, 0x09, 0xf5, 0x5a, 0x76, 0x64, 0xe0, 0xf1, 0x10, 0x49, 0x72,
+	0x17, 0x6a, 0x31, 0x4b, 0xb5, 0x8d, 0xa9, 0x2d, 0x1b, 0x38, 0x08, 0x60, 0x50, 0xed, 0xfc, 0x01,
+	0x5a, 0xab, 0xc6, 0x91, 0x36, 0x94, 0xb3, 0x31, 0xb1, 0x46, 0x71, 0x89, 0x31, 0x7c, 0x1d, 0xcc,
+	0x12, 0xe6, 0x66, 0x68, 0x4b, 0xfc, 0x6e, 0xed, 0x87, 0x52, 0xe7, 0x1f, 0x25, 0x58, 0xc7, 0x32,
+	0x8d, 0x21, 0xe3, 0xea, 0x84, 0x2d, 0xcd, 0x8e, 0x42, 0x5c, 0x0b, 0x9c, 0xc7, 0x5d, 0x1b, 0x70,
+	0x14, 0xc6, 0xb2, 0x16, 0xe7, 0x36, 0x3a, 0x6d, 0x06, 0xe4, 0x34, 0xfa, 0x54, 0xb2, 0xf1, 0x10,
+	0x47, 0x41, 0x97, 0x01, 0x8e, 0xc4, 0x00, 0x94, 0x6c, 0x7c, 0x36, 0x1e, 0x2b, 0x66, 0x4b, 0xef,
+	0x06, 0x5d, 0x02, 0x9d, 0xff, 0x95, 0xa0, 0x5e, 0x68, 0x8e, 0xd8, 0x36, 0xd8, 0x78, 0xcc, 0x42,
+	0xcd, 0xaf, 0x99, 0x9f, 0x4f, 0x96, 0x35, 0xda, 0xcc, 0x51, 0xb3, 0xe9, 0x6d, 0xd8, 0x9c, 0x07,
+	0xf2, 0x8a, 0xd9, 0x4e, 0x55, 0xa5, 0x8e, 0x22, 0xbf, 0x84, 0xf6, 0x52, 0x7d, 0xa5, 0x53, 0x6d,
+	0xe5, 0xb8, 0xab, 0x60, 0xf7, 0x00, 0x0a, 0x03, 0xf6, 0xba, 0xed, 0x2a, 0x8b, 0xe2, 0x97, 0x86,
+	0x49, 0xce, 0x0d, 0xc3, 0x30, 0x6b, 0xf2, 0x18, 0xb6, 0x24, 0x8b, 0x82, 0x50, 0xb3, 0xc8, 0x1f,
+	0x73, 0x36, 0x8b, 0x94, 0xb7, 0x69, 0xda, 0x55, 0x2b, 0x83, 0x4f, 0x0c, 0xda, 0x19, 0x43, 0x73,
+	0x98, 0x1e, 0x07, 0x3a, 0x70, 0x65, 0xd7, 0xcc, 0x86, 0xab, 0xdf, 0x3b, 0x8e, 0x2c, 0x38, 0xc1,
+	0x3a, 0xca, 0x51, 0x38, 0xde, 0x8f, 0xa5, 0x78, 0xcf, 0xe2, 0x55, 0x33, 0x1a, 0x16, 0x74, 0x1d,
+	0x57, 0x00, 0xa0, 0x27, 0x29, 0x0b, 0x85, 0x34, 0x2f, 0x8d, 0x5d, 0xb1, 0x9b, 0xbb, 0xb4, 0x46,
+	0x97, 0x00, 0x06, 0x3d, 0x12, 0x87, 0xc5, 0xc3, 0x0a, 0x08, 0x7e, 0xf5, 0x70, 0xcd, 0xe6, 0xf9,
+	0x58, 0xee, 0x22, 0x1e, 0xf7, 0x7f, 0xc1, 0x6e, 0xa8, 0x61, 0x76, 0x2e, 0xa0, 0xe2, 0x80, 0xa2,
+	0xc7, 0x9d, 0x49, 0x8e, 0x44, 0x93, 0x84, 0x75, 0xb7, 0x33, 0xc9, 0x52, 0x05, 0x53, 0xcb, 0x45,
+	0x53, 0x3b, 0xff, 0x29, 0x41, 0x7b, 0x69, 0xc6, 0x31, 0xd3, 0x01, 0x9f, 0x91, 0x7d, 0xa8, 0x8a,
+	0x05, 0x8b, 0x11, 0xf7, 0x4a, 0xab, 0x49, 0xb8, 0x94, 0xa5, 0xb9, 0x0c, 0x79, 0x06, 0x80, 0x01,
+	0xc4, 0x22, 0xa3, 0xb1, 0xf6, 0x49, 0x8d, 0x82, 0x14, 0xea, 0xd8, 0xe7, 0x34, 0x3a, 0xe5, 0x4f,
+	0xeb, 0x2c, 0xa5, 0x3a, 0x7d, 0xd8, 0x3e, 0x0a, 0x66, 0x41, 0x1c, 0x32, 0x7b, 0xd1, 0xec, 0x73,
+	0x76, 0x64, 0xc1, 0xec, 0x2d, 0x1c, 0x89, 0x39, 0xc3, 0xd5, 0x89, 0x51, 0x77, 0xa1, 0x9a, 0xd3,
+	0x9d, 0xbf, 0x5a, 0xef, 0xd9, 0xa9, 0x9d, 0xec, 0x41, 0x15, 0xbd, 0x81, 0x13, 0x8f, 0xfb, 0x9e,
+	0x6e, 0xac, 0x5c, 0x25, 0xe7, 0x92, 0x47, 0xd0, 0x34, 0xa3, 0xd0, 0x05, 0x9b, 0x31, 0x0c, 0x3a,
+	0xf7, 0xcc, 0xab, 0x60, 0xe7, 0x9f, 0x25, 0x0c, 0xc2, 0x53, 0x53, 0x76, 0xcf, 0xa5, 0x10, 0x63,
+	0xf2, 0x10, 0xd6, 0x74, 0xea, 0x1e, 0xf3, 0x67, 0x4b, 0xfc, 0x9a, 0x4e, 0xc9, 0x77, 0xd8, 0x47,
+	0x82, 0x88, 0x49, 0xf7, 0x86, 0xb7, 0x7e, 0xb6, 0xb8, 0x52, 0x27, 0x84, 0x65, 0x86, 0xc7, 0x11,
+	0x4b, 0xcd, 0xeb, 0x6d, 0x50, 0x4b, 0xa0, 0xa7, 0x47, 0x32, 0x88, 0xc3, 0xa9, 0xf9, 0x6a, 0x6b,
+	0x50, 0x47, 0x3d, 0x79, 0x07, 0xdb, 0x85, 0xf3, 0xf0, 0x73, 0x3a, 0x51, 0x64, 0x0b, 0xea, 0xc3,
+	0xb7, 0xfe, 0xe5, 0xe0, 0xb8, 0x77, 0xd2, 0x1f, 0xf4, 0xda, 0x5f, 0x90, 0x16, 0xc0, 0xf0, 0xad,
+	0x3f, 0x38, 0xeb, 0xbd, 0xed, 0x5f, 0x0c, 0xdb, 0x25, 0x47, 0x77, 0xcf, 0x06, 0x27, 0x7d, 0x7a,
+	0xda, 0x5e, 0x23, 0x6d, 0x68, 0x0c, 0xdf, 0xfa, 0x27, 0x97, 0xb4, 0x7b, 0x38, 0xec, 0x9f, 0x0d,
+	0xda, 0x65, 0x87, 0x5c, 0x0e, 0x32, 0x99, 0x75, 0xd2, 0x84, 0x1a, 0xca, 0x1c, 0xf6, 0x5f, 0xf6,
+	0x8e, 0xdb, 0x1b, 0x4f, 0x86, 0x50, 0xb7, 0x03, 0x5c, 0x7e, 0xe4, 0xd1, 0xcb, 0xb3, 0xee, 0x0b,
+	0xbf, 0x47, 0xe9, 0x19, 0x6d, 0x7f, 0xb1, 0x04, 0x86, 0xf4, 0x72, 0xf0, 0xa2, 0x5d, 0xc2, 0x1d,
+	0x2d, 0x70, 0x44, 0x0f, 0x07, 0xdd, 0xe7, 0xed, 0x35, 0xb2, 0x0d, 0x4d, 0x8b, 0x64, 0x17, 0x2b,
+	0x3f, 0x79, 0x09, 0x15, 0xf7, 0xa7, 0x00, 0x69, 0x40, 0x75, 0xd0, 0x7b, 0xe3, 0xbf, 0xee, 0xf7,
+	0xde, 0xb4, 0xbf, 0x20, 0x75, 0xa8, 0x9c, 0xd3, 0xde, 0xf9, 0x21, 0xed, 0xd9, 0xeb, 0x9f, 0xd3,
+	0x9e, 0xdf, 0x3d, 0x3b, 0x3d, 0xed, 0x0f, 0xdb, 0x6b, 0x04, 0x60, 0xd3, 0xad, 0xcb, 0xb8, 0x3e,
+	0xee, 0x75, 0xfb, 0xc7, 0xbd, 0xf6, 0xfa, 0xd1, 0xef, 0xff, 0xf2, 0xe3, 0x84, 0xeb, 0x69, 0x32,
+	0xda, 0x0f, 0xc5, 0xfc, 0xc0, 0xfe, 0x25, 0x83, 0x23, 0xcc, 0xc1, 0xf2, 0xdf, 0x99, 0x4f, 0xfe,
+	0x31, 0x34, 0xda, 0x34, 0x83, 0xd0, 0xaf, 0xff, 0x3f, 0x00, 0x22, 0x9f, 0xa0, 0x24, 0x3c, 0x12,
+	0x00, 0x00,
 }
@@ -42,6 +42,7 @@ func (p *P2PServerV1) SendMessage(ctx xctx.XContext, msg *pb.XuperMessage, optFu
 		return errors.New("p2p SendMessage: filter returned error data")
 	}
 
+	peerIDs = p.routeByChain(msg, peerIDs)
 	if len(peerIDs) <= 0 {
 		p.log.Warn("SendMessage peerID empty", "log_id", msg.GetHeader().GetLogid(),
 			"msgType", msg.GetHeader().GetType(), "checksum", msg.GetHeader().GetDataCheckSum())
@@ -103,6 +104,7 @@ func (p *P2PServerV1) SendMessageWithResponse(ctx xctx.XContext, msg *pb.XuperMe
 		return nil, errors.New("p2p: SendMessageWithRes: filter returned error data")
 	}
 
+	peerIDs = p.routeByChain(msg, peerIDs)
 	if len(peerIDs) <= 0 {
 		p.log.Warn("SendMessageWithResponse peerID empty", "log_id", msg.GetHeader().GetLogid(),
 			"msgType", msg.GetHeader().GetType(), "checksum", msg.GetHeader().GetDataCheckSum())
@@ -1,38 +1,63 @@
 package p2pv1
 
 import (
+	"net"
+	"sync"
+
 	"github.com/patrickmn/go-cache"
 	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
 	"github.com/xuperchain/xupercore/kernel/network/p2p"
 	pb "github.com/xuperchain/xupercore/protos"
 )
 
+// GetPeerInfo 逐个地址交换PeerInfo，签名校验通过的PeerInfo的账户绑定到拨号的地址
 func (p *P2PServerV1) GetPeerInfo(addresses []string) ([]*pb.PeerInfo, error) {
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	remotePeers := make([]*pb.PeerInfo, 0, len(addresses))
+	for _, address := range addresses {
+		wg.Add(1)
+		go func(address string) {
+			defer wg.Done()
+			peerInfo, err := p.getPeerInfo(address)
+			if err != nil {
+				return
+			}
+			p.setPeerInfo(peerInfo, address)
+
+			mutex.Lock()
+			remotePeers = append(remotePeers, peerInfo)
+			mutex.Unlock()
+		}(address)
+	}
+	wg.Wait()
+
+	if len(remotePeers) <= 0 {
+		return nil, ErrNoResponse
+	}
+	return remotePeers, nil
+}
+
+// getPeerInfo 向一个地址请求PeerInfo，逐个发送才能确定响应来自哪个地址
+func (p *P2PServerV1) getPeerInfo(address string) (*pb.PeerInfo, error) {
 	peerInfo := p.PeerInfo()
 	msg := p2p.NewMessage(pb.XuperMessage_GET_PEER_INFO, &peerInfo)
-	response, err := p.SendMessageWithResponse(p.ctx, msg, p2p.WithAddresses(addresses))
+	response, err := p.SendMessageWithResponse(p.ctx, msg, p2p.WithAddresses([]string{address}))
 	if err != nil {
-		p.log.Error("get peer error", "log_id", msg.GetHeader().GetLogid(), "error", err)
+		p.log.Warn("get peer error", "log_id", msg.GetHeader().GetLogid(), "address", address, "error", err)
 		return nil, err
 	}
 
-	remotePeers := make([]*pb.PeerInfo, 0, len(response))
-	for _, msg := range response {
-		var peerInfo pb.PeerInfo
-		err := p2p.Unmarshal(msg, &peerInfo)
-		if err != nil {
-			p.log.Warn("unmarshal NewNode response error", "log_id", msg.GetHeader().GetLogid(), "error", err)
-			continue
-		}
-
-		remotePeers = append(remotePeers, &peerInfo)
+	var remote pb.PeerInfo
+	if err := p2p.Unmarshal(response[0], &remote); err != nil {
+		p.log.Warn("unmarshal NewNode response error", "log_id", msg.GetHeader().GetLogid(), "error", err)
+		return nil, err
 	}
-
-	return remotePeers, nil
+	return &remote, nil
 }
 
 func (p *P2PServerV1) registerConnectHandler() error {
-	err := p.Register(p2p.NewSubscriber(p.ctx, pb.XuperMessage_GET_PEER_INFO, p2p.HandleFunc(p.handleGetPeerInfo)))
+	err := p.Register(p2p.NewSubscriber(p.ctx, pb.XuperMessage_GET_PEER_INFO, p2p.StreamHandleFunc(p.handleGetPeerInfo)))
 	if err != nil {
 		p.log.Error("registerSubscribe error", "error", err)
 		return err
@@ -41,7 +66,8 @@ func (p *P2PServerV1) registerConnectHandler() error {
 	return nil
 }
 
-func (p *P2PServerV1) handleGetPeerInfo(ctx xctx.XContext, request *pb.XuperMessage) (*pb.XuperMessage, error) {
+func (p *P2PServerV1) handleGetPeerInfo(ctx xctx.XContext, request *pb.XuperMessage,
+	stream p2p.Stream) (*pb.XuperMessage, error) {
 	output := p.PeerInfo()
 	opts := []p2p.MessageOption{
 		p2p.WithBCName(request.GetHeader().GetBcname()),
@@ -68,5 +94,13 @@ func (p *P2PServerV1) handleGetPeerInfo(ctx xctx.XContext, request *pb.XuperMess
 	}
 	p.accounts.Set(peerInfo.GetAccount(), peerInfo.GetAddress(), cache.NoExpiration)
 
+	// 入站连接只能确认对端IP，声明的地址必须在该IP上
+	host, _, err := net.SplitHostPort(peerInfo.GetAddress())
+	if err != nil || host != remoteHost(stream) {
+		p.log.Debug("drop peer info not from the peer", "address", peerInfo.GetAddress(), "remote", remoteHost(stream))
+		return resp, nil
+	}
+	p.setPeerInfo(&peerInfo, peerInfo.GetAddress())
+
 	return resp, nil
 }
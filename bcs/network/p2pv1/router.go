@@ -0,0 +1,182 @@
+package p2pv1
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc/peer"
+
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+// ServeChain 设置本节点服务的链及群组成员，并向已连接节点公告
+func (p *P2PServerV1) ServeChain(bcname string, members []string) {
+	p.router.ServeChain(bcname, members)
+	go p.announceChains()
+}
+
+// LeaveChain 取消服务的链，并向已连接节点公告
+func (p *P2PServerV1) LeaveChain(bcname string) {
+	p.router.LeaveChain(bcname)
+	go p.announceChains()
+}
+
+// announceChains 通过交换PeerInfo向已连接节点公告本节点服务的链
+func (p *P2PServerV1) announceChains() {
+	addresses := make([]string, 0)
+	for _, address := range p.pool.GetAll() {
+		addresses = append(addresses, address)
+	}
+	if len(addresses) <= 0 {
+		return
+	}
+
+	remotePeerInfos, err := p.GetPeerInfo(addresses)
+	if err != nil {
+		p.log.Warn("announce chains error", "error", err)
+		return
+	}
+
+	for _, peerInfo := range remotePeerInfos {
+		p.accounts.Set(peerInfo.GetAccount(), peerInfo.GetAddress(), 0)
+	}
+}
+
+// setPeerInfo 仅记录签名校验通过的PeerInfo的账户与服务的链，账户绑定到传输层确认的对端地址，
+// 而不是PeerInfo中自行声明的地址
+func (p *P2PServerV1) setPeerInfo(peerInfo *pb.PeerInfo, address string) {
+	if err := p2p.VerifyPeerInfo(peerInfo); err != nil {
+		p.log.Debug("drop unverified peer info", "address", address,
+			"account", peerInfo.GetAccount(), "error", err)
+		return
+	}
+
+	// 签名时间早于已记录公告的PeerInfo是重放的旧公告
+	if !p.router.SetPeerChains(peerInfo.GetAccount(), peerInfo.GetChains(), peerInfo.GetTimestamp()) {
+		p.log.Debug("drop stale peer info", "address", address, "account", peerInfo.GetAccount())
+		return
+	}
+	p.peerIndex.set(address, peerInfo.GetAccount())
+}
+
+// routeByChain 过滤掉不能接收该链消息的节点
+func (p *P2PServerV1) routeByChain(msg *pb.XuperMessage, peerIDs []string) []string {
+	bcname := msg.GetHeader().GetBcname()
+	if !p.router.IsRestricted(bcname) {
+		return peerIDs
+	}
+
+	routable := make([]string, 0, len(peerIDs))
+	for _, peerID := range peerIDs {
+		account := p.getAccountByPeerID(peerID)
+		if !p.router.Routable(bcname, account) {
+			p.log.Debug("p2p: peer not routable for chain", "log_id", msg.GetHeader().GetLogid(),
+				"bcname", bcname, "peerID", peerID, "account", account)
+			continue
+		}
+		routable = append(routable, peerID)
+	}
+
+	return routable
+}
+
+// resolveAccount 根据传输层的远端地址解析发送方账户，不信任消息头中的from；
+// 开启TLS时远端地址所在的连接已经过证书认证
+func (p *P2PServerV1) resolveAccount(_ *pb.XuperMessage, stream p2p.Stream) string {
+	host := remoteHost(stream)
+	if host == "" {
+		return ""
+	}
+	return p.peerIndex.getByHost(host)
+}
+
+// remoteHost 入站连接的对端IP，入站连接的端口为临时端口，只能按IP匹配
+func remoteHost(stream p2p.Stream) string {
+	s, ok := stream.(interface{ Context() context.Context })
+	if !ok {
+		return ""
+	}
+
+	remote, ok := peer.FromContext(s.Context())
+	if !ok || remote.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(remote.Addr.String())
+	if err != nil {
+		return ""
+	}
+	return host
+}
+
+// getAccountByPeerID p2pv1的peerID即为节点地址 "IP:Port"
+func (p *P2PServerV1) getAccountByPeerID(peerID string) string {
+	return p.peerIndex.get(peerID)
+}
+
+// peerAccountIndex 签名校验通过的节点地址到账户的反向索引
+type peerAccountIndex struct {
+	mutex sync.RWMutex
+	// addresses "IP:Port" => account
+	addresses map[string]string
+	// hosts IP => account => 该IP上绑定该账户的地址数
+	hosts map[string]map[string]int
+}
+
+func newPeerAccountIndex() *peerAccountIndex {
+	return &peerAccountIndex{
+		addresses: make(map[string]string),
+		hosts:     make(map[string]map[string]int),
+	}
+}
+
+func (idx *peerAccountIndex) set(address, account string) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return
+	}
+
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+
+	old, ok := idx.addresses[address]
+	if ok && old == account {
+		return
+	}
+	if ok {
+		idx.hosts[host][old]--
+		if idx.hosts[host][old] <= 0 {
+			delete(idx.hosts[host], old)
+		}
+	}
+
+	idx.addresses[address] = account
+	if idx.hosts[host] == nil {
+		idx.hosts[host] = make(map[string]int)
+	}
+	idx.hosts[host][account]++
+}
+
+func (idx *peerAccountIndex) get(address string) string {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+
+	return idx.addresses[address]
+}
+
+// getByHost 同一IP上绑定了多个账户时无法区分，返回空
+func (idx *peerAccountIndex) getByHost(host string) string {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+
+	accounts := idx.hosts[host]
+	if len(accounts) != 1 {
+		return ""
+	}
+	for account := range accounts {
+		return account
+	}
+	return ""
+}
@@ -0,0 +1,59 @@
+package p2pv1
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc/peer"
+
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+type mockStream struct {
+	ctx context.Context
+}
+
+func (s *mockStream) Send(*pb.XuperMessage) error {
+	return nil
+}
+
+func (s *mockStream) Context() context.Context {
+	return s.ctx
+}
+
+func streamFrom(addr string) *mockStream {
+	tcpAddr, _ := net.ResolveTCPAddr("tcp", addr)
+	return &mockStream{ctx: peer.NewContext(context.Background(), &peer.Peer{Addr: tcpAddr})}
+}
+
+func TestResolveAccount(t *testing.T) {
+	p := &P2PServerV1{peerIndex: newPeerAccountIndex()}
+	p.peerIndex.set("127.0.0.1:47101", "alice")
+	p.peerIndex.set("127.0.0.2:47101", "bob")
+
+	if account := p.getAccountByPeerID("127.0.0.1:47101"); account != "alice" {
+		t.Errorf("get account by peer id error: %s", account)
+	}
+
+	// 消息头中的from不参与解析
+	msg := &pb.XuperMessage{Header: &pb.XuperMessage_MessageHeader{From: "127.0.0.1:47101"}}
+	if account := p.resolveAccount(msg, streamFrom("127.0.0.2:53412")); account != "bob" {
+		t.Errorf("resolve account should use transport address: %s", account)
+	}
+	if account := p.resolveAccount(msg, nil); account != "" {
+		t.Errorf("resolve account without transport should fail: %s", account)
+	}
+
+	// 同一IP绑定多个账户时无法区分
+	p.peerIndex.set("127.0.0.2:47102", "carol")
+	if account := p.resolveAccount(msg, streamFrom("127.0.0.2:53412")); account != "" {
+		t.Errorf("ambiguous host should not resolve: %s", account)
+	}
+
+	// 地址重新绑定后旧账户不再可解析
+	p.peerIndex.set("127.0.0.2:47102", "bob")
+	if account := p.resolveAccount(msg, streamFrom("127.0.0.2:53412")); account != "bob" {
+		t.Errorf("rebind address error: %s", account)
+	}
+}
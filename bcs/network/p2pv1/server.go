@@ -58,6 +58,13 @@ type P2PServerV1 struct {
 	account string
 	// accounts store remote peer account: key:account => v:peer.ID
 	accounts *cache.Cache
+
+	// router chain level peer membership
+	router *p2p.ChainRouter
+	// peerIndex verified peer address => account
+	peerIndex *peerAccountIndex
+	// signer sign local peer info with account key
+	signer *p2p.PeerSigner
 }
 
 var _ p2p.Server = &P2PServerV1{}
@@ -79,7 +86,9 @@ func (p *P2PServerV1) Init(ctx *nctx.NetCtx) error {
 	p.log = ctx.GetLog()
 	p.config = ctx.P2PConf
 	p.pool = pool
-	p.dispatcher = p2p.NewDispatcher(ctx)
+	p.router = p2p.NewChainRouter()
+	p.peerIndex = newPeerAccountIndex()
+	p.dispatcher = p2p.NewDispatcher(ctx, p2p.WithChainRouter(p.router, p.resolveAccount))
 
 	// address
 	p.address, err = multiaddr.NewMultiaddr(ctx.P2PConf.Address)
@@ -101,6 +110,10 @@ func (p *P2PServerV1) Init(ctx *nctx.NetCtx) error {
 		p.log.Error("load account error", "path", keyPath)
 		return ErrLoadAccount
 	}
+	// 未加载到账户密钥时PeerInfo不签名，对端无法将本节点识别为受限链的成员
+	if p.signer, err = p2p.NewPeerSigner(keyPath); err != nil {
+		p.log.Warn("load peer signer error", "path", keyPath, "error", err)
+	}
 	p.accounts = cache.New(cache.NoExpiration, cache.NoExpiration)
 
 	p.bootNodes = make([]string, 0)
@@ -211,6 +224,12 @@ func (p *P2PServerV1) PeerInfo() pb.PeerInfo {
 		Id:      ip,
 		Address: ip,
 		Account: p.account,
+		Chains:  p.router.Chains(),
+	}
+	if p.signer != nil {
+		if err := p.signer.Sign(&peerInfo); err != nil {
+			p.log.Warn("sign peer info error", "error", err)
+		}
 	}
 
 	accounts := p.accounts.Items()
@@ -62,6 +62,7 @@ func (p *P2PServerV2) SendMessage(ctx xctx.XContext, msg *pb.XuperMessage,
 		peerIDs = peers
 	}
 
+	peerIDs = p.routeByChain(msg, peerIDs)
 	if len(peerIDs) <= 0 {
 		p.log.Warn("SendMessage peerID empty", "log_id", msg.GetHeader().GetLogid(),
 			"msgType", msg.GetHeader().GetType(), "checksum", msg.GetHeader().GetDataCheckSum())
@@ -143,6 +144,7 @@ func (p *P2PServerV2) SendMessageWithResponse(ctx xctx.XContext, msg *pb.XuperMe
 	} else {
 		peerIDs = peers
 	}
+	peerIDs = p.routeByChain(msg, peerIDs)
 	ctx.GetTimer().Mark("filter")
 
 	if len(peerIDs) <= 0 {
@@ -0,0 +1,162 @@
+package p2pv2
+
+import (
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/patrickmn/go-cache"
+
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+// peerInfoRequestInterval 同一节点PeerInfo请求的最小间隔
+const peerInfoRequestInterval = 30 * time.Second
+
+// ServeChain 设置本节点服务的链及群组成员，并向路由表中的节点公告
+func (p *P2PServerV2) ServeChain(bcname string, members []string) {
+	p.router.ServeChain(bcname, members)
+	go p.announceChains()
+}
+
+// LeaveChain 取消服务的链，并向路由表中的节点公告
+func (p *P2PServerV2) LeaveChain(bcname string) {
+	p.router.LeaveChain(bcname)
+	go p.announceChains()
+}
+
+func (p *P2PServerV2) registerPeerInfoHandler() {
+	sub := p2p.NewSubscriber(p.ctx, pb.XuperMessage_GET_PEER_INFO, p2p.StreamHandleFunc(p.handleGetPeerInfo))
+	if err := p.Register(sub); err != nil {
+		p.log.Error("register peer info subscriber error", "error", err)
+	}
+}
+
+// handleGetPeerInfo 记录对方公告的链，并返回本节点信息
+func (p *P2PServerV2) handleGetPeerInfo(ctx xctx.XContext, request *pb.XuperMessage,
+	stream p2p.Stream) (*pb.XuperMessage, error) {
+	output := p.localPeerInfo()
+	opts := []p2p.MessageOption{
+		p2p.WithBCName(request.GetHeader().GetBcname()),
+		p2p.WithErrorType(pb.XuperMessage_SUCCESS),
+		p2p.WithLogId(request.GetHeader().GetLogid()),
+	}
+	resp := p2p.NewMessage(pb.XuperMessage_GET_PEER_INFO_RES, &output, opts...)
+
+	s, ok := stream.(*Stream)
+	if !ok {
+		return resp, nil
+	}
+	var peerInfo pb.PeerInfo
+	if err := p2p.Unmarshal(request, &peerInfo); err != nil {
+		p.log.Warn("unmarshal peer info error", "error", err)
+		return resp, nil
+	}
+	p.setPeerInfo(&peerInfo, s.PeerID())
+
+	return resp, nil
+}
+
+// announceChains 逐个与路由表中的节点交换PeerInfo，公告本节点服务的链，
+// 逐个发送才能确定每个响应来自哪个节点
+func (p *P2PServerV2) announceChains() {
+	for _, peerID := range p.kdht.RoutingTable().ListPeers() {
+		go p.exchangePeerInfo(peerID)
+	}
+}
+
+// setPeerInfo 仅记录签名校验通过且节点ID与传输层对端一致的PeerInfo，
+// 避免账户持有者伪造其他节点ID的PeerInfo覆盖该节点的账户和服务的链
+func (p *P2PServerV2) setPeerInfo(peerInfo *pb.PeerInfo, remote peer.ID) {
+	if err := p2p.VerifyPeerInfo(peerInfo); err != nil {
+		p.log.Debug("drop unverified peer info", "peerID", peerInfo.GetId(),
+			"account", peerInfo.GetAccount(), "error", err)
+		return
+	}
+
+	peerID, err := peer.Decode(peerInfo.GetId())
+	if err != nil || peerID != remote {
+		p.log.Debug("drop peer info not from the peer", "peerID", peerInfo.GetId(), "remote", remote)
+		return
+	}
+
+	// 签名时间早于已记录公告的PeerInfo是重放的旧公告
+	if !p.router.SetPeerChains(peerInfo.GetAccount(), peerInfo.GetChains(), peerInfo.GetTimestamp()) {
+		p.log.Debug("drop stale peer info", "peerID", peerInfo.GetId(), "account", peerInfo.GetAccount())
+		return
+	}
+	p.peerAccounts.Set(peerID.Pretty(), peerInfo.GetAccount(), cache.NoExpiration)
+}
+
+// requestPeerInfo 向未知账户的节点交换PeerInfo，同一节点在间隔内只请求一次
+func (p *P2PServerV2) requestPeerInfo(peerID peer.ID) {
+	if err := p.peerInfoRequests.Add(peerID.Pretty(), true, cache.DefaultExpiration); err != nil {
+		return
+	}
+	p.exchangePeerInfo(peerID)
+}
+
+// exchangePeerInfo 向指定节点发送本节点的PeerInfo并记录对方返回的PeerInfo
+func (p *P2PServerV2) exchangePeerInfo(peerID peer.ID) {
+	peerInfo := p.localPeerInfo()
+	msg := p2p.NewMessage(pb.XuperMessage_GET_PEER_INFO, &peerInfo)
+	response, err := p.SendMessageWithResponse(p.ctx, msg, p2p.WithPeerIDs([]string{peerID.Pretty()}))
+	if err != nil {
+		p.log.Debug("exchange peer info error", "peerID", peerID, "error", err)
+		return
+	}
+
+	for _, resp := range response {
+		var remote pb.PeerInfo
+		if err := p2p.Unmarshal(resp, &remote); err != nil {
+			continue
+		}
+		p.setPeerInfo(&remote, peerID)
+	}
+}
+
+// routeByChain 过滤掉不能接收该链消息的节点
+func (p *P2PServerV2) routeByChain(msg *pb.XuperMessage, peerIDs []peer.ID) []peer.ID {
+	bcname := msg.GetHeader().GetBcname()
+	if !p.router.IsRestricted(bcname) {
+		return peerIDs
+	}
+
+	routable := make([]peer.ID, 0, len(peerIDs))
+	for _, peerID := range peerIDs {
+		account := p.GetAccountByPeerID(peerID)
+		if !p.router.Routable(bcname, account) {
+			p.log.Debug("p2p: peer not routable for chain", "log_id", msg.GetHeader().GetLogid(),
+				"bcname", bcname, "peerID", peerID, "account", account)
+			continue
+		}
+		routable = append(routable, peerID)
+	}
+
+	return routable
+}
+
+// resolveAccount 优先使用流的远端节点ID解析账户，避免信任消息头中的from
+func (p *P2PServerV2) resolveAccount(msg *pb.XuperMessage, stream p2p.Stream) string {
+	if s, ok := stream.(*Stream); ok {
+		return p.GetAccountByPeerID(s.PeerID())
+	}
+
+	peerID, err := peer.Decode(msg.GetHeader().GetFrom())
+	if err != nil {
+		return ""
+	}
+	return p.GetAccountByPeerID(peerID)
+}
+
+// GetAccountByPeerID get verified account of remote peer, return empty string if not found
+func (p *P2PServerV2) GetAccountByPeerID(peerID peer.ID) string {
+	if value, ok := p.peerAccounts.Get(peerID.Pretty()); ok {
+		return value.(string)
+	}
+
+	// dht中的peer.ID => account由对方自行写入，未经校验，改为交换签名的PeerInfo
+	go p.requestPeerInfo(peerID)
+	return ""
+}
@@ -87,6 +87,15 @@ type P2PServerV2 struct {
 	// accounts store remote peer account: key:account => v:peer.ID
 	// accounts as cache, store in dht
 	accounts *cache.Cache
+	// peerAccounts store remote peer account: key:peer.ID => v:account
+	peerAccounts *cache.Cache
+
+	// router chain level peer membership
+	router *p2p.ChainRouter
+	// signer sign local peer info with account key
+	signer *p2p.PeerSigner
+	// peerInfoRequests peers whose peer info is being requested
+	peerInfoRequests *cache.Cache
 }
 
 var _ p2p.Server = &P2PServerV2{}
@@ -144,11 +153,18 @@ func (p *P2PServerV2) Init(ctx *nctx.NetCtx) error {
 	if err != nil {
 		return ErrLoadAccount
 	}
+	// 未加载到账户密钥时PeerInfo不签名，对端无法将本节点识别为受限链的成员
+	if p.signer, err = p2p.NewPeerSigner(keyPath); err != nil {
+		p.log.Warn("load peer signer error", "error", err)
+	}
 
 	p.accounts = cache.New(cache.NoExpiration, cache.NoExpiration)
+	p.peerAccounts = cache.New(cache.NoExpiration, cache.NoExpiration)
+	p.peerInfoRequests = cache.New(peerInfoRequestInterval, peerInfoRequestInterval)
 
 	// dispatcher
-	p.dispatcher = p2p.NewDispatcher(ctx)
+	p.router = p2p.NewChainRouter()
+	p.dispatcher = p2p.NewDispatcher(ctx, p2p.WithChainRouter(p.router, p.resolveAccount))
 
 	p.streamPool, err = NewStreamPool(ctx, p)
 	if err != nil {
@@ -245,6 +261,7 @@ func (p *P2PServerV2) Start() {
 	p.host.SetStreamHandler(protocol.ID(protocolID), p.streamHandler)
 
 	p.setKdhtValue()
+	p.registerPeerInfoHandler()
 
 	ctx, cancel := context.WithCancel(p.ctx)
 	p.cancel = cancel
@@ -348,11 +365,7 @@ func (p *P2PServerV2) Context() *nctx.NetCtx {
 }
 
 func (p *P2PServerV2) PeerInfo() pb.PeerInfo {
-	peerInfo := pb.PeerInfo{
-		Id:      p.host.ID().Pretty(),
-		Address: p.getMultiAddr(p.host.ID(), p.host.Addrs()),
-		Account: p.account,
-	}
+	peerInfo := p.localPeerInfo()
 
 	peerStore := p.host.Peerstore()
 	for _, peerID := range p.kdht.RoutingTable().ListPeers() {
@@ -374,6 +387,23 @@ func (p *P2PServerV2) PeerInfo() pb.PeerInfo {
 	return peerInfo
 }
 
+// localPeerInfo return peer info of local host without remote peers
+func (p *P2PServerV2) localPeerInfo() pb.PeerInfo {
+	peerInfo := pb.PeerInfo{
+		Id:      p.host.ID().Pretty(),
+		Address: p.getMultiAddr(p.host.ID(), p.host.Addrs()),
+		Account: p.account,
+		Chains:  p.router.Chains(),
+	}
+	if p.signer != nil {
+		if err := p.signer.Sign(&peerInfo); err != nil {
+			p.log.Warn("sign peer info error", "error", err)
+		}
+	}
+
+	return peerInfo
+}
+
 func (p *P2PServerV2) getMultiAddr(peerID peer.ID, addrs []multiaddr.Multiaddr) string {
 	peerInfo := &peer.AddrInfo{
 		ID:    peerID,
@@ -1,13 +1,20 @@
 package p2pv2
 
 import (
+	"crypto/rand"
 	"fmt"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/patrickmn/go-cache"
+
 	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
 	"github.com/xuperchain/xupercore/kernel/mock"
 	nctx "github.com/xuperchain/xupercore/kernel/network/context"
 	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/logs"
 	pb "github.com/xuperchain/xupercore/protos"
-	"testing"
 )
 
 func Handler(ctx xctx.XContext, msg *pb.XuperMessage) (*pb.XuperMessage, error) {
@@ -112,3 +119,53 @@ func TestP2PServerV2(t *testing.T) {
 	startNode2(t)
 	startNode3(t)
 }
+
+func TestSetPeerInfo(t *testing.T) {
+	mock.InitLogForTest()
+	log, _ := logs.NewLogger("", "p2pv2")
+	p := &P2PServerV2{
+		log:          log,
+		router:       p2p.NewChainRouter(),
+		peerAccounts: cache.New(cache.NoExpiration, cache.NoExpiration),
+	}
+	p.router.ServeChain("para", []string{"alice"})
+	signer, err := p2p.NewPeerSigner("../../../kernel/mock/p2pv2/node1/data/keys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	victim, attacker := newPeerID(t), newPeerID(t)
+
+	info := &pb.PeerInfo{
+		Id:      victim.Pretty(),
+		Account: signer.Address(),
+		Chains:  []string{"para"},
+	}
+	if err := signer.Sign(info); err != nil {
+		t.Fatal(err)
+	}
+	// 节点ID与传输层的对端不一致
+	p.setPeerInfo(info, attacker)
+	if _, ok := p.peerAccounts.Get(victim.Pretty()); ok {
+		t.Fatal("peer info from other peer should be dropped")
+	}
+	if _, ok := p.peerAccounts.Get(attacker.Pretty()); ok {
+		t.Fatal("peer info from other peer should be dropped")
+	}
+
+	p.setPeerInfo(info, victim)
+	if account, ok := p.peerAccounts.Get(victim.Pretty()); !ok || account != signer.Address() {
+		t.Fatalf("expect account bound to peer, got %v", account)
+	}
+}
+
+func newPeerID(t *testing.T) peer.ID {
+	priv, _, err := crypto.GenerateEd25519Key(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := peer.IDFromPrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
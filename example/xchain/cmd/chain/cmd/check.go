@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/checker"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/context"
+	xdef "github.com/xuperchain/xupercore/example/xchain/common/def"
+	"github.com/xuperchain/xupercore/lib/crypto/client"
+	"github.com/xuperchain/xupercore/lib/logs"
+
+	"github.com/spf13/cobra"
+)
+
+type CheckCmd struct {
+	BaseCmd
+}
+
+func GetCheckCmd() *CheckCmd {
+	checkCmdIns := new(CheckCmd)
+
+	// 定义命令行参数变量
+	var envCfgPath string
+	var bcName string
+	var repair string
+
+	checkCmdIns.Cmd = &cobra.Command{
+		Use:           "check",
+		Short:         "Check and repair ledger and state consistency offline.",
+		Example:       xdef.ServerName + " check --conf /home/rd/xuperos/conf/env.yaml --name xuper --repair truncate",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return CheckChain(envCfgPath, bcName, repair)
+		},
+	}
+
+	// 设置命令行参数并绑定变量
+	checkCmdIns.Cmd.Flags().StringVarP(&envCfgPath, "conf", "c", "",
+		"engine environment config file path")
+	checkCmdIns.Cmd.Flags().StringVarP(&bcName, "name", "n", "xuper", "block chain name")
+	checkCmdIns.Cmd.Flags().StringVarP(&repair, "repair", "r", "",
+		"repair mode, truncate or replay, only check if empty")
+
+	return checkCmdIns
+}
+
+// CheckChain 检查链的账本和状态机，节点需要停止运行
+func CheckChain(envCfgPath, bcName, repair string) error {
+	envConf, _, err := loadConf(envCfgPath)
+	if err != nil {
+		return err
+	}
+	logs.InitLog(envConf.GenConfFilePath(envConf.LogConf), envConf.GenDirAbsPath(envConf.LogDir))
+
+	lctx, err := ledger.NewLedgerCtx(envConf, bcName)
+	if err != nil {
+		return err
+	}
+	leg, err := ledger.OpenLedger(lctx)
+	if err != nil {
+		return err
+	}
+	defer leg.Close()
+
+	crypt, err := client.CreateCryptoClient(leg.GetGenesisBlock().GetConfig().GetCryptoType())
+	if err != nil {
+		return err
+	}
+	sctx, err := context.NewStateCtx(envConf, bcName, leg, crypt)
+	if err != nil {
+		return err
+	}
+	sta, err := state.NewState(sctx)
+	if err != nil {
+		return err
+	}
+	defer sta.Close()
+
+	report, err := checker.Check(leg, sta)
+	if err != nil {
+		return err
+	}
+	printReport(report)
+	if report.Consistent() || repair == "" {
+		return nil
+	}
+
+	if err := checker.Repair(leg, sta, report, repair); err != nil {
+		return err
+	}
+	report, err = checker.Check(leg, sta)
+	if err != nil {
+		return err
+	}
+	fmt.Println("after repair:")
+	printReport(report)
+	return nil
+}
+
+func printReport(report *checker.Report) {
+	for _, line := range report.Strings() {
+		fmt.Println(line)
+	}
+	if report.Consistent() {
+		fmt.Println("ledger and state are consistent")
+	}
+}
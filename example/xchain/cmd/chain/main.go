@@ -39,6 +39,8 @@ func NewServiceCommand() (*cobra.Command, error) {
 
 	// cmd service
 	rootCmd.AddCommand(cmd.GetStartupCmd().GetCmd())
+	// cmd check
+	rootCmd.AddCommand(cmd.GetCheckCmd().GetCmd())
 	// cmd version
 	rootCmd.AddCommand(GetVersionCmd().GetCmd())
 
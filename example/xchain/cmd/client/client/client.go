@@ -143,6 +143,26 @@ func (t *XchainClient) QueryTx(txId string) (*xchainpb.QueryTxResp, error) {
 	return resp, nil
 }
 
+func (t *XchainClient) Backup(name string) (*xchainpb.BackupResp, error) {
+	req := &xchainpb.BackupReq{
+		Header: t.genReqHeader(),
+		Bcname: global.GFlagBCName,
+		Name:   name,
+	}
+
+	ctx := context.TODO()
+	resp, err := t.xclient.Backup(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.GetHeader().GetErrCode() != 0 {
+		return nil, fmt.Errorf("ErrCode:%d ErrMsg:%s LogId:%s TraceId:%s", resp.GetHeader().GetErrCode(),
+			resp.GetHeader().GetErrMsg(), resp.GetHeader().GetLogId(), resp.GetHeader().GetTraceId())
+	}
+
+	return resp, nil
+}
+
 func (t *XchainClient) genReqHeader() *xchainpb.ReqHeader {
 	return &xchainpb.ReqHeader{
 		LogId:    utils.GenLogId(),
@@ -144,12 +144,14 @@ type Transaction struct {
 	AuthRequireSigns  []SignatureInfo  `json:"authRequireSigns"`
 	ReceivedTimestamp int64            `json:"receivedTimestamp"`
 	ModifyBlock       ModifyBlock      `json:"modifyBlock"`
+	ValidUntilHeight  int64            `json:"validUntilHeight,omitempty"`
 }
 
 type ModifyBlock struct {
-	Marked          bool   `json:"marked"`
-	EffectiveHeight int64  `json:"effectiveHeight"`
-	EffectiveTxid   string `json:"effectiveTxid"`
+	Marked          bool     `json:"marked"`
+	EffectiveHeight int64    `json:"effectiveHeight"`
+	EffectiveTxid   string   `json:"effectiveTxid"`
+	RedactedFields  []string `json:"redactedFields,omitempty"`
 }
 
 // BigInt big int
@@ -181,6 +183,7 @@ func FromPBTx(tx *xldgpb.Transaction) *Transaction {
 		Coinbase:          tx.Coinbase,
 		Initiator:         tx.Initiator,
 		ReceivedTimestamp: tx.ReceivedTimestamp,
+		ValidUntilHeight:  tx.ValidUntilHeight,
 	}
 	for _, input := range tx.TxInputs {
 		t.TxInputs = append(t.TxInputs, TxInput{
@@ -255,6 +258,7 @@ func FromPBTx(tx *xldgpb.Transaction) *Transaction {
 			EffectiveHeight: tx.ModifyBlock.EffectiveHeight,
 			Marked:          tx.ModifyBlock.Marked,
 			EffectiveTxid:   tx.ModifyBlock.EffectiveTxid,
+			RedactedFields:  tx.ModifyBlock.RedactedFields,
 		}
 	}
 	return t
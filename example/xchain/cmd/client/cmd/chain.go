@@ -27,6 +27,10 @@ func GetChainCmd() *ChainCmd {
 	chainCmdIns.Cmd.AddCommand(chaincmd.GetChainStatusCmd().GetCmd())
 	// create chain
 	chainCmdIns.Cmd.AddCommand(chaincmd.GetCreateChainCmd().GetCmd())
+	// backup chain online
+	chainCmdIns.Cmd.AddCommand(chaincmd.GetBackupChainCmd().GetCmd())
+	// restore chain from backup
+	chainCmdIns.Cmd.AddCommand(chaincmd.GetRestoreChainCmd().GetCmd())
 
 	return chainCmdIns
 }
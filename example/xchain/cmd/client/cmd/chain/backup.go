@@ -0,0 +1,58 @@
+package chain
+
+import (
+	"fmt"
+
+	"github.com/xuperchain/xupercore/example/xchain/cmd/client/client"
+	"github.com/xuperchain/xupercore/example/xchain/cmd/client/common/global"
+	xdef "github.com/xuperchain/xupercore/example/xchain/common/def"
+
+	"github.com/spf13/cobra"
+)
+
+type BackupChainCmd struct {
+	global.BaseCmd
+	// 备份文件名，写入节点数据目录下的backup目录
+	File string
+}
+
+func GetBackupChainCmd() *BackupChainCmd {
+	backupChainCmdIns := new(BackupChainCmd)
+
+	subCmd := &cobra.Command{
+		Use:           "backup",
+		Short:         "backup chain data online.",
+		Example:       xdef.CmdLineName + " chain backup -f xuper.bak",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return backupChainCmdIns.backupChain()
+		},
+	}
+	backupChainCmdIns.SetCmd(subCmd)
+
+	// 设置命令行参数并绑定变量
+	subCmd.Flags().StringVarP(&backupChainCmdIns.File, "file", "f", "", "backup file name")
+
+	return backupChainCmdIns
+}
+
+func (t *BackupChainCmd) backupChain() error {
+	if t.File == "" {
+		return fmt.Errorf("backup file name unset")
+	}
+
+	xcli, err := client.NewXchainClient()
+	if err != nil {
+		return fmt.Errorf("grpc dial failed.err:%v", err)
+	}
+
+	resp, err := xcli.Backup(t.File)
+	if err != nil {
+		return fmt.Errorf("backup chain failed.err:%v", err)
+	}
+
+	fmt.Printf("backup chain succ.bc_name:%s height:%d tip_blockid:%s path:%s\n",
+		resp.GetBcname(), resp.GetHeight(), resp.GetTipBlockid(), resp.GetPath())
+	return nil
+}
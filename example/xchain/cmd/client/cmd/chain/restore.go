@@ -0,0 +1,82 @@
+package chain
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/backup"
+	"github.com/xuperchain/xupercore/example/xchain/cmd/client/common/global"
+	xdef "github.com/xuperchain/xupercore/example/xchain/common/def"
+	"github.com/xuperchain/xupercore/kernel/common/xconfig"
+	"github.com/xuperchain/xupercore/lib/logs"
+	_ "github.com/xuperchain/xupercore/lib/storage/kvdb/leveldb"
+	xutils "github.com/xuperchain/xupercore/lib/utils"
+
+	"github.com/spf13/cobra"
+)
+
+type RestoreChainCmd struct {
+	global.BaseCmd
+	// 备份文件
+	File string
+	// 环境配置文件
+	EnvConf string
+}
+
+func GetRestoreChainCmd() *RestoreChainCmd {
+	restoreChainCmdIns := new(RestoreChainCmd)
+
+	subCmd := &cobra.Command{
+		Use:           "restore",
+		Short:         "restore chain from backup file, node must be stopped.",
+		Example:       xdef.CmdLineName + " chain restore -f ./data/backup/xuper.bak",
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return restoreChainCmdIns.restoreChain()
+		},
+	}
+	restoreChainCmdIns.SetCmd(subCmd)
+
+	// 设置命令行参数并绑定变量
+	subCmd.Flags().StringVarP(&restoreChainCmdIns.File, "file", "f", "", "backup file path")
+	subCmd.Flags().StringVarP(&restoreChainCmdIns.EnvConf,
+		"env_conf", "e", "./conf/env.yaml", "env config file path")
+
+	return restoreChainCmdIns
+}
+
+func (t *RestoreChainCmd) restoreChain() error {
+	log.Printf("start restore chain.bc_name:%s file:%s env_conf:%s\n",
+		global.GFlagBCName, t.File, t.EnvConf)
+
+	if !xutils.FileIsExist(t.File) || !xutils.FileIsExist(t.EnvConf) {
+		log.Printf("file not exist.file:%s env_conf:%s\n", t.File, t.EnvConf)
+		return fmt.Errorf("file not exist")
+	}
+
+	econf, err := xconfig.LoadEnvConf(t.EnvConf)
+	if err != nil {
+		log.Printf("load env config failed.env_conf:%s err:%v\n", t.EnvConf, err)
+		return fmt.Errorf("load env config failed")
+	}
+	logs.InitLog(econf.GenConfFilePath(econf.LogConf), econf.GenDirAbsPath(econf.LogDir))
+
+	f, err := os.Open(t.File)
+	if err != nil {
+		log.Printf("open backup file failed.file:%s err:%v\n", t.File, err)
+		return fmt.Errorf("open backup file failed")
+	}
+	defer f.Close()
+
+	manifest, err := backup.Restore(econf, global.GFlagBCName, f)
+	if err != nil {
+		log.Printf("restore chain failed.err:%v\n", err)
+		return fmt.Errorf("restore chain failed")
+	}
+
+	log.Printf("restore chain succ.bc_name:%s height:%d tip_blockid:%s\n",
+		manifest.BCName, manifest.Height, manifest.TipBlockid)
+	return nil
+}
@@ -39,6 +39,7 @@ type TransferTxCmd struct {
 	To           string
 	Amount       string
 	FrozenHeight int64
+	ValidUntil   int64
 }
 
 func GetTransferTxCmd() *TransferTxCmd {
@@ -59,6 +60,7 @@ func GetTransferTxCmd() *TransferTxCmd {
 	transTxCmdIns.Cmd.Flags().StringVarP(&transTxCmdIns.To, "to", "t", "", "to address")
 	transTxCmdIns.Cmd.Flags().StringVarP(&transTxCmdIns.Amount, "amount", "a", "", "transfer amount")
 	transTxCmdIns.Cmd.Flags().Int64VarP(&transTxCmdIns.FrozenHeight, "frozen", "f", 0, "frozen height of one tx")
+	transTxCmdIns.Cmd.Flags().Int64Var(&transTxCmdIns.ValidUntil, "valid-until", 0, "tx can only be packed into blocks not higher than this height")
 
 	return transTxCmdIns
 }
@@ -111,12 +113,13 @@ func (t *TransferTxCmd) generateTx(utxoRes *xchainpb.SelectUtxoResp, amount *big
 	}
 
 	tx := &xldgpb.Transaction{
-		Version:   1,
-		Coinbase:  false,
-		Desc:      CommonTransferDesc,
-		Nonce:     utils.GenNonce(),
-		Timestamp: time.Now().UnixNano(),
-		Initiator: addr.Address,
+		Version:          1,
+		Coinbase:         false,
+		Desc:             CommonTransferDesc,
+		Nonce:            utils.GenNonce(),
+		Timestamp:        time.Now().UnixNano(),
+		Initiator:        addr.Address,
+		ValidUntilHeight: t.ValidUntil,
 	}
 	txOutput := &protos.TxOutput{}
 	txOutput.ToAddr = []byte(t.To)
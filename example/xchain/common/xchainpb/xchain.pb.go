@@ -593,13 +593,15 @@ func (m *QueryTxReq) GetTxid() []byte {
 }
 
 type QueryTxResp struct {
-	Header               *RespHeader              `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
-	Status               xldgpb.TransactionStatus `protobuf:"varint,2,opt,name=status,proto3,enum=xldgpb.TransactionStatus" json:"status,omitempty"`
-	Distance             int64                    `protobuf:"varint,3,opt,name=distance,proto3" json:"distance,omitempty"`
-	Tx                   *xldgpb.Transaction      `protobuf:"bytes,4,opt,name=tx,proto3" json:"tx,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
-	XXX_unrecognized     []byte                   `json:"-"`
-	XXX_sizecache        int32                    `json:"-"`
+	Header   *RespHeader              `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Status   xldgpb.TransactionStatus `protobuf:"varint,2,opt,name=status,proto3,enum=xldgpb.TransactionStatus" json:"status,omitempty"`
+	Distance int64                    `protobuf:"varint,3,opt,name=distance,proto3" json:"distance,omitempty"`
+	Tx       *xldgpb.Transaction      `protobuf:"bytes,4,opt,name=tx,proto3" json:"tx,omitempty"`
+	// 交易内容是否被监管脱敏
+	Redacted             bool     `protobuf:"varint,5,opt,name=redacted,proto3" json:"redacted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *QueryTxResp) Reset()         { *m = QueryTxResp{} }
@@ -655,6 +657,13 @@ func (m *QueryTxResp) GetTx() *xldgpb.Transaction {
 	return nil
 }
 
+func (m *QueryTxResp) GetRedacted() bool {
+	if m != nil {
+		return m.Redacted
+	}
+	return false
+}
+
 type QueryBlockReq struct {
 	Header               *ReqHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
 	Bcname               string     `protobuf:"bytes,2,opt,name=bcname,proto3" json:"bcname,omitempty"`
@@ -899,6 +908,134 @@ func (m *QueryChainStatusResp) GetBranchBlockId() []string {
 	return nil
 }
 
+type BackupReq struct {
+	Header *ReqHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Bcname string     `protobuf:"bytes,2,opt,name=bcname,proto3" json:"bcname,omitempty"`
+	// 备份文件名，文件写入节点数据目录下的backup目录
+	Name                 string   `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BackupReq) Reset()         { *m = BackupReq{} }
+func (m *BackupReq) String() string { return proto.CompactTextString(m) }
+func (*BackupReq) ProtoMessage()    {}
+func (*BackupReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_db0991b9525664ca, []int{15}
+}
+
+func (m *BackupReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BackupReq.Unmarshal(m, b)
+}
+func (m *BackupReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BackupReq.Marshal(b, m, deterministic)
+}
+func (m *BackupReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BackupReq.Merge(m, src)
+}
+func (m *BackupReq) XXX_Size() int {
+	return xxx_messageInfo_BackupReq.Size(m)
+}
+func (m *BackupReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_BackupReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BackupReq proto.InternalMessageInfo
+
+func (m *BackupReq) GetHeader() *ReqHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *BackupReq) GetBcname() string {
+	if m != nil {
+		return m.Bcname
+	}
+	return ""
+}
+
+func (m *BackupReq) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type BackupResp struct {
+	Header *RespHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	Bcname string      `protobuf:"bytes,2,opt,name=bcname,proto3" json:"bcname,omitempty"`
+	// 备份文件在节点上的路径
+	Path                 string   `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	Height               int64    `protobuf:"varint,4,opt,name=height,proto3" json:"height,omitempty"`
+	TipBlockid           string   `protobuf:"bytes,5,opt,name=tipBlockid,proto3" json:"tipBlockid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BackupResp) Reset()         { *m = BackupResp{} }
+func (m *BackupResp) String() string { return proto.CompactTextString(m) }
+func (*BackupResp) ProtoMessage()    {}
+func (*BackupResp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_db0991b9525664ca, []int{16}
+}
+
+func (m *BackupResp) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BackupResp.Unmarshal(m, b)
+}
+func (m *BackupResp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BackupResp.Marshal(b, m, deterministic)
+}
+func (m *BackupResp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BackupResp.Merge(m, src)
+}
+func (m *BackupResp) XXX_Size() int {
+	return xxx_messageInfo_BackupResp.Size(m)
+}
+func (m *BackupResp) XXX_DiscardUnknown() {
+	xxx_messageInfo_BackupResp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BackupResp proto.InternalMessageInfo
+
+func (m *BackupResp) GetHeader() *RespHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *BackupResp) GetBcname() string {
+	if m != nil {
+		return m.Bcname
+	}
+	return ""
+}
+
+func (m *BackupResp) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *BackupResp) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *BackupResp) GetTipBlockid() string {
+	if m != nil {
+		return m.TipBlockid
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*ReqHeader)(nil), "xchainpb.ReqHeader")
 	proto.RegisterType((*RespHeader)(nil), "xchainpb.RespHeader")
@@ -915,67 +1052,74 @@ func init() {
 	proto.RegisterType((*QueryBlockResp)(nil), "xchainpb.QueryBlockResp")
 	proto.RegisterType((*QueryChainStatusReq)(nil), "xchainpb.QueryChainStatusReq")
 	proto.RegisterType((*QueryChainStatusResp)(nil), "xchainpb.QueryChainStatusResp")
+	proto.RegisterType((*BackupReq)(nil), "xchainpb.BackupReq")
+	proto.RegisterType((*BackupResp)(nil), "xchainpb.BackupResp")
 }
 
 func init() { proto.RegisterFile("xchain.proto", fileDescriptor_db0991b9525664ca) }
 
 var fileDescriptor_db0991b9525664ca = []byte{
-	// 876 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0xdd, 0x6e, 0x1b, 0x45,
-	0x14, 0xee, 0xc6, 0x8d, 0xbd, 0x3e, 0x4e, 0x42, 0x99, 0xd6, 0xed, 0xd6, 0xfc, 0x59, 0x0b, 0x17,
-	0x96, 0x52, 0x39, 0x8a, 0x11, 0xd0, 0x3b, 0x94, 0x44, 0x48, 0x58, 0x4a, 0x2b, 0x98, 0x14, 0x89,
-	0xbb, 0x68, 0xbc, 0x7b, 0xb0, 0x57, 0x59, 0xcf, 0x38, 0x33, 0xe3, 0x6a, 0xb9, 0x47, 0x42, 0x82,
-	0x1b, 0x2e, 0x79, 0x04, 0xee, 0x78, 0x09, 0x24, 0x5e, 0x84, 0x07, 0x41, 0x33, 0xfb, 0x37, 0x59,
-	0xd2, 0x08, 0x4b, 0xee, 0xd5, 0xee, 0xf9, 0x3f, 0xe7, 0x3b, 0x3f, 0x03, 0x7b, 0x59, 0xb4, 0x60,
-	0x09, 0x1f, 0xaf, 0xa4, 0xd0, 0x82, 0xf8, 0x39, 0xb5, 0x9a, 0x0d, 0x8e, 0xb3, 0xf5, 0x0a, 0x65,
-	0x24, 0x24, 0x1e, 0xcd, 0x22, 0x75, 0x94, 0x62, 0x3c, 0x47, 0x79, 0x94, 0x55, 0xdf, 0x78, 0xbe,
-	0x9a, 0x95, 0x64, 0x6e, 0x3c, 0xf8, 0xa8, 0x36, 0xb1, 0x0c, 0x75, 0x14, 0x09, 0xae, 0x25, 0x8b,
-	0x74, 0xae, 0x10, 0x7e, 0x09, 0x5d, 0x8a, 0xd7, 0x5f, 0x23, 0x8b, 0x51, 0x92, 0x3e, 0xb4, 0x53,
-	0x31, 0xbf, 0x4c, 0xe2, 0xc0, 0x1b, 0x7a, 0xa3, 0x2e, 0xdd, 0x4d, 0xc5, 0x7c, 0x1a, 0x93, 0xf7,
-	0xa0, 0xab, 0x30, 0xfd, 0xe1, 0x92, 0xb3, 0x25, 0x06, 0x3b, 0x56, 0xe2, 0x1b, 0xc6, 0x4b, 0xb6,
-	0xc4, 0x50, 0x02, 0x50, 0x54, 0xab, 0xbb, 0x3d, 0x3c, 0x05, 0x1f, 0xa5, 0xbc, 0x8c, 0x44, 0x9c,
-	0x3b, 0x68, 0xd1, 0x0e, 0x4a, 0x79, 0x26, 0x62, 0x24, 0x4f, 0xc0, 0xfc, 0x5e, 0x2e, 0xd5, 0x3c,
-	0x68, 0x59, 0x93, 0x36, 0x4a, 0xf9, 0x42, 0xcd, 0x8d, 0x8d, 0x49, 0x14, 0x8d, 0xb3, 0xfb, 0x56,
-	0xd2, 0xb1, 0xf4, 0x34, 0x0e, 0x3f, 0x87, 0xce, 0x29, 0x53, 0x48, 0xf1, 0x9a, 0x1c, 0x42, 0x7b,
-	0x61, 0x43, 0xdb, 0x80, 0xbd, 0xc9, 0xc3, 0x71, 0x09, 0xd7, 0xb8, 0xaa, 0x8b, 0x16, 0x2a, 0xe1,
-	0x73, 0xf0, 0x73, 0x3b, 0xb5, 0x22, 0xcf, 0x1a, 0x86, 0x8f, 0x5c, 0xc3, 0xb2, 0x9e, 0xca, 0xf2,
-	0x57, 0x0f, 0x7a, 0x17, 0xeb, 0xd9, 0x32, 0xd1, 0xaf, 0xb2, 0x4d, 0xc3, 0x92, 0xc7, 0xd0, 0x9e,
-	0x45, 0x0e, 0x78, 0x05, 0x45, 0x08, 0xdc, 0xd7, 0x59, 0x12, 0xdb, 0xba, 0xf7, 0xa8, 0xfd, 0x27,
-	0x1f, 0xc3, 0x8e, 0xce, 0x6c, 0xbd, 0xd6, 0xa9, 0xed, 0xe9, 0xf8, 0x95, 0x64, 0x5c, 0xb1, 0x48,
-	0x27, 0x82, 0xd3, 0x1d, 0x9d, 0x85, 0x7f, 0x79, 0x00, 0xdf, 0x48, 0xfc, 0x2a, 0xc3, 0x68, 0x6b,
-	0xc9, 0x1c, 0x83, 0x2f, 0xf1, 0x7a, 0x8d, 0x4a, 0xab, 0xa0, 0x35, 0x6c, 0x8d, 0x7a, 0x93, 0x7e,
-	0x3e, 0x22, 0x6a, 0x3c, 0xe5, 0xaf, 0xc5, 0x95, 0x41, 0xdb, 0x48, 0x69, 0xa5, 0x46, 0xde, 0x87,
-	0x6e, 0xc2, 0x13, 0x9d, 0x30, 0x2d, 0x64, 0xd1, 0xa2, 0x9a, 0x41, 0x86, 0xd0, 0x63, 0x6b, 0xbd,
-	0x30, 0x66, 0x89, 0xc4, 0x60, 0x77, 0xd8, 0x1a, 0x75, 0xa9, 0xcb, 0x0a, 0x7f, 0xf6, 0xa0, 0x57,
-	0x95, 0xb1, 0x69, 0x4b, 0xde, 0x58, 0xc8, 0xc4, 0x14, 0xa2, 0x56, 0x82, 0x2b, 0xb4, 0xc8, 0xf6,
-	0x26, 0x8f, 0x9b, 0x85, 0xe4, 0x52, 0x5a, 0xe9, 0x85, 0x7f, 0x78, 0xb0, 0x7f, 0x81, 0x29, 0x46,
-	0xfa, 0x3b, 0x9d, 0x89, 0xad, 0x61, 0x1a, 0x40, 0x87, 0xc5, 0xb1, 0x44, 0xa5, 0x8a, 0xd9, 0x2e,
-	0x49, 0x03, 0x9d, 0x16, 0x9a, 0xa5, 0x2f, 0x11, 0xe3, 0x60, 0x37, 0x87, 0xae, 0x62, 0x90, 0x01,
-	0xf8, 0x1c, 0x31, 0x3e, 0x17, 0xd1, 0x55, 0xd0, 0x1e, 0x7a, 0x23, 0x9f, 0x56, 0x74, 0xf8, 0x8b,
-	0x07, 0x07, 0x6e, 0xaa, 0x1b, 0xe3, 0x36, 0x02, 0x7f, 0xad, 0x33, 0x71, 0x9e, 0x28, 0x1d, 0xec,
-	0xd8, 0x46, 0xef, 0x95, 0x73, 0x66, 0x3d, 0x56, 0x52, 0xd3, 0x41, 0x9b, 0xd3, 0xc9, 0x52, 0xac,
-	0xb9, 0x2e, 0x4a, 0x70, 0x59, 0x21, 0x02, 0x7c, 0xbb, 0x46, 0xf9, 0xe3, 0xdb, 0x5d, 0x8a, 0xf0,
-	0x4f, 0x0f, 0x7a, 0x55, 0x9c, 0x8d, 0x0b, 0x3e, 0x86, 0xb6, 0xd2, 0x4c, 0xaf, 0x95, 0x8d, 0x74,
-	0x30, 0x79, 0x7a, 0xcb, 0x5a, 0x5d, 0x58, 0x05, 0x5a, 0x28, 0x9a, 0x06, 0xc4, 0x89, 0xd2, 0x8c,
-	0x47, 0xf9, 0x0c, 0xb5, 0x68, 0x45, 0xff, 0xbf, 0x0d, 0xfd, 0xcd, 0x83, 0x7d, 0x9b, 0xf1, 0x69,
-	0x2a, 0xa2, 0xab, 0x6d, 0x0e, 0xd4, 0xcc, 0x38, 0x9c, 0x96, 0xf8, 0x94, 0xa4, 0xe9, 0x95, 0x19,
-	0x91, 0x33, 0xc1, 0x35, 0x72, 0x6d, 0xd3, 0xf3, 0xa9, 0xcb, 0x0a, 0x7f, 0xf7, 0xe0, 0xc0, 0x4d,
-	0x69, 0x63, 0x1c, 0x0f, 0x1b, 0x38, 0x56, 0xc5, 0x5b, 0x87, 0x0d, 0x04, 0x0f, 0x61, 0xd7, 0xa6,
-	0x56, 0xac, 0x60, 0xbf, 0xd4, 0x9d, 0x72, 0x8d, 0x92, 0xb3, 0x34, 0x4f, 0x22, 0xd7, 0x09, 0x7f,
-	0xf2, 0xe0, 0xa1, 0x4d, 0xed, 0xcc, 0x44, 0x2f, 0x3c, 0x6d, 0x0b, 0xb3, 0x11, 0xbc, 0x63, 0x60,
-	0x38, 0x95, 0x8c, 0x47, 0x8b, 0xd3, 0x2a, 0x27, 0x9f, 0x36, 0xd9, 0xe1, 0x3f, 0x1e, 0x3c, 0xfa,
-	0x6f, 0x1a, 0x5b, 0x3c, 0x4c, 0x90, 0xbf, 0xcd, 0x2f, 0x50, 0xb3, 0x02, 0x17, 0x52, 0xe2, 0x72,
-	0x5e, 0x49, 0xa8, 0xa3, 0x45, 0x9e, 0xe5, 0xcb, 0x6a, 0x2d, 0xf2, 0x91, 0x7b, 0xe0, 0x2e, 0xab,
-	0xd5, 0xaf, 0x34, 0xc8, 0x27, 0xb0, 0x3f, 0xab, 0xeb, 0x99, 0xc6, 0xc5, 0xd1, 0xbd, 0xc9, 0x9c,
-	0xfc, 0xdd, 0x82, 0xf6, 0xf7, 0x36, 0x7f, 0xf2, 0x19, 0xc0, 0xd9, 0x02, 0xa3, 0xab, 0x93, 0x34,
-	0x79, 0x8d, 0xe4, 0xdd, 0xba, 0xac, 0xe2, 0x79, 0x1d, 0x90, 0x26, 0x4b, 0xad, 0xc2, 0x7b, 0xe4,
-	0x0b, 0xf0, 0xcb, 0xc7, 0x90, 0xf4, 0x6b, 0x0d, 0xe7, 0x81, 0x7c, 0x83, 0xe1, 0x73, 0xe8, 0x14,
-	0x07, 0x9f, 0x38, 0x18, 0xd6, 0x4f, 0xd9, 0xa0, 0x7f, 0x0b, 0xd7, 0x5a, 0x9e, 0x00, 0xd4, 0x57,
-	0x8f, 0x3c, 0x71, 0x82, 0xba, 0x67, 0x7b, 0x10, 0xdc, 0x2e, 0x28, 0x83, 0x17, 0x47, 0xc4, 0x0d,
-	0x5e, 0xdf, 0x2f, 0x37, 0xb8, 0x73, 0x6d, 0xf2, 0xe0, 0xf5, 0xe6, 0xb8, 0xc1, 0x6f, 0xac, 0xb8,
-	0x1b, 0xfc, 0xe6, 0xa2, 0x85, 0xf7, 0xc8, 0x05, 0x3c, 0x68, 0x8e, 0x16, 0xf9, 0xa0, 0xa1, 0x7f,
-	0x73, 0xfa, 0x07, 0x1f, 0xde, 0x25, 0x36, 0x4e, 0x67, 0x6d, 0xfb, 0xae, 0x7d, 0xfa, 0x6f, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0xb0, 0x44, 0x04, 0xda, 0x31, 0x0a, 0x00, 0x00,
+	// 956 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0x4f, 0x6f, 0xe3, 0x44,
+	0x14, 0xc7, 0x4d, 0x9b, 0x38, 0x2f, 0xdb, 0xb2, 0x4c, 0x9b, 0x5d, 0xaf, 0x81, 0x25, 0x32, 0x1c,
+	0x22, 0x75, 0x95, 0xaa, 0x41, 0x0b, 0x7b, 0x43, 0x4d, 0x85, 0x44, 0xa4, 0xee, 0x0a, 0xa6, 0x8b,
+	0xc4, 0xad, 0x9a, 0xd8, 0x8f, 0xc4, 0x4a, 0xe2, 0x71, 0x67, 0x26, 0x2b, 0x73, 0x47, 0x42, 0x82,
+	0x0b, 0x47, 0x4e, 0x9c, 0xf9, 0x20, 0xdc, 0xf8, 0x1a, 0x7c, 0x0e, 0x84, 0x66, 0xc6, 0x76, 0x5c,
+	0x6f, 0x76, 0x45, 0xa4, 0x70, 0xb2, 0xdf, 0xff, 0xf7, 0x7e, 0xf3, 0xde, 0x9b, 0x81, 0x7b, 0x59,
+	0x38, 0x63, 0x71, 0x32, 0x48, 0x05, 0x57, 0x9c, 0xb8, 0x96, 0x4a, 0x27, 0xfe, 0x79, 0xb6, 0x4a,
+	0x51, 0x84, 0x5c, 0xe0, 0xd9, 0x24, 0x94, 0x67, 0x0b, 0x8c, 0xa6, 0x28, 0xce, 0xb2, 0xf2, 0x1b,
+	0x4d, 0xd3, 0x49, 0x41, 0x5a, 0x63, 0xff, 0xa3, 0xb5, 0x89, 0x61, 0xc8, 0xb3, 0x90, 0x27, 0x4a,
+	0xb0, 0x50, 0x59, 0x85, 0xe0, 0x0b, 0x68, 0x53, 0xbc, 0xfd, 0x0a, 0x59, 0x84, 0x82, 0x74, 0xa1,
+	0xb9, 0xe0, 0xd3, 0x9b, 0x38, 0xf2, 0x9c, 0x9e, 0xd3, 0x6f, 0xd3, 0x83, 0x05, 0x9f, 0x8e, 0x23,
+	0xf2, 0x3e, 0xb4, 0x25, 0x2e, 0xbe, 0xbf, 0x49, 0xd8, 0x12, 0xbd, 0x3d, 0x23, 0x71, 0x35, 0xe3,
+	0x05, 0x5b, 0x62, 0x20, 0x00, 0x28, 0xca, 0xf4, 0xed, 0x1e, 0x1e, 0x81, 0x8b, 0x42, 0xdc, 0x84,
+	0x3c, 0xb2, 0x0e, 0x1a, 0xb4, 0x85, 0x42, 0x5c, 0xf2, 0x08, 0xc9, 0x43, 0xd0, 0xbf, 0x37, 0x4b,
+	0x39, 0xf5, 0x1a, 0xc6, 0xa4, 0x89, 0x42, 0x3c, 0x97, 0x53, 0x6d, 0xa3, 0x13, 0x45, 0xed, 0x6c,
+	0xdf, 0x48, 0x5a, 0x86, 0x1e, 0x47, 0xc1, 0x67, 0xd0, 0x1a, 0x31, 0x89, 0x14, 0x6f, 0xc9, 0x29,
+	0x34, 0x67, 0x26, 0xb4, 0x09, 0xd8, 0x19, 0x1e, 0x0f, 0x0a, 0xb8, 0x06, 0x65, 0x5d, 0x34, 0x57,
+	0x09, 0x9e, 0x81, 0x6b, 0xed, 0x64, 0x4a, 0x9e, 0xd4, 0x0c, 0x4f, 0xaa, 0x86, 0x32, 0xad, 0x59,
+	0xfe, 0xe2, 0x40, 0xe7, 0x7a, 0x35, 0x59, 0xc6, 0xea, 0x65, 0xb6, 0x6d, 0x58, 0xf2, 0x00, 0x9a,
+	0x93, 0xb0, 0x02, 0x5e, 0x4e, 0x11, 0x02, 0xfb, 0x2a, 0x8b, 0x23, 0x53, 0xf7, 0x3d, 0x6a, 0xfe,
+	0xc9, 0xc7, 0xb0, 0xa7, 0x32, 0x6f, 0xbf, 0x70, 0x6a, 0xce, 0x74, 0xf0, 0x52, 0xb0, 0x44, 0xb2,
+	0x50, 0xc5, 0x3c, 0xa1, 0x7b, 0x2a, 0x0b, 0xfe, 0x74, 0x00, 0xbe, 0x16, 0xf8, 0x65, 0x86, 0xe1,
+	0xce, 0x92, 0x39, 0x07, 0x57, 0xe0, 0xed, 0x0a, 0xa5, 0x92, 0x5e, 0xa3, 0xd7, 0xe8, 0x77, 0x86,
+	0x5d, 0xdb, 0x22, 0x72, 0x30, 0x4e, 0x5e, 0xf1, 0x39, 0x52, 0x2b, 0xa5, 0xa5, 0x1a, 0xf9, 0x00,
+	0xda, 0x71, 0x12, 0xab, 0x98, 0x29, 0x2e, 0xf2, 0x23, 0x5a, 0x33, 0x48, 0x0f, 0x3a, 0x6c, 0xa5,
+	0x66, 0xda, 0x2c, 0x16, 0xe8, 0x1d, 0xf4, 0x1a, 0xfd, 0x36, 0xad, 0xb2, 0x82, 0x9f, 0x1c, 0xe8,
+	0x94, 0x65, 0x6c, 0x7b, 0x24, 0x6f, 0x2c, 0x64, 0xa8, 0x0b, 0x91, 0x29, 0x4f, 0x24, 0x1a, 0x64,
+	0x3b, 0xc3, 0x07, 0xf5, 0x42, 0xac, 0x94, 0x96, 0x7a, 0xc1, 0x1f, 0x0e, 0x1c, 0x5e, 0xe3, 0x02,
+	0x43, 0xf5, 0xad, 0xca, 0xf8, 0xce, 0x30, 0xf5, 0xa0, 0xc5, 0xa2, 0x48, 0xa0, 0x94, 0x79, 0x6f,
+	0x17, 0xa4, 0x86, 0x4e, 0x71, 0xc5, 0x16, 0x2f, 0x10, 0x23, 0xef, 0xc0, 0x42, 0x57, 0x32, 0x88,
+	0x0f, 0x6e, 0x82, 0x18, 0x5d, 0xf1, 0x70, 0xee, 0x35, 0x7b, 0x4e, 0xdf, 0xa5, 0x25, 0x1d, 0xfc,
+	0xec, 0xc0, 0x51, 0x35, 0xd5, 0xad, 0x71, 0xeb, 0x83, 0xbb, 0x52, 0x19, 0xbf, 0x8a, 0xa5, 0xf2,
+	0xf6, 0xcc, 0x41, 0xdf, 0x2b, 0xfa, 0xcc, 0x78, 0x2c, 0xa5, 0xfa, 0x04, 0x4d, 0x4e, 0x17, 0x4b,
+	0xbe, 0x4a, 0x54, 0x5e, 0x42, 0x95, 0x15, 0x20, 0xc0, 0x37, 0x2b, 0x14, 0x3f, 0xfc, 0xbf, 0x43,
+	0x11, 0xfc, 0xe5, 0x40, 0xa7, 0x8c, 0xb3, 0x75, 0xc1, 0xe7, 0xd0, 0x94, 0x8a, 0xa9, 0x95, 0x34,
+	0x91, 0x8e, 0x86, 0x8f, 0x36, 0x8c, 0xd5, 0xb5, 0x51, 0xa0, 0xb9, 0xa2, 0x3e, 0x80, 0x28, 0x96,
+	0x8a, 0x25, 0xa1, 0xed, 0xa1, 0x06, 0x2d, 0xe9, 0xff, 0x34, 0xa1, 0xda, 0x81, 0xc0, 0x88, 0x85,
+	0x2a, 0x3f, 0x5e, 0x97, 0x96, 0x74, 0xf0, 0xab, 0x03, 0x87, 0xa6, 0x9a, 0xd1, 0x82, 0x87, 0xf3,
+	0x5d, 0x36, 0xdb, 0x44, 0x3b, 0x1c, 0x17, 0xd8, 0x15, 0xa4, 0x3e, 0x47, 0xdd, 0x3e, 0x97, 0x3c,
+	0x51, 0x98, 0x28, 0x93, 0xba, 0x4b, 0xab, 0xac, 0xe0, 0x37, 0x07, 0x8e, 0xaa, 0x29, 0x6d, 0x8d,
+	0xf1, 0x69, 0x0d, 0xe3, 0x12, 0x18, 0xe3, 0xb0, 0x86, 0xee, 0x29, 0x1c, 0x98, 0xd4, 0xf2, 0xf1,
+	0xec, 0x16, 0xba, 0xe3, 0x44, 0xa1, 0x48, 0xd8, 0xc2, 0x26, 0x61, 0x75, 0x82, 0x1f, 0x1d, 0x38,
+	0x36, 0xa9, 0x5d, 0xea, 0xe8, 0xb9, 0xa7, 0x5d, 0x61, 0xd6, 0x87, 0x77, 0x35, 0x0c, 0x23, 0xc1,
+	0x92, 0x70, 0x36, 0x2a, 0x73, 0x72, 0x69, 0x9d, 0x1d, 0xfc, 0xed, 0xc0, 0xc9, 0xeb, 0x69, 0xec,
+	0x70, 0x69, 0x81, 0xbd, 0xb7, 0x9f, 0xa3, 0x62, 0x39, 0x2e, 0xa4, 0xc0, 0xe5, 0xaa, 0x94, 0xd0,
+	0x8a, 0x16, 0x79, 0x62, 0x07, 0xd9, 0x58, 0xd8, 0x76, 0xbc, 0x5f, 0x1d, 0x64, 0xa3, 0x5f, 0x6a,
+	0x90, 0x4f, 0xe0, 0x70, 0xb2, 0xae, 0x67, 0x1c, 0xe5, 0x0b, 0xf9, 0x2e, 0x33, 0x88, 0xa0, 0x3d,
+	0x62, 0xe1, 0x7c, 0x95, 0xee, 0x72, 0x9e, 0x0d, 0xd7, 0x6e, 0x0f, 0xf3, 0x1f, 0xfc, 0xee, 0x00,
+	0x14, 0x61, 0x76, 0x06, 0x21, 0x81, 0xfd, 0x94, 0xa9, 0x59, 0x11, 0x48, 0xff, 0x6b, 0xdd, 0x19,
+	0xc6, 0xd3, 0x99, 0x6d, 0xfa, 0x06, 0xcd, 0x29, 0xf2, 0x18, 0x40, 0xc5, 0xa9, 0x29, 0x3a, 0x2e,
+	0xf6, 0x6f, 0x85, 0x33, 0xfc, 0xa7, 0x01, 0xcd, 0xef, 0x4c, 0x0e, 0xe4, 0x29, 0xc0, 0xe5, 0x0c,
+	0xc3, 0xf9, 0xc5, 0x22, 0x7e, 0x85, 0xe4, 0xbd, 0x75, 0x6a, 0xf9, 0x0b, 0xc4, 0x27, 0x75, 0x96,
+	0x4c, 0x83, 0x77, 0xc8, 0xe7, 0xe0, 0x16, 0xef, 0x05, 0xd2, 0x5d, 0x6b, 0x54, 0xde, 0x10, 0x6f,
+	0x30, 0x7c, 0x06, 0xad, 0xfc, 0x4e, 0x24, 0x15, 0x1c, 0xd6, 0xb7, 0xbd, 0xdf, 0xdd, 0xc0, 0x35,
+	0x96, 0x17, 0x00, 0xeb, 0x8b, 0x81, 0x3c, 0xac, 0x04, 0xad, 0xde, 0x6c, 0xbe, 0xb7, 0x59, 0x50,
+	0x04, 0xcf, 0xf7, 0x6c, 0x35, 0xf8, 0x7a, 0xc5, 0xfb, 0xdd, 0x0d, 0xdc, 0x22, 0xf8, 0x7a, 0x81,
+	0x54, 0x83, 0xdf, 0xd9, 0x74, 0xbe, 0xb7, 0x59, 0x60, 0x5c, 0x5c, 0xc3, 0xfd, 0xfa, 0x84, 0x91,
+	0x0f, 0x6b, 0xfa, 0x77, 0x97, 0x80, 0xff, 0xf8, 0x6d, 0x62, 0xe3, 0xf4, 0x29, 0x34, 0x6d, 0xa7,
+	0x91, 0xe3, 0x2a, 0xdc, 0x79, 0x8b, 0xfb, 0x27, 0xaf, 0x33, 0xb5, 0xd9, 0xa4, 0x69, 0x5e, 0x0c,
+	0x9f, 0xfe, 0x3b, 0x00, 0x42, 0x7e, 0xe0, 0x06, 0x8b, 0x0b, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1004,6 +1148,8 @@ type XchainClient interface {
 	QueryBlock(ctx context.Context, in *QueryBlockReq, opts ...grpc.CallOption) (*QueryBlockResp, error)
 	// 查询区块链状态
 	QueryChainStatus(ctx context.Context, in *QueryChainStatusReq, opts ...grpc.CallOption) (*QueryChainStatusResp, error)
+	// 在线备份链数据
+	Backup(ctx context.Context, in *BackupReq, opts ...grpc.CallOption) (*BackupResp, error)
 }
 
 type xchainClient struct {
@@ -1077,6 +1223,15 @@ func (c *xchainClient) QueryChainStatus(ctx context.Context, in *QueryChainStatu
 	return out, nil
 }
 
+func (c *xchainClient) Backup(ctx context.Context, in *BackupReq, opts ...grpc.CallOption) (*BackupResp, error) {
+	out := new(BackupResp)
+	err := c.cc.Invoke(ctx, "/xchainpb.Xchain/Backup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // XchainServer is the server API for Xchain service.
 type XchainServer interface {
 	// 示例接口
@@ -1093,6 +1248,8 @@ type XchainServer interface {
 	QueryBlock(context.Context, *QueryBlockReq) (*QueryBlockResp, error)
 	// 查询区块链状态
 	QueryChainStatus(context.Context, *QueryChainStatusReq) (*QueryChainStatusResp, error)
+	// 在线备份链数据
+	Backup(context.Context, *BackupReq) (*BackupResp, error)
 }
 
 // UnimplementedXchainServer can be embedded to have forward compatible implementations.
@@ -1120,6 +1277,9 @@ func (*UnimplementedXchainServer) QueryBlock(ctx context.Context, req *QueryBloc
 func (*UnimplementedXchainServer) QueryChainStatus(ctx context.Context, req *QueryChainStatusReq) (*QueryChainStatusResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method QueryChainStatus not implemented")
 }
+func (*UnimplementedXchainServer) Backup(ctx context.Context, req *BackupReq) (*BackupResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Backup not implemented")
+}
 
 func RegisterXchainServer(s *grpc.Server, srv XchainServer) {
 	s.RegisterService(&_Xchain_serviceDesc, srv)
@@ -1251,6 +1411,24 @@ func _Xchain_QueryChainStatus_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Xchain_Backup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackupReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(XchainServer).Backup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/xchainpb.Xchain/Backup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(XchainServer).Backup(ctx, req.(*BackupReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Xchain_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "xchainpb.Xchain",
 	HandlerType: (*XchainServer)(nil),
@@ -1283,6 +1461,10 @@ var _Xchain_serviceDesc = grpc.ServiceDesc{
 			MethodName: "QueryChainStatus",
 			Handler:    _Xchain_QueryChainStatus_Handler,
 		},
+		{
+			MethodName: "Backup",
+			Handler:    _Xchain_Backup_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "xchain.proto",
@@ -2,7 +2,11 @@ package models
 
 import (
 	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/backup"
 	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	sctx "github.com/xuperchain/xupercore/example/xchain/common/context"
 	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
@@ -13,6 +17,9 @@ import (
 	"github.com/xuperchain/xupercore/protos"
 )
 
+// BackupDir 在线备份文件所在目录，相对于节点数据目录
+const BackupDir = "backup"
+
 type ChainHandle struct {
 	bcName string
 	reqCtx sctx.ReqCtx
@@ -66,6 +73,49 @@ func (t *ChainHandle) QueryChainStatus(needBranch bool) (*xpb.ChainStatus, error
 	return reader.NewChainReader(t.chain.Context(), t.genXctx()).GetChainStatus()
 }
 
+// Backup 在线备份链数据，备份文件只能写入节点数据目录下的backup目录
+func (t *ChainHandle) Backup(name string) (string, *backup.Manifest, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", nil, ecom.ErrParameter
+	}
+
+	ctx := t.chain.Context()
+	dir := ctx.EngCtx.EnvCfg.GenDataAbsPath(BackupDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.log.Warn("create backup dir failed", "dir", dir, "err", err)
+		return "", nil, ecom.ErrInternal
+	}
+
+	// 先写临时文件，完成后再改名，避免留下不完整的备份文件
+	path := filepath.Join(dir, name)
+	tmpPath := path + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		t.log.Warn("create backup file failed", "path", tmpPath, "err", err)
+		return "", nil, ecom.ErrForbidden
+	}
+	manifest, err := backup.Backup(t.bcName, ctx.Ledger, ctx.State, f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		t.log.Warn("backup chain failed", "bcName", t.bcName, "err", err)
+		return "", nil, ecom.ErrInternal
+	}
+	if _, err := os.Stat(path); err == nil {
+		os.Remove(tmpPath)
+		return "", nil, ecom.ErrForbidden
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		t.log.Warn("rename backup file failed", "path", path, "err", err)
+		return "", nil, ecom.ErrInternal
+	}
+
+	return path, manifest, nil
+}
+
 func (t *ChainHandle) genXctx() xctx.XContext {
 	return &xctx.BaseCtx{
 		XLog:  t.reqCtx.GetLog(),
@@ -76,7 +76,8 @@ func (t *RpcServ) PreExec(gctx context.Context, req *pb.PreExecReq) (*pb.PreExec
 	rctx.GetLog().SetInfoField("bc_name", req.GetBcname())
 	rctx.GetLog().SetInfoField("initiator", req.GetInitiator())
 	// 设置响应
-	if err == nil {
+	// 开启trace时预执行失败也会返回执行轨迹
+	if err == nil || res != nil {
 		resp.Bcname = req.GetBcname()
 		resp.Response = res
 	}
@@ -145,6 +146,7 @@ func (t *RpcServ) QueryTx(gctx context.Context, req *pb.QueryTxReq) (*pb.QueryTx
 		resp.Status = res.GetStatus()
 		resp.Distance = res.GetDistance()
 		resp.Tx = res.GetTx()
+		resp.Redacted = res.GetRedacted()
 	}
 
 	return resp, err
@@ -212,3 +214,34 @@ func (t *RpcServ) QueryChainStatus(gctx context.Context,
 
 	return resp, err
 }
+
+// 在线备份链数据
+func (t *RpcServ) Backup(gctx context.Context, req *pb.BackupReq) (*pb.BackupResp, error) {
+	// 默认响应
+	resp := &pb.BackupResp{}
+	// 获取请求上下文，对内传递rctx
+	rctx := sctx.ValueReqCtx(gctx)
+
+	// 校验参数
+	if req == nil || req.GetBcname() == "" || req.GetName() == "" {
+		return resp, ecom.ErrParameter
+	}
+
+	handle, err := models.NewChainHandle(req.GetBcname(), rctx)
+	if err != nil {
+		rctx.GetLog().Warn("new chain handle failed", "err", err.Error())
+		return resp, err
+	}
+	path, manifest, err := handle.Backup(req.GetName())
+	rctx.GetLog().SetInfoField("bc_name", req.GetBcname())
+	rctx.GetLog().SetInfoField("name", req.GetName())
+	// 设置响应
+	if err == nil {
+		resp.Bcname = req.GetBcname()
+		resp.Path = path
+		resp.Height = manifest.Height
+		resp.TipBlockid = manifest.TipBlockid
+	}
+
+	return resp, err
+}
@@ -316,6 +316,10 @@ func (c *FakeKContext) Select(bucket string, startKey []byte, endKey []byte) (co
 	return nil, nil
 }
 
+func (c *FakeKContext) SelectRange(bucket string, startKey []byte, endKey []byte, reverse bool) (contract.Iterator, error) {
+	return nil, nil
+}
+
 func (c *FakeKContext) Put(bucket string, key, value []byte) error {
 	if _, ok := c.m[bucket]; !ok {
 		a := make(map[string][]byte)
@@ -351,6 +355,21 @@ func (c *FakeKContext) UTXORWSet() *contract.UTXORWSet {
 		WSet: []*protos.TxOutput{},
 	}
 }
+func (c *FakeKContext) Checkpoint() int {
+	return 0
+}
+func (c *FakeKContext) RevertToCheckpoint(cp int) error {
+	return nil
+}
+func (c *FakeKContext) ReleaseCheckpoint(cp int) {}
+
+func (c *FakeKContext) ContractVersion() bool {
+	return false
+}
+
+func (c *FakeKContext) ContractCallRevert() bool {
+	return false
+}
 func (c *FakeKContext) Transfer(from string, to string, amount *big.Int) error {
 	return nil
 }
@@ -423,6 +442,10 @@ func (r *FakeXMReader) Select(bucket string, startKey []byte, endKey []byte) (le
 	return nil, nil
 }
 
+func (r *FakeXMReader) SelectRange(bucket string, startKey []byte, endKey []byte, reverse bool) (ledger.XMIterator, error) {
+	return nil, nil
+}
+
 func NewXContent() *xctx.BaseCtx {
 	return &xctx.BaseCtx{}
 }
@@ -0,0 +1,297 @@
+// Package abi 定义wasm和native合约的类型化ABI
+// 合约部署时可以附带ABI，客户端使用json格式的参数调用合约，预执行时按ABI校验并编码为合约使用的
+// map[string][]byte，交易中保存编码后的参数；返回值和事件的body按ABI解码为json。
+// evm合约使用solidity ABI，不使用本包。
+package abi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+const (
+	// 与evm合约一致，jsonEncoded为true时input为json格式的参数
+	ArgJSONEncoded = "jsonEncoded"
+	ArgInput       = "input"
+)
+
+// 参数、返回值和事件body支持的类型
+const (
+	TypeString = "string"
+	TypeBytes  = "bytes"
+	TypeInt64  = "int64"
+	TypeUint64 = "uint64"
+	TypeBigInt = "bigint"
+	TypeBool   = "bool"
+	TypeJSON   = "json"
+)
+
+var (
+	ErrInvalidABI     = errors.New("invalid contract abi")
+	ErrMethodNotFound = errors.New("method not found in contract abi")
+	ErrEventNotFound  = errors.New("event not found in contract abi")
+	ErrInvalidArgs    = errors.New("invalid contract args")
+)
+
+// Param 方法参数
+type Param struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// Method 合约方法，Returns为返回值的类型，为空时不解码返回值
+type Method struct {
+	Name    string   `json:"name"`
+	Inputs  []*Param `json:"inputs"`
+	Returns string   `json:"returns,omitempty"`
+}
+
+// Event 合约事件，Body为事件body的类型
+type Event struct {
+	Name string `json:"name"`
+	Body string `json:"body"`
+}
+
+// ABI 合约的方法和事件定义
+type ABI struct {
+	Methods []*Method `json:"methods"`
+	Events  []*Event  `json:"events,omitempty"`
+
+	methods map[string]*Method
+	events  map[string]*Event
+}
+
+// Parse 解析并校验json格式的ABI
+func Parse(data []byte) (*ABI, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	a := new(ABI)
+	if err := dec.Decode(a); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidABI, err)
+	}
+
+	a.methods = make(map[string]*Method, len(a.Methods))
+	for _, method := range a.Methods {
+		if method == nil || method.Name == "" {
+			return nil, fmt.Errorf("%w: empty method name", ErrInvalidABI)
+		}
+		if _, ok := a.methods[method.Name]; ok {
+			return nil, fmt.Errorf("%w: duplicated method %s", ErrInvalidABI, method.Name)
+		}
+		names := make(map[string]bool, len(method.Inputs))
+		for _, param := range method.Inputs {
+			if param == nil || param.Name == "" {
+				return nil, fmt.Errorf("%w: empty param name of method %s", ErrInvalidABI, method.Name)
+			}
+			if names[param.Name] {
+				return nil, fmt.Errorf("%w: duplicated param %s of method %s", ErrInvalidABI, param.Name, method.Name)
+			}
+			names[param.Name] = true
+			if !validType(param.Type) {
+				return nil, fmt.Errorf("%w: unknown type %s of param %s", ErrInvalidABI, param.Type, param.Name)
+			}
+		}
+		if method.Returns != "" && !validType(method.Returns) {
+			return nil, fmt.Errorf("%w: unknown returns type %s of method %s", ErrInvalidABI, method.Returns, method.Name)
+		}
+		a.methods[method.Name] = method
+	}
+
+	a.events = make(map[string]*Event, len(a.Events))
+	for _, event := range a.Events {
+		if event == nil || event.Name == "" {
+			return nil, fmt.Errorf("%w: empty event name", ErrInvalidABI)
+		}
+		if _, ok := a.events[event.Name]; ok {
+			return nil, fmt.Errorf("%w: duplicated event %s", ErrInvalidABI, event.Name)
+		}
+		if !validType(event.Body) {
+			return nil, fmt.Errorf("%w: unknown body type %s of event %s", ErrInvalidABI, event.Body, event.Name)
+		}
+		a.events[event.Name] = event
+	}
+	return a, nil
+}
+
+// EncodeArgs 按方法定义校验json对象格式的参数，并编码为合约参数
+func (a *ABI) EncodeArgs(method string, input []byte) (map[string][]byte, error) {
+	m, ok := a.methods[method]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMethodNotFound, method)
+	}
+	values := make(map[string]json.RawMessage)
+	if len(bytes.TrimSpace(input)) != 0 {
+		if err := json.Unmarshal(input, &values); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+		}
+	}
+
+	args := make(map[string][]byte, len(m.Inputs))
+	for _, param := range m.Inputs {
+		raw, ok := values[param.Name]
+		delete(values, param.Name)
+		if !ok || string(raw) == "null" {
+			if param.Optional {
+				continue
+			}
+			return nil, fmt.Errorf("%w: missing param %s", ErrInvalidArgs, param.Name)
+		}
+		value, err := encodeValue(param.Type, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: param %s: %v", ErrInvalidArgs, param.Name, err)
+		}
+		args[param.Name] = value
+	}
+	for name := range values {
+		return nil, fmt.Errorf("%w: unknown param %s", ErrInvalidArgs, name)
+	}
+	return args, nil
+}
+
+// DecodeResponse 按方法的返回值类型将返回值解码为json，未定义返回值类型时原样返回
+func (a *ABI) DecodeResponse(method string, body []byte) ([]byte, error) {
+	m, ok := a.methods[method]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMethodNotFound, method)
+	}
+	if m.Returns == "" {
+		return body, nil
+	}
+	return decodeValue(m.Returns, body)
+}
+
+// DecodeEvent 按事件定义将事件body解码为json
+func (a *ABI) DecodeEvent(name string, body []byte) ([]byte, error) {
+	e, ok := a.events[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrEventNotFound, name)
+	}
+	return decodeValue(e.Body, body)
+}
+
+func validType(typ string) bool {
+	switch typ {
+	case TypeString, TypeBytes, TypeInt64, TypeUint64, TypeBigInt, TypeBool, TypeJSON:
+		return true
+	}
+	return false
+}
+
+// numberText 整数可以使用json数字或者字符串
+func numberText(raw json.RawMessage) (string, error) {
+	if len(raw) > 0 && raw[0] == '"' {
+		var s string
+		err := json.Unmarshal(raw, &s)
+		return s, err
+	}
+	return string(raw), nil
+}
+
+// encodeValue 将json值编码为合约参数，数字和布尔值使用十进制字符串
+func encodeValue(typ string, raw json.RawMessage) ([]byte, error) {
+	switch typ {
+	case TypeString:
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+		return []byte(s), nil
+	case TypeBytes:
+		var b []byte
+		if err := json.Unmarshal(raw, &b); err != nil {
+			return nil, err
+		}
+		return b, nil
+	case TypeInt64:
+		s, err := numberText(raw)
+		if err != nil {
+			return nil, err
+		}
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(strconv.FormatInt(n, 10)), nil
+	case TypeUint64:
+		s, err := numberText(raw)
+		if err != nil {
+			return nil, err
+		}
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(strconv.FormatUint(n, 10)), nil
+	case TypeBigInt:
+		s, err := numberText(raw)
+		if err != nil {
+			return nil, err
+		}
+		n, ok := new(big.Int).SetString(s, 10)
+		if !ok {
+			return nil, fmt.Errorf("bad bigint %s", s)
+		}
+		return []byte(n.String()), nil
+	case TypeBool:
+		var b bool
+		if err := json.Unmarshal(raw, &b); err != nil {
+			return nil, err
+		}
+		return []byte(strconv.FormatBool(b)), nil
+	case TypeJSON:
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, raw); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unknown type %s", typ)
+}
+
+// decodeValue 将合约返回的字节按类型解码为json
+func decodeValue(typ string, data []byte) ([]byte, error) {
+	var value interface{}
+	switch typ {
+	case TypeString:
+		value = string(data)
+	case TypeBytes:
+		value = data
+	case TypeInt64:
+		n, err := strconv.ParseInt(string(data), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		value = n
+	case TypeUint64:
+		n, err := strconv.ParseUint(string(data), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		value = n
+	case TypeBigInt:
+		n, ok := new(big.Int).SetString(string(data), 10)
+		if !ok {
+			return nil, fmt.Errorf("bad bigint %s", data)
+		}
+		value = n
+	case TypeBool:
+		b, err := strconv.ParseBool(string(data))
+		if err != nil {
+			return nil, err
+		}
+		value = b
+	case TypeJSON:
+		if !json.Valid(data) {
+			return nil, errors.New("bad json value")
+		}
+		value = json.RawMessage(data)
+	default:
+		return nil, fmt.Errorf("unknown type %s", typ)
+	}
+	return json.Marshal(value)
+}
@@ -0,0 +1,139 @@
+package abi
+
+import (
+	"errors"
+	"testing"
+)
+
+var counterABI = []byte(`{
+    "methods": [
+        {
+            "name": "increase",
+            "inputs": [
+                {"name": "key", "type": "string"},
+                {"name": "delta", "type": "uint64", "optional": true}
+            ],
+            "returns": "uint64"
+        },
+        {
+            "name": "transfer",
+            "inputs": [
+                {"name": "to", "type": "bytes"},
+                {"name": "amount", "type": "bigint"},
+                {"name": "memo", "type": "json", "optional": true},
+                {"name": "force", "type": "bool", "optional": true}
+            ],
+            "returns": "json"
+        }
+    ],
+    "events": [
+        {"name": "increase", "body": "int64"}
+    ]
+}`)
+
+func TestParse(t *testing.T) {
+	if _, err := Parse(counterABI); err != nil {
+		t.Fatal(err)
+	}
+	cases := []string{
+		`not json`,
+		`{"methods": [{"name": "", "inputs": []}]}`,
+		`{"methods": [{"name": "a", "inputs": []}, {"name": "a", "inputs": []}]}`,
+		`{"methods": [{"name": "a", "inputs": [{"name": "x", "type": "float"}]}]}`,
+		`{"methods": [{"name": "a", "inputs": [{"name": "x", "type": "string"}, {"name": "x", "type": "bool"}]}]}`,
+		`{"methods": [{"name": "a", "inputs": [], "returns": "map"}]}`,
+		`{"methods": [], "events": [{"name": "e", "body": ""}]}`,
+		`{"methods": [], "unknown": 1}`,
+	}
+	for _, c := range cases {
+		if _, err := Parse([]byte(c)); !errors.Is(err, ErrInvalidABI) {
+			t.Fatalf("expect invalid abi for %s, got %v", c, err)
+		}
+	}
+}
+
+func TestEncodeArgs(t *testing.T) {
+	a, err := Parse(counterABI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args, err := a.EncodeArgs("increase", []byte(`{"key": "k1", "delta": 10}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(args["key"]) != "k1" || string(args["delta"]) != "10" {
+		t.Fatalf("unexpected args %v", args)
+	}
+	args, err = a.EncodeArgs("increase", []byte(`{"key": "k1", "delta": null}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := args["delta"]; ok || len(args) != 1 {
+		t.Fatalf("optional param should be omitted, got %v", args)
+	}
+
+	args, err = a.EncodeArgs("transfer", []byte(`{"to": "AQI=", "amount": "100000000000000000000",
+		"memo": {"a": [1, 2]}, "force": true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(args["to"]) != "\x01\x02" || string(args["amount"]) != "100000000000000000000" ||
+		string(args["memo"]) != `{"a":[1,2]}` || string(args["force"]) != "true" {
+		t.Fatalf("unexpected args %v", args)
+	}
+
+	invalid := map[string]string{
+		"increase": `{"delta": 1}`,
+		"transfer": `{"to": "AQI=", "amount": "1.5"}`,
+	}
+	for method, input := range invalid {
+		if _, err := a.EncodeArgs(method, []byte(input)); !errors.Is(err, ErrInvalidArgs) {
+			t.Fatalf("expect invalid args for %s, got %v", input, err)
+		}
+	}
+	for _, input := range []string{`{"key": 1}`, `{"key": "k", "delta": -1}`, `{"key": "k", "other": 1}`, `[]`} {
+		if _, err := a.EncodeArgs("increase", []byte(input)); !errors.Is(err, ErrInvalidArgs) {
+			t.Fatalf("expect invalid args for %s, got %v", input, err)
+		}
+	}
+	if _, err := a.EncodeArgs("decrease", nil); !errors.Is(err, ErrMethodNotFound) {
+		t.Fatalf("expect method not found, got %v", err)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	a, err := Parse(counterABI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := a.DecodeResponse("increase", []byte("18446744073709551615"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "18446744073709551615" {
+		t.Fatalf("unexpected response %s", out)
+	}
+	if _, err := a.DecodeResponse("increase", []byte("abc")); err == nil {
+		t.Fatal("expect decode error")
+	}
+	out, err = a.DecodeResponse("transfer", []byte(`{"ok":true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"ok":true}` {
+		t.Fatalf("unexpected response %s", out)
+	}
+
+	out, err = a.DecodeEvent("increase", []byte("-3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "-3" {
+		t.Fatalf("unexpected event %s", out)
+	}
+	if _, err := a.DecodeEvent("transfer", nil); !errors.Is(err, ErrEventNotFound) {
+		t.Fatalf("expect event not found, got %v", err)
+	}
+}
@@ -65,7 +65,7 @@ func (c *codeProvider) GetContractCode(name string) ([]byte, error) {
 }
 
 func (c *codeProvider) GetContractAbi(name string) ([]byte, error) {
-	value, err := c.xstore.Get("contract", contractAbiKey(name))
+	value, err := c.xstore.Get("contract", ContractAbiKey(name))
 	if err != nil {
 		return nil, fmt.Errorf("get contract abi for '%s' error:%s", name, err)
 	}
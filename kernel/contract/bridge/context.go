@@ -53,6 +53,9 @@ type Context struct {
 
 	// Write by contract
 	Output *pb.Response
+
+	// 非空时记录合约调用轨迹
+	Tracer *contract.Tracer
 }
 
 // DiskUsed returns the bytes written to xmodel
@@ -31,6 +31,16 @@ type vmContextImpl struct {
 }
 
 func (v *vmContextImpl) Invoke(method string, args map[string][]byte) (*contract.Response, error) {
+	if !v.ctx.Tracer.Enabled() {
+		return v.invoke(method, args)
+	}
+	v.ctx.Tracer.Enter(v.ctx.Module, v.ctx.ContractName, method, args)
+	resp, err := v.invoke(method, args)
+	v.ctx.Tracer.Exit(resp, v.ctx.ResourceUsed(), err)
+	return resp, err
+}
+
+func (v *vmContextImpl) invoke(method string, args map[string][]byte) (*contract.Response, error) {
 	if !v.ctx.CanInitialize && method == initMethod {
 		return nil, errors.New("invalid contract method " + method)
 	}
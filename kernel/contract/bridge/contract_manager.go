@@ -4,14 +4,20 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/xuperchain/crypto/core/hash"
 	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/abi"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
 	"github.com/xuperchain/xupercore/protos"
 
 	"github.com/golang/protobuf/proto"
 )
 
+// contractCodeHistoryLimit 升级时保留的旧版本代码数量，保存旧代码写入的字节按磁盘资源计入升级交易
+const contractCodeHistoryLimit = 5
+
 type contractManager struct {
 	xbridge      *XBridge
 	codeProvider ContractCodeProvider
@@ -64,7 +70,23 @@ func (c *contractManager) DeployContract(kctx contract.KContext) (*contract.Resp
 
 	if desc.ContractType == string(TypeEvm) {
 		abiBuf := args["contract_abi"]
-		if err := state.Put("contract", contractAbiKey(contractName), abiBuf); err != nil {
+		if err := state.Put("contract", ContractAbiKey(contractName), abiBuf); err != nil {
+			return nil, contract.Limits{}, err
+		}
+	} else if abiBuf := args["contract_abi"]; len(abiBuf) != 0 && state.ContractVersion() {
+		// 其它合约的ABI是可选的，开启合约版本历史前忽略
+		if _, err := abi.Parse(abiBuf); err != nil {
+			return nil, contract.Limits{}, err
+		}
+		if err := state.Put("contract", ContractAbiKey(contractName), abiBuf); err != nil {
+			return nil, contract.Limits{}, err
+		}
+	}
+	if state.ContractVersion() {
+		if err := putContractVersion(state, contractName, &protos.ContractVersion{
+			Version: 1,
+			Digest:  desc.Digest,
+		}); err != nil {
 			return nil, contract.Limits{}, err
 		}
 	}
@@ -127,7 +149,8 @@ func (v *contractManager) initContract(tp ContractType, contextConfig *contract.
 	return out, ctx.ResourceUsed(), nil
 }
 
-// UpgradeContract deploy contract and initialize contract
+// UpgradeContract upgrade contract code and abi, invoke migrate method if migrate_args is given
+// 未开启合约版本历史时保持原有的升级行为，只替换代码和描述，忽略abi和migrate_args
 func (c *contractManager) UpgradeContract(kctx contract.KContext) (*contract.Response, contract.Limits, error) {
 	args := kctx.Args()
 	if !c.xbridge.config.EnableUpgrade {
@@ -148,12 +171,38 @@ func (c *contractManager) UpgradeContract(kctx contract.KContext) (*contract.Res
 	if code == nil {
 		return nil, contract.Limits{}, errors.New("missing contract code")
 	}
+	store := kctx
+	versioned := store.ContractVersion()
+	var migrateArgs map[string][]byte
+	var version int64
+	if versioned {
+		if migrateArgsBuf := args["migrate_args"]; migrateArgsBuf != nil {
+			if err := json.Unmarshal(migrateArgsBuf, &migrateArgs); err != nil {
+				return nil, contract.Limits{}, err
+			}
+		}
+		version, err = getContractVersion(store, contractName)
+		if err != nil {
+			return nil, contract.Limits{}, err
+		}
+		if err := archiveContractCode(store, contractName, version); err != nil {
+			return nil, contract.Limits{}, err
+		}
+	}
+
 	desc.Digest = hash.DoubleSha256(code)
 	descbuf, _ := proto.Marshal(desc)
-
-	store := kctx
-	store.Put("contract", ContractCodeDescKey(contractName), descbuf)
-	store.Put("contract", contractCodeKey(contractName), code)
+	if err := store.Put("contract", ContractCodeDescKey(contractName), descbuf); err != nil {
+		return nil, contract.Limits{}, err
+	}
+	if err := store.Put("contract", contractCodeKey(contractName), code); err != nil {
+		return nil, contract.Limits{}, err
+	}
+	if versioned {
+		if err := updateContractAbi(store, desc, contractName, args["contract_abi"]); err != nil {
+			return nil, contract.Limits{}, err
+		}
+	}
 
 	cp := newCodeProvider(store)
 
@@ -175,12 +224,113 @@ func (c *contractManager) UpgradeContract(kctx contract.KContext) (*contract.Res
 	}
 	instance.Release()
 
+	// 在升级交易中执行新代码的migrate方法，迁移失败时升级失败
+	var resourceUsed contract.Limits
+	if migrateArgs != nil {
+		migrateConfig := &contract.ContextConfig{
+			ResourceLimits:        kctx.ResourceLimit(),
+			State:                 kctx,
+			Initiator:             kctx.Initiator(),
+			AuthRequire:           kctx.AuthRequire(),
+			ContractName:          contractName,
+			ContractCodeFromCache: true,
+		}
+		var out *contract.Response
+		out, resourceUsed, err = c.invokeContract(migrateConfig, "migrate", migrateArgs)
+		if err != nil {
+			return nil, contract.Limits{}, err
+		}
+		if out.Status >= contract.StatusErrorThreshold {
+			return nil, contract.Limits{}, fmt.Errorf("migrate contract %s failed: %s", contractName, out.Message)
+		}
+	}
+
+	if versioned {
+		err = putContractVersion(store, contractName, &protos.ContractVersion{
+			Version:  version + 1,
+			Digest:   desc.Digest,
+			Migrated: migrateArgs != nil,
+		})
+		if err != nil {
+			return nil, contract.Limits{}, err
+		}
+	}
+	resourceUsed.Disk = modelCacheDiskUsed(store)
+
 	return &contract.Response{
-			Status: 200,
-			Body:   []byte("upgrade success"),
-		}, contract.Limits{
-			Disk: modelCacheDiskUsed(store),
-		}, nil
+		Status: 200,
+		Body:   []byte("upgrade success"),
+	}, resourceUsed, nil
+}
+
+func (v *contractManager) invokeContract(contextConfig *contract.ContextConfig, method string, args map[string][]byte) (*contract.Response, contract.Limits, error) {
+	ctx, err := v.xbridge.NewContext(contextConfig)
+	if err != nil {
+		return nil, contract.Limits{}, err
+	}
+	defer ctx.Release()
+	out, err := ctx.Invoke(method, args)
+	if err != nil {
+		return nil, contract.Limits{}, err
+	}
+	return out, ctx.ResourceUsed(), nil
+}
+
+// updateContractAbi 升级时更新合约的ABI，evm合约必须提供ABI，其它合约未提供时保留原有的ABI
+func updateContractAbi(store contract.XMState, desc *protos.WasmCodeDesc, contractName string, abiBuf []byte) error {
+	if desc.ContractType == string(TypeEvm) {
+		if len(abiBuf) == 0 {
+			return errors.New("missing contract abi")
+		}
+		return store.Put("contract", ContractAbiKey(contractName), abiBuf)
+	}
+	if len(abiBuf) == 0 {
+		return nil
+	}
+	if _, err := abi.Parse(abiBuf); err != nil {
+		return err
+	}
+	return store.Put("contract", ContractAbiKey(contractName), abiBuf)
+}
+
+// archiveContractCode 保留被替换版本的代码，只保留最近contractCodeHistoryLimit个旧版本，
+// 更早版本的代码被删除，版本记录中的摘要仍然保留
+func archiveContractCode(store contract.XMState, contractName string, version int64) error {
+	oldCode, err := store.Get("contract", contractCodeKey(contractName))
+	if err != nil {
+		return fmt.Errorf("get contract code for '%s' error:%s", contractName, err)
+	}
+	if err := store.Put("contract", contractHistoryCodeKey(contractName, version), oldCode); err != nil {
+		return err
+	}
+	if expired := version - contractCodeHistoryLimit; expired > 0 {
+		return store.Del("contract", contractHistoryCodeKey(contractName, expired))
+	}
+	return nil
+}
+
+// getContractVersion 返回合约的当前版本，没有版本记录的合约(包括开启版本历史前部署的合约)为1
+func getContractVersion(store contract.XMState, contractName string) (int64, error) {
+	value, err := store.Get("contract", contractVersionKey(contractName))
+	if err == sandbox.ErrNotFound || err == sandbox.ErrHasDel {
+		return 1, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(value), 10, 64)
+}
+
+// putContractVersion 记录合约的当前版本和版本历史
+func putContractVersion(store contract.XMState, contractName string, version *protos.ContractVersion) error {
+	buf, err := proto.Marshal(version)
+	if err != nil {
+		return err
+	}
+	if err := store.Put("contract", ContractHistoryKey(contractName, version.Version), buf); err != nil {
+		return err
+	}
+	return store.Put("contract", contractVersionKey(contractName), []byte(strconv.FormatInt(version.Version, 10)))
 }
 
 func modelCacheDiskUsed(store contract.KContext) int64 {
@@ -201,10 +351,24 @@ func contractCodeKey(contractName string) []byte {
 	return []byte(contractName + "." + "code")
 }
 
-func contractAbiKey(contractName string) []byte {
+// ContractAbiKey 合约ABI的key，evm合约为solidity ABI，其它合约为类型化ABI
+func ContractAbiKey(contractName string) []byte {
 	return []byte(contractName + "." + "abi")
 }
 
+func contractVersionKey(contractName string) []byte {
+	return []byte(contractName + "." + "version")
+}
+
+// ContractHistoryKey 合约版本记录的key，按版本号排序
+func ContractHistoryKey(contractName string, version int64) []byte {
+	return []byte(fmt.Sprintf("%s.history.%020d", contractName, version))
+}
+
+func contractHistoryCodeKey(contractName string, version int64) []byte {
+	return []byte(fmt.Sprintf("%s.code.%020d", contractName, version))
+}
+
 func getContractType(desc *protos.WasmCodeDesc) (ContractType, error) {
 	switch desc.ContractType {
 	case "", "wasm":
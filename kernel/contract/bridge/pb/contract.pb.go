@@ -566,7 +566,13 @@ type IteratorRequest struct {
 	Start []byte `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
 	Limit []byte `protobuf:"bytes,3,opt,name=limit,proto3" json:"limit,omitempty"`
 	// cap代表一次请求的最大IteratorItem个数，如果为0则采用xchain的默认配置
-	Cap                  int32    `protobuf:"varint,4,opt,name=cap,proto3" json:"cap,omitempty"`
+	Cap int32 `protobuf:"varint,4,opt,name=cap,proto3" json:"cap,omitempty"`
+	// prefix不为空时扫描所有以prefix开头的key，不能和start、limit同时使用
+	Prefix []byte `protobuf:"bytes,5,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// reverse为true时按照key从大到小的顺序返回
+	Reverse bool `protobuf:"varint,6,opt,name=reverse,proto3" json:"reverse,omitempty"`
+	// cursor为上一次请求返回的next_cursor，从上一批结束的位置继续扫描，其余参数需要和上一次请求一致
+	Cursor               []byte   `protobuf:"bytes,7,opt,name=cursor,proto3" json:"cursor,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -625,6 +631,27 @@ func (m *IteratorRequest) GetCap() int32 {
 	return 0
 }
 
+func (m *IteratorRequest) GetPrefix() []byte {
+	if m != nil {
+		return m.Prefix
+	}
+	return nil
+}
+
+func (m *IteratorRequest) GetReverse() bool {
+	if m != nil {
+		return m.Reverse
+	}
+	return false
+}
+
+func (m *IteratorRequest) GetCursor() []byte {
+	if m != nil {
+		return m.Cursor
+	}
+	return nil
+}
+
 type IteratorItem struct {
 	Key                  []byte   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 	Value                []byte   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
@@ -673,10 +700,12 @@ func (m *IteratorItem) GetValue() []byte {
 }
 
 type IteratorResponse struct {
-	Items                []*IteratorItem `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+	Items []*IteratorItem `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	// 还有剩余数据时不为空，用于请求下一批数据
+	NextCursor           []byte   `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *IteratorResponse) Reset()         { *m = IteratorResponse{} }
@@ -711,6 +740,13 @@ func (m *IteratorResponse) GetItems() []*IteratorItem {
 	return nil
 }
 
+func (m *IteratorResponse) GetNextCursor() []byte {
+	if m != nil {
+		return m.NextCursor
+	}
+	return nil
+}
+
 type QueryTxRequest struct {
 	Header               *SyscallHeader `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
 	Txid                 string         `protobuf:"bytes,2,opt,name=txid,proto3" json:"txid,omitempty"`
@@ -2023,84 +2059,86 @@ func init() {
 func init() { proto.RegisterFile("contract.proto", fileDescriptor_d19debeba7dea55a) }
 
 var fileDescriptor_d19debeba7dea55a = []byte{
-	// 1250 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x57, 0xef, 0x6e, 0xdb, 0x46,
-	0x0c, 0x87, 0xfc, 0x27, 0xb6, 0x69, 0xc7, 0x49, 0x94, 0xa0, 0x53, 0xd2, 0x16, 0x70, 0x55, 0x0c,
-	0xcd, 0xbe, 0x38, 0x5d, 0x07, 0x6c, 0xe8, 0xb6, 0x2f, 0x69, 0xd6, 0x35, 0xc5, 0x86, 0x35, 0x55,
-	0x0d, 0x0c, 0x2b, 0x30, 0xb8, 0x67, 0xe9, 0x6c, 0x1f, 0x62, 0xe9, 0xd4, 0x3b, 0x2a, 0x50, 0xf6,
-	0x06, 0xc5, 0x1e, 0x61, 0xd8, 0xa7, 0xbd, 0xc7, 0x5e, 0x61, 0xaf, 0x34, 0xdc, 0x1f, 0xc9, 0x0a,
-	0xea, 0x74, 0x7f, 0xea, 0x7e, 0x23, 0x29, 0x1e, 0xf9, 0x23, 0x8f, 0xe4, 0x51, 0xd0, 0x0f, 0x79,
-	0x82, 0x82, 0x84, 0x38, 0x4c, 0x05, 0x47, 0xee, 0xee, 0xe6, 0xe1, 0x9c, 0xb0, 0x64, 0x58, 0x8a,
-	0x65, 0x74, 0xee, 0x6f, 0x42, 0xf7, 0x8c, 0x25, 0xb3, 0x80, 0xbe, 0xce, 0xa8, 0x44, 0xbf, 0x0f,
-	0x3d, 0xc3, 0xca, 0x94, 0x27, 0x92, 0xfa, 0x9f, 0xc0, 0xce, 0x0f, 0x04, 0xd9, 0x05, 0x3d, 0x21,
-	0x8b, 0x85, 0x55, 0x72, 0xf7, 0xa0, 0x19, 0x62, 0xce, 0x22, 0xcf, 0x19, 0x38, 0x87, 0xf5, 0xc0,
-	0x30, 0xfe, 0x1e, 0xb8, 0x55, 0x55, 0x6b, 0xe0, 0x53, 0x68, 0x1d, 0x8b, 0xd9, 0x19, 0x61, 0xc2,
-	0xdd, 0x86, 0xfa, 0x39, 0xbd, 0xd4, 0x87, 0x3a, 0x81, 0x22, 0x95, 0xa1, 0x0b, 0xb2, 0xc8, 0xa8,
-	0x57, 0x1b, 0x38, 0x87, 0xbd, 0xc0, 0x30, 0xfe, 0x9f, 0x0e, 0xb4, 0x95, 0x8d, 0x63, 0x31, 0x93,
-	0xee, 0x0d, 0xd8, 0x88, 0x29, 0xce, 0x79, 0x64, 0xcf, 0x59, 0xce, 0xbd, 0x0f, 0x0d, 0x22, 0x66,
-	0xd2, 0xab, 0x0d, 0xea, 0x87, 0xdd, 0x07, 0xb7, 0x86, 0x2b, 0x62, 0x1b, 0x5a, 0xc7, 0x81, 0xd6,
-	0x74, 0x6f, 0x41, 0x87, 0x25, 0x0c, 0x19, 0x41, 0x2e, 0xbc, 0xba, 0x36, 0xb6, 0x14, 0xb8, 0x77,
-	0xa0, 0x47, 0x32, 0x9c, 0x8f, 0x05, 0x7d, 0x9d, 0x31, 0x41, 0xbd, 0xc6, 0xa0, 0x7e, 0xd8, 0x09,
-	0xba, 0x4a, 0x16, 0x18, 0x91, 0x7b, 0x0f, 0xb6, 0x50, 0x90, 0x44, 0x4e, 0xa9, 0x18, 0x93, 0x98,
-	0x67, 0x09, 0x7a, 0x4d, 0x6d, 0xa6, 0x5f, 0x88, 0x8f, 0xb5, 0xd4, 0xff, 0x18, 0x36, 0x5f, 0x5c,
-	0xca, 0x90, 0x2c, 0x16, 0xa7, 0x94, 0x44, 0x54, 0x5c, 0x93, 0xb0, 0x14, 0xe0, 0x2c, 0xc3, 0x22,
-	0xa9, 0x5f, 0xc2, 0xc6, 0x5c, 0x6b, 0x6b, 0xa5, 0xee, 0x03, 0x7f, 0x65, 0x48, 0x57, 0xec, 0x06,
-	0xf6, 0x44, 0x91, 0x59, 0x93, 0xc5, 0xab, 0x99, 0xad, 0x57, 0x33, 0xab, 0x2e, 0x5b, 0x79, 0xb4,
-	0x77, 0xf3, 0x12, 0xe0, 0x09, 0xfd, 0x30, 0x00, 0xfc, 0xbb, 0xd0, 0xd5, 0xb6, 0x8d, 0xab, 0x25,
-	0x1e, 0xa7, 0x8a, 0xe7, 0x67, 0xd8, 0xfc, 0x86, 0x2e, 0x28, 0xd2, 0x0f, 0x83, 0x61, 0x1b, 0xfa,
-	0x85, 0x79, 0x1b, 0xf1, 0xaf, 0x0e, 0x6c, 0x3d, 0x45, 0x2a, 0xd4, 0x95, 0xaf, 0xc3, 0xe7, 0x1e,
-	0x34, 0x25, 0x12, 0x81, 0x45, 0x01, 0x6b, 0x46, 0x49, 0x17, 0x2c, 0x66, 0x58, 0x24, 0x5f, 0x33,
-	0x0a, 0x5f, 0x48, 0x52, 0xaf, 0x31, 0x70, 0x0e, 0x9b, 0x81, 0x22, 0xfd, 0xcf, 0xa1, 0x57, 0x80,
-	0x79, 0x8a, 0x34, 0xae, 0x36, 0x48, 0xef, 0x5d, 0x0d, 0xf2, 0x1d, 0x6c, 0x2f, 0x83, 0xb0, 0x09,
-	0xfe, 0x02, 0x9a, 0x0c, 0x69, 0x2c, 0x3d, 0x47, 0x37, 0xc4, 0x9d, 0x95, 0x41, 0x54, 0xbd, 0x05,
-	0x46, 0xdf, 0x7f, 0x05, 0xfd, 0xe7, 0x19, 0x15, 0x97, 0xa3, 0x7c, 0x1d, 0x09, 0x71, 0xa1, 0xa1,
-	0x0b, 0xbd, 0xa6, 0x1b, 0x43, 0xd3, 0xfe, 0x09, 0x6c, 0x95, 0x1e, 0x2c, 0xda, 0xfb, 0x50, 0xc3,
-	0xdc, 0x9a, 0x1f, 0xac, 0x34, 0x3f, 0x52, 0x2d, 0x45, 0x42, 0x64, 0x3c, 0x09, 0x6a, 0x98, 0xfb,
-	0x0c, 0x76, 0xb4, 0x91, 0x47, 0x0b, 0x1e, 0x9e, 0xaf, 0x03, 0xa9, 0x07, 0xad, 0x89, 0xb2, 0x55,
-	0x82, 0x2d, 0x58, 0xff, 0x5b, 0x70, 0xab, 0xae, 0x4a, 0xc8, 0x4d, 0xad, 0x60, 0x5d, 0x1d, 0xac,
-	0x74, 0x65, 0x8e, 0x18, 0x45, 0xff, 0x8d, 0x03, 0x5b, 0x23, 0x3b, 0x19, 0xd6, 0x94, 0xdb, 0xa9,
-	0xe0, 0x71, 0x91, 0x5b, 0x45, 0xbb, 0x7d, 0xa8, 0x21, 0xb7, 0xd3, 0xac, 0x86, 0x5c, 0x8d, 0x4b,
-	0x3b, 0x9a, 0x1a, 0x66, 0x5c, 0x1a, 0xce, 0x77, 0x61, 0x7b, 0x09, 0xc5, 0x36, 0xc3, 0x5f, 0x0e,
-	0xec, 0x9e, 0x58, 0xb7, 0xd5, 0xf1, 0xfe, 0x3e, 0x18, 0xd5, 0xb8, 0xe6, 0x51, 0xb6, 0xa0, 0x16,
-	0xa5, 0xe5, 0xdc, 0x03, 0x68, 0x17, 0xa7, 0x2d, 0xda, 0x92, 0xaf, 0x8c, 0xf8, 0xc6, 0xca, 0x11,
-	0xdf, 0xfc, 0xb7, 0x23, 0xde, 0x7f, 0x0e, 0x7b, 0x57, 0x03, 0xb2, 0x77, 0xf7, 0x10, 0xda, 0xc2,
-	0xd2, 0x36, 0xa6, 0xdb, 0x2b, 0xad, 0x15, 0x07, 0x82, 0x52, 0xdd, 0xff, 0xcd, 0x81, 0xfd, 0x13,
-	0xc1, 0xa5, 0x2c, 0x0c, 0xeb, 0xd2, 0x58, 0xd3, 0xbc, 0xca, 0x04, 0xb3, 0x79, 0x52, 0xe4, 0xff,
-	0x08, 0xf8, 0x47, 0x38, 0x58, 0x05, 0xee, 0xfd, 0xc3, 0x3e, 0x83, 0x76, 0x69, 0xe6, 0x06, 0x6c,
-	0x48, 0x24, 0x98, 0x49, 0x6d, 0xa4, 0x19, 0x58, 0x4e, 0x75, 0x50, 0x4c, 0xa5, 0x24, 0xb3, 0xe2,
-	0xb2, 0x0b, 0x56, 0x55, 0xea, 0x84, 0x47, 0x97, 0x76, 0xfe, 0x69, 0x5a, 0x75, 0xc3, 0xf6, 0x0b,
-	0x8a, 0xcf, 0x32, 0x4c, 0xd7, 0xf3, 0xe8, 0x55, 0xa3, 0xab, 0xfd, 0xb7, 0xe8, 0x76, 0x61, 0xa7,
-	0x02, 0xa5, 0x0c, 0xd9, 0x7d, 0x42, 0xb1, 0x58, 0x3c, 0xd6, 0x80, 0xd0, 0xff, 0xdd, 0x81, 0xd6,
-	0x28, 0x7f, 0x9a, 0xa4, 0x19, 0xba, 0xfb, 0x0a, 0xed, 0x74, 0x5c, 0x6e, 0x01, 0x9d, 0xa0, 0x25,
-	0xe8, 0x74, 0x94, 0xb3, 0xc8, 0xbd, 0x0d, 0xa0, 0x3e, 0xf1, 0xe9, 0x54, 0x52, 0xf3, 0x92, 0x34,
-	0x83, 0x8e, 0xa0, 0xd3, 0x67, 0x5a, 0xe0, 0xde, 0x84, 0x8e, 0x6a, 0xf5, 0x31, 0x89, 0x22, 0xa1,
-	0x17, 0x8e, 0x5e, 0xd0, 0x56, 0x82, 0xe3, 0x28, 0x12, 0x95, 0x7e, 0xdf, 0xa8, 0xf6, 0xbb, 0x7b,
-	0x17, 0x36, 0xa7, 0x82, 0xff, 0x42, 0x93, 0xf1, 0x9c, 0xb2, 0xd9, 0x1c, 0xbd, 0x96, 0xde, 0x3c,
-	0x7a, 0x46, 0x78, 0xaa, 0x65, 0xfe, 0x2b, 0x68, 0x8f, 0x72, 0x93, 0x85, 0x8a, 0x21, 0xe7, 0x8a,
-	0xa1, 0x8f, 0xa0, 0x85, 0xdc, 0xf8, 0x36, 0x6f, 0xd0, 0x06, 0x72, 0xed, 0xf9, 0x2d, 0x0f, 0x8d,
-	0x15, 0x1e, 0xde, 0xd4, 0xa0, 0x5b, 0x99, 0xe4, 0xe5, 0xf3, 0xe0, 0x2c, 0x9f, 0x87, 0xeb, 0x07,
-	0xb1, 0xfb, 0x10, 0x3a, 0x98, 0x8f, 0x99, 0xca, 0x9f, 0xf4, 0xea, 0xef, 0x68, 0x0a, 0x9b, 0xe4,
-	0xa0, 0x8d, 0x86, 0x90, 0xee, 0xd7, 0x00, 0x98, 0x8f, 0xb9, 0x8e, 0x4d, 0xea, 0x65, 0xee, 0xba,
-	0xf2, 0x28, 0x32, 0x10, 0x74, 0xd0, 0x52, 0x52, 0xc1, 0x8c, 0xa8, 0x0c, 0x75, 0x4e, 0x7b, 0x81,
-	0xa6, 0xaf, 0xae, 0x8f, 0x07, 0xff, 0xb4, 0x3e, 0xde, 0x7c, 0x6b, 0x7d, 0xf4, 0xff, 0xa8, 0x41,
-	0x53, 0xbf, 0x0f, 0xd5, 0x88, 0xeb, 0x57, 0x23, 0xde, 0x87, 0x76, 0x2a, 0xe8, 0x78, 0x4e, 0xe4,
-	0xdc, 0x0e, 0xc3, 0x56, 0x2a, 0xe8, 0x29, 0x91, 0x73, 0x35, 0x41, 0x53, 0xc1, 0x53, 0x2e, 0x69,
-	0x59, 0x05, 0x05, 0xaf, 0xf0, 0x4a, 0x36, 0x4b, 0x6c, 0x0d, 0x68, 0x5a, 0x5d, 0x68, 0x9a, 0x4d,
-	0xd4, 0x3e, 0xd1, 0x32, 0xf7, 0x66, 0x38, 0x25, 0xb7, 0x17, 0xd6, 0xd1, 0x17, 0x66, 0x39, 0x15,
-	0x1f, 0xb2, 0x98, 0x4a, 0x24, 0x71, 0xea, 0x81, 0xfe, 0xb4, 0x14, 0xa8, 0x45, 0x44, 0x5d, 0x96,
-	0xf4, 0xba, 0x3a, 0x30, 0xc3, 0x28, 0xb8, 0x98, 0x8f, 0x43, 0x5d, 0x36, 0x3d, 0x5d, 0xb7, 0x2d,
-	0xcc, 0x4f, 0x74, 0xdd, 0xec, 0x43, 0x9b, 0x25, 0x63, 0x14, 0x59, 0x72, 0xee, 0xf5, 0x07, 0xce,
-	0x61, 0x3b, 0x68, 0xb1, 0x64, 0xa4, 0x58, 0x55, 0xd0, 0x09, 0xcd, 0xd1, 0x44, 0xb9, 0x65, 0x1e,
-	0x03, 0x25, 0x50, 0x61, 0xfa, 0x02, 0x0e, 0x9e, 0x50, 0x3c, 0x0e, 0xb5, 0x51, 0x55, 0x68, 0x54,
-	0x4a, 0x2a, 0xd7, 0xf4, 0xe0, 0x13, 0x63, 0xb6, 0xa8, 0x33, 0xcb, 0xfa, 0x5f, 0xc1, 0xcd, 0x95,
-	0x3e, 0xed, 0xfc, 0xbb, 0x05, 0x1d, 0x52, 0x08, 0xf5, 0x7a, 0xd5, 0x09, 0x96, 0x02, 0x7f, 0x02,
-	0xfd, 0x33, 0x2e, 0xf1, 0x7b, 0x3e, 0x5b, 0xd3, 0x42, 0x49, 0x13, 0x14, 0x97, 0x16, 0xa2, 0x61,
-	0xfc, 0x7b, 0xb0, 0x55, 0xfa, 0x58, 0x2e, 0xd4, 0x46, 0xd1, 0xa9, 0x2a, 0x5e, 0xc0, 0xf6, 0xe3,
-	0x98, 0xe1, 0xe3, 0x0b, 0x9a, 0xe0, 0x9a, 0x56, 0x8e, 0x84, 0xc4, 0xc5, 0x7c, 0xd7, 0xf4, 0xca,
-	0xe1, 0xbe, 0x0b, 0x3b, 0x15, 0xbf, 0x06, 0xe2, 0xa3, 0x9f, 0xe0, 0x20, 0xe4, 0xf1, 0x70, 0x42,
-	0x58, 0x94, 0x0d, 0xf3, 0x2c, 0xa5, 0xa2, 0x74, 0x99, 0x4e, 0x4e, 0xeb, 0x2f, 0x1f, 0xce, 0x18,
-	0xce, 0xb3, 0xc9, 0x30, 0xe4, 0xf1, 0x91, 0xfe, 0xac, 0x61, 0x59, 0x92, 0x0b, 0x7a, 0x74, 0x4e,
-	0x45, 0x42, 0x17, 0x47, 0xc5, 0xa1, 0xa3, 0x89, 0x60, 0xd1, 0x8c, 0x1e, 0xa5, 0x93, 0xc9, 0x86,
-	0xfe, 0xa3, 0xfd, 0xec, 0xef, 0x00, 0x00, 0x00, 0xff, 0xff, 0x1b, 0x5b, 0x01, 0xd0, 0xe3, 0x0e,
-	0x00, 0x00,
+	// 1290 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x57, 0xdb, 0x6e, 0xdb, 0x46,
+	0x13, 0x06, 0x75, 0xd6, 0x48, 0x96, 0x6d, 0xda, 0xc8, 0xcf, 0x38, 0x09, 0x7e, 0x85, 0xc1, 0x8f,
+	0xf8, 0xbf, 0x91, 0xd3, 0x14, 0x68, 0x91, 0xb6, 0x37, 0x8e, 0x9b, 0xda, 0x01, 0x8a, 0xc6, 0x61,
+	0x04, 0x14, 0x0d, 0x50, 0x28, 0x2b, 0x72, 0x25, 0x2d, 0x2c, 0x72, 0x99, 0xdd, 0xa1, 0x41, 0xf7,
+	0x0d, 0xf2, 0x0c, 0x45, 0xaf, 0xfa, 0x1e, 0x7d, 0x85, 0x5e, 0xf5, 0x7d, 0x8a, 0x3d, 0x90, 0xa2,
+	0x11, 0x25, 0x3d, 0x44, 0xb9, 0x9b, 0x19, 0xee, 0xce, 0x7c, 0x33, 0x3b, 0x27, 0xc2, 0x20, 0xe4,
+	0x09, 0x0a, 0x12, 0xe2, 0x28, 0x15, 0x1c, 0xb9, 0xbb, 0x97, 0x87, 0x0b, 0xc2, 0x92, 0x51, 0x29,
+	0x96, 0xd1, 0x85, 0xbf, 0x05, 0xbd, 0x73, 0x96, 0xcc, 0x03, 0xfa, 0x3a, 0xa3, 0x12, 0xfd, 0x01,
+	0xf4, 0x0d, 0x2b, 0x53, 0x9e, 0x48, 0xea, 0xff, 0x1f, 0x76, 0xbf, 0x23, 0xc8, 0x2e, 0xe9, 0x09,
+	0x59, 0x2e, 0xed, 0x21, 0x77, 0x1f, 0x9a, 0x21, 0xe6, 0x2c, 0xf2, 0x9c, 0xa1, 0x73, 0x58, 0x0f,
+	0x0c, 0xe3, 0xef, 0x83, 0x5b, 0x3d, 0x6a, 0x15, 0x7c, 0x02, 0xed, 0x63, 0x31, 0x3f, 0x27, 0x4c,
+	0xb8, 0x3b, 0x50, 0xbf, 0xa0, 0x57, 0xfa, 0x52, 0x37, 0x50, 0xa4, 0x52, 0x74, 0x49, 0x96, 0x19,
+	0xf5, 0x6a, 0x43, 0xe7, 0xb0, 0x1f, 0x18, 0xc6, 0xff, 0xcd, 0x81, 0x8e, 0xd2, 0x71, 0x2c, 0xe6,
+	0xd2, 0xbd, 0x01, 0xad, 0x98, 0xe2, 0x82, 0x47, 0xf6, 0x9e, 0xe5, 0xdc, 0x07, 0xd0, 0x20, 0x62,
+	0x2e, 0xbd, 0xda, 0xb0, 0x7e, 0xd8, 0x7b, 0x78, 0x7b, 0xb4, 0xc6, 0xb7, 0x91, 0x35, 0x1c, 0xe8,
+	0x93, 0xee, 0x6d, 0xe8, 0xb2, 0x84, 0x21, 0x23, 0xc8, 0x85, 0x57, 0xd7, 0xca, 0x56, 0x02, 0xf7,
+	0x2e, 0xf4, 0x49, 0x86, 0x8b, 0x89, 0xa0, 0xaf, 0x33, 0x26, 0xa8, 0xd7, 0x18, 0xd6, 0x0f, 0xbb,
+	0x41, 0x4f, 0xc9, 0x02, 0x23, 0x72, 0xef, 0xc3, 0x36, 0x0a, 0x92, 0xc8, 0x19, 0x15, 0x13, 0x12,
+	0xf3, 0x2c, 0x41, 0xaf, 0xa9, 0xd5, 0x0c, 0x0a, 0xf1, 0xb1, 0x96, 0xfa, 0xff, 0x83, 0xad, 0x17,
+	0x57, 0x32, 0x24, 0xcb, 0xe5, 0x19, 0x25, 0x11, 0x15, 0xef, 0x08, 0x58, 0x0a, 0x70, 0x9e, 0x61,
+	0x11, 0xd4, 0x2f, 0xa0, 0xb5, 0xd0, 0xa7, 0xf5, 0xa1, 0xde, 0x43, 0x7f, 0xad, 0x4b, 0xd7, 0xf4,
+	0x06, 0xf6, 0x46, 0x11, 0x59, 0x13, 0xc5, 0xeb, 0x91, 0xad, 0x57, 0x23, 0xab, 0x1e, 0x3b, 0xc3,
+	0xf2, 0x6d, 0x5e, 0x02, 0x9c, 0xd2, 0x8f, 0x03, 0xc0, 0xbf, 0x07, 0xbd, 0x53, 0x5a, 0x9a, 0x5a,
+	0xe1, 0x71, 0xaa, 0x78, 0x7e, 0x84, 0xad, 0xaf, 0xe9, 0x92, 0x22, 0xfd, 0x38, 0x18, 0x76, 0x60,
+	0x50, 0xa8, 0xb7, 0x1e, 0xff, 0xe1, 0xc0, 0xf6, 0x53, 0xa4, 0x42, 0x3d, 0xf9, 0x26, 0x6c, 0xee,
+	0x43, 0x53, 0x22, 0x11, 0x58, 0x24, 0xb0, 0x66, 0x94, 0x74, 0xc9, 0x62, 0x86, 0x45, 0xf0, 0x35,
+	0xa3, 0xf0, 0x85, 0x24, 0xf5, 0x1a, 0x43, 0xe7, 0xb0, 0x19, 0x28, 0x52, 0xe5, 0x76, 0x2a, 0xe8,
+	0x8c, 0xe5, 0x3a, 0x8f, 0xfa, 0x81, 0xe5, 0x5c, 0x0f, 0xda, 0x82, 0x5e, 0x52, 0x21, 0xa9, 0xd7,
+	0x1a, 0x3a, 0x87, 0x9d, 0xa0, 0x60, 0xd5, 0x8d, 0x30, 0x13, 0x92, 0x0b, 0xaf, 0x6d, 0x6e, 0x18,
+	0xce, 0xff, 0x0c, 0xfa, 0x85, 0x5b, 0x4f, 0x91, 0xc6, 0xd5, 0x52, 0xeb, 0xbf, 0xaf, 0xd4, 0x96,
+	0xb0, 0xb3, 0x0a, 0x87, 0x7d, 0xaa, 0xcf, 0xa1, 0xc9, 0x90, 0xc6, 0xd2, 0x73, 0x74, 0x69, 0xdd,
+	0x5d, 0x1b, 0x8e, 0xaa, 0xb5, 0xc0, 0x9c, 0x77, 0xff, 0x0b, 0xbd, 0x84, 0xe6, 0x38, 0xb1, 0x08,
+	0x8d, 0x21, 0x50, 0xa2, 0x13, 0x83, 0xf2, 0x15, 0x0c, 0x9e, 0x67, 0x54, 0x5c, 0x8d, 0xf3, 0x4d,
+	0xc4, 0xde, 0x85, 0x86, 0xae, 0xa9, 0x9a, 0xae, 0x41, 0x4d, 0xfb, 0x27, 0xb0, 0x5d, 0x5a, 0xb0,
+	0xee, 0x3c, 0x80, 0x1a, 0xe6, 0x56, 0xfd, 0x70, 0xad, 0xfa, 0xb1, 0xaa, 0x5e, 0x12, 0x22, 0xe3,
+	0x49, 0x50, 0xc3, 0xdc, 0x67, 0xb0, 0xab, 0x95, 0x3c, 0x5e, 0xf2, 0xf0, 0x62, 0x13, 0x48, 0x3d,
+	0x68, 0x4f, 0x95, 0xae, 0x12, 0x6c, 0xc1, 0xfa, 0xdf, 0x80, 0x5b, 0x35, 0x55, 0x42, 0x6e, 0xea,
+	0x03, 0xd6, 0xd4, 0xc1, 0x5a, 0x53, 0xe6, 0x8a, 0x39, 0xe8, 0xbf, 0x71, 0x60, 0x7b, 0x6c, 0x9b,
+	0xd0, 0x86, 0x62, 0x3b, 0x13, 0x3c, 0x2e, 0x62, 0xab, 0x68, 0x77, 0x00, 0x35, 0xe4, 0xb6, 0x71,
+	0xd6, 0x90, 0xab, 0x5c, 0xb4, 0x5d, 0xb0, 0xa1, 0x65, 0x96, 0xf3, 0x5d, 0xd8, 0x59, 0x41, 0xb1,
+	0x75, 0xf7, 0xbb, 0x03, 0x7b, 0x27, 0xd6, 0x6c, 0x75, 0x92, 0x7c, 0x08, 0x46, 0x35, 0x19, 0x78,
+	0x94, 0x2d, 0xa9, 0x45, 0x69, 0x39, 0xf7, 0x00, 0x3a, 0xc5, 0x6d, 0x8b, 0xb6, 0xe4, 0x2b, 0xd3,
+	0xa4, 0xb1, 0x76, 0x9a, 0x34, 0xff, 0xee, 0x34, 0xf1, 0x9f, 0xc3, 0xfe, 0x75, 0x87, 0xec, 0xdb,
+	0x3d, 0x82, 0x8e, 0xb0, 0xb4, 0xf5, 0xe9, 0xce, 0x5a, 0x6d, 0xc5, 0x85, 0xa0, 0x3c, 0xee, 0xff,
+	0xec, 0xc0, 0xcd, 0x13, 0xc1, 0xa5, 0x2c, 0x14, 0xeb, 0xd4, 0xd8, 0x50, 0x6b, 0xcc, 0x04, 0xb3,
+	0x71, 0x52, 0xe4, 0xbf, 0x70, 0xf8, 0x7b, 0x38, 0x58, 0x07, 0xee, 0xc3, 0xdd, 0x3e, 0x87, 0x4e,
+	0xa9, 0xe6, 0x06, 0xb4, 0x24, 0x12, 0xcc, 0xa4, 0x56, 0xd2, 0x0c, 0x2c, 0xa7, 0x2a, 0x28, 0xa6,
+	0x52, 0x92, 0x79, 0xf1, 0xd8, 0x05, 0xab, 0x32, 0x75, 0xca, 0xa3, 0x2b, 0xdb, 0x6a, 0x35, 0xad,
+	0xaa, 0x61, 0xe7, 0x05, 0xc5, 0x67, 0x19, 0xa6, 0x9b, 0x99, 0xaf, 0x55, 0xef, 0x6a, 0xff, 0xcc,
+	0xbb, 0x3d, 0xd8, 0xad, 0x40, 0x29, 0x5d, 0x76, 0x4f, 0x29, 0x16, 0x3b, 0xce, 0x06, 0x10, 0xfa,
+	0xbf, 0x38, 0xd0, 0x1e, 0xe7, 0x4f, 0x93, 0x34, 0x43, 0xf7, 0xa6, 0x42, 0x3b, 0x9b, 0x94, 0x0b,
+	0x47, 0x57, 0xcd, 0x8f, 0xd9, 0x38, 0x67, 0x91, 0x7b, 0x07, 0x40, 0x7d, 0xe2, 0xb3, 0x99, 0xa4,
+	0x66, 0x68, 0x35, 0x83, 0xae, 0xa0, 0xb3, 0x67, 0x5a, 0xe0, 0xde, 0x82, 0xae, 0x2a, 0xf5, 0x09,
+	0x89, 0x22, 0x61, 0x67, 0x52, 0x47, 0x09, 0x8e, 0xa3, 0x48, 0x54, 0xea, 0xbd, 0x55, 0xad, 0x77,
+	0xf7, 0x1e, 0x6c, 0xcd, 0x04, 0xff, 0x89, 0x26, 0x93, 0x05, 0x65, 0xf3, 0x05, 0xea, 0xd1, 0x54,
+	0x0f, 0xfa, 0x46, 0x78, 0xa6, 0x65, 0xfe, 0x2b, 0xe8, 0x8c, 0x73, 0x13, 0x85, 0x8a, 0x22, 0xe7,
+	0x9a, 0xa2, 0xff, 0x40, 0x1b, 0xb9, 0xb1, 0x6d, 0x66, 0x47, 0x0b, 0xb9, 0xb6, 0xfc, 0x96, 0x85,
+	0xc6, 0x1a, 0x0b, 0x6f, 0x6a, 0xd0, 0xab, 0x74, 0xf2, 0x72, 0x3c, 0x38, 0xab, 0xf1, 0xf0, 0xee,
+	0x46, 0xec, 0x3e, 0x82, 0x2e, 0xe6, 0x13, 0xa6, 0xe2, 0x27, 0xbd, 0xfa, 0x7b, 0x8a, 0xc2, 0x06,
+	0x39, 0xe8, 0xa0, 0x21, 0xa4, 0xfb, 0x15, 0x00, 0xe6, 0x13, 0xae, 0x7d, 0x93, 0x7a, 0x6f, 0x7c,
+	0x57, 0x7a, 0x14, 0x11, 0x08, 0xba, 0x68, 0x29, 0xa9, 0x60, 0x46, 0x54, 0x86, 0x3a, 0xa6, 0xfd,
+	0x40, 0xd3, 0xd7, 0x37, 0xd5, 0x83, 0xbf, 0xda, 0x54, 0x6f, 0xbd, 0xb5, 0xa9, 0xfa, 0xbf, 0xd6,
+	0xa0, 0xa9, 0xe7, 0x43, 0xd5, 0xe3, 0xfa, 0x75, 0x8f, 0x6f, 0x42, 0x27, 0x15, 0x74, 0xb2, 0x20,
+	0x72, 0x61, 0x9b, 0x61, 0x3b, 0x15, 0xf4, 0x8c, 0xc8, 0x85, 0xea, 0xa0, 0xa9, 0xe0, 0x29, 0x97,
+	0xb4, 0xcc, 0x82, 0x82, 0x57, 0x78, 0x25, 0x9b, 0x27, 0x36, 0x07, 0x34, 0xad, 0xf7, 0x98, 0x6c,
+	0xaa, 0x16, 0x0e, 0xbb, 0x95, 0x18, 0x4e, 0xc9, 0xed, 0x83, 0x75, 0xf5, 0x83, 0x59, 0x4e, 0xf9,
+	0x87, 0x2c, 0xa6, 0x12, 0x49, 0x9c, 0x7a, 0xa0, 0x3f, 0xad, 0x04, 0x6a, 0x53, 0x51, 0x8f, 0x25,
+	0xbd, 0x9e, 0x76, 0xcc, 0x30, 0x0a, 0x2e, 0xe6, 0x93, 0x50, 0xa7, 0x4d, 0x5f, 0xe7, 0x6d, 0x1b,
+	0xf3, 0x13, 0xc5, 0xaa, 0x4f, 0x2c, 0x99, 0xa0, 0xc8, 0x92, 0x0b, 0x6f, 0x60, 0xf6, 0x25, 0x96,
+	0x8c, 0x15, 0xab, 0x12, 0x5a, 0xaf, 0x24, 0xda, 0xcb, 0x6d, 0x33, 0x0c, 0x94, 0x40, 0xb9, 0xe9,
+	0x0b, 0x38, 0x38, 0xa5, 0x78, 0x1c, 0x6a, 0xa5, 0x2a, 0xd1, 0xa8, 0x94, 0x54, 0x6e, 0x68, 0xe0,
+	0x13, 0xa3, 0xb6, 0xc8, 0x33, 0xcb, 0xfa, 0x5f, 0xc2, 0xad, 0xb5, 0x36, 0x6d, 0xff, 0xbb, 0x0d,
+	0x5d, 0x52, 0x08, 0xf5, 0xfe, 0xd5, 0x0d, 0x56, 0x02, 0x7f, 0x0a, 0x83, 0x73, 0x2e, 0xf1, 0x5b,
+	0x3e, 0xdf, 0xd0, 0xee, 0x4a, 0x13, 0x14, 0x57, 0x16, 0xa2, 0x61, 0xfc, 0xfb, 0xb0, 0x5d, 0xda,
+	0x58, 0xed, 0xee, 0xe6, 0xa0, 0x53, 0x3d, 0x78, 0x09, 0x3b, 0x4f, 0x62, 0x86, 0x4f, 0x2e, 0x69,
+	0x82, 0x1b, 0x5a, 0x39, 0x12, 0x12, 0x17, 0xfd, 0x5d, 0xd3, 0x6b, 0x9b, 0xfb, 0x1e, 0xec, 0x56,
+	0xec, 0x1a, 0x88, 0x8f, 0x7f, 0x80, 0x83, 0x90, 0xc7, 0xa3, 0x29, 0x61, 0x51, 0x36, 0xca, 0xb3,
+	0x94, 0x8a, 0xd2, 0x64, 0x3a, 0x3d, 0xab, 0xbf, 0x7c, 0x34, 0x67, 0xb8, 0xc8, 0xa6, 0xa3, 0x90,
+	0xc7, 0x47, 0xfa, 0xb3, 0x86, 0x65, 0x49, 0x2e, 0xe8, 0xd1, 0x05, 0x15, 0x09, 0x5d, 0x1e, 0x15,
+	0x97, 0x8e, 0xa6, 0x82, 0x45, 0x73, 0x7a, 0x94, 0x4e, 0xa7, 0x2d, 0xfd, 0xf3, 0xfc, 0xe9, 0x9f,
+	0x03, 0x00, 0xc5, 0xa3, 0x26, 0x7e, 0x4e, 0x0f, 0x00, 0x00,
 }
@@ -3,6 +3,7 @@
 package bridge
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"errors"
@@ -172,6 +173,7 @@ func (c *SyscallService) ContractCall(ctx context.Context, in *pb.ContractCallRe
 		Caller:         nctx.ContractName,
 		ResourceLimits: *limits,
 		ContractSet:    nctx.ContractSet,
+		Tracer:         nctx.Tracer,
 	}
 	vctx, err := c.bridge.NewContext(cfg)
 	if err != nil {
@@ -182,10 +184,27 @@ func (c *SyscallService) ContractCall(ctx context.Context, in *pb.ContractCallRe
 		delete(nctx.ContractSet, in.GetContract())
 	}()
 
+	// 分叉后被调合约失败时撤销其对状态的修改，避免调用方捕获错误后继续使用部分修改的状态，
+	// 分叉前的交易按原规则保留修改，保证历史区块可以重放
+	revert := nctx.State.ContractCallRevert()
+	var cp int
+	if revert {
+		cp = nctx.State.Checkpoint()
+	}
 	vresp, err := vctx.Invoke(in.GetMethod(), args)
 	if err != nil {
+		if revert {
+			nctx.State.RevertToCheckpoint(cp)
+		}
 		return nil, err
 	}
+	if revert {
+		if vresp.Status < 200 || vresp.Status >= 300 {
+			nctx.State.RevertToCheckpoint(cp)
+		} else {
+			nctx.State.ReleaseCheckpoint(cp)
+		}
+	}
 	nctx.SubResourceUsed.Add(vctx.ResourceUsed())
 
 	return &pb.ContractCallResponse{
@@ -292,25 +311,82 @@ func (c *SyscallService) NewIterator(ctx context.Context, in *pb.IteratorRequest
 	if limit <= 0 {
 		limit = DefaultCap
 	}
-	iter, err := nctx.State.Select(nctx.ContractName, in.Start, in.Limit)
+	start, end, err := iteratorRange(in)
 	if err != nil {
 		return nil, err
 	}
+	// 只有使用了新增的prefix、reverse、cursor参数时空的limit才表示扫描到bucket末尾，
+	// 其余请求保持原有的Select语义，保证已上链交易的重放结果不变
+	var iter contract.Iterator
+	if len(in.GetPrefix()) != 0 || in.GetReverse() || len(in.GetCursor()) != 0 {
+		iter, err = nctx.State.SelectRange(nctx.ContractName, start, end, in.GetReverse())
+	} else {
+		iter, err = nctx.State.Select(nctx.ContractName, start, end)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
 	out := new(pb.IteratorResponse)
-	for iter.Next() && limit > 0 {
+	for iter.Next() {
+		// 多读出的一个key作为下一批的起点，已经记录在读集中，验证时结果一致
+		if int32(len(out.Items)) >= limit {
+			out.NextCursor = append([]byte(""), iter.Key()...)
+			break
+		}
 		out.Items = append(out.Items, &pb.IteratorItem{
 			Key:   append([]byte(""), iter.Key()...), //make a copy
 			Value: append([]byte(""), iter.Value()...),
 		})
-		limit -= 1
 	}
-	if iter.Error() != nil {
+	if err := iter.Error(); err != nil {
 		return nil, err
 	}
-	iter.Close()
 	return out, nil
 }
 
+// iteratorRange 根据请求计算扫描区间[start, end)
+// cursor是下一批的第一个key，正序时作为start，逆序时作为end并包含cursor
+func iteratorRange(in *pb.IteratorRequest) ([]byte, []byte, error) {
+	start, end := in.GetStart(), in.GetLimit()
+	if len(in.GetPrefix()) != 0 {
+		if len(start) != 0 || len(end) != 0 {
+			return nil, nil, errors.New("prefix can not be used with start or limit")
+		}
+		start, end = in.GetPrefix(), prefixEnd(in.GetPrefix())
+	}
+	// 空的end表示扫描到bucket的最后一个元素
+	if len(end) == 0 {
+		end = nil
+	}
+
+	cursor := in.GetCursor()
+	if len(cursor) == 0 {
+		return start, end, nil
+	}
+	if bytes.Compare(cursor, start) < 0 || (end != nil && bytes.Compare(cursor, end) >= 0) {
+		return nil, nil, errors.New("iterator cursor out of range")
+	}
+	if in.GetReverse() {
+		return start, append(append([]byte(""), cursor...), 0), nil
+	}
+	return cursor, end, nil
+}
+
+// prefixEnd 返回第一个大于所有以prefix开头的key的[]byte，不存在时返回nil
+func prefixEnd(prefix []byte) []byte {
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] < 0xff {
+			limit := make([]byte, i+1)
+			copy(limit, prefix)
+			limit[i]++
+			return limit
+		}
+	}
+	return nil
+}
+
 // GetCallArgs implements Syscall interface
 func (c *SyscallService) GetCallArgs(ctx context.Context, in *pb.GetCallArgsRequest) (*pb.CallArgs, error) {
 	nctx, ok := c.ctxmgr.Context(in.GetHeader().Ctxid)
@@ -0,0 +1,84 @@
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge/pb"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+)
+
+// iterateAll 按cap分批读取所有数据，返回读到的key
+func iterateAll(t *testing.T, syscall *SyscallService, req *pb.IteratorRequest) []string {
+	var keys []string
+	for {
+		resp, err := syscall.NewIterator(context.TODO(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, item := range resp.GetItems() {
+			keys = append(keys, string(item.GetKey()))
+		}
+		if len(resp.GetNextCursor()) == 0 {
+			return keys
+		}
+		if len(resp.GetItems()) != int(req.GetCap()) {
+			t.Fatalf("expect full batch before cursor, got %d", len(resp.GetItems()))
+		}
+		req.Cursor = resp.GetNextCursor()
+	}
+}
+
+func TestNewIterator(t *testing.T) {
+	state := sandbox.NewXModelCache(&contract.SandboxConfig{
+		XMReader: sandbox.NewMemXModel(),
+	})
+	for i := 0; i < 5; i++ {
+		state.Put("counter", []byte(fmt.Sprintf("a%d", i)), []byte("v"))
+		state.Put("counter", []byte(fmt.Sprintf("b%d", i)), []byte("v"))
+	}
+	ctxmgr := NewContextManager()
+	ctx := ctxmgr.MakeContext()
+	ctx.ContractName = "counter"
+	ctx.State = state
+	syscall := NewSyscallService(ctxmgr, nil)
+	header := &pb.SyscallHeader{Ctxid: ctx.ID}
+
+	testCases := []struct {
+		req    *pb.IteratorRequest
+		expect string
+	}{
+		{&pb.IteratorRequest{Cap: 3}, "[a0 a1 a2 a3 a4 b0 b1 b2 b3 b4]"},
+		{&pb.IteratorRequest{Cap: 3, Reverse: true}, "[b4 b3 b2 b1 b0 a4 a3 a2 a1 a0]"},
+		{&pb.IteratorRequest{Cap: 2, Prefix: []byte("b")}, "[b0 b1 b2 b3 b4]"},
+		{&pb.IteratorRequest{Cap: 2, Prefix: []byte("a"), Reverse: true}, "[a4 a3 a2 a1 a0]"},
+		{&pb.IteratorRequest{Cap: 4, Start: []byte("a3"), Limit: []byte("b2"), Reverse: true}, "[b1 b0 a4 a3]"},
+		{&pb.IteratorRequest{Cap: 5, Start: []byte("a3"), Limit: []byte("b2")}, "[a3 a4 b0 b1]"},
+	}
+	for _, tc := range testCases {
+		tc.req.Header = header
+		keys := fmt.Sprint(iterateAll(t, syscall, tc.req))
+		if keys != tc.expect {
+			t.Errorf("expect %s got %s", tc.expect, keys)
+		}
+	}
+
+	_, err := syscall.NewIterator(context.TODO(), &pb.IteratorRequest{
+		Header: header,
+		Prefix: []byte("a"),
+		Start:  []byte("a1"),
+	})
+	if err == nil {
+		t.Error("expect error when prefix used with start")
+	}
+	_, err = syscall.NewIterator(context.TODO(), &pb.IteratorRequest{
+		Header: header,
+		Prefix: []byte("a"),
+		Cursor: []byte("b1"),
+	})
+	if err == nil {
+		t.Error("expect error when cursor out of range")
+	}
+}
@@ -137,6 +137,7 @@ func (v *XBridge) NewContext(ctxCfg *contract.ContextConfig) (contract.Context,
 	ctx.CanInitialize = ctxCfg.CanInitialize
 	ctx.TransferAmount = ctxCfg.TransferAmount
 	ctx.ContractSet = ctxCfg.ContractSet
+	ctx.Tracer = ctxCfg.Tracer
 	if ctx.ContractSet == nil {
 		ctx.ContractSet = make(map[string]bool)
 		ctx.ContractSet[ctx.ContractName] = true
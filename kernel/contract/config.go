@@ -9,6 +9,16 @@ type NativeConfig struct {
 	Driver string
 	// Timeout (in seconds) to stop native code process
 	StopTimeout int
+	// Interval (in seconds) of health check, default 1
+	HealthCheckInterval int
+	// Max backoff (in seconds) of restarting crashed process, default 30
+	MaxRestartBackoff int
+	// Max concurrent calls per contract, 0 means unlimited
+	MaxConcurrency int
+	// Timeout (in seconds) to stop idle process, 0 means never
+	IdleTimeout int
+	// Timeout (in seconds) of a call, including waiting for concurrency and process start, default 30
+	CallTimeout int
 	Docker      NativeDockerConfig
 	Enable      bool
 }
@@ -52,4 +52,7 @@ type ContextConfig struct {
 
 	// ContractCodeFromCache control whether fetch contract code from XMCache
 	ContractCodeFromCache bool
+
+	// Tracer 非空时记录合约调用轨迹，子合约调用沿用调用方的Tracer
+	Tracer *Tracer
 }
@@ -3,10 +3,13 @@ package mock
 import (
 	"crypto/rand"
 	"encoding/json"
+	"fmt"
 	"github.com/xuperchain/xupercore/kernel/contract/bridge"
 	"io/ioutil"
 	"math/big"
 	"os"
+	"strings"
+	"sync/atomic"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/xuperchain/xupercore/kernel/contract"
@@ -22,20 +25,22 @@ const (
 	FeaturesContractName = "features"
 )
 
+var basedirSeq int64
+
 type TestHelper struct {
 	basedir    string
 	utxo       *contract.UTXORWSet
 	utxoReader sandbox.UtxoReader
 	state      *sandbox.MemXModel
 	manager    contract.Manager
+	// 部署和升级合约时是否记录版本历史
+	contractVersion bool
+	// 被调合约失败时是否回滚其修改
+	contractCallRevert bool
 }
 
 func NewTestHelper(cfg *contract.ContractConfig) *TestHelper {
-	basedir, err := ioutil.TempDir("", "contract-test")
-	if err != nil {
-		panic(err)
-	}
-
+	basedir := newBasedir(cfg)
 	state := sandbox.NewMemXModel()
 	core := new(fakeChainCore)
 	m, err := contract.CreateManager("default", &contract.ManagerConfig{
@@ -58,6 +63,20 @@ func NewTestHelper(cfg *contract.ContractConfig) *TestHelper {
 	return th
 }
 
+// newBasedir 状态数据都在内存中，只有wasm和native合约需要在磁盘上存放编译产物和合约进程，
+// 其他情况使用不落盘的内存路径
+func newBasedir(cfg *contract.ContractConfig) string {
+	if !cfg.Wasm.Enable && !cfg.Native.Enable {
+		return fmt.Sprintf("/memory/contract-test/%d", atomic.AddInt64(&basedirSeq, 1))
+	}
+
+	basedir, err := ioutil.TempDir("", "contract-test")
+	if err != nil {
+		panic(err)
+	}
+	return basedir
+}
+
 func (t *TestHelper) Manager() contract.Manager {
 	return t.manager
 }
@@ -73,6 +92,16 @@ func (t *TestHelper) UTXOState() *contract.UTXORWSet {
 	return t.utxo
 }
 
+// SetContractVersion 设置部署和升级合约时是否记录版本历史
+func (t *TestHelper) SetContractVersion(enabled bool) {
+	t.contractVersion = enabled
+}
+
+// SetContractCallRevert 设置被调合约失败时是否回滚其修改
+func (t *TestHelper) SetContractCallRevert(enabled bool) {
+	t.contractCallRevert = enabled
+}
+
 func (t *TestHelper) initAccount() {
 	t.state.Put(utils.GetAccountBucket(), []byte(ContractAccount), &ledger.VersionedData{
 		RefTxid:  []byte("txid"),
@@ -95,8 +124,10 @@ func (t *TestHelper) initAccount() {
 func (t *TestHelper) Deploy(module, lang, contractName string, bin []byte, args map[string][]byte) (*contract.Response, error) {
 	m := t.Manager()
 	state, err := m.NewStateSandbox(&contract.SandboxConfig{
-		XMReader:   t.State(),
-		UTXOReader: t.utxoReader,
+		XMReader:           t.State(),
+		UTXOReader:         t.utxoReader,
+		ContractVersion:    t.contractVersion,
+		ContractCallRevert: t.contractCallRevert,
 	})
 	if err != nil {
 		return nil, err
@@ -128,7 +159,7 @@ func (t *TestHelper) Deploy(module, lang, contractName string, bin []byte, args
 		"contract_desc": descbuf,
 		"init_args":     argsBuf,
 	}
-	if bridge.ContractType(module) == bridge.TypeEvm {
+	if bridge.ContractType(module) == bridge.TypeEvm || args["contract_abi"] != nil {
 		invokeArgs["contract_abi"] = args["contract_abi"]
 	}
 	resp, err := ctx.Invoke("deployContract", invokeArgs)
@@ -143,10 +174,17 @@ func (t *TestHelper) Deploy(module, lang, contractName string, bin []byte, args
 }
 
 func (t *TestHelper) Upgrade(contractName string, bin []byte) error {
+	return t.UpgradeWithArgs(contractName, bin, nil)
+}
+
+// UpgradeWithArgs 升级合约，args不为nil时作为migrate方法的参数
+func (t *TestHelper) UpgradeWithArgs(contractName string, bin []byte, args map[string][]byte) error {
 	m := t.Manager()
 	state, err := m.NewStateSandbox(&contract.SandboxConfig{
-		XMReader:   t.State(),
-		UTXOReader: t.utxoReader,
+		XMReader:           t.State(),
+		UTXOReader:         t.utxoReader,
+		ContractVersion:    t.contractVersion,
+		ContractCallRevert: t.contractCallRevert,
 	})
 	if err != nil {
 		return err
@@ -162,10 +200,14 @@ func (t *TestHelper) Upgrade(contractName string, bin []byte) error {
 		return err
 	}
 
-	_, err = ctx.Invoke("upgradeContract", map[string][]byte{
+	invokeArgs := map[string][]byte{
 		"contract_name": []byte(contractName),
 		"contract_code": bin,
-	})
+	}
+	if args != nil {
+		invokeArgs["migrate_args"], _ = json.Marshal(args)
+	}
+	_, err = ctx.Invoke("upgradeContract", invokeArgs)
 	ctx.Release()
 	t.Commit(state)
 	return err
@@ -174,8 +216,10 @@ func (t *TestHelper) Upgrade(contractName string, bin []byte) error {
 func (t *TestHelper) Invoke(module, contractName, method string, args map[string][]byte) (*contract.Response, error) {
 	m := t.Manager()
 	state, err := m.NewStateSandbox(&contract.SandboxConfig{
-		XMReader:   t.State(),
-		UTXOReader: t.utxoReader,
+		XMReader:           t.State(),
+		UTXOReader:         t.utxoReader,
+		ContractVersion:    t.contractVersion,
+		ContractCallRevert: t.contractCallRevert,
 	})
 	if err != nil {
 		return nil, err
@@ -221,5 +265,8 @@ func (t *TestHelper) Commit(state contract.StateSandbox) {
 }
 
 func (t *TestHelper) Close() {
+	if strings.HasPrefix(t.basedir, "/memory/") {
+		return
+	}
 	os.RemoveAll(t.basedir)
 }
@@ -0,0 +1,362 @@
+// Package parallel 在同一个状态快照上乐观地并发预执行一批交易
+//
+// 执行分两个阶段:
+//  1. 所有交易在各自的沙盒中并发执行，读取的都是快照中的数据；
+//  2. 按交易顺序依次提交，交易读集中的key如果被前面已提交的交易写过，说明读到的是旧值，
+//     在叠加了前面交易写集的状态上重新执行，重新执行按顺序进行，结果是确定的。
+//
+// 冲突检测除了读集中的key，还记录执行时Select/SelectRange扫描过的区间，前面交易新写入的key
+// 落在扫描区间内时同样认为冲突，避免幻读；区间的结束key为空时按扫描到bucket末尾处理。
+//
+// 结果的顺序与输入一致，执行失败的交易不影响后面的交易，打包时跳过即可。
+// 读到前面交易写集的交易，读集版本中的txid是占位符，生成交易后用ResolveRSet替换为真实的txid。
+// 合约转账需要选择utxo，并发执行时无法避免重复选择，不在这里支持。
+//
+// 状态机生成定时交易时(Miner.packBlock打包和校验区块中的定时交易都经过State.GetTimerTx)，
+// 分叉后每个定时任务作为一个交易并发预执行，再用Merge按任务顺序合并为一个定时交易。
+package parallel
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+	"github.com/xuperchain/xupercore/kernel/ledger"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+const (
+	pendingTxidPrefix = "$pending:"
+)
+
+var (
+	ErrParameter      = errors.New("parallel executor parameter error")
+	ErrInvokeFailed   = errors.New("contract invoke failed")
+	ErrPendingMissing = errors.New("txid of pending tx not found")
+)
+
+// Task 一个需要预执行的交易，合约调用按顺序在同一个沙盒中执行
+type Task struct {
+	Initiator   string
+	AuthRequire []string
+	// 合约看到的调用方，为空时与直接发起的交易相同
+	Caller   string
+	Requests []*protos.InvokeRequest
+}
+
+// Result 交易的预执行结果
+type Result struct {
+	// 交易在输入中的位置
+	Index     int
+	RWSet     *contract.RWSet
+	Responses []*contract.Response
+	// 读到了哪些交易的写集，按位置排序
+	Deps []int
+	// 冲突后重新执行过
+	Reexecuted bool
+	Err        error
+
+	// 执行时扫描过的区间
+	ranges []keyRange
+}
+
+// keyRange 一次Select扫描的区间[start, end)，end为空表示到bucket末尾
+type keyRange struct {
+	bucket string
+	start  []byte
+	end    []byte
+}
+
+// Executor 乐观并发执行器
+type Executor struct {
+	manager            contract.Manager
+	workers            int
+	contractCallRevert bool
+}
+
+// NewExecutor 并发执行的协程数为workers，小于1时按1处理
+func NewExecutor(manager contract.Manager, workers int) *Executor {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Executor{
+		manager: manager,
+		workers: workers,
+	}
+}
+
+// SetContractCallRevert 设置被调合约失败时是否回滚其修改，与沙盒配置的ContractCallRevert相同
+func (e *Executor) SetContractCallRevert(enabled bool) {
+	e.contractCallRevert = enabled
+}
+
+// Execute 在reader上执行一批交易，返回与tasks顺序一致的结果
+func (e *Executor) Execute(reader ledger.XMReader, tasks []*Task) ([]*Result, error) {
+	if e.manager == nil || reader == nil {
+		return nil, ErrParameter
+	}
+
+	// 第一阶段: 在快照上并发执行
+	results := make([]*Result, len(tasks))
+	ch := make(chan int)
+	wg := sync.WaitGroup{}
+	for i := 0; i < e.workers && i < len(tasks); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range ch {
+				results[idx] = e.execute(idx, reader, tasks[idx])
+			}
+		}()
+	}
+	for idx := range tasks {
+		ch <- idx
+	}
+	close(ch)
+	wg.Wait()
+
+	// 第二阶段: 按顺序校验读集并提交写集
+	committed := sandbox.NewMemXModel()
+	layered := sandbox.NewLayeredReader(committed, reader)
+	hasCommitted := false
+	for idx, result := range results {
+		if hasCommitted && conflicted(committed, result) {
+			result = e.execute(idx, layered, tasks[idx])
+			result.Reexecuted = true
+			results[idx] = result
+		}
+		if result.Err != nil {
+			continue
+		}
+		result.Deps = pendingDeps(result.RWSet.RSet)
+		for offset, data := range result.RWSet.WSet {
+			if data.GetBucket() == sandbox.TransientBucket {
+				continue
+			}
+			committed.Put(data.GetBucket(), data.GetKey(), &ledger.VersionedData{
+				RefTxid:   PendingTxid(idx),
+				RefOffset: int32(offset),
+				PureData:  data,
+			})
+			hasCommitted = true
+		}
+	}
+	return results, nil
+}
+
+// execute 在独立的沙盒中执行一个交易
+func (e *Executor) execute(idx int, reader ledger.XMReader, task *Task) *Result {
+	result := &Result{
+		Index: idx,
+	}
+	recorder := &rangeRecorder{
+		XMReader: reader,
+	}
+	state, err := e.manager.NewStateSandbox(&contract.SandboxConfig{
+		XMReader:           recorder,
+		ContractCallRevert: e.contractCallRevert,
+	})
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	for _, req := range task.Requests {
+		resp, err := e.invoke(state, task, req)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		result.Responses = append(result.Responses, resp)
+	}
+	if err := state.Flush(); err != nil {
+		result.Err = err
+		return result
+	}
+	result.RWSet = state.RWSet()
+	result.ranges = recorder.ranges
+	return result
+}
+
+func (e *Executor) invoke(state contract.StateSandbox, task *Task, req *protos.InvokeRequest) (*contract.Response, error) {
+	ctx, err := e.manager.NewContext(&contract.ContextConfig{
+		State:          state,
+		Initiator:      task.Initiator,
+		AuthRequire:    task.AuthRequire,
+		Caller:         task.Caller,
+		Module:         req.GetModuleName(),
+		ContractName:   req.GetContractName(),
+		ResourceLimits: contract.MaxLimits,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer ctx.Release()
+
+	resp, err := ctx.Invoke(req.GetMethodName(), req.GetArgs())
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status >= 400 {
+		return nil, fmt.Errorf("%w: %s.%s status %d, %s", ErrInvokeFailed, req.GetContractName(),
+			req.GetMethodName(), resp.Status, resp.Message)
+	}
+	return resp, nil
+}
+
+// Merge 把base和执行成功的结果按顺序合并为一个交易的读写集，base可以为nil。
+// 读到前面交易写集的读在合并后的交易内部满足，不进入读集，其余的读每个key只保留一次；
+// 同一个key的写以后面的交易为准。读写集按key排序，与沙盒生成的顺序一致
+func Merge(base *contract.RWSet, results []*Result) *contract.RWSet {
+	rset := sandbox.NewMemXModel()
+	wset := sandbox.NewMemXModel()
+	add := func(rwSet *contract.RWSet) {
+		for _, data := range rwSet.RSet {
+			if _, ok := ParsePendingTxid(data.GetRefTxid()); ok {
+				continue
+			}
+			pure := data.GetPureData()
+			if _, err := rset.Get(pure.GetBucket(), pure.GetKey()); err == nil {
+				continue
+			}
+			rset.Put(pure.GetBucket(), pure.GetKey(), data)
+		}
+		for _, data := range rwSet.WSet {
+			wset.Put(data.GetBucket(), data.GetKey(), &ledger.VersionedData{
+				PureData: data,
+			})
+		}
+	}
+	if base != nil {
+		add(base)
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		add(result.RWSet)
+	}
+
+	merged := &contract.RWSet{}
+	iter := rset.NewIterator()
+	for iter.Next() {
+		merged.RSet = append(merged.RSet, iter.Value())
+	}
+	iter.Close()
+	iter = wset.NewIterator()
+	for iter.Next() {
+		merged.WSet = append(merged.WSet, iter.Value().GetPureData())
+	}
+	iter.Close()
+	return merged
+}
+
+// conflicted 读集中的key或者扫描过的区间被前面已提交的交易写过，
+// 执行失败的交易可能是读到了旧值，也需要重新执行
+func conflicted(committed *sandbox.MemXModel, result *Result) bool {
+	if result.RWSet == nil {
+		return true
+	}
+	for _, data := range result.RWSet.RSet {
+		pure := data.GetPureData()
+		if _, err := committed.Get(pure.GetBucket(), pure.GetKey()); err == nil {
+			return true
+		}
+	}
+	for _, r := range result.ranges {
+		iter, err := committed.Select(r.bucket, r.start, r.end)
+		if err != nil {
+			return true
+		}
+		found := iter.Next()
+		iter.Close()
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// rangeRecorder 记录沙盒通过Select/SelectRange扫描过的区间
+type rangeRecorder struct {
+	ledger.XMReader
+	mutex  sync.Mutex
+	ranges []keyRange
+}
+
+func (r *rangeRecorder) Select(bucket string, startKey []byte, endKey []byte) (ledger.XMIterator, error) {
+	r.record(bucket, startKey, endKey)
+	return r.XMReader.Select(bucket, startKey, endKey)
+}
+
+func (r *rangeRecorder) SelectRange(bucket string, startKey []byte, endKey []byte, reverse bool) (ledger.XMIterator, error) {
+	r.record(bucket, startKey, endKey)
+	return r.XMReader.SelectRange(bucket, startKey, endKey, reverse)
+}
+
+func (r *rangeRecorder) record(bucket string, startKey []byte, endKey []byte) {
+	// 空的结束key按扫描到bucket末尾处理，区间偏大只会多重新执行
+	if len(endKey) == 0 {
+		endKey = nil
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.ranges = append(r.ranges, keyRange{
+		bucket: bucket,
+		start:  append([]byte(nil), startKey...),
+		end:    append([]byte(nil), endKey...),
+	})
+}
+
+func pendingDeps(rset []*ledger.VersionedData) []int {
+	seen := make(map[int]bool)
+	var deps []int
+	for _, data := range rset {
+		idx, ok := ParsePendingTxid(data.GetRefTxid())
+		if !ok || seen[idx] {
+			continue
+		}
+		seen[idx] = true
+		deps = append(deps, idx)
+	}
+	sort.Ints(deps)
+	return deps
+}
+
+// PendingTxid 第idx个交易生成前用于读集版本的占位txid
+func PendingTxid(idx int) []byte {
+	return []byte(pendingTxidPrefix + strconv.Itoa(idx))
+}
+
+// ParsePendingTxid 解析占位txid，返回交易的位置
+func ParsePendingTxid(txid []byte) (int, bool) {
+	s := string(txid)
+	if !strings.HasPrefix(s, pendingTxidPrefix) {
+		return 0, false
+	}
+	idx, err := strconv.Atoi(s[len(pendingTxidPrefix):])
+	if err != nil {
+		return 0, false
+	}
+	return idx, true
+}
+
+// ResolveRSet 把读集版本中的占位txid替换为交易生成后的txid，txids按交易位置索引
+func ResolveRSet(rset []*ledger.VersionedData, txids map[int][]byte) error {
+	for _, data := range rset {
+		idx, ok := ParsePendingTxid(data.GetRefTxid())
+		if !ok {
+			continue
+		}
+		txid, ok := txids[idx]
+		if !ok {
+			return fmt.Errorf("%w: %d", ErrPendingMissing, idx)
+		}
+		data.RefTxid = txid
+	}
+	return nil
+}
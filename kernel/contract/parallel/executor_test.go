@@ -0,0 +1,284 @@
+package parallel_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	_ "github.com/xuperchain/xupercore/kernel/contract/kernel"
+	_ "github.com/xuperchain/xupercore/kernel/contract/manager"
+	"github.com/xuperchain/xupercore/kernel/contract/mock"
+	"github.com/xuperchain/xupercore/kernel/contract/parallel"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
+	"github.com/xuperchain/xupercore/kernel/ledger"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+var contractConfig = &contract.ContractConfig{
+	Xkernel: contract.XkernelConfig{
+		Enable: true,
+		Driver: "default",
+	},
+	LogDriver: mock.NewMockLogger(),
+}
+
+func incr(ctx contract.KContext) (*contract.Response, error) {
+	key := ctx.Args()["key"]
+	value, err := ctx.Get("counter", key)
+	if err != nil {
+		return nil, err
+	}
+	n, _ := strconv.Atoi(string(value))
+	value = []byte(strconv.Itoa(n + 1))
+	if err := ctx.Put("counter", key, value); err != nil {
+		return nil, err
+	}
+	return &contract.Response{Status: 200, Body: value}, nil
+}
+
+func fail(ctx contract.KContext) (*contract.Response, error) {
+	return &contract.Response{Status: 500, Message: "fail"}, nil
+}
+
+func newTask(method string, key string) *parallel.Task {
+	return &parallel.Task{
+		Requests: []*protos.InvokeRequest{{
+			ModuleName:   "xkernel",
+			ContractName: "$counter",
+			MethodName:   method,
+			Args:         map[string][]byte{"key": []byte(key)},
+		}},
+	}
+}
+
+func TestExecute(t *testing.T) {
+	th := mock.NewTestHelper(contractConfig)
+	defer th.Close()
+	m := th.Manager()
+	m.GetKernRegistry().RegisterKernMethod("$counter", "Incr", incr)
+	m.GetKernRegistry().RegisterKernMethod("$counter", "Fail", fail)
+
+	state := sandbox.NewMemXModel()
+	for _, key := range []string{"a", "b"} {
+		state.Put("counter", []byte(key), &ledger.VersionedData{
+			RefTxid:  []byte("txid"),
+			PureData: &ledger.PureData{Bucket: "counter", Key: []byte(key), Value: []byte("0")},
+		})
+	}
+	tasks := []*parallel.Task{
+		newTask("Incr", "a"),
+		newTask("Incr", "b"),
+		newTask("Incr", "a"),
+		newTask("Fail", "a"),
+		newTask("Incr", "a"),
+	}
+	expect := []struct {
+		body       string
+		deps       []int
+		reexecuted bool
+		failed     bool
+	}{
+		{"1", nil, false, false},
+		{"1", nil, false, false},
+		{"2", []int{0}, true, false},
+		{"", nil, false, true},
+		{"3", []int{2}, true, false},
+	}
+
+	for _, workers := range []int{1, 4} {
+		results, err := parallel.NewExecutor(m, workers).Execute(state, tasks)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != len(tasks) {
+			t.Fatalf("expect %d results, got %d", len(tasks), len(results))
+		}
+		for i, result := range results {
+			if result.Index != i || (result.Err != nil) != expect[i].failed {
+				t.Fatalf("workers %d task %d unexpected result, err %v", workers, i, result.Err)
+			}
+			if expect[i].failed {
+				if !errors.Is(result.Err, parallel.ErrInvokeFailed) {
+					t.Fatalf("expect invoke failed, got %v", result.Err)
+				}
+				continue
+			}
+			if string(result.Responses[0].Body) != expect[i].body || result.Reexecuted != expect[i].reexecuted {
+				t.Fatalf("workers %d task %d expect %s, got %s", workers, i, expect[i].body, result.Responses[0].Body)
+			}
+			if len(result.Deps) != len(expect[i].deps) || (len(result.Deps) > 0 && result.Deps[0] != expect[i].deps[0]) {
+				t.Fatalf("workers %d task %d expect deps %v, got %v", workers, i, expect[i].deps, result.Deps)
+			}
+		}
+
+		// 生成交易后替换占位txid
+		txids := map[int][]byte{0: []byte("tx0"), 2: []byte("tx2")}
+		if err := parallel.ResolveRSet(results[4].RWSet.RSet, map[int][]byte{0: []byte("tx0")}); !errors.Is(err, parallel.ErrPendingMissing) {
+			t.Fatalf("expect pending missing, got %v", err)
+		}
+		if err := parallel.ResolveRSet(results[4].RWSet.RSet, txids); err != nil {
+			t.Fatal(err)
+		}
+		for _, data := range results[4].RWSet.RSet {
+			if string(data.GetPureData().GetKey()) == "a" && string(data.GetRefTxid()) != "tx2" {
+				t.Fatalf("expect read version from tx2, got %s", data.GetRefTxid())
+			}
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	th := mock.NewTestHelper(contractConfig)
+	defer th.Close()
+	m := th.Manager()
+	m.GetKernRegistry().RegisterKernMethod("$counter", "Incr", incr)
+	m.GetKernRegistry().RegisterKernMethod("$counter", "Fail", fail)
+
+	state := sandbox.NewMemXModel()
+	for _, key := range []string{"a", "b"} {
+		state.Put("counter", []byte(key), &ledger.VersionedData{
+			RefTxid:  []byte("txid"),
+			PureData: &ledger.PureData{Bucket: "counter", Key: []byte(key), Value: []byte("0")},
+		})
+	}
+	tasks := []*parallel.Task{
+		newTask("Incr", "b"),
+		newTask("Incr", "a"),
+		newTask("Fail", "a"),
+		newTask("Incr", "a"),
+	}
+	results, err := parallel.NewExecutor(m, 4).Execute(state, tasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged := parallel.Merge(nil, results)
+
+	// 与在同一个沙盒中按顺序执行成功的任务结果相同
+	sb, err := m.NewStateSandbox(&contract.SandboxConfig{XMReader: state})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, task := range tasks {
+		req := task.Requests[0]
+		ctx, err := m.NewContext(&contract.ContextConfig{
+			State:          sb,
+			Module:         req.GetModuleName(),
+			ContractName:   req.GetContractName(),
+			ResourceLimits: contract.MaxLimits,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx.Invoke(req.GetMethodName(), req.GetArgs())
+		ctx.Release()
+	}
+	if err := sb.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	expect := sb.RWSet()
+	if len(expect.WSet) != 2 || len(merged.RSet) != len(expect.RSet) || len(merged.WSet) != len(expect.WSet) {
+		t.Fatalf("expect %d reads %d writes, got %d reads %d writes",
+			len(expect.RSet), len(expect.WSet), len(merged.RSet), len(merged.WSet))
+	}
+	for i, data := range merged.RSet {
+		if string(data.GetPureData().GetKey()) != string(expect.RSet[i].GetPureData().GetKey()) ||
+			string(data.GetRefTxid()) != "txid" {
+			t.Fatalf("read %d unexpected %v", i, data)
+		}
+	}
+	for i, data := range merged.WSet {
+		if string(data.GetKey()) != string(expect.WSet[i].GetKey()) ||
+			string(data.GetValue()) != string(expect.WSet[i].GetValue()) {
+			t.Fatalf("write %d expect %s=%s, got %s=%s", i, expect.WSet[i].GetKey(), expect.WSet[i].GetValue(),
+				data.GetKey(), data.GetValue())
+		}
+	}
+}
+
+func TestPendingTxid(t *testing.T) {
+	idx, ok := parallel.ParsePendingTxid(parallel.PendingTxid(12))
+	if !ok || idx != 12 {
+		t.Fatalf("expect 12, got %d", idx)
+	}
+	if _, ok := parallel.ParsePendingTxid([]byte("txid")); ok {
+		t.Fatal("expect not pending txid")
+	}
+}
+
+func count(ctx contract.KContext) (*contract.Response, error) {
+	var iter contract.Iterator
+	var err error
+	if len(ctx.Args()["to_end"]) > 0 {
+		iter, err = ctx.SelectRange("counter", []byte("item_"), nil, false)
+	} else {
+		iter, err = ctx.Select("counter", []byte("item_"), []byte("item_~"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+	n := 0
+	for iter.Next() {
+		n++
+	}
+	return &contract.Response{Status: 200, Body: []byte(strconv.Itoa(n))}, nil
+}
+
+func add(ctx contract.KContext) (*contract.Response, error) {
+	if err := ctx.Put("counter", ctx.Args()["key"], []byte("1")); err != nil {
+		return nil, err
+	}
+	return &contract.Response{Status: 200}, nil
+}
+
+func TestExecuteRangeConflict(t *testing.T) {
+	th := mock.NewTestHelper(contractConfig)
+	defer th.Close()
+	m := th.Manager()
+	m.GetKernRegistry().RegisterKernMethod("$counter", "Count", count)
+	m.GetKernRegistry().RegisterKernMethod("$counter", "Add", add)
+
+	state := sandbox.NewMemXModel()
+	for _, key := range []string{"item_a", "item_b", "z"} {
+		state.Put("counter", []byte(key), &ledger.VersionedData{
+			RefTxid:  []byte("txid"),
+			PureData: &ledger.PureData{Bucket: "counter", Key: []byte(key), Value: []byte("1")},
+		})
+	}
+	countToEnd := newTask("Count", "")
+	countToEnd.Requests[0].Args["to_end"] = []byte("1")
+	tasks := []*parallel.Task{
+		newTask("Add", "y"),
+		// 写入的key不在扫描区间内，不需要重新执行
+		newTask("Count", ""),
+		newTask("Add", "item_c"),
+		// 前面的交易在扫描区间内新增了key，读集中没有这个key，也需要重新执行
+		newTask("Count", ""),
+		countToEnd,
+	}
+	expect := []struct {
+		body       string
+		reexecuted bool
+	}{
+		{"", false},
+		{"2", false},
+		{"", false},
+		{"3", true},
+		{"5", true},
+	}
+
+	results, err := parallel.NewExecutor(m, 4).Execute(state, tasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, result := range results {
+		if result.Err != nil {
+			t.Fatalf("task %d failed: %v", i, result.Err)
+		}
+		if string(result.Responses[0].Body) != expect[i].body || result.Reexecuted != expect[i].reexecuted {
+			t.Fatalf("task %d expect %s %v, got %s %v", i, expect[i].body, expect[i].reexecuted,
+				result.Responses[0].Body, result.Reexecuted)
+		}
+	}
+}
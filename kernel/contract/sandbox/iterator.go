@@ -75,6 +75,8 @@ func (p *peekIterator) Close() {
 type multiIterator struct {
 	front *peekIterator
 	back  *peekIterator
+	// 两个XMIterator都是逆序时按照key从大到小合并
+	reverse bool
 
 	key   []byte
 	value *ledger.VersionedData
@@ -88,6 +90,15 @@ func newMultiIterator(front, back ledger.XMIterator) ledger.XMIterator {
 	return m
 }
 
+func newReverseMultiIterator(front, back ledger.XMIterator) ledger.XMIterator {
+	m := &multiIterator{
+		front:   newPeekIterator(front),
+		back:    newPeekIterator(back),
+		reverse: true,
+	}
+	return m
+}
+
 func (m *multiIterator) Key() []byte {
 	return m.key
 }
@@ -111,6 +122,9 @@ func (m *multiIterator) Next() bool {
 	k1, _ := m.front.Peek()
 	k2, _ := m.back.Peek()
 	ret := compareBytes(k1, k2)
+	if m.reverse {
+		ret = -ret
+	}
 	switch ret {
 	case 0:
 		m.key, m.value = m.front.Next()
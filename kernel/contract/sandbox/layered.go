@@ -0,0 +1,67 @@
+package sandbox
+
+import (
+	"github.com/xuperchain/xupercore/kernel/ledger"
+)
+
+var (
+	_ ledger.XMReader = (*LayeredReader)(nil)
+)
+
+// LayeredReader 在底层状态上叠加一层未提交的写入，读取时上层优先，
+// 用于在同一个快照上依次执行多个交易，后面的交易能读到前面交易的写集
+type LayeredReader struct {
+	upper *MemXModel
+	lower ledger.XMReader
+}
+
+// NewLayeredReader new an instance of LayeredReader
+func NewLayeredReader(upper *MemXModel, lower ledger.XMReader) *LayeredReader {
+	return &LayeredReader{
+		upper: upper,
+		lower: lower,
+	}
+}
+
+// Get 上层中被删除的key返回删除标记
+func (l *LayeredReader) Get(bucket string, key []byte) (*ledger.VersionedData, error) {
+	data, err := l.upper.Get(bucket, key)
+	if err == nil {
+		return data, nil
+	}
+	if err != ErrNotFound {
+		return nil, err
+	}
+	return l.lower.Get(bucket, key)
+}
+
+// Select 合并上下两层，同一个key以上层为准
+func (l *LayeredReader) Select(bucket string, startKey []byte, endKey []byte) (ledger.XMIterator, error) {
+	upperIter, err := l.upper.Select(bucket, startKey, endKey)
+	if err != nil {
+		return nil, err
+	}
+	lowerIter, err := l.lower.Select(bucket, startKey, endKey)
+	if err != nil {
+		upperIter.Close()
+		return nil, err
+	}
+	return newMultiIterator(upperIter, lowerIter), nil
+}
+
+// SelectRange likes Select, iterates from the biggest key when reverse is true
+func (l *LayeredReader) SelectRange(bucket string, startKey []byte, endKey []byte, reverse bool) (ledger.XMIterator, error) {
+	upperIter, err := l.upper.SelectRange(bucket, startKey, endKey, reverse)
+	if err != nil {
+		return nil, err
+	}
+	lowerIter, err := l.lower.SelectRange(bucket, startKey, endKey, reverse)
+	if err != nil {
+		upperIter.Close()
+		return nil, err
+	}
+	if reverse {
+		return newReverseMultiIterator(upperIter, lowerIter), nil
+	}
+	return newMultiIterator(upperIter, lowerIter), nil
+}
@@ -0,0 +1,56 @@
+package sandbox
+
+import (
+	"testing"
+)
+
+func TestLayeredReader(t *testing.T) {
+	lower := NewMemXModel()
+	putVersionedData(lower, "b", []byte("k1"), []byte("v1"))
+	putVersionedData(lower, "b", []byte("k2"), []byte("v2"))
+	upper := NewMemXModel()
+	putVersionedData(upper, "b", []byte("k2"), []byte("v2_new"))
+	putVersionedData(upper, "b", []byte("k3"), []byte("v3"))
+	reader := NewLayeredReader(upper, lower)
+
+	data, err := reader.Get("b", []byte("k2"))
+	if err != nil || string(data.GetPureData().GetValue()) != "v2_new" {
+		t.Fatalf("expect value of upper layer, got %v %v", data, err)
+	}
+	data, err = reader.Get("b", []byte("k1"))
+	if err != nil || string(data.GetPureData().GetValue()) != "v1" {
+		t.Fatalf("expect value of lower layer, got %v %v", data, err)
+	}
+
+	expect := []string{"k1:v1", "k2:v2_new", "k3:v3"}
+	iter, err := reader.Select("b", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for iter.Next() {
+		got = append(got, string(iter.Key())+":"+string(iter.Value().GetPureData().GetValue()))
+	}
+	iter.Close()
+	if len(got) != len(expect) {
+		t.Fatalf("expect %v, got %v", expect, got)
+	}
+	for i := range expect {
+		if got[i] != expect[i] {
+			t.Fatalf("expect %v, got %v", expect, got)
+		}
+	}
+
+	iter, err = reader.SelectRange("b", nil, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = got[:0]
+	for iter.Next() {
+		got = append(got, string(iter.Key()))
+	}
+	iter.Close()
+	if len(got) != 3 || got[0] != "k3" || got[2] != "k1" {
+		t.Fatalf("unexpected reverse keys %v", got)
+	}
+}
@@ -12,6 +12,15 @@ import (
 
 type MemXModel struct {
 	tree *redblacktree.Tree
+	// 开启检查点后记录每次Put之前的值，用于回滚
+	journal    []journalEntry
+	journaling bool
+}
+
+// journalEntry 记录被覆盖的值，prev为nil表示key原来不存在
+type journalEntry struct {
+	rawKey []byte
+	prev   *ledger.VersionedData
 }
 
 func XMReaderFromRWSet(rwset *contract.RWSet) ledger.XMReader {
@@ -41,18 +50,73 @@ func (m *MemXModel) Get(bucket string, key []byte) (*ledger.VersionedData, error
 
 func (m *MemXModel) Put(bucket string, key []byte, value *ledger.VersionedData) error {
 	buKey := makeRawKey(bucket, key)
+	if m.journaling {
+		entry := journalEntry{rawKey: buKey}
+		if v, ok := m.tree.Get(buKey); ok {
+			entry.prev = v.(*ledger.VersionedData)
+		}
+		m.journal = append(m.journal, entry)
+	}
 	m.tree.Put(buKey, value)
 	return nil
 }
 
+// Checkpoint 开启写入日志，返回当前的日志位置
+func (m *MemXModel) Checkpoint() int {
+	m.journaling = true
+	return len(m.journal)
+}
+
+// RevertToCheckpoint 逆序撤销日志位置cp之后的写入
+func (m *MemXModel) RevertToCheckpoint(cp int) {
+	if cp < 0 || cp > len(m.journal) {
+		return
+	}
+	for i := len(m.journal) - 1; i >= cp; i-- {
+		entry := m.journal[i]
+		if entry.prev == nil {
+			m.tree.Remove(entry.rawKey)
+			continue
+		}
+		m.tree.Put(entry.rawKey, entry.prev)
+	}
+	m.journal = m.journal[:cp]
+}
+
+// DiscardJournal 关闭写入日志，之前的检查点都不能再回滚
+func (m *MemXModel) DiscardJournal() {
+	m.journaling = false
+	m.journal = nil
+}
+
 // Select 扫描一个bucket中所有的kv, 调用者可以设置key区间[startKey, endKey)
 // start为nil意味着从bucket的第一个元素开始，end为空意味着遍历到bucket的最后一个元素
 func (m *MemXModel) Select(bucket string, startKey []byte, endKey []byte) (ledger.XMIterator, error) {
+	rawStartKey, rawEndKey, err := m.rawRange(bucket, startKey, endKey)
+	if err != nil {
+		return nil, err
+	}
+	return newTreeRangeIterator(m.tree, rawStartKey, rawEndKey), nil
+}
+
+// SelectRange 与Select相同，reverse为true时按照key从大到小的顺序扫描
+func (m *MemXModel) SelectRange(bucket string, startKey []byte, endKey []byte, reverse bool) (ledger.XMIterator, error) {
+	if !reverse {
+		return m.Select(bucket, startKey, endKey)
+	}
+	rawStartKey, rawEndKey, err := m.rawRange(bucket, startKey, endKey)
+	if err != nil {
+		return nil, err
+	}
+	return newTreeReverseRangeIterator(m.tree, rawStartKey, rawEndKey), nil
+}
+
+func (m *MemXModel) rawRange(bucket string, startKey []byte, endKey []byte) ([]byte, []byte, error) {
 	if bucket == "" {
-		return nil, errors.New("empty bucket name")
+		return nil, nil, errors.New("empty bucket name")
 	}
 	if startKey != nil && endKey != nil && m.tree.Comparator(startKey, endKey) > 0 {
-		return nil, errors.New("bad select range, start key can't bigger than end key")
+		return nil, nil, errors.New("bad select range, start key can't bigger than end key")
 	}
 	var rawEndKey []byte
 	rawStartKey := makeRawKey(bucket, startKey)
@@ -61,7 +125,7 @@ func (m *MemXModel) Select(bucket string, startKey []byte, endKey []byte) (ledge
 	} else {
 		rawEndKey = prefixEnd(makeRawKey(bucket, nil))
 	}
-	return newTreeRangeIterator(m.tree, rawStartKey, rawEndKey), nil
+	return rawStartKey, rawEndKey, nil
 }
 
 func (m *MemXModel) NewIterator() ledger.XMIterator {
@@ -70,10 +134,14 @@ func (m *MemXModel) NewIterator() ledger.XMIterator {
 
 // treeIterator 把tree的Iterator转换成XMIterator
 type treeIterator struct {
-	cmp  utils.Comparator
-	iter *redblacktree.Iterator
-	end  []byte
-	err  error
+	cmp   utils.Comparator
+	iter  *redblacktree.Iterator
+	start []byte
+	end   []byte
+	err   error
+
+	// reverse为true时向前迭代，直到小于start
+	reverse bool
 
 	iterDone bool
 }
@@ -107,6 +175,30 @@ func newTreeRangeIterator(tree *redblacktree.Tree, start, end []byte) ledger.XMI
 	return it
 }
 
+// newTreeReverseRangeIterator按照[start, end)的范围从大到小迭代，start和end不能为空
+func newTreeReverseRangeIterator(tree *redblacktree.Tree, start, end []byte) ledger.XMIterator {
+	it := &treeIterator{
+		cmp:     tree.Comparator,
+		start:   start,
+		reverse: true,
+	}
+	// 找到最后一个小于等于end的节点
+	endNode, ok := tree.Floor(end)
+	if !ok {
+		it.iterDone = true
+		return it
+	}
+	iter := tree.IteratorAt(endNode)
+	// 调用Next时通过Prev移动游标，因此游标需要停在第一个元素的下一个位置
+	// 节点等于end时正好不包含end
+	if it.cmp(endNode.Key, end) < 0 {
+		iter.Next()
+	}
+
+	it.iter = &iter
+	return it
+}
+
 func (t *treeIterator) Next() bool {
 	if t.iterDone {
 		return false
@@ -121,6 +213,14 @@ func (t *treeIterator) Next() bool {
 		return false
 	}
 
+	if t.reverse {
+		if !t.iter.Prev() || t.cmp(t.iter.Key(), t.start) < 0 {
+			t.iterDone = true
+			return false
+		}
+		return true
+	}
+
 	if !t.iter.Next() {
 		t.iterDone = true
 		return false
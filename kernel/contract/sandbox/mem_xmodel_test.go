@@ -132,3 +132,43 @@ func TestXModelIteratorStartAndEnd(t *testing.T) {
 	})
 
 }
+
+func TestXModelReverseIterator(t *testing.T) {
+	m := NewMemXModel()
+	putVersionedData(m, "tess", []byte("9"), []byte("9"))
+	putVersionedData(m, "test", []byte("1"), []byte("1"))
+	putVersionedData(m, "test", []byte("2"), []byte("2"))
+	putVersionedData(m, "test", []byte("3"), []byte("3"))
+	putVersionedData(m, "test1", []byte("1"), []byte("1"))
+
+	testCases := []struct {
+		start  []byte
+		end    []byte
+		expect []string
+	}{
+		{nil, nil, []string{"3", "2", "1"}},
+		{[]byte("2"), nil, []string{"3", "2"}},
+		{nil, []byte("3"), []string{"2", "1"}},
+		{[]byte("1"), []byte("25"), []string{"2", "1"}},
+		{[]byte("4"), []byte("5"), nil},
+	}
+	for _, tc := range testCases {
+		iter, err := m.SelectRange("test", tc.start, tc.end, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var keys []string
+		for iter.Next() {
+			keys = append(keys, string(iter.Key()))
+		}
+		iter.Close()
+		if len(keys) != len(tc.expect) {
+			t.Fatalf("range [%s, %s) expect %v got %v", tc.start, tc.end, tc.expect, keys)
+		}
+		for i := range keys {
+			if keys[i] != tc.expect[i] {
+				t.Fatalf("range [%s, %s) expect %v got %v", tc.start, tc.end, tc.expect, keys)
+			}
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+)
+
+// 合约存储计量:
+// 合约数据保存在与合约同名的bucket中，占用按len(key)+len(value)计算，删除的key不占用空间。
+// 交易不读写存储用量，避免同一合约的并发交易在用量上冲突，已确认的用量由状态机在区块执行时按交易的读写集汇总。
+// 预执行在Flush时按快照中的用量和配额检查，这些读取不进入读集，因此验证区块中的交易时不检查配额。
+
+const (
+	// StorageBucket 保存提案设置的合约存储配额
+	StorageBucket = "$storage"
+
+	storageQuotaPrefix = "quota/"
+)
+
+var (
+	// ErrStorageQuotaExceeded is returned when contract storage usage exceeds its quota
+	ErrStorageQuotaExceeded = errors.New("contract storage quota exceeded")
+)
+
+// StorageQuotaKey 合约存储配额的key
+func StorageQuotaKey(contractName string) []byte {
+	return []byte(storageQuotaPrefix + contractName)
+}
+
+// EncodeStorageBytes 编码存储用量和配额
+func EncodeStorageBytes(n int64) []byte {
+	return []byte(strconv.FormatInt(n, 10))
+}
+
+// DecodeStorageBytes 解码存储用量和配额，值为空或已删除时返回0
+func DecodeStorageBytes(value []byte) (int64, error) {
+	if len(value) == 0 || IsDelFlag(value) {
+		return 0, nil
+	}
+	return strconv.ParseInt(string(value), 10, 64)
+}
+
+// StorageSize 一个key占用的字节数
+func StorageSize(key []byte, value []byte) int64 {
+	if len(value) == 0 || IsDelFlag(value) {
+		return 0
+	}
+	return int64(len(key) + len(value))
+}
+
+// isContractBucket 只统计已部署合约的bucket，部署信息见bridge.ContractCodeDescKey，
+// 从本次的写集和快照中读取，不记录到读集
+func (xc *XMCache) isContractBucket(bucket string) bool {
+	if contract.ValidContractName(bucket) != nil {
+		return false
+	}
+	descKey := []byte(bucket + ".desc")
+	if data, err := xc.outputsCache.Get("contract", descKey); err == nil {
+		return !IsDelFlag(data.GetPureData().GetValue())
+	}
+	return xc.snapshotValue("contract", descKey) != nil
+}
+
+// storageDeltas 按写集计算每个合约bucket占用字节的变化，bucket按写集顺序返回
+func (xc *XMCache) storageDeltas() ([]string, map[string]int64) {
+	var buckets []string
+	deltas := make(map[string]int64)
+	iter := xc.outputsCache.NewIterator()
+	defer iter.Close()
+	for iter.Next() {
+		data := iter.Value().GetPureData()
+		bucket := data.GetBucket()
+		delta := StorageSize(data.GetKey(), data.GetValue())
+		// Put前会强制读取，旧值在读集中
+		if old, err := xc.inputsCache.Get(bucket, data.GetKey()); err == nil {
+			delta -= StorageSize(data.GetKey(), old.GetPureData().GetValue())
+		}
+		if _, ok := deltas[bucket]; !ok {
+			buckets = append(buckets, bucket)
+		}
+		deltas[bucket] += delta
+	}
+	return buckets, deltas
+}
+
+// checkStorageQuota 合约的存储用量增加且超过配额时返回错误
+func (xc *XMCache) checkStorageQuota() error {
+	if xc.storageQuota == nil {
+		return nil
+	}
+	buckets, deltas := xc.storageDeltas()
+	for _, bucket := range buckets {
+		delta := deltas[bucket]
+		if delta <= 0 || !xc.isContractBucket(bucket) {
+			continue
+		}
+		quota, err := xc.storageQuotaOf(bucket)
+		if err != nil {
+			return err
+		}
+		if quota == 0 {
+			continue
+		}
+		var used int64
+		if xc.storageQuota.Usage != nil {
+			if used, err = xc.storageQuota.Usage(bucket); err != nil {
+				return err
+			}
+		}
+		if used+delta > quota {
+			return fmt.Errorf("%w: contract %s used %d, quota %d", ErrStorageQuotaExceeded, bucket, used+delta, quota)
+		}
+	}
+	return nil
+}
+
+// storageQuotaOf 返回合约的存储配额，未单独设置时使用默认配额
+func (xc *XMCache) storageQuotaOf(contractName string) (int64, error) {
+	value := xc.snapshotValue(StorageBucket, StorageQuotaKey(contractName))
+	if value == nil {
+		return xc.storageQuota.Default, nil
+	}
+	return DecodeStorageBytes(value)
+}
+
+// snapshotValue 直接从快照读取，不记录到读集，不存在或已删除时返回nil
+func (xc *XMCache) snapshotValue(bucket string, key []byte) []byte {
+	data, err := xc.model.Get(bucket, key)
+	if err != nil {
+		return nil
+	}
+	value := data.GetPureData().GetValue()
+	if len(value) == 0 || IsDelFlag(value) {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,105 @@
+package sandbox
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+)
+
+func newStorageStore(quota string) *MemXModel {
+	store := NewMemXModel()
+	putVersionedData(store, "contract", []byte("counter.desc"), []byte("desc"))
+	putVersionedData(store, "counter", []byte("k0"), []byte("v0"))
+	if quota != "" {
+		putVersionedData(store, StorageBucket, StorageQuotaKey("counter"), []byte(quota))
+	}
+	return store
+}
+
+func newStorageQuota(defaultQuota int64, used int64) *contract.StorageQuota {
+	return &contract.StorageQuota{
+		Default: defaultQuota,
+		Usage: func(contractName string) (int64, error) {
+			return used, nil
+		},
+	}
+}
+
+func TestStorageDeltas(t *testing.T) {
+	xc := NewXModelCache(&contract.SandboxConfig{
+		XMReader:     newStorageStore(""),
+		StorageQuota: newStorageQuota(20, 4),
+	})
+	xc.Put("counter", []byte("k1"), []byte("v1"))
+	xc.Put("counter", []byte("k0"), []byte("v00"))
+	xc.Put("XCAccount", []byte("account"), []byte("value"))
+	buckets, deltas := xc.storageDeltas()
+	if len(buckets) != 2 || deltas["counter"] != 5 {
+		t.Fatalf("unexpected storage deltas %v %v", buckets, deltas)
+	}
+	if err := xc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 用量、配额和部署信息不进入读写集，同一合约的交易不会因此冲突
+	rwSet := xc.RWSet()
+	for _, data := range rwSet.RSet {
+		if bucket := data.GetPureData().GetBucket(); bucket == StorageBucket || bucket == "contract" {
+			t.Errorf("unexpected read %s/%s", bucket, data.GetPureData().GetKey())
+		}
+	}
+	for _, data := range rwSet.WSet {
+		if data.GetBucket() == StorageBucket {
+			t.Errorf("unexpected write %s/%s", data.GetBucket(), data.GetKey())
+		}
+	}
+}
+
+func TestStorageQuota(t *testing.T) {
+	xc := NewXModelCache(&contract.SandboxConfig{
+		XMReader:     newStorageStore(""),
+		StorageQuota: newStorageQuota(20, 18),
+	})
+	xc.Put("counter", []byte("k1"), []byte("v1"))
+	if err := xc.Flush(); !errors.Is(err, ErrStorageQuotaExceeded) {
+		t.Fatalf("expect quota exceeded, got %v", err)
+	}
+
+	// 不是合约的bucket不检查
+	xc = NewXModelCache(&contract.SandboxConfig{
+		XMReader:     newStorageStore(""),
+		StorageQuota: newStorageQuota(1, 0),
+	})
+	xc.Put("XCAccount", []byte("account"), []byte("value"))
+	if err := xc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 单独设置的配额优先于默认配额
+	xc = NewXModelCache(&contract.SandboxConfig{
+		XMReader:     newStorageStore("100"),
+		StorageQuota: newStorageQuota(20, 18),
+	})
+	xc.Put("counter", []byte("k1"), []byte("v1"))
+	if err := xc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 超过配额后仍然可以删除数据
+	xc = NewXModelCache(&contract.SandboxConfig{
+		XMReader:     newStorageStore(""),
+		StorageQuota: newStorageQuota(20, 30),
+	})
+	xc.Del("counter", []byte("k0"))
+	if err := xc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 未开启计量时不检查
+	xc = NewXModelCache(&contract.SandboxConfig{XMReader: newStorageStore("1")})
+	xc.Put("counter", []byte("k1"), []byte("v1"))
+	if err := xc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,142 @@
+package sandbox
+
+import (
+	"math/big"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+// traceSandbox 在沙盒的状态操作上记录系统调用的参数和结果，其余操作直接透传
+type traceSandbox struct {
+	contract.StateSandbox
+	tracer *contract.Tracer
+}
+
+// NewTraceSandbox 返回记录系统调用轨迹的沙盒，tracer为空时直接返回原沙盒
+func NewTraceSandbox(state contract.StateSandbox, tracer *contract.Tracer) contract.StateSandbox {
+	if !tracer.Enabled() {
+		return state
+	}
+	return &traceSandbox{
+		StateSandbox: state,
+		tracer:       tracer,
+	}
+}
+
+func (s *traceSandbox) Get(bucket string, key []byte) ([]byte, error) {
+	value, err := s.StateSandbox.Get(bucket, key)
+	s.tracer.AddSyscall(&protos.SyscallTrace{
+		Name:   contract.SyscallGet,
+		Bucket: bucket,
+		Key:    key,
+		Value:  value,
+		Error:  errorString(err),
+	})
+	return value, err
+}
+
+func (s *traceSandbox) Select(bucket string, startKey []byte, endKey []byte) (contract.Iterator, error) {
+	iter, err := s.StateSandbox.Select(bucket, startKey, endKey)
+	return s.traceSelect(contract.SyscallSelect, bucket, startKey, endKey, iter, err)
+}
+
+func (s *traceSandbox) SelectRange(bucket string, startKey []byte, endKey []byte, reverse bool) (contract.Iterator, error) {
+	iter, err := s.StateSandbox.SelectRange(bucket, startKey, endKey, reverse)
+	name := contract.SyscallSelect
+	if reverse {
+		name = contract.SyscallReverseSelect
+	}
+	return s.traceSelect(name, bucket, startKey, endKey, iter, err)
+}
+
+func (s *traceSandbox) traceSelect(name, bucket string, startKey []byte, endKey []byte,
+	iter contract.Iterator, err error) (contract.Iterator, error) {
+	s.tracer.AddSyscall(&protos.SyscallTrace{
+		Name:   name,
+		Bucket: bucket,
+		Key:    startKey,
+		EndKey: endKey,
+		Error:  errorString(err),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &traceIterator{
+		Iterator: iter,
+		bucket:   bucket,
+		tracer:   s.tracer,
+	}, nil
+}
+
+func (s *traceSandbox) Put(bucket string, key, value []byte) error {
+	err := s.StateSandbox.Put(bucket, key, value)
+	s.tracer.AddSyscall(&protos.SyscallTrace{
+		Name:   contract.SyscallPut,
+		Bucket: bucket,
+		Key:    key,
+		Value:  value,
+		Error:  errorString(err),
+	})
+	return err
+}
+
+func (s *traceSandbox) Del(bucket string, key []byte) error {
+	err := s.StateSandbox.Del(bucket, key)
+	s.tracer.AddSyscall(&protos.SyscallTrace{
+		Name:   contract.SyscallDel,
+		Bucket: bucket,
+		Key:    key,
+		Error:  errorString(err),
+	})
+	return err
+}
+
+func (s *traceSandbox) Transfer(from string, to string, amount *big.Int) error {
+	err := s.StateSandbox.Transfer(from, to, amount)
+	s.tracer.AddSyscall(&protos.SyscallTrace{
+		Name:   contract.SyscallTransfer,
+		From:   from,
+		To:     to,
+		Amount: amount.String(),
+		Error:  errorString(err),
+	})
+	return err
+}
+
+func (s *traceSandbox) AddEvent(events ...*protos.ContractEvent) {
+	s.StateSandbox.AddEvent(events...)
+	for _, event := range events {
+		s.tracer.AddSyscall(&protos.SyscallTrace{
+			Name:  contract.SyscallEmitEvent,
+			Event: event,
+		})
+	}
+}
+
+// traceIterator 记录迭代器返回的每一个kv
+type traceIterator struct {
+	contract.Iterator
+	bucket string
+	tracer *contract.Tracer
+}
+
+func (i *traceIterator) Next() bool {
+	ok := i.Iterator.Next()
+	if ok {
+		i.tracer.AddSyscall(&protos.SyscallTrace{
+			Name:   contract.SyscallIteratorNext,
+			Bucket: i.bucket,
+			Key:    i.Iterator.Key(),
+			Value:  i.Iterator.Value(),
+		})
+	}
+	return ok
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,90 @@
+package sandbox
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func TestTraceSandbox(t *testing.T) {
+	mc := NewXModelCache(&contract.SandboxConfig{
+		XMReader: NewMemXModel(),
+	})
+	if NewTraceSandbox(mc, nil) != contract.StateSandbox(mc) {
+		t.Fatal("expect raw sandbox without tracer")
+	}
+
+	tracer := contract.NewTracer(0)
+	state := NewTraceSandbox(mc, tracer)
+
+	// 没有调用帧时不记录
+	state.Put("b1", []byte("k0"), []byte("v0"))
+
+	tracer.Enter("native", "c1", "invoke", map[string][]byte{"a": []byte("1")})
+	state.Put("b1", []byte("k1"), []byte("v1"))
+	state.Get("b1", []byte("k1"))
+	tracer.Enter("wasm", "c2", "sub", nil)
+	state.Del("b2", []byte("k0"))
+	state.AddEvent(&protos.ContractEvent{Contract: "c2", Name: "e1"})
+	tracer.Exit(nil, contract.Limits{Cpu: 10}, errors.New("sub failed"))
+	iter, err := state.Select("b1", []byte("k"), []byte("l"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for iter.Next() {
+	}
+	iter.Close()
+	tracer.Exit(&contract.Response{Status: 200, Message: "ok"}, contract.Limits{Cpu: 20}, nil)
+
+	traces := tracer.Traces()
+	if len(traces) != 1 {
+		t.Fatalf("expect 1 trace, got %d", len(traces))
+	}
+	root := traces[0]
+	if root.ContractName != "c1" || root.Status != 200 || root.ResourceUsed[0].Limit != 20 {
+		t.Fatalf("unexpected root frame %v", root)
+	}
+	names := []string{contract.SyscallPut, contract.SyscallGet, contract.SyscallContractCall,
+		contract.SyscallSelect, contract.SyscallIteratorNext, contract.SyscallIteratorNext}
+	if len(root.Syscalls) != len(names) {
+		t.Fatalf("expect %d syscalls, got %v", len(names), root.Syscalls)
+	}
+	for i, name := range names {
+		if root.Syscalls[i].Name != name {
+			t.Errorf("syscall %d expect %s got %s", i, name, root.Syscalls[i].Name)
+		}
+	}
+	if string(root.Syscalls[1].Value) != "v1" || string(root.Syscalls[4].Key) != "k0" {
+		t.Errorf("unexpected syscall result %v", root.Syscalls)
+	}
+	sub := root.Syscalls[2].Call
+	if sub.ContractName != "c2" || sub.Error != "sub failed" || len(sub.Syscalls) != 2 ||
+		sub.Syscalls[1].Event.GetName() != "e1" {
+		t.Fatalf("unexpected sub frame %v", sub)
+	}
+}
+
+func TestTraceSandboxTruncated(t *testing.T) {
+	mc := NewXModelCache(&contract.SandboxConfig{
+		XMReader: NewMemXModel(),
+	})
+	tracer := contract.NewTracer(100)
+	state := NewTraceSandbox(mc, tracer)
+
+	// 超过上限后丢弃系统调用和嵌套调用，顶层调用仍然记录
+	tracer.Enter("native", "c1", "invoke", nil)
+	state.Put("b1", []byte("k1"), make([]byte, 32))
+	state.Put("b1", []byte("k2"), make([]byte, 32))
+	tracer.Enter("native", "c2", "sub", nil)
+	tracer.Exit(nil, contract.Limits{}, nil)
+	tracer.Exit(nil, contract.Limits{}, nil)
+	tracer.Enter("native", "c3", "invoke", nil)
+	tracer.Exit(nil, contract.Limits{}, nil)
+
+	traces := tracer.Traces()
+	if !tracer.Truncated() || len(traces) != 2 || len(traces[0].Syscalls) != 1 {
+		t.Fatalf("unexpected truncated traces %v", traces)
+	}
+}
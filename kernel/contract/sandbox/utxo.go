@@ -42,3 +42,15 @@ func (r *UTXOReader) SelectUtxo(from string, amount *big.Int, lock bool, exclude
 	r.inputIdx += n
 	return inputCache[:n], nil, sum, nil
 }
+
+// Checkpoint 返回当前的读取位置
+func (r *UTXOReader) Checkpoint() int {
+	return r.inputIdx
+}
+
+// RevertToCheckpoint 回滚读取位置，被撤销的转账使用的输入可以重新被选择
+func (r *UTXOReader) RevertToCheckpoint(cp int) {
+	if cp >= 0 && cp <= r.inputIdx {
+		r.inputIdx = cp
+	}
+}
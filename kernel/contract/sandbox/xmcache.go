@@ -20,6 +20,8 @@ var (
 	ErrHasDel = errors.New("Key has been mark as del")
 	// ErrNotFound is returned when key is not found
 	ErrNotFound = errors.New("Key not found")
+	// ErrInvalidCheckpoint is returned when checkpoint is reverted or released
+	ErrInvalidCheckpoint = errors.New("invalid checkpoint")
 )
 
 var (
@@ -50,15 +52,32 @@ type XMCache struct {
 	utxoSandbox *utxo.UTXOSandbox
 	// crossQueryCache *CrossQueryCache
 	events []*protos.ContractEvent
+
+	checkpoints []xmCheckpoint
+
+	storageQuota       *contract.StorageQuota
+	contractVersion    bool
+	contractCallRevert bool
+}
+
+// xmCheckpoint 记录检查点时的写集日志位置、UTXO和事件数量
+// 读集不回滚，被撤销的调用读过的key仍然参与冲突检测
+type xmCheckpoint struct {
+	outputs int
+	utxo    utxo.UTXOCheckpoint
+	events  int
 }
 
 // NewXModelCache new an instance of XModel Cache
 func NewXModelCache(cfg *contract.SandboxConfig) *XMCache {
 	return &XMCache{
-		model:        cfg.XMReader,
-		inputsCache:  NewMemXModel(),
-		outputsCache: NewMemXModel(),
-		utxoSandbox:  utxo.NewUTXOSandbox(cfg),
+		model:              cfg.XMReader,
+		inputsCache:        NewMemXModel(),
+		outputsCache:       NewMemXModel(),
+		utxoSandbox:        utxo.NewUTXOSandbox(cfg),
+		storageQuota:       cfg.StorageQuota,
+		contractVersion:    cfg.ContractVersion,
+		contractCallRevert: cfg.ContractCallRevert,
 
 		// crossQueryCache: NewCrossQueryCache(),
 	}
@@ -152,18 +171,36 @@ func (xc *XMCache) Del(bucket string, key []byte) error {
 // Select select all kv from a bucket, can set key range, left closed, right opend
 // When xc.isPenetrate equals true, three-way merge, When xc.isPenetrate equals false, two-way merge
 func (xc *XMCache) Select(bucket string, startKey []byte, endKey []byte) (contract.Iterator, error) {
-	return xc.newXModelCacheIterator(bucket, startKey, endKey)
+	selectFunc := func(reader ledger.XMReader) (ledger.XMIterator, error) {
+		return reader.Select(bucket, startKey, endKey)
+	}
+	return xc.newXModelCacheIterator(bucket, selectFunc, false)
+}
+
+// SelectRange likes Select, but an empty endKey means the end of bucket and iterates
+// from the biggest key when reverse is true, keys read from backend are recorded to read set the same as Select
+func (xc *XMCache) SelectRange(bucket string, startKey []byte, endKey []byte, reverse bool) (contract.Iterator, error) {
+	selectFunc := func(reader ledger.XMReader) (ledger.XMIterator, error) {
+		return reader.SelectRange(bucket, startKey, endKey, reverse)
+	}
+	return xc.newXModelCacheIterator(bucket, selectFunc, reverse)
 }
 
 // newXModelCacheIterator new an instance of XModel Cache iterator
-func (mc *XMCache) newXModelCacheIterator(bucket string, startKey []byte, endKey []byte) (contract.Iterator, error) {
-	iter, _ := mc.outputsCache.Select(bucket, startKey, endKey)
+func (mc *XMCache) newXModelCacheIterator(bucket string, selectFunc func(ledger.XMReader) (ledger.XMIterator, error),
+	reverse bool) (contract.Iterator, error) {
+	mergeFunc := newMultiIterator
+	if reverse {
+		mergeFunc = newReverseMultiIterator
+	}
+
+	iter, _ := selectFunc(mc.outputsCache)
 	outputIter := iter
 
-	iter, _ = mc.inputsCache.Select(bucket, startKey, endKey)
+	iter, _ = selectFunc(mc.inputsCache)
 	inputIter := newStripDelIterator(iter)
 
-	backendIter, err := mc.model.Select(bucket, startKey, endKey)
+	backendIter, err := selectFunc(mc.model)
 	if err != nil {
 		return nil, err
 	}
@@ -174,8 +211,8 @@ func (mc *XMCache) newXModelCacheIterator(bucket string, startKey []byte, endKey
 
 	// 优先级顺序 outputIter -> inputIter -> backendIter
 	// 意味着如果一个key在三个迭代器里面同时出现，优先级高的会覆盖优先级底的
-	multiIter := newMultiIterator(inputIter, backendIter)
-	multiIter = newMultiIterator(outputIter, multiIter)
+	multiIter := mergeFunc(inputIter, backendIter)
+	multiIter = mergeFunc(outputIter, multiIter)
 	return newContractIterator(multiIter), nil
 }
 
@@ -217,7 +254,7 @@ func (xc *XMCache) Transfer(from, to string, amount *big.Int) error {
 	return xc.utxoSandbox.Transfer(from, to, amount)
 }
 
-//UTXORWSet returns the inputs and outputs of utxo
+// UTXORWSet returns the inputs and outputs of utxo
 func (xc *XMCache) UTXORWSet() *contract.UTXORWSet {
 	return xc.utxoSandbox.GetUTXORWSets()
 }
@@ -451,6 +488,40 @@ func (xc *XMCache) AddEvent(events ...*protos.ContractEvent) {
 	xc.events = append(xc.events, events...)
 }
 
+// Checkpoint 记录当前状态，返回的检查点可以嵌套
+func (xc *XMCache) Checkpoint() int {
+	xc.checkpoints = append(xc.checkpoints, xmCheckpoint{
+		outputs: xc.outputsCache.Checkpoint(),
+		utxo:    xc.utxoSandbox.Checkpoint(),
+		events:  len(xc.events),
+	})
+	return len(xc.checkpoints) - 1
+}
+
+// RevertToCheckpoint 撤销检查点之后的写入、转账和事件
+func (xc *XMCache) RevertToCheckpoint(cp int) error {
+	if cp < 0 || cp >= len(xc.checkpoints) {
+		return ErrInvalidCheckpoint
+	}
+	checkpoint := xc.checkpoints[cp]
+	xc.outputsCache.RevertToCheckpoint(checkpoint.outputs)
+	xc.utxoSandbox.RevertToCheckpoint(checkpoint.utxo)
+	xc.events = xc.events[:checkpoint.events]
+	xc.ReleaseCheckpoint(cp)
+	return nil
+}
+
+// ReleaseCheckpoint 保留检查点之后的修改，外层检查点回滚时仍会被撤销
+func (xc *XMCache) ReleaseCheckpoint(cp int) {
+	if cp < 0 || cp >= len(xc.checkpoints) {
+		return
+	}
+	xc.checkpoints = xc.checkpoints[:cp]
+	if len(xc.checkpoints) == 0 {
+		xc.outputsCache.DiscardJournal()
+	}
+}
+
 func (xc *XMCache) writeEventRWSet() error {
 	if len(xc.events) == 0 {
 		return nil
@@ -481,5 +552,20 @@ func (xc *XMCache) Flush() error {
 	if err != nil {
 		return err
 	}
+
+	err = xc.checkStorageQuota()
+	if err != nil {
+		return err
+	}
 	return nil
 }
+
+// ContractVersion 部署和升级合约时是否记录版本历史
+func (xc *XMCache) ContractVersion() bool {
+	return xc.contractVersion
+}
+
+// ContractCallRevert 被调合约失败时是否回滚其对状态的修改
+func (xc *XMCache) ContractCallRevert() bool {
+	return xc.contractCallRevert
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/xuperchain/xupercore/kernel/ledger"
+	"github.com/xuperchain/xupercore/protos"
 )
 
 func TestXMCachePutGet(t *testing.T) {
@@ -104,3 +105,113 @@ func TestXMCacheIterator(t *testing.T) {
 		t.Logf("%s", r.GetPureData().GetKey())
 	}
 }
+
+func TestXMCacheCheckpoint(t *testing.T) {
+	inputs := []*protos.TxInput{
+		{FromAddr: []byte("alice"), Amount: big.NewInt(10).Bytes()},
+		{FromAddr: []byte("alice"), Amount: big.NewInt(20).Bytes()},
+	}
+	mc := NewXModelCache(&contract.SandboxConfig{
+		XMReader:   NewMemXModel(),
+		UTXOReader: NewUTXOReaderFromInput(inputs),
+	})
+	mc.Put("test", []byte("k1"), []byte("v1"))
+
+	outer := mc.Checkpoint()
+	mc.Put("test", []byte("k1"), []byte("v2"))
+	mc.Put("test", []byte("k2"), []byte("v2"))
+	mc.AddEvent(&protos.ContractEvent{Name: "e1"})
+	if err := mc.Transfer("alice", "bob", big.NewInt(5)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 内层回滚只撤销内层的修改
+	inner := mc.Checkpoint()
+	mc.Put("test", []byte("k3"), []byte("v3"))
+	if err := mc.RevertToCheckpoint(inner); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mc.Get("test", []byte("k3")); err != ErrNotFound {
+		t.Fatalf("expect k3 reverted, got %v", err)
+	}
+	if v, _ := mc.Get("test", []byte("k2")); string(v) != "v2" {
+		t.Fatalf("expect k2 kept, got %s", v)
+	}
+	if err := mc.RevertToCheckpoint(inner); err != ErrInvalidCheckpoint {
+		t.Fatalf("expect invalid checkpoint, got %v", err)
+	}
+
+	if err := mc.RevertToCheckpoint(outer); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := mc.Get("test", []byte("k1")); string(v) != "v1" {
+		t.Fatalf("expect k1 reverted to v1, got %s", v)
+	}
+	if _, err := mc.Get("test", []byte("k2")); err != ErrNotFound {
+		t.Fatalf("expect k2 reverted, got %v", err)
+	}
+	if len(mc.events) != 0 || len(mc.UTXORWSet().Rset) != 0 || len(mc.UTXORWSet().WSet) != 0 {
+		t.Fatal("expect events and utxo reverted")
+	}
+
+	// 回滚后被撤销转账的输入可以重新使用，释放的检查点保留修改
+	cp := mc.Checkpoint()
+	if err := mc.Transfer("alice", "bob", big.NewInt(15)); err != nil {
+		t.Fatal(err)
+	}
+	mc.ReleaseCheckpoint(cp)
+	utxoRWSet := mc.UTXORWSet()
+	if len(utxoRWSet.Rset) != 2 || len(utxoRWSet.WSet) != 2 {
+		t.Fatalf("unexpected utxo rwset: %v", utxoRWSet)
+	}
+	if len(mc.RWSet().WSet) != 1 {
+		t.Fatalf("unexpected write set: %v", mc.RWSet().WSet)
+	}
+}
+
+func TestXMCacheReverseIterator(t *testing.T) {
+	store := NewMemXModel()
+	putVersionedData(store, "test", []byte("k1"), []byte("v1"))
+	putVersionedData(store, "test", []byte("k3"), []byte("v3"))
+	putVersionedData(store, "test", []byte("k5"), []byte("v5"))
+
+	mc := NewXModelCache(&contract.SandboxConfig{
+		XMReader: store,
+	})
+	mc.Put("test", []byte("k4"), []byte("v4"))
+	mc.Del("test", []byte("k3"))
+
+	iter, err := mc.SelectRange("test", nil, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for iter.Next() {
+		if IsDelFlag(iter.Value()) {
+			continue
+		}
+		keys = append(keys, string(iter.Key()))
+	}
+	iter.Close()
+	if len(keys) != 3 || keys[0] != "k5" || keys[1] != "k4" || keys[2] != "k1" {
+		t.Fatalf("unexpected keys %v", keys)
+	}
+
+	// 迭代到的key都记录在读集中，用读集重新执行得到相同的结果
+	reader := XMReaderFromRWSet(mc.RWSet())
+	verify := NewXModelCache(&contract.SandboxConfig{
+		XMReader: reader,
+	})
+	iter, err = verify.SelectRange("test", []byte("k2"), nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys = keys[:0]
+	for iter.Next() {
+		keys = append(keys, string(iter.Key()))
+	}
+	iter.Close()
+	if len(keys) != 2 || keys[0] != "k5" || keys[1] != "k3" {
+		t.Fatalf("unexpected keys from read set %v", keys)
+	}
+}
@@ -9,6 +9,20 @@ import (
 type SandboxConfig struct {
 	XMReader   ledger.XMReader
 	UTXOReader UtxoReader
+	// StorageQuota 不为nil时Flush检查合约存储配额
+	StorageQuota *StorageQuota
+	// ContractVersion 部署和升级合约时记录版本历史
+	ContractVersion bool
+	// ContractCallRevert 被调合约失败时回滚其对状态的修改
+	ContractCallRevert bool
+}
+
+// StorageQuota 合约存储计量配置
+type StorageQuota struct {
+	// Default 未通过提案单独设置配额的合约可使用的字节数，0表示不限制
+	Default int64
+	// Usage 返回合约已确认的存储用量，为nil时按0计算
+	Usage func(contractName string) (int64, error)
 }
 type UtxoReader interface {
 	SelectUtxo(string, *big.Int, bool, bool) ([]*protos.TxInput, [][]byte, *big.Int, error)
@@ -30,6 +44,8 @@ type XMState interface {
 	Get(bucket string, key []byte) ([]byte, error)
 	//扫描一个bucket中所有的kv, 调用者可以设置key区间[startKey, endKey)
 	Select(bucket string, startKey []byte, endKey []byte) (Iterator, error)
+	//与Select相同，endKey为空时扫描到bucket的最后一个元素，reverse为true时按照key从大到小的顺序扫描
+	SelectRange(bucket string, startKey []byte, endKey []byte, reverse bool) (Iterator, error)
 	Put(bucket string, key, value []byte) error
 	Del(bucket string, key []byte) error
 }
@@ -63,6 +79,16 @@ type StateSandbox interface {
 	Flush() error
 	RWSet() *RWSet
 	UTXORWSet() *UTXORWSet
+	// Checkpoint 记录当前的写集、UTXO和事件，返回的检查点用于回滚，支持嵌套
+	Checkpoint() int
+	// RevertToCheckpoint 撤销检查点之后的修改，检查点及之后的检查点失效
+	RevertToCheckpoint(cp int) error
+	// ReleaseCheckpoint 保留检查点之后的修改，检查点及之后的检查点失效
+	ReleaseCheckpoint(cp int)
+	// ContractVersion 部署和升级合约时是否记录版本历史
+	ContractVersion() bool
+	// ContractCallRevert 被调合约失败时是否回滚其对状态的修改
+	ContractCallRevert() bool
 }
 
 type RWSet struct {
@@ -0,0 +1,159 @@
+package contract
+
+import (
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+const (
+	SyscallGet           = "Get"
+	SyscallPut           = "Put"
+	SyscallDel           = "Del"
+	SyscallSelect        = "Select"
+	SyscallReverseSelect = "ReverseSelect"
+	SyscallIteratorNext  = "IteratorNext"
+	SyscallTransfer      = "Transfer"
+	SyscallEmitEvent     = "EmitEvent"
+	SyscallContractCall  = "ContractCall"
+)
+
+// Tracer 记录预执行过程中合约的调用树、系统调用和每层调用的资源消耗，
+// 没有进入任何合约调用时产生的系统调用不会被记录。
+// 记录的大小超过上限后丢弃之后的嵌套调用和系统调用，调用帧仍然入栈以保持Enter和Exit配对。
+// nil Tracer的所有方法都是空操作，调用方不需要判断是否开启了trace
+type Tracer struct {
+	mutex     sync.Mutex
+	traces    []*protos.ContractTrace
+	frames    []*protos.ContractTrace
+	maxSize   int64
+	size      int64
+	truncated bool
+}
+
+// NewTracer returns a new Tracer, maxSize为记录的字节数上限，0表示不限制
+func NewTracer(maxSize int64) *Tracer {
+	return &Tracer{
+		maxSize: maxSize,
+	}
+}
+
+// reserve 记录size字节前检查上限，超过上限时标记截断
+func (t *Tracer) reserve(size int) bool {
+	if t.truncated {
+		return false
+	}
+	if t.maxSize > 0 && t.size+int64(size) > t.maxSize {
+		t.truncated = true
+		return false
+	}
+	t.size += int64(size)
+	return true
+}
+
+// Enter 进入一次合约调用，嵌套调用作为ContractCall记录在调用方的系统调用中
+func (t *Tracer) Enter(module, contractName, method string, args map[string][]byte) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	frame := &protos.ContractTrace{
+		ModuleName:   module,
+		ContractName: contractName,
+		MethodName:   method,
+		Args:         args,
+	}
+	t.frames = append(t.frames, frame)
+	// 顶层调用总是记录，以便与请求对应
+	if len(t.frames) == 1 {
+		t.size += int64(proto.Size(frame))
+		t.traces = append(t.traces, frame)
+	} else if t.reserve(proto.Size(frame)) {
+		parent := t.frames[len(t.frames)-2]
+		parent.Syscalls = append(parent.Syscalls, &protos.SyscallTrace{
+			Name: SyscallContractCall,
+			Call: frame,
+		})
+	}
+}
+
+// Exit 结束当前的合约调用，记录执行结果和资源消耗
+func (t *Tracer) Exit(resp *Response, used Limits, err error) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if len(t.frames) == 0 {
+		return
+	}
+	frame := t.frames[len(t.frames)-1]
+	t.frames = t.frames[:len(t.frames)-1]
+	frame.ResourceUsed = ToPbLimits(used)
+	if resp != nil {
+		frame.Status = int32(resp.Status)
+		frame.Message = resp.Message
+	}
+	if err != nil {
+		frame.Error = err.Error()
+	}
+}
+
+// AddSyscall 在当前的合约调用中记录一次系统调用
+func (t *Tracer) AddSyscall(call *protos.SyscallTrace) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if len(t.frames) == 0 || !t.reserve(proto.Size(call)) {
+		return
+	}
+	frame := t.frames[len(t.frames)-1]
+	frame.Syscalls = append(frame.Syscalls, call)
+}
+
+// AddEvmCall 在当前的合约调用中记录一次evm内部调用
+func (t *Tracer) AddEvmCall(line string) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if len(t.frames) == 0 || !t.reserve(len(line)) {
+		return
+	}
+	frame := t.frames[len(t.frames)-1]
+	frame.EvmCalls = append(frame.EvmCalls, line)
+}
+
+// Enabled 是否开启了trace
+func (t *Tracer) Enabled() bool {
+	return t != nil
+}
+
+// Traces 按执行顺序返回所有顶层合约调用的轨迹
+func (t *Tracer) Traces() []*protos.ContractTrace {
+	if t == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.traces
+}
+
+// Truncated 轨迹是否因超过大小上限被截断
+func (t *Tracer) Truncated() bool {
+	if t == nil {
+		return false
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.truncated
+}
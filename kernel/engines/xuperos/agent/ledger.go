@@ -82,9 +82,10 @@ func (t *LedgerAgent) GetConsensusConf() ([]byte, error) {
 }
 
 // 查询区块
+// 共识只使用区块头，交易已被裁剪的区块同样返回
 func (t *LedgerAgent) QueryBlock(blkId []byte) (kledger.BlockHandle, error) {
 	block, err := t.chainCtx.Ledger.QueryBlock(blkId)
-	if err != nil {
+	if err != nil && err != ledger.ErrBlockPruned {
 		return nil, err
 	}
 
@@ -93,7 +94,7 @@ func (t *LedgerAgent) QueryBlock(blkId []byte) (kledger.BlockHandle, error) {
 
 func (t *LedgerAgent) QueryBlockByHeight(height int64) (kledger.BlockHandle, error) {
 	block, err := t.chainCtx.Ledger.QueryBlockByHeight(height)
-	if err != nil {
+	if err != nil && err != ledger.ErrBlockPruned {
 		return nil, err
 	}
 
@@ -7,7 +7,9 @@ import (
 	"github.com/xuperchain/xupercore/lib/logs"
 	"github.com/xuperchain/xupercore/lib/timer"
 
+	lconf "github.com/xuperchain/xupercore/bcs/ledger/xledger/config"
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/snapshot"
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
 	statctx "github.com/xuperchain/xupercore/bcs/ledger/xledger/state/context"
 	xconf "github.com/xuperchain/xupercore/kernel/common/xconfig"
@@ -97,6 +99,23 @@ func (t *ChainRelyAgentImpl) CreateState(leg *ledger.Ledger,
 	return stat, nil
 }
 
+// 创建状态快照生成器
+func (t *ChainRelyAgentImpl) CreateSnapshot(leg *ledger.Ledger, stat *state.State) (*snapshot.Producer, error) {
+	ctx := t.chain.Context()
+	envcfg := ctx.EngCtx.EnvCfg
+	lcfg, err := lconf.LoadLedgerConf(envcfg.GenConfFilePath(envcfg.LedgerConf))
+	if err != nil {
+		return nil, fmt.Errorf("load ledger conf failed.err:%v", err)
+	}
+
+	log, err := logs.NewLogger("", "snapshot")
+	if err != nil {
+		return nil, fmt.Errorf("create snapshot failed because new logger error.err:%v", err)
+	}
+	dir := filepath.Join(envcfg.GenDataAbsPath(envcfg.ChainDir), ctx.BCName, snapshot.DirName)
+	return snapshot.NewProducer(ctx.BCName, leg, stat, &lcfg.Snapshot, dir, log), nil
+}
+
 // 加密
 func (t *ChainRelyAgentImpl) CreateCrypto(cryptoType string) (cryptoBase.CryptoClient, error) {
 	crypto, err := cryptoClient.CreateCryptoClient(cryptoType)
@@ -14,6 +14,9 @@ import (
 	"github.com/xuperchain/xupercore/kernel/common/xaddress"
 	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
 	"github.com/xuperchain/xupercore/kernel/contract"
+	"github.com/xuperchain/xupercore/kernel/contract/abi"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge"
+	"github.com/xuperchain/xupercore/kernel/contract/sandbox"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/agent"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/miner"
@@ -103,6 +106,7 @@ func (t *Chain) Start() {
 func (t *Chain) Stop() {
 	// 停止矿工等其余组件
 	t.miner.Stop()
+	t.ctx.Snapshot.Close()
 	t.ctx.Ledger.Close()
 	t.ctx.State.Close()
 	t.ctx = nil
@@ -137,17 +141,34 @@ func (t *Chain) PreExec(ctx xctx.XContext, reqs []*protos.InvokeRequest, initiat
 	}
 
 	stateConfig := &contract.SandboxConfig{
-		XMReader:   t.ctx.State.CreateXMReader(),
-		UTXOReader: t.ctx.State.CreateUtxoReader(),
+		XMReader:           t.ctx.State.CreateXMReader(),
+		UTXOReader:         t.ctx.State.CreateUtxoReader(),
+		StorageQuota:       t.ctx.State.StorageQuota(),
+		ContractVersion:    t.ctx.State.ContractVersionEnabled(),
+		ContractCallRevert: t.ctx.State.ContractCallRevertEnabled(),
 	}
-	sandbox, err := t.ctx.Contract.NewStateSandbox(stateConfig)
+	stateSandbox, err := t.ctx.Contract.NewStateSandbox(stateConfig)
 	if err != nil {
 		t.log.Error("PreExec new state sandbox error", "error", err)
 		return nil, common.ErrContractNewSandboxFailed
 	}
+	// 任一请求开启trace时记录系统调用，未开启trace的请求没有调用帧，不会被记录
+	var tracer *contract.Tracer
+	engCfg := t.ctx.EngCtx.EngCfg
+	for _, req := range reqs {
+		if !req.GetTrace() {
+			continue
+		}
+		if !engCfg.ContractTrace {
+			return nil, common.ErrParameter.More("contract trace is disabled")
+		}
+		tracer = contract.NewTracer(engCfg.ContractTraceMaxSize)
+		break
+	}
+	stateSandbox = sandbox.NewTraceSandbox(stateSandbox, tracer)
 
 	contextConfig := &contract.ContextConfig{
-		State:          sandbox,
+		State:          stateSandbox,
 		Initiator:      initiator,
 		AuthRequire:    authRequires,
 		ResourceLimits: contract.MaxLimits,
@@ -176,6 +197,10 @@ func (t *Chain) PreExec(ctx xctx.XContext, reqs []*protos.InvokeRequest, initiat
 		} else {
 			contextConfig.TransferAmount = ""
 		}
+		contextConfig.Tracer = nil
+		if req.GetTrace() {
+			contextConfig.Tracer = tracer
+		}
 
 		context, err := t.ctx.Contract.NewContext(contextConfig)
 		if err != nil {
@@ -187,19 +212,26 @@ func (t *Chain) PreExec(ctx xctx.XContext, reqs []*protos.InvokeRequest, initiat
 			return nil, common.ErrContractNewCtxFailed.More("%v", err)
 		}
 
-		resp, err := context.Invoke(req.MethodName, req.Args)
+		args, typedAbi, err := t.encodeAbiArgs(req)
+		if err != nil {
+			context.Release()
+			ctx.GetLog().Error("PreExec encode args error", "error", err, "contractName", req.ContractName)
+			return nil, common.ErrParameter.More("%v", err)
+		}
+
+		resp, err := context.Invoke(req.MethodName, args)
 		if err != nil {
 			context.Release()
 			ctx.GetLog().Error("PreExec Invoke error", "error", err, "contractName", req.ContractName)
 			metrics.ContractInvokeCounter.WithLabelValues(t.ctx.BCName, req.ModuleName, req.ContractName, req.MethodName, "InvokeError").Inc()
-			return nil, common.ErrContractInvokeFailed.More("%v", err)
+			return tracedResponse(tracer), common.ErrContractInvokeFailed.More("%v", err)
 		}
 
 		if resp.Status >= 400 && i < len(reservedRequests) {
 			context.Release()
 			ctx.GetLog().Error("PreExec Invoke error", "status", resp.Status, "contractName", req.ContractName)
 			metrics.ContractInvokeCounter.WithLabelValues(t.ctx.BCName, req.ModuleName, req.ContractName, req.MethodName, "InvokeError").Inc()
-			return nil, common.ErrContractInvokeFailed.More("%v", resp.Message)
+			return tracedResponse(tracer), common.ErrContractInvokeFailed.More("%v", resp.Message)
 		}
 
 		metrics.ContractInvokeCounter.WithLabelValues(t.ctx.BCName, req.ModuleName, req.ContractName, req.MethodName, "OK").Inc()
@@ -210,10 +242,21 @@ func (t *Chain) PreExec(ctx xctx.XContext, reqs []*protos.InvokeRequest, initiat
 
 		// request
 		request := *req
+		request.Args = args
 		request.ResourceLimits = contract.ToPbLimits(resourceUsed)
+		request.Trace = false
 		requests = append(requests, &request)
 
 		// response
+		if typedAbi != nil && resp.Status < 400 {
+			body, err := typedAbi.DecodeResponse(req.MethodName, resp.Body)
+			if err != nil {
+				context.Release()
+				ctx.GetLog().Error("PreExec decode response error", "error", err, "contractName", req.ContractName)
+				return nil, common.ErrContractInvokeFailed.More("%v", err)
+			}
+			resp.Body = body
+		}
 		response := &protos.ContractResponse{
 			Status:  int32(resp.Status),
 			Message: resp.Message,
@@ -226,27 +269,61 @@ func (t *Chain) PreExec(ctx xctx.XContext, reqs []*protos.InvokeRequest, initiat
 		metrics.ContractInvokeHistogram.WithLabelValues(t.ctx.BCName, req.ModuleName, req.ContractName, req.MethodName).Observe(time.Since(beginTime).Seconds())
 	}
 
-	err = sandbox.Flush()
+	err = stateSandbox.Flush()
 	if err != nil {
 		return nil, err
 	}
-	rwSet := sandbox.RWSet()
-	utxoRWSet := sandbox.UTXORWSet()
+	rwSet := stateSandbox.RWSet()
+	utxoRWSet := stateSandbox.UTXORWSet()
 
 	invokeResponse := &protos.InvokeResponse{
-		GasUsed:     gasUsed,
-		Response:    responseBodes,
-		Inputs:      xmodel.GetTxInputs(rwSet.RSet),
-		Outputs:     xmodel.GetTxOutputs(rwSet.WSet),
-		Requests:    requests,
-		Responses:   responses,
-		UtxoInputs:  utxoRWSet.Rset,
-		UtxoOutputs: utxoRWSet.WSet,
+		GasUsed:         gasUsed,
+		Response:        responseBodes,
+		Inputs:          xmodel.GetTxInputs(rwSet.RSet),
+		Outputs:         xmodel.GetTxOutputs(rwSet.WSet),
+		Requests:        requests,
+		Responses:       responses,
+		UtxoInputs:      utxoRWSet.Rset,
+		UtxoOutputs:     utxoRWSet.WSet,
+		Traces:          tracer.Traces(),
+		TracesTruncated: tracer.Truncated(),
 	}
 
 	return invokeResponse, nil
 }
 
+// encodeAbiArgs jsonEncoded为true时按合约的类型化ABI将json格式的参数编码为合约参数，
+// 交易中保存编码后的参数，evm合约由虚拟机按solidity ABI编码
+func (t *Chain) encodeAbiArgs(req *protos.InvokeRequest) (map[string][]byte, *abi.ABI, error) {
+	if req.ModuleName == string(bridge.TypeEvm) || string(req.Args[abi.ArgJSONEncoded]) != "true" {
+		return req.Args, nil, nil
+	}
+	contractAbi, err := t.ctx.State.QueryContractAbi(req.ContractName)
+	if err != nil {
+		return nil, nil, err
+	}
+	typedAbi, err := abi.Parse(contractAbi.GetAbi())
+	if err != nil {
+		return nil, nil, err
+	}
+	args, err := typedAbi.EncodeArgs(req.MethodName, req.Args[abi.ArgInput])
+	if err != nil {
+		return nil, nil, err
+	}
+	return args, typedAbi, nil
+}
+
+// tracedResponse 开启trace的预执行失败时仍然返回已记录的执行轨迹，便于定位错误
+func tracedResponse(tracer *contract.Tracer) *protos.InvokeResponse {
+	if !tracer.Enabled() {
+		return nil
+	}
+	return &protos.InvokeResponse{
+		Traces:          tracer.Traces(),
+		TracesTruncated: tracer.Truncated(),
+	}
+}
+
 // 提交交易到交易池(xuperos引擎同时更新到状态机和交易池)
 func (t *Chain) SubmitTx(ctx xctx.XContext, tx *lpb.Transaction) error {
 	if tx == nil || ctx == nil || ctx.GetLog() == nil || len(tx.GetTxid()) <= 0 {
@@ -355,6 +432,14 @@ func (t *Chain) initChainCtx() error {
 	t.ctx.State = stat
 	t.log.Trace("open state succ", "bcName", t.ctx.BCName)
 
+	// 状态快照
+	snap, err := t.relyAgent.CreateSnapshot(leg, stat)
+	if err != nil {
+		t.log.Error("create snapshot failed", "bcName", t.ctx.BCName, "err", err)
+		return fmt.Errorf("create snapshot failed")
+	}
+	t.ctx.Snapshot = snap
+
 	// 4.加载节点账户信息
 	keyPath := t.ctx.EngCtx.EnvCfg.GenDataAbsPath(t.ctx.EngCtx.EnvCfg.KeyDir)
 	addr, err := xaddress.LoadAddrInfo(keyPath, t.ctx.Crypto)
@@ -16,4 +16,16 @@ const (
 	// 出块节点将新块用Full_BroadCast_Mode模式广播
 	// 其他节点使用Interactive_BroadCast_Mode模式广播区块
 	MixedBroadCastMode
+	// 紧凑块广播模式，即广播区块头和交易短ID给相邻节点
+	// 邻节点根据本地未确认交易重建区块，只向发送方获取缺失的交易
+	CompactBroadCastMode
+)
+
+// 交易广播模式
+const (
+	// 完全交易广播模式，即直接广播完整交易给相邻节点
+	FullTxBroadCastMode = iota
+	// 交易清单广播模式，即批量广播交易ID给相邻节点
+	// 邻节点通过GET_TXS只获取本地未知的交易，交易内容通过BATCHPOSTTX送达
+	InventoryTxBroadCastMode
 )
@@ -3,6 +3,7 @@ package common
 
 import (
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/snapshot"
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
 	"github.com/xuperchain/xupercore/kernel/common/xaddress"
 	xconf "github.com/xuperchain/xupercore/kernel/common/xconfig"
@@ -44,6 +45,8 @@ type ChainCtx struct {
 	Ledger *ledger.Ledger
 	// 状态机
 	State *state.State
+	// 状态快照
+	Snapshot *snapshot.Producer
 	// 合约
 	Contract contract.Manager
 	// 共识
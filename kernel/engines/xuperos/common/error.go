@@ -95,6 +95,7 @@ var (
 	ErrTxNotEnough           = &Error{ErrStatusInternalErr, 50403, "tx not enough"}
 	ErrSubmitTxFailed        = &Error{ErrStatusInternalErr, 50404, "submit tx failed"}
 	ErrGenerateTimerTxFailed = &Error{ErrStatusInternalErr, 50405, "generate timer tx failed"}
+	ErrEstimateFeeFailed     = &Error{ErrStatusInternalErr, 50406, "estimate fee failed"}
 
 	// contract
 	ErrContractNewCtxFailed     = &Error{ErrStatusInternalErr, 50500, "contract new context failed"}
@@ -109,4 +110,8 @@ var (
 
 	// consensus
 	ErrConsensusStatus = &Error{ErrStatusInternalErr, 50701, "consensus status error"}
+
+	// snapshot
+	ErrSnapshotNotExist = &Error{ErrStatusInternalErr, 50800, "snapshot not exist"}
+	ErrFastSyncFailed   = &Error{ErrStatusInternalErr, 50801, "fast sync failed"}
 )
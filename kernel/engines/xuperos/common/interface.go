@@ -2,6 +2,7 @@ package common
 
 import (
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/snapshot"
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
 	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	xconf "github.com/xuperchain/xupercore/kernel/common/xconfig"
@@ -54,6 +55,7 @@ type EngineRelyAgent interface {
 type ChainRelyAgent interface {
 	CreateLedger() (*ledger.Ledger, error)
 	CreateState(*ledger.Ledger, cryptoBase.CryptoClient) (*state.State, error)
+	CreateSnapshot(*ledger.Ledger, *state.State) (*snapshot.Producer, error)
 	CreateContract(kledger.XMReader) (contract.Manager, error)
 	CreateConsensus() (consensus.ConsensusInterface, error)
 	CreateCrypto(cryptoType string) (cryptoBase.CryptoClient, error)
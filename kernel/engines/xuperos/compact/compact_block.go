@@ -0,0 +1,109 @@
+// 紧凑块的构造与重建，供出块和网络转发共用
+package compact
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+)
+
+const (
+	// 交易短ID长度，取txid前缀
+	ShortTxIDLen = 8
+)
+
+var (
+	ErrBlockInvalid       = errors.New("compact block invalid")
+	ErrTxsMissing         = errors.New("compact block txs missing")
+	ErrMerkleRootMismatch = errors.New("compact block merkle root mismatch")
+)
+
+// NewCompactBlock 生成紧凑块，只保留区块头和交易短ID
+// coinbase和autogen交易不会出现在对方的交易池中，直接随紧凑块发送
+func NewCompactBlock(block *lpb.InternalBlock) *xpb.CompactBlock {
+	header := *block
+	header.Transactions = nil
+	header.MerkleTree = nil
+
+	cb := &xpb.CompactBlock{
+		Header:     &header,
+		ShortTxids: make([][]byte, 0, len(block.Transactions)),
+	}
+	for _, tx := range block.Transactions {
+		cb.ShortTxids = append(cb.ShortTxids, ShortTxID(tx.GetTxid()))
+		if tx.GetCoinbase() || tx.GetAutogen() {
+			cb.PrefilledTxs = append(cb.PrefilledTxs, tx)
+		}
+	}
+
+	return cb
+}
+
+// ShortTxID 交易短ID
+func ShortTxID(txid []byte) []byte {
+	if len(txid) <= ShortTxIDLen {
+		return txid
+	}
+	return txid[:ShortTxIDLen]
+}
+
+// MatchTxs 用本地候选交易和预填充交易匹配交易短ID，返回按区块顺序排列的交易及缺失交易的下标
+// 多个候选交易的短ID相同时无法区分，按缺失处理
+func MatchTxs(cb *xpb.CompactBlock, candidates []*lpb.Transaction) ([]*lpb.Transaction, []uint32, error) {
+	if cb.GetHeader() == nil || int(cb.GetHeader().GetTxCount()) != len(cb.GetShortTxids()) {
+		return nil, nil, ErrBlockInvalid
+	}
+
+	pool := make(map[string]*lpb.Transaction, len(candidates))
+	collided := make(map[string]bool)
+	for _, tx := range candidates {
+		key := string(ShortTxID(tx.GetTxid()))
+		if prev, ok := pool[key]; ok && !bytes.Equal(prev.GetTxid(), tx.GetTxid()) {
+			collided[key] = true
+		}
+		pool[key] = tx
+	}
+	for _, tx := range cb.GetPrefilledTxs() {
+		key := string(ShortTxID(tx.GetTxid()))
+		pool[key] = tx
+		delete(collided, key)
+	}
+
+	txs := make([]*lpb.Transaction, len(cb.GetShortTxids()))
+	missing := make([]uint32, 0)
+	for i, shortID := range cb.GetShortTxids() {
+		key := string(shortID)
+		if tx, ok := pool[key]; ok && !collided[key] {
+			txs[i] = tx
+			continue
+		}
+		missing = append(missing, uint32(i))
+	}
+
+	return txs, missing, nil
+}
+
+// Assemble 用完整的交易列表还原区块，并校验默克尔根
+func Assemble(cb *xpb.CompactBlock, txs []*lpb.Transaction) (*lpb.InternalBlock, error) {
+	header := cb.GetHeader()
+	if header == nil || int(header.GetTxCount()) != len(txs) {
+		return nil, ErrBlockInvalid
+	}
+	for _, tx := range txs {
+		if tx == nil {
+			return nil, ErrTxsMissing
+		}
+	}
+
+	block := *header
+	block.Transactions = txs
+	block.MerkleTree = ledger.MakeMerkleTree(txs)
+	if len(block.MerkleTree) == 0 || !bytes.Equal(block.MerkleTree[len(block.MerkleTree)-1], header.GetMerkleRoot()) {
+		return nil, ErrMerkleRootMismatch
+	}
+
+	return &block, nil
+}
@@ -0,0 +1,109 @@
+package compact
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+)
+
+func mockBlock(txs ...*lpb.Transaction) *lpb.InternalBlock {
+	tree := ledger.MakeMerkleTree(txs)
+	return &lpb.InternalBlock{
+		Blockid:      []byte("blockid"),
+		Height:       1,
+		TxCount:      int32(len(txs)),
+		Transactions: txs,
+		MerkleTree:   tree,
+		MerkleRoot:   tree[len(tree)-1],
+	}
+}
+
+func mockTx(txid string) *lpb.Transaction {
+	return &lpb.Transaction{Txid: []byte(txid)}
+}
+
+func TestBuildAndAssemble(t *testing.T) {
+	award := &lpb.Transaction{Txid: []byte("award-tx-0000000"), Coinbase: true}
+	tx1 := mockTx("tx1-aaaaaaaaaaaa")
+	tx2 := mockTx("tx2-bbbbbbbbbbbb")
+	block := mockBlock(award, tx1, tx2)
+
+	cb := NewCompactBlock(block)
+	if cb.GetHeader().GetTransactions() != nil || cb.GetHeader().GetMerkleTree() != nil {
+		t.Fatal("compact block header should not carry txs")
+	}
+	if len(cb.GetShortTxids()) != 3 || !bytes.Equal(cb.GetShortTxids()[1], []byte("tx1-aaaa")) {
+		t.Fatalf("unexpected short txids: %q", cb.GetShortTxids())
+	}
+	if len(cb.GetPrefilledTxs()) != 1 || cb.GetPrefilledTxs()[0] != award {
+		t.Fatal("coinbase tx should be prefilled")
+	}
+
+	// 交易池中有全部交易
+	txs, missing, err := MatchTxs(cb, []*lpb.Transaction{tx2, tx1, mockTx("other")})
+	if err != nil || len(missing) != 0 {
+		t.Fatalf("match txs error: %v, missing %v", err, missing)
+	}
+	rebuilt, err := Assemble(cb, txs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rebuilt.GetMerkleRoot(), block.GetMerkleRoot()) || len(rebuilt.Transactions) != 3 ||
+		rebuilt.Transactions[1] != tx1 || rebuilt.Transactions[2] != tx2 {
+		t.Fatal("rebuilt block mismatch")
+	}
+
+	// 交易池中缺少交易
+	txs, missing, err = MatchTxs(cb, []*lpb.Transaction{tx1})
+	if err != nil || len(missing) != 1 || missing[0] != 2 {
+		t.Fatalf("unexpected missing: %v, %v", missing, err)
+	}
+	if _, err := Assemble(cb, txs); err != ErrTxsMissing {
+		t.Errorf("assemble with missing txs should fail: %v", err)
+	}
+	txs[2] = tx2
+	if _, err := Assemble(cb, txs); err != nil {
+		t.Errorf("assemble with fetched txs error: %v", err)
+	}
+
+	// 交易数与短ID数不一致
+	cb.Header.TxCount = 2
+	if _, _, err := MatchTxs(cb, nil); err != ErrBlockInvalid {
+		t.Errorf("invalid compact block should be rejected: %v", err)
+	}
+}
+
+func TestShortIDCollision(t *testing.T) {
+	tx1 := mockTx("collide-11111111")
+	tx2 := mockTx("collide-22222222")
+	block := mockBlock(tx1)
+	cb := NewCompactBlock(block)
+
+	// 交易池中两笔交易短ID相同，无法区分时按缺失处理
+	txs, missing, err := MatchTxs(cb, []*lpb.Transaction{tx1, tx2})
+	if err != nil || len(missing) != 1 || txs[0] != nil {
+		t.Fatalf("collided short id should be missing: %v, %v", missing, err)
+	}
+
+	// 交易池中只有短ID相同的其他交易，默克尔根校验失败
+	txs, missing, err = MatchTxs(cb, []*lpb.Transaction{tx2})
+	if err != nil || len(missing) != 0 {
+		t.Fatalf("match txs error: %v, %v", missing, err)
+	}
+	if _, err := Assemble(cb, txs); err != ErrMerkleRootMismatch {
+		t.Errorf("wrong tx should fail merkle root check: %v", err)
+	}
+
+	// 预填充交易优先于交易池
+	award := &lpb.Transaction{Txid: []byte("collide-33333333"), Coinbase: true}
+	cb = NewCompactBlock(mockBlock(award))
+	txs, missing, err = MatchTxs(cb, []*lpb.Transaction{tx1, tx2})
+	if err != nil || len(missing) != 0 || txs[0] != award {
+		t.Fatalf("prefilled tx should be used: %v, %v", missing, err)
+	}
+	if _, err := Assemble(cb, txs); err != nil {
+		t.Errorf("assemble prefilled block error: %v", err)
+	}
+}
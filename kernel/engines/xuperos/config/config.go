@@ -25,6 +25,23 @@ type EngineConf struct {
 	TxIdCacheGCInterval time.Duration `yaml:"txIdCacheGCInterval,omitempty"`
 	// MaxBlockQueueSize the queue size of the processing block
 	MaxBlockQueueSize int64 `yaml:"maxBlockQueueSize,omitempty"`
+	// TxBroadcastMode is the mode for broadcast new tx
+	TxBroadcastMode uint8 `yaml:"txBroadcastMode,omitempty"`
+	// TxAnnounceInterval interval for announcing batched tx ids
+	TxAnnounceInterval time.Duration `yaml:"txAnnounceInterval,omitempty"`
+	// TxAnnounceBatchSize max tx ids in one announcement
+	TxAnnounceBatchSize int `yaml:"txAnnounceBatchSize,omitempty"`
+	// FastSync new node downloads state snapshot from peers instead of replaying from genesis
+	FastSync bool `yaml:"fastSync,omitempty"`
+	// FastSyncQuorum min number of validators that must serve the same snapshot manifest, at least 2f+1 of validators,
+	// manifests are only requested from validators of local consensus
+	FastSyncQuorum int `yaml:"fastSyncQuorum,omitempty"`
+	// FastSyncTimeout max time waiting for enough peers
+	FastSyncTimeout time.Duration `yaml:"fastSyncTimeout,omitempty"`
+	// ContractTrace allow pre-exec requests to return contract execution traces
+	ContractTrace bool `yaml:"contractTrace,omitempty"`
+	// ContractTraceMaxSize max bytes of traces recorded in one pre-exec, later records are dropped
+	ContractTraceMaxSize int64 `yaml:"contractTraceMaxSize,omitempty"`
 }
 
 func LoadEngineConf(cfgFile string) (*EngineConf, error) {
@@ -44,6 +61,14 @@ func GetDefEngineConf() *EngineConf {
 		TxIdCacheExpiredTime: 180 * time.Second,
 		TxIdCacheGCInterval:  300 * time.Second,
 		MaxBlockQueueSize:    100,
+		TxBroadcastMode:      0,
+		TxAnnounceInterval:   500 * time.Millisecond,
+		TxAnnounceBatchSize:  1000,
+		FastSync:             false,
+		FastSyncQuorum:       0,
+		FastSyncTimeout:      60 * time.Second,
+		ContractTrace:        false,
+		ContractTraceMaxSize: 1 << 20,
 	}
 }
 
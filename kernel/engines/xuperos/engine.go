@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"path/filepath"
 	"sync"
+	"time"
 
 	xconf "github.com/xuperchain/xupercore/kernel/common/xconfig"
 	"github.com/xuperchain/xupercore/kernel/engines"
@@ -13,6 +14,7 @@ import (
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
 	engconf "github.com/xuperchain/xupercore/kernel/engines/xuperos/config"
 	xnet "github.com/xuperchain/xupercore/kernel/engines/xuperos/net"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/parachain"
 	"github.com/xuperchain/xupercore/lib/logs"
 	"github.com/xuperchain/xupercore/lib/timer"
 )
@@ -31,8 +33,13 @@ type Engine struct {
 	relyAgent common.EngineRelyAgent
 	// 确保Exit调用幂等
 	exitOnce sync.Once
+	// 引擎退出时关闭
+	exitCh chan struct{}
 }
 
+// 读取平行链群组失败后的重试间隔
+const serveChainRetryInterval = 10 * time.Second
+
 // 向工厂注册自己的创建方法
 func init() {
 	engines.Register(common.BCEngineName, NewEngine)
@@ -72,6 +79,7 @@ func (t *Engine) Init(envCfg *xconf.EnvConf) error {
 	}
 	t.engCtx = engCtx
 	t.log = t.engCtx.XLog
+	t.exitCh = make(chan struct{})
 	t.chainM = ChainManagerImpl{
 		engCtx: engCtx,
 		log:    t.log,
@@ -125,6 +133,11 @@ func (t *Engine) Run() {
 		t.netEvent.Start()
 	}()
 
+	// 新节点通过状态快照快速同步
+	if t.engCtx.EngCfg.FastSync {
+		t.fastSyncChains()
+	}
+
 	// 遍历启动每条链
 	t.chainM.StartChains()
 
@@ -179,47 +192,112 @@ func (t *Engine) loadChains() error {
 			continue
 		}
 
-		chainDir := filepath.Join(dataDir, fInfo.Name())
-		t.log.Trace("start load chain", "chain", fInfo.Name(), "dir", chainDir)
-		chain, err := LoadChain(t.engCtx, fInfo.Name())
-		if err != nil {
-			t.log.Error("load chain from data dir failed", "error", err, "dir", chainDir)
+		if err := t.loadChain(fInfo.Name()); err != nil {
 			return err
 		}
-		t.log.Trace("load chain from data dir succ", "chain", fInfo.Name())
-
-		// 记录链实例
-		t.chainM.Put(fInfo.Name(), chain)
-
-		// 启动异步任务worker
-		if fInfo.Name() == rootChain {
-			aw, err := asyncworker.NewAsyncWorkerImpl(fInfo.Name(), t, chain.ctx.State.GetLDB())
-			if err != nil {
-				t.log.Error("create asyncworker error", "bcName", rootChain, "err", err)
-				return err
-			}
-			chain.ctx.Asyncworker = aw
-			err = chain.CreateParaChain()
-			if err != nil {
-				t.log.Error("create parachain mgmt error", "bcName", rootChain, "err", err)
-				return fmt.Errorf("create parachain error")
-			}
-			aw.Start()
-		}
-
-		t.log.Trace("load chain succeeded", "chain", fInfo.Name(), "dir", chainDir)
 		chainCnt++
 	}
 
 	// root链必须存在
-	if _, err := t.chainM.Get(rootChain); err != nil {
+	rootChainHD, err := t.chainM.Get(rootChain)
+	if err != nil {
 		t.log.Error("root chain not exist, please create it first", "rootChain", rootChain)
 		return fmt.Errorf("root chain not exist")
 	}
 	t.log.Trace("load chain form data dir succeeded", "chainCnt", chainCnt)
+
+	t.serveChains(rootChainHD)
 	return nil
 }
 
+// 加载链实例并记录到链管理，root链同时启动异步任务worker
+func (t *Engine) loadChain(bcName string) error {
+	dataDir := t.engCtx.EnvCfg.GenDataAbsPath(t.engCtx.EnvCfg.ChainDir)
+	chainDir := filepath.Join(dataDir, bcName)
+	t.log.Trace("start load chain", "chain", bcName, "dir", chainDir)
+	chain, err := LoadChain(t.engCtx, bcName)
+	if err != nil {
+		t.log.Error("load chain from data dir failed", "error", err, "dir", chainDir)
+		return err
+	}
+	t.log.Trace("load chain from data dir succ", "chain", bcName)
+
+	// 记录链实例
+	t.chainM.Put(bcName, chain)
+
+	// 启动异步任务worker
+	if bcName == t.engCtx.EngCfg.RootChain {
+		aw, err := asyncworker.NewAsyncWorkerImpl(bcName, t, chain.ctx.State.GetLDB())
+		if err != nil {
+			t.log.Error("create asyncworker error", "bcName", bcName, "err", err)
+			return err
+		}
+		chain.ctx.Asyncworker = aw
+		err = chain.CreateParaChain()
+		if err != nil {
+			t.log.Error("create parachain mgmt error", "bcName", bcName, "err", err)
+			return fmt.Errorf("create parachain error")
+		}
+		aw.Start()
+	}
+
+	t.log.Trace("load chain succeeded", "chain", bcName, "dir", chainDir)
+	return nil
+}
+
+// 向网络公告本节点服务的链，平行链的消息只在其群组成员间路由
+func (t *Engine) serveChains(rootChain common.Chain) {
+	for _, bcName := range t.chainM.GetChains() {
+		if bcName == t.engCtx.EngCfg.RootChain {
+			t.engCtx.Net.ServeChain(bcName, nil)
+			continue
+		}
+		if err := t.serveParaChain(rootChain, bcName); err != nil {
+			// 群组未知时不能按公开链路由，先不向任何节点开放，后台重试
+			t.log.Warn("get parachain group failed, retry later", "bcName", bcName, "err", err)
+			t.engCtx.Net.ServeChain(bcName, []string{})
+			go t.retryServeParaChain(rootChain, bcName)
+		}
+	}
+}
+
+// serveParaChain 从主链状态读取平行链群组并公告，没有群组的平行链对所有节点开放
+func (t *Engine) serveParaChain(rootChain common.Chain, bcName string) error {
+	reader := rootChain.Context().State.CreateXMReader()
+	group, err := parachain.GetChainGroup(reader, bcName)
+	if err != nil {
+		return err
+	}
+	var members []string
+	if group != nil {
+		members = group.Members()
+	}
+	t.engCtx.Net.ServeChain(bcName, members)
+	return nil
+}
+
+// retryServeParaChain 定期重试读取平行链群组，直到成功、链被停止或引擎退出
+func (t *Engine) retryServeParaChain(rootChain common.Chain, bcName string) {
+	ticker := time.NewTicker(serveChainRetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-t.exitCh:
+			return
+		case <-ticker.C:
+		}
+		if _, err := t.chainM.Get(bcName); err != nil {
+			return
+		}
+		err := t.serveParaChain(rootChain, bcName)
+		if err == nil {
+			t.log.Info("serve parachain after retry", "bcName", bcName)
+			return
+		}
+		t.log.Warn("get parachain group failed, retry later", "bcName", bcName, "err", err)
+	}
+}
+
 func (t *Engine) createEngCtx(envCfg *xconf.EnvConf) (*common.EngineCtx, error) {
 	// 引擎日志
 	log, err := logs.NewLogger("", common.BCEngineName)
@@ -248,6 +326,9 @@ func (t *Engine) createEngCtx(envCfg *xconf.EnvConf) (*common.EngineCtx, error)
 }
 
 func (t *Engine) exit() {
+	if t.exitCh != nil {
+		close(t.exitCh)
+	}
 	// 关闭矿工
 	wg := &sync.WaitGroup{}
 	t.chainM.StopChains()
@@ -0,0 +1,173 @@
+package xuperos
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/snapshot"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/asyncworker"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/lib/timer"
+)
+
+const (
+	// 查询快照的重试间隔
+	fastSyncRetryInterval = 3 * time.Second
+)
+
+// fastSyncChains 对只有创世块的链，从其他节点下载状态快照并安装，失败时退回逐块同步
+func (t *Engine) fastSyncChains() {
+	for _, bcName := range t.chainM.GetChains() {
+		chain, err := t.chainM.Get(bcName)
+		if err != nil || chain.Context().Ledger.GetMeta().GetTrunkHeight() > 0 {
+			continue
+		}
+		if err := t.fastSync(bcName); err != nil {
+			t.log.Warn("fast sync failed, fallback to sync blocks", "bcName", bcName, "err", err)
+		}
+	}
+}
+
+func (t *Engine) fastSync(bcName string) error {
+	ctx := &xctx.BaseCtx{XLog: t.log, Timer: timer.NewXTimer()}
+	manifest, peers, err := t.selectSnapshot(ctx, bcName)
+	if err != nil {
+		return err
+	}
+	t.log.Info("start fast sync", "bcName", bcName, "height", manifest.Height,
+		"blockid", manifest.Blockid, "chunks", len(manifest.Chunks), "peers", len(peers))
+
+	chainHD, err := t.chainM.Get(bcName)
+	if err != nil {
+		return err
+	}
+	store := chainHD.Context().Snapshot.Store()
+	if err := t.downloadSnapshot(ctx, bcName, store, manifest, peers); err != nil {
+		return err
+	}
+
+	// 关闭链后安装快照，无论成功与否都重新加载链
+	if aw, ok := chainHD.Context().Asyncworker.(*asyncworker.AsyncWorkerImpl); ok {
+		aw.Stop()
+	}
+	if err := t.chainM.Stop(bcName); err != nil {
+		return err
+	}
+	_, installErr := snapshot.Install(t.engCtx.EnvCfg, bcName, store, manifest.Height, chainHD.Context().Crypto)
+	if installErr != nil {
+		store.Remove(manifest.Height)
+	}
+	if err := t.loadChain(bcName); err != nil {
+		return err
+	}
+	if installErr != nil {
+		return installErr
+	}
+	t.log.Info("fast sync succ", "bcName", bcName, "height", manifest.Height, "blockid", manifest.Blockid)
+	return nil
+}
+
+// selectSnapshot 在超时前反复向验证人查询，直到足够多的验证人提供相同的快照，返回快照和提供快照的节点
+// 只有验证人的应答计票，需要的票数不少于验证人个数的2f+1，验证人集合变化后凑不齐票数时退回逐块同步
+func (t *Engine) selectSnapshot(ctx xctx.XContext, bcName string) (*snapshot.Manifest, []string, error) {
+	cfg := t.engCtx.EngCfg
+	validators, err := t.snapshotValidators(bcName)
+	if err != nil {
+		return nil, nil, err
+	}
+	quorum := snapshot.Quorum(len(validators))
+	if cfg.FastSyncQuorum > quorum {
+		quorum = cfg.FastSyncQuorum
+	}
+	deadline := time.Now().Add(cfg.FastSyncTimeout)
+	for {
+		manifests, err := t.netEvent.GetSnapshotManifests(ctx, bcName, validators)
+		if err == nil {
+			values := make([][]byte, 0, len(manifests))
+			for _, data := range manifests {
+				values = append(values, data)
+			}
+			if m, err := snapshot.SelectManifest(values, quorum); err == nil {
+				return m, snapshotPeers(m, manifests), nil
+			}
+		}
+		if time.Now().Add(fastSyncRetryInterval).After(deadline) {
+			return nil, nil, snapshot.ErrNoQuorum
+		}
+		time.Sleep(fastSyncRetryInterval)
+	}
+}
+
+// snapshotValidators 返回本地共识记录的验证人，只有创世块时为创世共识的初始验证人
+func (t *Engine) snapshotValidators(bcName string) ([]string, error) {
+	chain, err := t.chainM.Get(bcName)
+	if err != nil {
+		return nil, err
+	}
+	status, err := chain.Context().Consensus.GetConsensusStatus()
+	if err != nil {
+		return nil, err
+	}
+	var info struct {
+		Validators []string `json:"validators"`
+	}
+	if err := json.Unmarshal(status.GetCurrentValidatorsInfo(), &info); err != nil || len(info.Validators) == 0 {
+		return nil, common.ErrFastSyncFailed
+	}
+	return info.Validators, nil
+}
+
+func snapshotPeers(m *snapshot.Manifest, manifests map[string][]byte) []string {
+	target, _ := m.Hash()
+	var peers []string
+	for peer, data := range manifests {
+		pm, err := snapshot.ParseManifest(data)
+		if err != nil {
+			continue
+		}
+		if h, err := pm.Hash(); err == nil && bytes.Equal(h, target) {
+			peers = append(peers, peer)
+		}
+	}
+	return peers
+}
+
+// downloadSnapshot 依次下载并校验分片，单个分片失败时换下一个节点重试
+func (t *Engine) downloadSnapshot(ctx xctx.XContext, bcName string, store *snapshot.Store,
+	m *snapshot.Manifest, peers []string) error {
+	if len(peers) == 0 {
+		return snapshot.ErrNoQuorum
+	}
+	w, err := store.NewWriter(m.Height)
+	if err != nil {
+		return err
+	}
+	next := 0
+	for i := range m.Chunks {
+		var data []byte
+		for tries := 0; tries < len(peers); tries++ {
+			peer := peers[next%len(peers)]
+			next++
+			data, err = t.netEvent.GetSnapshotChunk(ctx, bcName, m.Height, i, peer)
+			if err == nil {
+				err = m.VerifyChunk(i, data)
+			}
+			if err == nil {
+				break
+			}
+			t.log.Warn("download snapshot chunk failed", "bcName", bcName, "index", i, "peer", peer, "err", err)
+		}
+		if err != nil {
+			w.Abort()
+			return common.ErrFastSyncFailed
+		}
+		if err := w.WriteChunk(data); err != nil {
+			w.Abort()
+			return err
+		}
+		t.log.Debug("download snapshot chunk succ", "bcName", bcName, "index", i, "total", len(m.Chunks))
+	}
+	return w.Commit(m)
+}
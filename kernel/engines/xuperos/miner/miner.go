@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"math/big"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -19,6 +18,7 @@ import (
 	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/compact"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
 	"github.com/xuperchain/xupercore/kernel/network/p2p"
 	"github.com/xuperchain/xupercore/lib/logs"
@@ -289,8 +289,14 @@ func (t *Miner) packBlock(ctx xctx.XContext, height int64,
 
 	ctx.GetLog().Debug("pack block get timer tx succ", "auto tx", autoTx)
 
-	// 2.选择本次要打包的tx
-	generalTxList, err := t.getUnconfirmedTx(sizeLimit)
+	// 2.选择本次要打包的tx，timer交易计入合约存储的累计用量
+	quota := t.ctx.State.NewStorageQuotaChecker()
+	if quota != nil && len(autoTx.TxOutputsExt) > 0 {
+		if err := quota.Add(autoTx); err != nil {
+			return nil, err
+		}
+	}
+	generalTxList, err := t.getUnconfirmedTx(sizeLimit, quota)
 	if err != nil {
 		return nil, err
 	}
@@ -354,18 +360,36 @@ func (t *Miner) getTimerTx(height int64) (*lpb.Transaction, error) {
 	return autoTx, nil
 }
 
-func (t *Miner) getUnconfirmedTx(sizeLimit int) ([]*lpb.Transaction, error) {
+func (t *Miner) getUnconfirmedTx(sizeLimit int, quota *state.StorageQuotaChecker) ([]*lpb.Transaction, error) {
 	unconfirmedTxs, err := t.ctx.State.GetUnconfirmedTx(false)
 	if err != nil {
 		return nil, err
 	}
 
+	// 开启费用市场时跳过不足以支付基础费用的交易，开启存储计量时跳过累计用量超过配额的交易，
+	// 依赖被跳过交易的交易同样跳过
+	baseFee, err := t.ctx.State.NextBaseFee()
+	if err != nil {
+		return nil, err
+	}
+	skipped := make(map[string]bool)
 	txList := make([]*lpb.Transaction, 0)
 	for _, tx := range unconfirmedTxs {
+		if dependsOnTxs(tx, skipped) || (baseFee != nil && t.ctx.State.VerifyTxBaseFee(tx, baseFee) != nil) {
+			skipped[string(tx.Txid)] = true
+			continue
+		}
 		size := proto.Size(tx)
 		if size > sizeLimit {
 			break
 		}
+		if quota != nil {
+			if err := quota.Add(tx); err != nil {
+				t.log.Debug("skip tx exceeding storage quota", "txid", utils.F(tx.Txid), "err", err)
+				skipped[string(tx.Txid)] = true
+				continue
+			}
+		}
 		sizeLimit -= size
 		txList = append(txList, tx)
 	}
@@ -373,13 +397,32 @@ func (t *Miner) getUnconfirmedTx(sizeLimit int) ([]*lpb.Transaction, error) {
 	return txList, nil
 }
 
+// dependsOnTxs 交易是否引用了txids中交易的输出
+func dependsOnTxs(tx *lpb.Transaction, txids map[string]bool) bool {
+	if len(txids) == 0 {
+		return false
+	}
+	for _, input := range tx.TxInputs {
+		if txids[string(input.RefTxid)] {
+			return true
+		}
+	}
+	for _, input := range tx.TxInputsExt {
+		if txids[string(input.RefTxid)] {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *Miner) getAwardTx(height int64) (*lpb.Transaction, error) {
-	amount := t.ctx.Ledger.GenesisBlock.CalcAward(height)
-	if amount.Cmp(big.NewInt(0)) < 0 {
-		return nil, errors.New("amount in transaction can not be negative number")
+	// 按当前的分配规则和发行上限生成奖励输出
+	outputs, err := t.ctx.State.AwardOutputs(height, []byte(t.ctx.Address.Address))
+	if err != nil {
+		return nil, err
 	}
 
-	awardTx, err := tx.GenerateAwardTx(t.ctx.Address.Address, amount.String(), []byte("award"))
+	awardTx, err := tx.GenerateAwardTxWithOutputs(outputs, []byte("award"))
 	if err != nil {
 		return nil, err
 	}
@@ -753,13 +796,15 @@ func countConfirmBlock(messages []*protos.XuperMessage) bool {
 }
 
 // 广播新区块
-// 三种块传播模式：
+// 四种块传播模式：
 //  1. 一种是完全块广播模式(Full_BroadCast_Mode)，即直接广播原始块给所有相邻节点，
 //     适用于出块矿工在知道周围节点都不具备该块的情况下；
 //  2. 一种是问询式块广播模式(Interactive_BroadCast_Mode)，即先广播新块的头部给相邻节点，
 //     相邻节点在没有相同块的情况下通过GetBlock主动获取块数据。
 //  3. Mixed_BroadCast_Mode是指出块节点将新块用Full_BroadCast_Mode模式广播，
 //     其他节点使用Interactive_BroadCast_Mode
+//  4. 紧凑块广播模式(Compact_BroadCast_Mode)，即广播区块头和交易短ID，
+//     相邻节点根据本地未确认交易重建区块，只获取缺失的交易
 // broadcast block in Full_BroadCast_Mode since it's the original miner
 func (t *Miner) broadcastBlock(ctx xctx.XContext, block *lpb.InternalBlock) {
 	engCtx := t.ctx.EngCtx
@@ -768,12 +813,15 @@ func (t *Miner) broadcastBlock(ctx xctx.XContext, block *lpb.InternalBlock) {
 		p2p.WithLogId(ctx.GetLog().GetLogId()),
 	}
 	var msg *protos.XuperMessage
-	if engCtx.EngCfg.BlockBroadcastMode == common.InteractiveBroadCastMode {
+	switch engCtx.EngCfg.BlockBroadcastMode {
+	case common.InteractiveBroadCastMode:
 		blockID := &lpb.InternalBlock{
 			Blockid: block.Blockid,
 		}
 		msg = p2p.NewMessage(protos.XuperMessage_NEW_BLOCKID, blockID, opts...)
-	} else {
+	case common.CompactBroadCastMode:
+		msg = p2p.NewMessage(protos.XuperMessage_COMPACT_BLOCK, compact.NewCompactBlock(block), opts...)
+	default:
 		msg = p2p.NewMessage(protos.XuperMessage_SENDBLOCK, block, opts...)
 	}
 
@@ -4,6 +4,7 @@ import (
 	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
 	"github.com/xuperchain/xupercore/kernel/network/p2p"
 	"github.com/xuperchain/xupercore/protos"
 )
@@ -15,12 +16,22 @@ func (t *NetEvent) GetBlock(ctx xctx.XContext, request *protos.XuperMessage) (*l
 		return nil, common.ErrParameter
 	}
 
+	return t.getFullBlock(ctx, request.Header.Bcname, block.Blockid, request.GetHeader().GetFrom())
+}
+
+// getFullBlock 向指定节点获取完整区块
+func (t *NetEvent) getFullBlock(ctx xctx.XContext, bcName string, blockID []byte, from string) (*lpb.InternalBlock, error) {
+	input := &xpb.BlockID{
+		Bcname:      bcName,
+		Blockid:     blockID,
+		NeedContent: true,
+	}
 	msgOpts := []p2p.MessageOption{
-		p2p.WithBCName(request.Header.Bcname),
-		p2p.WithLogId(request.Header.Bcname),
+		p2p.WithBCName(bcName),
+		p2p.WithLogId(ctx.GetLog().GetLogId()),
 	}
-	msg := p2p.NewMessage(protos.XuperMessage_GET_BLOCK, &block, msgOpts...)
-	responses, err := t.engine.Context().Net.SendMessageWithResponse(ctx, msg, p2p.WithPeerIDs([]string{request.GetHeader().GetFrom()}))
+	msg := p2p.NewMessage(protos.XuperMessage_GET_BLOCK, input, msgOpts...)
+	responses, err := t.engine.Context().Net.SendMessageWithResponse(ctx, msg, p2p.WithPeerIDs([]string{from}))
 	if err != nil {
 		return nil, common.ErrSendMessageFailed
 	}
@@ -31,14 +42,14 @@ func (t *NetEvent) GetBlock(ctx xctx.XContext, request *protos.XuperMessage) (*l
 			continue
 		}
 
-		var block lpb.InternalBlock
-		err := p2p.Unmarshal(response, &block)
-		if err != nil {
+		var blockInfo xpb.BlockInfo
+		err := p2p.Unmarshal(response, &blockInfo)
+		if err != nil || blockInfo.GetBlock() == nil {
 			ctx.GetLog().Warn("GetBlock unmarshal error", "error", err, "from", response.GetHeader().GetFrom())
 			continue
 		}
 
-		return &block, nil
+		return blockInfo.GetBlock(), nil
 	}
 
 	return nil, common.ErrNetworkNoResponse
@@ -0,0 +1,169 @@
+package xuperos
+
+import (
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/compact"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/utils"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+func (t *NetEvent) handleCompactBlock(ctx xctx.XContext, request *protos.XuperMessage) {
+	var input xpb.CompactBlock
+	if err := p2p.Unmarshal(request, &input); err != nil {
+		ctx.GetLog().Warn("handleCompactBlock Unmarshal request error", "error", err)
+		return
+	}
+	if input.GetHeader() == nil || len(input.GetHeader().GetBlockid()) == 0 {
+		ctx.GetLog().Warn("handleCompactBlock header is empty")
+		return
+	}
+
+	bcName := request.GetHeader().GetBcname()
+	chain, err := t.engine.Get(bcName)
+	if err != nil {
+		ctx.GetLog().Warn("chain not exist", "error", err, "bcName", bcName)
+		return
+	}
+
+	blockID := input.GetHeader().GetBlockid()
+	if chain.Context().Ledger.ExistBlock(blockID) {
+		return
+	}
+
+	from := request.GetHeader().GetFrom()
+	block, err := t.rebuildBlock(ctx, chain, from, &input)
+	if err != nil {
+		// 重建失败时回退为获取完整区块
+		ctx.GetLog().Info("rebuild compact block failed, fallback to get block",
+			"blockId", utils.F(blockID), "error", err)
+		block, err = t.getFullBlock(ctx, bcName, blockID, from)
+		if err != nil {
+			ctx.GetLog().Warn("get full block error", "blockId", utils.F(blockID), "error", err)
+			return
+		}
+	}
+
+	if err := t.SendBlock(ctx, chain, block); err != nil {
+		return
+	}
+
+	go t.engine.Context().Net.SendMessage(ctx, request)
+}
+
+// rebuildBlock 根据本地未确认交易重建区块，缺失的交易向发送方获取
+func (t *NetEvent) rebuildBlock(ctx xctx.XContext, chain common.Chain, from string,
+	cb *xpb.CompactBlock) (*lpb.InternalBlock, error) {
+	unconfirmedTxs, err := chain.Context().State.GetUnconfirmedTx(false)
+	if err != nil {
+		return nil, err
+	}
+
+	txs, missing, err := compact.MatchTxs(cb, unconfirmedTxs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(missing) > 0 {
+		blockID := cb.GetHeader().GetBlockid()
+		ctx.GetLog().Debug("compact block txs missing", "blockId", utils.F(blockID),
+			"txCount", len(txs), "missing", len(missing))
+		fetched, err := t.getBlockTxs(ctx, chain.Context().BCName, blockID, missing, from)
+		if err != nil {
+			return nil, err
+		}
+		if len(fetched) != len(missing) {
+			return nil, compact.ErrTxsMissing
+		}
+		for i, idx := range missing {
+			txs[idx] = fetched[i]
+		}
+	}
+
+	return compact.Assemble(cb, txs)
+}
+
+func (t *NetEvent) getBlockTxs(ctx xctx.XContext, bcName string, blockID []byte,
+	indexes []uint32, from string) ([]*lpb.Transaction, error) {
+	msgOpts := []p2p.MessageOption{
+		p2p.WithBCName(bcName),
+		p2p.WithLogId(ctx.GetLog().GetLogId()),
+	}
+	input := &xpb.BlockTxsRequest{
+		Blockid: blockID,
+		Indexes: indexes,
+	}
+	msg := p2p.NewMessage(protos.XuperMessage_GET_BLOCK_TXS, input, msgOpts...)
+	responses, err := t.engine.Context().Net.SendMessageWithResponse(ctx, msg, p2p.WithPeerIDs([]string{from}))
+	if err != nil {
+		return nil, common.ErrSendMessageFailed
+	}
+
+	for _, response := range responses {
+		if response.GetHeader().GetErrorType() != protos.XuperMessage_SUCCESS {
+			ctx.GetLog().Warn("GetBlockTxs response error", "errorType", response.GetHeader().GetErrorType(), "from", response.GetHeader().GetFrom())
+			continue
+		}
+
+		var output xpb.BlockTxs
+		if err := p2p.Unmarshal(response, &output); err != nil {
+			ctx.GetLog().Warn("GetBlockTxs unmarshal error", "error", err, "from", response.GetHeader().GetFrom())
+			continue
+		}
+
+		return output.Txs, nil
+	}
+
+	return nil, common.ErrNetworkNoResponse
+}
+
+func (t *NetEvent) handleGetBlockTxs(ctx xctx.XContext, request *protos.XuperMessage) (*protos.XuperMessage, error) {
+	var input xpb.BlockTxsRequest
+	var output *xpb.BlockTxs
+
+	bcName := request.GetHeader().GetBcname()
+	response := func(err error) (*protos.XuperMessage, error) {
+		opts := []p2p.MessageOption{
+			p2p.WithBCName(bcName),
+			p2p.WithErrorType(ErrorType(err)),
+			p2p.WithLogId(request.GetHeader().GetLogid()),
+		}
+		resp := p2p.NewMessage(p2p.GetRespMessageType(request.GetHeader().GetType()), output, opts...)
+		return resp, nil
+	}
+
+	err := p2p.Unmarshal(request, &input)
+	if err != nil {
+		ctx.GetLog().Error("unmarshal error", "bcName", bcName, "error", err)
+		return response(common.ErrParameter)
+	}
+
+	chain, err := t.engine.Get(bcName)
+	if err != nil {
+		ctx.GetLog().Warn("chain not exist", "error", err, "bcName", bcName)
+		return response(common.ErrChainNotExist)
+	}
+
+	block, err := chain.Context().Ledger.QueryBlock(input.Blockid)
+	if err != nil {
+		ctx.GetLog().Warn("query block error", "blockId", utils.F(input.Blockid), "error", err)
+		return response(common.ErrBlockNotExist)
+	}
+
+	output = &xpb.BlockTxs{
+		Blockid: input.Blockid,
+		Txs:     make([]*lpb.Transaction, 0, len(input.Indexes)),
+	}
+	for _, idx := range input.Indexes {
+		if int(idx) >= len(block.Transactions) {
+			output = nil
+			return response(common.ErrParameter)
+		}
+		output.Txs = append(output.Txs, block.Transactions[idx])
+	}
+
+	return response(nil)
+}
@@ -0,0 +1,158 @@
+package xuperos
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/tx"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/compact"
+	engconf "github.com/xuperchain/xupercore/kernel/engines/xuperos/config"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+// confirmTestBlock 在发送方账本上确认包含award交易和指定交易的区块
+func confirmTestBlock(t *testing.T, chain *testChain, txs ...*lpb.Transaction) *lpb.InternalBlock {
+	awardTx, err := tx.GenerateAwardTx("miner", "1000", []byte("award"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	block, err := chain.ledger.FormatBlock(append([]*lpb.Transaction{awardTx}, txs...), []byte("miner"),
+		privateKey, time.Now().UnixNano(), 0, 0, chain.rootBlock.Blockid, chain.state.GetTotal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := chain.ledger.ConfirmBlock(block, false); !status.Succ {
+		t.Fatal("confirm block fail", status.Error)
+	}
+	return block
+}
+
+// newCompactBlockPeers 发送方已确认区块，接收方交易池中只有区块的部分交易
+func newCompactBlockPeers(t *testing.T) (*NetEvent, *mockNetwork, *lpb.InternalBlock) {
+	senderChain := newTestChain(t)
+	receiverChain := newTestChain(t)
+	block := confirmTestBlock(t, senderChain, receiverChain.pendingTx, senderChain.pendingTx)
+
+	engCfg := engconf.GetDefEngineConf()
+	engCfg.BlockBroadcastMode = common.CompactBroadCastMode
+	sender, _ := newTestNetEvent(t, engCfg, senderChain)
+	receiver, net := newTestNetEvent(t, engCfg, receiverChain)
+
+	// 接收方的请求由发送方处理
+	net.respond = func(msg *protos.XuperMessage) ([]*protos.XuperMessage, error) {
+		var resp *protos.XuperMessage
+		var err error
+		switch msg.GetHeader().GetType() {
+		case protos.XuperMessage_GET_BLOCK_TXS:
+			resp, err = sender.handleGetBlockTxs(newTestCtx(t), msg)
+		case protos.XuperMessage_GET_BLOCK:
+			resp, err = sender.handleGetBlock(newTestCtx(t), msg)
+		default:
+			return nil, errors.New("unexpected request")
+		}
+		if err != nil {
+			return nil, err
+		}
+		return []*protos.XuperMessage{resp}, nil
+	}
+
+	return receiver, net, block
+}
+
+func receivedBlocks(event *NetEvent) []*lpb.InternalBlock {
+	return event.engine.(*mockEngine).chains[testBCName].(*mockChain).blocks
+}
+
+func newCompactBlockMessage(block *lpb.InternalBlock) *protos.XuperMessage {
+	msg := p2p.NewMessage(protos.XuperMessage_COMPACT_BLOCK, compact.NewCompactBlock(block), p2p.WithBCName(testBCName))
+	msg.Header.From = testPeer
+	return msg
+}
+
+func TestHandleCompactBlock(t *testing.T) {
+	receiver, net, block := newCompactBlockPeers(t)
+
+	// 缺失的交易向发送方获取
+	var requested []protos.XuperMessage_MessageType
+	respond := net.respond
+	net.respond = func(msg *protos.XuperMessage) ([]*protos.XuperMessage, error) {
+		requested = append(requested, msg.GetHeader().GetType())
+		return respond(msg)
+	}
+
+	request := newCompactBlockMessage(block)
+	receiver.handleCompactBlock(newTestCtx(t), request)
+	if len(requested) != 1 || requested[0] != protos.XuperMessage_GET_BLOCK_TXS {
+		t.Fatalf("only missing txs should be requested: %v", requested)
+	}
+
+	blocks := receivedBlocks(receiver)
+	if len(blocks) != 1 {
+		t.Fatalf("rebuilt block not processed")
+	}
+	if !bytes.Equal(blocks[0].GetBlockid(), block.GetBlockid()) || len(blocks[0].Transactions) != len(block.Transactions) {
+		t.Fatalf("rebuilt block mismatch")
+	}
+	for i, tx := range block.Transactions {
+		if !bytes.Equal(blocks[0].Transactions[i].GetTxid(), tx.GetTxid()) {
+			t.Errorf("rebuilt tx %d mismatch", i)
+		}
+	}
+
+	// 处理成功后继续转发紧凑块
+	if sent := net.waitSent(t, 1); sent[0].msg != request {
+		t.Errorf("compact block should be relayed")
+	}
+}
+
+func TestHandleCompactBlockFallback(t *testing.T) {
+	receiver, net, block := newCompactBlockPeers(t)
+
+	// 获取缺失交易失败时回退为获取完整区块
+	var requested []protos.XuperMessage_MessageType
+	respond := net.respond
+	net.respond = func(msg *protos.XuperMessage) ([]*protos.XuperMessage, error) {
+		requested = append(requested, msg.GetHeader().GetType())
+		if msg.GetHeader().GetType() == protos.XuperMessage_GET_BLOCK_TXS {
+			return nil, errors.New("get block txs error")
+		}
+		return respond(msg)
+	}
+
+	receiver.handleCompactBlock(newTestCtx(t), newCompactBlockMessage(block))
+	if len(requested) != 2 || requested[1] != protos.XuperMessage_GET_BLOCK {
+		t.Fatalf("should fallback to get block: %v", requested)
+	}
+
+	blocks := receivedBlocks(receiver)
+	if len(blocks) != 1 || !bytes.Equal(blocks[0].GetBlockid(), block.GetBlockid()) ||
+		len(blocks[0].Transactions) != len(block.Transactions) {
+		t.Fatalf("full block not processed")
+	}
+}
+
+func TestGetFullBlock(t *testing.T) {
+	receiver, _, block := newCompactBlockPeers(t)
+
+	// GET_BLOCK请求为BlockID，响应为BlockInfo
+	full, err := receiver.getFullBlock(newTestCtx(t), testBCName, block.GetBlockid(), testPeer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(full.GetBlockid(), block.GetBlockid()) || len(full.Transactions) != len(block.Transactions) {
+		t.Fatalf("full block mismatch")
+	}
+
+	if _, err := receiver.getFullBlock(newTestCtx(t), testBCName, []byte("unknown"), testPeer); err == nil {
+		t.Errorf("get unknown block should fail")
+	}
+}
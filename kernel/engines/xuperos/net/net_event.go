@@ -3,11 +3,15 @@ package xuperos
 import (
 	"bytes"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/patrickmn/go-cache"
+
 	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/compact"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/reader"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
 	"github.com/xuperchain/xupercore/kernel/network/p2p"
@@ -31,6 +35,11 @@ type NetEvent struct {
 	engine   common.Engine
 	msgChan  chan *protos.XuperMessage
 	exitChan chan bool
+	// 已见过的交易ID，交易清单广播模式下避免重复获取交易
+	seenTxs *cache.Cache
+	// 待公告的交易ID bcname => txids
+	txInv    map[string][][]byte
+	txInvMtx sync.Mutex
 }
 
 func NewNetEvent(engine common.Engine) (*NetEvent, error) {
@@ -38,11 +47,14 @@ func NewNetEvent(engine common.Engine) (*NetEvent, error) {
 		return nil, fmt.Errorf("new net event failed because param error")
 	}
 
+	engCfg := engine.Context().EngCfg
 	obj := &NetEvent{
 		log:      engine.Context().XLog,
 		engine:   engine,
 		msgChan:  make(chan *protos.XuperMessage, DefMsgChanBufSize),
 		exitChan: make(chan bool, 1),
+		seenTxs:  cache.New(engCfg.TxIdCacheExpiredTime, engCfg.TxIdCacheGCInterval),
+		txInv:    make(map[string][][]byte),
 	}
 
 	// 订阅监听事件
@@ -72,6 +84,9 @@ func (t *NetEvent) Subscriber() error {
 		protos.XuperMessage_SENDBLOCK,
 		protos.XuperMessage_BATCHPOSTTX,
 		protos.XuperMessage_NEW_BLOCKID,
+		protos.XuperMessage_NEW_TXIDS,
+		protos.XuperMessage_GET_TXS,
+		protos.XuperMessage_COMPACT_BLOCK,
 	}
 
 	// 走同步处理的网络消息句柄
@@ -79,6 +94,9 @@ func (t *NetEvent) Subscriber() error {
 		protos.XuperMessage_GET_BLOCK:                t.handleGetBlock,
 		protos.XuperMessage_GET_BLOCKCHAINSTATUS:     t.handleGetChainStatus,
 		protos.XuperMessage_CONFIRM_BLOCKCHAINSTATUS: t.handleConfirmChainStatus,
+		protos.XuperMessage_GET_BLOCK_TXS:            t.handleGetBlockTxs,
+		protos.XuperMessage_GET_SNAPSHOT_MANIFEST:    t.handleGetSnapshotManifest,
+		protos.XuperMessage_GET_SNAPSHOT_CHUNK:       t.handleGetSnapshotChunk,
 		//protos.XuperMessage_GET_BLOCKIDS:             t.handleGetBlockIds,
 		//protos.XuperMessage_GET_BLOCKS:               t.handleGetBlocks,
 	}
@@ -108,10 +126,15 @@ func (t *NetEvent) Subscriber() error {
 
 // 阻塞等待chan中消息，直到收到退出信号
 func (t *NetEvent) procMsgLoop() {
+	ticker := time.NewTicker(t.txAnnounceInterval())
+	defer ticker.Stop()
+
 	for {
 		select {
 		case request := <-t.msgChan:
 			go t.procAsyncMsg(request)
+		case <-ticker.C:
+			t.flushTxInv()
 		case <-t.exitChan:
 			t.log.Trace("wait for the processing message loop to end")
 			return
@@ -121,10 +144,13 @@ func (t *NetEvent) procMsgLoop() {
 
 func (t *NetEvent) procAsyncMsg(request *protos.XuperMessage) {
 	var AsyncMsgList = map[protos.XuperMessage_MessageType]AsyncMsgHandle{
-		protos.XuperMessage_POSTTX:      t.handlePostTx,
-		protos.XuperMessage_SENDBLOCK:   t.handleSendBlock,
-		protos.XuperMessage_BATCHPOSTTX: t.handleBatchPostTx,
-		protos.XuperMessage_NEW_BLOCKID: t.handleNewBlockID,
+		protos.XuperMessage_POSTTX:        t.handlePostTx,
+		protos.XuperMessage_SENDBLOCK:     t.handleSendBlock,
+		protos.XuperMessage_BATCHPOSTTX:   t.handleBatchPostTx,
+		protos.XuperMessage_NEW_BLOCKID:   t.handleNewBlockID,
+		protos.XuperMessage_NEW_TXIDS:     t.handleNewTxIDs,
+		protos.XuperMessage_GET_TXS:       t.handleGetTxs,
+		protos.XuperMessage_COMPACT_BLOCK: t.handleCompactBlock,
 	}
 
 	// 处理任务
@@ -158,7 +184,7 @@ func (t *NetEvent) handlePostTx(ctx xctx.XContext, request *protos.XuperMessage)
 
 	err = t.PostTx(ctx, chain, &tx)
 	if err == nil {
-		go t.engine.Context().Net.SendMessage(ctx, request)
+		t.relayTxs(ctx, request.Header.Bcname, request, []*lpb.Transaction{&tx})
 	}
 }
 
@@ -189,7 +215,7 @@ func (t *NetEvent) handleBatchPostTx(ctx xctx.XContext, request *protos.XuperMes
 	input.Txs = broadcastTx
 	msg := p2p.NewMessage(protos.XuperMessage_BATCHPOSTTX, &input)
 
-	go t.engine.Context().Net.SendMessage(ctx, msg)
+	t.relayTxs(ctx, request.Header.Bcname, msg, broadcastTx)
 }
 
 func (t *NetEvent) PostTx(ctx xctx.XContext, chain common.Chain, tx *lpb.Transaction) error {
@@ -224,9 +250,13 @@ func (t *NetEvent) handleSendBlock(ctx xctx.XContext, request *protos.XuperMessa
 	}
 
 	net := t.engine.Context().Net
-	if t.engine.Context().EngCfg.BlockBroadcastMode == common.FullBroadCastMode {
+	switch t.engine.Context().EngCfg.BlockBroadcastMode {
+	case common.FullBroadCastMode:
 		go net.SendMessage(ctx, request)
-	} else {
+	case common.CompactBroadCastMode:
+		msg := p2p.NewMessage(protos.XuperMessage_COMPACT_BLOCK, compact.NewCompactBlock(&block), p2p.WithBCName(request.Header.Bcname))
+		go net.SendMessage(ctx, msg)
+	default:
 		blockID := &lpb.InternalBlock{
 			Blockid: block.Blockid,
 		}
@@ -244,7 +274,7 @@ func (t *NetEvent) handleNewBlockID(ctx xctx.XContext, request *protos.XuperMess
 
 	block, err := t.GetBlock(ctx, request)
 	if err != nil {
-		ctx.GetLog().Warn("GetBlock error", "error", err, "blockId", block.Blockid)
+		ctx.GetLog().Warn("GetBlock error", "error", err)
 		return
 	}
 
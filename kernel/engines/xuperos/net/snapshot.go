@@ -0,0 +1,169 @@
+package xuperos
+
+import (
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+// GetSnapshotManifests 向accounts对应的节点查询最新的快照，返回节点id到manifest原始内容的映射
+func (t *NetEvent) GetSnapshotManifests(ctx xctx.XContext, bcName string, accounts []string) (map[string][]byte, error) {
+	if len(accounts) == 0 {
+		return nil, common.ErrParameter
+	}
+	msgOpts := []p2p.MessageOption{
+		p2p.WithBCName(bcName),
+		p2p.WithLogId(ctx.GetLog().GetLogId()),
+	}
+	input := &xpb.SnapshotManifestRequest{}
+	msg := p2p.NewMessage(protos.XuperMessage_GET_SNAPSHOT_MANIFEST, input, msgOpts...)
+	responses, err := t.engine.Context().Net.SendMessageWithResponse(ctx, msg, p2p.WithAccounts(accounts))
+	if err != nil {
+		return nil, common.ErrSendMessageFailed
+	}
+
+	manifests := make(map[string][]byte)
+	for _, response := range responses {
+		from := response.GetHeader().GetFrom()
+		if response.GetHeader().GetErrorType() != protos.XuperMessage_SUCCESS {
+			ctx.GetLog().Debug("GetSnapshotManifest response error", "errorType", response.GetHeader().GetErrorType(), "from", from)
+			continue
+		}
+
+		var output xpb.SnapshotManifest
+		if err := p2p.Unmarshal(response, &output); err != nil {
+			ctx.GetLog().Warn("GetSnapshotManifest unmarshal error", "error", err, "from", from)
+			continue
+		}
+		manifests[from] = output.Manifest
+	}
+
+	return manifests, nil
+}
+
+// GetSnapshotChunk 向指定节点下载快照分片
+func (t *NetEvent) GetSnapshotChunk(ctx xctx.XContext, bcName string, height int64, index int, from string) ([]byte, error) {
+	msgOpts := []p2p.MessageOption{
+		p2p.WithBCName(bcName),
+		p2p.WithLogId(ctx.GetLog().GetLogId()),
+	}
+	input := &xpb.SnapshotChunkRequest{
+		Height: height,
+		Index:  int32(index),
+	}
+	msg := p2p.NewMessage(protos.XuperMessage_GET_SNAPSHOT_CHUNK, input, msgOpts...)
+	responses, err := t.engine.Context().Net.SendMessageWithResponse(ctx, msg, p2p.WithPeerIDs([]string{from}))
+	if err != nil {
+		return nil, common.ErrSendMessageFailed
+	}
+
+	for _, response := range responses {
+		if response.GetHeader().GetErrorType() != protos.XuperMessage_SUCCESS {
+			ctx.GetLog().Warn("GetSnapshotChunk response error", "errorType", response.GetHeader().GetErrorType(), "from", response.GetHeader().GetFrom())
+			continue
+		}
+
+		var output xpb.SnapshotChunk
+		if err := p2p.Unmarshal(response, &output); err != nil {
+			ctx.GetLog().Warn("GetSnapshotChunk unmarshal error", "error", err, "from", response.GetHeader().GetFrom())
+			continue
+		}
+		if output.Height != height || int(output.Index) != index {
+			continue
+		}
+
+		return output.Data, nil
+	}
+
+	return nil, common.ErrNetworkNoResponse
+}
+
+func (t *NetEvent) handleGetSnapshotManifest(ctx xctx.XContext, request *protos.XuperMessage) (*protos.XuperMessage, error) {
+	var input xpb.SnapshotManifestRequest
+	var output *xpb.SnapshotManifest
+
+	bcName := request.GetHeader().GetBcname()
+	response := func(err error) (*protos.XuperMessage, error) {
+		opts := []p2p.MessageOption{
+			p2p.WithBCName(bcName),
+			p2p.WithErrorType(ErrorType(err)),
+			p2p.WithLogId(request.GetHeader().GetLogid()),
+		}
+		resp := p2p.NewMessage(p2p.GetRespMessageType(request.GetHeader().GetType()), output, opts...)
+		return resp, nil
+	}
+
+	err := p2p.Unmarshal(request, &input)
+	if err != nil {
+		ctx.GetLog().Error("unmarshal error", "bcName", bcName, "error", err)
+		return response(common.ErrParameter)
+	}
+
+	chain, err := t.engine.Get(bcName)
+	if err != nil {
+		ctx.GetLog().Warn("chain not exist", "error", err, "bcName", bcName)
+		return response(common.ErrChainNotExist)
+	}
+
+	producer := chain.Context().Snapshot
+	var data []byte
+	if input.Height == 0 {
+		_, data, err = producer.Latest()
+	} else {
+		_, data, err = producer.Get(input.Height)
+	}
+	if err != nil {
+		return response(common.ErrSnapshotNotExist)
+	}
+
+	output = &xpb.SnapshotManifest{Manifest: data}
+	return response(nil)
+}
+
+func (t *NetEvent) handleGetSnapshotChunk(ctx xctx.XContext, request *protos.XuperMessage) (*protos.XuperMessage, error) {
+	var input xpb.SnapshotChunkRequest
+	var output *xpb.SnapshotChunk
+
+	bcName := request.GetHeader().GetBcname()
+	response := func(err error) (*protos.XuperMessage, error) {
+		opts := []p2p.MessageOption{
+			p2p.WithBCName(bcName),
+			p2p.WithErrorType(ErrorType(err)),
+			p2p.WithLogId(request.GetHeader().GetLogid()),
+		}
+		resp := p2p.NewMessage(p2p.GetRespMessageType(request.GetHeader().GetType()), output, opts...)
+		return resp, nil
+	}
+
+	err := p2p.Unmarshal(request, &input)
+	if err != nil {
+		ctx.GetLog().Error("unmarshal error", "bcName", bcName, "error", err)
+		return response(common.ErrParameter)
+	}
+
+	chain, err := t.engine.Get(bcName)
+	if err != nil {
+		ctx.GetLog().Warn("chain not exist", "error", err, "bcName", bcName)
+		return response(common.ErrChainNotExist)
+	}
+
+	// 只提供仍在主干上的快照
+	producer := chain.Context().Snapshot
+	if _, _, err := producer.Get(input.Height); err != nil {
+		return response(common.ErrSnapshotNotExist)
+	}
+	data, err := producer.Store().Chunk(input.Height, int(input.Index))
+	if err != nil {
+		ctx.GetLog().Warn("read snapshot chunk error", "height", input.Height, "index", input.Index, "error", err)
+		return response(common.ErrSnapshotNotExist)
+	}
+
+	output = &xpb.SnapshotChunk{
+		Height: input.Height,
+		Index:  input.Index,
+		Data:   data,
+	}
+	return response(nil)
+}
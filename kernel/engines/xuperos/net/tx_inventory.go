@@ -0,0 +1,203 @@
+package xuperos
+
+import (
+	"time"
+
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/timer"
+	"github.com/xuperchain/xupercore/lib/utils"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+const (
+	// 默认交易ID公告间隔
+	DefTxAnnounceInterval = 500 * time.Millisecond
+	// 默认单次公告的交易ID数量上限
+	DefTxAnnounceBatchSize = 1000
+)
+
+// relayTxs 转发本地接收成功的交易
+// 完全广播模式直接转发完整交易，交易清单模式只将交易ID加入待公告列表
+func (t *NetEvent) relayTxs(ctx xctx.XContext, bcName string, msg *protos.XuperMessage, txs []*lpb.Transaction) {
+	if len(txs) == 0 {
+		return
+	}
+
+	if t.engine.Context().EngCfg.TxBroadcastMode != common.InventoryTxBroadCastMode {
+		go t.engine.Context().Net.SendMessage(ctx, msg)
+		return
+	}
+
+	for _, tx := range txs {
+		t.markTxSeen(bcName, tx.GetTxid())
+		t.announceTx(bcName, tx.GetTxid())
+	}
+}
+
+// announceTx 将交易ID加入待公告列表，达到批量上限时立即公告
+func (t *NetEvent) announceTx(bcName string, txid []byte) {
+	t.txInvMtx.Lock()
+	t.txInv[bcName] = append(t.txInv[bcName], txid)
+	if len(t.txInv[bcName]) < t.txAnnounceBatchSize() {
+		t.txInvMtx.Unlock()
+		return
+	}
+	txids := t.txInv[bcName]
+	delete(t.txInv, bcName)
+	t.txInvMtx.Unlock()
+
+	go t.sendTxIDs(bcName, txids)
+}
+
+// flushTxInv 公告所有待公告的交易ID
+func (t *NetEvent) flushTxInv() {
+	t.txInvMtx.Lock()
+	txInv := t.txInv
+	t.txInv = make(map[string][][]byte)
+	t.txInvMtx.Unlock()
+
+	for bcName, txids := range txInv {
+		go t.sendTxIDs(bcName, txids)
+	}
+}
+
+func (t *NetEvent) sendTxIDs(bcName string, txids [][]byte) {
+	if len(txids) == 0 {
+		return
+	}
+
+	msg := p2p.NewMessage(protos.XuperMessage_NEW_TXIDS, &xpb.TxIDs{Txids: txids}, p2p.WithBCName(bcName))
+	ctx := &xctx.BaseCtx{XLog: t.log, Timer: timer.NewXTimer()}
+	if err := t.engine.Context().Net.SendMessage(ctx, msg); err != nil {
+		t.log.Warn("announce tx ids error", "bcName", bcName, "count", len(txids), "error", err)
+	}
+}
+
+// handleNewTxIDs 收到交易ID公告，向公告方获取本地未知的交易
+func (t *NetEvent) handleNewTxIDs(ctx xctx.XContext, request *protos.XuperMessage) {
+	var input xpb.TxIDs
+	if err := p2p.Unmarshal(request, &input); err != nil {
+		ctx.GetLog().Warn("handleNewTxIDs Unmarshal request error", "error", err)
+		return
+	}
+
+	bcName := request.GetHeader().GetBcname()
+	chain, err := t.engine.Get(bcName)
+	if err != nil {
+		ctx.GetLog().Warn("chain not exist", "error", err, "bcName", bcName)
+		return
+	}
+
+	unknown := make([][]byte, 0, len(input.Txids))
+	for _, txid := range input.Txids {
+		if len(unknown) >= t.txAnnounceBatchSize() {
+			break
+		}
+		if t.isTxSeen(bcName, txid) || t.isTxExist(chain, txid) {
+			continue
+		}
+
+		t.markTxSeen(bcName, txid)
+		unknown = append(unknown, txid)
+	}
+	if len(unknown) == 0 {
+		return
+	}
+
+	msgOpts := []p2p.MessageOption{
+		p2p.WithBCName(bcName),
+		p2p.WithLogId(request.GetHeader().GetLogid()),
+	}
+	msg := p2p.NewMessage(protos.XuperMessage_GET_TXS, &xpb.TxIDs{Txids: unknown}, msgOpts...)
+	err = t.engine.Context().Net.SendMessage(ctx, msg, p2p.WithPeerIDs([]string{request.GetHeader().GetFrom()}))
+	if err != nil {
+		ctx.GetLog().Warn("get txs error", "bcName", bcName, "count", len(unknown), "error", err)
+		// 获取失败允许后续的公告重新获取
+		for _, txid := range unknown {
+			t.seenTxs.Delete(seenTxKey(bcName, txid))
+		}
+	}
+}
+
+// handleGetTxs 通过BATCHPOSTTX消息向请求方发送本地未确认的交易
+func (t *NetEvent) handleGetTxs(ctx xctx.XContext, request *protos.XuperMessage) {
+	var input xpb.TxIDs
+	if err := p2p.Unmarshal(request, &input); err != nil {
+		ctx.GetLog().Warn("handleGetTxs Unmarshal request error", "error", err)
+		return
+	}
+
+	bcName := request.GetHeader().GetBcname()
+	chain, err := t.engine.Get(bcName)
+	if err != nil {
+		ctx.GetLog().Warn("chain not exist", "error", err, "bcName", bcName)
+		return
+	}
+
+	output := &xpb.Transactions{}
+	for _, txid := range input.Txids {
+		if len(output.Txs) >= t.txAnnounceBatchSize() {
+			break
+		}
+
+		tx, confirmed, err := chain.Context().State.QueryTx(txid)
+		if err != nil || confirmed {
+			ctx.GetLog().Trace("tx not in unconfirmed table", "txid", utils.F(txid), "confirmed", confirmed, "error", err)
+			continue
+		}
+		output.Txs = append(output.Txs, tx)
+	}
+	if len(output.Txs) == 0 {
+		return
+	}
+
+	msgOpts := []p2p.MessageOption{
+		p2p.WithBCName(bcName),
+		p2p.WithLogId(request.GetHeader().GetLogid()),
+	}
+	msg := p2p.NewMessage(protos.XuperMessage_BATCHPOSTTX, output, msgOpts...)
+	err = t.engine.Context().Net.SendMessage(ctx, msg, p2p.WithPeerIDs([]string{request.GetHeader().GetFrom()}))
+	if err != nil {
+		ctx.GetLog().Warn("send txs error", "bcName", bcName, "count", len(output.Txs), "error", err)
+	}
+}
+
+func (t *NetEvent) isTxExist(chain common.Chain, txid []byte) bool {
+	if exist, _ := chain.Context().State.HasTx(txid); exist {
+		return true
+	}
+
+	exist, _ := chain.Context().Ledger.HasTransaction(txid)
+	return exist
+}
+
+func (t *NetEvent) isTxSeen(bcName string, txid []byte) bool {
+	_, ok := t.seenTxs.Get(seenTxKey(bcName, txid))
+	return ok
+}
+
+func (t *NetEvent) markTxSeen(bcName string, txid []byte) {
+	t.seenTxs.SetDefault(seenTxKey(bcName, txid), true)
+}
+
+func (t *NetEvent) txAnnounceInterval() time.Duration {
+	if interval := t.engine.Context().EngCfg.TxAnnounceInterval; interval > 0 {
+		return interval
+	}
+	return DefTxAnnounceInterval
+}
+
+func (t *NetEvent) txAnnounceBatchSize() int {
+	if size := t.engine.Context().EngCfg.TxAnnounceBatchSize; size > 0 {
+		return size
+	}
+	return DefTxAnnounceBatchSize
+}
+
+func seenTxKey(bcName string, txid []byte) string {
+	return bcName + "/" + string(txid)
+}
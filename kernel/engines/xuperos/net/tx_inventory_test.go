@@ -0,0 +1,439 @@
+package xuperos
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
+	sctx "github.com/xuperchain/xupercore/bcs/ledger/xledger/state/context"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state/utxo/txhash"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/tx"
+	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
+	"github.com/xuperchain/xupercore/kernel/common/xaddress"
+	xconf "github.com/xuperchain/xupercore/kernel/common/xconfig"
+	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	engconf "github.com/xuperchain/xupercore/kernel/engines/xuperos/config"
+	"github.com/xuperchain/xupercore/kernel/engines/xuperos/xpb"
+	"github.com/xuperchain/xupercore/kernel/mock"
+	nctx "github.com/xuperchain/xupercore/kernel/network/context"
+	"github.com/xuperchain/xupercore/kernel/network/p2p"
+	"github.com/xuperchain/xupercore/lib/crypto/client"
+	"github.com/xuperchain/xupercore/lib/crypto/client/base"
+	"github.com/xuperchain/xupercore/lib/logs"
+	"github.com/xuperchain/xupercore/lib/timer"
+	"github.com/xuperchain/xupercore/protos"
+)
+
+const (
+	testBCName = "xuper"
+	testPeer   = "remote-peer"
+	testKeyDir = "../../../mock/p2pv2/node1/data/keys"
+)
+
+// sentMessage 记录mockNetwork发送的消息及目标节点
+type sentMessage struct {
+	msg     *protos.XuperMessage
+	peerIDs []string
+}
+
+type mockNetwork struct {
+	mutex sync.Mutex
+	sent  []*sentMessage
+	err   error
+	// respond 处理需要响应的消息
+	respond func(*protos.XuperMessage) ([]*protos.XuperMessage, error)
+}
+
+func (n *mockNetwork) Start() {}
+func (n *mockNetwork) Stop()  {}
+
+func (n *mockNetwork) SendMessage(_ xctx.XContext, msg *protos.XuperMessage, opts ...p2p.OptionFunc) error {
+	opt := &p2p.Option{}
+	for _, f := range opts {
+		f(opt)
+	}
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.sent = append(n.sent, &sentMessage{msg: msg, peerIDs: opt.PeerIDs})
+	return n.err
+}
+
+func (n *mockNetwork) SendMessageWithResponse(_ xctx.XContext, msg *protos.XuperMessage,
+	_ ...p2p.OptionFunc) ([]*protos.XuperMessage, error) {
+	if n.respond == nil {
+		return nil, errors.New("not implemented")
+	}
+	return n.respond(msg)
+}
+
+func (n *mockNetwork) NewSubscriber(protos.XuperMessage_MessageType, interface{}, ...p2p.SubscriberOption) p2p.Subscriber {
+	return nil
+}
+func (n *mockNetwork) Register(p2p.Subscriber) error   { return nil }
+func (n *mockNetwork) UnRegister(p2p.Subscriber) error { return nil }
+func (n *mockNetwork) Context() *nctx.NetCtx           { return nil }
+func (n *mockNetwork) PeerInfo() protos.PeerInfo       { return protos.PeerInfo{} }
+func (n *mockNetwork) ServeChain(string, []string)     {}
+func (n *mockNetwork) LeaveChain(string)               {}
+
+// waitSent 等待异步发送的消息数达到n
+func (n *mockNetwork) waitSent(t *testing.T, count int) []*sentMessage {
+	for i := 0; i < 100; i++ {
+		n.mutex.Lock()
+		sent := append([]*sentMessage(nil), n.sent...)
+		n.mutex.Unlock()
+		if len(sent) >= count {
+			return sent
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("wait %d sent messages timeout", count)
+	return nil
+}
+
+func (n *mockNetwork) reset() {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	n.sent = nil
+}
+
+type mockChain struct {
+	ctx *common.ChainCtx
+	// blocks 收到的区块
+	blocks []*lpb.InternalBlock
+}
+
+func (c *mockChain) Context() *common.ChainCtx { return c.ctx }
+func (c *mockChain) Start()                    {}
+func (c *mockChain) Stop()                     {}
+func (c *mockChain) PreExec(xctx.XContext, []*protos.InvokeRequest, string, []string) (*protos.InvokeResponse, error) {
+	return nil, errors.New("not implemented")
+}
+func (c *mockChain) SubmitTx(xctx.XContext, *lpb.Transaction) error { return nil }
+func (c *mockChain) ProcBlock(_ xctx.XContext, block *lpb.InternalBlock) error {
+	c.blocks = append(c.blocks, block)
+	return nil
+}
+func (c *mockChain) SetRelyAgent(common.ChainRelyAgent) error { return nil }
+
+type mockEngine struct {
+	ctx    *common.EngineCtx
+	chains map[string]common.Chain
+}
+
+func (e *mockEngine) Init(*xconf.EnvConf) error { return nil }
+func (e *mockEngine) Run()                      {}
+func (e *mockEngine) Exit()                     {}
+func (e *mockEngine) Get(name string) (common.Chain, error) {
+	if chain, ok := e.chains[name]; ok {
+		return chain, nil
+	}
+	return nil, common.ErrChainNotExist
+}
+func (e *mockEngine) GetChains() []string                       { return nil }
+func (e *mockEngine) LoadChain(string) error                    { return nil }
+func (e *mockEngine) Stop(string) error                         { return nil }
+func (e *mockEngine) Context() *common.EngineCtx                { return e.ctx }
+func (e *mockEngine) SetRelyAgent(common.EngineRelyAgent) error { return nil }
+
+// testChain 内存存储引擎上的账本和状态机，genesisTx已确认，pendingTx在未确认交易表中
+type testChain struct {
+	ledger    *ledger.Ledger
+	state     *state.State
+	genesisTx *lpb.Transaction
+	rootBlock *lpb.InternalBlock
+	pendingTx *lpb.Transaction
+}
+
+func newTestChain(t *testing.T) *testChain {
+	econf, err := mock.NewMemoryEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesisConf, err := ioutil.ReadFile(econf.GenDataAbsPath("genesis/xuper.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lctx, err := ledger.NewLedgerCtx(econf, testBCName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lctx.EnvCfg.ChainDir = fmt.Sprintf("/memory/net_test/%s/%d", t.Name(), time.Now().UnixNano())
+	leg, err := ledger.CreateLedger(lctx, genesisConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(leg.Close)
+
+	genesisTx, err := tx.GenerateRootTx(genesisConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rootBlock, err := leg.FormatRootBlock([]*lpb.Transaction{genesisTx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := leg.ConfirmBlock(rootBlock, true); !status.Succ {
+		t.Fatal("confirm root block fail", status.Error)
+	}
+
+	crypt, err := client.CreateCryptoClient(client.CryptoTypeDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stateCtx, err := sctx.NewStateCtx(econf, testBCName, leg, crypt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sta, err := state.NewState(stateCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sta.Close() })
+	if err := sta.Play(rootBlock.Blockid); err != nil {
+		t.Fatal(err)
+	}
+
+	pendingTx := newTransferTx(t, crypt, genesisTx)
+	if err := sta.DoTx(pendingTx); err != nil {
+		t.Fatal(err)
+	}
+
+	return &testChain{
+		ledger:    leg,
+		state:     sta,
+		genesisTx: genesisTx,
+		rootBlock: rootBlock,
+		pendingTx: pendingTx,
+	}
+}
+
+// newTransferTx 构造花费创世交易输出的转账交易
+func newTransferTx(t *testing.T, crypt base.CryptoClient, genesisTx *lpb.Transaction) *lpb.Transaction {
+	addr, err := xaddress.LoadAddrInfo(testKeyDir, crypt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	total := big.NewInt(0).SetBytes(genesisTx.TxOutputs[0].Amount)
+	amount := big.NewInt(10000)
+	transfer := &lpb.Transaction{
+		Version:     1,
+		Desc:        []byte("transfer"),
+		Nonce:       "nonce",
+		Timestamp:   time.Now().UnixNano(),
+		Initiator:   addr.Address,
+		AuthRequire: []string{addr.Address},
+		TxInputs: []*protos.TxInput{
+			{
+				RefTxid:   genesisTx.Txid,
+				RefOffset: 0,
+				FromAddr:  []byte(addr.Address),
+				Amount:    total.Bytes(),
+			},
+		},
+		TxOutputs: []*protos.TxOutput{
+			{ToAddr: []byte("SmJG3rH2ZzYQ9ojxhbRCPwFiE9y6pD1Co"), Amount: amount.Bytes()},
+			{ToAddr: []byte(addr.Address), Amount: big.NewInt(0).Sub(total, amount).Bytes()},
+		},
+	}
+
+	sign, err := txhash.ProcessSignTx(crypt, transfer, []byte(addr.PrivateKeyStr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signs := []*protos.SignatureInfo{{PublicKey: addr.PublicKeyStr, Sign: sign}}
+	transfer.InitiatorSigns = signs
+	transfer.AuthRequireSigns = signs
+	transfer.Txid, err = txhash.MakeTransactionID(transfer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return transfer
+}
+
+// newTestNetEvent 构造只依赖mock引擎和网络的NetEvent，不注册订阅
+func newTestNetEvent(t *testing.T, engCfg *engconf.EngineConf, chain *testChain) (*NetEvent, *mockNetwork) {
+	if err := mock.InitLogForTest(); err != nil {
+		t.Fatal(err)
+	}
+	log, err := logs.NewLogger("", "net_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	net := &mockNetwork{}
+	engCtx := &common.EngineCtx{
+		BaseCtx: xctx.BaseCtx{XLog: log, Timer: timer.NewXTimer()},
+		EngCfg:  engCfg,
+		Net:     net,
+	}
+	engine := &mockEngine{ctx: engCtx, chains: make(map[string]common.Chain)}
+	if chain != nil {
+		engine.chains[testBCName] = &mockChain{ctx: &common.ChainCtx{
+			BaseCtx: xctx.BaseCtx{XLog: log, Timer: timer.NewXTimer()},
+			EngCtx:  engCtx,
+			BCName:  testBCName,
+			Ledger:  chain.ledger,
+			State:   chain.state,
+		}}
+	}
+
+	return &NetEvent{
+		log:     log,
+		engine:  engine,
+		seenTxs: cache.New(engCfg.TxIdCacheExpiredTime, engCfg.TxIdCacheGCInterval),
+		txInv:   make(map[string][][]byte),
+	}, net
+}
+
+func newTestCtx(t *testing.T) xctx.XContext {
+	log, err := logs.NewLogger("", "net_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &xctx.BaseCtx{XLog: log, Timer: timer.NewXTimer()}
+}
+
+func newTxIDsMessage(t *testing.T, typ protos.XuperMessage_MessageType, txids ...[]byte) *protos.XuperMessage {
+	msg := p2p.NewMessage(typ, &xpb.TxIDs{Txids: txids}, p2p.WithBCName(testBCName))
+	msg.Header.From = testPeer
+	return msg
+}
+
+func unmarshalTxIDs(t *testing.T, msg *protos.XuperMessage) [][]byte {
+	var txids xpb.TxIDs
+	if err := p2p.Unmarshal(msg, &txids); err != nil {
+		t.Fatal(err)
+	}
+	return txids.Txids
+}
+
+func TestRelayTxs(t *testing.T) {
+	engCfg := engconf.GetDefEngineConf()
+	engCfg.TxBroadcastMode = common.InventoryTxBroadCastMode
+	engCfg.TxAnnounceBatchSize = 2
+	event, net := newTestNetEvent(t, engCfg, nil)
+
+	txs := []*lpb.Transaction{{Txid: []byte("tx1")}, {Txid: []byte("tx2")}, {Txid: []byte("tx3")}}
+	event.relayTxs(newTestCtx(t), testBCName, nil, txs)
+
+	// 达到批量上限的交易ID立即公告，剩余的等待定时公告
+	sent := net.waitSent(t, 1)
+	if sent[0].msg.GetHeader().GetType() != protos.XuperMessage_NEW_TXIDS {
+		t.Fatalf("unexpected message type: %v", sent[0].msg.GetHeader().GetType())
+	}
+	if txids := unmarshalTxIDs(t, sent[0].msg); len(txids) != 2 || !bytes.Equal(txids[0], []byte("tx1")) {
+		t.Fatalf("unexpected announced txids: %q", txids)
+	}
+	for _, tx := range txs {
+		if !event.isTxSeen(testBCName, tx.Txid) {
+			t.Errorf("relayed tx should be seen: %s", tx.Txid)
+		}
+	}
+
+	event.flushTxInv()
+	sent = net.waitSent(t, 2)
+	if txids := unmarshalTxIDs(t, sent[1].msg); len(txids) != 1 || !bytes.Equal(txids[0], []byte("tx3")) {
+		t.Fatalf("unexpected flushed txids: %q", txids)
+	}
+	if len(event.txInv) != 0 {
+		t.Errorf("tx inventory should be empty after flush")
+	}
+
+	// 完全广播模式直接转发原消息
+	engCfg.TxBroadcastMode = common.FullTxBroadCastMode
+	net.reset()
+	msg := p2p.NewMessage(protos.XuperMessage_POSTTX, txs[0], p2p.WithBCName(testBCName))
+	event.relayTxs(newTestCtx(t), testBCName, msg, txs[:1])
+	if sent := net.waitSent(t, 1); sent[0].msg != msg {
+		t.Errorf("full broadcast mode should relay original message")
+	}
+}
+
+func TestHandleNewTxIDs(t *testing.T) {
+	chain := newTestChain(t)
+	engCfg := engconf.GetDefEngineConf()
+	engCfg.TxBroadcastMode = common.InventoryTxBroadCastMode
+	event, net := newTestNetEvent(t, engCfg, chain)
+	ctx := newTestCtx(t)
+
+	// 已确认、已在交易池中的交易不再获取
+	unknown := []byte("unknown-tx")
+	event.handleNewTxIDs(ctx, newTxIDsMessage(t, protos.XuperMessage_NEW_TXIDS,
+		chain.genesisTx.Txid, chain.pendingTx.Txid, unknown))
+	sent := net.waitSent(t, 1)
+	if sent[0].msg.GetHeader().GetType() != protos.XuperMessage_GET_TXS {
+		t.Fatalf("unexpected message type: %v", sent[0].msg.GetHeader().GetType())
+	}
+	if len(sent[0].peerIDs) != 1 || sent[0].peerIDs[0] != testPeer {
+		t.Errorf("get txs should be sent to announcer: %v", sent[0].peerIDs)
+	}
+	if txids := unmarshalTxIDs(t, sent[0].msg); len(txids) != 1 || !bytes.Equal(txids[0], unknown) {
+		t.Fatalf("unexpected requested txids: %q", txids)
+	}
+
+	// 已请求过的交易不重复获取
+	event.handleNewTxIDs(ctx, newTxIDsMessage(t, protos.XuperMessage_NEW_TXIDS, unknown))
+	if len(net.sent) != 1 {
+		t.Errorf("seen tx should not be requested again")
+	}
+
+	// 获取失败后允许重新获取
+	net.reset()
+	net.err = errors.New("send error")
+	other := []byte("other-tx")
+	event.handleNewTxIDs(ctx, newTxIDsMessage(t, protos.XuperMessage_NEW_TXIDS, other))
+	if event.isTxSeen(testBCName, other) {
+		t.Errorf("failed request should unmark seen tx")
+	}
+	net.err = nil
+	event.handleNewTxIDs(ctx, newTxIDsMessage(t, protos.XuperMessage_NEW_TXIDS, other))
+	if len(net.sent) != 2 {
+		t.Errorf("tx should be requested again after failure, sent %d", len(net.sent))
+	}
+}
+
+func TestHandleGetTxs(t *testing.T) {
+	chain := newTestChain(t)
+	event, net := newTestNetEvent(t, engconf.GetDefEngineConf(), chain)
+	ctx := newTestCtx(t)
+
+	// 只返回未确认的交易
+	event.handleGetTxs(ctx, newTxIDsMessage(t, protos.XuperMessage_GET_TXS,
+		chain.genesisTx.Txid, chain.pendingTx.Txid, []byte("unknown-tx")))
+	sent := net.waitSent(t, 1)
+	if sent[0].msg.GetHeader().GetType() != protos.XuperMessage_BATCHPOSTTX {
+		t.Fatalf("unexpected message type: %v", sent[0].msg.GetHeader().GetType())
+	}
+	if len(sent[0].peerIDs) != 1 || sent[0].peerIDs[0] != testPeer {
+		t.Errorf("txs should be sent to requester: %v", sent[0].peerIDs)
+	}
+	var output xpb.Transactions
+	if err := p2p.Unmarshal(sent[0].msg, &output); err != nil {
+		t.Fatal(err)
+	}
+	if len(output.Txs) != 1 || !bytes.Equal(output.Txs[0].Txid, chain.pendingTx.Txid) {
+		t.Fatalf("unexpected response txs: %v", output.Txs)
+	}
+
+	// 没有可返回的交易时不发送
+	net.reset()
+	event.handleGetTxs(ctx, newTxIDsMessage(t, protos.XuperMessage_GET_TXS, chain.genesisTx.Txid))
+	if len(net.sent) != 0 {
+		t.Errorf("no response expected for confirmed txs")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/utils"
 	"github.com/xuperchain/xupercore/kernel/contract"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
+	kledger "github.com/xuperchain/xupercore/kernel/ledger"
 	"github.com/xuperchain/xupercore/protos"
 )
 
@@ -78,7 +79,12 @@ func (p *paraChainContract) handleCreateChain(ctx common.TaskContext) error {
 	if !haveAccess {
 		return nil
 	}
-	return p.doCreateChain(args.BcName, args.GenesisConfig)
+	if err := p.doCreateChain(args.BcName, args.GenesisConfig); err != nil {
+		return err
+	}
+	// 平行链消息只在群组成员间路由
+	p.ChainCtx.EngCtx.Net.ServeChain(args.BcName, args.Group.Members())
+	return nil
 }
 
 func (p *paraChainContract) doCreateChain(bcName string, bcGenesisConfig string) error {
@@ -108,6 +114,7 @@ func (p *paraChainContract) handleStopChain(ctx common.TaskContext) error {
 }
 
 func (p *paraChainContract) doStopChain(bcName string) error {
+	p.ChainCtx.EngCtx.Net.LeaveChain(bcName)
 	if _, err := p.ChainCtx.EngCtx.ChainM.Get(bcName); err != nil {
 		p.ChainCtx.XLog.Warn("Chain hasn't been loaded yet", "chain", bcName)
 		return nil
@@ -124,7 +131,12 @@ func (p *paraChainContract) handleRefreshChain(ctx common.TaskContext) error {
 	// 根据当前节点目前是否有权限获取该链，决定当前是停掉链还是加载链
 	haveAccess := isContain(args.Group.Admin, p.ChainCtx.Address.Address) || isContain(args.Group.Identities, p.ChainCtx.Address.Address)
 	if haveAccess {
-		return p.doCreateChain(args.BcName, args.GenesisConfig)
+		if err := p.doCreateChain(args.BcName, args.GenesisConfig); err != nil {
+			return err
+		}
+		// 群组变更后刷新平行链的路由成员
+		p.ChainCtx.EngCtx.Net.ServeChain(args.BcName, args.Group.Members())
+		return nil
 	}
 	return p.doStopChain(args.BcName)
 }
@@ -296,6 +308,35 @@ type Group struct {
 	Identities []string `json:"identities,omitempty"`
 }
 
+// Members 返回群组内所有成员账户，包括管理员
+func (g *Group) Members() []string {
+	members := make([]string, 0, len(g.Admin)+len(g.Identities))
+	members = append(members, g.Admin...)
+	for _, id := range g.Identities {
+		if !isContain(members, id) {
+			members = append(members, id)
+		}
+	}
+	return members
+}
+
+// GetChainGroup 从主链状态读取平行链群组，群组不存在时返回nil
+func GetChainGroup(reader kledger.XMReader, bcName string) (*Group, error) {
+	value, err := reader.Get(ParaChainKernelContract, []byte(bcName))
+	if err != nil {
+		return nil, err
+	}
+	if len(value.GetPureData().GetValue()) == 0 {
+		return nil, nil
+	}
+
+	group := &Group{}
+	if err := json.Unmarshal(value.GetPureData().GetValue(), group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
 // methodEditGroup 控制平行链对应的权限管理，被称为平行链群组or群组，旨在向外提供平行链权限信息
 func (p *paraChainContract) editGroup(ctx contract.KContext) (*contract.Response, error) {
 	group := &Group{}
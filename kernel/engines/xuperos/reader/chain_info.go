@@ -3,6 +3,7 @@ package reader
 import (
 	"bytes"
 	"fmt"
+	"math/big"
 
 	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
@@ -21,6 +22,8 @@ type ChainReader interface {
 	GetNetURL() (string, error)
 	// 获取共识状态
 	GetConsensusStatus() (*xpb.ConsensusStatus, error)
+	// 估算下一个区块中消耗gasUsed的交易需要的手续费
+	EstimateFee(gasUsed int64) (*xpb.FeeEstimate, error)
 }
 
 type chainReader struct {
@@ -116,3 +119,30 @@ func (t *chainReader) GetNetURL() (string, error) {
 	peerInfo := t.chainCtx.EngCtx.Net.PeerInfo()
 	return peerInfo.Address, nil
 }
+
+func (t *chainReader) EstimateFee(gasUsed int64) (*xpb.FeeEstimate, error) {
+	if gasUsed < 0 {
+		return nil, common.ErrParameter
+	}
+	out := &xpb.FeeEstimate{
+		Height:  t.chainCtx.Ledger.GetMeta().GetTrunkHeight() + 1,
+		GasUsed: gasUsed,
+	}
+	baseFee, err := t.chainCtx.State.NextBaseFee()
+	if err != nil {
+		t.log.Warn("calc next base fee error", "err", err)
+		return nil, common.ErrEstimateFeeFailed
+	}
+	// 手续费需不低于gas消耗，开启费用市场时还需支付基础费用
+	minFee := big.NewInt(gasUsed)
+	out.BaseFee = "0"
+	if baseFee != nil {
+		out.Enabled = true
+		out.BaseFee = baseFee.String()
+		if fee := big.NewInt(0).Mul(big.NewInt(gasUsed), baseFee); fee.Cmp(minFee) > 0 {
+			minFee = fee
+		}
+	}
+	out.MinFee = minFee.String()
+	return out, nil
+}
@@ -2,6 +2,8 @@ package reader
 
 import (
 	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
+	"github.com/xuperchain/xupercore/kernel/contract/abi"
+	"github.com/xuperchain/xupercore/kernel/contract/bridge"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
 	"github.com/xuperchain/xupercore/lib/logs"
 	"github.com/xuperchain/xupercore/protos"
@@ -22,6 +24,16 @@ type ContractReader interface {
 	QueryContractMethodACL(contract, method string) (*protos.Acl, error)
 	// 查询账户治理代币余额
 	QueryAccountGovernTokenBalance(account string) (*protos.GovernTokenBalance, error)
+	// 查询合约版本历史
+	QueryContractHistory(contractName string) ([]*protos.ContractVersion, error)
+	// 查询合约存储用量和配额
+	QueryContractStorage(contractName string) (*protos.ContractStorage, error)
+	// 查询所有合约的存储用量
+	QueryStorageUsage() ([]*protos.ContractStorage, error)
+	// 查询合约ABI
+	QueryContractAbi(contractName string) (*protos.ContractAbi, error)
+	// 按合约ABI将事件body解码为json
+	DecodeContractEvent(event *protos.ContractEvent) ([]byte, error)
 }
 
 type contractReader struct {
@@ -130,3 +142,64 @@ func (t *contractReader) QueryAccountGovernTokenBalance(account string) (*protos
 
 	return amount, nil
 }
+
+func (t *contractReader) QueryContractHistory(contractName string) ([]*protos.ContractVersion, error) {
+	history, err := t.chainCtx.State.QueryContractHistory(contractName)
+	if err != nil {
+		return nil, common.CastError(err)
+	}
+
+	return history, nil
+}
+
+func (t *contractReader) QueryContractStorage(contractName string) (*protos.ContractStorage, error) {
+	storage, err := t.chainCtx.State.QueryContractStorage(contractName)
+	if err != nil {
+		return nil, common.CastError(err)
+	}
+
+	return storage, nil
+}
+
+func (t *contractReader) QueryStorageUsage() ([]*protos.ContractStorage, error) {
+	usage, err := t.chainCtx.State.QueryStorageUsage()
+	if err != nil {
+		return nil, common.CastError(err)
+	}
+
+	return usage, nil
+}
+
+func (t *contractReader) QueryContractAbi(contractName string) (*protos.ContractAbi, error) {
+	contractAbi, err := t.chainCtx.State.QueryContractAbi(contractName)
+	if err != nil {
+		return nil, common.CastError(err)
+	}
+
+	return contractAbi, nil
+}
+
+func (t *contractReader) DecodeContractEvent(event *protos.ContractEvent) ([]byte, error) {
+	if event == nil {
+		return nil, common.ErrParameter
+	}
+	contractAbi, err := t.chainCtx.State.QueryContractAbi(event.GetContract())
+	if err != nil {
+		return nil, common.CastError(err)
+	}
+	// evm合约的事件在执行时已经按ABI解码
+	if contractAbi.GetContractType() == string(bridge.TypeEvm) {
+		return event.GetBody(), nil
+	}
+
+	typedAbi, err := abi.Parse(contractAbi.GetAbi())
+	if err != nil {
+		return nil, common.CastError(err)
+	}
+	body, err := typedAbi.DecodeEvent(event.GetName(), event.GetBody())
+	if err != nil {
+		return nil, common.ErrParameter.More("%v", err)
+	}
+
+	return body, nil
+}
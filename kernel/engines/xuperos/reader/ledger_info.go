@@ -2,6 +2,7 @@ package reader
 
 import (
 	"github.com/xuperchain/xupercore/bcs/ledger/xledger/ledger"
+	"github.com/xuperchain/xupercore/bcs/ledger/xledger/state"
 	lpb "github.com/xuperchain/xupercore/bcs/ledger/xledger/xldgpb"
 	xctx "github.com/xuperchain/xupercore/kernel/common/xcontext"
 	"github.com/xuperchain/xupercore/kernel/engines/xuperos/common"
@@ -17,6 +18,10 @@ type LedgerReader interface {
 	QueryBlock(blkId []byte, needContent bool) (*xpb.BlockInfo, error)
 	// 通过区块高度查询区块信息（GetBlockByHeight）
 	QueryBlockByHeight(height int64, needContent bool) (*xpb.BlockInfo, error)
+	// 查询已确认交易及其merkle路径和区块头，用于轻节点校验
+	QueryTxProof(txId []byte) (*lpb.TxMerkleProof, error)
+	// 查询状态数据及其在区块状态根下的证明，key由state.XModelStateKey或state.UtxoStateKey生成
+	QueryStateProof(blkId []byte, key []byte) (*xpb.StateProof, error)
 }
 
 type ledgerReader struct {
@@ -71,6 +76,7 @@ func (t *ledgerReader) QueryTx(txId []byte) (*xpb.TxInfo, error) {
 	t.log.Debug("query block succeeded", "txId", utils.F(txId), "blockId", utils.F(tx.Blockid))
 	meta := t.chainCtx.Ledger.GetMeta()
 	out.Tx = tx
+	out.Redacted = ledger.IsRedactedTx(tx)
 	if block.InTrunk {
 		out.Distance = meta.TrunkHeight - block.Height
 		out.Status = lpb.TransactionStatus_TX_CONFIRM
@@ -134,3 +140,42 @@ func (t *ledgerReader) QueryBlockByHeight(height int64, needContent bool) (*xpb.
 
 	return out, nil
 }
+
+func (t *ledgerReader) QueryTxProof(txId []byte) (*lpb.TxMerkleProof, error) {
+	proof, err := t.chainCtx.Ledger.QueryTxMerkleProof(txId)
+	if err != nil {
+		t.log.Warn("query tx merkle proof error", "txId", utils.F(txId), "err", err)
+		if err == ledger.ErrTxNotFound {
+			return nil, common.ErrTxNotExist
+		}
+		return nil, common.ErrInternal
+	}
+
+	return proof, nil
+}
+
+func (t *ledgerReader) QueryStateProof(blkId []byte, key []byte) (*xpb.StateProof, error) {
+	if !t.chainCtx.Ledger.ExistBlock(blkId) {
+		return nil, common.ErrBlockNotExist
+	}
+
+	proof, root, err := t.chainCtx.State.GetStateProof(blkId, key)
+	if err != nil {
+		t.log.Warn("query state proof error", "blockId", utils.F(blkId), "err", err)
+		if err == state.ErrStateRootUnsupported {
+			return nil, common.ErrParameter
+		}
+		return nil, common.ErrInternal
+	}
+
+	return &xpb.StateProof{
+		Blockid:       blkId,
+		StateRoot:     root,
+		Key:           proof.Key,
+		Value:         proof.Value,
+		Exist:         proof.Exist,
+		Siblings:      proof.Siblings,
+		LeafPath:      proof.LeafPath,
+		LeafValueHash: proof.LeafValueHash,
+	}, nil
+}
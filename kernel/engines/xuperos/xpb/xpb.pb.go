@@ -60,22 +60,399 @@ func (m *Transactions) GetTxs() []*xldgpb.Transaction {
 	return nil
 }
 
+type TxIDs struct {
+	Txids                [][]byte `protobuf:"bytes,1,rep,name=txids,proto3" json:"txids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TxIDs) Reset()         { *m = TxIDs{} }
+func (m *TxIDs) String() string { return proto.CompactTextString(m) }
+func (*TxIDs) ProtoMessage()    {}
+func (*TxIDs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{1}
+}
+
+func (m *TxIDs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxIDs.Unmarshal(m, b)
+}
+func (m *TxIDs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxIDs.Marshal(b, m, deterministic)
+}
+func (m *TxIDs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxIDs.Merge(m, src)
+}
+func (m *TxIDs) XXX_Size() int {
+	return xxx_messageInfo_TxIDs.Size(m)
+}
+func (m *TxIDs) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxIDs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxIDs proto.InternalMessageInfo
+
+func (m *TxIDs) GetTxids() [][]byte {
+	if m != nil {
+		return m.Txids
+	}
+	return nil
+}
+
+type CompactBlock struct {
+	// 不包含交易内容和merkle树的区块
+	Header *xldgpb.InternalBlock `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
+	// 区块内交易的短ID，按区块内交易顺序排列
+	ShortTxids [][]byte `protobuf:"bytes,2,rep,name=short_txids,json=shortTxids,proto3" json:"short_txids,omitempty"`
+	// 对方交易池中不存在的交易，例如coinbase和autogen交易
+	PrefilledTxs         []*xldgpb.Transaction `protobuf:"bytes,3,rep,name=prefilled_txs,json=prefilledTxs,proto3" json:"prefilled_txs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *CompactBlock) Reset()         { *m = CompactBlock{} }
+func (m *CompactBlock) String() string { return proto.CompactTextString(m) }
+func (*CompactBlock) ProtoMessage()    {}
+func (*CompactBlock) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{2}
+}
+
+func (m *CompactBlock) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompactBlock.Unmarshal(m, b)
+}
+func (m *CompactBlock) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompactBlock.Marshal(b, m, deterministic)
+}
+func (m *CompactBlock) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompactBlock.Merge(m, src)
+}
+func (m *CompactBlock) XXX_Size() int {
+	return xxx_messageInfo_CompactBlock.Size(m)
+}
+func (m *CompactBlock) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompactBlock.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompactBlock proto.InternalMessageInfo
+
+func (m *CompactBlock) GetHeader() *xldgpb.InternalBlock {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *CompactBlock) GetShortTxids() [][]byte {
+	if m != nil {
+		return m.ShortTxids
+	}
+	return nil
+}
+
+func (m *CompactBlock) GetPrefilledTxs() []*xldgpb.Transaction {
+	if m != nil {
+		return m.PrefilledTxs
+	}
+	return nil
+}
+
+type BlockTxsRequest struct {
+	Blockid []byte `protobuf:"bytes,1,opt,name=blockid,proto3" json:"blockid,omitempty"`
+	// 缺失交易在区块内的下标
+	Indexes              []uint32 `protobuf:"varint,2,rep,packed,name=indexes,proto3" json:"indexes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockTxsRequest) Reset()         { *m = BlockTxsRequest{} }
+func (m *BlockTxsRequest) String() string { return proto.CompactTextString(m) }
+func (*BlockTxsRequest) ProtoMessage()    {}
+func (*BlockTxsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{3}
+}
+
+func (m *BlockTxsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockTxsRequest.Unmarshal(m, b)
+}
+func (m *BlockTxsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockTxsRequest.Marshal(b, m, deterministic)
+}
+func (m *BlockTxsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockTxsRequest.Merge(m, src)
+}
+func (m *BlockTxsRequest) XXX_Size() int {
+	return xxx_messageInfo_BlockTxsRequest.Size(m)
+}
+func (m *BlockTxsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockTxsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockTxsRequest proto.InternalMessageInfo
+
+func (m *BlockTxsRequest) GetBlockid() []byte {
+	if m != nil {
+		return m.Blockid
+	}
+	return nil
+}
+
+func (m *BlockTxsRequest) GetIndexes() []uint32 {
+	if m != nil {
+		return m.Indexes
+	}
+	return nil
+}
+
+type BlockTxs struct {
+	Blockid              []byte                `protobuf:"bytes,1,opt,name=blockid,proto3" json:"blockid,omitempty"`
+	Txs                  []*xldgpb.Transaction `protobuf:"bytes,2,rep,name=txs,proto3" json:"txs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *BlockTxs) Reset()         { *m = BlockTxs{} }
+func (m *BlockTxs) String() string { return proto.CompactTextString(m) }
+func (*BlockTxs) ProtoMessage()    {}
+func (*BlockTxs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{4}
+}
+
+func (m *BlockTxs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockTxs.Unmarshal(m, b)
+}
+func (m *BlockTxs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockTxs.Marshal(b, m, deterministic)
+}
+func (m *BlockTxs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockTxs.Merge(m, src)
+}
+func (m *BlockTxs) XXX_Size() int {
+	return xxx_messageInfo_BlockTxs.Size(m)
+}
+func (m *BlockTxs) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockTxs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockTxs proto.InternalMessageInfo
+
+func (m *BlockTxs) GetBlockid() []byte {
+	if m != nil {
+		return m.Blockid
+	}
+	return nil
+}
+
+func (m *BlockTxs) GetTxs() []*xldgpb.Transaction {
+	if m != nil {
+		return m.Txs
+	}
+	return nil
+}
+
+// 状态快照，manifest为snapshot.Manifest的json编码
+type SnapshotManifestRequest struct {
+	// 0表示最新的可用快照
+	Height               int64    `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotManifestRequest) Reset()         { *m = SnapshotManifestRequest{} }
+func (m *SnapshotManifestRequest) String() string { return proto.CompactTextString(m) }
+func (*SnapshotManifestRequest) ProtoMessage()    {}
+func (*SnapshotManifestRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{5}
+}
+
+func (m *SnapshotManifestRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotManifestRequest.Unmarshal(m, b)
+}
+func (m *SnapshotManifestRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotManifestRequest.Marshal(b, m, deterministic)
+}
+func (m *SnapshotManifestRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotManifestRequest.Merge(m, src)
+}
+func (m *SnapshotManifestRequest) XXX_Size() int {
+	return xxx_messageInfo_SnapshotManifestRequest.Size(m)
+}
+func (m *SnapshotManifestRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotManifestRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotManifestRequest proto.InternalMessageInfo
+
+func (m *SnapshotManifestRequest) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+type SnapshotManifest struct {
+	Manifest             []byte   `protobuf:"bytes,1,opt,name=manifest,proto3" json:"manifest,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotManifest) Reset()         { *m = SnapshotManifest{} }
+func (m *SnapshotManifest) String() string { return proto.CompactTextString(m) }
+func (*SnapshotManifest) ProtoMessage()    {}
+func (*SnapshotManifest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{6}
+}
+
+func (m *SnapshotManifest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotManifest.Unmarshal(m, b)
+}
+func (m *SnapshotManifest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotManifest.Marshal(b, m, deterministic)
+}
+func (m *SnapshotManifest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotManifest.Merge(m, src)
+}
+func (m *SnapshotManifest) XXX_Size() int {
+	return xxx_messageInfo_SnapshotManifest.Size(m)
+}
+func (m *SnapshotManifest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotManifest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotManifest proto.InternalMessageInfo
+
+func (m *SnapshotManifest) GetManifest() []byte {
+	if m != nil {
+		return m.Manifest
+	}
+	return nil
+}
+
+type SnapshotChunkRequest struct {
+	Height int64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	// 快照分片序号，从0开始
+	Index                int32    `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotChunkRequest) Reset()         { *m = SnapshotChunkRequest{} }
+func (m *SnapshotChunkRequest) String() string { return proto.CompactTextString(m) }
+func (*SnapshotChunkRequest) ProtoMessage()    {}
+func (*SnapshotChunkRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{7}
+}
+
+func (m *SnapshotChunkRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotChunkRequest.Unmarshal(m, b)
+}
+func (m *SnapshotChunkRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotChunkRequest.Marshal(b, m, deterministic)
+}
+func (m *SnapshotChunkRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotChunkRequest.Merge(m, src)
+}
+func (m *SnapshotChunkRequest) XXX_Size() int {
+	return xxx_messageInfo_SnapshotChunkRequest.Size(m)
+}
+func (m *SnapshotChunkRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotChunkRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotChunkRequest proto.InternalMessageInfo
+
+func (m *SnapshotChunkRequest) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *SnapshotChunkRequest) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+type SnapshotChunk struct {
+	Height               int64    `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Index                int32    `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	Data                 []byte   `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotChunk) Reset()         { *m = SnapshotChunk{} }
+func (m *SnapshotChunk) String() string { return proto.CompactTextString(m) }
+func (*SnapshotChunk) ProtoMessage()    {}
+func (*SnapshotChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{8}
+}
+
+func (m *SnapshotChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotChunk.Unmarshal(m, b)
+}
+func (m *SnapshotChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotChunk.Marshal(b, m, deterministic)
+}
+func (m *SnapshotChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotChunk.Merge(m, src)
+}
+func (m *SnapshotChunk) XXX_Size() int {
+	return xxx_messageInfo_SnapshotChunk.Size(m)
+}
+func (m *SnapshotChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotChunk proto.InternalMessageInfo
+
+func (m *SnapshotChunk) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *SnapshotChunk) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *SnapshotChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
 type TxInfo struct {
 	// 当前状态
 	Status xldgpb.TransactionStatus `protobuf:"varint,1,opt,name=status,proto3,enum=xldgpb.TransactionStatus" json:"status,omitempty"`
 	// 离主干末端的距离（如果在主干上)
-	Distance             int64               `protobuf:"varint,2,opt,name=distance,proto3" json:"distance,omitempty"`
-	Tx                   *xldgpb.Transaction `protobuf:"bytes,3,opt,name=tx,proto3" json:"tx,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
-	XXX_unrecognized     []byte              `json:"-"`
-	XXX_sizecache        int32               `json:"-"`
+	Distance int64               `protobuf:"varint,2,opt,name=distance,proto3" json:"distance,omitempty"`
+	Tx       *xldgpb.Transaction `protobuf:"bytes,3,opt,name=tx,proto3" json:"tx,omitempty"`
+	// 交易内容是否被监管脱敏
+	Redacted             bool     `protobuf:"varint,4,opt,name=redacted,proto3" json:"redacted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *TxInfo) Reset()         { *m = TxInfo{} }
 func (m *TxInfo) String() string { return proto.CompactTextString(m) }
 func (*TxInfo) ProtoMessage()    {}
 func (*TxInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e9685bde11a1952e, []int{1}
+	return fileDescriptor_e9685bde11a1952e, []int{9}
 }
 
 func (m *TxInfo) XXX_Unmarshal(b []byte) error {
@@ -117,6 +494,13 @@ func (m *TxInfo) GetTx() *xldgpb.Transaction {
 	return nil
 }
 
+func (m *TxInfo) GetRedacted() bool {
+	if m != nil {
+		return m.Redacted
+	}
+	return false
+}
+
 type BlockInfo struct {
 	Status               xldgpb.BlockStatus    `protobuf:"varint,1,opt,name=status,proto3,enum=xldgpb.BlockStatus" json:"status,omitempty"`
 	Block                *xldgpb.InternalBlock `protobuf:"bytes,2,opt,name=block,proto3" json:"block,omitempty"`
@@ -129,7 +513,7 @@ func (m *BlockInfo) Reset()         { *m = BlockInfo{} }
 func (m *BlockInfo) String() string { return proto.CompactTextString(m) }
 func (*BlockInfo) ProtoMessage()    {}
 func (*BlockInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e9685bde11a1952e, []int{2}
+	return fileDescriptor_e9685bde11a1952e, []int{10}
 }
 
 func (m *BlockInfo) XXX_Unmarshal(b []byte) error {
@@ -178,7 +562,7 @@ func (m *ChainStatus) Reset()         { *m = ChainStatus{} }
 func (m *ChainStatus) String() string { return proto.CompactTextString(m) }
 func (*ChainStatus) ProtoMessage()    {}
 func (*ChainStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e9685bde11a1952e, []int{3}
+	return fileDescriptor_e9685bde11a1952e, []int{11}
 }
 
 func (m *ChainStatus) XXX_Unmarshal(b []byte) error {
@@ -239,7 +623,7 @@ func (m *SystemStatus) Reset()         { *m = SystemStatus{} }
 func (m *SystemStatus) String() string { return proto.CompactTextString(m) }
 func (*SystemStatus) ProtoMessage()    {}
 func (*SystemStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e9685bde11a1952e, []int{4}
+	return fileDescriptor_e9685bde11a1952e, []int{12}
 }
 
 func (m *SystemStatus) XXX_Unmarshal(b []byte) error {
@@ -285,7 +669,7 @@ func (m *TipStatus) Reset()         { *m = TipStatus{} }
 func (m *TipStatus) String() string { return proto.CompactTextString(m) }
 func (*TipStatus) ProtoMessage()    {}
 func (*TipStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e9685bde11a1952e, []int{5}
+	return fileDescriptor_e9685bde11a1952e, []int{13}
 }
 
 func (m *TipStatus) XXX_Unmarshal(b []byte) error {
@@ -327,7 +711,7 @@ func (m *BlockID) Reset()         { *m = BlockID{} }
 func (m *BlockID) String() string { return proto.CompactTextString(m) }
 func (*BlockID) ProtoMessage()    {}
 func (*BlockID) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e9685bde11a1952e, []int{6}
+	return fileDescriptor_e9685bde11a1952e, []int{14}
 }
 
 func (m *BlockID) XXX_Unmarshal(b []byte) error {
@@ -383,7 +767,7 @@ func (m *ConsensusStatus) Reset()         { *m = ConsensusStatus{} }
 func (m *ConsensusStatus) String() string { return proto.CompactTextString(m) }
 func (*ConsensusStatus) ProtoMessage()    {}
 func (*ConsensusStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e9685bde11a1952e, []int{7}
+	return fileDescriptor_e9685bde11a1952e, []int{15}
 }
 
 func (m *ConsensusStatus) XXX_Unmarshal(b []byte) error {
@@ -432,8 +816,190 @@ func (m *ConsensusStatus) GetValidatorsInfo() string {
 	return ""
 }
 
+// 状态数据在某个区块状态根下的证明
+type StateProof struct {
+	Blockid   []byte `protobuf:"bytes,1,opt,name=blockid,proto3" json:"blockid,omitempty"`
+	StateRoot []byte `protobuf:"bytes,2,opt,name=state_root,json=stateRoot,proto3" json:"state_root,omitempty"`
+	Key       []byte `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	Value     []byte `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	Exist     bool   `protobuf:"varint,5,opt,name=exist,proto3" json:"exist,omitempty"`
+	// 自根节点向下路径上的兄弟节点哈希
+	Siblings [][]byte `protobuf:"bytes,6,rep,name=siblings,proto3" json:"siblings,omitempty"`
+	// 不存在性证明中路径终点的叶子
+	LeafPath             []byte   `protobuf:"bytes,7,opt,name=leaf_path,json=leafPath,proto3" json:"leaf_path,omitempty"`
+	LeafValueHash        []byte   `protobuf:"bytes,8,opt,name=leaf_value_hash,json=leafValueHash,proto3" json:"leaf_value_hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StateProof) Reset()         { *m = StateProof{} }
+func (m *StateProof) String() string { return proto.CompactTextString(m) }
+func (*StateProof) ProtoMessage()    {}
+func (*StateProof) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{16}
+}
+
+func (m *StateProof) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateProof.Unmarshal(m, b)
+}
+func (m *StateProof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateProof.Marshal(b, m, deterministic)
+}
+func (m *StateProof) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateProof.Merge(m, src)
+}
+func (m *StateProof) XXX_Size() int {
+	return xxx_messageInfo_StateProof.Size(m)
+}
+func (m *StateProof) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateProof.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateProof proto.InternalMessageInfo
+
+func (m *StateProof) GetBlockid() []byte {
+	if m != nil {
+		return m.Blockid
+	}
+	return nil
+}
+
+func (m *StateProof) GetStateRoot() []byte {
+	if m != nil {
+		return m.StateRoot
+	}
+	return nil
+}
+
+func (m *StateProof) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *StateProof) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *StateProof) GetExist() bool {
+	if m != nil {
+		return m.Exist
+	}
+	return false
+}
+
+func (m *StateProof) GetSiblings() [][]byte {
+	if m != nil {
+		return m.Siblings
+	}
+	return nil
+}
+
+func (m *StateProof) GetLeafPath() []byte {
+	if m != nil {
+		return m.LeafPath
+	}
+	return nil
+}
+
+func (m *StateProof) GetLeafValueHash() []byte {
+	if m != nil {
+		return m.LeafValueHash
+	}
+	return nil
+}
+
+// 下一个区块的手续费估算
+type FeeEstimate struct {
+	// 是否开启费用市场
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// 下一个区块的高度
+	Height int64 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	// 下一个区块的基础费用(每单位gas)
+	BaseFee string `protobuf:"bytes,3,opt,name=base_fee,json=baseFee,proto3" json:"base_fee,omitempty"`
+	GasUsed int64  `protobuf:"varint,4,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
+	// 支付基础费用所需的最少手续费，不含小费
+	MinFee               string   `protobuf:"bytes,5,opt,name=min_fee,json=minFee,proto3" json:"min_fee,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FeeEstimate) Reset()         { *m = FeeEstimate{} }
+func (m *FeeEstimate) String() string { return proto.CompactTextString(m) }
+func (*FeeEstimate) ProtoMessage()    {}
+func (*FeeEstimate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e9685bde11a1952e, []int{17}
+}
+
+func (m *FeeEstimate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FeeEstimate.Unmarshal(m, b)
+}
+func (m *FeeEstimate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FeeEstimate.Marshal(b, m, deterministic)
+}
+func (m *FeeEstimate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FeeEstimate.Merge(m, src)
+}
+func (m *FeeEstimate) XXX_Size() int {
+	return xxx_messageInfo_FeeEstimate.Size(m)
+}
+func (m *FeeEstimate) XXX_DiscardUnknown() {
+	xxx_messageInfo_FeeEstimate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FeeEstimate proto.InternalMessageInfo
+
+func (m *FeeEstimate) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *FeeEstimate) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *FeeEstimate) GetBaseFee() string {
+	if m != nil {
+		return m.BaseFee
+	}
+	return ""
+}
+
+func (m *FeeEstimate) GetGasUsed() int64 {
+	if m != nil {
+		return m.GasUsed
+	}
+	return 0
+}
+
+func (m *FeeEstimate) GetMinFee() string {
+	if m != nil {
+		return m.MinFee
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Transactions)(nil), "protos.Transactions")
+	proto.RegisterType((*TxIDs)(nil), "protos.TxIDs")
+	proto.RegisterType((*CompactBlock)(nil), "protos.CompactBlock")
+	proto.RegisterType((*BlockTxsRequest)(nil), "protos.BlockTxsRequest")
+	proto.RegisterType((*BlockTxs)(nil), "protos.BlockTxs")
+	proto.RegisterType((*SnapshotManifestRequest)(nil), "protos.SnapshotManifestRequest")
+	proto.RegisterType((*SnapshotManifest)(nil), "protos.SnapshotManifest")
+	proto.RegisterType((*SnapshotChunkRequest)(nil), "protos.SnapshotChunkRequest")
+	proto.RegisterType((*SnapshotChunk)(nil), "protos.SnapshotChunk")
 	proto.RegisterType((*TxInfo)(nil), "protos.TxInfo")
 	proto.RegisterType((*BlockInfo)(nil), "protos.BlockInfo")
 	proto.RegisterType((*ChainStatus)(nil), "protos.ChainStatus")
@@ -441,6 +1007,8 @@ func init() {
 	proto.RegisterType((*TipStatus)(nil), "protos.TipStatus")
 	proto.RegisterType((*BlockID)(nil), "protos.BlockID")
 	proto.RegisterType((*ConsensusStatus)(nil), "protos.ConsensusStatus")
+	proto.RegisterType((*StateProof)(nil), "protos.StateProof")
+	proto.RegisterType((*FeeEstimate)(nil), "protos.FeeEstimate")
 }
 
 func init() {
@@ -448,41 +1016,66 @@ func init() {
 }
 
 var fileDescriptor_e9685bde11a1952e = []byte{
-	// 571 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x53, 0xd1, 0x6e, 0xd3, 0x30,
-	0x14, 0x55, 0xdb, 0xd1, 0x35, 0x37, 0x61, 0x43, 0x46, 0xa0, 0x30, 0x84, 0xd4, 0x05, 0x26, 0x2a,
-	0x4d, 0x6b, 0xb4, 0x4e, 0xf0, 0x80, 0x78, 0xda, 0x78, 0x60, 0x12, 0xf0, 0xe0, 0x75, 0x2f, 0x3c,
-	0x10, 0x39, 0x8e, 0xd7, 0x5a, 0x4b, 0xed, 0xc8, 0x76, 0xa6, 0x20, 0xf1, 0x2d, 0xfc, 0x09, 0xff,
-	0x86, 0x6c, 0x27, 0x6d, 0x35, 0x31, 0xf1, 0x50, 0x25, 0xf7, 0xf8, 0x9c, 0x9e, 0x73, 0x6f, 0x7c,
-	0xe1, 0xcd, 0x2d, 0x53, 0x82, 0x95, 0x29, 0x13, 0x0b, 0x2e, 0x98, 0x4e, 0x9b, 0xba, 0x62, 0x4a,
-	0xea, 0xb4, 0xa9, 0x72, 0xfb, 0x9b, 0x56, 0x4a, 0x1a, 0x89, 0x86, 0xee, 0xa1, 0x0f, 0x4e, 0xdd,
-	0x31, 0x95, 0x8a, 0xa5, 0x39, 0xd5, 0x69, 0xc9, 0x8a, 0x05, 0x53, 0x69, 0xb3, 0x7e, 0x16, 0x0b,
-	0x2b, 0xf3, 0xa5, 0x97, 0x26, 0xef, 0x20, 0x9a, 0x2b, 0x22, 0x34, 0xa1, 0x86, 0x4b, 0xa1, 0xd1,
-	0x11, 0x0c, 0x4c, 0xa3, 0xe3, 0xde, 0x78, 0x30, 0x09, 0x67, 0x4f, 0xa7, 0x5e, 0x33, 0xdd, 0xa2,
-	0x60, 0x7b, 0x9e, 0xfc, 0x82, 0xe1, 0xbc, 0xb9, 0x14, 0x37, 0x12, 0x9d, 0xc2, 0x50, 0x1b, 0x62,
-	0x6a, 0xab, 0xe9, 0x4d, 0xf6, 0x66, 0x2f, 0xfe, 0xa1, 0xb9, 0x72, 0x04, 0xdc, 0x12, 0xd1, 0x01,
-	0x8c, 0x0a, 0xae, 0x0d, 0x11, 0x94, 0xc5, 0xfd, 0x71, 0x6f, 0x32, 0xc0, 0xeb, 0x1a, 0xbd, 0x86,
-	0xbe, 0x69, 0xe2, 0xc1, 0xb8, 0xf7, 0x90, 0x7d, 0xdf, 0x34, 0x09, 0x83, 0xe0, 0xbc, 0x94, 0xf4,
-	0xd6, 0x05, 0x38, 0xbe, 0x17, 0x60, 0xad, 0x72, 0x94, 0x7b, 0xd6, 0xc7, 0xf0, 0x28, 0xb7, 0xb0,
-	0xf3, 0x0d, 0x67, 0xcf, 0x3a, 0xee, 0xa5, 0x30, 0x4c, 0x09, 0x52, 0x3a, 0x0d, 0xf6, 0x9c, 0xe4,
-	0x4f, 0x0f, 0xc2, 0x8b, 0x25, 0xe1, 0x6d, 0x7e, 0x74, 0x06, 0xa1, 0x9f, 0x5d, 0xb6, 0x62, 0x86,
-	0x38, 0xbb, 0x70, 0x86, 0xba, 0xbf, 0xf8, 0xe2, 0x8e, 0xbe, 0x32, 0x43, 0x30, 0x94, 0xeb, 0x77,
-	0x74, 0x02, 0x41, 0x6d, 0x1a, 0xe9, 0x25, 0xde, 0xf5, 0x49, 0x27, 0xb9, 0x36, 0x8d, 0x74, 0x82,
-	0x51, 0xdd, 0xbe, 0x6d, 0x02, 0x0e, 0xfe, 0x1f, 0x10, 0xbd, 0x02, 0xc8, 0x15, 0x11, 0x74, 0x99,
-	0xf1, 0x42, 0xc7, 0x3b, 0xe3, 0xc1, 0x24, 0xc0, 0x81, 0x47, 0x2e, 0x0b, 0x9d, 0x50, 0x88, 0xae,
-	0x7e, 0x6a, 0xc3, 0x56, 0x6d, 0xfe, 0xf7, 0x10, 0x51, 0xdb, 0x4e, 0xb6, 0x35, 0x2f, 0x3b, 0x65,
-	0x7f, 0x7b, 0xa6, 0x5b, 0xad, 0xe2, 0x90, 0x6e, 0xf5, 0xfd, 0x12, 0x82, 0x8a, 0x31, 0x95, 0xd5,
-	0xaa, 0xd4, 0x71, 0xdf, 0xb9, 0x8c, 0x2c, 0x70, 0xad, 0x4a, 0x9d, 0x9c, 0x40, 0x30, 0xe7, 0x55,
-	0xcb, 0x1c, 0x43, 0xc4, 0x75, 0x66, 0x54, 0x2d, 0x6e, 0x33, 0xc3, 0x2b, 0xe7, 0x30, 0xc2, 0xc0,
-	0xf5, 0xdc, 0x42, 0x73, 0x5e, 0x25, 0x3f, 0x60, 0xd7, 0x7f, 0xba, 0x4f, 0xe8, 0x39, 0x0c, 0x73,
-	0x2a, 0xc8, 0x8a, 0x39, 0x5a, 0x80, 0xdb, 0x0a, 0xc5, 0xb0, 0xeb, 0xda, 0xe3, 0x85, 0x9b, 0x57,
-	0x84, 0xbb, 0x12, 0x1d, 0x42, 0x24, 0x18, 0x2b, 0x32, 0x2a, 0x85, 0x61, 0xc2, 0xb8, 0x19, 0x8d,
-	0x70, 0x68, 0xb1, 0x0b, 0x0f, 0x25, 0xbf, 0x7b, 0xb0, 0x7f, 0x21, 0x85, 0x66, 0x42, 0xd7, 0xba,
-	0x4d, 0x15, 0xc3, 0xee, 0x1d, 0x53, 0x9a, 0x4b, 0xd1, 0x3a, 0x75, 0x25, 0x3a, 0x82, 0x3d, 0xda,
-	0x91, 0x33, 0x17, 0xa5, 0xef, 0x08, 0x8f, 0xd7, 0xe8, 0x37, 0x9b, 0xe8, 0x10, 0x22, 0x6d, 0x88,
-	0x32, 0xd9, 0x92, 0xf1, 0xc5, 0xd2, 0xfb, 0x06, 0x38, 0x74, 0xd8, 0x67, 0x07, 0xa1, 0xb7, 0xb0,
-	0x7f, 0x47, 0x4a, 0x5e, 0x10, 0x23, 0x95, 0xce, 0xb8, 0xb8, 0x91, 0xf1, 0x8e, 0x63, 0xed, 0x6d,
-	0x60, 0x7b, 0x5d, 0xcf, 0x3f, 0x7e, 0xff, 0xb0, 0xe0, 0x66, 0x59, 0xe7, 0x53, 0x2a, 0x57, 0x7e,
-	0x9f, 0xdd, 0xac, 0xd3, 0xcd, 0xee, 0x3e, 0xbc, 0xf3, 0xb9, 0xdf, 0xf4, 0xb3, 0xbf, 0x01, 0x00,
-	0x00, 0xff, 0xff, 0x87, 0x7d, 0xc4, 0xc4, 0x18, 0x04, 0x00, 0x00,
+	// 976 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0x5f, 0x6f, 0x23, 0x35,
+	0x10, 0x57, 0x92, 0x4b, 0x93, 0x4c, 0xb6, 0x7f, 0x64, 0x0e, 0x6e, 0xef, 0x50, 0x45, 0x6e, 0xe1,
+	0xa0, 0xd2, 0xa9, 0x89, 0xda, 0x13, 0x08, 0x21, 0x9e, 0xae, 0xbd, 0xea, 0x2a, 0x38, 0x74, 0xb8,
+	0x29, 0x0f, 0x3c, 0xb0, 0xf2, 0xee, 0x4e, 0xb2, 0x56, 0x37, 0xf6, 0x62, 0x7b, 0xab, 0xbd, 0x2f,
+	0xc1, 0x33, 0x4f, 0x7c, 0x13, 0xbe, 0x0d, 0x1f, 0x04, 0xd9, 0xde, 0x4d, 0xff, 0x88, 0xb6, 0xe2,
+	0x21, 0x8a, 0x7f, 0x3f, 0xcf, 0x8c, 0x7f, 0x1e, 0xcf, 0xcc, 0xc2, 0x17, 0x17, 0xa8, 0x04, 0x16,
+	0x33, 0x14, 0x4b, 0x2e, 0x50, 0xcf, 0xea, 0xaa, 0x44, 0x25, 0xf5, 0xac, 0x2e, 0x13, 0xfb, 0x9b,
+	0x96, 0x4a, 0x1a, 0x49, 0x36, 0xdc, 0x9f, 0x7e, 0x76, 0xe0, 0xb6, 0x53, 0xa9, 0x70, 0x96, 0xa4,
+	0x7a, 0x56, 0x60, 0xb6, 0x44, 0x35, 0xab, 0xd7, 0xff, 0xd9, 0xb2, 0x4c, 0x5a, 0xe8, 0x5d, 0xa3,
+	0xaf, 0x21, 0x98, 0x2b, 0x26, 0x34, 0x4b, 0x0d, 0x97, 0x42, 0x93, 0x17, 0xd0, 0x33, 0xb5, 0x0e,
+	0x3b, 0x93, 0xde, 0xde, 0xf8, 0xf0, 0xa3, 0xa9, 0xf7, 0x99, 0x5e, 0x33, 0xa1, 0x76, 0x3f, 0xda,
+	0x85, 0xfe, 0xbc, 0x3e, 0x3d, 0xd6, 0xe4, 0x31, 0xf4, 0x4d, 0xcd, 0x33, 0xef, 0x11, 0x50, 0x0f,
+	0xa2, 0x3f, 0x3b, 0x10, 0x1c, 0xc9, 0x55, 0xc9, 0x52, 0xf3, 0xba, 0x90, 0xe9, 0x05, 0xd9, 0x87,
+	0x8d, 0x1c, 0x59, 0x86, 0x2a, 0xec, 0x4c, 0x3a, 0x7b, 0xe3, 0xc3, 0x8f, 0xdb, 0xc8, 0xa7, 0xc2,
+	0xa0, 0x12, 0xac, 0x70, 0x66, 0xb4, 0x31, 0x22, 0x9f, 0xc1, 0x58, 0xe7, 0x52, 0x99, 0xd8, 0xc7,
+	0xee, 0xba, 0xd8, 0xe0, 0xa8, 0xb9, 0x65, 0xc8, 0xb7, 0xb0, 0x59, 0x2a, 0x5c, 0xf0, 0xa2, 0xc0,
+	0x2c, 0xb6, 0x82, 0x7b, 0x77, 0x0b, 0x0e, 0xd6, 0x96, 0xf3, 0x5a, 0x47, 0x6f, 0x60, 0xdb, 0x9d,
+	0x35, 0xaf, 0x35, 0xc5, 0xdf, 0x2b, 0xd4, 0x86, 0x84, 0x30, 0x48, 0x2c, 0xc5, 0x33, 0xa7, 0x2e,
+	0xa0, 0x2d, 0xb4, 0x3b, 0x5c, 0x64, 0x58, 0xa3, 0xd7, 0xb0, 0x49, 0x5b, 0x18, 0xfd, 0x00, 0xc3,
+	0x36, 0xcc, 0x3d, 0xfe, 0x4d, 0x36, 0xbb, 0x0f, 0x64, 0xf3, 0x00, 0x9e, 0x9c, 0x09, 0x56, 0xea,
+	0x5c, 0x9a, 0x77, 0x4c, 0xf0, 0x05, 0x6a, 0xd3, 0x6a, 0xfb, 0xc4, 0x26, 0x8e, 0x2f, 0x73, 0xe3,
+	0x42, 0xf7, 0x68, 0x83, 0xa2, 0x29, 0xec, 0xdc, 0x76, 0x21, 0xcf, 0x60, 0xb8, 0x6a, 0xd6, 0x8d,
+	0x90, 0x35, 0x8e, 0x8e, 0xe1, 0x71, 0x6b, 0x7f, 0x94, 0x57, 0xe2, 0xe2, 0x81, 0xf8, 0xf6, 0x5d,
+	0xdd, 0x55, 0xc3, 0xee, 0xa4, 0xb3, 0xd7, 0xa7, 0x1e, 0x44, 0x3f, 0xc3, 0xe6, 0x8d, 0x28, 0xff,
+	0xcf, 0x9d, 0x10, 0x78, 0x94, 0x31, 0xc3, 0xc2, 0x9e, 0x13, 0xe7, 0xd6, 0xb6, 0x54, 0x36, 0xe6,
+	0xf5, 0xa9, 0x58, 0x48, 0x72, 0x00, 0x1b, 0xda, 0x30, 0x53, 0x69, 0x17, 0x6c, 0xeb, 0xf0, 0xe9,
+	0x7f, 0x24, 0xec, 0xcc, 0x19, 0xd0, 0xc6, 0xd0, 0x5e, 0x39, 0xe3, 0xda, 0x30, 0x91, 0xa2, 0x3b,
+	0xaa, 0x47, 0xd7, 0x98, 0x7c, 0x0e, 0x5d, 0x53, 0xbb, 0xb3, 0xee, 0xc8, 0x7d, 0xd7, 0xd4, 0x36,
+	0x80, 0xc2, 0x8c, 0xa5, 0x06, 0xb3, 0xf0, 0xd1, 0xa4, 0xb3, 0x37, 0xa4, 0x6b, 0x1c, 0x21, 0x8c,
+	0xdc, 0x1b, 0x3b, 0x71, 0x2f, 0x6f, 0x89, 0x5b, 0x47, 0x74, 0x26, 0xb7, 0x64, 0xbd, 0x84, 0xbe,
+	0x2b, 0x81, 0xb0, 0x7b, 0x5f, 0xb5, 0x7b, 0x9b, 0xe8, 0xef, 0x0e, 0x8c, 0x8f, 0x72, 0xc6, 0x9b,
+	0xbb, 0x91, 0x57, 0x30, 0xf6, 0x2d, 0x1a, 0xaf, 0xd0, 0xb0, 0xa6, 0x61, 0x48, 0x1b, 0xe2, 0x47,
+	0xb7, 0xf5, 0x0e, 0x0d, 0xa3, 0x50, 0xac, 0xd7, 0x64, 0x1f, 0x46, 0x95, 0xa9, 0xa5, 0x77, 0xf1,
+	0xa7, 0xee, 0xb4, 0x2e, 0xe7, 0xa6, 0x96, 0xce, 0x61, 0x58, 0x35, 0xab, 0x2b, 0x81, 0xbd, 0x87,
+	0x05, 0x92, 0x5d, 0x80, 0x44, 0x31, 0x91, 0xe6, 0xb1, 0x6d, 0xc6, 0x47, 0x93, 0xde, 0xde, 0x88,
+	0x8e, 0x3c, 0x73, 0x9a, 0xe9, 0x28, 0x85, 0xe0, 0xec, 0x83, 0x36, 0xb8, 0x6a, 0xf4, 0x7f, 0x03,
+	0x41, 0x6a, 0xaf, 0x13, 0x5f, 0xcb, 0x97, 0x7d, 0x01, 0x3f, 0xa4, 0xa6, 0xd7, 0xae, 0x4a, 0xc7,
+	0xe9, 0x15, 0x20, 0x9f, 0xc2, 0xa8, 0x44, 0x54, 0x71, 0xa5, 0x0a, 0xdf, 0x32, 0x23, 0x3a, 0xb4,
+	0xc4, 0xb9, 0x2a, 0x74, 0xb4, 0x0f, 0xa3, 0x39, 0x2f, 0x1b, 0xcb, 0x09, 0x04, 0x5c, 0xc7, 0x46,
+	0x55, 0xe2, 0x22, 0x36, 0xbc, 0x74, 0x27, 0x0c, 0x29, 0x70, 0x3d, 0xb7, 0xd4, 0x9c, 0x97, 0xd1,
+	0x6f, 0x30, 0xf0, 0x4f, 0x77, 0x6c, 0x4b, 0x34, 0x49, 0x05, 0x5b, 0xa1, 0x33, 0x1b, 0xd1, 0x06,
+	0x5d, 0xef, 0xda, 0xee, 0xcd, 0xae, 0x7d, 0x0e, 0x81, 0x40, 0xcc, 0xe2, 0x54, 0x0a, 0x83, 0xc2,
+	0xb8, 0x1c, 0x0d, 0xe9, 0xd8, 0x72, 0x47, 0x9e, 0x8a, 0xfe, 0xea, 0xc0, 0xf6, 0x91, 0x14, 0x1a,
+	0x85, 0xae, 0x74, 0xa3, 0x2a, 0x84, 0xc1, 0x25, 0x2a, 0xcd, 0xa5, 0x68, 0x4e, 0x6a, 0x21, 0x79,
+	0x01, 0x5b, 0x69, 0x6b, 0x1c, 0x3b, 0x29, 0x5d, 0x67, 0xb0, 0xb9, 0x66, 0x7f, 0xb2, 0x8a, 0x9e,
+	0x43, 0xa0, 0x0d, 0x53, 0x26, 0x6e, 0x5a, 0xaa, 0xe7, 0x8c, 0xc6, 0x8e, 0x7b, 0xeb, 0x28, 0xf2,
+	0x15, 0x6c, 0x5f, 0xb2, 0x82, 0x67, 0xcc, 0x48, 0xa5, 0x63, 0x2e, 0x16, 0xd2, 0x55, 0xed, 0x88,
+	0x6e, 0x5d, 0xd1, 0xb6, 0x5c, 0xa3, 0x7f, 0x3a, 0x00, 0x56, 0x17, 0xbe, 0x57, 0x52, 0x2e, 0xee,
+	0x19, 0x51, 0xbb, 0x00, 0xf6, 0x9d, 0x30, 0x56, 0x52, 0x9a, 0x26, 0x13, 0x23, 0xc7, 0x50, 0x29,
+	0x0d, 0xd9, 0x81, 0xde, 0x05, 0x7e, 0x68, 0x3a, 0xd6, 0x2e, 0x6d, 0x6b, 0x5f, 0xb2, 0xa2, 0x42,
+	0x77, 0x70, 0x40, 0x3d, 0xb0, 0x2c, 0xd6, 0x5c, 0x9b, 0xb0, 0xef, 0x92, 0xe5, 0x81, 0xed, 0x2e,
+	0xcd, 0x93, 0x82, 0x8b, 0xa5, 0x0e, 0x37, 0xdc, 0x10, 0x5f, 0x63, 0xfb, 0xdc, 0x05, 0xb2, 0x45,
+	0x5c, 0x32, 0x93, 0x87, 0x03, 0x3f, 0xae, 0x2c, 0xf1, 0x9e, 0x99, 0x9c, 0x7c, 0x09, 0xdb, 0x6e,
+	0xd3, 0x05, 0x8f, 0x73, 0xa6, 0xf3, 0x70, 0xe8, 0x4c, 0x36, 0x2d, 0xfd, 0x8b, 0x65, 0xdf, 0x32,
+	0x9d, 0x47, 0x7f, 0x74, 0x60, 0x7c, 0x82, 0xf8, 0x46, 0x1b, 0xbe, 0x62, 0xc6, 0x3d, 0x2a, 0x0a,
+	0x96, 0x14, 0x98, 0x35, 0x45, 0xd1, 0xc2, 0x6b, 0x93, 0xaa, 0x7b, 0x63, 0x52, 0x3d, 0x85, 0x61,
+	0xc2, 0x34, 0xc6, 0x0b, 0xc4, 0x26, 0xe1, 0x03, 0x8b, 0x4f, 0x10, 0xed, 0xd6, 0x92, 0xe9, 0xb8,
+	0xd2, 0xcd, 0x6c, 0xe8, 0xd1, 0xc1, 0x92, 0xe9, 0x73, 0x8d, 0x19, 0x79, 0x02, 0x83, 0x15, 0x17,
+	0xce, 0xa9, 0xef, 0xab, 0x6a, 0xc5, 0xc5, 0x09, 0xe2, 0xeb, 0xef, 0x7f, 0xfd, 0x6e, 0xc9, 0x4d,
+	0x5e, 0x25, 0xd3, 0x54, 0xae, 0xfc, 0xe7, 0xda, 0xd5, 0xf8, 0xec, 0xea, 0xd3, 0x7c, 0xf7, 0x27,
+	0x3d, 0xf1, 0x1f, 0xf2, 0x57, 0xff, 0x0e, 0x00, 0x81, 0x8c, 0xe5, 0x89, 0xf7, 0x07, 0x00, 0x00,
 }
@@ -27,6 +27,9 @@ type XMReader interface {
 	Get(bucket string, key []byte) (*VersionedData, error)
 	//扫描一个bucket中所有的kv, 调用者可以设置key区间[startKey, endKey)
 	Select(bucket string, startKey []byte, endKey []byte) (XMIterator, error)
+	//与Select相同，endKey为空时扫描到bucket的最后一个元素，reverse为true时按照key从大到小的顺序扫描
+	//Select中空的endKey保持原有语义以保证重放结果一致，新的迭代功能使用SelectRange
+	SelectRange(bucket string, startKey []byte, endKey []byte, reverse bool) (XMIterator, error)
 }
 
 // XMIterator iterates over key/value pairs in key order
@@ -5,9 +5,15 @@ import (
 
 	xconf "github.com/xuperchain/xupercore/kernel/common/xconfig"
 	"github.com/xuperchain/xupercore/lib/logs"
+	_ "github.com/xuperchain/xupercore/lib/storage/kvdb/memory"
 	"github.com/xuperchain/xupercore/lib/utils"
 )
 
+const (
+	// 使用内存存储引擎的账本配置
+	MemoryLedgerConf = "ledger_memory.yaml"
+)
+
 func NewEnvConfForTest(paths ...string) (*xconf.EnvConf, error) {
 	path := "conf/env.yaml"
 	if len(paths) > 0 {
@@ -26,6 +32,17 @@ func NewEnvConfForTest(paths ...string) (*xconf.EnvConf, error) {
 	return econf, nil
 }
 
+// NewMemoryEnvConfForTest 账本和状态机使用内存存储引擎，单测不会在磁盘上留下数据
+func NewMemoryEnvConfForTest(paths ...string) (*xconf.EnvConf, error) {
+	econf, err := NewEnvConfForTest(paths...)
+	if err != nil {
+		return nil, err
+	}
+
+	econf.LedgerConf = MemoryLedgerConf
+	return econf, nil
+}
+
 func InitLogForTest() error {
 	_, err := NewEnvConfForTest()
 	if err != nil {
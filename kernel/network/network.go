@@ -72,6 +72,9 @@ type Network interface {
 
 	Context() *nctx.NetCtx
 	PeerInfo() pb.PeerInfo
+
+	ServeChain(bcname string, members []string)
+	LeaveChain(bcname string)
 }
 
 // 如果有领域内公共逻辑，可以在这层扩展，对上层暴露高级接口
@@ -163,6 +166,22 @@ func (t *NetworkImpl) PeerInfo() pb.PeerInfo {
 	return t.p2pServ.PeerInfo()
 }
 
+func (t *NetworkImpl) ServeChain(bcname string, members []string) {
+	if !t.isInit() || bcname == "" {
+		return
+	}
+
+	t.p2pServ.ServeChain(bcname, members)
+}
+
+func (t *NetworkImpl) LeaveChain(bcname string) {
+	if !t.isInit() || bcname == "" {
+		return
+	}
+
+	t.p2pServ.LeaveChain(bcname)
+}
+
 func (t *NetworkImpl) isInit() bool {
 	if t.ctx == nil || t.p2pServ == nil {
 		return false
@@ -62,6 +62,12 @@ func (t *MockP2PServ) PeerInfo() pb.PeerInfo {
 	return pb.PeerInfo{}
 }
 
+func (t *MockP2PServ) ServeChain(string, []string) {
+}
+
+func (t *MockP2PServ) LeaveChain(string) {
+}
+
 func TestNewNetwork(t *testing.T) {
 	mock.InitLogForTest()
 
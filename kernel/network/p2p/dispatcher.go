@@ -25,6 +25,7 @@ var (
 	ErrMessageHandled = errors.New("message handled")
 	ErrStreamNil      = errors.New("stream is nil")
 	ErrNotRegister    = errors.New("message not register")
+	ErrNotChainMember = errors.New("message sender not chain member")
 )
 
 // Dispatcher
@@ -47,11 +48,26 @@ type dispatcher struct {
 
 	// control goroutinue number
 	parallel chan struct{}
+
+	// chain level membership check
+	router   *ChainRouter
+	resolver AccountResolver
 }
 
 var _ Dispatcher = &dispatcher{}
 
-func NewDispatcher(ctx *nctx.NetCtx) Dispatcher {
+// DispatcherOption define single option function for dispatcher
+type DispatcherOption func(*dispatcher)
+
+// WithChainRouter 只分发链群组成员发来的消息，resolver用于解析消息发送方的账户
+func WithChainRouter(router *ChainRouter, resolver AccountResolver) DispatcherOption {
+	return func(d *dispatcher) {
+		d.router = router
+		d.resolver = resolver
+	}
+}
+
+func NewDispatcher(ctx *nctx.NetCtx, opts ...DispatcherOption) Dispatcher {
 	d := &dispatcher{
 		ctx:     ctx,
 		log:     ctx.XLog,
@@ -61,6 +77,10 @@ func NewDispatcher(ctx *nctx.NetCtx) Dispatcher {
 		parallel: make(chan struct{}, 1024),
 	}
 
+	for _, opt := range opts {
+		opt(d)
+	}
+
 	return d
 }
 
@@ -125,6 +145,11 @@ func (d *dispatcher) Dispatch(msg *pb.XuperMessage, stream Stream) error {
 		return ErrStreamNil
 	}
 
+	if !d.isChainMember(msg, stream) {
+		ctx.GetLog().SetInfoField("member", false)
+		return ErrNotChainMember
+	}
+
 	if _, ok := d.mc[msg.GetHeader().GetType()]; !ok {
 		return ErrNotRegister
 	}
@@ -160,6 +185,20 @@ func (d *dispatcher) Dispatch(msg *pb.XuperMessage, stream Stream) error {
 	return nil
 }
 
+// isChainMember 受限链的消息只接收群组成员发来的
+func (d *dispatcher) isChainMember(msg *pb.XuperMessage, stream Stream) bool {
+	bcname := msg.GetHeader().GetBcname()
+	if d.router == nil || !d.router.IsRestricted(bcname) {
+		return true
+	}
+
+	account := ""
+	if d.resolver != nil {
+		account = d.resolver(msg, stream)
+	}
+	return account != "" && d.router.IsMember(bcname, account)
+}
+
 func MessageKey(msg *pb.XuperMessage) string {
 	if msg == nil || msg.GetHeader() == nil {
 		return ""
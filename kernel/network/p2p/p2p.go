@@ -22,4 +22,9 @@ type Server interface {
 	Context() *nctx.NetCtx
 
 	PeerInfo() pb.PeerInfo
+
+	// ServeChain 公告本节点服务的链，members非空时该链消息只在群组成员间路由
+	ServeChain(bcname string, members []string)
+	// LeaveChain 取消服务的链
+	LeaveChain(bcname string)
 }
@@ -0,0 +1,125 @@
+package p2p
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/xuperchain/crypto/core/hash"
+
+	"github.com/xuperchain/xupercore/kernel/common/xaddress"
+	"github.com/xuperchain/xupercore/lib/crypto/client"
+	"github.com/xuperchain/xupercore/lib/crypto/client/base"
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+// PeerInfoMaxAge 签名时间与本地时间相差超过该值的PeerInfo被拒绝，防止重放过期的公告
+const PeerInfoMaxAge = 5 * time.Minute
+
+var (
+	ErrPeerInfoNotSigned   = errors.New("peer info not signed")
+	ErrPeerInfoKeyMismatch = errors.New("peer info public key mismatch account")
+	ErrPeerInfoSignInvalid = errors.New("peer info signature invalid")
+	ErrPeerInfoExpired     = errors.New("peer info expired")
+)
+
+// PeerSigner 使用节点账户私钥对PeerInfo签名，将账户绑定到节点标识
+type PeerSigner struct {
+	crypto  base.CryptoClient
+	address *xaddress.Address
+}
+
+// NewPeerSigner 从节点keys目录加载账户密钥
+func NewPeerSigner(keyDir string) (*PeerSigner, error) {
+	priKey, err := ioutil.ReadFile(filepath.Join(keyDir, "private.key"))
+	if err != nil {
+		return nil, err
+	}
+	crypto, err := client.CreateCryptoClientFromJSONPrivateKey(priKey)
+	if err != nil {
+		return nil, err
+	}
+	address, err := xaddress.LoadAddrInfo(keyDir, crypto)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PeerSigner{
+		crypto:  crypto,
+		address: address,
+	}, nil
+}
+
+// Address 签名使用的账户地址
+func (s *PeerSigner) Address() string {
+	return s.address.Address
+}
+
+// Sign 填充公钥和签名时间并对PeerInfo签名
+func (s *PeerSigner) Sign(info *pb.PeerInfo) error {
+	if info.Account != s.address.Address {
+		return ErrPeerInfoKeyMismatch
+	}
+
+	info.Timestamp = time.Now().Unix()
+	sign, err := s.crypto.SignECDSA(s.address.PrivateKey, peerInfoDigest(info))
+	if err != nil {
+		return err
+	}
+
+	info.PublicKey = []byte(s.address.PublicKeyStr)
+	info.Signature = sign
+	return nil
+}
+
+// VerifyPeerInfo 校验公钥与声明账户匹配，签名覆盖节点标识、地址、账户、服务的链与签名时间，
+// 且签名时间在PeerInfoMaxAge之内；节点标识是否与传输层的对端一致由调用方校验
+func VerifyPeerInfo(info *pb.PeerInfo) error {
+	if info == nil || info.Account == "" || len(info.PublicKey) == 0 || len(info.Signature) == 0 {
+		return ErrPeerInfoNotSigned
+	}
+
+	age := time.Since(time.Unix(info.Timestamp, 0))
+	if age > PeerInfoMaxAge || age < -PeerInfoMaxAge {
+		return ErrPeerInfoExpired
+	}
+
+	crypto, err := client.CreateCryptoClientFromJSONPublicKey(info.PublicKey)
+	if err != nil {
+		return err
+	}
+	publicKey, err := crypto.GetEcdsaPublicKeyFromJsonStr(string(info.PublicKey))
+	if err != nil {
+		return err
+	}
+
+	if ok, _ := crypto.VerifyAddressUsingPublicKey(info.Account, publicKey); !ok {
+		return ErrPeerInfoKeyMismatch
+	}
+
+	ok, err := crypto.VerifyECDSA(publicKey, info.Signature, peerInfoDigest(info))
+	if err != nil || !ok {
+		return ErrPeerInfoSignInvalid
+	}
+
+	return nil
+}
+
+// peerInfoDigest 签名内容不包含附带的远程节点列表
+func peerInfoDigest(info *pb.PeerInfo) []byte {
+	var buf bytes.Buffer
+	for _, field := range []string{info.Id, info.Address, info.Account} {
+		buf.WriteString(field)
+		buf.WriteByte(0)
+	}
+	for _, chain := range info.Chains {
+		buf.WriteString(chain)
+		buf.WriteByte(0)
+	}
+	binary.Write(&buf, binary.BigEndian, info.Timestamp)
+
+	return hash.DoubleSha256(buf.Bytes())
+}
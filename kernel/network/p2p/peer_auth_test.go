@@ -0,0 +1,71 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+func TestPeerInfoSign(t *testing.T) {
+	signer, err := NewPeerSigner("../../mock/p2pv2/node1/data/keys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewPeerSigner("../../mock/p2pv2/node2/data/keys")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := &pb.PeerInfo{
+		Id:      "peer1",
+		Address: "/ip4/127.0.0.1/tcp/47101",
+		Account: signer.address.Address,
+		Chains:  []string{"xuper"},
+	}
+	if err := VerifyPeerInfo(info); err != ErrPeerInfoNotSigned {
+		t.Fatalf("unsigned peer info should be rejected: %v", err)
+	}
+	if err := signer.Sign(info); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyPeerInfo(info); err != nil {
+		t.Fatalf("verify peer info error: %v", err)
+	}
+
+	// 声明其他账户
+	spoofed := *info
+	spoofed.Account = other.address.Address
+	if err := VerifyPeerInfo(&spoofed); err != ErrPeerInfoKeyMismatch {
+		t.Errorf("spoofed account should be rejected: %v", err)
+	}
+
+	// 篡改节点标识
+	spoofed = *info
+	spoofed.Id = "peer2"
+	if err := VerifyPeerInfo(&spoofed); err != ErrPeerInfoSignInvalid {
+		t.Errorf("tampered peer id should be rejected: %v", err)
+	}
+
+	// 篡改签名时间
+	spoofed = *info
+	spoofed.Timestamp++
+	if err := VerifyPeerInfo(&spoofed); err != ErrPeerInfoSignInvalid {
+		t.Errorf("tampered timestamp should be rejected: %v", err)
+	}
+
+	// 过期的PeerInfo
+	stale := &pb.PeerInfo{Id: "peer1", Account: signer.address.Address}
+	if err := signer.Sign(stale); err != nil {
+		t.Fatal(err)
+	}
+	stale.Timestamp -= int64(2 * PeerInfoMaxAge / time.Second)
+	if err := VerifyPeerInfo(stale); err != ErrPeerInfoExpired {
+		t.Errorf("expired peer info should be rejected: %v", err)
+	}
+
+	// 不能为其他账户签名
+	if err := other.Sign(&pb.PeerInfo{Id: "peer1", Account: signer.address.Address}); err != ErrPeerInfoKeyMismatch {
+		t.Errorf("sign for other account should fail: %v", err)
+	}
+}
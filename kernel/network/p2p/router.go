@@ -0,0 +1,148 @@
+package p2p
+
+import (
+	"sort"
+	"sync"
+
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+// AccountResolver 解析消息发送方节点对应的账户地址，解析失败返回空串
+type AccountResolver func(msg *pb.XuperMessage, stream Stream) string
+
+// ChainRouter 维护链级别的节点成员关系，用于平行链消息路由
+// 本节点服务的链如果设置了群组，该链的消息只发送给群组内且公告服务该链的节点，
+// 也只接收群组内节点发来的消息；未设置群组的链(例如主链)对所有节点开放
+type ChainRouter struct {
+	mu sync.RWMutex
+	// 本节点服务的链 bcname => 群组成员账户，成员为nil表示公开链
+	groups map[string]map[string]struct{}
+	// 远程节点公告服务的链 account => chains
+	peerChains map[string]*peerChains
+}
+
+// peerChains 节点公告的链及公告的签名时间
+type peerChains struct {
+	chains    map[string]struct{}
+	timestamp int64
+}
+
+// NewChainRouter create ChainRouter instance
+func NewChainRouter() *ChainRouter {
+	return &ChainRouter{
+		groups:     make(map[string]map[string]struct{}),
+		peerChains: make(map[string]*peerChains),
+	}
+}
+
+// ServeChain 设置本节点服务的链及其群组成员，members为nil表示该链对所有节点开放，
+// 非nil的空列表表示该链受限但暂无成员，例如群组读取失败时不向任何节点开放
+func (r *ChainRouter) ServeChain(bcname string, members []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if members == nil {
+		r.groups[bcname] = nil
+		return
+	}
+	r.groups[bcname] = toSet(members)
+}
+
+// LeaveChain 本节点不再服务该链
+func (r *ChainRouter) LeaveChain(bcname string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.groups, bcname)
+}
+
+// Chains 返回本节点服务的链，用于向其他节点公告
+func (r *ChainRouter) Chains() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chains := make([]string, 0, len(r.groups))
+	for bcname := range r.groups {
+		chains = append(chains, bcname)
+	}
+	sort.Strings(chains)
+	return chains
+}
+
+// IsRestricted 判断链是否只对群组成员开放
+func (r *ChainRouter) IsRestricted(bcname string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.groups[bcname] != nil
+}
+
+// IsMember 判断账户是否可以参与该链的消息交互，公开链对所有账户返回true
+func (r *ChainRouter) IsMember(bcname, account string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.isMember(bcname, account)
+}
+
+func (r *ChainRouter) isMember(bcname, account string) bool {
+	members := r.groups[bcname]
+	if members == nil {
+		return true
+	}
+
+	_, ok := members[account]
+	return ok
+}
+
+// SetPeerChains 记录远程节点公告服务的链，签名时间早于已记录公告的重放公告被忽略
+func (r *ChainRouter) SetPeerChains(account string, chains []string, timestamp int64) bool {
+	if account == "" {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if old, ok := r.peerChains[account]; ok && timestamp < old.timestamp {
+		return false
+	}
+	r.peerChains[account] = &peerChains{
+		chains:    toSet(chains),
+		timestamp: timestamp,
+	}
+	return true
+}
+
+// Routable 判断该链的消息能否发送给账户对应的节点
+// 受限链要求节点是群组成员，且公告过服务该链；成员公告之前不向其发送该链的消息
+func (r *ChainRouter) Routable(bcname, account string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.groups[bcname] == nil {
+		return true
+	}
+
+	if !r.isMember(bcname, account) {
+		return false
+	}
+
+	peer, ok := r.peerChains[account]
+	if !ok {
+		return false
+	}
+	_, serve := peer.chains[bcname]
+	return serve
+}
+
+func toSet(items []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		if item == "" {
+			continue
+		}
+		set[item] = struct{}{}
+	}
+	return set
+}
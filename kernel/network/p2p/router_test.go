@@ -0,0 +1,97 @@
+package p2p
+
+import (
+	"testing"
+
+	"github.com/xuperchain/xupercore/kernel/mock"
+	nctx "github.com/xuperchain/xupercore/kernel/network/context"
+	pb "github.com/xuperchain/xupercore/protos"
+)
+
+func TestChainRouter(t *testing.T) {
+	router := NewChainRouter()
+	router.ServeChain("xuper", nil)
+	router.ServeChain("para", []string{"alice", "bob"})
+
+	if router.IsRestricted("xuper") || !router.IsRestricted("para") {
+		t.Fatal("restricted chain error")
+	}
+
+	if !router.Routable("xuper", "carol") || !router.Routable("unknown", "carol") {
+		t.Error("public chain should be routable to any peer")
+	}
+
+	// 成员公告服务该链之前不可路由
+	if router.Routable("para", "alice") {
+		t.Error("member not announced should not be routable")
+	}
+	router.SetPeerChains("alice", []string{"para"}, 1)
+	router.SetPeerChains("carol", []string{"para"}, 1)
+	if !router.Routable("para", "alice") || router.Routable("para", "carol") || router.Routable("para", "") {
+		t.Error("restricted chain should only be routable to members")
+	}
+
+	// bob is a member but announced that it does not serve para
+	router.SetPeerChains("bob", []string{"xuper"}, 10)
+	if router.Routable("para", "bob") {
+		t.Error("peer not serving chain should not be routable")
+	}
+	// 重放更早的公告不能恢复已离开的链
+	if router.SetPeerChains("bob", []string{"para"}, 9) || router.Routable("para", "bob") {
+		t.Error("stale announcement should be ignored")
+	}
+	if !router.IsMember("para", "bob") {
+		t.Error("bob should still be member of para")
+	}
+
+	chains := router.Chains()
+	if len(chains) != 2 || chains[0] != "para" || chains[1] != "xuper" {
+		t.Errorf("chains error: %v", chains)
+	}
+
+	// 受限但暂无成员的链不向任何节点开放
+	router.ServeChain("locked", []string{})
+	if !router.IsRestricted("locked") || router.Routable("locked", "alice") || router.IsMember("locked", "alice") {
+		t.Error("restricted chain without members should not be routable")
+	}
+
+	router.LeaveChain("para")
+	if router.IsRestricted("para") || len(router.Chains()) != 2 {
+		t.Error("leave chain error")
+	}
+}
+
+func TestDispatcherChainMember(t *testing.T) {
+	mock.InitLogForTest()
+	ecfg, err := mock.NewEnvConfForTest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, _ := nctx.NewNetCtx(ecfg)
+
+	router := NewChainRouter()
+	router.ServeChain("para", []string{"alice"})
+	resolver := func(msg *pb.XuperMessage, stream Stream) string {
+		return msg.GetHeader().GetFrom()
+	}
+	dispatcher := NewDispatcher(ctx, WithChainRouter(router, resolver))
+
+	ch := make(chan *pb.XuperMessage, 1)
+	if err := dispatcher.Register(NewSubscriber(ctx, pb.XuperMessage_POSTTX, ch)); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := NewMessage(pb.XuperMessage_POSTTX, &pb.XuperMessage{}, WithBCName("para"), WithLogId("member"))
+	msg.Header.From = "carol"
+	if err := dispatcher.Dispatch(msg, &mockStream{}); err != ErrNotChainMember {
+		t.Errorf("dispatch from non member error: %v", err)
+	}
+
+	msg.Header.From = "alice"
+	if err := dispatcher.Dispatch(msg, &mockStream{}); err != nil {
+		t.Errorf("dispatch from member error: %v", err)
+	}
+	if len(ch) != 1 {
+		t.Error("message from member should be dispatched")
+	}
+}
@@ -34,6 +34,9 @@ type Stream interface {
 
 type HandleFunc func(xctx.XContext, *pb.XuperMessage) (*pb.XuperMessage, error)
 
+// StreamHandleFunc 与HandleFunc相同，可以从stream获取传输层的对端信息
+type StreamHandleFunc func(xctx.XContext, *pb.XuperMessage, Stream) (*pb.XuperMessage, error)
+
 type SubscriberOption func(*subscriber)
 
 func WithFilterFrom(from string) SubscriberOption {
@@ -59,6 +62,10 @@ func NewSubscriber(ctx *nctx.NetCtx, typ pb.XuperMessage_MessageType,
 
 	switch obj := v.(type) {
 	case HandleFunc:
+		s.handler = func(ctx xctx.XContext, msg *pb.XuperMessage, _ Stream) (*pb.XuperMessage, error) {
+			return obj(ctx, msg)
+		}
+	case StreamHandleFunc:
 		s.handler = obj
 	case chan *pb.XuperMessage:
 		s.channel = obj
@@ -90,7 +97,7 @@ type subscriber struct {
 	from   string // 接收指定节点的消息
 
 	channel chan *pb.XuperMessage
-	handler HandleFunc
+	handler StreamHandleFunc
 }
 
 var _ Subscriber = &subscriber{}
@@ -123,7 +130,7 @@ func (s *subscriber) HandleMessage(ctx xctx.XContext, msg *pb.XuperMessage, stre
 	}()
 
 	if s.handler != nil {
-		resp, err := s.handler(ctx, msg)
+		resp, err := s.handler(ctx, msg, stream)
 		ctx.GetTimer().Mark("handle")
 		if err != nil {
 			ctx.GetLog().Error("subscriber: call user handler error", "err", err)
@@ -85,6 +85,47 @@ var (
 			Buckets: DefBuckets,
 		},
 		[]string{LabelBCName, LabelContractModuleName, LabelContractName, LabelContractMethod})
+	// native合约进程
+	ContractNativeRunningGauge = prom.NewGaugeVec(
+		prom.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemContract,
+			Name: "native_process_running",
+			Help: "Whether native contract process is running.",
+		},
+		[]string{LabelContractName})
+	ContractNativeRestartCounter = prom.NewCounterVec(
+		prom.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemContract,
+			Name: "native_process_restart_total",
+			Help: "Total number of native contract process restart.",
+		},
+		[]string{LabelContractName})
+	ContractNativeCPUGauge = prom.NewGaugeVec(
+		prom.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemContract,
+			Name: "native_process_cpu_seconds",
+			Help: "Total cpu seconds used by native contract process.",
+		},
+		[]string{LabelContractName})
+	ContractNativeMemoryGauge = prom.NewGaugeVec(
+		prom.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemContract,
+			Name: "native_process_memory_bytes",
+			Help: "Memory bytes used by native contract process.",
+		},
+		[]string{LabelContractName})
+	ContractNativeConcurrentCallGauge = prom.NewGaugeVec(
+		prom.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: SubsystemContract,
+			Name: "native_concurrent_calls",
+			Help: "Number of concurrent calls of native contract.",
+		},
+		[]string{LabelContractName})
 )
 
 // ledger
@@ -192,6 +233,11 @@ func RegisterMetrics() {
 	// contract
 	prom.MustRegister(ContractInvokeCounter)
 	prom.MustRegister(ContractInvokeHistogram)
+	prom.MustRegister(ContractNativeRunningGauge)
+	prom.MustRegister(ContractNativeRestartCounter)
+	prom.MustRegister(ContractNativeCPUGauge)
+	prom.MustRegister(ContractNativeMemoryGauge)
+	prom.MustRegister(ContractNativeConcurrentCallGauge)
 	// ledger
 	prom.MustRegister(LedgerConfirmTxCounter)
 	prom.MustRegister(LedgerSwitchBranchCounter)
@@ -18,6 +18,7 @@ type KVParameter struct {
 const (
 	KVEngineTypeLDB    = "leveldb"
 	KVEngineTypeBadger = "badger"
+	KVEngineTypeMemory = "memory"
 )
 
 const (
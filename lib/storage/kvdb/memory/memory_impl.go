@@ -0,0 +1,377 @@
+package memory
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/emirpasic/gods/trees/redblacktree"
+
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+)
+
+// ErrNotFound key不存在，满足kvdb.ErrNotFound的判断
+var ErrNotFound = errors.New("memory: not found")
+
+var (
+	storesMu sync.Mutex
+	// 同一进程内相同路径且仍打开的实例共享数据，所有实例关闭后释放
+	stores = make(map[string]*memStore)
+	// 关闭后仍保留数据的路径前缀 prefix => 保留次数
+	retained = make(map[string]int)
+)
+
+// memStore 按key有序存储的内存数据
+type memStore struct {
+	mu   sync.RWMutex
+	tree *redblacktree.Tree
+	// refs 打开该数据的实例数，由storesMu保护
+	refs int
+}
+
+func newMemStore() *memStore {
+	return &memStore{
+		tree: redblacktree.NewWith(compare),
+	}
+}
+
+// MemDatabase 内存kv引擎，数据不落盘，用于单测和临时节点
+type MemDatabase struct {
+	fn     string
+	store  *memStore
+	closed bool
+}
+
+// NewKVDBInstance create memory kv instance
+func NewKVDBInstance(param *kvdb.KVParameter) (kvdb.Database, error) {
+	baseDB := new(MemDatabase)
+	err := baseDB.Open(param.GetDBPath(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return baseDB, nil
+}
+
+func init() {
+	kvdb.Register(kvdb.KVEngineTypeMemory, NewKVDBInstance)
+}
+
+// Open 打开路径对应的内存实例，路径为空时创建独立的实例
+func (db *MemDatabase) Open(path string, options map[string]interface{}) error {
+	db.fn = path
+	if path == "" {
+		db.store = newMemStore()
+		return nil
+	}
+
+	storesMu.Lock()
+	defer storesMu.Unlock()
+
+	store, ok := stores[path]
+	if !ok {
+		store = newMemStore()
+		stores[path] = store
+	}
+	store.refs++
+	db.store = store
+	return nil
+}
+
+// Path returns the path of the database
+func (db *MemDatabase) Path() string {
+	return db.fn
+}
+
+// Put puts the given key / value
+func (db *MemDatabase) Put(key []byte, value []byte) error {
+	db.store.mu.Lock()
+	defer db.store.mu.Unlock()
+
+	db.store.tree.Put(copyBytes(key), copyBytes(value))
+	return nil
+}
+
+// Has if the given key exists
+func (db *MemDatabase) Has(key []byte) (bool, error) {
+	db.store.mu.RLock()
+	defer db.store.mu.RUnlock()
+
+	_, ok := db.store.tree.Get(key)
+	return ok, nil
+}
+
+// Get returns the given key if it's present.
+func (db *MemDatabase) Get(key []byte) ([]byte, error) {
+	db.store.mu.RLock()
+	defer db.store.mu.RUnlock()
+
+	value, ok := db.store.tree.Get(key)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return copyBytes(value.([]byte)), nil
+}
+
+// Delete deletes the key
+func (db *MemDatabase) Delete(key []byte) error {
+	db.store.mu.Lock()
+	defer db.store.mu.Unlock()
+
+	db.store.tree.Remove(key)
+	return nil
+}
+
+// Close 关闭实例，同一路径的实例全部关闭后释放数据
+func (db *MemDatabase) Close() {
+	if db.fn == "" || db.closed {
+		return
+	}
+
+	storesMu.Lock()
+	defer storesMu.Unlock()
+
+	db.closed = true
+	db.store.refs--
+	if db.store.refs <= 0 && stores[db.fn] == db.store && !isRetained(db.fn) {
+		delete(stores, db.fn)
+	}
+}
+
+// Retain 在release调用前保留路径前缀下已关闭实例的数据，同一进程内可通过相同路径重新打开
+func Retain(prefix string) (release func()) {
+	storesMu.Lock()
+	retained[prefix]++
+	storesMu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			storesMu.Lock()
+			defer storesMu.Unlock()
+
+			if retained[prefix]--; retained[prefix] <= 0 {
+				delete(retained, prefix)
+			}
+			for path, store := range stores {
+				if store.refs <= 0 && !isRetained(path) {
+					delete(stores, path)
+				}
+			}
+		})
+	}
+}
+
+// isRetained 调用方需持有storesMu
+func isRetained(path string) bool {
+	for prefix := range retained {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewIteratorWithRange returns an instance of Iterator with range [start, limit)
+func (db *MemDatabase) NewIteratorWithRange(start []byte, limit []byte) kvdb.Iterator {
+	return db.newIterator(start, limit)
+}
+
+// NewIteratorWithPrefix returns an instance of Iterator with prefix
+func (db *MemDatabase) NewIteratorWithPrefix(prefix []byte) kvdb.Iterator {
+	if len(prefix) == 0 {
+		return db.newIterator(nil, nil)
+	}
+	return db.newIterator(prefix, prefixEnd(prefix))
+}
+
+// newIterator 创建迭代器时复制区间内的数据，迭代过程不受后续写入影响
+func (db *MemDatabase) newIterator(start []byte, limit []byte) kvdb.Iterator {
+	db.store.mu.RLock()
+	defer db.store.mu.RUnlock()
+
+	it := &memIterator{pos: -1}
+	tree := db.store.tree
+	var node *redblacktree.Node
+	if start == nil {
+		node = tree.Left()
+	} else {
+		var ok bool
+		if node, ok = tree.Ceiling(start); !ok {
+			return it
+		}
+	}
+	if node == nil {
+		return it
+	}
+
+	iter := tree.IteratorAt(node)
+	for ok := true; ok; ok = iter.Next() {
+		key := iter.Key().([]byte)
+		if limit != nil && bytes.Compare(key, limit) >= 0 {
+			break
+		}
+		it.keys = append(it.keys, key)
+		it.values = append(it.values, iter.Value().([]byte))
+	}
+	return it
+}
+
+// NewBatch returns batch instance
+func (db *MemDatabase) NewBatch() kvdb.Batch {
+	return &memBatch{db: db, keys: map[string]bool{}}
+}
+
+type batchOp struct {
+	key    []byte
+	value  []byte
+	delete bool
+}
+
+type memBatch struct {
+	db   *MemDatabase
+	ops  []batchOp
+	size int
+	keys map[string]bool
+}
+
+func (b *memBatch) Put(key, value []byte) error {
+	b.ops = append(b.ops, batchOp{key: copyBytes(key), value: copyBytes(value)})
+	b.size += len(value)
+	return nil
+}
+
+func (b *memBatch) Delete(key []byte) error {
+	b.ops = append(b.ops, batchOp{key: copyBytes(key), delete: true})
+	b.size += len(key)
+	return nil
+}
+
+func (b *memBatch) PutIfAbsent(key, value []byte) error {
+	if !b.keys[string(key)] {
+		b.ops = append(b.ops, batchOp{key: copyBytes(key), value: copyBytes(value)})
+		b.size += len(value)
+		b.keys[string(key)] = true
+		return nil
+	}
+	return fmt.Errorf("duplicated key in batch, (HEX) %x", key)
+}
+
+func (b *memBatch) Exist(key []byte) bool {
+	return b.keys[string(key)]
+}
+
+// Write 原子地写入batch中的全部操作
+func (b *memBatch) Write() error {
+	store := b.db.store
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	for _, op := range b.ops {
+		if op.delete {
+			store.tree.Remove(op.key)
+			continue
+		}
+		store.tree.Put(op.key, op.value)
+	}
+	return nil
+}
+
+func (b *memBatch) ValueSize() int {
+	return b.size
+}
+
+func (b *memBatch) Reset() {
+	b.ops = nil
+	b.size = 0
+	b.keys = map[string]bool{}
+}
+
+// memIterator 有序迭代器，初始位置在第一个元素之前
+type memIterator struct {
+	keys   [][]byte
+	values [][]byte
+	pos    int
+}
+
+func (it *memIterator) valid() bool {
+	return it.pos >= 0 && it.pos < len(it.keys)
+}
+
+func (it *memIterator) Key() []byte {
+	if !it.valid() {
+		return nil
+	}
+	return it.keys[it.pos]
+}
+
+func (it *memIterator) Value() []byte {
+	if !it.valid() {
+		return nil
+	}
+	return it.values[it.pos]
+}
+
+func (it *memIterator) Next() bool {
+	if it.pos < len(it.keys) {
+		it.pos++
+	}
+	return it.valid()
+}
+
+func (it *memIterator) Prev() bool {
+	if it.pos >= 0 {
+		it.pos--
+	}
+	return it.valid()
+}
+
+func (it *memIterator) First() bool {
+	it.pos = 0
+	return it.valid()
+}
+
+func (it *memIterator) Last() bool {
+	it.pos = len(it.keys) - 1
+	return it.valid()
+}
+
+func (it *memIterator) Error() error {
+	return nil
+}
+
+func (it *memIterator) Release() {
+	it.keys = nil
+	it.values = nil
+	it.pos = -1
+}
+
+func compare(a, b interface{}) int {
+	return bytes.Compare(a.([]byte), b.([]byte))
+}
+
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return []byte{}
+	}
+	c := make([]byte, len(b))
+	copy(c, b)
+	return c
+}
+
+// prefixEnd返回第一个大于prefix的[]byte，prefix全部为0xff时返回nil
+func prefixEnd(prefix []byte) []byte {
+	var limit []byte
+	for i := len(prefix) - 1; i >= 0; i-- {
+		c := prefix[i]
+		if c < 0xff {
+			limit = make([]byte, i+1)
+			copy(limit, prefix)
+			limit[i] = c + 1
+			break
+		}
+	}
+	return limit
+}
@@ -0,0 +1,173 @@
+package memory
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/xuperchain/xupercore/lib/storage/kvdb"
+)
+
+func makeDB(path string) (kvdb.Database, error) {
+	kvParam := &kvdb.KVParameter{
+		DBPath:       path,
+		KVEngineType: kvdb.KVEngineTypeMemory,
+	}
+	return kvdb.CreateKVInstance(kvParam)
+}
+
+func TestMemDatabase(t *testing.T) {
+	db, err := makeDB("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.Put([]byte("key1"), []byte("value1")); err != nil {
+		t.Fatal(err)
+	}
+	value, err := db.Get([]byte("key1"))
+	if err != nil || string(value) != "value1" {
+		t.Fatalf("get key1 error, value:%s err:%v", value, err)
+	}
+	if ok, _ := db.Has([]byte("key1")); !ok {
+		t.Fatal("key1 should exist")
+	}
+
+	if err := db.Delete([]byte("key1")); err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Get([]byte("key1"))
+	if err == nil || !kvdb.ErrNotFound(err) {
+		t.Fatalf("expect not found, got %v", err)
+	}
+}
+
+func TestMemBatch(t *testing.T) {
+	db, _ := makeDB("")
+	db.Put([]byte("del"), []byte("v"))
+
+	batch := db.NewBatch()
+	batch.Put([]byte("a"), []byte("1"))
+	batch.Delete([]byte("del"))
+	if err := batch.PutIfAbsent([]byte("b"), []byte("2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := batch.PutIfAbsent([]byte("b"), []byte("3")); err == nil {
+		t.Fatal("expect duplicated key error")
+	}
+	if !batch.Exist([]byte("b")) || batch.Exist([]byte("c")) {
+		t.Fatal("batch exist error")
+	}
+
+	// 写入前不可见
+	if ok, _ := db.Has([]byte("a")); ok {
+		t.Fatal("batch should not be visible before write")
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := db.Has([]byte("a")); !ok {
+		t.Fatal("a should exist after write")
+	}
+	if ok, _ := db.Has([]byte("del")); ok {
+		t.Fatal("del should be deleted after write")
+	}
+
+	batch.Reset()
+	if batch.ValueSize() != 0 || batch.Exist([]byte("b")) {
+		t.Fatal("batch reset error")
+	}
+}
+
+func TestMemIterator(t *testing.T) {
+	db, _ := makeDB("")
+	for _, k := range []string{"p2", "a", "p1", "p3", "z"} {
+		db.Put([]byte(k), []byte("v"+k))
+	}
+
+	var keys []string
+	it := db.NewIteratorWithPrefix([]byte("p"))
+	for it.Next() {
+		keys = append(keys, string(it.Key()))
+	}
+	if len(keys) != 3 || keys[0] != "p1" || keys[2] != "p3" {
+		t.Fatalf("prefix iterate error, keys:%v", keys)
+	}
+	if !it.Prev() || string(it.Key()) != "p3" {
+		t.Fatal("prev from end should be last")
+	}
+	if !it.First() || string(it.Key()) != "p1" || it.Prev() {
+		t.Fatal("first error")
+	}
+	if !it.Last() || !bytes.Equal(it.Value(), []byte("vp3")) {
+		t.Fatal("last error")
+	}
+	it.Release()
+
+	it = db.NewIteratorWithRange([]byte("b"), []byte("p3"))
+	keys = keys[:0]
+	for it.Next() {
+		keys = append(keys, string(it.Key()))
+	}
+	it.Release()
+	if len(keys) != 2 || keys[0] != "p1" || keys[1] != "p2" {
+		t.Fatalf("range iterate error, keys:%v", keys)
+	}
+
+	it = db.NewIteratorWithPrefix([]byte("none"))
+	if it.Next() || it.First() || it.Last() || it.Key() != nil {
+		t.Fatal("empty iterator error")
+	}
+	it.Release()
+}
+
+func TestMemShareAndRelease(t *testing.T) {
+	path := "/memory/test/share"
+
+	db, _ := makeDB(path)
+	db.Put([]byte("key"), []byte("value"))
+
+	// 仍打开的实例共享数据
+	other, _ := makeDB(path)
+	if value, err := other.Get([]byte("key")); err != nil || string(value) != "value" {
+		t.Fatalf("share error, value:%s err:%v", value, err)
+	}
+	other.Close()
+	other.Close()
+	if value, err := db.Get([]byte("key")); err != nil || string(value) != "value" {
+		t.Fatalf("data should be kept while opened, value:%s err:%v", value, err)
+	}
+
+	// 所有实例关闭后释放
+	db.Close()
+	if _, ok := stores[path]; ok {
+		t.Fatal("store should be released after close")
+	}
+	db, _ = makeDB(path)
+	if ok, _ := db.Has([]byte("key")); ok {
+		t.Fatal("data should be released")
+	}
+	db.Close()
+}
+
+func TestMemRetain(t *testing.T) {
+	path := "/memory/test/retain/db"
+	release := Retain("/memory/test/retain")
+
+	db, _ := makeDB(path)
+	db.Put([]byte("key"), []byte("value"))
+	db.Close()
+
+	// 保留期间关闭后可以重新打开
+	db, _ = makeDB(path)
+	if value, err := db.Get([]byte("key")); err != nil || string(value) != "value" {
+		t.Fatalf("reopen error, value:%s err:%v", value, err)
+	}
+	db.Close()
+
+	release()
+	release()
+	if _, ok := stores[path]; ok {
+		t.Fatal("store should be released after release")
+	}
+}
@@ -1,5 +1,5 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
-// source: protos/contract.proto
+// source: contract.proto
 
 package protos
 
@@ -49,7 +49,7 @@ func (x ResourceType) String() string {
 }
 
 func (ResourceType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_919de52f3bf773d2, []int{0}
+	return fileDescriptor_d19debeba7dea55a, []int{0}
 }
 
 type GasPrice struct {
@@ -66,7 +66,7 @@ func (m *GasPrice) Reset()         { *m = GasPrice{} }
 func (m *GasPrice) String() string { return proto.CompactTextString(m) }
 func (*GasPrice) ProtoMessage()    {}
 func (*GasPrice) Descriptor() ([]byte, []int) {
-	return fileDescriptor_919de52f3bf773d2, []int{0}
+	return fileDescriptor_d19debeba7dea55a, []int{0}
 }
 
 func (m *GasPrice) XXX_Unmarshal(b []byte) error {
@@ -127,7 +127,7 @@ func (m *ResourceLimit) Reset()         { *m = ResourceLimit{} }
 func (m *ResourceLimit) String() string { return proto.CompactTextString(m) }
 func (*ResourceLimit) ProtoMessage()    {}
 func (*ResourceLimit) Descriptor() ([]byte, []int) {
-	return fileDescriptor_919de52f3bf773d2, []int{1}
+	return fileDescriptor_d19debeba7dea55a, []int{1}
 }
 
 func (m *ResourceLimit) XXX_Unmarshal(b []byte) error {
@@ -171,7 +171,9 @@ type InvokeRequest struct {
 	ResourceLimits []*ResourceLimit  `protobuf:"bytes,5,rep,name=resource_limits,json=resourceLimits,proto3" json:"resource_limits,omitempty"`
 	// amount is the amount transfer to the contract
 	// attention: In one transaction, transfer to only one contract is allowed
-	Amount               string   `protobuf:"bytes,6,opt,name=amount,proto3" json:"amount,omitempty"`
+	Amount string `protobuf:"bytes,6,opt,name=amount,proto3" json:"amount,omitempty"`
+	// trace 预执行时记录合约的调用树和系统调用，不会写入交易
+	Trace                bool     `protobuf:"varint,7,opt,name=trace,proto3" json:"trace,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -181,7 +183,7 @@ func (m *InvokeRequest) Reset()         { *m = InvokeRequest{} }
 func (m *InvokeRequest) String() string { return proto.CompactTextString(m) }
 func (*InvokeRequest) ProtoMessage()    {}
 func (*InvokeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_919de52f3bf773d2, []int{2}
+	return fileDescriptor_d19debeba7dea55a, []int{2}
 }
 
 func (m *InvokeRequest) XXX_Unmarshal(b []byte) error {
@@ -244,26 +246,37 @@ func (m *InvokeRequest) GetAmount() string {
 	return ""
 }
 
+func (m *InvokeRequest) GetTrace() bool {
+	if m != nil {
+		return m.Trace
+	}
+	return false
+}
+
 // 预执行的返回结构
 type InvokeResponse struct {
-	Inputs               []*TxInputExt       `protobuf:"bytes,1,rep,name=inputs,proto3" json:"inputs,omitempty"`
-	Outputs              []*TxOutputExt      `protobuf:"bytes,2,rep,name=outputs,proto3" json:"outputs,omitempty"`
-	Response             [][]byte            `protobuf:"bytes,3,rep,name=response,proto3" json:"response,omitempty"`
-	GasUsed              int64               `protobuf:"varint,4,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
-	Requests             []*InvokeRequest    `protobuf:"bytes,5,rep,name=requests,proto3" json:"requests,omitempty"`
-	Responses            []*ContractResponse `protobuf:"bytes,6,rep,name=responses,proto3" json:"responses,omitempty"`
-	UtxoInputs           []*TxInput          `protobuf:"bytes,7,rep,name=utxoInputs,proto3" json:"utxoInputs,omitempty"`
-	UtxoOutputs          []*TxOutput         `protobuf:"bytes,8,rep,name=utxoOutputs,proto3" json:"utxoOutputs,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
-	XXX_unrecognized     []byte              `json:"-"`
-	XXX_sizecache        int32               `json:"-"`
+	Inputs      []*TxInputExt       `protobuf:"bytes,1,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	Outputs     []*TxOutputExt      `protobuf:"bytes,2,rep,name=outputs,proto3" json:"outputs,omitempty"`
+	Response    [][]byte            `protobuf:"bytes,3,rep,name=response,proto3" json:"response,omitempty"`
+	GasUsed     int64               `protobuf:"varint,4,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
+	Requests    []*InvokeRequest    `protobuf:"bytes,5,rep,name=requests,proto3" json:"requests,omitempty"`
+	Responses   []*ContractResponse `protobuf:"bytes,6,rep,name=responses,proto3" json:"responses,omitempty"`
+	UtxoInputs  []*TxInput          `protobuf:"bytes,7,rep,name=utxoInputs,proto3" json:"utxoInputs,omitempty"`
+	UtxoOutputs []*TxOutput         `protobuf:"bytes,8,rep,name=utxoOutputs,proto3" json:"utxoOutputs,omitempty"`
+	// 请求设置trace时返回，与请求一一对应，未开启trace的请求为空
+	Traces []*ContractTrace `protobuf:"bytes,9,rep,name=traces,proto3" json:"traces,omitempty"`
+	// 轨迹超过节点配置的大小上限，之后的记录被丢弃
+	TracesTruncated      bool     `protobuf:"varint,10,opt,name=traces_truncated,json=tracesTruncated,proto3" json:"traces_truncated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *InvokeResponse) Reset()         { *m = InvokeResponse{} }
 func (m *InvokeResponse) String() string { return proto.CompactTextString(m) }
 func (*InvokeResponse) ProtoMessage()    {}
 func (*InvokeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_919de52f3bf773d2, []int{3}
+	return fileDescriptor_d19debeba7dea55a, []int{3}
 }
 
 func (m *InvokeResponse) XXX_Unmarshal(b []byte) error {
@@ -340,6 +353,256 @@ func (m *InvokeResponse) GetUtxoOutputs() []*TxOutput {
 	return nil
 }
 
+func (m *InvokeResponse) GetTraces() []*ContractTrace {
+	if m != nil {
+		return m.Traces
+	}
+	return nil
+}
+
+func (m *InvokeResponse) GetTracesTruncated() bool {
+	if m != nil {
+		return m.TracesTruncated
+	}
+	return false
+}
+
+// ContractTrace 一次合约调用的执行轨迹
+type ContractTrace struct {
+	ModuleName   string            `protobuf:"bytes,1,opt,name=module_name,json=moduleName,proto3" json:"module_name,omitempty"`
+	ContractName string            `protobuf:"bytes,2,opt,name=contract_name,json=contractName,proto3" json:"contract_name,omitempty"`
+	MethodName   string            `protobuf:"bytes,3,opt,name=method_name,json=methodName,proto3" json:"method_name,omitempty"`
+	Args         map[string][]byte `protobuf:"bytes,4,rep,name=args,proto3" json:"args,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// 按发生顺序记录的系统调用，子合约调用记录为ContractCall
+	Syscalls []*SyscallTrace `protobuf:"bytes,5,rep,name=syscalls,proto3" json:"syscalls,omitempty"`
+	// 本次调用消耗的资源，包括子合约调用
+	ResourceUsed []*ResourceLimit `protobuf:"bytes,6,rep,name=resource_used,json=resourceUsed,proto3" json:"resource_used,omitempty"`
+	Status       int32            `protobuf:"varint,7,opt,name=status,proto3" json:"status,omitempty"`
+	Message      string           `protobuf:"bytes,8,opt,name=message,proto3" json:"message,omitempty"`
+	Error        string           `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	// evm合约内部的调用，按调用结束的顺序记录
+	EvmCalls             []string `protobuf:"bytes,10,rep,name=evm_calls,json=evmCalls,proto3" json:"evm_calls,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ContractTrace) Reset()         { *m = ContractTrace{} }
+func (m *ContractTrace) String() string { return proto.CompactTextString(m) }
+func (*ContractTrace) ProtoMessage()    {}
+func (*ContractTrace) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d19debeba7dea55a, []int{4}
+}
+
+func (m *ContractTrace) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContractTrace.Unmarshal(m, b)
+}
+func (m *ContractTrace) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContractTrace.Marshal(b, m, deterministic)
+}
+func (m *ContractTrace) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContractTrace.Merge(m, src)
+}
+func (m *ContractTrace) XXX_Size() int {
+	return xxx_messageInfo_ContractTrace.Size(m)
+}
+func (m *ContractTrace) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContractTrace.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContractTrace proto.InternalMessageInfo
+
+func (m *ContractTrace) GetModuleName() string {
+	if m != nil {
+		return m.ModuleName
+	}
+	return ""
+}
+
+func (m *ContractTrace) GetContractName() string {
+	if m != nil {
+		return m.ContractName
+	}
+	return ""
+}
+
+func (m *ContractTrace) GetMethodName() string {
+	if m != nil {
+		return m.MethodName
+	}
+	return ""
+}
+
+func (m *ContractTrace) GetArgs() map[string][]byte {
+	if m != nil {
+		return m.Args
+	}
+	return nil
+}
+
+func (m *ContractTrace) GetSyscalls() []*SyscallTrace {
+	if m != nil {
+		return m.Syscalls
+	}
+	return nil
+}
+
+func (m *ContractTrace) GetResourceUsed() []*ResourceLimit {
+	if m != nil {
+		return m.ResourceUsed
+	}
+	return nil
+}
+
+func (m *ContractTrace) GetStatus() int32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *ContractTrace) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *ContractTrace) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *ContractTrace) GetEvmCalls() []string {
+	if m != nil {
+		return m.EvmCalls
+	}
+	return nil
+}
+
+// SyscallTrace 一次系统调用的参数和结果
+type SyscallTrace struct {
+	// Get, Put, Del, Select, ReverseSelect, IteratorNext, Transfer, EmitEvent, ContractCall
+	Name                 string         `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Bucket               string         `protobuf:"bytes,2,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Key                  []byte         `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	Value                []byte         `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	EndKey               []byte         `protobuf:"bytes,5,opt,name=end_key,json=endKey,proto3" json:"end_key,omitempty"`
+	From                 string         `protobuf:"bytes,6,opt,name=from,proto3" json:"from,omitempty"`
+	To                   string         `protobuf:"bytes,7,opt,name=to,proto3" json:"to,omitempty"`
+	Amount               string         `protobuf:"bytes,8,opt,name=amount,proto3" json:"amount,omitempty"`
+	Event                *ContractEvent `protobuf:"bytes,9,opt,name=event,proto3" json:"event,omitempty"`
+	Error                string         `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`
+	Call                 *ContractTrace `protobuf:"bytes,11,opt,name=call,proto3" json:"call,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *SyscallTrace) Reset()         { *m = SyscallTrace{} }
+func (m *SyscallTrace) String() string { return proto.CompactTextString(m) }
+func (*SyscallTrace) ProtoMessage()    {}
+func (*SyscallTrace) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d19debeba7dea55a, []int{5}
+}
+
+func (m *SyscallTrace) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SyscallTrace.Unmarshal(m, b)
+}
+func (m *SyscallTrace) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SyscallTrace.Marshal(b, m, deterministic)
+}
+func (m *SyscallTrace) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SyscallTrace.Merge(m, src)
+}
+func (m *SyscallTrace) XXX_Size() int {
+	return xxx_messageInfo_SyscallTrace.Size(m)
+}
+func (m *SyscallTrace) XXX_DiscardUnknown() {
+	xxx_messageInfo_SyscallTrace.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SyscallTrace proto.InternalMessageInfo
+
+func (m *SyscallTrace) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SyscallTrace) GetBucket() string {
+	if m != nil {
+		return m.Bucket
+	}
+	return ""
+}
+
+func (m *SyscallTrace) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *SyscallTrace) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *SyscallTrace) GetEndKey() []byte {
+	if m != nil {
+		return m.EndKey
+	}
+	return nil
+}
+
+func (m *SyscallTrace) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *SyscallTrace) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+func (m *SyscallTrace) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *SyscallTrace) GetEvent() *ContractEvent {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (m *SyscallTrace) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *SyscallTrace) GetCall() *ContractTrace {
+	if m != nil {
+		return m.Call
+	}
+	return nil
+}
+
 // ContractResponse is the response returnd by contract
 type ContractResponse struct {
 	Status               int32    `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
@@ -354,7 +617,7 @@ func (m *ContractResponse) Reset()         { *m = ContractResponse{} }
 func (m *ContractResponse) String() string { return proto.CompactTextString(m) }
 func (*ContractResponse) ProtoMessage()    {}
 func (*ContractResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_919de52f3bf773d2, []int{4}
+	return fileDescriptor_d19debeba7dea55a, []int{6}
 }
 
 func (m *ContractResponse) XXX_Unmarshal(b []byte) error {
@@ -411,7 +674,7 @@ func (m *WasmCodeDesc) Reset()         { *m = WasmCodeDesc{} }
 func (m *WasmCodeDesc) String() string { return proto.CompactTextString(m) }
 func (*WasmCodeDesc) ProtoMessage()    {}
 func (*WasmCodeDesc) Descriptor() ([]byte, []int) {
-	return fileDescriptor_919de52f3bf773d2, []int{5}
+	return fileDescriptor_d19debeba7dea55a, []int{7}
 }
 
 func (m *WasmCodeDesc) XXX_Unmarshal(b []byte) error {
@@ -480,7 +743,7 @@ func (m *ContractEvent) Reset()         { *m = ContractEvent{} }
 func (m *ContractEvent) String() string { return proto.CompactTextString(m) }
 func (*ContractEvent) ProtoMessage()    {}
 func (*ContractEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_919de52f3bf773d2, []int{6}
+	return fileDescriptor_d19debeba7dea55a, []int{8}
 }
 
 func (m *ContractEvent) XXX_Unmarshal(b []byte) error {
@@ -534,7 +797,7 @@ func (m *ContractStatData) Reset()         { *m = ContractStatData{} }
 func (m *ContractStatData) String() string { return proto.CompactTextString(m) }
 func (*ContractStatData) ProtoMessage()    {}
 func (*ContractStatData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_919de52f3bf773d2, []int{7}
+	return fileDescriptor_d19debeba7dea55a, []int{9}
 }
 
 func (m *ContractStatData) XXX_Unmarshal(b []byte) error {
@@ -586,7 +849,7 @@ func (m *ContractStatus) Reset()         { *m = ContractStatus{} }
 func (m *ContractStatus) String() string { return proto.CompactTextString(m) }
 func (*ContractStatus) ProtoMessage()    {}
 func (*ContractStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_919de52f3bf773d2, []int{8}
+	return fileDescriptor_d19debeba7dea55a, []int{10}
 }
 
 func (m *ContractStatus) XXX_Unmarshal(b []byte) error {
@@ -649,6 +912,191 @@ func (m *ContractStatus) GetRuntime() string {
 	return ""
 }
 
+// Version record of a contract, txid and height are filled when queried
+type ContractVersion struct {
+	Version int64  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Digest  []byte `protobuf:"bytes,2,opt,name=digest,proto3" json:"digest,omitempty"`
+	// whether migrate method is invoked in the upgrade tx
+	Migrated             bool     `protobuf:"varint,3,opt,name=migrated,proto3" json:"migrated,omitempty"`
+	Txid                 string   `protobuf:"bytes,4,opt,name=txid,proto3" json:"txid,omitempty"`
+	Height               int64    `protobuf:"varint,5,opt,name=height,proto3" json:"height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ContractVersion) Reset()         { *m = ContractVersion{} }
+func (m *ContractVersion) String() string { return proto.CompactTextString(m) }
+func (*ContractVersion) ProtoMessage()    {}
+func (*ContractVersion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d19debeba7dea55a, []int{11}
+}
+
+func (m *ContractVersion) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContractVersion.Unmarshal(m, b)
+}
+func (m *ContractVersion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContractVersion.Marshal(b, m, deterministic)
+}
+func (m *ContractVersion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContractVersion.Merge(m, src)
+}
+func (m *ContractVersion) XXX_Size() int {
+	return xxx_messageInfo_ContractVersion.Size(m)
+}
+func (m *ContractVersion) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContractVersion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContractVersion proto.InternalMessageInfo
+
+func (m *ContractVersion) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *ContractVersion) GetDigest() []byte {
+	if m != nil {
+		return m.Digest
+	}
+	return nil
+}
+
+func (m *ContractVersion) GetMigrated() bool {
+	if m != nil {
+		return m.Migrated
+	}
+	return false
+}
+
+func (m *ContractVersion) GetTxid() string {
+	if m != nil {
+		return m.Txid
+	}
+	return ""
+}
+
+func (m *ContractVersion) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+// Storage usage of a contract, used and quota are in bytes, quota 0 means unlimited
+type ContractStorage struct {
+	ContractName         string   `protobuf:"bytes,1,opt,name=contract_name,json=contractName,proto3" json:"contract_name,omitempty"`
+	Used                 int64    `protobuf:"varint,2,opt,name=used,proto3" json:"used,omitempty"`
+	Quota                int64    `protobuf:"varint,3,opt,name=quota,proto3" json:"quota,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ContractStorage) Reset()         { *m = ContractStorage{} }
+func (m *ContractStorage) String() string { return proto.CompactTextString(m) }
+func (*ContractStorage) ProtoMessage()    {}
+func (*ContractStorage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d19debeba7dea55a, []int{12}
+}
+
+func (m *ContractStorage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContractStorage.Unmarshal(m, b)
+}
+func (m *ContractStorage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContractStorage.Marshal(b, m, deterministic)
+}
+func (m *ContractStorage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContractStorage.Merge(m, src)
+}
+func (m *ContractStorage) XXX_Size() int {
+	return xxx_messageInfo_ContractStorage.Size(m)
+}
+func (m *ContractStorage) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContractStorage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContractStorage proto.InternalMessageInfo
+
+func (m *ContractStorage) GetContractName() string {
+	if m != nil {
+		return m.ContractName
+	}
+	return ""
+}
+
+func (m *ContractStorage) GetUsed() int64 {
+	if m != nil {
+		return m.Used
+	}
+	return 0
+}
+
+func (m *ContractStorage) GetQuota() int64 {
+	if m != nil {
+		return m.Quota
+	}
+	return 0
+}
+
+// ABI of a contract, solidity abi for evm contracts, typed abi for others
+type ContractAbi struct {
+	ContractName         string   `protobuf:"bytes,1,opt,name=contract_name,json=contractName,proto3" json:"contract_name,omitempty"`
+	ContractType         string   `protobuf:"bytes,2,opt,name=contract_type,json=contractType,proto3" json:"contract_type,omitempty"`
+	Abi                  []byte   `protobuf:"bytes,3,opt,name=abi,proto3" json:"abi,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ContractAbi) Reset()         { *m = ContractAbi{} }
+func (m *ContractAbi) String() string { return proto.CompactTextString(m) }
+func (*ContractAbi) ProtoMessage()    {}
+func (*ContractAbi) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d19debeba7dea55a, []int{13}
+}
+
+func (m *ContractAbi) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContractAbi.Unmarshal(m, b)
+}
+func (m *ContractAbi) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContractAbi.Marshal(b, m, deterministic)
+}
+func (m *ContractAbi) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContractAbi.Merge(m, src)
+}
+func (m *ContractAbi) XXX_Size() int {
+	return xxx_messageInfo_ContractAbi.Size(m)
+}
+func (m *ContractAbi) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContractAbi.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContractAbi proto.InternalMessageInfo
+
+func (m *ContractAbi) GetContractName() string {
+	if m != nil {
+		return m.ContractName
+	}
+	return ""
+}
+
+func (m *ContractAbi) GetContractType() string {
+	if m != nil {
+		return m.ContractType
+	}
+	return ""
+}
+
+func (m *ContractAbi) GetAbi() []byte {
+	if m != nil {
+		return m.Abi
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("protos.ResourceType", ResourceType_name, ResourceType_value)
 	proto.RegisterType((*GasPrice)(nil), "protos.GasPrice")
@@ -656,67 +1104,94 @@ func init() {
 	proto.RegisterType((*InvokeRequest)(nil), "protos.InvokeRequest")
 	proto.RegisterMapType((map[string][]byte)(nil), "protos.InvokeRequest.ArgsEntry")
 	proto.RegisterType((*InvokeResponse)(nil), "protos.InvokeResponse")
+	proto.RegisterType((*ContractTrace)(nil), "protos.ContractTrace")
+	proto.RegisterMapType((map[string][]byte)(nil), "protos.ContractTrace.ArgsEntry")
+	proto.RegisterType((*SyscallTrace)(nil), "protos.SyscallTrace")
 	proto.RegisterType((*ContractResponse)(nil), "protos.ContractResponse")
 	proto.RegisterType((*WasmCodeDesc)(nil), "protos.WasmCodeDesc")
 	proto.RegisterType((*ContractEvent)(nil), "protos.ContractEvent")
 	proto.RegisterType((*ContractStatData)(nil), "protos.ContractStatData")
 	proto.RegisterType((*ContractStatus)(nil), "protos.ContractStatus")
-}
-
-func init() { proto.RegisterFile("protos/contract.proto", fileDescriptor_919de52f3bf773d2) }
-
-var fileDescriptor_919de52f3bf773d2 = []byte{
-	// 817 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x55, 0x6d, 0x6f, 0x1b, 0x45,
-	0x10, 0xc6, 0xbe, 0x8b, 0x7d, 0x9e, 0x38, 0xa9, 0xb5, 0xb4, 0xe8, 0x08, 0xa0, 0x46, 0x07, 0x42,
-	0x56, 0x25, 0x62, 0x91, 0x4a, 0x14, 0xf1, 0x01, 0x89, 0x3a, 0x06, 0x45, 0x50, 0x52, 0x6d, 0x5a,
-	0x51, 0x10, 0x92, 0xb5, 0xbe, 0x5b, 0x2e, 0xa7, 0x78, 0x6f, 0x8f, 0x7d, 0xb1, 0x6c, 0x7e, 0x0e,
-	0x3f, 0x81, 0xcf, 0xfc, 0x2a, 0x7e, 0x01, 0xda, 0x37, 0xfb, 0xec, 0xf6, 0xdb, 0x3e, 0x33, 0xcf,
-	0xcc, 0x3d, 0x33, 0xb3, 0xb3, 0x07, 0x8f, 0x1a, 0xc1, 0x15, 0x97, 0x93, 0x9c, 0xd7, 0x4a, 0x90,
-	0x5c, 0x5d, 0x58, 0x8c, 0x7a, 0xce, 0x7c, 0xf6, 0xc9, 0x5a, 0x37, 0x54, 0xe4, 0x5c, 0xd0, 0x89,
-	0x27, 0x2e, 0x69, 0x51, 0x52, 0xe1, 0x68, 0xd9, 0x5f, 0x90, 0xfc, 0x40, 0xe4, 0x4b, 0x51, 0xe5,
-	0x14, 0x7d, 0x08, 0x49, 0xde, 0xe8, 0xb9, 0x20, 0x8a, 0xa6, 0x9d, 0xf3, 0xce, 0x38, 0xc2, 0xfd,
-	0xbc, 0xd1, 0x98, 0x28, 0xeb, 0x62, 0x94, 0x39, 0x57, 0xd7, 0xb9, 0x18, 0x65, 0xd6, 0xf5, 0x11,
-	0x0c, 0x8a, 0x4a, 0xde, 0x3b, 0x5f, 0x64, 0x7d, 0x89, 0x31, 0x04, 0xe7, 0xfa, 0x0f, 0x4a, 0x9d,
-	0x33, 0x76, 0x4e, 0x63, 0x30, 0xce, 0xec, 0x06, 0x4e, 0x30, 0x95, 0x5c, 0x8b, 0x9c, 0xfe, 0x54,
-	0xb1, 0x4a, 0xa1, 0x31, 0xc4, 0x6a, 0xd3, 0xb8, 0x8f, 0x9f, 0x5e, 0x3e, 0x74, 0x12, 0xe5, 0x45,
-	0x20, 0xbd, 0xda, 0x34, 0x14, 0x5b, 0x06, 0x7a, 0x08, 0x47, 0x4b, 0x13, 0xe2, 0xc5, 0x38, 0x90,
-	0xfd, 0xdb, 0x85, 0x93, 0xeb, 0x7a, 0xc5, 0xef, 0x29, 0xa6, 0x7f, 0x6a, 0x2a, 0x15, 0x7a, 0x0c,
-	0xc7, 0x8c, 0x17, 0x7a, 0x49, 0xe7, 0x35, 0x61, 0x2e, 0xf1, 0x00, 0x83, 0x33, 0xfd, 0x4c, 0x18,
-	0x45, 0x9f, 0xc2, 0x49, 0x68, 0x9c, 0xa3, 0x74, 0x2d, 0x65, 0x18, 0x8c, 0x96, 0x64, 0xb2, 0x50,
-	0x75, 0xc7, 0x0b, 0x47, 0x89, 0x7c, 0x16, 0x6b, 0xb2, 0x84, 0xa7, 0x10, 0x13, 0x51, 0xca, 0x34,
-	0x3e, 0x8f, 0xc6, 0xc7, 0x97, 0x8f, 0x83, 0xf0, 0x3d, 0x2d, 0x17, 0xdf, 0x89, 0x52, 0xce, 0x6a,
-	0x25, 0x36, 0xd8, 0x92, 0xd1, 0xb7, 0xf0, 0x40, 0xf8, 0xca, 0xe6, 0x56, 0xbf, 0x4c, 0x8f, 0x6c,
-	0xfc, 0xa3, 0xc3, 0xc2, 0x6d, 0x77, 0xf0, 0xa9, 0x68, 0x43, 0x89, 0x3e, 0x80, 0x1e, 0x61, 0x5c,
-	0xd7, 0x2a, 0xed, 0x59, 0x41, 0x1e, 0x9d, 0x3d, 0x83, 0xc1, 0xf6, 0x53, 0x68, 0x04, 0xd1, 0x3d,
-	0xdd, 0xf8, 0xc2, 0xcd, 0xd1, 0xb4, 0x6e, 0x45, 0x96, 0xda, 0x55, 0x3a, 0xc4, 0x0e, 0x7c, 0xd3,
-	0xfd, 0xba, 0x93, 0xfd, 0xd7, 0x85, 0xd3, 0x20, 0x59, 0x36, 0xbc, 0x96, 0x14, 0x3d, 0x81, 0x5e,
-	0x55, 0x37, 0x5a, 0xc9, 0xb4, 0x63, 0xa5, 0xa1, 0x20, 0xed, 0xd5, 0xfa, 0xda, 0xd8, 0x67, 0x6b,
-	0x85, 0x3d, 0x03, 0x7d, 0x01, 0x7d, 0xae, 0x95, 0x25, 0x77, 0x2d, 0xf9, 0xfd, 0x1d, 0xf9, 0xc6,
-	0x3a, 0x0c, 0x3b, 0x70, 0xd0, 0x19, 0x24, 0xc2, 0x7f, 0x26, 0x8d, 0xce, 0xa3, 0xf1, 0x10, 0x6f,
-	0xb1, 0xb9, 0x6e, 0x25, 0x91, 0x73, 0x2d, 0x69, 0xe1, 0x6f, 0x4d, 0xbf, 0x24, 0xf2, 0xb5, 0xa4,
-	0x05, 0xfa, 0xd2, 0x84, 0xd9, 0x86, 0xbe, 0xd5, 0xae, 0xbd, 0x76, 0xe3, 0x2d, 0x0d, 0x7d, 0x05,
-	0x83, 0x90, 0x59, 0xa6, 0x3d, 0x1b, 0x93, 0x86, 0x98, 0xa9, 0x9f, 0x73, 0xa8, 0x18, 0xef, 0xa8,
-	0x68, 0x02, 0xa0, 0xd5, 0x9a, 0x5f, 0xbb, 0x06, 0xf4, 0x6d, 0xe0, 0x83, 0x83, 0x06, 0xe0, 0x16,
-	0x05, 0x5d, 0xc2, 0xb1, 0x41, 0x37, 0xbe, 0x0b, 0x89, 0x8d, 0x18, 0x1d, 0x76, 0x01, 0xb7, 0x49,
-	0xd9, 0x1b, 0x18, 0x1d, 0x6a, 0x30, 0x93, 0x95, 0x8a, 0x28, 0x2d, 0xed, 0xdc, 0x8e, 0xb0, 0x47,
-	0x28, 0x85, 0x3e, 0xa3, 0x52, 0x92, 0x32, 0x5c, 0xd3, 0x00, 0x11, 0x82, 0x78, 0xc1, 0x8b, 0x8d,
-	0xbd, 0x9a, 0x43, 0x6c, 0xcf, 0xd9, 0xdf, 0x1d, 0x18, 0xfe, 0x42, 0x24, 0x9b, 0xf2, 0x82, 0x5e,
-	0x51, 0x99, 0x9b, 0x70, 0xa1, 0x6b, 0x55, 0x6d, 0x17, 0x21, 0x40, 0x33, 0x8b, 0x9c, 0xb3, 0xa6,
-	0x5a, 0x52, 0xe1, 0x33, 0x6f, 0xb1, 0x11, 0x53, 0x54, 0x25, 0x95, 0xca, 0x27, 0xf7, 0xc8, 0x2c,
-	0xc5, 0x8a, 0xcd, 0xb7, 0x61, 0xb1, 0x5b, 0x8a, 0x15, 0x9b, 0x86, 0xc0, 0xf6, 0x6a, 0xd9, 0xb5,
-	0x3e, 0xda, 0x5f, 0x2d, 0xb3, 0xce, 0xd9, 0x2d, 0x9c, 0x84, 0xf2, 0x67, 0x2b, 0x5a, 0x2b, 0x27,
-	0xc5, 0x19, 0xbc, 0xca, 0x2d, 0x36, 0x55, 0xb6, 0x76, 0xd4, 0x9e, 0xdf, 0x59, 0xf9, 0xef, 0xbb,
-	0x9e, 0xde, 0x2a, 0xa2, 0xae, 0x88, 0x22, 0x28, 0x83, 0x21, 0xc9, 0x73, 0xb3, 0x20, 0x53, 0xbb,
-	0x33, 0xee, 0x81, 0xdb, 0xb3, 0xa1, 0xcf, 0x76, 0x8a, 0x1d, 0xc9, 0xbd, 0x2e, 0xfb, 0xc6, 0xec,
-	0x9f, 0x0e, 0x9c, 0xb6, 0xd3, 0x6b, 0xf9, 0xf6, 0x2b, 0xd2, 0x79, 0xc7, 0x2b, 0x82, 0x20, 0x56,
-	0xeb, 0xaa, 0x08, 0xea, 0xcd, 0xd9, 0xd8, 0x0a, 0x2a, 0xf3, 0xa0, 0xde, 0x9c, 0xcd, 0x9b, 0x59,
-	0xc9, 0xf9, 0x82, 0xd4, 0xb5, 0xbf, 0xfd, 0x09, 0x4e, 0x2a, 0xf9, 0xdc, 0x62, 0xf4, 0x31, 0x0c,
-	0xcc, 0xc4, 0xa4, 0x22, 0xac, 0xb1, 0x0d, 0x8d, 0xf0, 0xce, 0xd0, 0x9e, 0x70, 0x6f, 0x6f, 0xc2,
-	0x4f, 0x9e, 0xc1, 0xb0, 0xfd, 0x8c, 0xa2, 0x3e, 0x44, 0xd3, 0x97, 0xaf, 0x47, 0xef, 0x21, 0x80,
-	0xde, 0x8b, 0xd9, 0x8b, 0x1b, 0xfc, 0xeb, 0xa8, 0x83, 0x12, 0x88, 0xaf, 0xae, 0x6f, 0x7f, 0x1c,
-	0x75, 0xcd, 0xe9, 0xcd, 0xf7, 0xb3, 0xd9, 0x28, 0x7a, 0x3e, 0xfe, 0xed, 0xf3, 0xb2, 0x52, 0x77,
-	0x7a, 0x71, 0x91, 0x73, 0x36, 0x71, 0x3f, 0x93, 0x3b, 0x52, 0xd5, 0x93, 0xc3, 0xff, 0xca, 0xc2,
-	0xfd, 0x71, 0x9e, 0xfe, 0x1f, 0x00, 0x00, 0xff, 0xff, 0x4e, 0xe4, 0xd3, 0xa2, 0x91, 0x06, 0x00,
-	0x00,
+	proto.RegisterType((*ContractVersion)(nil), "protos.ContractVersion")
+	proto.RegisterType((*ContractStorage)(nil), "protos.ContractStorage")
+	proto.RegisterType((*ContractAbi)(nil), "protos.ContractAbi")
+}
+
+func init() { proto.RegisterFile("contract.proto", fileDescriptor_d19debeba7dea55a) }
+
+var fileDescriptor_d19debeba7dea55a = []byte{
+	// 1164 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x56, 0xdd, 0x6e, 0x1b, 0x45,
+	0x14, 0xc6, 0xbb, 0x9b, 0xf5, 0xfa, 0xc4, 0x71, 0xac, 0xa1, 0x94, 0x25, 0x80, 0x1a, 0x2d, 0x08,
+	0xb9, 0x45, 0x4d, 0xa0, 0x95, 0x28, 0xea, 0x05, 0x52, 0xeb, 0x18, 0x14, 0x95, 0x92, 0x6a, 0x92,
+	0x42, 0x41, 0x48, 0x66, 0xbc, 0x3b, 0x75, 0x56, 0xf1, 0xee, 0xb8, 0x33, 0xb3, 0x96, 0xcd, 0x2b,
+	0xf0, 0x16, 0x48, 0xbc, 0x00, 0x37, 0xbc, 0x04, 0x57, 0x3c, 0x11, 0x9a, 0x3f, 0x7b, 0x37, 0x09,
+	0xa8, 0xe2, 0x86, 0x1b, 0x7b, 0xce, 0x39, 0xdf, 0xcc, 0x7c, 0xe7, 0x77, 0x07, 0x7a, 0x29, 0x2b,
+	0x25, 0x27, 0xa9, 0x3c, 0x98, 0x73, 0x26, 0x19, 0x0a, 0xf5, 0x9f, 0xd8, 0x7b, 0x7f, 0x59, 0xcd,
+	0x29, 0x4f, 0x19, 0xa7, 0x87, 0x46, 0x73, 0x38, 0xa3, 0xd9, 0x94, 0x72, 0x03, 0x4b, 0x7e, 0x86,
+	0xe8, 0x2b, 0x22, 0x9e, 0xf1, 0x3c, 0xa5, 0xe8, 0x1d, 0x88, 0xd2, 0x79, 0x35, 0xe6, 0x44, 0xd2,
+	0xb8, 0xb5, 0xdf, 0x1a, 0xf8, 0xb8, 0x9d, 0xce, 0x2b, 0x4c, 0xa4, 0x36, 0x15, 0xb4, 0x30, 0x26,
+	0xcf, 0x98, 0x0a, 0x5a, 0x68, 0xd3, 0xbb, 0xd0, 0xc9, 0x72, 0x71, 0x61, 0x6c, 0xbe, 0xb6, 0x45,
+	0x4a, 0xe1, 0x8c, 0xcb, 0x97, 0x94, 0x1a, 0x63, 0x60, 0x8c, 0x4a, 0xa1, 0x8c, 0xc9, 0x09, 0xec,
+	0x60, 0x2a, 0x58, 0xc5, 0x53, 0xfa, 0x75, 0x5e, 0xe4, 0x12, 0x0d, 0x20, 0x90, 0xab, 0xb9, 0xb9,
+	0xbc, 0x77, 0xef, 0x86, 0xa1, 0x28, 0x0e, 0x1c, 0xe8, 0x6c, 0x35, 0xa7, 0x58, 0x23, 0xd0, 0x0d,
+	0xd8, 0x9a, 0xa9, 0x2d, 0x96, 0x8c, 0x11, 0x92, 0xbf, 0x3c, 0xd8, 0x39, 0x2e, 0x17, 0xec, 0x82,
+	0x62, 0xfa, 0xaa, 0xa2, 0x42, 0xa2, 0x5b, 0xb0, 0x5d, 0xb0, 0xac, 0x9a, 0xd1, 0x71, 0x49, 0x0a,
+	0x73, 0x70, 0x07, 0x83, 0x51, 0x7d, 0x43, 0x0a, 0x8a, 0x3e, 0x80, 0x1d, 0x17, 0x38, 0x03, 0xf1,
+	0x34, 0xa4, 0xeb, 0x94, 0x1a, 0xa4, 0x4e, 0xa1, 0xf2, 0x9c, 0x65, 0x06, 0xe2, 0xdb, 0x53, 0xb4,
+	0x4a, 0x03, 0xee, 0x43, 0x40, 0xf8, 0x54, 0xc4, 0xc1, 0xbe, 0x3f, 0xd8, 0xbe, 0x77, 0xcb, 0x11,
+	0x6f, 0x70, 0x39, 0x78, 0xc4, 0xa7, 0x62, 0x54, 0x4a, 0xbe, 0xc2, 0x1a, 0x8c, 0xbe, 0x80, 0x5d,
+	0x6e, 0x3d, 0x1b, 0x6b, 0xfe, 0x22, 0xde, 0xd2, 0xfb, 0xdf, 0xba, 0xec, 0xb8, 0x8e, 0x0e, 0xee,
+	0xf1, 0xba, 0x28, 0xd0, 0x4d, 0x08, 0x49, 0xc1, 0xaa, 0x52, 0xc6, 0xa1, 0x26, 0x64, 0x25, 0x15,
+	0x1b, 0x45, 0x9d, 0xc6, 0xed, 0xfd, 0xd6, 0x20, 0xc2, 0x46, 0xd8, 0x7b, 0x00, 0x9d, 0x35, 0x01,
+	0xd4, 0x07, 0xff, 0x82, 0xae, 0x6c, 0x38, 0xd4, 0x52, 0x6d, 0x5a, 0x90, 0x59, 0x65, 0xfc, 0xef,
+	0x62, 0x23, 0x3c, 0xf4, 0x3e, 0x6f, 0x25, 0x7f, 0xfa, 0xd0, 0x73, 0x8e, 0x88, 0x39, 0x2b, 0x05,
+	0x45, 0x77, 0x20, 0xcc, 0xcb, 0x79, 0x25, 0x45, 0xdc, 0xd2, 0x84, 0x91, 0x23, 0x7c, 0xb6, 0x3c,
+	0x56, 0xfa, 0xd1, 0x52, 0x62, 0x8b, 0x40, 0x77, 0xa1, 0xcd, 0x2a, 0xa9, 0xc1, 0x9e, 0x06, 0xbf,
+	0xb9, 0x01, 0x9f, 0x54, 0xd2, 0xa2, 0x1d, 0x06, 0xed, 0x41, 0xc4, 0xed, 0x35, 0xb1, 0xbf, 0xef,
+	0x0f, 0xba, 0x78, 0x2d, 0xab, 0x22, 0x9c, 0x12, 0x31, 0xae, 0x04, 0xcd, 0x6c, 0x2d, 0xb5, 0xa7,
+	0x44, 0x3c, 0x17, 0x34, 0x43, 0x9f, 0xaa, 0x6d, 0x3a, 0xcc, 0x57, 0x82, 0xd8, 0x48, 0x02, 0x5e,
+	0xc3, 0xd0, 0x67, 0xd0, 0x71, 0x27, 0x8b, 0x38, 0xd4, 0x7b, 0x62, 0xb7, 0x67, 0x68, 0xb3, 0xef,
+	0x3c, 0xc6, 0x1b, 0x28, 0x3a, 0x04, 0xa8, 0xe4, 0x92, 0x1d, 0x9b, 0x00, 0xb4, 0xf5, 0xc6, 0xdd,
+	0x4b, 0x01, 0xc0, 0x35, 0x08, 0xba, 0x07, 0xdb, 0x4a, 0x3a, 0xb1, 0x51, 0x88, 0xf4, 0x8e, 0xfe,
+	0xe5, 0x28, 0xe0, 0x3a, 0x08, 0xdd, 0x85, 0x50, 0xa7, 0x4d, 0xc4, 0x9d, 0xa6, 0x37, 0x8e, 0xd9,
+	0x99, 0xb2, 0x62, 0x0b, 0x42, 0xb7, 0xa1, 0x6f, 0x56, 0x63, 0xc9, 0xab, 0x32, 0x25, 0x92, 0x66,
+	0x31, 0xe8, 0xec, 0xef, 0x1a, 0xfd, 0x99, 0x53, 0x27, 0x7f, 0xf8, 0xb0, 0xd3, 0x38, 0xe4, 0x7f,
+	0xee, 0x91, 0x06, 0x97, 0x2b, 0x3d, 0xf2, 0x09, 0x44, 0x62, 0x25, 0x52, 0x32, 0x9b, 0xb9, 0xbc,
+	0xae, 0xa7, 0xc2, 0xa9, 0xd1, 0x9b, 0x40, 0xac, 0x51, 0xe8, 0x21, 0xec, 0xac, 0xbb, 0x4a, 0x57,
+	0x4a, 0xf8, 0x6f, 0x3d, 0xd5, 0x75, 0x58, 0x5d, 0x45, 0x37, 0x21, 0x14, 0x92, 0xc8, 0x4a, 0xe8,
+	0xd6, 0xd9, 0xc2, 0x56, 0x42, 0x31, 0xb4, 0x0b, 0x2a, 0x04, 0x99, 0xd2, 0x38, 0xd2, 0x7e, 0x39,
+	0x51, 0xb5, 0x0d, 0xe5, 0x9c, 0xf1, 0xb8, 0xa3, 0xf5, 0x46, 0x50, 0x53, 0x8f, 0x2e, 0x8a, 0xb1,
+	0xa1, 0x0d, 0xfb, 0xfe, 0xa0, 0x83, 0x23, 0xba, 0x28, 0x86, 0x4a, 0xfe, 0xef, 0x8d, 0xf8, 0x9b,
+	0x07, 0xdd, 0xba, 0xd3, 0x08, 0x41, 0x50, 0xcb, 0x98, 0x5e, 0x2b, 0x17, 0x26, 0x55, 0x7a, 0x41,
+	0xa5, 0x4d, 0x92, 0x95, 0xdc, 0x45, 0xbe, 0x3e, 0xb4, 0x79, 0x51, 0x50, 0xbb, 0x08, 0xbd, 0x0d,
+	0x6d, 0x5a, 0x66, 0x63, 0x85, 0xdd, 0xd2, 0xfa, 0x90, 0x96, 0xd9, 0x13, 0xba, 0x52, 0x97, 0xbd,
+	0xe4, 0xac, 0xb0, 0xb3, 0x46, 0xaf, 0x51, 0x0f, 0x3c, 0xc9, 0x74, 0xac, 0x3a, 0xd8, 0x93, 0xac,
+	0x36, 0x91, 0xa2, 0xc6, 0x44, 0xfa, 0x18, 0xb6, 0xe8, 0x82, 0x96, 0x52, 0x47, 0xe9, 0x9a, 0x62,
+	0x1e, 0x29, 0x23, 0x36, 0x98, 0x4d, 0x48, 0xa1, 0x1e, 0xd2, 0xdb, 0x10, 0x28, 0xc7, 0xe3, 0xed,
+	0xeb, 0x4f, 0x30, 0x55, 0xa0, 0x21, 0xc9, 0x0b, 0xe8, 0x5f, 0xee, 0xdf, 0x5a, 0x66, 0x5b, 0xff,
+	0x94, 0x59, 0xaf, 0x99, 0x59, 0x04, 0xc1, 0x84, 0x65, 0x2e, 0x62, 0x7a, 0x9d, 0xfc, 0xda, 0x82,
+	0xee, 0x77, 0x44, 0x14, 0x43, 0x96, 0xd1, 0x23, 0x2a, 0x52, 0xb5, 0x9d, 0x57, 0xa5, 0xcc, 0xd7,
+	0x49, 0x70, 0xa2, 0x9a, 0x63, 0x29, 0x2b, 0xe6, 0xf9, 0x8c, 0x72, 0x7b, 0xf2, 0x5a, 0x56, 0x64,
+	0xb2, 0x7c, 0x4a, 0x85, 0xb4, 0x87, 0x5b, 0x49, 0xb5, 0x90, 0xaa, 0x1a, 0xb7, 0x2d, 0x30, 0x2d,
+	0xb4, 0x28, 0x86, 0x6e, 0x63, 0xbd, 0x11, 0xf5, 0x87, 0x72, 0xab, 0xd9, 0x88, 0xea, 0x03, 0x99,
+	0x9c, 0xc2, 0x4e, 0x23, 0xae, 0x86, 0x8a, 0x51, 0x58, 0x96, 0x6b, 0x79, 0x5d, 0x42, 0x5e, 0xad,
+	0x84, 0xae, 0xf3, 0xfc, 0xc7, 0x4d, 0x4c, 0x4f, 0x25, 0x91, 0x47, 0x44, 0x12, 0x94, 0x40, 0x97,
+	0xa4, 0xa9, 0x4a, 0xf0, 0x50, 0xfd, 0xd8, 0x27, 0x43, 0x43, 0x87, 0x3e, 0xdc, 0x30, 0x36, 0x20,
+	0xf3, 0xbd, 0x6e, 0x2a, 0x93, 0xdf, 0x5b, 0xd0, 0xab, 0x1f, 0x5f, 0x89, 0xab, 0x33, 0xa7, 0x75,
+	0xcd, 0xcc, 0x41, 0x10, 0xc8, 0x65, 0x9e, 0x39, 0xf6, 0x6a, 0xad, 0x74, 0x19, 0x15, 0xa9, 0x63,
+	0xaf, 0xd6, 0xaa, 0x1f, 0x73, 0x31, 0x9e, 0x90, 0xb2, 0xb4, 0x5f, 0x8e, 0x08, 0x47, 0xb9, 0x78,
+	0xac, 0x65, 0xf4, 0x1e, 0x74, 0x54, 0xc6, 0x84, 0x24, 0xc5, 0x5c, 0x07, 0xd4, 0xc7, 0x1b, 0x45,
+	0x3d, 0xc3, 0x61, 0x23, 0xc3, 0xc9, 0x2f, 0x2d, 0xd8, 0x75, 0xa4, 0xbf, 0xa5, 0x5c, 0xe4, 0xac,
+	0x54, 0xe8, 0x85, 0x59, 0xba, 0x07, 0x94, 0x15, 0x6b, 0x39, 0xf7, 0x1a, 0x39, 0xdf, 0x83, 0xa8,
+	0xc8, 0xa7, 0x5c, 0x4f, 0x6c, 0xdf, 0x30, 0x73, 0xf2, 0xda, 0xbd, 0xa0, 0xe6, 0xde, 0x4d, 0x08,
+	0xcf, 0x69, 0x3e, 0x3d, 0x97, 0x96, 0xaa, 0x95, 0x92, 0x9f, 0x36, 0x64, 0x4e, 0x25, 0xe3, 0xaa,
+	0x82, 0x5f, 0x37, 0x84, 0x7a, 0x4a, 0x9a, 0xbc, 0xe8, 0xb5, 0xea, 0xc0, 0x57, 0x15, 0x93, 0xc4,
+	0xbe, 0xe6, 0x8c, 0x90, 0xe4, 0xb0, 0xed, 0x6e, 0x78, 0x34, 0xc9, 0x5f, 0xef, 0xf4, 0x2b, 0x05,
+	0xeb, 0x5d, 0x2d, 0x58, 0x35, 0x9a, 0xc8, 0x24, 0x77, 0xa3, 0x89, 0x4c, 0xf2, 0x3b, 0x0f, 0xa0,
+	0x5b, 0x7f, 0xf3, 0xa1, 0x36, 0xf8, 0xc3, 0x67, 0xcf, 0xfb, 0x6f, 0x20, 0x80, 0xf0, 0xe9, 0xe8,
+	0xe9, 0x09, 0xfe, 0xbe, 0xdf, 0x42, 0x11, 0x04, 0x47, 0xc7, 0xa7, 0x4f, 0xfa, 0x9e, 0x5a, 0xbd,
+	0xf8, 0x72, 0x34, 0xea, 0xfb, 0x8f, 0x07, 0x3f, 0x7c, 0x34, 0xcd, 0xe5, 0x79, 0x35, 0x39, 0x48,
+	0x59, 0x71, 0x68, 0x5e, 0xbe, 0xe7, 0x24, 0x2f, 0x0f, 0x2f, 0x3f, 0x82, 0x27, 0xe6, 0x79, 0x7c,
+	0xff, 0xef, 0x01, 0x00, 0x6a, 0xe6, 0xa2, 0x91, 0x37, 0x0b, 0x00, 0x00,
 }
@@ -1,5 +1,5 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
-// source: protos/network.proto
+// source: network.proto
 
 package protos
 
@@ -67,6 +67,24 @@ const (
 	XuperMessage_GET_BLOCKS_RES    XuperMessage_MessageType = 23
 	XuperMessage_GET_PEER_INFO     XuperMessage_MessageType = 24
 	XuperMessage_GET_PEER_INFO_RES XuperMessage_MessageType = 25
+	// broadcast new tx ids to other node
+	XuperMessage_NEW_TXIDS XuperMessage_MessageType = 26
+	// 发送方通过GET_TXS消息获取NEW_TXIDS公告中本地未知的交易,
+	// 接受方通过BATCHPOSTTX消息发送对应的交易
+	XuperMessage_GET_TXS XuperMessage_MessageType = 27
+	// broadcast compact block(header and short tx ids) to other node
+	XuperMessage_COMPACT_BLOCK XuperMessage_MessageType = 28
+	// 消息对(GET_BLOCK_TXS <-> GET_BLOCK_TXS_RES),
+	// 发送方重建compact block时获取本地缺失的交易
+	XuperMessage_GET_BLOCK_TXS     XuperMessage_MessageType = 29
+	XuperMessage_GET_BLOCK_TXS_RES XuperMessage_MessageType = 30
+	// 消息对(GET_SNAPSHOT_MANIFEST <-> GET_SNAPSHOT_MANIFEST_RES),
+	// 新节点获取其他节点可用的状态快照描述
+	XuperMessage_GET_SNAPSHOT_MANIFEST     XuperMessage_MessageType = 31
+	XuperMessage_GET_SNAPSHOT_MANIFEST_RES XuperMessage_MessageType = 32
+	// 消息对(GET_SNAPSHOT_CHUNK <-> GET_SNAPSHOT_CHUNK_RES), 下载状态快照分片
+	XuperMessage_GET_SNAPSHOT_CHUNK     XuperMessage_MessageType = 33
+	XuperMessage_GET_SNAPSHOT_CHUNK_RES XuperMessage_MessageType = 34
 )
 
 var XuperMessage_MessageType_name = map[int32]string{
@@ -96,6 +114,15 @@ var XuperMessage_MessageType_name = map[int32]string{
 	23: "GET_BLOCKS_RES",
 	24: "GET_PEER_INFO",
 	25: "GET_PEER_INFO_RES",
+	26: "NEW_TXIDS",
+	27: "GET_TXS",
+	28: "COMPACT_BLOCK",
+	29: "GET_BLOCK_TXS",
+	30: "GET_BLOCK_TXS_RES",
+	31: "GET_SNAPSHOT_MANIFEST",
+	32: "GET_SNAPSHOT_MANIFEST_RES",
+	33: "GET_SNAPSHOT_CHUNK",
+	34: "GET_SNAPSHOT_CHUNK_RES",
 }
 
 var XuperMessage_MessageType_value = map[string]int32{
@@ -125,6 +152,15 @@ var XuperMessage_MessageType_value = map[string]int32{
 	"GET_BLOCKS_RES":               23,
 	"GET_PEER_INFO":                24,
 	"GET_PEER_INFO_RES":            25,
+	"NEW_TXIDS":                    26,
+	"GET_TXS":                      27,
+	"COMPACT_BLOCK":                28,
+	"GET_BLOCK_TXS":                29,
+	"GET_BLOCK_TXS_RES":            30,
+	"GET_SNAPSHOT_MANIFEST":        31,
+	"GET_SNAPSHOT_MANIFEST_RES":    32,
+	"GET_SNAPSHOT_CHUNK":           33,
+	"GET_SNAPSHOT_CHUNK_RES":       34,
 }
 
 func (x XuperMessage_MessageType) String() string {
@@ -132,7 +168,7 @@ func (x XuperMessage_MessageType) String() string {
 }
 
 func (XuperMessage_MessageType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_9898f5d59e04eeea, []int{0, 0}
+	return fileDescriptor_8571034d60397816, []int{0, 0}
 }
 
 type XuperMessage_ErrorType int32
@@ -190,7 +226,7 @@ func (x XuperMessage_ErrorType) String() string {
 }
 
 func (XuperMessage_ErrorType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_9898f5d59e04eeea, []int{0, 1}
+	return fileDescriptor_8571034d60397816, []int{0, 1}
 }
 
 // XuperMessage is the message of Xuper p2p server
@@ -206,7 +242,7 @@ func (m *XuperMessage) Reset()         { *m = XuperMessage{} }
 func (m *XuperMessage) String() string { return proto.CompactTextString(m) }
 func (*XuperMessage) ProtoMessage()    {}
 func (*XuperMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9898f5d59e04eeea, []int{0}
+	return fileDescriptor_8571034d60397816, []int{0}
 }
 
 func (m *XuperMessage) XXX_Unmarshal(b []byte) error {
@@ -260,7 +296,7 @@ func (m *XuperMessage_MessageHeader) Reset()         { *m = XuperMessage_Message
 func (m *XuperMessage_MessageHeader) String() string { return proto.CompactTextString(m) }
 func (*XuperMessage_MessageHeader) ProtoMessage()    {}
 func (*XuperMessage_MessageHeader) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9898f5d59e04eeea, []int{0, 0}
+	return fileDescriptor_8571034d60397816, []int{0, 0}
 }
 
 func (m *XuperMessage_MessageHeader) XXX_Unmarshal(b []byte) error {
@@ -350,7 +386,7 @@ func (m *XuperMessage_MessageData) Reset()         { *m = XuperMessage_MessageDa
 func (m *XuperMessage_MessageData) String() string { return proto.CompactTextString(m) }
 func (*XuperMessage_MessageData) ProtoMessage()    {}
 func (*XuperMessage_MessageData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9898f5d59e04eeea, []int{0, 1}
+	return fileDescriptor_8571034d60397816, []int{0, 1}
 }
 
 func (m *XuperMessage_MessageData) XXX_Unmarshal(b []byte) error {
@@ -379,20 +415,28 @@ func (m *XuperMessage_MessageData) GetMsgInfo() []byte {
 }
 
 type PeerInfo struct {
-	Id                   string      `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Address              string      `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
-	Account              string      `protobuf:"bytes,3,opt,name=account,proto3" json:"account,omitempty"`
-	Peer                 []*PeerInfo `protobuf:"bytes,4,rep,name=peer,proto3" json:"peer,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
+	Id      string      `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Address string      `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Account string      `protobuf:"bytes,3,opt,name=account,proto3" json:"account,omitempty"`
+	Peer    []*PeerInfo `protobuf:"bytes,4,rep,name=peer,proto3" json:"peer,omitempty"`
+	// chains served by the peer
+	Chains []string `protobuf:"bytes,5,rep,name=chains,proto3" json:"chains,omitempty"`
+	// public key of account and signature of id, address, account, chains and timestamp,
+	// binding the account to the peer
+	PublicKey []byte `protobuf:"bytes,6,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Signature []byte `protobuf:"bytes,7,opt,name=signature,proto3" json:"signature,omitempty"`
+	// unix time when the peer info is signed, stale peer info is rejected
+	Timestamp            int64    `protobuf:"varint,8,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *PeerInfo) Reset()         { *m = PeerInfo{} }
 func (m *PeerInfo) String() string { return proto.CompactTextString(m) }
 func (*PeerInfo) ProtoMessage()    {}
 func (*PeerInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9898f5d59e04eeea, []int{1}
+	return fileDescriptor_8571034d60397816, []int{1}
 }
 
 func (m *PeerInfo) XXX_Unmarshal(b []byte) error {
@@ -441,6 +485,34 @@ func (m *PeerInfo) GetPeer() []*PeerInfo {
 	return nil
 }
 
+func (m *PeerInfo) GetChains() []string {
+	if m != nil {
+		return m.Chains
+	}
+	return nil
+}
+
+func (m *PeerInfo) GetPublicKey() []byte {
+	if m != nil {
+		return m.PublicKey
+	}
+	return nil
+}
+
+func (m *PeerInfo) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *PeerInfo) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("protos.XuperMessage_MessageType", XuperMessage_MessageType_name, XuperMessage_MessageType_value)
 	proto.RegisterEnum("protos.XuperMessage_ErrorType", XuperMessage_ErrorType_name, XuperMessage_ErrorType_value)
@@ -450,62 +522,71 @@ func init() {
 	proto.RegisterType((*PeerInfo)(nil), "protos.PeerInfo")
 }
 
-func init() { proto.RegisterFile("protos/network.proto", fileDescriptor_9898f5d59e04eeea) }
-
-var fileDescriptor_9898f5d59e04eeea = []byte{
-	// 831 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x55, 0x5f, 0x73, 0xda, 0x46,
-	0x10, 0xb7, 0xb0, 0xcc, 0x9f, 0xe5, 0x8f, 0xcf, 0x6b, 0xe2, 0xa8, 0x6e, 0x26, 0x65, 0x98, 0x4e,
-	0xca, 0x93, 0xdd, 0xa1, 0x7d, 0xea, 0xf4, 0x05, 0xc4, 0xd9, 0x68, 0x1c, 0x4e, 0x9a, 0x3b, 0x11,
-	0x3b, 0x7d, 0xd1, 0xc8, 0x70, 0xb1, 0x99, 0x04, 0xa4, 0x11, 0x90, 0x36, 0x5f, 0xa8, 0x2f, 0xfd,
-	0x08, 0x9d, 0xe9, 0x67, 0xeb, 0xdc, 0x49, 0xc2, 0xd8, 0x26, 0x79, 0x42, 0xfb, 0xfb, 0x73, 0xbb,
-	0xb7, 0x5a, 0x2d, 0xd0, 0x8c, 0x93, 0x68, 0x15, 0x2d, 0xcf, 0x17, 0x72, 0xf5, 0x67, 0x94, 0x7c,
-	0x3c, 0xd3, 0x21, 0x16, 0x53, 0xb4, 0xfd, 0x1f, 0x40, 0xed, 0x66, 0x1d, 0xcb, 0x64, 0x24, 0x97,
-	0xcb, 0xf0, 0x4e, 0xe2, 0x6f, 0x50, 0x1c, 0xca, 0x70, 0x2a, 0x13, 0xcb, 0x68, 0x19, 0x9d, 0x6a,
-	0xb7, 0x9d, 0x1a, 0x96, 0x67, 0xdb, 0xaa, 0xb3, 0xec, 0x37, 0x55, 0xf2, 0xcc, 0x81, 0xbf, 0x82,
-	0x39, 0x08, 0x57, 0xa1, 0x55, 0xd0, 0xce, 0xd6, 0xb7, 0x9c, 0x4a, 0xc7, 0xb5, 0xfa, 0xf4, 0x9f,
-	0x02, 0xd4, 0x1f, 0x9d, 0x87, 0x16, 0x94, 0x3e, 0xcb, 0x64, 0x39, 0x8b, 0x16, 0xba, 0x88, 0x0a,
-	0xcf, 0x43, 0x6c, 0xc2, 0xc1, 0xa7, 0xe8, 0x6e, 0x36, 0xd5, 0x29, 0x2a, 0x3c, 0x0d, 0x10, 0xc1,
-	0xfc, 0x90, 0x44, 0x73, 0x6b, 0x5f, 0x83, 0xfa, 0x19, 0x4f, 0xa0, 0x78, 0x3b, 0x59, 0x84, 0x73,
-	0x69, 0x99, 0x1a, 0xcd, 0x22, 0x55, 0xe3, 0xea, 0x4b, 0x2c, 0xad, 0x83, 0x96, 0xd1, 0x69, 0x7c,
-	0xbb, 0x46, 0xff, 0x4b, 0x2c, 0xb9, 0x56, 0x63, 0x1b, 0x6a, 0xd3, 0x70, 0x15, 0xda, 0xf7, 0x72,
-	0xf2, 0x51, 0xac, 0xe7, 0x56, 0xb1, 0x65, 0x74, 0xea, 0xfc, 0x11, 0x86, 0xbf, 0x43, 0x45, 0x26,
-	0x49, 0x94, 0x28, 0x9b, 0x55, 0xd2, 0xc7, 0xbf, 0xde, 0x79, 0x3c, 0xcd, 0x55, 0xfc, 0xc1, 0x80,
-	0x6f, 0xa0, 0x21, 0x17, 0xe1, 0xed, 0x27, 0x69, 0x47, 0xf3, 0x38, 0x91, 0xcb, 0xa5, 0x55, 0x6e,
-	0x19, 0x9d, 0x32, 0x7f, 0x82, 0x9e, 0xfe, 0x04, 0xd5, 0xad, 0x16, 0xaa, 0x56, 0xcd, 0x97, 0x77,
-	0xce, 0xe2, 0x43, 0xa4, 0x6f, 0x5f, 0xe3, 0x79, 0xd8, 0xfe, 0xd7, 0xdc, 0x28, 0x75, 0x82, 0x3a,
-	0x54, 0x04, 0x65, 0x83, 0xfe, 0x5b, 0xd7, 0xbe, 0x22, 0x7b, 0x08, 0x50, 0xf4, 0x5c, 0xe1, 0xfb,
-	0x37, 0xc4, 0xc0, 0x43, 0xa8, 0xf6, 0x7b, 0xbe, 0x3d, 0xcc, 0x80, 0x82, 0xd2, 0x5e, 0x52, 0x3f,
-	0x48, 0xb5, 0xfb, 0x58, 0x06, 0xd3, 0x73, 0xd8, 0x25, 0x31, 0xd1, 0x82, 0xe6, 0x86, 0xb0, 0x87,
-	0x3d, 0x87, 0x09, 0xbf, 0xe7, 0x8f, 0x05, 0x39, 0xc0, 0x23, 0xa8, 0x6f, 0x98, 0x80, 0x53, 0x41,
-	0x8a, 0xf8, 0x0a, 0xac, 0x5d, 0x62, 0xcd, 0x96, 0x14, 0x6b, 0xbb, 0xec, 0xc2, 0xe1, 0xa3, 0xe7,
-	0xc7, 0x95, 0xb1, 0x05, 0xaf, 0xbe, 0xc6, 0x6a, 0x7f, 0x45, 0x25, 0x1c, 0x89, 0xcb, 0xc0, 0x7f,
-	0xef, 0xd1, 0x80, 0xb9, 0x8c, 0x12, 0x40, 0x02, 0x35, 0x95, 0x90, 0x7b, 0x76, 0xe0, 0xb9, 0xdc,
-	0x27, 0x55, 0x6c, 0x02, 0xd9, 0x46, 0xb4, 0xb5, 0x86, 0x27, 0x80, 0x0a, 0xed, 0x8d, 0xfd, 0x21,
-	0x65, 0xbe, 0x63, 0xf7, 0x7c, 0xc7, 0x65, 0xa4, 0x8e, 0xa7, 0x70, 0xf2, 0x1c, 0xd7, 0x9e, 0x86,
-	0x2e, 0x57, 0xd5, 0x40, 0x07, 0x41, 0xff, 0xc2, 0x0f, 0x18, 0xbd, 0x0e, 0xde, 0x39, 0xf4, 0x3a,
-	0x18, 0x89, 0x4b, 0x72, 0xa8, 0xcb, 0x7d, 0xc2, 0x7a, 0xdc, 0xf5, 0x5c, 0xd1, 0x7b, 0xab, 0x15,
-	0x44, 0x75, 0x6e, 0x5b, 0xf1, 0xce, 0xf5, 0xa9, 0x66, 0x8e, 0x54, 0xf7, 0x95, 0x5e, 0x5f, 0xd3,
-	0x19, 0x10, 0xc4, 0x1a, 0x94, 0x15, 0xc0, 0xdc, 0x01, 0x25, 0xc7, 0xf9, 0xa5, 0x32, 0x5a, 0x90,
-	0x66, 0x7e, 0xa9, 0x1c, 0xd1, 0x05, 0xbe, 0xc0, 0x06, 0xc0, 0x06, 0x15, 0xe4, 0x04, 0x11, 0x1a,
-	0x0f, 0xb1, 0xd6, 0xbc, 0xcc, 0x5f, 0x92, 0x47, 0x29, 0x0f, 0x1c, 0x76, 0xe1, 0x12, 0x0b, 0x5f,
-	0xc0, 0xd1, 0x23, 0x48, 0x2b, 0xbf, 0x6b, 0xff, 0x5d, 0x80, 0xca, 0x66, 0x4e, 0xb1, 0x0a, 0x25,
-	0x31, 0xb6, 0x6d, 0x2a, 0x04, 0xd9, 0x53, 0xd3, 0xa0, 0xfb, 0x6d, 0xa8, 0xd2, 0xc6, 0xec, 0x8a,
-	0xb9, 0xd7, 0x01, 0xe5, 0xdc, 0xe5, 0xa4, 0x80, 0xc7, 0x70, 0x68, 0x0f, 0xa9, 0x7d, 0x15, 0x88,
-	0xf1, 0x28, 0x03, 0xf7, 0x55, 0xeb, 0xc6, 0x6c, 0xd4, 0xe3, 0x62, 0x98, 0x76, 0x23, 0xe8, 0xbb,
-	0x83, 0xf7, 0x19, 0x6b, 0xaa, 0x3a, 0x6d, 0x97, 0x31, 0x6a, 0xab, 0xb7, 0x73, 0x31, 0x16, 0x94,
-	0x1c, 0x3c, 0x1f, 0xb3, 0x4c, 0x5d, 0xc4, 0x97, 0x70, 0xbc, 0x85, 0x32, 0xd7, 0xa7, 0x37, 0x8e,
-	0xf0, 0x49, 0x49, 0x65, 0x7e, 0x98, 0xbf, 0x54, 0x5d, 0xc6, 0x36, 0xbc, 0xfe, 0xea, 0x14, 0xa5,
-	0x9a, 0x4a, 0x3e, 0xa5, 0x4f, 0x5e, 0x7a, 0xca, 0x02, 0xfe, 0x00, 0xdf, 0xef, 0x60, 0x99, 0xeb,
-	0x07, 0x5e, 0x4f, 0x08, 0x52, 0x6d, 0xaf, 0xa0, 0xec, 0x49, 0x99, 0xa8, 0x4f, 0x0e, 0x1b, 0x50,
-	0x98, 0x4d, 0xb3, 0x95, 0x55, 0x98, 0x4d, 0xd5, 0xc7, 0x19, 0x4e, 0xa7, 0xfa, 0x63, 0x4e, 0xf7,
-	0x55, 0x1e, 0x6a, 0x66, 0x32, 0x89, 0xd6, 0x8b, 0x55, 0xb6, 0xb4, 0xf2, 0x10, 0x7f, 0x04, 0x33,
-	0x96, 0x32, 0xb1, 0xcc, 0xd6, 0x7e, 0xa7, 0xda, 0x25, 0xf9, 0x02, 0xc9, 0x73, 0x70, 0xcd, 0x76,
-	0x3d, 0x80, 0xb8, 0x1b, 0x0b, 0x99, 0x7c, 0x9e, 0x4d, 0x24, 0xf6, 0xa1, 0x21, 0xe4, 0x62, 0xea,
-	0x75, 0xe3, 0x7c, 0x8b, 0x37, 0x77, 0x2d, 0x9e, 0xd3, 0x9d, 0x68, 0x7b, 0xaf, 0x63, 0xfc, 0x6c,
-	0xf4, 0x3b, 0x7f, 0xbc, 0xb9, 0x9b, 0xad, 0xee, 0xd7, 0xb7, 0x67, 0x93, 0x68, 0x7e, 0xfe, 0x97,
-	0x12, 0x4c, 0xee, 0xc3, 0xd9, 0x22, 0x7b, 0x8c, 0x12, 0x79, 0x9e, 0x9a, 0x6f, 0xd3, 0xbf, 0x8e,
-	0x5f, 0xfe, 0x0f, 0x00, 0x00, 0xff, 0xff, 0x54, 0x0a, 0x31, 0x74, 0x59, 0x06, 0x00, 0x00,
+func init() { proto.RegisterFile("network.proto", fileDescriptor_8571034d60397816) }
+
+var fileDescriptor_8571034d60397816 = []byte{
+	// 975 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x55, 0x4b, 0x73, 0xdb, 0x36,
+	0x17, 0xb5, 0x1e, 0x96, 0xa5, 0xab, 0x87, 0x61, 0xf8, 0x11, 0xda, 0xb1, 0x1d, 0x7d, 0x9a, 0x6f,
+	0x52, 0xad, 0x9c, 0x8e, 0xdb, 0x55, 0xa7, 0x1b, 0x8a, 0x82, 0x2d, 0x8e, 0x23, 0x90, 0x03, 0x40,
+	0xb1, 0xd3, 0x0d, 0x87, 0x96, 0x10, 0x5b, 0x63, 0x4b, 0xe4, 0x90, 0x54, 0x5a, 0x6f, 0x3b, 0xd3,
+	0xbf, 0xd2, 0x4d, 0x7f, 0x58, 0xff, 0x46, 0x07, 0x20, 0x29, 0xbf, 0x94, 0xac, 0xa4, 0x7b, 0xce,
+	0xb9, 0xb8, 0x17, 0x17, 0xc0, 0x21, 0x34, 0xe7, 0x32, 0xf9, 0x3d, 0x88, 0xee, 0x4e, 0xc2, 0x28,
+	0x48, 0x02, 0x5c, 0xd1, 0x3f, 0x71, 0xe7, 0xaf, 0x06, 0x34, 0xae, 0x16, 0xa1, 0x8c, 0x86, 0x32,
+	0x8e, 0xfd, 0x1b, 0x89, 0x7f, 0x81, 0xca, 0x40, 0xfa, 0x13, 0x19, 0x19, 0x85, 0x76, 0xa1, 0x5b,
+	0x3f, 0xed, 0xa4, 0x09, 0xf1, 0xc9, 0x53, 0xd5, 0x49, 0xf6, 0x9b, 0x2a, 0x59, 0x96, 0x81, 0x7f,
+	0x86, 0x72, 0xdf, 0x4f, 0x7c, 0xa3, 0xa8, 0x33, 0xdb, 0xdf, 0xcb, 0x54, 0x3a, 0xa6, 0xd5, 0x07,
+	0xff, 0x14, 0xa1, 0xf9, 0x6c, 0x3d, 0x6c, 0xc0, 0xc6, 0x57, 0x19, 0xc5, 0xd3, 0x60, 0xae, 0x9b,
+	0xa8, 0xb1, 0x3c, 0xc4, 0x3b, 0xb0, 0x7e, 0x1f, 0xdc, 0x4c, 0x27, 0xba, 0x44, 0x8d, 0xa5, 0x01,
+	0xc6, 0x50, 0xfe, 0x12, 0x05, 0x33, 0xa3, 0xa4, 0x41, 0xfd, 0x1f, 0xef, 0x41, 0xe5, 0x7a, 0x3c,
+	0xf7, 0x67, 0xd2, 0x28, 0x6b, 0x34, 0x8b, 0x54, 0x8f, 0xc9, 0x43, 0x28, 0x8d, 0xf5, 0x76, 0xa1,
+	0xdb, 0xfa, 0x7e, 0x8f, 0xe2, 0x21, 0x94, 0x4c, 0xab, 0x71, 0x07, 0x1a, 0x13, 0x3f, 0xf1, 0xad,
+	0x5b, 0x39, 0xbe, 0xe3, 0x8b, 0x99, 0x51, 0x69, 0x17, 0xba, 0x4d, 0xf6, 0x0c, 0xc3, 0xbf, 0x42,
+	0x4d, 0x46, 0x51, 0x10, 0xa9, 0x34, 0x63, 0x43, 0x2f, 0x7f, 0xbc, 0x72, 0x79, 0x92, 0xab, 0xd8,
+	0x63, 0x02, 0x7e, 0x0f, 0x2d, 0x39, 0xf7, 0xaf, 0xef, 0xa5, 0x15, 0xcc, 0xc2, 0x48, 0xc6, 0xb1,
+	0x51, 0x6d, 0x17, 0xba, 0x55, 0xf6, 0x02, 0x3d, 0xf8, 0x01, 0xea, 0x4f, 0x46, 0xa8, 0x46, 0x35,
+	0x8b, 0x6f, 0xec, 0xf9, 0x97, 0x40, 0xef, 0xbe, 0xc1, 0xf2, 0xb0, 0xf3, 0x67, 0x65, 0xa9, 0xd4,
+	0x05, 0x9a, 0x50, 0xe3, 0x84, 0xf6, 0x7b, 0x1f, 0x1d, 0xeb, 0x02, 0xad, 0x61, 0x80, 0x8a, 0xeb,
+	0x70, 0x21, 0xae, 0x50, 0x01, 0x6f, 0x42, 0xbd, 0x67, 0x0a, 0x6b, 0x90, 0x01, 0x45, 0xa5, 0x3d,
+	0x27, 0xc2, 0x4b, 0xb5, 0x25, 0x5c, 0x85, 0xb2, 0x6b, 0xd3, 0x73, 0x54, 0xc6, 0x06, 0xec, 0x2c,
+	0x09, 0x6b, 0x60, 0xda, 0x94, 0x0b, 0x53, 0x8c, 0x38, 0x5a, 0xc7, 0x5b, 0xd0, 0x5c, 0x32, 0x1e,
+	0x23, 0x1c, 0x55, 0xf0, 0x21, 0x18, 0xab, 0xc4, 0x9a, 0xdd, 0x50, 0xac, 0xe5, 0xd0, 0x33, 0x9b,
+	0x0d, 0x5f, 0x2f, 0x57, 0xc5, 0x6d, 0x38, 0xfc, 0x16, 0xab, 0xf3, 0x6b, 0xaa, 0xe0, 0x90, 0x9f,
+	0x7b, 0xe2, 0xb3, 0x4b, 0x3c, 0xea, 0x50, 0x82, 0x00, 0x23, 0x68, 0xa8, 0x82, 0xcc, 0xb5, 0x3c,
+	0xd7, 0x61, 0x02, 0xd5, 0xf1, 0x0e, 0xa0, 0xa7, 0x88, 0x4e, 0x6d, 0xe0, 0x3d, 0xc0, 0x0a, 0x35,
+	0x47, 0x62, 0x40, 0xa8, 0xb0, 0x2d, 0x53, 0xd8, 0x0e, 0x45, 0x4d, 0x7c, 0x00, 0x7b, 0xaf, 0x71,
+	0x9d, 0xd3, 0xd2, 0xed, 0xaa, 0x1e, 0x48, 0xdf, 0xeb, 0x9d, 0x09, 0x8f, 0x92, 0x4b, 0xef, 0x93,
+	0x4d, 0x2e, 0xbd, 0x21, 0x3f, 0x47, 0x9b, 0xba, 0xdd, 0x17, 0xac, 0xcb, 0x1c, 0xd7, 0xe1, 0xe6,
+	0x47, 0xad, 0x40, 0x6a, 0x72, 0x4f, 0x15, 0x9f, 0x1c, 0x41, 0x34, 0xb3, 0xa5, 0xa6, 0xaf, 0xf4,
+	0x7a, 0x9b, 0x76, 0x1f, 0x61, 0xdc, 0x80, 0xaa, 0x02, 0xa8, 0xd3, 0x27, 0x68, 0x3b, 0xdf, 0x54,
+	0x46, 0x73, 0xb4, 0x93, 0x6f, 0x2a, 0x47, 0x74, 0x83, 0xbb, 0xb8, 0x05, 0xb0, 0x44, 0x39, 0xda,
+	0xc3, 0x18, 0x5a, 0x8f, 0xb1, 0xd6, 0xbc, 0xc9, 0x0f, 0xc9, 0x25, 0x84, 0x79, 0x36, 0x3d, 0x73,
+	0x90, 0x81, 0x77, 0x61, 0xeb, 0x19, 0xa4, 0x95, 0xfb, 0xea, 0x06, 0xa8, 0x1e, 0xc4, 0x95, 0x2a,
+	0x79, 0x80, 0xeb, 0xb0, 0xa1, 0x54, 0xe2, 0x8a, 0xa3, 0xb7, 0x6a, 0x15, 0xcb, 0x19, 0xba, 0xa6,
+	0x95, 0xdf, 0x90, 0xc3, 0xe7, 0xa7, 0xaf, 0x54, 0x47, 0xf9, 0xc2, 0x4b, 0x48, 0x2f, 0x7c, 0x8c,
+	0xf7, 0x61, 0x57, 0xc1, 0x9c, 0x9a, 0x2e, 0x1f, 0x38, 0xc2, 0x1b, 0x9a, 0xd4, 0x3e, 0x23, 0x5c,
+	0xa0, 0x77, 0xf8, 0x08, 0xf6, 0x57, 0x52, 0x3a, 0xb3, 0x9d, 0x9f, 0xda, 0x92, 0xb6, 0x06, 0x23,
+	0x7a, 0x81, 0xfe, 0x97, 0x9f, 0xda, 0x73, 0x5c, 0xe7, 0x74, 0x3a, 0x7f, 0x17, 0xa1, 0xb6, 0x7c,
+	0x6e, 0x6a, 0x17, 0x7c, 0x64, 0x59, 0x84, 0x73, 0xb4, 0xa6, 0x2e, 0xb5, 0xbe, 0x36, 0x05, 0x35,
+	0xe1, 0x11, 0xbd, 0xa0, 0xce, 0xa5, 0x47, 0x18, 0x73, 0x18, 0x2a, 0xe2, 0x6d, 0xd8, 0xb4, 0x06,
+	0xc4, 0xba, 0xf0, 0xf8, 0x68, 0x98, 0x81, 0x25, 0x75, 0x03, 0x46, 0x74, 0x68, 0x32, 0x3e, 0x48,
+	0x0f, 0xd5, 0xeb, 0x39, 0xfd, 0xcf, 0x19, 0x5b, 0x56, 0xe3, 0xb6, 0x1c, 0x4a, 0x89, 0xa5, 0xda,
+	0x3d, 0x1b, 0x71, 0x82, 0xd6, 0x5f, 0xbf, 0x96, 0x4c, 0x5d, 0xc1, 0x6f, 0x60, 0xfb, 0x09, 0x4a,
+	0x1d, 0x41, 0xae, 0x6c, 0x2e, 0xd0, 0x86, 0xaa, 0xfc, 0x38, 0xb5, 0x54, 0x5d, 0xc5, 0x1d, 0x38,
+	0xfe, 0xe6, 0x63, 0x48, 0x35, 0xb5, 0xfc, 0xb1, 0xbd, 0xb8, 0xbb, 0x29, 0x0b, 0xf8, 0x1d, 0xbc,
+	0x5d, 0xc1, 0x52, 0x47, 0x78, 0xae, 0xc9, 0x39, 0xaa, 0x77, 0xfe, 0x2d, 0x40, 0xd5, 0x95, 0x32,
+	0x52, 0xd6, 0x81, 0x5b, 0x50, 0x9c, 0x4e, 0x32, 0xeb, 0x2d, 0x4e, 0x27, 0xca, 0x64, 0xfc, 0xc9,
+	0x44, 0x9b, 0x52, 0xea, 0xbb, 0x79, 0xa8, 0x99, 0xf1, 0x38, 0x58, 0xcc, 0x93, 0xcc, 0x7c, 0xf3,
+	0x10, 0xff, 0x1f, 0xca, 0xa1, 0x94, 0x91, 0x51, 0x6e, 0x97, 0xba, 0xf5, 0x53, 0x94, 0x1b, 0x61,
+	0x5e, 0x83, 0x69, 0x56, 0xb9, 0xf4, 0xf8, 0xd6, 0x9f, 0xce, 0x63, 0x63, 0xbd, 0x5d, 0x52, 0x2e,
+	0x9d, 0x46, 0xf8, 0x08, 0x20, 0x5c, 0x5c, 0xdf, 0x4f, 0xc7, 0xde, 0x9d, 0x7c, 0xd0, 0x6e, 0xdb,
+	0x60, 0xb5, 0x14, 0xb9, 0x90, 0x0f, 0xf8, 0x10, 0x6a, 0xf1, 0xf4, 0x66, 0xee, 0x27, 0x8b, 0x28,
+	0xb5, 0xda, 0x06, 0x7b, 0x04, 0x14, 0x9b, 0x4c, 0x67, 0x32, 0x4e, 0xfc, 0x59, 0xa8, 0x5d, 0xb4,
+	0xc4, 0x1e, 0x81, 0x53, 0x17, 0x20, 0x3c, 0x0d, 0xb9, 0x8c, 0xbe, 0x4e, 0xc7, 0x12, 0xf7, 0xa0,
+	0xc5, 0xe5, 0x7c, 0xe2, 0x9e, 0x86, 0xf9, 0x07, 0x70, 0x67, 0x95, 0x67, 0x1f, 0xac, 0x44, 0x3b,
+	0x6b, 0xdd, 0xc2, 0x8f, 0x85, 0x5e, 0xf7, 0xb7, 0xf7, 0x37, 0xd3, 0xe4, 0x76, 0x71, 0x7d, 0x32,
+	0x0e, 0x66, 0x1f, 0xfe, 0x50, 0x02, 0xbd, 0x8d, 0xec, 0x6f, 0x10, 0xc9, 0x0f, 0x69, 0xf2, 0x75,
+	0xfa, 0xd5, 0xfd, 0xe9, 0xbf, 0x01, 0x00, 0xae, 0x20, 0x4b, 0x79, 0x8d, 0x07, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -617,5 +698,5 @@ var _P2PService_serviceDesc = grpc.ServiceDesc{
 			ClientStreams: true,
 		},
 	},
-	Metadata: "protos/network.proto",
+	Metadata: "network.proto",
 }